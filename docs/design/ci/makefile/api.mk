@@ -54,6 +54,12 @@ api-check: ## Verify generated code is in sync with spec (CI target)
 	@echo "🔍 Checking generated code sync..."
 	@./docs/design/ci/scripts/api-check.sh
 
+.PHONY: api-contract-test
+api-contract-test: ## Validate every registered route against the spec (CI target)
+	@echo "🔍 Running handler contract tests..."
+	@go test ./internal/api/contract/... -count=1
+	@echo "✅ Handlers match the OpenAPI spec"
+
 .PHONY: api-breaking
 api-breaking: ## Detect breaking changes vs main branch
 	@echo "🔍 Checking for breaking changes..."
@@ -131,6 +137,7 @@ api-help: ## Show API-related targets
 	@echo ""
 	@echo "CI/Review:"
 	@echo "  api-check      Verify generated code is in sync"
+	@echo "  api-contract-test Validate handlers against the spec"
 	@echo "  api-breaking   Detect breaking changes vs main"
 	@echo "  api-changelog  Generate changelog vs main"
 	@echo ""
@@ -0,0 +1,40 @@
+# Hot-Path Performance Smoke
+# Include this file from the main Makefile:
+#   include build/perf.mk
+#
+# Prerequisites:
+#   - Docker (testcontainers), or SHEPHERD_TEST_DATABASE_URL pointing at
+#     a PostgreSQL 18 server with CREATEDB
+
+# ─────────────────────────────────────────────────────────────────────────────
+# Configuration
+# ─────────────────────────────────────────────────────────────────────────────
+
+BENCH_PKG := ./internal/testutil/bench/...
+BENCH_BASELINE := test/perf/baseline.txt
+BENCH_OUTPUT := bench.txt
+
+# Fixed iteration count keeps the smoke short and runs comparable; six
+# runs give check_bench_regression.go a stable median.
+BENCH_FLAGS := -run '^$$' -bench . -benchmem -benchtime 200x -count 6
+
+# Allowed slowdown of the median ns/op against the baseline
+BENCH_THRESHOLD := 0.25
+
+# ─────────────────────────────────────────────────────────────────────────────
+# Targets
+# ─────────────────────────────────────────────────────────────────────────────
+
+.PHONY: perf-smoke
+perf-smoke: ## Run hot-path benchmarks and compare with the baseline (CI target)
+	@echo "⏱️  Running hot-path benchmarks..."
+	@go test $(BENCH_PKG) $(BENCH_FLAGS) | tee $(BENCH_OUTPUT)
+	@go run docs/design/ci/scripts/check_bench_regression.go \
+		-baseline $(BENCH_BASELINE) -current $(BENCH_OUTPUT) -threshold $(BENCH_THRESHOLD)
+
+.PHONY: perf-baseline
+perf-baseline: ## Record a new baseline (run on main, on the CI runner class)
+	@echo "📝 Recording benchmark baseline..."
+	@mkdir -p $(dir $(BENCH_BASELINE))
+	@go test $(BENCH_PKG) $(BENCH_FLAGS) | tee $(BENCH_BASELINE)
+	@echo "✅ Baseline written to $(BENCH_BASELINE); commit it with the change that justifies it"
@@ -0,0 +1,143 @@
+// scripts/ci/check_bench_regression.go
+
+/*
+基准性能回归检查 - CI 强制执行
+
+🛑 检查规则：
+1. 热路径基准（internal/testutil/bench）的 ns/op 中位数
+   不得比已提交的基线慢超过阈值（默认 25%）
+2. 基线中存在但本次未运行的基准视为失败（防止基准被悄悄删除）
+3. 本次新增、基线中没有的基准只提示，不阻断
+
+检测模式：
+- 解析 go test -bench 的输出（-count 多次运行取中位数）
+- 与 test/perf/baseline.txt 逐项比较
+
+用法：
+  go run docs/design/ci/scripts/check_bench_regression.go \
+      -baseline test/perf/baseline.txt -current bench.txt [-threshold 0.25]
+*/
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "test/perf/baseline.txt", "已提交的基线")
+	currentPath := flag.String("current", "bench.txt", "本次运行的输出")
+	threshold := flag.Float64("threshold", 0.25, "允许的最大变慢比例")
+	flag.Parse()
+
+	baseline, err := readMedians(*baselinePath)
+	if err != nil {
+		fmt.Printf("❌ 读取基线失败: %v\n", err)
+		os.Exit(1)
+	}
+	current, err := readMedians(*currentPath)
+	if err != nil {
+		fmt.Printf("❌ 读取本次结果失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	names := make([]string, 0, len(baseline))
+	for name := range baseline {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errors []string
+	fmt.Printf("%-40s %14s %14s %8s\n", "benchmark", "baseline ns/op", "current ns/op", "delta")
+	for _, name := range names {
+		base := baseline[name]
+		cur, ok := current[name]
+		if !ok {
+			errors = append(errors, fmt.Sprintf("%s: 基线中存在但本次未运行", name))
+			continue
+		}
+		delta := (cur - base) / base
+		mark := ""
+		if delta > *threshold {
+			mark = " ❌"
+			errors = append(errors, fmt.Sprintf("%s: %.0f → %.0f ns/op (+%.0f%%，阈值 %.0f%%)",
+				name, base, cur, delta*100, *threshold*100))
+		}
+		fmt.Printf("%-40s %14.0f %14.0f %+7.1f%%%s\n", name, base, cur, delta*100, mark)
+	}
+	for name := range current {
+		if _, ok := baseline[name]; !ok {
+			fmt.Printf("⚠️  %s 不在基线中，请运行 make perf-baseline 更新基线\n", name)
+		}
+	}
+
+	if len(errors) > 0 {
+		fmt.Println("\n❌ 发现性能回归:")
+		for _, e := range errors {
+			fmt.Printf("  %s\n", e)
+		}
+		fmt.Println("\n📋 处理方式:")
+		fmt.Println("  - 确认是 schema/查询变更导致的，优先修复（索引、查询计划）")
+		fmt.Println("  - 有意的取舍：在 main 上运行 make perf-baseline 并在 PR 中说明原因")
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ 未发现性能回归")
+}
+
+// readMedians 解析 go test -bench 输出，返回每个基准 ns/op 的中位数。
+// 基准名去掉 -GOMAXPROCS 后缀，使不同核数的机器可比较。
+func readMedians(path string) (map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	samples := map[string][]float64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		// BenchmarkName-8  200  123456 ns/op  ...
+		for i := 2; i+1 < len(fields); i++ {
+			if fields[i+1] != "ns/op" {
+				continue
+			}
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fields[0], err)
+			}
+			name := fields[0]
+			if j := strings.LastIndex(name, "-"); j > 0 {
+				if _, err := strconv.Atoi(name[j+1:]); err == nil {
+					name = name[:j]
+				}
+			}
+			samples[name] = append(samples[name], v)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	medians := map[string]float64{}
+	for name, vs := range samples {
+		sort.Float64s(vs)
+		n := len(vs)
+		if n%2 == 1 {
+			medians[name] = vs[n/2]
+		} else {
+			medians[name] = (vs[n/2-1] + vs[n/2]) / 2
+		}
+	}
+	return medians, nil
+}
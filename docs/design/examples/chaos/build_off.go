@@ -0,0 +1,7 @@
+//go:build !chaos
+
+package chaos
+
+// Compiled reports whether fault injection is built in (-tags chaos).
+// Release images are built without the tag: chaos.enabled fails startup.
+const Compiled = false
@@ -0,0 +1,6 @@
+//go:build chaos
+
+package chaos
+
+// Compiled reports whether fault injection is built in (-tags chaos).
+const Compiled = true
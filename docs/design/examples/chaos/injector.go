@@ -0,0 +1,95 @@
+// Package chaos injects failures for resilience testing in non-prod
+// environments: provider timeouts, database serialization failures and
+// job panics, each at a configured rate.
+//
+// Two gates: the binary must be built with -tags chaos, and chaos.enabled
+// must be set. Release images are built without the tag, so enabling
+// chaos there fails startup instead of silently injecting faults.
+//
+// What each fault exercises:
+//
+//	Fault                    Injected at                 Exercises
+//	───────────────────────────────────────────────────────────────────────
+//	Provider timeout         InfrastructureProvider      Handler retry, saga compensation
+//	DB serialization (40001) River worker middleware     Job retry, TX rollback
+//	Job panic                River worker middleware     River panic recovery, retry
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/chaos
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/config"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// Injector decides which calls fail. A nil Injector never injects, so
+// wiring can pass the result of New unconditionally.
+type Injector struct {
+	cfg config.ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// New returns nil when chaos is disabled.
+func New(cfg config.ChaosConfig) (*Injector, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if !Compiled {
+		return nil, ErrNotCompiled
+	}
+	for name, rate := range map[string]float64{
+		"provider_timeout_rate": cfg.ProviderTimeoutRate,
+		"db_serialization_rate": cfg.DBSerializationRate,
+		"job_panic_rate":        cfg.JobPanicRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("chaos.%s must be between 0 and 1: %w", name, ErrInvalidRate)
+		}
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	logger.Warn("Chaos fault injection enabled",
+		zap.Int64("seed", seed), // Log it: a failing run can be replayed
+		zap.Float64("provider_timeout_rate", cfg.ProviderTimeoutRate),
+		zap.Float64("db_serialization_rate", cfg.DBSerializationRate),
+		zap.Float64("job_panic_rate", cfg.JobPanicRate),
+	)
+	return &Injector{cfg: cfg, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// roll reports whether to inject a fault of the given kind for op.
+func (i *Injector) roll(kind, op string, rate float64) bool {
+	if i == nil || rate == 0 {
+		return false
+	}
+	i.mu.Lock()
+	hit := i.rng.Float64() < rate
+	i.mu.Unlock()
+
+	if hit {
+		logger.Info("Chaos fault injected",
+			zap.String("fault", kind),
+			zap.String("operation", op),
+		)
+	}
+	return hit
+}
+
+// Errors
+var (
+	ErrNotCompiled = errors.New("chaos.enabled requires a build with -tags chaos")
+	ErrInvalidRate = errors.New("invalid chaos rate")
+)
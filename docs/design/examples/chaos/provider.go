@@ -0,0 +1,132 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+)
+
+// Provider wraps the provider registry and makes calls time out at
+// chaos.provider_timeout_rate. A timed-out call waits provider_timeout
+// (or until ctx ends) and never reaches the cluster, like a request lost
+// on the way; faults after the cluster applied a change are covered by
+// the conflict and idempotency checks of the handlers.
+//
+// Wraps the InfrastructureProvider that use cases and event handlers get.
+// Capability accessors on the registry are not wrapped.
+type Provider struct {
+	provider.InfrastructureProvider
+	inj *Injector
+}
+
+// WrapProvider returns next unchanged when inj is nil.
+func WrapProvider(inj *Injector, next provider.InfrastructureProvider) provider.InfrastructureProvider {
+	if inj == nil {
+		return next
+	}
+	return &Provider{InfrastructureProvider: next, inj: inj}
+}
+
+func (p *Provider) fault(ctx context.Context, op string) error {
+	if !p.inj.roll("provider_timeout", op, p.inj.cfg.ProviderTimeoutRate) {
+		return nil
+	}
+	timer := time.NewTimer(p.inj.cfg.ProviderTimeout)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return fmt.Errorf("chaos: %s: %w", op, context.DeadlineExceeded)
+}
+
+// GetVM may time out instead of calling the provider.
+func (p *Provider) GetVM(ctx context.Context, cluster, namespace, name string) (*domain.VM, error) {
+	if err := p.fault(ctx, "GetVM"); err != nil {
+		return nil, err
+	}
+	return p.InfrastructureProvider.GetVM(ctx, cluster, namespace, name)
+}
+
+// ListVMs may time out instead of calling the provider.
+func (p *Provider) ListVMs(ctx context.Context, cluster, namespace string, opts provider.ListOptions) (*domain.VMList, error) {
+	if err := p.fault(ctx, "ListVMs"); err != nil {
+		return nil, err
+	}
+	return p.InfrastructureProvider.ListVMs(ctx, cluster, namespace, opts)
+}
+
+// CreateVM may time out instead of calling the provider.
+func (p *Provider) CreateVM(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.VM, error) {
+	if err := p.fault(ctx, "CreateVM"); err != nil {
+		return nil, err
+	}
+	return p.InfrastructureProvider.CreateVM(ctx, cluster, namespace, spec)
+}
+
+// UpdateVM may time out instead of calling the provider.
+func (p *Provider) UpdateVM(ctx context.Context, cluster, namespace, name string, spec *domain.VMSpec) (*domain.VM, error) {
+	if err := p.fault(ctx, "UpdateVM"); err != nil {
+		return nil, err
+	}
+	return p.InfrastructureProvider.UpdateVM(ctx, cluster, namespace, name, spec)
+}
+
+// DeleteVM may time out instead of calling the provider.
+func (p *Provider) DeleteVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "DeleteVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.DeleteVM(ctx, cluster, namespace, name)
+}
+
+// StartVM may time out instead of calling the provider.
+func (p *Provider) StartVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "StartVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.StartVM(ctx, cluster, namespace, name)
+}
+
+// StopVM may time out instead of calling the provider.
+func (p *Provider) StopVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "StopVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.StopVM(ctx, cluster, namespace, name)
+}
+
+// RestartVM may time out instead of calling the provider.
+func (p *Provider) RestartVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "RestartVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.RestartVM(ctx, cluster, namespace, name)
+}
+
+// PauseVM may time out instead of calling the provider.
+func (p *Provider) PauseVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "PauseVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.PauseVM(ctx, cluster, namespace, name)
+}
+
+// UnpauseVM may time out instead of calling the provider.
+func (p *Provider) UnpauseVM(ctx context.Context, cluster, namespace, name string) error {
+	if err := p.fault(ctx, "UnpauseVM"); err != nil {
+		return err
+	}
+	return p.InfrastructureProvider.UnpauseVM(ctx, cluster, namespace, name)
+}
+
+// ValidateSpec may time out instead of calling the provider.
+func (p *Provider) ValidateSpec(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.ValidationResult, error) {
+	if err := p.fault(ctx, "ValidateSpec"); err != nil {
+		return nil, err
+	}
+	return p.InfrastructureProvider.ValidateSpec(ctx, cluster, namespace, spec)
+}
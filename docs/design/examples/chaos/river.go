@@ -0,0 +1,45 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// serializationFailure is SQLSTATE 40001, as returned when PostgreSQL
+// aborts a transaction.
+const serializationFailure = "40001"
+
+// WorkerMiddleware fails River jobs before they run: with a
+// serialization failure (the job's transaction rolled back) or a panic.
+// Both must end in a River retry with no partial effects.
+type WorkerMiddleware struct {
+	river.MiddlewareDefaults
+	inj *Injector
+}
+
+// NewWorkerMiddleware returns nil when inj is nil; append only non-nil
+// middleware to river.Config.Middleware.
+func NewWorkerMiddleware(inj *Injector) *WorkerMiddleware {
+	if inj == nil {
+		return nil
+	}
+	return &WorkerMiddleware{inj: inj}
+}
+
+// Work injects a fault or runs the job.
+func (m *WorkerMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(ctx context.Context) error) error {
+	if m.inj.roll("job_panic", job.Kind, m.inj.cfg.JobPanicRate) {
+		panic(fmt.Sprintf("chaos: injected panic in %s job %d", job.Kind, job.ID))
+	}
+	if m.inj.roll("db_serialization", job.Kind, m.inj.cfg.DBSerializationRate) {
+		return fmt.Errorf("chaos: %s job %d: %w", job.Kind, job.ID, &pgconn.PgError{
+			Code:    serializationFailure,
+			Message: "could not serialize access due to concurrent update (injected)",
+		})
+	}
+	return doInner(ctx)
+}
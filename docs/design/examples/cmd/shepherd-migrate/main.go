@@ -0,0 +1,127 @@
+// Command shepherd-migrate imports a legacy VM platform inventory (oVirt,
+// vSphere) into Shepherd as Systems, Services and pending adoptions.
+//
+//	shepherd-migrate --platform vsphere --inventory vms.csv --mapping rules.yaml --dry-run
+//	SHEPHERD_TOKEN=... shepherd-migrate --platform vsphere --inventory vms.csv \
+//	    --mapping rules.yaml --api https://shepherd.example.com --progress vms.progress.json
+//
+// Re-running with the same --progress file resumes an interrupted import.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/cmd/shepherd-migrate
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"kv-shepherd.io/shepherd/internal/migrate"
+)
+
+func main() {
+	if err := newCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newCommand() *cobra.Command {
+	var (
+		platform, inventory, mapping string
+		apiURL, progressPath         string
+		dryRun                       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:          "shepherd-migrate",
+		Short:        "Import a legacy VM inventory into Shepherd",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			plan, err := buildPlan(ctx, migrate.Platform(platform), inventory, mapping)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				return printJSON(plan)
+			}
+
+			token := os.Getenv("SHEPHERD_TOKEN") // Not a flag: keeps it out of shell history
+			if apiURL == "" || token == "" {
+				return errors.New("--api and SHEPHERD_TOKEN are required unless --dry-run")
+			}
+			progress, err := migrate.LoadProgress(progressPath)
+			if err != nil {
+				return err
+			}
+
+			logger, _ := zap.NewProduction()
+			defer logger.Sync()
+
+			client := migrate.NewClient(apiURL, token, &http.Client{Timeout: 30 * time.Second})
+			report, err := migrate.NewRunner(client, progress, logger).Run(ctx, plan)
+			if perr := printJSON(report); perr != nil {
+				return perr
+			}
+			if err != nil {
+				return err
+			}
+			if len(report.Failed) > 0 {
+				return fmt.Errorf("%d steps failed; re-run to retry", len(report.Failed))
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&platform, "platform", "", "legacy platform: ovirt or vsphere")
+	f.StringVar(&inventory, "inventory", "", "inventory CSV export")
+	f.StringVar(&mapping, "mapping", "", "mapping rules YAML")
+	f.StringVar(&apiURL, "api", "", "Shepherd API base URL")
+	f.StringVar(&progressPath, "progress", "shepherd-migrate.progress.json", "progress file for resume")
+	f.BoolVar(&dryRun, "dry-run", false, "print the plan without calling the API")
+	for _, name := range []string{"platform", "inventory", "mapping"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+	return cmd
+}
+
+func buildPlan(ctx context.Context, platform migrate.Platform, inventory, mapping string) (*migrate.Plan, error) {
+	data, err := os.ReadFile(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("read mapping: %w", err)
+	}
+	var rules migrate.Rules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse mapping: %w", err)
+	}
+
+	file, err := os.Open(inventory)
+	if err != nil {
+		return nil, fmt.Errorf("open inventory: %w", err)
+	}
+	defer file.Close()
+
+	source := &migrate.CSVSource{Platform: platform, Reader: file}
+	vms, err := source.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+	return rules.Map(vms), nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
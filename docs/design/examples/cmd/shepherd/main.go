@@ -0,0 +1,83 @@
+// Command shepherd is the operator CLI shipped in the server image.
+//
+//	shepherd doctor                       # table, exit 1 if a check failed
+//	shepherd doctor --output json         # for support tickets and automation
+//	kubectl exec deploy/shepherd -- shepherd doctor
+//
+// It reads the same config file and environment as the server
+// (config.Load), so running it in the server's pod checks exactly what the
+// server will use.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/cmd/shepherd
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kv-shepherd.io/shepherd/internal/app"
+	"kv-shepherd.io/shepherd/internal/doctor"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:          "shepherd",
+		Short:        "KubeVirt Shepherd operator commands",
+		SilenceUsage: true,
+	}
+	root.AddCommand(newDoctorCommand())
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newDoctorCommand() *cobra.Command {
+	var (
+		migrationsDir, output string
+		timeout               time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check config, database, migrations and clusters without starting the server",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if output != "text" && output != "json" {
+				return fmt.Errorf("--output must be text or json, got %q", output)
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			report := doctor.Run(ctx, doctor.Options{
+				MigrationsDir: migrationsDir,
+				Timeout:       timeout,
+				Clusters:      app.DoctorClusterDeps, // Same wiring as the server (bootstrap.go)
+			})
+
+			if output == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				doctor.Print(os.Stdout, report)
+			}
+			if report.Failed() {
+				return errors.New("doctor: at least one check failed")
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&migrationsDir, "migrations", "migrations/atlas", "Atlas migration files shipped with this binary")
+	f.DurationVar(&timeout, "timeout", 10*time.Second, "time limit per check")
+	f.StringVarP(&output, "output", "o", "text", "report format: text or json")
+	return cmd
+}
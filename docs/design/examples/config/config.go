@@ -17,12 +17,19 @@ import (
 
 // Config is the root configuration structure
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Session  SessionConfig  `mapstructure:"session"`
-	K8s      K8sConfig      `mapstructure:"k8s"`
-	Log      LogConfig      `mapstructure:"log"`
-	River    RiverConfig    `mapstructure:"river"`
+	Server       ServerConfig       `mapstructure:"server"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	Session      SessionConfig      `mapstructure:"session"`
+	K8s          K8sConfig          `mapstructure:"k8s"`
+	Log          LogConfig          `mapstructure:"log"`
+	River        RiverConfig        `mapstructure:"river"`
+	Governance   GovernanceConfig   `mapstructure:"governance"`
+	Slack        SlackConfig        `mapstructure:"slack"`
+	Warmup       WarmupConfig       `mapstructure:"warmup"`
+	Backup       BackupConfig       `mapstructure:"backup"`
+	AuditArchive AuditArchiveConfig `mapstructure:"audit_archive"`
+	Cache        CacheConfig        `mapstructure:"cache"`
+	Chaos        ChaosConfig        `mapstructure:"chaos"`
 }
 
 // ServerConfig contains HTTP server settings
@@ -31,6 +38,19 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+
+	// SignatureMaxSkew is the accepted clock difference for signed webhook
+	// and automation requests; nonces are remembered for the same window.
+	SignatureMaxSkew time.Duration `mapstructure:"signature_max_skew"`
+
+	// LeaderCheckInterval is how often followers contend for singleton
+	// components (watchers) and the leader verifies it still holds the lock.
+	LeaderCheckInterval time.Duration `mapstructure:"leader_check_interval"`
+
+	// DrainDelay is how long a terminating replica keeps serving after
+	// failing readiness, until load balancers have removed it. Then HTTP
+	// and River get ShutdownTimeout to finish.
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
 }
 
 // DatabaseConfig contains PostgreSQL connection settings
@@ -42,17 +62,33 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	Database string `mapstructure:"database"`
 
+	// Managed PostgreSQL failover (Phase 0 §6). Hosts lists every node as
+	// host[:port] and replaces Host/Port when set; TargetSessionAttrs
+	// read-write connects only to the current primary.
+	Hosts               []string      `mapstructure:"hosts"`
+	TargetSessionAttrs  string        `mapstructure:"target_session_attrs"`
+	ConnectTimeout      time.Duration `mapstructure:"connect_timeout"`
+	StartupTimeout      time.Duration `mapstructure:"startup_timeout"`       // Wait for the primary at startup
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"` // DatabaseMonitor probe
+
 	// Pool configuration (shared by Ent, River, sqlc)
 	MaxConns        int32         `mapstructure:"max_conns"`
 	MinConns        int32         `mapstructure:"min_conns"`
 	MaxConnLifetime time.Duration `mapstructure:"max_conn_lifetime"`
 	MaxConnIdleTime time.Duration `mapstructure:"max_conn_idle_time"`
 
-	// Optional: PgBouncer dual-pool configuration
+	// Optional: PgBouncer dual-pool configuration. With PgBouncer set,
+	// Host/Hosts point at PgBouncer in transaction pooling mode and
+	// WorkerHost (required) directly at PostgreSQL (Phase 0 §6 PgBouncer Mode).
+	PgBouncer  bool   `mapstructure:"pgbouncer"`
 	WorkerHost string `mapstructure:"worker_host"`
 	WorkerPort int    `mapstructure:"worker_port"`
 
 	AutoMigrate bool `mapstructure:"auto_migrate"`
+
+	// RowLevelSecurity publishes the caller's scope to PostgreSQL RLS
+	// policies on scoped reads (defense in depth, Phase 4 §10.5).
+	RowLevelSecurity bool `mapstructure:"row_level_security"`
 }
 
 // SessionConfig contains session storage settings
@@ -69,6 +105,34 @@ type SessionConfig struct {
 type K8sConfig struct {
 	ClusterConcurrency int           `mapstructure:"cluster_concurrency"`
 	OperationTimeout   time.Duration `mapstructure:"operation_timeout"`
+
+	// InstallationID is written on every created object and checked before
+	// each mutation, so Shepherd instances sharing a cluster never touch
+	// each other's VMs. Required; must never change after first use.
+	InstallationID string `mapstructure:"installation_id"`
+
+	// CapabilityRefreshInterval is how often KubeVirt/CDI versions and
+	// feature gates are re-detected (ADR-0014). Registration always detects.
+	CapabilityRefreshInterval time.Duration `mapstructure:"capability_refresh_interval"`
+
+	// InstanceTypeSyncInterval is how often pushed instancetypes are
+	// reconciled against the InstanceSize catalog.
+	InstanceTypeSyncInterval time.Duration `mapstructure:"instancetype_sync_interval"`
+
+	// NamespaceBaselineSyncInterval is how often namespace baselines
+	// (NetworkPolicy/ResourceQuota/LimitRange) are checked for drift.
+	NamespaceBaselineSyncInterval time.Duration `mapstructure:"namespace_baseline_sync_interval"`
+
+	// ResyncPageSize and ResyncPageInterval bound the load of an admin
+	// resync on each API server: one page of VMs per interval per cluster.
+	ResyncPageSize     int           `mapstructure:"resync_page_size"`
+	ResyncPageInterval time.Duration `mapstructure:"resync_page_interval"`
+
+	// TemplateDryRunCluster and TemplateDryRunNamespace are where templates
+	// are validated server-side before publishing; nothing is created there.
+	// An empty cluster skips the dry-run (linting still applies).
+	TemplateDryRunCluster   string `mapstructure:"template_dry_run_cluster"`
+	TemplateDryRunNamespace string `mapstructure:"template_dry_run_namespace"`
 }
 
 // LogConfig contains logging settings
@@ -79,8 +143,175 @@ type LogConfig struct {
 
 // RiverConfig contains River Queue settings
 type RiverConfig struct {
-	MaxWorkers                  int           `mapstructure:"max_workers"`
-	CompletedJobRetentionPeriod time.Duration `mapstructure:"completed_job_retention_period"`
+	MaxWorkers int `mapstructure:"max_workers"` // default queue (normal priority)
+
+	// Dedicated workers for the high and emergency priority queues, so a
+	// backlog on the default queue never delays them.
+	HighPriorityWorkers      int `mapstructure:"high_priority_workers"`
+	EmergencyPriorityWorkers int `mapstructure:"emergency_priority_workers"`
+}
+
+// GovernanceConfig contains approval and quota settings
+type GovernanceConfig struct {
+	// QuotaSweepInterval is how often HELD quota reservations are checked
+	// for failed, cancelled or orphaned executions.
+	QuotaSweepInterval time.Duration `mapstructure:"quota_sweep_interval"`
+
+	// QuotaReservationGrace is the minimum age before a reservation with
+	// no live event/job is treated as orphaned.
+	QuotaReservationGrace time.Duration `mapstructure:"quota_reservation_grace"`
+
+	// ActionLinkKey signs email action links (GOVERNANCE_ACTION_LINK_KEY,
+	// at least 32 random bytes). Rotating it invalidates outstanding links.
+	ActionLinkKey string `mapstructure:"action_link_key"`
+
+	// ActionLinkTTL is how long email action links stay valid.
+	ActionLinkTTL time.Duration `mapstructure:"action_link_ttl"`
+
+	// VNCTokenKey signs VNC console tokens (GOVERNANCE_VNC_TOKEN_KEY, at
+	// least 32 random bytes). Rotating it invalidates unused tokens; the
+	// token lifetime is chosen per request, at most domain.MaxVNCTokenTTL.
+	VNCTokenKey string `mapstructure:"vnc_token_key"`
+
+	// SLACheckInterval is how often pending tickets are checked against
+	// their SLA due time and overdue ones escalated. The SLA itself is
+	// set per environment policy.
+	SLACheckInterval time.Duration `mapstructure:"sla_check_interval"`
+
+	// TicketTTL is how long a ticket may stay pending before it expires;
+	// TicketTTLByRequestType overrides it per request type (e.g.
+	// DELETE_VM: 72h). See domain.TicketTTL.
+	TicketTTL              time.Duration            `mapstructure:"ticket_ttl"`
+	TicketTTLByRequestType map[string]time.Duration `mapstructure:"ticket_ttl_by_request_type"`
+
+	// TicketExpiryInterval is how often stale pending tickets are expired.
+	TicketExpiryInterval time.Duration `mapstructure:"ticket_expiry_interval"`
+
+	// LeaseExpiryInterval is how often VM leases are checked; owners are
+	// warned LeaseWarningLead before expiry (see domain/vm_lease.go).
+	LeaseExpiryInterval time.Duration `mapstructure:"lease_expiry_interval"`
+	LeaseWarningLead    time.Duration `mapstructure:"lease_warning_lead"`
+
+	// RetentionPurgeInterval is how often each record type is purged
+	// under its retention policy (set by admins, see domain/retention.go).
+	RetentionPurgeInterval time.Duration `mapstructure:"retention_purge_interval"`
+
+	// UsageSnapshotInterval is how often today's usage per Service is
+	// recorded for the usage trend report; each run overwrites today's.
+	UsageSnapshotInterval time.Duration `mapstructure:"usage_snapshot_interval"`
+
+	// APIUsageFlushInterval is how often each replica adds its API call
+	// counts to api_usage_daily and reloads per-token quotas.
+	APIUsageFlushInterval time.Duration `mapstructure:"api_usage_flush_interval"`
+
+	// EventArchiveInterval is how often finished events older than
+	// EventArchiveAfter (at least 7 days) move to domain_events_archive.
+	EventArchiveInterval time.Duration `mapstructure:"event_archive_interval"`
+	EventArchiveAfter    time.Duration `mapstructure:"event_archive_after"`
+
+	// Vulnerability feed thresholds (domain.VulnerabilityPolicy): low,
+	// medium, high, critical or empty to disable the step. Scans at or
+	// above VulnerabilityAdvisoryAt keep an advisory on the digest.
+	VulnerabilityAdvisoryAt           string `mapstructure:"vulnerability_advisory_at"`
+	VulnerabilityNotifyAt             string `mapstructure:"vulnerability_notify_at"`
+	VulnerabilityCampaignAt           string `mapstructure:"vulnerability_campaign_at"`
+	VulnerabilityCampaignConfirmation string `mapstructure:"vulnerability_campaign_confirmation"`
+}
+
+// SlackConfig contains the Slack approval integration settings.
+// Disabled unless Enabled is set; secrets come from SLACK_BOT_TOKEN and
+// SLACK_SIGNING_SECRET.
+type SlackConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	BotToken        string `mapstructure:"bot_token"`
+	SigningSecret   string `mapstructure:"signing_secret"`
+	WorkspaceID     string `mapstructure:"workspace_id"`     // Interactions from other workspaces are rejected
+	ApprovalChannel string `mapstructure:"approval_channel"` // Channel ID for approval messages
+
+	// MapByEmail links unmapped Slack users to the platform user with the
+	// same IdP-verified email on first interaction.
+	MapByEmail bool `mapstructure:"map_by_email"`
+}
+
+// WarmupConfig contains post-provision verification settings. When
+// enabled, a created VM's event completes only once the checks pass.
+type WarmupConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	GuestAgent bool          `mapstructure:"guest_agent"` // Require the qemu guest agent to be connected
+	Port       int           `mapstructure:"port"`        // TCP port probed on the VM IP; 0 disables (default 22)
+	Timeout    time.Duration `mapstructure:"timeout"`     // Event FAILED if checks do not pass in time
+	Interval   time.Duration `mapstructure:"interval"`    // Time between attempts
+}
+
+// BackupConfig selects the external backup system behind BACKUP_VM
+// requests (provider.BackupProvider). Empty Provider disables backups;
+// snapshots work either way. The webhook secret comes from
+// BACKUP_WEBHOOK_SECRET.
+type BackupConfig struct {
+	Provider      string        `mapstructure:"provider"`    // "", velero, kasten, webhook
+	Namespace     string        `mapstructure:"namespace"`   // velero/kasten: where the backup system runs in each cluster
+	Location      string        `mapstructure:"location"`    // velero: BackupStorageLocation; kasten: location profile
+	TTL           time.Duration `mapstructure:"ttl"`         // Retention asked of the provider; 0 = provider default
+	WebhookURL    string        `mapstructure:"webhook_url"` // webhook: base URL of the backup API
+	WebhookKeyID  string        `mapstructure:"webhook_key_id"`
+	WebhookSecret string        `mapstructure:"webhook_secret"`
+	PollInterval  time.Duration `mapstructure:"poll_interval"` // How long a job sleeps while the backup runs
+}
+
+// AuditArchiveConfig enables copying audit logs to append-only object
+// storage (domain/audit_archive.go). Disabled by default. The bucket must
+// have S3 Object Lock enabled; credentials come from
+// AUDIT_ARCHIVE_ACCESS_KEY and AUDIT_ARCHIVE_SECRET_KEY.
+type AuditArchiveConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Endpoint  string `mapstructure:"endpoint"` // S3-compatible endpoint
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	Prefix    string `mapstructure:"prefix"` // Object keys start with it; one prefix per installation
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+
+	// Retention is how long objects stay locked, at least
+	// domain.MinAuditArchiveRetention.
+	Retention time.Duration `mapstructure:"retention"`
+
+	// BatchInterval is how often unarchived audit logs are written, at
+	// most BatchSize per object.
+	BatchInterval time.Duration `mapstructure:"batch_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+
+	// AnchorInterval is how often the chain head is anchored.
+	// TimestampURL, if set, is an RFC 3161 timestamp authority
+	// countersigning each anchor.
+	AnchorInterval time.Duration `mapstructure:"anchor_interval"`
+	TimestampURL   string        `mapstructure:"timestamp_url"`
+}
+
+// CacheConfig contains in-process cache settings.
+type CacheConfig struct {
+	Catalog CatalogCacheConfig `mapstructure:"catalog"`
+}
+
+// CatalogCacheConfig configures the catalog read cache (InstanceSizes,
+// templates, clusters, environment policies), invalidated across replicas
+// via LISTEN/NOTIFY.
+type CatalogCacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	MaxEntries int64         `mapstructure:"max_entries"`
+	TTL        time.Duration `mapstructure:"ttl"` // Upper bound on staleness if a notification is lost
+}
+
+// ChaosConfig configures failure injection for resilience testing in
+// non-prod environments. Enabled fails startup unless the binary was built
+// with -tags chaos. Rates are probabilities per call or job (0 to 1).
+type ChaosConfig struct {
+	Enabled bool  `mapstructure:"enabled"`
+	Seed    int64 `mapstructure:"seed"` // 0 = random; logged at startup for replay
+
+	ProviderTimeoutRate float64       `mapstructure:"provider_timeout_rate"`
+	ProviderTimeout     time.Duration `mapstructure:"provider_timeout"` // How long an injected timeout hangs
+	DBSerializationRate float64       `mapstructure:"db_serialization_rate"`
+	JobPanicRate        float64       `mapstructure:"job_panic_rate"`
 }
 
 // Load reads configuration from file and environment variables
@@ -121,15 +352,25 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
 	viper.SetDefault("server.shutdown_timeout", "30s")
+	viper.SetDefault("server.signature_max_skew", "5m")
+	viper.SetDefault("server.leader_check_interval", "5s")
+	viper.SetDefault("server.drain_delay", "5s")
 
 	// Database (ADR-0012 shared pool)
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
+	viper.SetDefault("database.target_session_attrs", "read-write")
+	viper.SetDefault("database.connect_timeout", "5s")
+	viper.SetDefault("database.startup_timeout", "2m")
+	viper.SetDefault("database.health_check_interval", "2s")
 	viper.SetDefault("database.max_conns", 50)
 	viper.SetDefault("database.min_conns", 5)
 	viper.SetDefault("database.max_conn_lifetime", "1h")
 	viper.SetDefault("database.max_conn_idle_time", "10m")
+	viper.SetDefault("database.pgbouncer", false)
+	viper.SetDefault("database.worker_port", 5432)
 	viper.SetDefault("database.auto_migrate", false)
+	viper.SetDefault("database.row_level_security", false)
 
 	// Session (PostgreSQL-based, replaces Redis)
 	viper.SetDefault("session.lifetime", "24h")
@@ -141,6 +382,12 @@ func setDefaults() {
 	// K8s
 	viper.SetDefault("k8s.cluster_concurrency", 20)
 	viper.SetDefault("k8s.operation_timeout", "5m")
+	viper.SetDefault("k8s.capability_refresh_interval", "1h")
+	viper.SetDefault("k8s.instancetype_sync_interval", "15m")
+	viper.SetDefault("k8s.namespace_baseline_sync_interval", "15m")
+	viper.SetDefault("k8s.resync_page_size", 100)
+	viper.SetDefault("k8s.resync_page_interval", "2s")
+	viper.SetDefault("k8s.template_dry_run_namespace", "shepherd-dry-run")
 
 	// Log
 	viper.SetDefault("log.level", "info")
@@ -148,5 +395,54 @@ func setDefaults() {
 
 	// River
 	viper.SetDefault("river.max_workers", 10)
-	viper.SetDefault("river.completed_job_retention_period", "24h")
+	viper.SetDefault("river.high_priority_workers", 5)
+	viper.SetDefault("river.emergency_priority_workers", 3)
+
+	// Governance
+	viper.SetDefault("governance.quota_sweep_interval", "10m")
+	viper.SetDefault("governance.quota_reservation_grace", "30m")
+	viper.SetDefault("governance.action_link_ttl", "72h")
+	viper.SetDefault("governance.sla_check_interval", "5m")
+	viper.SetDefault("governance.ticket_ttl", "336h") // 14 days
+	viper.SetDefault("governance.ticket_expiry_interval", "1h")
+	viper.SetDefault("governance.lease_expiry_interval", "15m")
+	viper.SetDefault("governance.lease_warning_lead", "72h")
+	viper.SetDefault("governance.retention_purge_interval", "24h")
+	viper.SetDefault("governance.usage_snapshot_interval", "1h")
+	viper.SetDefault("governance.api_usage_flush_interval", "10s")
+	viper.SetDefault("governance.event_archive_interval", "24h")
+	viper.SetDefault("governance.event_archive_after", "720h") // 30 days
+
+	// Slack
+	viper.SetDefault("slack.enabled", false)
+	viper.SetDefault("slack.map_by_email", true)
+
+	// Warm-up
+	viper.SetDefault("warmup.enabled", false)
+	viper.SetDefault("warmup.guest_agent", true)
+	viper.SetDefault("warmup.port", 22)
+	viper.SetDefault("warmup.timeout", "10m")
+	viper.SetDefault("warmup.interval", "15s")
+
+	// Backup (disabled unless a provider is set)
+	viper.SetDefault("backup.provider", "")
+	viper.SetDefault("backup.ttl", "720h") // 30 days
+	viper.SetDefault("backup.poll_interval", "30s")
+
+	// Audit archive (disabled unless enabled)
+	viper.SetDefault("audit_archive.enabled", false)
+	viper.SetDefault("audit_archive.prefix", "audit")
+	viper.SetDefault("audit_archive.retention", "26280h") // 3 years
+	viper.SetDefault("audit_archive.batch_interval", "5m")
+	viper.SetDefault("audit_archive.batch_size", 5000)
+	viper.SetDefault("audit_archive.anchor_interval", "24h")
+
+	// Cache
+	viper.SetDefault("cache.catalog.enabled", true)
+	viper.SetDefault("cache.catalog.max_entries", 10000)
+	viper.SetDefault("cache.catalog.ttl", "5m")
+
+	// Chaos (non-prod only, requires -tags chaos)
+	viper.SetDefault("chaos.enabled", false)
+	viper.SetDefault("chaos.provider_timeout", "30s")
 }
@@ -0,0 +1,197 @@
+package contract_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/api"
+	"kv-shepherd.io/shepherd/internal/api/contract"
+	"kv-shepherd.io/shepherd/internal/api/fakes"
+	"kv-shepherd.io/shepherd/internal/api/generated"
+)
+
+// Users and IDs the fakes know (internal/api/fakes): every use case
+// succeeds for them, except that fakes.Missing is not found and
+// fakes.Outsider holds no role anywhere.
+const (
+	admin    = fakes.PlatformAdmin
+	alice    = fakes.Requester // Requester of every fake ticket
+	approver = fakes.Approver
+	outsider = fakes.Outsider
+	missing  = fakes.Missing
+)
+
+func TestHandlersMatchSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(contract.IdentityStub())
+	api.RegisterRoutes(engine, fakes.Handlers(t))
+
+	spec, err := generated.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := contract.New(spec, engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Outside the public contract
+	h.Exempt["GET /health/live"] = true
+	h.Exempt["GET /health/ready"] = true
+	h.Exempt["GET /health/requirements"] = true
+	h.Exempt["POST /api/v1/integrations/slack/interactions"] = true // Slack's payload, signed by Slack
+	h.Exempt["GET /schemas/events"] = true                          // JSON Schema documents, not operations
+	h.Exempt["GET /schemas/events/:version/:name"] = true
+	h.Exempt["GET /api/v1/changes/stream"] = true // text/event-stream, never completes
+
+	h.Run(t, cases)
+}
+
+var reason = map[string]any{"reason": "contract test"}
+
+var cases = []contract.Case{
+	// Lists and timelines
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/vms", Query: "limit=10", UserID: alice, Status: http.StatusOK},
+	{Name: "bad limit", Method: http.MethodGet, Path: "/api/v1/vms", Query: "limit=x", UserID: alice, Status: http.StatusBadRequest},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/approvals", Query: "status=PENDING_APPROVAL", UserID: approver, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/events", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/vms/vm-1/timeline", UserID: alice, Status: http.StatusOK},
+	{Name: "not found", Method: http.MethodGet, Path: "/api/v1/vms/" + missing + "/timeline", UserID: alice, Status: http.StatusNotFound},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/vms/vm-1/restore-points", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/changes", Query: "timeout=1s", UserID: alice, Status: http.StatusOK},
+
+	// Tickets
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/cancel", Body: reason, UserID: alice, Status: http.StatusNoContent},
+	{Name: "not requester", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/cancel", Body: reason, UserID: outsider, Status: http.StatusForbidden},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/resubmit", Body: map[string]any{"cpu": 4, "reason": "smaller"}, UserID: alice, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/approvals/t-1/history", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/approvals/t-1/comments", UserID: alice, Status: http.StatusOK},
+	{Name: "not participant", Method: http.MethodGet, Path: "/api/v1/approvals/t-1/comments", UserID: outsider, Status: http.StatusForbidden},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/comments", Body: map[string]any{"body": "please check the size"}, UserID: approver, Status: http.StatusCreated},
+	{Name: "empty body", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/comments", Body: map[string]any{}, UserID: approver, Status: http.StatusBadRequest},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/approvals/t-1/spec-diff", UserID: approver, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/approvals/t-1/execution-schedule", Body: map[string]any{"execute_at": nil}, UserID: approver, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/freeze-override", Body: reason, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/approvals/t-1/freeze-override/approve", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/action-links/tok-1", Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/action-links/tok-1/confirm", Body: reason, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/approvals/bulk-approve", Body: map[string]any{"ticket_ids": []string{"t-1", "t-2"}}, UserID: admin, Status: http.StatusOK},
+
+	// Delegations
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/delegations", Body: map[string]any{"delegate_id": "bob", "ends_at": "2030-01-08T00:00:00Z", "reason": "leave"}, UserID: approver, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/delegations", UserID: approver, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/delegations/dlg-1", UserID: approver, Status: http.StatusNoContent},
+	{Name: "not delegator", Method: http.MethodDelete, Path: "/api/v1/delegations/dlg-1", UserID: outsider, Status: http.StatusForbidden},
+
+	// VM operations
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/power", Body: map[string]any{"action": "restart", "reason": "hung"}, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/clones", Body: reason, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/snapshots", Body: reason, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/backups", Body: reason, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/restores", Body: map[string]any{"restore_point_id": "rp-1", "reason": "bad patch"}, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/lease/renewals", Body: map[string]any{"expires_at": "2030-02-01T00:00:00Z", "reason": "project extended"}, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/replace", Body: map[string]any{"confirmation": "owner", "reason": "rebuild"}, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/vm-replacements/rpl-1", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vm-replacements/rpl-1/confirm", UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vm-replacements/rpl-1/abort", UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/vms/vm-1/vnc/requests", Body: map[string]any{"duration_minutes": 30, "reason": "debug boot"}, UserID: alice, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/vnc/requests/t-1/token", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/vnc/tokens/vnc-1", UserID: alice, Status: http.StatusNoContent},
+
+	// Services and systems
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/services/svc-1/vm-request/ui-schema", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/services/svc-1/vm-request/preview", Body: map[string]any{"template_id": "tpl-1"}, UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/services/svc-1/request-defaults", Body: map[string]any{"template_name": "ubuntu", "instance_size_name": "small"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/services/svc-1/emergency-stop", Body: map[string]any{"confirm": "svc-1", "reason": "INC-1"}, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/systems/sys-1/emergency-stop", Body: map[string]any{"confirm": "sys-1", "reason": "INC-1"}, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/emergency-stops/es-1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/instance-sizes/small/clusters", Query: "environment=prod", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/api-usage", Query: "days=30", UserID: alice, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/webhooks/image-scans", Body: map[string]any{"digest": "sha256:aa", "scanned_at": "2030-01-01T00:00:00Z", "findings": []any{}}, Status: http.StatusOK},
+
+	// Admin: approval policy
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/approval-rules", Body: map[string]any{"name": "small", "action": "auto"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/approval-rules", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/approval-rules/rule-1", Body: map[string]any{"name": "small", "action": "manual"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/admin/approval-rules/rule-1", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/approval-policy/simulate", Query: "limit=100", Body: map[string]any{"rules": []any{}}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/policies", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/policies", Body: map[string]any{"name": "after-hours", "kind": "approval", "language": "cel", "expression": "true", "action": "manual"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/policies/pol-1", Body: map[string]any{"expected_version": 1, "reason": "tighten", "expression": "false", "action": "manual"}, UserID: admin, Status: http.StatusOK},
+	{Name: "version conflict", Method: http.MethodPut, Path: "/api/v1/admin/policies/pol-1", Body: map[string]any{"expected_version": 99, "reason": "tighten", "expression": "false", "action": "manual"}, UserID: admin, Status: http.StatusConflict},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/policies/pol-1/versions", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/policies/dry-run", Body: map[string]any{"policy": map[string]any{"name": "draft", "kind": "approval", "language": "cel", "expression": "true", "action": "auto"}, "inputs": []any{map[string]any{}}}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/change-freezes", Body: map[string]any{"name": "year end", "start": "2030-12-20T00:00:00Z", "end": "2031-01-02T00:00:00Z", "reason": "close"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/change-freezes", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/admin/change-freezes/frz-1", UserID: admin, Status: http.StatusNoContent},
+
+	// Admin: catalog and templates
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/instance-sizes/small", Body: map[string]any{"expected_version": 1, "reason": "more memory", "cpu_cores": 2, "memory": "8Gi"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/instance-sizes/small/versions", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/instance-sizes/small/versions/1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/outdated-instance-sizes", Query: "size=small", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/templates/tpl-1/preview", Body: map[string]any{"vm_name": "web-01", "namespace": "team-a"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/templates/tpl-1/golden/accept", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/templates/tpl-1/publish", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/namespace-baselines/prod", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/namespace-baselines/prod", Body: map[string]any{"objects": []any{}}, UserID: admin, Status: http.StatusOK},
+
+	// Admin: images and compliance
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/images/ubuntu/channels/dev", Body: map[string]any{"repository": "registry.example.com/ubuntu", "digest": "sha256:aa"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/images/ubuntu/promotions", Body: map[string]any{"channel": "staging", "reason": "tested"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/image-promotions/prm-1/approve", UserID: approver, Status: http.StatusOK},
+	{Name: "self approval", Method: http.MethodPost, Path: "/api/v1/admin/image-promotions/prm-1/approve", UserID: admin, Status: http.StatusForbidden},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/image-promotions/prm-1/reject", UserID: approver, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/images/ubuntu/report", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/images/ubuntu/digests/sha256:aa/vms", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/image-scans/sha256:aa", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/compliance/vms", Query: "min_severity=high", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/image-advisories", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/image-advisories", Body: map[string]any{"image": "ubuntu", "kind": "security", "severity": "high", "summary": "openssl"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/admin/image-advisories/adv-1", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/remediation-campaigns", Body: map[string]any{"name": "openssl", "action": "rebuild", "confirmation": "owner"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/remediation-campaigns/cmp-1", UserID: admin, Status: http.StatusOK},
+
+	// Admin: clusters and VMs
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/clusters/c-1/nodes/n-1/drain", Body: map[string]any{"reason": "kernel update"}, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/node-drains/drn-1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/resyncs", Body: reason, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/resyncs/rs-1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/resyncs/rs-1/cancel", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/vms/vm-1/migrations", Body: reason, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/migrations/mig-1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/migrations/mig-1/cancel", UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/vms/vm-1/relocations", Body: map[string]any{"target_cluster": "c-2", "reason": "decommission"}, UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/relocations", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/relocations/rel-1", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/relocations/rel-1/confirm", UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/relocations/rel-1/abort", UserID: admin, Status: http.StatusAccepted},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/events/evt-1/diagnostics", UserID: admin, Status: http.StatusOK},
+	{Name: "not found", Method: http.MethodGet, Path: "/api/v1/admin/events/" + missing + "/diagnostics", UserID: admin, Status: http.StatusNotFound},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/events/evt-1/requeue", Body: reason, UserID: admin, Status: http.StatusOK},
+
+	// Admin: governance data
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/api-usage", Query: "days=30", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/api-quotas", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/api-quotas/key-1", Body: map[string]any{"per_minute": 60, "per_day": 10000, "reason": "ci runner"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/admin/api-quotas/key-1", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/retention-policies", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPut, Path: "/api/v1/admin/retention-policies/events", Body: map[string]any{"window_days": 365}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/legal-holds", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/legal-holds", Body: map[string]any{"resource_type": "service", "resource_id": "svc-1", "reason": "litigation"}, UserID: admin, Status: http.StatusCreated},
+	{Name: "ok", Method: http.MethodDelete, Path: "/api/v1/admin/legal-holds/hold-1", UserID: admin, Status: http.StatusNoContent},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/users/alice/data-export", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodPost, Path: "/api/v1/admin/users/alice/anonymize", Body: map[string]any{"confirm": "alice", "reason": "HR-1"}, UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/audit-archive/anchors", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/audit-archive/verify", Query: "from=1&to=10", UserID: admin, Status: http.StatusOK},
+
+	// Admin: reports
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/emergency-usage", Query: "flagged=true", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/approvals-by-environment", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/approval-sla", UserID: admin, Status: http.StatusOK},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/approval-analytics", Query: "window=30d", UserID: admin, Status: http.StatusOK},
+	{Name: "bad window", Method: http.MethodGet, Path: "/api/v1/admin/reports/approval-analytics", Query: "window=5y", UserID: admin, Status: http.StatusBadRequest},
+	{Name: "ok", Method: http.MethodGet, Path: "/api/v1/admin/reports/usage-trends", Query: "scope=service&id=svc-1&granularity=week&window=90d", UserID: admin, Status: http.StatusOK},
+}
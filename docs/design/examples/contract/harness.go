@@ -0,0 +1,228 @@
+// Package contract checks the gin router against the OpenAPI document
+// (ADR-0021: the spec is the single source of truth).
+//
+// The harness runs in-process, with no server and no database: handlers
+// are wired to fakes by the caller. It fails when
+//
+//   - a registered route has no operation in the spec, or the reverse;
+//   - a route has no contract case, so new endpoints cannot skip the check;
+//   - a request built by a case does not match the operation's parameters
+//     and request body;
+//   - a response status is undeclared, or its body does not match the
+//     declared schema (error responses included).
+//
+// The spec comes from the embedded document oapi-codegen generates
+// (generated.GetSwagger), so the check runs against what was published.
+//
+// Import Path: kv-shepherd.io/shepherd/internal/api/contract
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// Case is one request against a route and the status it must produce.
+// Every route needs at least one case; error paths are worth their own.
+type Case struct {
+	Name   string
+	Method string
+	Path   string // Concrete path, e.g. /api/v1/approvals/t-1/comments
+	Query  string // Without "?"
+	Body   any    // Marshalled as JSON; nil sends no body
+	UserID string // Set as "user_id" like the auth middleware
+	Status int    // Expected status; must also be declared in the spec
+}
+
+// Harness validates a router against a spec.
+type Harness struct {
+	spec   *openapi3.T
+	router routers.Router
+	engine *gin.Engine
+
+	// Exempt lists routes outside the public contract, as "METHOD /path"
+	// in gin syntax: probes, metrics, the Slack webhook.
+	Exempt map[string]bool
+}
+
+// New loads spec and builds the operation router. engine must have every
+// route registered, with handlers wired to fakes.
+func New(spec *openapi3.T, engine *gin.Engine) (*Harness, error) {
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validate spec: %w", err)
+	}
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("build spec router: %w", err)
+	}
+	return &Harness{spec: spec, router: router, engine: engine, Exempt: map[string]bool{}}, nil
+}
+
+// Run checks route coverage and then every case as a subtest.
+func (h *Harness) Run(t *testing.T, cases []Case) {
+	t.Helper()
+	h.checkCoverage(t, cases)
+	for _, c := range cases {
+		t.Run(c.Method+" "+c.Path+" "+c.Name, func(t *testing.T) {
+			h.runCase(t, c)
+		})
+	}
+}
+
+// paramPattern matches path parameters in both syntaxes, so that
+// /vms/:id and /vms/{vmId} compare equal.
+var paramPattern = regexp.MustCompile(`:[^/]+|\{[^}]+\}`)
+
+func routeKey(method, path string) string {
+	return method + " " + paramPattern.ReplaceAllString(path, "{}")
+}
+
+// checkCoverage compares the registered routes with the spec operations
+// and requires a case per route.
+func (h *Harness) checkCoverage(t *testing.T, cases []Case) {
+	t.Helper()
+
+	base := ""
+	if len(h.spec.Servers) > 0 {
+		base = strings.TrimSuffix(h.spec.Servers[0].URL, "/")
+	}
+
+	specOps := map[string]bool{}
+	for path, item := range h.spec.Paths.Map() {
+		for method := range item.Operations() {
+			specOps[routeKey(method, base+path)] = true
+		}
+	}
+
+	registered := map[string]string{} // key → gin path, for messages
+	for _, r := range h.engine.Routes() {
+		if h.Exempt[r.Method+" "+r.Path] {
+			continue
+		}
+		registered[routeKey(r.Method, r.Path)] = r.Path
+	}
+
+	covered := map[string]bool{}
+	for _, c := range cases {
+		if route, _, err := h.router.FindRoute(h.newRequest(c, nil)); err == nil {
+			covered[routeKey(c.Method, base+route.Path)] = true
+		}
+	}
+
+	var drift []string
+	for key, path := range registered {
+		switch {
+		case !specOps[key]:
+			drift = append(drift, fmt.Sprintf("route %s %s is not in the spec", strings.Fields(key)[0], path))
+		case !covered[key]:
+			drift = append(drift, fmt.Sprintf("route %s %s has no contract case", strings.Fields(key)[0], path))
+		}
+	}
+	for key := range specOps {
+		if _, ok := registered[key]; !ok {
+			drift = append(drift, fmt.Sprintf("operation %s is in the spec but not registered", key))
+		}
+	}
+	sort.Strings(drift)
+	for _, d := range drift {
+		t.Error(d)
+	}
+}
+
+// runCase sends the request through the engine and validates both sides
+// against the matched operation.
+func (h *Harness) runCase(t *testing.T, c Case) {
+	t.Helper()
+
+	var body []byte
+	if c.Body != nil {
+		var err error
+		if body, err = json.Marshal(c.Body); err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+	}
+
+	req := h.newRequest(c, body)
+	route, pathParams, err := h.router.FindRoute(req)
+	if err != nil {
+		t.Fatalf("no spec operation for %s %s: %v", c.Method, c.Path, err)
+	}
+
+	reqInput := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc, // Auth is the middleware's job
+		},
+	}
+	if err := openapi3filter.ValidateRequest(req.Context(), reqInput); err != nil {
+		t.Fatalf("request does not match the spec: %v", err)
+	}
+
+	// Validation consumed the body; send a fresh request
+	rec := httptest.NewRecorder()
+	h.engine.ServeHTTP(rec, h.newRequest(c, body))
+
+	if rec.Code != c.Status {
+		t.Fatalf("status %d, want %d; body: %s", rec.Code, c.Status, rec.Body.String())
+	}
+
+	respInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: reqInput,
+		Status:                 rec.Code,
+		Header:                 rec.Header(),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true}, // Undeclared statuses fail
+	}
+	respInput.SetBodyBytes(rec.Body.Bytes())
+	if err := openapi3filter.ValidateResponse(req.Context(), respInput); err != nil {
+		t.Fatalf("response does not match the spec: %v", err)
+	}
+}
+
+// testUserHeader carries Case.UserID to the identity stub the caller
+// registers in place of the auth middleware (see IdentityStub).
+const testUserHeader = "X-Contract-User"
+
+func (h *Harness) newRequest(c Case, body []byte) *http.Request {
+	target := c.Path
+	if c.Query != "" {
+		target += "?" + c.Query
+	}
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req := httptest.NewRequest(c.Method, target, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserID != "" {
+		req.Header.Set(testUserHeader, c.UserID)
+	}
+	return req
+}
+
+// IdentityStub replaces the auth middleware in contract tests: it sets
+// "user_id" from the case, as the real middleware does from the token.
+func IdentityStub() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader(testUserHeader))
+		c.Next()
+	}
+}
@@ -0,0 +1,341 @@
+// Package doctor checks an installation end to end without starting the
+// server: `shepherd doctor`, for installers after a deployment and for
+// support engineers reading a customer's report.
+//
+// Checks run in dependency order and a failed check skips the ones that
+// need it, so the report points at the first cause instead of listing
+// every consequence:
+//
+//	config            file and env load; required settings present
+//	database          connect (hosts, target_session_attrs), primary, server version
+//	migrations        Atlas revisions applied vs migrations shipped; none partial
+//	river             River schema version vs the linked River release
+//	cluster:<name>    credentials resolve for every non-decommissioned cluster
+//	kubevirt:<name>   KubeVirt answers; CDI and version skew reported as warnings
+//
+// Doctor only reads: it never migrates, attaches providers or writes
+// capabilities, so it is safe to run against production.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/doctor
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivermigrate"
+
+	"kv-shepherd.io/shepherd/internal/config"
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/infrastructure"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn" // Works, but needs attention (e.g. CDI missing)
+	StatusFail Status = "fail" // The server would not start or not work
+	StatusSkip Status = "skip" // Not run: a check it depends on failed
+)
+
+// severity orders statuses for the report's overall status.
+var severity = map[Status]int{StatusOK: 0, StatusSkip: 1, StatusWarn: 2, StatusFail: 3}
+
+// Result is one line of the report.
+type Result struct {
+	Check   string        `json:"check"` // e.g. "database", "kubevirt:prod-a"
+	Status  Status        `json:"status"`
+	Message string        `json:"message"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// Report is what `shepherd doctor` prints. Status is the worst result.
+type Report struct {
+	Status    Status    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	Results   []Result  `json:"results"`
+}
+
+// Failed reports whether any check failed; the command exits non-zero.
+func (r *Report) Failed() bool {
+	return r.Status == StatusFail
+}
+
+func (r *Report) add(res Result) {
+	r.Results = append(r.Results, res)
+	if severity[res.Status] > severity[r.Status] {
+		r.Status = res.Status
+	}
+}
+
+// ClusterDeps reach the clusters. Built by the composition root from the
+// database clients, like for the server.
+type ClusterDeps struct {
+	Clusters    repository.ClusterRepository
+	Credentials provider.CredentialProvider
+	Info        provider.ClusterInfoReader
+}
+
+// Options configure a run.
+type Options struct {
+	// MigrationsDir holds the Atlas migration files shipped with the
+	// binary (migrations/atlas in the image). Empty skips the check.
+	MigrationsDir string
+
+	// Timeout bounds each check; the database check also uses it instead
+	// of database.startup_timeout, so an unreachable database fails fast.
+	Timeout time.Duration
+
+	// Clusters builds the cluster dependencies once the database is up.
+	Clusters func(db *infrastructure.DatabaseClients) ClusterDeps
+}
+
+// Run runs the checks and returns the report. Failures are results, not
+// errors: the report is printed either way.
+func Run(ctx context.Context, opts Options) *Report {
+	r := &Report{Status: StatusOK, CheckedAt: time.Now()}
+
+	var cfg *config.Config
+	r.add(timed(ctx, opts.Timeout, "config", func(context.Context) (Status, string) {
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			return StatusFail, fmt.Sprintf("load: %v", err)
+		}
+		if missing := missingSettings(cfg); len(missing) > 0 {
+			return StatusFail, "missing: " + strings.Join(missing, ", ")
+		}
+		return StatusOK, "loaded"
+	}))
+	if r.Status == StatusFail {
+		r.skip("database", "migrations", "river", "clusters")
+		return r
+	}
+
+	var db *infrastructure.DatabaseClients
+	r.add(timed(ctx, opts.Timeout, "database", func(ctx context.Context) (Status, string) {
+		dbCfg := cfg.Database
+		dbCfg.StartupTimeout = opts.Timeout
+		var err error
+		if db, err = infrastructure.NewDatabaseClients(ctx, dbCfg); err != nil {
+			return StatusFail, err.Error()
+		}
+		var version string
+		if err := db.Pool.QueryRow(ctx, "SHOW server_version").Scan(&version); err != nil {
+			return StatusFail, fmt.Sprintf("query server version: %v", err)
+		}
+		return StatusOK, "PostgreSQL " + version
+	}))
+	if db == nil {
+		r.skip("migrations", "river", "clusters")
+		return r
+	}
+	defer db.Close()
+
+	r.add(timed(ctx, opts.Timeout, "migrations", func(ctx context.Context) (Status, string) {
+		return checkMigrations(ctx, db, opts.MigrationsDir, cfg.Database.AutoMigrate)
+	}))
+	r.add(timed(ctx, opts.Timeout, "river", func(ctx context.Context) (Status, string) {
+		return checkRiver(ctx, db)
+	}))
+
+	deps := opts.Clusters(db)
+	var clusters []*domain.Cluster
+	r.add(timed(ctx, opts.Timeout, "clusters", func(ctx context.Context) (Status, string) {
+		var err error
+		if clusters, err = deps.Clusters.List(ctx); err != nil {
+			return StatusFail, fmt.Sprintf("list clusters: %v", err)
+		}
+		if len(clusters) == 0 {
+			return StatusWarn, "no clusters registered"
+		}
+		return StatusOK, fmt.Sprintf("%d registered", len(clusters))
+	}))
+	for _, cluster := range clusters {
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		checkCluster(ctx, opts.Timeout, r, deps, cluster)
+	}
+	return r
+}
+
+func (r *Report) skip(checks ...string) {
+	for _, check := range checks {
+		r.add(Result{Check: check, Status: StatusSkip, Message: "depends on a failed check"})
+	}
+}
+
+// timed runs one check within timeout and records how long it took.
+func timed(ctx context.Context, timeout time.Duration, check string, f func(context.Context) (Status, string)) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, msg := f(ctx)
+	return Result{Check: check, Status: status, Message: msg, Elapsed: time.Since(start)}
+}
+
+// missingSettings lists required settings that are empty. Everything else
+// has a default (config.setDefaults).
+func missingSettings(cfg *config.Config) []string {
+	var missing []string
+	if cfg.Database.Host == "" && len(cfg.Database.Hosts) == 0 {
+		missing = append(missing, "database.host or database.hosts")
+	}
+	if cfg.Database.User == "" {
+		missing = append(missing, "database.user")
+	}
+	if cfg.Database.Database == "" {
+		missing = append(missing, "database.database")
+	}
+	if cfg.Database.PgBouncer && cfg.Database.WorkerHost == "" {
+		missing = append(missing, "database.worker_host (database.pgbouncer is set)")
+	}
+	if cfg.K8s.InstallationID == "" {
+		missing = append(missing, "k8s.installation_id")
+	}
+	return missing
+}
+
+// checkMigrations compares the Atlas revision table with the migration
+// files. Pending migrations fail unless auto_migrate applies them at the
+// next start; revisions unknown to the files (database ahead of this
+// binary, e.g. during a rollback) only warn.
+func checkMigrations(ctx context.Context, db *infrastructure.DatabaseClients, dir string, autoMigrate bool) (Status, string) {
+	if dir == "" {
+		return StatusSkip, "no migrations directory given"
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil || len(files) == 0 {
+		return StatusFail, fmt.Sprintf("no migration files in %s", dir)
+	}
+	shipped := make(map[string]bool, len(files))
+	for _, f := range files {
+		version, _, _ := strings.Cut(filepath.Base(f), "_")
+		shipped[version] = true
+	}
+
+	rows, err := db.Pool.Query(ctx,
+		`SELECT version, applied = total AND error IS NULL
+		   FROM atlas_schema_revisions.atlas_schema_revisions`)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("read atlas revisions (schema never migrated?): %v", err)
+	}
+	defer rows.Close()
+	applied := map[string]bool{}
+	var partial, unknown []string
+	for rows.Next() {
+		var version string
+		var complete bool
+		if err := rows.Scan(&version, &complete); err != nil {
+			return StatusFail, fmt.Sprintf("scan atlas revision: %v", err)
+		}
+		applied[version] = true
+		if !complete {
+			partial = append(partial, version)
+		}
+		if !shipped[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return StatusFail, fmt.Sprintf("read atlas revisions: %v", err)
+	}
+
+	var pending []string
+	for version := range shipped {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	sort.Strings(pending)
+
+	switch {
+	case len(partial) > 0:
+		return StatusFail, "partially applied: " + strings.Join(partial, ", ") + "; fix and re-run atlas migrate apply"
+	case len(pending) > 0 && !autoMigrate:
+		return StatusFail, fmt.Sprintf("%d pending (first %s); run atlas migrate apply", len(pending), pending[0])
+	case len(pending) > 0:
+		return StatusWarn, fmt.Sprintf("%d pending; applied at next start (database.auto_migrate)", len(pending))
+	case len(unknown) > 0:
+		return StatusWarn, "applied but not shipped with this binary: " + strings.Join(unknown, ", ")
+	}
+	return StatusOK, fmt.Sprintf("%d applied, none pending", len(applied))
+}
+
+// checkRiver compares River's schema version with the linked release, on
+// the pool River itself uses.
+func checkRiver(ctx context.Context, db *infrastructure.DatabaseClients) (Status, string) {
+	migrator, err := rivermigrate.New(riverpgxv5.New(db.GetWorkerPool()), nil)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("create river migrator: %v", err)
+	}
+	existing, err := migrator.ExistingVersions(ctx)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("read river migrations: %v", err)
+	}
+	all := migrator.AllVersions()
+	want := all[len(all)-1].Version
+	if len(existing) == 0 {
+		return StatusFail, fmt.Sprintf("river schema missing; run river migrate-up (want version %d)", want)
+	}
+	if have := existing[len(existing)-1].Version; have < want {
+		return StatusFail, fmt.Sprintf("river schema at version %d, want %d; run river migrate-up", have, want)
+	}
+	return StatusOK, fmt.Sprintf("version %d", want)
+}
+
+// checkCluster adds the credential and KubeVirt results of one cluster.
+func checkCluster(ctx context.Context, timeout time.Duration, r *Report, deps ClusterDeps, cluster *domain.Cluster) {
+	credCheck, kvCheck := "cluster:"+cluster.Name, "kubevirt:"+cluster.Name
+
+	res := timed(ctx, timeout, credCheck, func(ctx context.Context) (Status, string) {
+		if _, err := deps.Credentials.GetRESTConfig(ctx, cluster.Name); err != nil {
+			return StatusFail, fmt.Sprintf("%s credentials: %v", deps.Credentials.Type(), err)
+		}
+		return StatusOK, deps.Credentials.Type() + " credentials resolved"
+	})
+	r.add(res)
+	if res.Status == StatusFail {
+		r.skip(kvCheck)
+		return
+	}
+	if cluster.ProviderType != "" && cluster.ProviderType != provider.ProviderTypeKubeVirt {
+		r.add(Result{Check: kvCheck, Status: StatusSkip, Message: "provider type " + cluster.ProviderType})
+		return
+	}
+
+	r.add(timed(ctx, timeout, kvCheck, func(ctx context.Context) (Status, string) {
+		caps, err := provider.NewKubeVirtCapabilityDetector(deps.Info).Detect(ctx, cluster.Name)
+		if err != nil {
+			return StatusFail, err.Error()
+		}
+		msg := "KubeVirt " + caps.KubeVirtVersion
+		if caps.CDIVersion == "" {
+			return StatusWarn, msg + "; CDI not installed"
+		}
+		msg += ", CDI " + caps.CDIVersion
+		if len(caps.Warnings) > 0 {
+			return StatusWarn, msg + "; " + strings.Join(caps.Warnings, "; ")
+		}
+		return StatusOK, msg
+	}))
+}
+
+// Print writes the report as a table, one check per line.
+func Print(w io.Writer, r *Report) {
+	for _, res := range r.Results {
+		fmt.Fprintf(w, "%-5s %-28s %s\n", strings.ToUpper(string(res.Status)), res.Check, res.Message)
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.ToUpper(string(r.Status)))
+}
@@ -0,0 +1,73 @@
+// Package domain provides domain models.
+//
+// This file defines the access scope used to filter list queries.
+//
+// PermissionChecker.CheckPermission answers "may user U do A on resource R?"
+// one resource at a time; post-filtering a page with it leaks counts, breaks
+// pagination and loads rows the caller must not see. List endpoints instead
+// expand the caller's bindings once into an AccessScope and push it into the
+// query as a predicate (see repository.ScopeFilter).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+// AccessScope is the set of resources a user may see for one action.
+type AccessScope struct {
+	UserID string `json:"user_id"`
+	Action string `json:"action"` // e.g. vm:read, service:read
+
+	// All is set by a global binding granting Action in every environment.
+	All bool `json:"all"`
+
+	// Grants from global (environment-restricted), system-scoped and
+	// resource-level bindings. Inheritance is resolved in the query:
+	// a system grant matches the System's Services and VMs.
+	Grants []ScopeGrant `json:"grants,omitempty"`
+}
+
+// ScopeGrant is one expanded binding.
+type ScopeGrant struct {
+	ResourceType ResourceType `json:"resource_type,omitempty"` // "" = any resource (global binding)
+	ResourceID   string       `json:"resource_id,omitempty"`
+
+	// Environments restricts the grant (RoleBinding.allowed_environments).
+	// Nil means every environment. Never empty: a binding with no allowed
+	// environments yields no grant.
+	Environments []string `json:"environments,omitempty"`
+}
+
+// Empty reports whether the scope can match nothing except the caller's
+// own requests, letting callers skip resource predicates entirely.
+func (s *AccessScope) Empty() bool {
+	return !s.All && len(s.Grants) == 0
+}
+
+// Allows evaluates the scope for a single object, with the same semantics
+// as the query predicate. Used for detail endpoints so list and get agree.
+func (s *AccessScope) Allows(systemID, serviceID, vmID, environment string) bool {
+	if s.All {
+		return true
+	}
+	for _, g := range s.Grants {
+		if g.Environments != nil && !containsString(g.Environments, environment) {
+			continue
+		}
+		switch g.ResourceType {
+		case "":
+			return true
+		case ResourceTypeSystem:
+			if g.ResourceID == systemID {
+				return true
+			}
+		case ResourceTypeService:
+			if g.ResourceID == serviceID {
+				return true
+			}
+		case ResourceTypeVM:
+			if vmID != "" && g.ResourceID == vmID {
+				return true
+			}
+		}
+	}
+	return false
+}
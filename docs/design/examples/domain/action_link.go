@@ -0,0 +1,126 @@
+// Package domain provides domain models.
+//
+// This file defines signed one-time action links embedded in approval
+// notification emails, for approvers who work from their mailbox.
+//
+// A link carries only a random ID and an expiry, signed with the platform's
+// action-link key. Ticket, approver and action are read from the stored
+// link, never from the URL, so a link cannot be edited into a different
+// decision. Opening a link (GET) has no side effect: mail scanners prefetch
+// URLs. The decision is made by an explicit confirm (POST).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ActionLinkAction is what a link does when confirmed.
+type ActionLinkAction string
+
+const (
+	ActionLinkView    ActionLinkAction = "view"    // Redirect to the ticket page (reusable until expiry)
+	ActionLinkApprove ActionLinkAction = "approve" // Approve with the requested spec, no modifications
+	ActionLinkReject  ActionLinkAction = "reject"
+)
+
+// MaxRejectReasonLen bounds the free-text reason accepted with a rejection.
+const MaxRejectReasonLen = 500
+
+// ActionLinkActions are issued together for every assigned approver.
+var ActionLinkActions = []ActionLinkAction{ActionLinkView, ActionLinkApprove, ActionLinkReject}
+
+// ActionLink is a stored link (approval_action_links).
+type ActionLink struct {
+	ID         string           `json:"id"`
+	TicketID   string           `json:"ticket_id"`
+	ApproverID string           `json:"approver_id"` // Acts as this user; must still be assigned
+	Action     ActionLinkAction `json:"action"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+	UsedAt     *time.Time       `json:"used_at,omitempty"`
+}
+
+// Decides reports whether confirming the link records a decision.
+// Deciding links are single-use and invalidate the approver's other links.
+func (l *ActionLink) Decides() bool {
+	return l.Action == ActionLinkApprove || l.Action == ActionLinkReject
+}
+
+// actionLinkClaims is the signed token payload.
+type actionLinkClaims struct {
+	ID  string `json:"id"`
+	Exp int64  `json:"exp"`
+}
+
+// SignActionLink returns the URL token for a link:
+// base64url(claims) "." base64url(HMAC-SHA256(key, claims)).
+func SignActionLink(key []byte, link *ActionLink) (string, error) {
+	claims, err := json.Marshal(actionLinkClaims{ID: link.ID, Exp: link.ExpiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(actionLinkMAC(key, payload)), nil
+}
+
+// ParseActionLink verifies a token and returns the link ID it names.
+// The caller must still load the link and call CheckUsable.
+func ParseActionLink(key []byte, token string, now time.Time) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrActionLinkInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, actionLinkMAC(key, payload)) {
+		return "", ErrActionLinkInvalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrActionLinkInvalid
+	}
+	var claims actionLinkClaims
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.ID == "" {
+		return "", ErrActionLinkInvalid
+	}
+	if now.Unix() >= claims.Exp {
+		return "", ErrActionLinkExpired
+	}
+	return claims.ID, nil
+}
+
+func actionLinkMAC(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// CheckUsable validates a stored link against the ticket it decides.
+func (l *ActionLink) CheckUsable(ticket *ApprovalTicket, now time.Time) error {
+	if !now.Before(l.ExpiresAt) {
+		return ErrActionLinkExpired
+	}
+	if l.UsedAt != nil {
+		return ErrActionLinkUsed
+	}
+	if l.Decides() && ticket.Status != TicketPendingApproval {
+		return ErrActionLinkUsed // Decided elsewhere (UI, another approver)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrActionLinkInvalid = domainerr.NotFound("ACTION_LINK_INVALID", "action link invalid")
+	ErrActionLinkExpired = domainerr.Gone("ACTION_LINK_EXPIRED", "action link expired")
+	ErrActionLinkUsed    = domainerr.Conflict("ACTION_LINK_USED", "action link already used")
+
+	ErrRejectReasonRequired = domainerr.Invalid("REJECT_REASON_REQUIRED", "reject reason must be 1-500 characters")
+)
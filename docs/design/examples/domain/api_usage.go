@@ -0,0 +1,127 @@
+// Package domain provides domain models.
+//
+// This file defines API usage metering and per-token quotas. Every
+// authenticated call is counted per principal and token: the signing key
+// of automation calls (signed_request.go), or "session" for the web UI.
+// Users see their own usage; platform admins see everyone's.
+//
+// A quota caps one signing key, per minute and per day, so a runaway
+// script is stopped without touching its owner's interactive use or
+// anyone else. It is enforced apart from the global rate limiter (ADR-0015
+// §19), which protects the platform as a whole: a token under quota can
+// still be throttled globally, and a token over quota is rejected even
+// when the platform is idle.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// SessionToken is the token recorded for calls authenticated by a session
+// rather than a signing key. Sessions have no quota.
+const SessionToken = "session"
+
+// Quota bounds. A quota above these is no quota; remove it instead.
+const (
+	MaxAPIQuotaPerMinute = 10_000
+	MaxAPIQuotaPerDay    = 1_000_000
+)
+
+// MaxAPIUsageDays bounds a usage query; daily rows are kept under the
+// api_usage retention policy (retention.go).
+const MaxAPIUsageDays = 90
+
+// APIQuota caps one signing key. Zero leaves that window unlimited.
+type APIQuota struct {
+	KeyID     string    `json:"key_id"`
+	PerMinute int64     `json:"per_minute"`
+	PerDay    int64     `json:"per_day"`
+	Reason    string    `json:"reason"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks an admin-set quota.
+func (q APIQuota) Validate() error {
+	if q.KeyID == "" || q.KeyID == SessionToken {
+		return fmt.Errorf("key_id must be a signing key: %w", ErrInvalidAPIQuota)
+	}
+	if q.PerMinute == 0 && q.PerDay == 0 {
+		return fmt.Errorf("per_minute or per_day is required: %w", ErrInvalidAPIQuota)
+	}
+	if q.PerMinute < 0 || q.PerMinute > MaxAPIQuotaPerMinute {
+		return fmt.Errorf("per_minute must be 0 to %d: %w", MaxAPIQuotaPerMinute, ErrInvalidAPIQuota)
+	}
+	if q.PerDay < 0 || q.PerDay > MaxAPIQuotaPerDay {
+		return fmt.Errorf("per_day must be 0 to %d: %w", MaxAPIQuotaPerDay, ErrInvalidAPIQuota)
+	}
+	if q.Reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrInvalidAPIQuota)
+	}
+	return nil
+}
+
+// APIQuotaWindows returns the fixed windows a call at now counts in: the
+// minute and the UTC day.
+func APIQuotaWindows(now time.Time) (minute, day time.Time) {
+	now = now.UTC()
+	return now.Truncate(time.Minute), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Exceeded reports whether a call that brought the key's counts to
+// minute and day is over quota, and when to retry: the end of the
+// exhausted window.
+func (q APIQuota) Exceeded(minute, day int64, now time.Time) (time.Duration, bool) {
+	minuteStart, dayStart := APIQuotaWindows(now)
+	switch {
+	case q.PerDay > 0 && day > q.PerDay:
+		return dayStart.AddDate(0, 0, 1).Sub(now), true
+	case q.PerMinute > 0 && minute > q.PerMinute:
+		return minuteStart.Add(time.Minute).Sub(now), true
+	}
+	return 0, false
+}
+
+// APIUsageDay is one principal's calls with one token on one UTC day.
+// Rejected counts calls refused by the token's quota.
+type APIUsageDay struct {
+	Day      time.Time `json:"day"`
+	UserID   string    `json:"user_id"`
+	Token    string    `json:"token"` // Signing key ID or SessionToken
+	Requests int64     `json:"requests"`
+	Rejected int64     `json:"rejected"`
+}
+
+// APIUsage is the usage report of one principal, or of everyone.
+type APIUsage struct {
+	From   time.Time     `json:"from"`
+	Days   []APIUsageDay `json:"days"`
+	Quotas []APIQuota    `json:"quotas,omitempty"` // Of the tokens listed
+}
+
+// ParseAPIUsageDays parses ?days= (default 30, at most MaxAPIUsageDays).
+func ParseAPIUsageDays(s string) (int, error) {
+	if s == "" {
+		return 30, nil
+	}
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 1 || days > MaxAPIUsageDays {
+		return 0, fmt.Errorf("days must be 1 to %d: %w", MaxAPIUsageDays, ErrInvalidAPIUsageQuery)
+	}
+	return days, nil
+}
+
+// Errors
+var (
+	ErrInvalidAPIQuota      = domainerr.Invalid("INVALID_API_QUOTA", "invalid API quota")
+	ErrInvalidAPIUsageQuery = domainerr.Invalid("INVALID_API_USAGE_QUERY", "invalid API usage query")
+
+	// The middleware writes its code and params
+	ErrAPIQuotaExceeded = domainerr.QuotaExceeded("API_QUOTA_EXCEEDED", "API quota exceeded")
+)
@@ -0,0 +1,176 @@
+// Package domain provides domain models.
+//
+// This file defines the approval analytics report used in governance
+// reviews: the approval funnel per request type, decision times per
+// approver group and the breakdown of rejection reasons, over a selectable
+// window.
+//
+// The report is computed from approval_tickets and its approval rows on
+// demand (read-only, platform admins). Tickets are counted in the window
+// they were submitted in; decisions in the window they were made in, so a
+// window's decision times are stable once it has passed.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Analytics window presets (?window=).
+var analyticsPresets = map[string]time.Duration{
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// DefaultAnalyticsWindow is used when neither a preset nor a range is given.
+const DefaultAnalyticsWindow = "30d"
+
+// MaxAnalyticsWindow bounds a custom range (one year plus a leap day).
+const MaxAnalyticsWindow = 366 * 24 * time.Hour
+
+// MaxRejectionReasons is how many distinct reasons the breakdown lists;
+// the rest are counted under RejectionReasonOther.
+const MaxRejectionReasons = 20
+
+// RejectionReasonOther groups the reasons beyond MaxRejectionReasons.
+const RejectionReasonOther = "(other)"
+
+// AnalyticsWindow is the half-open interval [From, To) a report covers.
+type AnalyticsWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ParseAnalyticsWindow resolves ?window= or ?from=&to= (RFC 3339).
+// A range takes precedence; a preset ends now; to defaults to now.
+func ParseAnalyticsWindow(preset, from, to string, now time.Time) (AnalyticsWindow, error) {
+	if from == "" && to == "" {
+		if preset == "" {
+			preset = DefaultAnalyticsWindow
+		}
+		d, ok := analyticsPresets[preset]
+		if !ok {
+			return AnalyticsWindow{}, fmt.Errorf("window must be 7d, 30d or 90d: %w", ErrInvalidAnalyticsWindow)
+		}
+		return AnalyticsWindow{From: now.Add(-d), To: now}, nil
+	}
+
+	w := AnalyticsWindow{To: now}
+	var err error
+	if w.From, err = time.Parse(time.RFC3339, from); err != nil {
+		return AnalyticsWindow{}, fmt.Errorf("from: %v: %w", err, ErrInvalidAnalyticsWindow)
+	}
+	if to != "" {
+		if w.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return AnalyticsWindow{}, fmt.Errorf("to: %v: %w", err, ErrInvalidAnalyticsWindow)
+		}
+	}
+	if !w.From.Before(w.To) {
+		return AnalyticsWindow{}, fmt.Errorf("from must be before to: %w", ErrInvalidAnalyticsWindow)
+	}
+	if w.To.Sub(w.From) > MaxAnalyticsWindow {
+		return AnalyticsWindow{}, fmt.Errorf("range exceeds %d days: %w", int(MaxAnalyticsWindow.Hours()/24), ErrInvalidAnalyticsWindow)
+	}
+	return w, nil
+}
+
+// ApprovalVolume is the funnel of one request type: tickets submitted in
+// the window and where they stand now.
+type ApprovalVolume struct {
+	RequestType  string `json:"request_type"`
+	Submitted    int    `json:"submitted"`
+	AutoApproved int    `json:"auto_approved"` // required_approvals = 0
+	Approved     int    `json:"approved"`      // By people, whatever happened after
+	Rejected     int    `json:"rejected"`
+	Cancelled    int    `json:"cancelled"`
+	Expired      int    `json:"expired"`
+	Pending      int    `json:"pending"`
+}
+
+// AutoApprovalRate is the share of submitted tickets approved by policy.
+func (v ApprovalVolume) AutoApprovalRate() float64 {
+	if v.Submitted == 0 {
+		return 0
+	}
+	return float64(v.AutoApproved) / float64(v.Submitted)
+}
+
+// DecisionTime summarizes the decisions of one approver group: the time
+// from submission to each approval or rejection made in the window.
+type DecisionTime struct {
+	ApproverGroup ApproverSource `json:"approver_group"`
+	Decisions     int            `json:"decisions"`
+	Rejections    int            `json:"rejections"`
+	MedianSeconds float64        `json:"median_seconds"`
+	P90Seconds    float64        `json:"p90_seconds"`
+}
+
+// RejectionReasonCount is one row of the rejection breakdown. Reasons are
+// free text, grouped case- and whitespace-insensitively.
+type RejectionReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// NormalizeRejectReason is the grouping key of a rejection reason; the
+// SQL query applies the same rule (lower, trim, collapse spaces).
+func NormalizeRejectReason(reason string) string {
+	return strings.Join(strings.Fields(strings.ToLower(reason)), " ")
+}
+
+// FoldRejectionReasons keeps the MaxRejectionReasons most frequent
+// reasons, most frequent first, and counts the rest as one
+// RejectionReasonOther row. rows must be sorted by count descending.
+func FoldRejectionReasons(rows []RejectionReasonCount) []RejectionReasonCount {
+	if len(rows) <= MaxRejectionReasons {
+		return rows
+	}
+	folded := append([]RejectionReasonCount(nil), rows[:MaxRejectionReasons]...)
+	other := RejectionReasonCount{Reason: RejectionReasonOther}
+	for _, r := range rows[MaxRejectionReasons:] {
+		other.Count += r.Count
+	}
+	return append(folded, other)
+}
+
+// ApprovalAnalytics is the full report for one window.
+type ApprovalAnalytics struct {
+	Window           AnalyticsWindow        `json:"window"`
+	Volume           []ApprovalVolume       `json:"volume"`
+	Total            ApprovalVolume         `json:"total"` // RequestType empty
+	AutoApprovalRate float64                `json:"auto_approval_rate"`
+	DecisionTimes    []DecisionTime         `json:"decision_times"`
+	RejectionReasons []RejectionReasonCount `json:"rejection_reasons"`
+}
+
+// NewApprovalAnalytics assembles the report and its totals.
+func NewApprovalAnalytics(w AnalyticsWindow, volume []ApprovalVolume, times []DecisionTime, reasons []RejectionReasonCount) *ApprovalAnalytics {
+	a := &ApprovalAnalytics{
+		Window:           w,
+		Volume:           volume,
+		DecisionTimes:    times,
+		RejectionReasons: FoldRejectionReasons(reasons),
+	}
+	for _, v := range volume {
+		a.Total.Submitted += v.Submitted
+		a.Total.AutoApproved += v.AutoApproved
+		a.Total.Approved += v.Approved
+		a.Total.Rejected += v.Rejected
+		a.Total.Cancelled += v.Cancelled
+		a.Total.Expired += v.Expired
+		a.Total.Pending += v.Pending
+	}
+	a.AutoApprovalRate = a.Total.AutoApprovalRate()
+	return a
+}
+
+// Errors
+var (
+	ErrInvalidAnalyticsWindow = domainerr.Invalid("INVALID_ANALYTICS_WINDOW", "invalid analytics window")
+)
@@ -0,0 +1,72 @@
+// Package domain provides domain models.
+//
+// This file defines the placement choices an approver records in a
+// ModifiedSpec for a VM creation: the cluster (ADR-0017) and the
+// InstanceSize (ADR-0018).
+//
+// Both are snapshots taken at approval. The size is expanded into cpu,
+// memory_mb and gpus when the approval is stored, so quota, spec diff and the
+// worker see the resources approved even if the size is edited later;
+// instance_size_id and the pinned instance_size_version are kept for
+// display, the instancetype reference and the outdated-size report
+// (instance_size_version.go). The cluster is checked against the ticket's environment and
+// its placement state at the final approval, and the worker creates the
+// VM there instead of running weight-based selection (ADR-0015 §15).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"slices"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ApplyInstanceSize records size, at size.Version, as the approved
+// InstanceSize and sets CPU, MemoryMB and GPUs from it. An approver picks a size or explicit
+// resources, not both: CPU, MemoryMB or GPUs already set to other values
+// than the size's is an error (the same values, as in a spec stored by an
+// earlier approver, are accepted).
+func (m *ModifiedSpec) ApplyInstanceSize(size *InstanceSize) error {
+	if !size.Enabled {
+		return fmt.Errorf("instance size %s: %w", size.Name, ErrInstanceSizeDisabled)
+	}
+	memoryMB, err := parseMemoryMB(size.Memory)
+	if err != nil {
+		return fmt.Errorf("instance size %s: %w", size.Name, err)
+	}
+	cpu := size.CPUCores
+	if (m.CPU != nil && *m.CPU != cpu) || (m.MemoryMB != nil && *m.MemoryMB != memoryMB) ||
+		(m.GPUs != nil && !slices.Equal(m.GPUs, size.GPUs)) {
+		return fmt.Errorf("instance size %s: %w", size.Name, ErrInstanceSizeWithResources)
+	}
+	version := size.Version
+	m.InstanceSizeID = &size.ID
+	m.InstanceSizeVersion = &version
+	m.CPU = &cpu
+	m.MemoryMB = &memoryMB
+	m.GPUs = size.GPUs
+	return nil
+}
+
+// CheckApprovalCluster checks that a VM of environment may be placed on c
+// by an approver: same environment (ADR-0015 §15) and accepting new
+// placements, as in the approval dropdown (FilterPlacementCandidates).
+func CheckApprovalCluster(c *Cluster, environment string) error {
+	if c.Environment != environment {
+		return fmt.Errorf("cluster %s is %s, ticket is %s: %w", c.Name, c.Environment, environment, ErrClusterEnvironmentMismatch)
+	}
+	if !c.AcceptsPlacements() {
+		return fmt.Errorf("cluster %s (%s, %s): %w", c.Name, c.Lifecycle, c.Status, ErrClusterNotPlaceable)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInstanceSizeWithResources  = domainerr.Invalid("INSTANCE_SIZE_WITH_RESOURCES", "instance_size_id cannot be combined with cpu or memory_mb")
+	ErrInstanceSizeDisabled       = domainerr.Invalid("INSTANCE_SIZE_DISABLED", "instance size is disabled")
+	ErrClusterEnvironmentMismatch = domainerr.Invalid("CLUSTER_ENVIRONMENT_MISMATCH", "cluster is not in the ticket's environment")
+	ErrClusterNotPlaceable        = domainerr.Conflict("CLUSTER_NOT_PLACEABLE", "cluster does not accept new VMs")
+)
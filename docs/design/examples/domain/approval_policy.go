@@ -0,0 +1,181 @@
+// Package domain provides domain models.
+//
+// This file defines approval rules, which route a request to automatic
+// approval or to the manual ticket path.
+//
+// Rules are evaluated in priority order and the first match decides. When
+// no rule matches, the environment policy's auto-approve limit decides, as
+// before rules existed. Approval policy expressions (policy_expression.go)
+// share the priority order with rules. Rules can never auto-approve what
+// the environment policy forbids: prod requests and requests that need
+// staged approval always go through a ticket.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ApprovalAction is what a matching rule does with a request.
+type ApprovalAction string
+
+const (
+	ApprovalActionAuto   ApprovalAction = "auto_approve"
+	ApprovalActionManual ApprovalAction = "manual"
+)
+
+// ApprovalRule is one routing rule (approval_rules, editable by platform
+// admins). Empty or zero conditions match every request.
+type ApprovalRule struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"` // Lower runs first
+	Enabled  bool   `json:"enabled"`
+
+	RequestType    string   `json:"request_type,omitempty"`    // CREATE_VM, MODIFY_VM
+	Namespace      string   `json:"namespace,omitempty"`       // Glob, e.g. "dev-*"
+	RequesterRoles []string `json:"requester_roles,omitempty"` // Any of: global roles or Service/System roles
+	MaxCPU         int      `json:"max_cpu,omitempty"`
+	MaxMemoryMB    int      `json:"max_memory_mb,omitempty"`
+
+	Action    ApprovalAction `json:"action"`
+	CreatedBy string         `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Validate checks a rule before it is saved.
+func (r *ApprovalRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required: %w", ErrInvalidApprovalRule)
+	}
+	if r.Action != ApprovalActionAuto && r.Action != ApprovalActionManual {
+		return fmt.Errorf("unknown action %q: %w", r.Action, ErrInvalidApprovalRule)
+	}
+	if r.MaxCPU < 0 || r.MaxMemoryMB < 0 {
+		return fmt.Errorf("limits must not be negative: %w", ErrInvalidApprovalRule)
+	}
+	if _, err := path.Match(r.Namespace, ""); err != nil {
+		return fmt.Errorf("namespace pattern: %v: %w", err, ErrInvalidApprovalRule)
+	}
+	return nil
+}
+
+// ApprovalRequest is what rules are matched against.
+type ApprovalRequest struct {
+	RequestType    string
+	Namespace      string
+	CPU            int // Resulting size for MODIFY_VM
+	MemoryMB       int
+	DiskGB         int // Policy expressions only; rules do not limit disk
+	RequesterRoles []string
+}
+
+// Matches reports whether every condition of the rule holds for req.
+func (r *ApprovalRule) Matches(req ApprovalRequest) bool {
+	if !r.Enabled {
+		return false
+	}
+	if r.RequestType != "" && r.RequestType != req.RequestType {
+		return false
+	}
+	if r.Namespace != "" {
+		if ok, _ := path.Match(r.Namespace, req.Namespace); !ok {
+			return false
+		}
+	}
+	if (r.MaxCPU != 0 && req.CPU > r.MaxCPU) || (r.MaxMemoryMB != 0 && req.MemoryMB > r.MaxMemoryMB) {
+		return false
+	}
+	if len(r.RequesterRoles) > 0 {
+		for _, role := range req.RequesterRoles {
+			if containsString(r.RequesterRoles, role) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// NeedRequesterRoles reports whether any rule conditions on requester
+// roles, i.e. whether they must be resolved before matching.
+func NeedRequesterRoles(rules []*ApprovalRule) bool {
+	for _, r := range rules {
+		if len(r.RequesterRoles) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RequesterRoles merges the requester's global roles with their unexpired
+// roles on the Service and its System, for matching RequesterRoles.
+func RequesterRoles(userID string, globalRoles []string, serviceBindings, systemBindings []*ResourceRoleBinding, now time.Time) []string {
+	roles := append([]string(nil), globalRoles...)
+	for _, bindings := range [][]*ResourceRoleBinding{serviceBindings, systemBindings} {
+		for _, b := range bindings {
+			if b.UserID == userID && !bindingExpired(b, now) && !containsString(roles, b.Role) {
+				roles = append(roles, b.Role)
+			}
+		}
+	}
+	return roles
+}
+
+// ApprovalRoute is the routing outcome for a request.
+type ApprovalRoute struct {
+	AutoApprove bool   `json:"auto_approve"`
+	RuleID      string `json:"rule_id,omitempty"` // Rule or policy expression; empty: environment policy decided
+	Reason      string `json:"reason"`            // Shown to the requester
+}
+
+// RouteApproval applies rules to req on top of the environment decision.
+func RouteApproval(rules []*ApprovalRule, req ApprovalRequest, env EnvironmentDecision) ApprovalRoute {
+	return RouteApprovalWithPolicies(rules, nil, req, env)
+}
+
+// RouteApprovalWithPolicies applies rules and the results of the approval
+// policy expressions for req (guardrail results are ignored) on top of
+// the environment decision. The first deciding rule or policy by priority
+// wins; a rule wins a tie.
+func RouteApprovalWithPolicies(rules []*ApprovalRule, results []PolicyResult, req ApprovalRequest, env EnvironmentDecision) ApprovalRoute {
+	sorted := make([]*ApprovalRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+
+	route := ApprovalRoute{AutoApprove: env.AutoApprove, Reason: "environment policy"}
+	priority, decided := 0, false
+	for _, r := range sorted {
+		if r.Matches(req) {
+			route = ApprovalRoute{AutoApprove: r.Action == ApprovalActionAuto, RuleID: r.ID, Reason: "rule " + r.Name}
+			priority, decided = r.Priority, true
+			break
+		}
+	}
+	for _, r := range results {
+		if r.Policy.Kind != PolicyKindApproval || !r.Decides() {
+			continue
+		}
+		if !decided || r.Policy.Priority < priority {
+			route = r.route()
+			priority, decided = r.Policy.Priority, true
+		}
+	}
+
+	// Rules only loosen within what the environment permits
+	if route.AutoApprove && !env.AllowsAutoApproval() {
+		route = ApprovalRoute{AutoApprove: false, RuleID: route.RuleID, Reason: fmt.Sprintf("%s requires approval", env.Environment)}
+	}
+	return route
+}
+
+// Errors
+var (
+	ErrInvalidApprovalRule = domainerr.Invalid("INVALID_APPROVAL_RULE", "invalid approval rule")
+)
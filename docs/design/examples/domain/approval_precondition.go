@@ -0,0 +1,160 @@
+// Package domain provides domain models.
+//
+// This file defines the quota and capacity preconditions re-checked on
+// the final approval, right before the quota reservation and River job
+// insert (ADR-0012: same transaction).
+//
+// Submission does not reserve anything, and a ticket may wait days for
+// approval: by then other approvals may have used up the Service's or
+// System's quota, or the cluster may have filled up. Approving anyway
+// only moves the failure to the worker, after the requester was told
+// "approved". A failed precondition instead leaves the ticket pending and
+// tells the approver what is missing, so they can shrink the spec, pick
+// another cluster or reject.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// QuotaAmount is an amount of Shepherd-level quota (see quota.go).
+type QuotaAmount struct {
+	CPU      int `json:"cpu"`
+	MemoryMB int `json:"memory_mb"`
+	DiskGB   int `json:"disk_gb"`
+	VMCount  int `json:"vm_count"`
+}
+
+// Amount returns what the reservation would hold.
+func (r *QuotaReservation) Amount() QuotaAmount {
+	return QuotaAmount{CPU: r.CPU, MemoryMB: r.MemoryMB, DiskGB: r.DiskGB, VMCount: r.VMCount}
+}
+
+// IsZero reports whether nothing is requested, e.g. a resize that only
+// decreases.
+func (a QuotaAmount) IsZero() bool {
+	return a == QuotaAmount{}
+}
+
+// PreconditionScope is what a shortfall is against.
+type PreconditionScope string
+
+const (
+	PreconditionService PreconditionScope = "service" // Service quota
+	PreconditionSystem  PreconditionScope = "system"  // System quota, shared by its Services
+	PreconditionCluster PreconditionScope = "cluster" // Cluster headroom
+)
+
+// QuotaStanding is one quota a request counts against. Limit fields of
+// zero are unlimited. Used counts live VMs plus HELD reservations, so
+// approved-but-not-yet-created VMs are included.
+type QuotaStanding struct {
+	Scope   PreconditionScope
+	ScopeID string
+	Limit   QuotaAmount
+	Used    QuotaAmount
+}
+
+// ClusterHeadroom is a cluster's free capacity as last observed by the
+// health checker (Phase 2 §4): allocatable minus requested, over
+// schedulable nodes. A snapshot, not a reservation: the scheduler still
+// has the final word, the check only catches clusters already known full.
+type ClusterHeadroom struct {
+	Cluster    string    `json:"cluster"` // Cluster name, as VM.Cluster
+	CPU        int       `json:"cpu"`
+	MemoryMB   int       `json:"memory_mb"`
+	ObservedAt time.Time `json:"observed_at"`
+
+	// GPUs is the free count of each GPU model, allocatable minus
+	// requested over schedulable nodes; models without any free are absent.
+	GPUs map[string]int `json:"gpus,omitempty"`
+}
+
+// Shortfall is one resource a request does not fit in.
+type Shortfall struct {
+	Scope     PreconditionScope `json:"scope"`
+	ScopeID   string            `json:"scope_id"`
+	Resource  string            `json:"resource"` // cpu, memory_mb, disk_gb, vm_count, gpu:{device_name}
+	Requested int               `json:"requested"`
+	Available int               `json:"available"` // Never negative
+}
+
+// CheckQuota returns the shortfalls of req against each standing.
+func CheckQuota(standings []QuotaStanding, req QuotaAmount) []Shortfall {
+	var out []Shortfall
+	for _, s := range standings {
+		for _, r := range []struct {
+			name             string
+			limit, used, req int
+		}{
+			{"cpu", s.Limit.CPU, s.Used.CPU, req.CPU},
+			{"memory_mb", s.Limit.MemoryMB, s.Used.MemoryMB, req.MemoryMB},
+			{"disk_gb", s.Limit.DiskGB, s.Used.DiskGB, req.DiskGB},
+			{"vm_count", s.Limit.VMCount, s.Used.VMCount, req.VMCount},
+		} {
+			if r.limit == 0 || r.req == 0 || r.used+r.req <= r.limit {
+				continue
+			}
+			out = append(out, Shortfall{
+				Scope:     s.Scope,
+				ScopeID:   s.ScopeID,
+				Resource:  r.name,
+				Requested: r.req,
+				Available: max(r.limit-r.used, 0),
+			})
+		}
+	}
+	return out
+}
+
+// CheckHeadroom returns the shortfalls of req and gpus on cluster h. Disk
+// is not checked: volumes are provisioned by the storage class, not the
+// nodes. GPUs are counted over the whole cluster, not per node.
+func CheckHeadroom(h ClusterHeadroom, req QuotaAmount, gpus []GPUDevice) []Shortfall {
+	var out []Shortfall
+	if req.CPU > h.CPU {
+		out = append(out, Shortfall{Scope: PreconditionCluster, ScopeID: h.Cluster, Resource: "cpu", Requested: req.CPU, Available: max(h.CPU, 0)})
+	}
+	if req.MemoryMB > h.MemoryMB {
+		out = append(out, Shortfall{Scope: PreconditionCluster, ScopeID: h.Cluster, Resource: "memory_mb", Requested: req.MemoryMB, Available: max(h.MemoryMB, 0)})
+	}
+	for _, g := range gpus {
+		if free := h.GPUs[g.DeviceName]; g.Count > free {
+			out = append(out, Shortfall{Scope: PreconditionCluster, ScopeID: h.Cluster, Resource: "gpu:" + g.DeviceName, Requested: g.Count, Available: max(free, 0)})
+		}
+	}
+	return out
+}
+
+// PreconditionFailed is the typed error returned when the final approval
+// would exceed a quota or the target cluster's headroom. Nothing is
+// written: the ticket stays PENDING_APPROVAL and the approval is not
+// recorded.
+type PreconditionFailed struct {
+	TicketID   string      `json:"ticket_id"`
+	Shortfalls []Shortfall `json:"shortfalls"`
+}
+
+func (e *PreconditionFailed) Error() string {
+	parts := make([]string, len(e.Shortfalls))
+	for i, s := range e.Shortfalls {
+		parts[i] = fmt.Sprintf("%s %s %s: requested %d, available %d", s.Scope, s.ScopeID, s.Resource, s.Requested, s.Available)
+	}
+	return fmt.Sprintf("approval precondition failed (ticket %s): %s", e.TicketID, strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrApprovalPreconditionFailed with the shortfalls as a
+// param, so handlers write it with writeError (409).
+func (e *PreconditionFailed) Unwrap() error {
+	return ErrApprovalPreconditionFailed.With("shortfalls", e.Shortfalls)
+}
+
+// ErrApprovalPreconditionFailed is the coded error every
+// *PreconditionFailed unwraps to.
+var ErrApprovalPreconditionFailed = domainerr.PreconditionFailed("APPROVAL_PRECONDITION_FAILED", "approval precondition failed")
@@ -0,0 +1,61 @@
+// Package domain provides domain models.
+//
+// This file defines approval SLAs.
+//
+// Each environment policy sets how long a ticket may wait for approval;
+// higher priorities get less. The due time is computed at submission and
+// frozen on the ticket (sla_due_at), like the required approvals. The
+// ticket_sla periodic job escalates a ticket still pending after its due
+// time exactly once: it sets escalated_at, notifies the approvers of the
+// current stage and the platform approvers, and audits approval.escalated.
+// Escalation only raises attention; it does not change who may approve.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// DefaultApprovalSLA applies when an environment policy sets none.
+const DefaultApprovalSLA = 24 * time.Hour
+
+// EmergencyApprovalSLA caps the SLA of emergency tickets, however long
+// the environment's SLA is.
+const EmergencyApprovalSLA = time.Hour
+
+// ApprovalSLA returns how long a ticket of priority p may stay pending
+// under an environment SLA of base.
+func (p Priority) ApprovalSLA(base time.Duration) time.Duration {
+	if base <= 0 {
+		base = DefaultApprovalSLA
+	}
+	switch p {
+	case PriorityEmergency:
+		return min(base, EmergencyApprovalSLA)
+	case PriorityHigh:
+		return base / 2
+	default:
+		return base
+	}
+}
+
+// SLADueAt returns when a ticket submitted at submitted becomes overdue.
+func SLADueAt(submitted time.Time, base time.Duration, p Priority) *time.Time {
+	due := submitted.Add(p.ApprovalSLA(base))
+	return &due
+}
+
+// Overdue reports whether the ticket is still pending past its due time.
+func (t *ApprovalTicket) Overdue(now time.Time) bool {
+	return t.Status == TicketPendingApproval && t.SLADueAt != nil && now.After(*t.SLADueAt)
+}
+
+// SLAOverdueCount is one row of the approval SLA report: pending tickets
+// of one environment and priority.
+type SLAOverdueCount struct {
+	Environment DeploymentEnvironment `json:"environment"`
+	Priority    Priority              `json:"priority"`
+	Pending     int                   `json:"pending"`
+	Overdue     int                   `json:"overdue"`
+	Escalated   int                   `json:"escalated"`
+	OldestDueAt *time.Time            `json:"oldest_due_at,omitempty"` // Earliest due time among overdue tickets
+}
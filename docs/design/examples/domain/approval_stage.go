@@ -0,0 +1,111 @@
+// Package domain provides domain models.
+//
+// This file defines ordered approval stages.
+//
+// A ticket is approved stage by stage; each stage has its own approver
+// role and number of distinct approvals. Only the last stage's final
+// approval enqueues execution. Tickets without explicit stages have one
+// stage of resource approvers with the ticket's RequiredApprovals, which
+// is the behavior before stages existed.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// StageRole selects who is assigned to a stage.
+type StageRole string
+
+const (
+	// StageRoleResourceApprover: owner/admin bindings on the Service and
+	// its System (the team lead), see ResolveApprovers.
+	StageRoleResourceApprover StageRole = "resource_approver"
+
+	// StageRolePlatformAdmin: global holders of approval:approve.
+	StageRolePlatformAdmin StageRole = "platform_admin"
+)
+
+// ApprovalStage is one step of an approval chain.
+type ApprovalStage struct {
+	Name              string    `json:"name"` // Shown to approvers, e.g. "team lead"
+	Role              StageRole `json:"role"`
+	RequiredApprovals int       `json:"required_approvals"`
+}
+
+// StagedApproval is an environment policy rule: requests exceeding Above
+// go through Stages instead of the single default stage.
+type StagedApproval struct {
+	Above  SizeLimit       `json:"above"`
+	Stages []ApprovalStage `json:"stages"`
+}
+
+// Validate checks the chain before the policy is saved.
+func (s *StagedApproval) Validate() error {
+	if len(s.Stages) == 0 {
+		return fmt.Errorf("staged approval needs at least one stage: %w", ErrInvalidEnvironmentPolicy)
+	}
+	for i, stage := range s.Stages {
+		if stage.Role != StageRoleResourceApprover && stage.Role != StageRolePlatformAdmin {
+			return fmt.Errorf("stage %d: unknown role %q: %w", i, stage.Role, ErrInvalidEnvironmentPolicy)
+		}
+		if stage.RequiredApprovals < 1 {
+			return fmt.Errorf("stage %d: required_approvals must be at least 1: %w", i, ErrInvalidEnvironmentPolicy)
+		}
+	}
+	return nil
+}
+
+// TotalApprovals is the number of distinct people the chain needs.
+// An approver counts in one stage only (SoDRuleRepeatApprover).
+func TotalApprovals(stages []ApprovalStage) int {
+	total := 0
+	for _, stage := range stages {
+		total += stage.RequiredApprovals
+	}
+	return total
+}
+
+// TicketStages returns a ticket's stages; tickets stored without stages
+// have the single default stage.
+func TicketStages(stages []ApprovalStage, requiredApprovals int) []ApprovalStage {
+	if len(stages) > 0 {
+		return stages
+	}
+	return DefaultStages(requiredApprovals)
+}
+
+// DefaultStages is the single stage of resource approvers.
+func DefaultStages(requiredApprovals int) []ApprovalStage {
+	return []ApprovalStage{{
+		Name:              "approval",
+		Role:              StageRoleResourceApprover,
+		RequiredApprovals: requiredApprovals,
+	}}
+}
+
+// ResolveStageApprovers assigns approvers per stage (TicketApprover.Stage).
+// A user may be assigned to several stages but approves in one only.
+// Every stage must have at least as many approvers as it requires.
+func ResolveStageApprovers(ticketID, requester string, stages []ApprovalStage, serviceBindings, systemBindings []*ResourceRoleBinding, platformAdmins []string, now time.Time) ([]*TicketApprover, error) {
+	var result []*TicketApprover
+	for i, stage := range stages {
+		var assigned []*TicketApprover
+		switch stage.Role {
+		case StageRoleResourceApprover:
+			assigned = ResolveApprovers(ticketID, requester, serviceBindings, systemBindings, platformAdmins, now)
+		case StageRolePlatformAdmin:
+			assigned = ResolveApprovers(ticketID, requester, nil, nil, platformAdmins, now)
+		}
+		if len(assigned) < stage.RequiredApprovals {
+			return nil, fmt.Errorf("stage %q: %w", stage.Name, ErrNoEligibleApprover)
+		}
+		for _, a := range assigned {
+			a.Stage = i
+		}
+		result = append(result, assigned...)
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+// Package domain provides domain models.
+//
+// This file defines the ApprovalTicket read model (Phase 4 §4).
+// Writes go through sqlc in the use case transaction (ADR-0012).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// TicketStatus is the ApprovalTicket status (Phase 4 §4 Status Flow).
+type TicketStatus string
+
+const (
+	TicketPendingApproval TicketStatus = "PENDING_APPROVAL"
+	TicketApproved        TicketStatus = "APPROVED"
+	TicketRejected        TicketStatus = "REJECTED"  // Terminal
+	TicketCancelled       TicketStatus = "CANCELLED" // Terminal
+	TicketExpired         TicketStatus = "EXPIRED"   // Terminal: pending past its TTL (ticket_expiry.go)
+	TicketExecuting       TicketStatus = "EXECUTING"
+	TicketSuccess         TicketStatus = "SUCCESS" // Terminal
+	TicketFailed          TicketStatus = "FAILED"  // Terminal
+)
+
+// ApprovalTicket is a request awaiting or past approval.
+type ApprovalTicket struct {
+	TicketID      string       `json:"ticket_id"`
+	EventID       string       `json:"event_id"`
+	ServiceID     string       `json:"service_id"` // Immutable after submission (ADR-0017)
+	RequestType   string       `json:"request_type"`
+	RequestReason string       `json:"request_reason"`
+	Status        TicketStatus `json:"status"`
+	Priority      Priority     `json:"priority"`
+
+	// Environment, RequiredApprovals and Stages are frozen from the
+	// Service's environment policy at submission. Empty Stages means one
+	// stage of RequiredApprovals (see TicketStages); otherwise the stages
+	// decide and RequiredApprovals is informational.
+	Environment       DeploymentEnvironment `json:"environment"`
+	RequiredApprovals int                   `json:"required_approvals"`
+	Stages            []ApprovalStage       `json:"stages,omitempty"`
+	CurrentStage      int                   `json:"current_stage"`
+
+	// ResizePlan (MODIFY_VM only) tells the approver whether the resize
+	// is applied live or needs a restart.
+	ResizePlan *ResizePlan `json:"resize_plan,omitempty"`
+
+	// ResubmittedFrom links a resubmission to the rejected ticket it
+	// revises (resubmission.go).
+	ResubmittedFrom string `json:"resubmitted_from,omitempty"`
+
+	ModifiedSpec []byte `json:"modified_spec,omitempty"` // See GetEffectiveSpec
+	CreatedBy    string `json:"created_by"`
+	ApprovedBy   string `json:"approved_by,omitempty"`
+	RejectedBy   string `json:"rejected_by,omitempty"`
+
+	// Set when a delegate decided: the delegator whose assignment was used
+	// (ApprovedBy/RejectedBy is the delegate).
+	ApprovedOnBehalfOf string `json:"approved_on_behalf_of,omitempty"`
+	RejectedOnBehalfOf string `json:"rejected_on_behalf_of,omitempty"`
+
+	// SLADueAt is frozen at submission (see approval_sla.go); EscalatedAt
+	// is set once by the ticket_sla job when the ticket is overdue.
+	SLADueAt    *time.Time `json:"sla_due_at,omitempty"`
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+
+	// ExecuteAt is the planned execution time set by an approver
+	// (execution_schedule.go); nil executes on final approval.
+	// ExecutionJobID is the River job executing the approved ticket.
+	ExecuteAt      *time.Time `json:"execute_at,omitempty"`
+	ExecutionJobID int64      `json:"-"`
+
+	RejectReason string    `json:"reject_reason,omitempty"`
+	CancelReason string    `json:"cancel_reason,omitempty"` // Set when the requester cancels
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ApprovalProgress reports where a ticket stands after an approval.
+// With RequiredApprovals > 1 (prod: two-person rule) or several stages
+// only the last approval of the last stage enqueues execution.
+// Approvals and Required refer to Stage.
+type ApprovalProgress struct {
+	Approved  bool `json:"approved"` // Final approval given, execution enqueued
+	Approvals int  `json:"approvals"`
+	Required  int  `json:"required"`
+	Stage     int  `json:"stage"`  // Stage awaiting approval (last stage once approved)
+	Stages    int  `json:"stages"` // Number of stages
+
+	OnBehalfOf string `json:"on_behalf_of,omitempty"` // Delegator, if the approver acted as delegate
+
+	ExecuteAt *time.Time `json:"execute_at,omitempty"` // Planned execution; nil executes now (or not yet approved)
+}
+
+// ValidateRejectReason checks the reason required with every rejection.
+func ValidateRejectReason(reason string) error {
+	if reason == "" || len(reason) > MaxRejectReasonLen {
+		return ErrRejectReasonRequired
+	}
+	return nil
+}
+
+// CheckCancel checks that userID may cancel a ticket with the given
+// status and requester: only the requester, and only while no approver
+// has decided. reason is optional.
+func CheckCancel(status TicketStatus, createdBy, userID, reason string) error {
+	if createdBy != userID {
+		return ErrNotRequester
+	}
+	if status != TicketPendingApproval {
+		return ErrTicketNotPending
+	}
+	if len(reason) > MaxRejectReasonLen {
+		return ErrCancelReasonTooLong
+	}
+	return nil
+}
+
+// RequestCancelledPayload is the payload of REQUEST_CANCELLED, recorded
+// when a requester withdraws a pending ticket. The cancelled request's own
+// event is set CANCELLED in the same transaction.
+type RequestCancelledPayload struct {
+	TicketID    string `json:"ticket_id"`
+	EventID     string `json:"event_id"` // The cancelled request's event
+	RequestType string `json:"request_type"`
+	CancelledBy string `json:"cancelled_by"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Validate requires the ticket, its event and who cancelled it.
+func (p RequestCancelledPayload) Validate() error {
+	return requireFields("ticket_id", p.TicketID, "event_id", p.EventID, "request_type", p.RequestType, "cancelled_by", p.CancelledBy)
+}
+
+// Errors
+var (
+	ErrTicketNotPending    = domainerr.Conflict("TICKET_NOT_PENDING", "ticket is not pending approval")
+	ErrNotRequester        = domainerr.PermissionDenied("NOT_REQUESTER", "only the requester can cancel or resubmit a request")
+	ErrCancelReasonTooLong = domainerr.Invalid("INVALID_REQUEST", "cancel reason must be at most 500 characters")
+)
@@ -0,0 +1,98 @@
+// Package domain provides domain models.
+//
+// This file defines approver assignment for approval tickets.
+//
+// Eligible approvers are resolved at submission from resource-level
+// owner/admin bindings on the ticket's Service and its parent System,
+// instead of the flat platform-admin pool. The requester is never eligible.
+// If no resource approver remains, the ticket falls back to platform admins
+// so it can never be stuck without an approver.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ApproverSource records why a user was assigned to a ticket.
+type ApproverSource string
+
+const (
+	ApproverSourceService       ApproverSource = "service_binding"
+	ApproverSourceSystem        ApproverSource = "system_binding"
+	ApproverSourcePlatformAdmin ApproverSource = "platform_admin_fallback"
+)
+
+// TicketApprover is one row of approval_ticket_approvers.
+// Written in the same transaction as the ApprovalTicket.
+type TicketApprover struct {
+	TicketID string         `json:"ticket_id"`
+	UserID   string         `json:"user_id"`
+	Source   ApproverSource `json:"source"`
+	Stage    int            `json:"stage"` // Index into the ticket's approval stages
+}
+
+// approverRoles are the resource roles allowed to approve.
+var approverRoles = map[string]bool{
+	string(ResourceRoleOwner): true,
+	string(ResourceRoleAdmin): true,
+}
+
+// ResolveApprovers computes the eligible approvers for a ticket.
+//
+// Service bindings take precedence over System bindings for the same user
+// (more specific source is recorded). Expired bindings are ignored.
+// Result is sorted by user ID for stable storage and display.
+func ResolveApprovers(ticketID, requester string, serviceBindings, systemBindings []*ResourceRoleBinding, platformAdmins []string, now time.Time) []*TicketApprover {
+	seen := make(map[string]bool)
+	var result []*TicketApprover
+
+	add := func(userID string, source ApproverSource) {
+		if userID == requester || seen[userID] {
+			return
+		}
+		seen[userID] = true
+		result = append(result, &TicketApprover{TicketID: ticketID, UserID: userID, Source: source})
+	}
+
+	for _, b := range serviceBindings {
+		if approverRoles[b.Role] && !bindingExpired(b, now) {
+			add(b.UserID, ApproverSourceService)
+		}
+	}
+	for _, b := range systemBindings {
+		if approverRoles[b.Role] && !bindingExpired(b, now) {
+			add(b.UserID, ApproverSourceSystem)
+		}
+	}
+
+	if len(result) == 0 {
+		for _, userID := range platformAdmins {
+			add(userID, ApproverSourcePlatformAdmin)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result
+}
+
+func bindingExpired(b *ResourceRoleBinding, now time.Time) bool {
+	return b.ExpiresAt != nil && !b.ExpiresAt.After(now)
+}
+
+// Errors
+
+var (
+	// ErrNoEligibleApprover is returned when neither resource bindings nor
+	// the platform-admin pool yield an approver other than the requester.
+	ErrNoEligibleApprover = errors.New("no eligible approver for ticket")
+
+	// ErrSelfApproval is returned when the requester tries to approve their own ticket.
+	ErrSelfApproval = errors.New("requester cannot approve their own ticket")
+
+	// ErrNotAssignedApprover is returned when the approver is not assigned to the ticket.
+	ErrNotAssignedApprover = errors.New("user is not an assigned approver for this ticket")
+)
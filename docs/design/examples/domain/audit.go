@@ -0,0 +1,134 @@
+// Package domain provides domain models.
+//
+// This file defines the audit log record (Phase 4 §7, ADR-0015 §6, ADR-0019 §3).
+// Audit logs are append-only and written in the SAME transaction as the
+// state change they describe, so an operation can never commit unaudited.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// Audit action codes (Phase 4 §7 ActionCodes, dotted resource.verb form).
+const (
+	AuditClusterDecommissionStarted   = "cluster.decommission_started"
+	AuditClusterDecommissionPlanned   = "cluster.decommission_planned"
+	AuditClusterDecommissionExecuted  = "cluster.decommission_executed"
+	AuditClusterDecommissionCancelled = "cluster.decommission_cancelled"
+	AuditClusterArchived              = "cluster.archived"
+
+	AuditTemplateGoldenAccepted = "template.golden_accepted"
+	AuditTemplatePublished      = "template.published"
+
+	AuditImageBuildRecorded      = "image.build_recorded"
+	AuditImagePromotionRequested = "image.promotion_requested"
+	AuditImagePromoted           = "image.promoted"
+	AuditImagePromotionRejected  = "image.promotion_rejected"
+	AuditImageAdvisoryCreated    = "image.advisory_created"
+	AuditImageAdvisoryWithdrawn  = "image.advisory_withdrawn"
+	AuditImageScanRecorded       = "image.scan_recorded"
+
+	AuditRemediationCampaignStarted = "remediation.campaign_started"
+
+	AuditApprovalSoDViolation         = "approval.sod_violation"
+	AuditApprovalRejected             = "approval.rejected"
+	AuditApprovalEscalated            = "approval.escalated"
+	AuditApprovalBulkApproved         = "approval.bulk_approved"
+	AuditApprovalExecutionRescheduled = "approval.execution_rescheduled"
+	AuditRequestCancelled             = "request.cancelled"
+	AuditRequestExpired               = "request.expired"
+
+	AuditDelegationCreated = "approval.delegation_created"
+	AuditDelegationRevoked = "approval.delegation_revoked"
+
+	AuditFreezeOverrideRequested = "approval.freeze_override_requested"
+	AuditFreezeOverrideApproved  = "approval.freeze_override_approved"
+
+	AuditSystemMetadataUpdated = "system.metadata_updated"
+
+	AuditEventRequeued = "event.requeued"
+
+	AuditInstanceSizeUpdated = "instance_size.updated" // Includes from_version and version
+
+	AuditPolicyExpressionCreated = "policy_expression.created"
+	AuditPolicyExpressionUpdated = "policy_expression.updated" // Includes from_version and version
+
+	AuditNamespaceBaselineUpdated = "namespace.baseline_updated"
+	AuditNamespaceBaselineDrift   = "namespace.baseline_drift" // Restored by the sync; actor "system"
+
+	AuditRetentionPolicyUpdated = "retention.policy_updated"
+	AuditRetentionPurged        = "retention.purged"
+	AuditLegalHoldPlaced        = "retention.legal_hold_placed"
+	AuditLegalHoldReleased      = "retention.legal_hold_released"
+
+	AuditArchiveAnchored = "audit.archive_anchored" // Includes the anchored chain digest; actor "system"
+
+	AuditUserDataExported = "user.data_exported"
+	AuditUserAnonymized   = "user.anonymized"
+
+	AuditVMDeletionRequested = "vm.deletion_requested"
+	AuditVMModifyRequested   = "vm.modify_requested"
+	AuditVMResyncStarted     = "vm.resync_started"
+	AuditVMResyncCancelled   = "vm.resync_cancelled"
+	AuditVMEmergencyStop     = "vm.emergency_stop" // On the Service/System
+	AuditVMStop              = "vm.stop"
+	AuditVMStart             = "vm.start"
+	AuditVMRestart           = "vm.restart"
+	AuditVMPowerRequested    = "vm.power_requested"
+	AuditVMSnapshotRequested = "vm.snapshot_requested"
+	AuditVMSnapshot          = "vm.snapshot"
+	AuditVMBackupRequested   = "vm.backup_requested"
+	AuditVMBackup            = "vm.backup"
+	AuditVMRestoreRequested  = "vm.restore_requested"
+	AuditVMRestore           = "vm.restore"
+	AuditVMCloneRequested    = "vm.clone_requested"
+	AuditVMClone             = "vm.clone"
+
+	AuditVMMigrationRequested       = "vm.migration_requested"
+	AuditVMMigrationCancelRequested = "vm.migration_cancel_requested"
+	AuditVMMigration                = "vm.migration"
+
+	AuditVMRelocationRequested      = "vm.relocation_requested"
+	AuditVMRelocationConfirmed      = "vm.relocation_confirmed" // Cutover confirmed
+	AuditVMRelocationAbortRequested = "vm.relocation_abort_requested"
+	AuditVMRelocation               = "vm.relocation"
+
+	AuditVMReplacementStarted   = "vm.replacement_started"
+	AuditVMReplacementConfirmed = "vm.replacement_confirmed"
+	AuditVMReplacementAborted   = "vm.replacement_aborted"
+	AuditVMReplacementCompleted = "vm.replacement_completed"
+	AuditVMReplacementFailed    = "vm.replacement_failed"
+
+	AuditBatchDeleteRequested = "batch.delete_requested"
+
+	AuditVMLeaseWarned           = "vm.lease_warned"
+	AuditVMLeaseExpired          = "vm.lease_expired"
+	AuditVMLeaseRenewalRequested = "vm.lease_renewal_requested"
+	AuditVMLeaseRenewed          = "vm.lease_renewed"
+
+	AuditVNCAccessRequested = "vnc.access_requested"
+	AuditVNCAccessDenied    = "vnc.access_denied"
+	AuditVNCTokenIssued     = "vnc.token_issued" // Includes the approver
+	AuditVNCTokenUsed       = "vnc.token_used"   // Includes the connection time
+	AuditVNCTokenRevoked    = "vnc.token_revoked"
+
+	AuditAPIQuotaSet     = "api.quota_set"
+	AuditAPIQuotaRemoved = "api.quota_removed"
+)
+
+// AuditLog is a single append-only audit record.
+//
+// Details MUST be redacted before storage (ADR-0019).
+type AuditLog struct {
+	ID           string                 `json:"id"`
+	Action       string                 `json:"action"`
+	ActorID      string                 `json:"actor_id"`
+	ResourceType string                 `json:"resource_type"` // system, service, vm, approval, cluster, ...
+	ResourceID   string                 `json:"resource_id"`
+	ResourceName string                 `json:"resource_name,omitempty"`
+	ParentType   string                 `json:"parent_type,omitempty"`
+	ParentID     string                 `json:"parent_id,omitempty"`
+	Environment  string                 `json:"environment,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
@@ -0,0 +1,238 @@
+// Package domain provides domain models.
+//
+// This file defines the audit archive: an optional pipeline copying
+// audit_logs to append-only object storage, for auditors who do not accept
+// a mutable SQL table as evidence (Phase 4 §7).
+//
+//	batch   every few minutes, the audit logs not yet archived are written
+//	        as one NDJSON object under S3 Object Lock (compliance mode), so
+//	        nobody, the platform included, can change or delete it before
+//	        its retention ends
+//	chain   each batch's SHA-256 is chained to the previous batch's
+//	        (ChainDigest), so a batch cannot be dropped or reordered unseen
+//	anchor  periodically the chain head is written as its own locked
+//	        object, optionally countersigned by an RFC 3161 timestamp
+//	        authority, and audited; auditors keep the anchor digests
+//
+// Batches follow audit_logs in commit order, by the writing transaction's
+// xid8 and the row ID, as the status change feed does (Phase 3): only rows
+// older than every running transaction are archived, so a transaction
+// committing late cannot slip behind the last batch.
+//
+// Objects and anchors verify on their own, without the database: each
+// anchor lists its batches with their digests. The database only records
+// the batches, the chain and the cursor.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Audit archive limits.
+const (
+	DefaultAuditArchiveBatchSize = 5000
+	MaxAuditArchiveBatchSize     = 50000
+
+	// MinAuditArchiveRetention keeps objects at least as long as the
+	// longest audit retention (SensitiveAuditRetention).
+	MinAuditArchiveRetention = SensitiveAuditRetention
+)
+
+// GenesisChainDigest is the chain value before the first batch.
+const GenesisChainDigest = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditArchiveRecord is one audit log as archived. actor_name, ip_address
+// and user_agent are left out: they identify the person and anonymization
+// must be able to erase them (Phase 4 §7), which a locked object cannot.
+// The actor stays identified by actor_id, which anonymization keeps.
+type AuditArchiveRecord struct {
+	XID          uint64                 `json:"xid"` // Writing transaction, for the commit-order cursor
+	ID           string                 `json:"id"`
+	Action       string                 `json:"action"`
+	ActorID      string                 `json:"actor_id"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	ResourceName string                 `json:"resource_name,omitempty"`
+	ParentType   string                 `json:"parent_type,omitempty"`
+	ParentID     string                 `json:"parent_id,omitempty"`
+	Environment  string                 `json:"environment,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"` // Redacted before storage (ADR-0019)
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// EncodeAuditBatch returns the object body for records, one JSON record
+// per line in the given order (xid, id), and its SHA-256 in hex.
+// JSON object keys are sorted, so the same records always encode to the
+// same bytes.
+func EncodeAuditBatch(records []*AuditArchiveRecord) ([]byte, string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf) // One record per line
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return nil, "", fmt.Errorf("encode audit log %s: %w", r.ID, err)
+		}
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:]), nil
+}
+
+// DecodeAuditBatch parses an object body written by EncodeAuditBatch.
+func DecodeAuditBatch(body []byte) ([]*AuditArchiveRecord, error) {
+	var records []*AuditArchiveRecord
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for dec.More() {
+		var r AuditArchiveRecord
+		if err := dec.Decode(&r); err != nil {
+			return nil, fmt.Errorf("decode line %d: %w", len(records)+1, err)
+		}
+		records = append(records, &r)
+	}
+	return records, nil
+}
+
+// ChainDigest returns the chain value after batch seq with body digest
+// digest: hex(SHA-256(prev + "\n" + seq + "\n" + digest)). prev is
+// GenesisChainDigest for batch 1.
+func ChainDigest(prev string, seq int64, digest string) string {
+	sum := sha256.Sum256([]byte(prev + "\n" + strconv.FormatInt(seq, 10) + "\n" + digest))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditBatch is one archived batch (audit_archive_batches).
+type AuditBatch struct {
+	Seq            int64     `json:"seq"` // 1, 2, ... without gaps
+	ObjectKey      string    `json:"object_key"`
+	Records        int       `json:"records"`
+	FirstCreatedAt time.Time `json:"first_created_at"`
+	LastCreatedAt  time.Time `json:"last_created_at"`
+	ToXID          uint64    `json:"to_xid"` // Cursor: the last record's (xid, id)
+	ToID           string    `json:"to_id"`
+	SHA256         string    `json:"sha256"` // Of the object body
+	Chain          string    `json:"chain"`  // ChainDigest after this batch
+	ArchivedAt     time.Time `json:"archived_at"`
+}
+
+// AuditBatchKey returns the object key of batch seq,
+// e.g. "audit/batches/000000000042.ndjson". A key depends on the seq
+// only, so a run retrying a batch finds the object an earlier attempt
+// wrote.
+func AuditBatchKey(prefix string, seq int64) string {
+	return fmt.Sprintf("%s/batches/%012d.ndjson", prefix, seq)
+}
+
+// AuditAnchor fixes the chain head at a batch (audit_archive_anchors), and
+// is itself written as a locked JSON object.
+type AuditAnchor struct {
+	Seq       int64           `json:"seq"`
+	FromBatch int64           `json:"from_batch"`
+	ToBatch   int64           `json:"to_batch"`
+	Chain     string          `json:"chain"` // Chain after ToBatch: the anchored digest
+	Batches   []AnchoredBatch `json:"batches"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	// RFC 3161 token over Chain from audit_archive.timestamp_url; empty
+	// when no timestamp authority is configured.
+	Timestamp []byte `json:"timestamp,omitempty"`
+}
+
+// AnchoredBatch is a batch as listed in an anchor.
+type AnchoredBatch struct {
+	Seq       int64  `json:"seq"`
+	ObjectKey string `json:"object_key"`
+	SHA256    string `json:"sha256"`
+}
+
+// NewAuditAnchor anchors batches, which follow the batch with chain value
+// prev, in order. Fails with ErrAuditChainBroken if the batches do not
+// chain from prev.
+func NewAuditAnchor(seq int64, prev string, batches []*AuditBatch, at time.Time) (*AuditAnchor, error) {
+	if err := VerifyAuditChain(prev, batches); err != nil {
+		return nil, err
+	}
+	a := &AuditAnchor{
+		Seq:       seq,
+		FromBatch: batches[0].Seq,
+		ToBatch:   batches[len(batches)-1].Seq,
+		Chain:     batches[len(batches)-1].Chain,
+		Batches:   make([]AnchoredBatch, len(batches)),
+		CreatedAt: at,
+	}
+	for i, b := range batches {
+		a.Batches[i] = AnchoredBatch{Seq: b.Seq, ObjectKey: b.ObjectKey, SHA256: b.SHA256}
+	}
+	return a, nil
+}
+
+// AuditAnchorKey returns the object key of anchor seq,
+// e.g. "audit/anchors/000000000007.json".
+func AuditAnchorKey(prefix string, seq int64) string {
+	return fmt.Sprintf("%s/anchors/%012d.json", prefix, seq)
+}
+
+// VerifyAuditChain checks that batches are consecutive and chain from
+// prev. The batch digests themselves are checked against the objects by
+// the caller.
+func VerifyAuditChain(prev string, batches []*AuditBatch) error {
+	if len(batches) == 0 {
+		return fmt.Errorf("no batches: %w", ErrAuditChainBroken)
+	}
+	for i, b := range batches {
+		if i > 0 && b.Seq != batches[i-1].Seq+1 {
+			return fmt.Errorf("batch %d follows %d: %w", b.Seq, batches[i-1].Seq, ErrAuditChainBroken)
+		}
+		if want := ChainDigest(prev, b.Seq, b.SHA256); b.Chain != want {
+			return fmt.Errorf("batch %d chain %s, want %s: %w", b.Seq, b.Chain, want, ErrAuditChainBroken)
+		}
+		prev = b.Chain
+	}
+	return nil
+}
+
+// AuditArchiveVerification is the result of re-reading archived batches.
+type AuditArchiveVerification struct {
+	FromBatch int64     `json:"from_batch"`
+	ToBatch   int64     `json:"to_batch"`
+	Batches   int       `json:"batches"`
+	Anchors   int       `json:"anchors"` // Anchors within the range whose chain matched
+	Intact    bool      `json:"intact"`
+	Problem   string    `json:"problem,omitempty"` // First mismatch found
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// AuditArchiveStore is the append-only object storage of the archive, an
+// S3-compatible bucket with Object Lock enabled.
+type AuditArchiveStore interface {
+	// CheckImmutable fails unless the bucket has Object Lock enabled, so
+	// the pipeline never writes evidence that could be overwritten.
+	CheckImmutable(ctx context.Context) error
+
+	// Put writes body under key, locked in compliance mode until
+	// retainUntil. It never overwrites: an existing key fails with
+	// ErrAuditObjectExists (If-None-Match: *).
+	Put(ctx context.Context, key string, body []byte, retainUntil time.Time) error
+
+	// Get returns the body under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// AuditTimestamper obtains an RFC 3161 timestamp token over a digest from
+// an external timestamp authority, proving the anchor existed by then.
+type AuditTimestamper interface {
+	Timestamp(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Errors
+var (
+	ErrAuditChainBroken  = errors.New("audit archive chain broken")
+	ErrAuditObjectExists = errors.New("audit archive object exists")
+)
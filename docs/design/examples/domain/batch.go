@@ -0,0 +1,231 @@
+// Package domain provides domain models.
+//
+// This file defines batch requests (ADR-0015 §19): one parent ticket
+// with a child ticket per VM, for batch create and batch delete.
+//
+// Creation is atomic (parent and every child in one transaction, a batch
+// is never partially submitted); execution is not (each child runs and
+// fails independently, the parent aggregates the outcome).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// BatchType is the operation of a batch.
+type BatchType string
+
+const (
+	BatchCreate BatchType = "BATCH_CREATE"
+	BatchDelete BatchType = "BATCH_DELETE"
+)
+
+// BatchStatus is the parent ticket status.
+type BatchStatus string
+
+const (
+	BatchPendingApproval BatchStatus = "PENDING_APPROVAL" // Some children not yet decided
+	BatchInProgress      BatchStatus = "IN_PROGRESS"
+	BatchCompleted       BatchStatus = "COMPLETED"       // Terminal: all succeeded
+	BatchPartialSuccess  BatchStatus = "PARTIAL_SUCCESS" // Terminal
+	BatchFailed          BatchStatus = "FAILED"          // Terminal: none succeeded
+)
+
+// Batch size limits (ADR-0015 §19).
+const (
+	MaxBatchCreate = 10
+	MaxBatchDelete = 10
+)
+
+// BatchApprovalTicket is the parent ticket. Children are ordinary
+// ApprovalTickets with ParentTicketID set.
+type BatchApprovalTicket struct {
+	TicketID     string      `json:"ticket_id"`
+	EventID      string      `json:"event_id"` // BATCH_*_REQUESTED parent event
+	ServiceID    string      `json:"service_id"`
+	BatchType    BatchType   `json:"batch_type"`
+	ChildCount   int         `json:"child_count"`
+	SuccessCount int         `json:"success_count"`
+	FailedCount  int         `json:"failed_count"`  // Includes rejected children
+	PendingCount int         `json:"pending_count"` // Not yet finished (any status before terminal)
+	Status       BatchStatus `json:"status"`
+	Reason       string      `json:"reason"`
+	CreatedBy    string      `json:"created_by"`
+	CreatedAt    time.Time   `json:"created_at"`
+
+	// Placement constraints of a batch create (JSONB, batch_placement.go)
+	Placement BatchPlacement `json:"placement"`
+}
+
+// CalculateStatus derives the parent status from the counters once every
+// child is decided. undecided is the number of children still
+// PENDING_APPROVAL.
+func (t *BatchApprovalTicket) CalculateStatus(undecided int) BatchStatus {
+	switch {
+	case undecided > 0:
+		return BatchPendingApproval
+	case t.PendingCount > 0:
+		return BatchInProgress
+	case t.FailedCount == 0:
+		return BatchCompleted
+	case t.SuccessCount == 0:
+		return BatchFailed
+	default:
+		return BatchPartialSuccess
+	}
+}
+
+// BatchCreatePayload is the payload of BATCH_CREATE_REQUESTED events.
+// Each child has its own VM_CREATION_REQUESTED event.
+type BatchCreatePayload struct {
+	BatchTicketID string `json:"batch_ticket_id"`
+	ServiceID     string `json:"service_id"`
+	TemplateID    string `json:"template_id"`
+	Namespace     string `json:"namespace"`
+	Count         int    `json:"count"`
+	Reason        string `json:"reason"`
+
+	// Placement is nil for batches without constraints (batch_placement.go).
+	Placement *BatchPlacement `json:"placement,omitempty"`
+}
+
+// Validate requires the batch ticket, target and a positive count.
+func (p BatchCreatePayload) Validate() error {
+	if err := requireFields("batch_ticket_id", p.BatchTicketID, "service_id", p.ServiceID, "template_id", p.TemplateID, "namespace", p.Namespace); err != nil {
+		return err
+	}
+	if p.Count <= 0 {
+		return fmt.Errorf("count must be positive: %w", ErrInvalidEventPayload)
+	}
+	if p.Placement != nil {
+		if err := p.Placement.Validate(p.Count); err != nil {
+			return fmt.Errorf("%v: %w", err, ErrInvalidEventPayload)
+		}
+	}
+	return nil
+}
+
+// BatchDeleteItem is one VM of a batch delete, as resolved at submission.
+type BatchDeleteItem struct {
+	VMID      string `json:"vm_id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+}
+
+// BatchDeletePayload is the payload of BATCH_DELETE_REQUESTED events.
+// VMs is the snapshot the selector resolved to at submission: approval
+// and execution act on exactly these VMs, even if labels change or new
+// VMs start matching later. Each child has its own VM_DELETION_REQUESTED
+// event.
+type BatchDeletePayload struct {
+	BatchTicketID string            `json:"batch_ticket_id"`
+	ServiceID     string            `json:"service_id"`
+	Selector      string            `json:"selector"`
+	VMs           []BatchDeleteItem `json:"vms"`
+	Reason        string            `json:"reason"`
+}
+
+// Validate requires the batch ticket, the Service and at least one VM.
+func (p BatchDeletePayload) Validate() error {
+	if err := requireFields("batch_ticket_id", p.BatchTicketID, "service_id", p.ServiceID); err != nil {
+		return err
+	}
+	if len(p.VMs) == 0 {
+		return fmt.Errorf("vms must not be empty: %w", ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+// CheckBatchDeleteConfirmation extends tiered confirmation to batches:
+// confirm=true if every VM is in a test namespace; if any VM is in a prod
+// namespace, the number of VMs must be typed back (confirmCount), so a
+// selector matching more than the user expected is caught.
+func CheckBatchDeleteConfirmation(vmCount int, anyProd, confirm bool, confirmCount int) error {
+	if anyProd {
+		if confirmCount != vmCount {
+			return fmt.Errorf("confirm_count must be %d: %w", vmCount, ErrDeleteNotConfirmed)
+		}
+		return nil
+	}
+	if !confirm {
+		return ErrDeleteNotConfirmed
+	}
+	return nil
+}
+
+// BatchLimits are the per-user and global submission limits
+// (ADR-0015 §19 two-layer rate limiting). Exempt users skip them.
+type BatchLimits struct {
+	MaxGlobalPendingBatches int           // 100
+	MaxUserPendingBatches   int           // 3
+	MaxUserPendingChildren  int           // 30
+	Cooldown                time.Duration // 2m between a user's submissions
+}
+
+// DefaultBatchLimits are the ADR-0015 §19 defaults.
+var DefaultBatchLimits = BatchLimits{
+	MaxGlobalPendingBatches: 100,
+	MaxUserPendingBatches:   3,
+	MaxUserPendingChildren:  30,
+	Cooldown:                2 * time.Minute,
+}
+
+// BatchUsage is the current load counted at submission.
+type BatchUsage struct {
+	GlobalPendingBatches int
+	UserPendingBatches   int
+	UserPendingChildren  int
+	UserLastSubmittedAt  *time.Time
+}
+
+// RateLimitExceededError is returned when a batch would exceed a limit
+// (HTTP 429, code RATE_LIMIT_EXCEEDED).
+type RateLimitExceededError struct {
+	LimitType    string `json:"limit_type"` // "global" or "user"
+	CurrentValue int    `json:"current_value"`
+	MaxValue     int    `json:"max_value"`
+	RetryAfter   int    `json:"retry_after,omitempty"` // Seconds, cooldown only
+	ContactAdmin bool   `json:"contact_admin"`         // User may request an exemption
+}
+
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf("%s batch limit exceeded: %d/%d", e.LimitType, e.CurrentValue, e.MaxValue)
+}
+
+// Is makes errors.Is(err, ErrRateLimitExceeded) match.
+func (e *RateLimitExceededError) Is(target error) bool { return target == ErrRateLimitExceeded }
+
+// Check rejects a batch of count children given usage at now.
+func (l BatchLimits) Check(u BatchUsage, count int, now time.Time) error {
+	if u.GlobalPendingBatches >= l.MaxGlobalPendingBatches {
+		return &RateLimitExceededError{LimitType: "global", CurrentValue: u.GlobalPendingBatches, MaxValue: l.MaxGlobalPendingBatches}
+	}
+	if u.UserPendingBatches >= l.MaxUserPendingBatches {
+		return &RateLimitExceededError{LimitType: "user", CurrentValue: u.UserPendingBatches, MaxValue: l.MaxUserPendingBatches, ContactAdmin: true}
+	}
+	if u.UserPendingChildren+count > l.MaxUserPendingChildren {
+		return &RateLimitExceededError{LimitType: "user", CurrentValue: u.UserPendingChildren, MaxValue: l.MaxUserPendingChildren, ContactAdmin: true}
+	}
+	if u.UserLastSubmittedAt != nil {
+		if wait := u.UserLastSubmittedAt.Add(l.Cooldown).Sub(now); wait > 0 {
+			return &RateLimitExceededError{LimitType: "user", MaxValue: 1, CurrentValue: 1, RetryAfter: int(wait.Seconds()) + 1}
+		}
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInvalidBatchSize  = errors.New("batch size out of range")
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+	ErrNotBatchChild     = errors.New("ticket does not belong to batch")
+	ErrInvalidSelector   = domainerr.Invalid("INVALID_SELECTOR", "invalid label selector")
+	ErrNoVMsMatched      = domainerr.Unprocessable("NO_VMS_MATCHED", "label selector matched no vms")
+)
@@ -0,0 +1,190 @@
+// Package domain provides domain models.
+//
+// This file defines placement constraints of a batch create (batch.go):
+//
+//	min_clusters        children land on at least N clusters of the environment
+//	node_anti_affinity  no two children of the batch share a node
+//
+// Cluster spread is decided at approval: every child of a spread batch is
+// assigned a cluster (ModifiedSpec.ClusterID) when it is approved, either
+// the approver's pick, checked to keep the spread reachable, or one chosen
+// by AssignSpread. The worker creates each child on its assigned cluster,
+// so weight-based selection never collapses the batch onto one cluster.
+//
+// Node anti-affinity is enforced by the scheduler: the worker labels each
+// child with LabelBatch and adds a required pod anti-affinity on the node
+// hostname for that label. It only applies within a cluster; children on
+// different clusters never share a node.
+//
+// The worker records where each child actually landed (cluster and node),
+// so the batch shows whether the constraints held (EvaluateBatchPlacement).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// BatchPlacement holds the placement constraints of a batch create.
+// The zero value has none.
+type BatchPlacement struct {
+	MinClusters      int  `json:"min_clusters,omitempty"`       // 0 or 1: no spread
+	NodeAntiAffinity bool `json:"node_anti_affinity,omitempty"` // Required, not preferred
+}
+
+// IsZero reports whether the batch has no constraints.
+func (p BatchPlacement) IsZero() bool {
+	return p.MinClusters <= 1 && !p.NodeAntiAffinity
+}
+
+// Spreads reports whether children must be assigned clusters at approval.
+func (p BatchPlacement) Spreads() bool {
+	return p.MinClusters > 1
+}
+
+// Validate checks the constraints for a batch of count children.
+func (p BatchPlacement) Validate(count int) error {
+	if p.MinClusters < 0 || p.MinClusters > count {
+		return fmt.Errorf("min_clusters must be 0-%d: %w", count, ErrInvalidBatchPlacement)
+	}
+	return nil
+}
+
+// BatchChildPlacement is one child of a batch: the cluster assigned at
+// approval and where the worker actually created it.
+type BatchChildPlacement struct {
+	TicketID string       `json:"ticket_id"`
+	Status   TicketStatus `json:"status"`
+
+	// ClusterID is the assigned cluster (ModifiedSpec.ClusterID); empty
+	// while undecided, or for batches without spread.
+	ClusterID string `json:"cluster_id,omitempty"`
+
+	// Recorded by the worker: the cluster the VM was created on, and the
+	// node its VMI was first scheduled to (empty for a VM created stopped).
+	Cluster string `json:"cluster,omitempty"`
+	Node    string `json:"node,omitempty"`
+}
+
+// Counts reports whether the child still takes part in the batch, i.e.
+// was not rejected or cancelled.
+func (c *BatchChildPlacement) Counts() bool {
+	return c.Status != TicketRejected && c.Status != TicketCancelled
+}
+
+// AssignSpread assigns a cluster to every child of children without one,
+// so that the children taking part land on at least p.MinClusters
+// clusters (fewer if fewer children take part). Clusters not used yet
+// come first; otherwise the one with the fewest children relative to its
+// scheduling weight, as in PlanRelocations.
+//
+// candidates are the clusters accepting placements in the batch's
+// environment (FilterPlacementCandidates). Returns the cluster ID
+// assigned per ticket, for the children that had none, or
+// ErrBatchPlacementUnsatisfiable when the spread cannot be reached, e.g.
+// too few clusters or siblings already assigned to the same one.
+func AssignSpread(p BatchPlacement, children []*BatchChildPlacement, candidates []*Cluster) (map[string]string, error) {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].SchedulingWeight != candidates[j].SchedulingWeight {
+			return candidates[i].SchedulingWeight > candidates[j].SchedulingWeight
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	assigned := make(map[string]int, len(candidates))
+	taking := 0
+	for _, c := range children {
+		if !c.Counts() {
+			continue
+		}
+		taking++
+		if c.ClusterID != "" {
+			assigned[c.ClusterID]++
+		}
+	}
+	want := min(p.MinClusters, taking)
+
+	result := make(map[string]string)
+	for _, c := range children {
+		if !c.Counts() || c.ClusterID != "" {
+			continue
+		}
+		var best *Cluster
+		for _, cl := range candidates {
+			if len(assigned) < want {
+				// Still short of the spread: only unused clusters qualify
+				if assigned[cl.ID] == 0 {
+					best = cl
+					break
+				}
+				continue
+			}
+			if best == nil || assigned[cl.ID]*best.SchedulingWeight < assigned[best.ID]*cl.SchedulingWeight {
+				best = cl
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("%d eligible clusters: %w", len(candidates), ErrBatchPlacementUnsatisfiable)
+		}
+		assigned[best.ID]++
+		result[c.TicketID] = best.ID
+	}
+
+	if len(assigned) < want {
+		return nil, fmt.Errorf("children assigned to %d clusters, %d required: %w", len(assigned), want, ErrBatchPlacementUnsatisfiable)
+	}
+	return result, nil
+}
+
+// BatchPlacementStatus tells whether the children that were created
+// honored the batch's constraints.
+type BatchPlacementStatus struct {
+	Clusters    int      `json:"clusters"`               // Distinct clusters children were created on
+	SharedNodes []string `json:"shared_nodes,omitempty"` // "cluster/node" hosting more than one child
+	Satisfied   bool     `json:"satisfied"`
+}
+
+// EvaluateBatchPlacement checks the recorded placements against p.
+// Children not created yet are not counted, so the spread is only
+// reported satisfied once enough children exist.
+func EvaluateBatchPlacement(p BatchPlacement, children []*BatchChildPlacement) BatchPlacementStatus {
+	clusters := make(map[string]bool)
+	nodes := make(map[string]int)
+	taking := 0
+	for _, c := range children {
+		if c.Counts() {
+			taking++
+		}
+		if c.Cluster == "" {
+			continue
+		}
+		clusters[c.Cluster] = true
+		if c.Node != "" {
+			nodes[c.Cluster+"/"+c.Node]++
+		}
+	}
+
+	status := BatchPlacementStatus{Clusters: len(clusters)}
+	for node, n := range nodes {
+		if n > 1 {
+			status.SharedNodes = append(status.SharedNodes, node)
+		}
+	}
+	sort.Strings(status.SharedNodes)
+
+	status.Satisfied = status.Clusters >= min(p.MinClusters, taking)
+	if p.NodeAntiAffinity && len(status.SharedNodes) > 0 {
+		status.Satisfied = false
+	}
+	return status
+}
+
+// Errors
+var (
+	ErrInvalidBatchPlacement       = domainerr.Invalid("INVALID_BATCH_PLACEMENT", "invalid batch placement constraints")
+	ErrBatchPlacementUnsatisfiable = domainerr.Conflict("BATCH_PLACEMENT_UNSATISFIABLE", "batch placement constraints cannot be satisfied")
+)
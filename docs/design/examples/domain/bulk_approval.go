@@ -0,0 +1,74 @@
+// Package domain provides domain models.
+//
+// This file defines bulk approval: a platform admin approves many pending
+// tickets in one call, picked by ID or by a filter, e.g. 50 identical
+// requests from an onboarding.
+//
+// Each ticket is approved on its own, exactly as through the single
+// approval endpoint (SoD, stages, quota reservation, River job in one
+// transaction per ticket): one failing ticket never blocks the others, and
+// the result reports every ticket's outcome.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxBulkApproval bounds the tickets approved per call. A filter matching
+// more approves the first MaxBulkApproval (inbox order); call again for
+// the rest.
+const MaxBulkApproval = 100
+
+// BulkApprovalFilter selects pending tickets. RequestType is required so
+// a filter only ever covers requests of one kind; the other fields narrow
+// it further.
+type BulkApprovalFilter struct {
+	RequestType string `json:"request_type"`           // CREATE_VM, MODIFY_VM, DELETE_VM, RENEW_LEASE, SNAPSHOT_VM or a power operation
+	ServiceID   string `json:"service_id,omitempty"`   // Optional
+	RequestedBy string `json:"requested_by,omitempty"` // Optional
+}
+
+// BulkApprovalSelection is what to approve: TicketIDs or Filter, not both.
+type BulkApprovalSelection struct {
+	TicketIDs []string            `json:"ticket_ids,omitempty"`
+	Filter    *BulkApprovalFilter `json:"filter,omitempty"`
+}
+
+// Validate checks that exactly one of TicketIDs and Filter is given and
+// that the ID list is within MaxBulkApproval.
+func (s BulkApprovalSelection) Validate() error {
+	switch {
+	case len(s.TicketIDs) > 0 && s.Filter != nil:
+		return fmt.Errorf("%w: ticket_ids and filter are exclusive", ErrInvalidBulkApproval)
+	case len(s.TicketIDs) > MaxBulkApproval:
+		return fmt.Errorf("%w: at most %d ticket_ids", ErrInvalidBulkApproval, MaxBulkApproval)
+	case len(s.TicketIDs) > 0:
+		return nil
+	case s.Filter == nil:
+		return fmt.Errorf("%w: ticket_ids or filter required", ErrInvalidBulkApproval)
+	case !BulkApprovable(s.Filter.RequestType):
+		return fmt.Errorf("%w: filter.request_type %q", ErrInvalidBulkApproval, s.Filter.RequestType)
+	}
+	return nil
+}
+
+// BulkApprovable reports whether tickets of requestType can be approved in
+// bulk. Batch tickets are approved through their batch (ApproveAll), which
+// keeps the parent's counters in step.
+func BulkApprovable(requestType string) bool {
+	switch requestType {
+	case "CREATE_VM", "MODIFY_VM", "DELETE_VM", "START_VM", "STOP_VM", "RESTART_VM", LeaseRenewalRequestType, SnapshotRequestType, BackupRequestType:
+		return true
+	}
+	return false
+}
+
+// Errors
+var (
+	ErrInvalidBulkApproval     = domainerr.Invalid("INVALID_REQUEST", "invalid bulk approval")
+	ErrBulkApprovalUnsupported = domainerr.Conflict("BULK_APPROVAL_UNSUPPORTED", "ticket cannot be approved in bulk")
+)
@@ -0,0 +1,217 @@
+// Package domain provides domain models.
+//
+// This file defines cluster capability detection results (ADR-0014) and the
+// capability matrix used to gate platform features per cluster.
+//
+// Detection runs at cluster registration and periodically (River periodic job).
+// Platform features whose requirements are not met are hidden in the UI and
+// rejected at request validation; dry run remains the final fallback.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClusterCapabilities is the detected KubeVirt/CDI state of a cluster.
+// Stored as JSONB in clusters.capabilities (ADR-0014 §4).
+type ClusterCapabilities struct {
+	KubeVirtVersion string   `json:"kubevirt_version"`      // e.g., "v1.7.0"
+	CDIVersion      string   `json:"cdi_version,omitempty"` // Empty if CDI not installed
+	FeatureGates    []string `json:"feature_gates"`         // Explicitly enabled in KubeVirt CR
+	EnabledFeatures []string `json:"enabled_features"`      // FeatureGates + GA features for version
+
+	// Hardware is what the nodes offer, for InstanceSize matching
+	// (size_matching.go). Nil if it could not be read.
+	Hardware *ClusterHardware `json:"hardware,omitempty"`
+
+	// Warnings are human-readable compatibility issues (e.g., version skew).
+	// Non-empty warnings do NOT make the cluster unhealthy.
+	Warnings []string `json:"warnings,omitempty"`
+
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// PlatformFeature is a platform operation that depends on cluster capabilities.
+type PlatformFeature string
+
+const (
+	FeatureLiveMigration    PlatformFeature = "live_migration"
+	FeatureCPUHotplug       PlatformFeature = "cpu_hotplug"
+	FeatureMemoryHotplug    PlatformFeature = "memory_hotplug"
+	FeatureMemoryOvercommit PlatformFeature = "memory_overcommit"
+	FeatureSnapshot         PlatformFeature = "snapshot"
+	FeatureVMExport         PlatformFeature = "vm_export"
+)
+
+// featureRequirement describes what a platform feature needs from a cluster.
+type featureRequirement struct {
+	MinKubeVirt string   // Minimum KubeVirt minor, e.g., "v1.2"
+	Features    []string // All must be in EnabledFeatures (explicit gate or GA)
+	RequiresCDI bool     // CDI must be installed
+}
+
+// featureRequirements is the capability matrix definition.
+// Maintained together with gaFeaturesByVersion (ADR-0014 §Maintenance).
+var featureRequirements = map[PlatformFeature]featureRequirement{
+	FeatureLiveMigration:    {MinKubeVirt: "v1.0", Features: []string{"LiveMigration"}},
+	FeatureCPUHotplug:       {MinKubeVirt: "v1.2", Features: []string{"VMLiveUpdateFeatures"}},
+	FeatureMemoryHotplug:    {MinKubeVirt: "v1.2", Features: []string{"VMLiveUpdateFeatures"}},
+	FeatureMemoryOvercommit: {MinKubeVirt: "v1.0"}, // Request/limit model, no gate (ADR-0018)
+	FeatureSnapshot:         {MinKubeVirt: "v1.0", Features: []string{"Snapshot"}, RequiresCDI: true},
+	FeatureVMExport:         {MinKubeVirt: "v1.0", Features: []string{"VMExport"}, RequiresCDI: true},
+}
+
+// gaFeaturesByVersion lists features enabled by default per KubeVirt minor (ADR-0014 §2).
+// Only table requiring maintenance, updated once per minor release.
+var gaFeaturesByVersion = map[string][]string{
+	"v1.4": {"LiveMigration", "NetworkHotplug", "CommonInstancetypesDeployment"},
+	"v1.5": {"LiveMigration", "NetworkHotplug", "CommonInstancetypesDeployment", "NUMA", "VMLiveUpdateFeatures"},
+	"v1.6": {"LiveMigration", "NetworkHotplug", "CommonInstancetypesDeployment", "NUMA", "VMLiveUpdateFeatures", "GPUAssignment"},
+	"v1.7": {"LiveMigration", "NetworkHotplug", "CommonInstancetypesDeployment", "NUMA", "VMLiveUpdateFeatures", "GPUAssignment", "NodeRestriction"},
+}
+
+// Supported version range. Outside this range the cluster still works
+// but a skew warning is recorded and shown to admins.
+const (
+	MinSupportedKubeVirt = "v1.4"
+	MaxSupportedKubeVirt = "v1.7" // Matches kubevirt.io/client-go in DEPENDENCIES.md
+)
+
+// cdiCompatibility lists CDI minors tested with each KubeVirt minor.
+var cdiCompatibility = map[string][]string{
+	"v1.4": {"v1.60", "v1.61"},
+	"v1.5": {"v1.61", "v1.62"},
+	"v1.6": {"v1.62", "v1.63"},
+	"v1.7": {"v1.63", "v1.64"},
+}
+
+// NewClusterCapabilities builds capabilities from detected raw values:
+// merges GA features and computes version-skew warnings.
+func NewClusterCapabilities(kubevirtVersion, cdiVersion string, featureGates []string, detectedAt time.Time) *ClusterCapabilities {
+	c := &ClusterCapabilities{
+		KubeVirtVersion: kubevirtVersion,
+		CDIVersion:      cdiVersion,
+		FeatureGates:    featureGates,
+		EnabledFeatures: mergeUnique(featureGates, gaFeaturesByVersion[minorOf(kubevirtVersion)]),
+		DetectedAt:      detectedAt,
+	}
+	c.Warnings = c.skewWarnings()
+	return c
+}
+
+// skewWarnings reports unsupported KubeVirt versions and KubeVirt/CDI skew.
+func (c *ClusterCapabilities) skewWarnings() []string {
+	var warnings []string
+
+	kv := minorOf(c.KubeVirtVersion)
+	switch {
+	case kv == "":
+		warnings = append(warnings, fmt.Sprintf("unparseable KubeVirt version %q", c.KubeVirtVersion))
+	case compareMinor(kv, MinSupportedKubeVirt) < 0:
+		warnings = append(warnings, fmt.Sprintf("KubeVirt %s is older than minimum supported %s", c.KubeVirtVersion, MinSupportedKubeVirt))
+	case compareMinor(kv, MaxSupportedKubeVirt) > 0:
+		warnings = append(warnings, fmt.Sprintf("KubeVirt %s is newer than tested %s", c.KubeVirtVersion, MaxSupportedKubeVirt))
+	}
+
+	if c.CDIVersion == "" {
+		warnings = append(warnings, "CDI not installed: snapshot and export are unavailable")
+	} else if tested, ok := cdiCompatibility[kv]; ok && !containsString(tested, minorOf(c.CDIVersion)) {
+		warnings = append(warnings, fmt.Sprintf("CDI %s not tested with KubeVirt %s (tested: %s)",
+			c.CDIVersion, c.KubeVirtVersion, strings.Join(tested, ", ")))
+	}
+
+	return warnings
+}
+
+// FeatureSupport is one row of the capability matrix.
+type FeatureSupport struct {
+	Supported bool   `json:"supported"`
+	Reason    string `json:"reason,omitempty"` // Why unsupported
+}
+
+// Supports reports whether the cluster can serve a platform feature.
+func (c *ClusterCapabilities) Supports(f PlatformFeature) bool {
+	return c.Check(f).Supported
+}
+
+// Check evaluates one feature against its requirement.
+func (c *ClusterCapabilities) Check(f PlatformFeature) FeatureSupport {
+	if c == nil {
+		return FeatureSupport{Reason: "capabilities not detected yet"}
+	}
+	req, ok := featureRequirements[f]
+	if !ok {
+		return FeatureSupport{Reason: "unknown feature"}
+	}
+	if kv := minorOf(c.KubeVirtVersion); kv == "" || compareMinor(kv, req.MinKubeVirt) < 0 {
+		return FeatureSupport{Reason: fmt.Sprintf("requires KubeVirt >= %s", req.MinKubeVirt)}
+	}
+	if req.RequiresCDI && c.CDIVersion == "" {
+		return FeatureSupport{Reason: "requires CDI"}
+	}
+	for _, feature := range req.Features {
+		if !containsString(c.EnabledFeatures, feature) {
+			return FeatureSupport{Reason: fmt.Sprintf("feature gate %s not enabled", feature)}
+		}
+	}
+	return FeatureSupport{Supported: true}
+}
+
+// Matrix evaluates all platform features (exposed on the admin cluster API).
+func (c *ClusterCapabilities) Matrix() map[PlatformFeature]FeatureSupport {
+	m := make(map[PlatformFeature]FeatureSupport, len(featureRequirements))
+	for f := range featureRequirements {
+		m[f] = c.Check(f)
+	}
+	return m
+}
+
+// minorOf returns "vMAJOR.MINOR" from "v1.7.2", "1.7.2" or "v1.7.2-rc.1"; empty if unparseable.
+func minorOf(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	for _, p := range parts[:2] {
+		if _, err := strconv.Atoi(p); err != nil {
+			return ""
+		}
+	}
+	return "v" + parts[0] + "." + parts[1]
+}
+
+// compareMinor compares two "vMAJOR.MINOR" strings numerically.
+func compareMinor(a, b string) int {
+	pa := strings.SplitN(strings.TrimPrefix(a, "v"), ".", 2)
+	pb := strings.SplitN(strings.TrimPrefix(b, "v"), ".", 2)
+	for i := 0; i < 2; i++ {
+		x, _ := strconv.Atoi(pa[i])
+		y, _ := strconv.Atoi(pb[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func mergeUnique(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	result := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
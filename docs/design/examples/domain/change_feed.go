@@ -0,0 +1,108 @@
+// Package domain provides domain models.
+//
+// This file defines the status change feed: the status changes of VMs,
+// approval tickets and events the caller may read, in commit order, from
+// a cursor. Clients follow it to update lists and detail pages without
+// reloading them.
+//
+// Two transports serve the same feed: a Server-Sent Events stream and,
+// where a proxy buffers or cuts streams, long-polling. A client starts
+// with the stream and falls back to long-polling with its last cursor if
+// nothing (not even a heartbeat) arrives in time; both wake up on the
+// same LISTEN/NOTIFY channel.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Long-poll bounds. The maximum stays below server.write_timeout (30s) and
+// the idle timeout of common proxies (60s).
+const (
+	DefaultLongPollTimeout = 25 * time.Second
+	MaxLongPollTimeout     = 25 * time.Second
+	MinLongPollTimeout     = time.Second
+)
+
+// MaxChangesPerPoll bounds one response; the client polls again at once
+// when it is full.
+const MaxChangesPerPoll = 100
+
+// ChangeFeedRetention is how long changes are kept. A client whose cursor
+// is older reloads its lists and starts from the head.
+const ChangeFeedRetention = 24 * time.Hour
+
+// StatusChange is one status transition of a resource.
+type StatusChange struct {
+	Cursor       string    `json:"cursor"`        // Resume after this change
+	ResourceType string    `json:"resource_type"` // vm, ticket or event
+	ResourceID   string    `json:"resource_id"`
+	Status       string    `json:"status"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// ChangePage is one poll's result. Cursor is where the next poll resumes,
+// also when Changes is empty: it then skips changes the caller cannot see.
+type ChangePage struct {
+	Changes []*StatusChange `json:"changes"`
+	Cursor  string          `json:"cursor"`
+}
+
+// ChangeCursor is a position in the feed: the writing transaction's ID,
+// then the row ID. Row IDs alone are not in commit order (a transaction
+// may commit after a later one); the feed only serves transactions older
+// than every transaction still running, in (XID, ID) order.
+type ChangeCursor struct {
+	XID uint64
+	ID  int64
+}
+
+// String encodes the cursor; clients treat it as opaque.
+func (c ChangeCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.XID, c.ID))
+}
+
+// ParseChangeCursor decodes a cursor; empty means "from the head".
+func ParseChangeCursor(s string) (ChangeCursor, bool, error) {
+	if s == "" {
+		return ChangeCursor{}, false, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ChangeCursor{}, false, ErrInvalidChangeCursor
+	}
+	var c ChangeCursor
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &c.XID, &c.ID); err != nil {
+		return ChangeCursor{}, false, ErrInvalidChangeCursor
+	}
+	return c, true, nil
+}
+
+// Before reports whether c comes before o.
+func (c ChangeCursor) Before(o ChangeCursor) bool {
+	return c.XID < o.XID || (c.XID == o.XID && c.ID < o.ID)
+}
+
+// ParseLongPollTimeout parses ?timeout= (e.g. "25s"), clamped to the bounds.
+func ParseLongPollTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultLongPollTimeout, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("timeout: %w", err)
+	}
+	return min(max(d, MinLongPollTimeout), MaxLongPollTimeout), nil
+}
+
+// Errors
+var (
+	ErrInvalidChangeCursor = domainerr.Invalid("INVALID_CHANGE_CURSOR", "invalid change cursor")
+	ErrChangeCursorExpired = domainerr.Gone("CHANGE_CURSOR_EXPIRED", "change cursor expired")
+)
@@ -0,0 +1,112 @@
+// Package domain provides domain models.
+//
+// This file defines organization-wide change freeze calendars.
+//
+// During a freeze (e.g. year-end), approved request executions are held,
+// not rejected: the job waits and runs once the freeze lifts. Approval
+// itself continues, so the queue is ready when the freeze ends. A ticket
+// may run during a freeze only with an emergency override that two
+// different people took part in (two-person rule).
+//
+// Platform operations without a ticket (node drain, capability detection,
+// sweeps) are not held.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// FreezePeriod is one calendar entry (change_freeze_periods).
+type FreezePeriod struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"` // e.g. "Year-end 2026"
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"` // Exclusive
+
+	// Environments limits the freeze (test/prod). Nil freezes every environment.
+	Environments []string `json:"environments,omitempty"`
+
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks a period before it is saved.
+func (p *FreezePeriod) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required: %w", ErrInvalidFreezePeriod)
+	}
+	if !p.End.After(p.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidFreezePeriod)
+	}
+	if p.Environments != nil && len(p.Environments) == 0 {
+		return fmt.Errorf("environments must be omitted or non-empty: %w", ErrInvalidFreezePeriod)
+	}
+	return nil
+}
+
+// Covers reports whether the period freezes environment at t.
+// An empty environment (not resolvable) is covered by every period.
+func (p *FreezePeriod) Covers(environment string, t time.Time) bool {
+	if t.Before(p.Start) || !t.Before(p.End) {
+		return false
+	}
+	return p.Environments == nil || environment == "" || containsString(p.Environments, environment)
+}
+
+// FreezeLiftsAt returns when the freeze covering environment at now ends,
+// following overlapping and back-to-back periods. ok is false when no
+// period covers now.
+func FreezeLiftsAt(periods []*FreezePeriod, environment string, now time.Time) (lift time.Time, ok bool) {
+	lift = now
+	for extended := true; extended; {
+		extended = false
+		for _, p := range periods {
+			if p.Covers(environment, lift) {
+				lift, ok, extended = p.End, true, true
+			}
+		}
+	}
+	return lift, ok
+}
+
+// FreezeOverride lets one ticket run during a freeze (change_freeze_overrides).
+type FreezeOverride struct {
+	TicketID    string     `json:"ticket_id"`
+	Reason      string     `json:"reason"`
+	RequestedBy string     `json:"requested_by"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ApprovedBy  string     `json:"approved_by,omitempty"` // Empty until the second person approves
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+}
+
+// Active reports whether the override releases the ticket.
+func (o *FreezeOverride) Active() bool {
+	return o != nil && o.ApprovedBy != ""
+}
+
+// CheckOverrideApproval enforces the two-person rule: the approver must
+// differ from both the override requester and the ticket requester.
+func CheckOverrideApproval(o *FreezeOverride, ticketRequester, approver string) error {
+	if o.Active() {
+		return ErrFreezeOverrideApproved
+	}
+	if approver == o.RequestedBy || approver == ticketRequester {
+		return ErrTwoPersonRule
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInvalidFreezePeriod    = domainerr.Invalid("INVALID_FREEZE_PERIOD", "invalid freeze period")
+	ErrTwoPersonRule          = domainerr.PermissionDenied("TWO_PERSON_RULE", "emergency override needs a second, different person")
+	ErrFreezeOverrideApproved = domainerr.Conflict("FREEZE_OVERRIDE_APPROVED", "emergency override already approved")
+
+	ErrFreezeOverrideNotRequested = domainerr.NotFound("FREEZE_OVERRIDE_NOT_REQUESTED", "no emergency override requested for ticket")
+)
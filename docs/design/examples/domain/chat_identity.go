@@ -0,0 +1,46 @@
+// Package domain provides domain models.
+//
+// This file maps chat platform accounts to platform users, so a decision
+// made by clicking a button in Slack is attributed to, and authorized as,
+// a Shepherd user.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ChatProvider is a chat platform.
+type ChatProvider string
+
+const (
+	ChatProviderSlack ChatProvider = "slack"
+)
+
+// ChatIdentitySource records how a mapping was established.
+type ChatIdentitySource string
+
+const (
+	// ChatIdentityEmail: chat profile email matched an IdP-verified user email.
+	ChatIdentityEmail ChatIdentitySource = "email_match"
+	// ChatIdentityAdmin: created by a platform admin.
+	ChatIdentityAdmin ChatIdentitySource = "admin"
+)
+
+// ChatIdentity links one chat account to one platform user (chat_identities).
+// Unique on (provider, workspace_id, external_user_id): the same Slack user
+// ID in another workspace is a different person.
+type ChatIdentity struct {
+	Provider       ChatProvider       `json:"provider"`
+	WorkspaceID    string             `json:"workspace_id"` // Slack team ID
+	ExternalUserID string             `json:"external_user_id"`
+	UserID         string             `json:"user_id"`
+	Source         ChatIdentitySource `json:"source"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// ErrChatIdentityUnmapped is returned when a chat account has no platform
+// user. The action is refused; it is never attributed to a default user.
+var ErrChatIdentityUnmapped = errors.New("chat account is not linked to a platform user")
@@ -0,0 +1,27 @@
+// Package domain provides domain models.
+//
+// This file defines the clock and ID generator use cases take instead of
+// calling time.Now and uuid.New directly.
+//
+// Every timestamp a use case persists (SLA due times, delegation windows,
+// reservation and snapshot times) and every ID it generates goes through
+// these, so tests can pin both and assert exact values, and a replayed
+// request produces the same rows. Production wiring uses
+// usecase.SystemClock and usecase.UUIDGenerator; tests use the fakes in
+// internal/testutil/factory.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator returns new unique IDs for events, tickets, audit entries
+// and the like.
+type IDGenerator interface {
+	NewID() string
+}
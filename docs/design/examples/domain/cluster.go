@@ -0,0 +1,72 @@
+// Package domain provides domain models.
+//
+// This file defines the registered cluster model.
+// Health status is maintained by the health checker (Phase 2 §4);
+// lifecycle state is maintained by admins (registration, decommission).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// ClusterHealthStatus is the connectivity status (Phase 2 §4 Status Enum).
+type ClusterHealthStatus string
+
+const (
+	ClusterHealthUnknown     ClusterHealthStatus = "UNKNOWN"
+	ClusterHealthHealthy     ClusterHealthStatus = "HEALTHY"
+	ClusterHealthUnhealthy   ClusterHealthStatus = "UNHEALTHY"
+	ClusterHealthUnreachable ClusterHealthStatus = "UNREACHABLE"
+)
+
+// ClusterLifecycle is the administrative state of a cluster.
+type ClusterLifecycle string
+
+const (
+	// ClusterLifecycleActive accepts new placements.
+	ClusterLifecycleActive ClusterLifecycle = "ACTIVE"
+
+	// ClusterLifecycleDecommissioning is frozen: no new placements,
+	// existing VMs are being relocated. Operations on existing VMs still work.
+	ClusterLifecycleDecommissioning ClusterLifecycle = "DECOMMISSIONING"
+
+	// ClusterLifecycleDecommissioned is archived (terminal). Record kept for audit.
+	ClusterLifecycleDecommissioned ClusterLifecycle = "DECOMMISSIONED"
+)
+
+// Cluster represents a registered KubeVirt cluster.
+type Cluster struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"name"`
+	Environment      string              `json:"environment"`       // test, prod (ADR-0015 §15)
+	SchedulingWeight int                 `json:"scheduling_weight"` // Higher = more likely selected
+	Status           ClusterHealthStatus `json:"status"`
+	Lifecycle        ClusterLifecycle    `json:"lifecycle"`
+
+	// ProviderType selects the provider implementation ("kubevirt" default,
+	// e.g., "libvirt"). Immutable after registration.
+	ProviderType string `json:"provider_type"`
+
+	// Capabilities (ADR-0014). Nil until first detection completes.
+	Capabilities *ClusterCapabilities `json:"capabilities,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"` // Set when DECOMMISSIONED
+}
+
+// AcceptsPlacements reports whether new VMs may be scheduled to this cluster.
+func (c *Cluster) AcceptsPlacements() bool {
+	return c.Lifecycle == ClusterLifecycleActive && c.Status == ClusterHealthHealthy
+}
+
+// FilterPlacementCandidates returns clusters eligible for new VMs in an environment.
+// Used by the approval cluster dropdown and by weight-based selection (ADR-0015 §15).
+func FilterPlacementCandidates(clusters []*Cluster, environment string) []*Cluster {
+	result := make([]*Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		if c.Environment == environment && c.AcceptsPlacements() {
+			result = append(result, c)
+		}
+	}
+	return result
+}
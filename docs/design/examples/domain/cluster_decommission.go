@@ -0,0 +1,197 @@
+// Package domain provides domain models.
+//
+// This file defines the guided cluster decommission flow:
+//
+//	Start ──► PLANNING ──► PLANNED ──► EXECUTING ──► READY_TO_ARCHIVE ──► COMPLETED
+//	  │ (placements frozen)   │              │ (relocation batch)               (cluster archived)
+//	  └───────────────────────┴──► CANCELLED (placements unfrozen)
+//
+// Relocation execution follows the batch model (ADR-0015 §19):
+// one child VM_RELOCATION_REQUESTED event per VM, independent execution.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DecommissionStatus is the status of a cluster decommission.
+type DecommissionStatus string
+
+const (
+	DecommissionPlanning       DecommissionStatus = "PLANNING"
+	DecommissionPlanned        DecommissionStatus = "PLANNED"
+	DecommissionExecuting      DecommissionStatus = "EXECUTING"
+	DecommissionReadyToArchive DecommissionStatus = "READY_TO_ARCHIVE"
+	DecommissionCompleted      DecommissionStatus = "COMPLETED"
+	DecommissionCancelled      DecommissionStatus = "CANCELLED"
+)
+
+// ClusterDecommission is the parent record of a decommission.
+type ClusterDecommission struct {
+	ID          string             `json:"id"`
+	EventID     string             `json:"event_id"` // CLUSTER_DECOMMISSION_REQUESTED event
+	ClusterID   string             `json:"cluster_id"`
+	Status      DecommissionStatus `json:"status"`
+	Reason      string             `json:"reason"`
+	RequestedBy string             `json:"requested_by"`
+
+	Plan []*RelocationPlanItem `json:"plan,omitempty"` // JSONB, replaced on re-plan
+
+	// Execution counters (same semantics as BatchApprovalTicket)
+	TotalCount   int `json:"total_count"`
+	SuccessCount int `json:"success_count"`
+	FailedCount  int `json:"failed_count"`
+	PendingCount int `json:"pending_count"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CanCancel reports whether the decommission can still be abandoned.
+// Once relocation has started, VMs already moved cannot be "un-moved".
+func (d *ClusterDecommission) CanCancel() bool {
+	return d.Status == DecommissionPlanning || d.Status == DecommissionPlanned
+}
+
+// RelocationPlanItem is one VM's planned move to another cluster.
+type RelocationPlanItem struct {
+	VMID          string `json:"vm_id"`
+	VMName        string `json:"vm_name"`
+	Namespace     string `json:"namespace"`
+	ServiceID     string `json:"service_id"`
+	SourceCluster string `json:"source_cluster"`
+	TargetCluster string `json:"target_cluster,omitempty"` // Empty when unplaceable
+	Unplaceable   string `json:"unplaceable,omitempty"`    // Why no target was found
+	EventID       string `json:"event_id,omitempty"`       // Set at execution
+}
+
+// PlanRelocations assigns every VM a target cluster in the same environment.
+//
+// Targets are chosen by spreading VMs proportionally to scheduling_weight
+// (deterministic, so re-planning yields a stable plan for review).
+// VMs whose namespace is not registered on any candidate are unplaceable;
+// the admin must register the namespace or override the target.
+func PlanRelocations(source *Cluster, vms []*VM, candidates []*Cluster, namespaceClusters map[string][]string) []*RelocationPlanItem {
+	// Stable order so the same inputs yield the same plan
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].SchedulingWeight != candidates[j].SchedulingWeight {
+			return candidates[i].SchedulingWeight > candidates[j].SchedulingWeight
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	assigned := make(map[string]int, len(candidates))
+	plan := make([]*RelocationPlanItem, 0, len(vms))
+
+	for _, vm := range vms {
+		item := &RelocationPlanItem{
+			VMID:          vm.ID,
+			VMName:        vm.Name,
+			Namespace:     vm.Namespace,
+			ServiceID:     vm.ServiceID,
+			SourceCluster: source.Name,
+		}
+
+		var best *Cluster
+		for _, c := range candidates {
+			if c.ID == source.ID || !containsString(namespaceClusters[vm.Namespace], c.ID) {
+				continue
+			}
+			// Lowest load relative to weight wins
+			if best == nil || assigned[c.ID]*best.SchedulingWeight < assigned[best.ID]*c.SchedulingWeight {
+				best = c
+			}
+		}
+
+		if best == nil {
+			item.Unplaceable = "namespace " + vm.Namespace + " not registered on any eligible cluster"
+		} else {
+			item.TargetCluster = best.Name
+			assigned[best.ID]++
+		}
+		plan = append(plan, item)
+	}
+
+	return plan
+}
+
+// UnplaceableCount returns how many plan items have no target.
+func UnplaceableCount(plan []*RelocationPlanItem) int {
+	n := 0
+	for _, item := range plan {
+		if item.TargetCluster == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// ClusterDecommissionPayload is the payload of CLUSTER_DECOMMISSION_REQUESTED.
+type ClusterDecommissionPayload struct {
+	DecommissionID string `json:"decommission_id"`
+	ClusterID      string `json:"cluster_id"`
+	ClusterName    string `json:"cluster_name"`
+	Reason         string `json:"reason"`
+}
+
+// Validate requires the decommission and its cluster.
+func (p ClusterDecommissionPayload) Validate() error {
+	return requireFields("decommission_id", p.DecommissionID, "cluster_id", p.ClusterID, "cluster_name", p.ClusterName)
+}
+
+// VMRelocationPayload is the payload of VM_RELOCATION_REQUESTED.
+// DecommissionID is empty for relocations requested outside a decommission
+// (vm_relocation.go). AggregateID is the VM ID, so a relocation in flight
+// blocks other operations on the VM.
+type VMRelocationPayload struct {
+	DecommissionID string `json:"decommission_id,omitempty"`
+	VMID           string `json:"vm_id"`
+	VMName         string `json:"vm_name"`
+	Namespace      string `json:"namespace"`
+	ServiceID      string `json:"service_id"`
+	SourceCluster  string `json:"source_cluster"`
+	TargetCluster  string `json:"target_cluster"`
+}
+
+// Validate requires the VM and two different clusters.
+func (p VMRelocationPayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "vm_name", p.VMName, "namespace", p.Namespace, "service_id", p.ServiceID,
+		"source_cluster", p.SourceCluster, "target_cluster", p.TargetCluster); err != nil {
+		return err
+	}
+	if p.SourceCluster == p.TargetCluster {
+		return fmt.Errorf("target_cluster must differ from source_cluster: %w", ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors
+
+var (
+	// ErrDecommissionInvalidState is returned when a step is called out of order.
+	ErrDecommissionInvalidState = errors.New("decommission is not in the required state for this step")
+
+	// ErrDecommissionUnplaceableVMs is returned when executing a plan with unplaced VMs.
+	ErrDecommissionUnplaceableVMs = errors.New("decommission plan has VMs without a target cluster")
+
+	// ErrDecommissionInvalidTarget is returned when an override targets a cluster
+	// that is frozen, unhealthy, or in another environment.
+	ErrDecommissionInvalidTarget = errors.New("override target cluster is not eligible for placements")
+
+	// ErrClusterNotEmpty is returned when archiving a cluster that still has managed VMs.
+	ErrClusterNotEmpty = errors.New("cluster still has platform-managed VMs")
+)
@@ -0,0 +1,237 @@
+// Package domain provides domain models.
+//
+// This file defines patch compliance: which VMs run a template version or
+// image that should no longer be in use.
+//
+// Every VM records what it was built from: template version (VM.Template),
+// image source and digest, and provision date. A VM is non-compliant when
+// its template version was superseded or archived, or when an open image
+// advisory matches its image. Advisories are recorded by platform admins:
+// "retired" for images taken out of service, "vulnerable" for images with
+// a known vulnerability. Non-compliant VMs are fixed by remediation
+// campaigns (remediation.go).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// AdvisoryKind says why an image should no longer be used.
+type AdvisoryKind string
+
+const (
+	AdvisoryRetired    AdvisoryKind = "retired"
+	AdvisoryVulnerable AdvisoryKind = "vulnerable"
+)
+
+// AdvisorySeverity ranks advisories; retired images are medium.
+type AdvisorySeverity string
+
+const (
+	SeverityLow      AdvisorySeverity = "low"
+	SeverityMedium   AdvisorySeverity = "medium"
+	SeverityHigh     AdvisorySeverity = "high"
+	SeverityCritical AdvisorySeverity = "critical"
+)
+
+var severityRank = map[AdvisorySeverity]int{
+	SeverityLow: 1, SeverityMedium: 2, SeverityHigh: 3, SeverityCritical: 4,
+}
+
+// Rank orders severities, low = 1 to critical = 4; 0 when unknown.
+func (s AdvisorySeverity) Rank() int {
+	return severityRank[s]
+}
+
+// ImageAdvisory flags an image (image_advisories). Image is a digest
+// (sha256:...) matching VMs pinned to it, or an image source matching
+// unpinned VMs built from it.
+type ImageAdvisory struct {
+	ID          string           `json:"id"`
+	Image       string           `json:"image"`
+	Kind        AdvisoryKind     `json:"kind"`
+	Severity    AdvisorySeverity `json:"severity"`
+	Reference   string           `json:"reference,omitempty"` // e.g. CVE-2026-1234
+	Summary     string           `json:"summary"`
+	CreatedBy   string           `json:"created_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+	WithdrawnAt *time.Time       `json:"withdrawn_at,omitempty"` // Withdrawn advisories match nothing
+}
+
+// Validate checks a new advisory.
+func (a *ImageAdvisory) Validate() error {
+	if a.Image == "" || strings.Contains(a.Image, " ") {
+		return fmt.Errorf("image must be a digest or image source: %w", ErrInvalidAdvisory)
+	}
+	if a.Kind != AdvisoryRetired && a.Kind != AdvisoryVulnerable {
+		return fmt.Errorf("kind must be retired or vulnerable: %w", ErrInvalidAdvisory)
+	}
+	if a.Severity.Rank() == 0 {
+		return fmt.Errorf("severity must be low, medium, high or critical: %w", ErrInvalidAdvisory)
+	}
+	if a.Summary == "" || len(a.Summary) > 500 {
+		return fmt.Errorf("summary must be 1-500 characters: %w", ErrInvalidAdvisory)
+	}
+	return nil
+}
+
+// Matches reports whether the open advisory applies to a VM built from
+// source, pinned to digest (empty when unpinned).
+func (a *ImageAdvisory) Matches(source, digest string) bool {
+	if a.WithdrawnAt != nil {
+		return false
+	}
+	if digest != "" && a.Image == digest {
+		return true
+	}
+	return a.Image == source
+}
+
+// ComplianceReason is why a VM is non-compliant.
+type ComplianceReason string
+
+const (
+	ReasonTemplateOutdated ComplianceReason = "TEMPLATE_OUTDATED" // A newer version is active (deprecated)
+	ReasonTemplateRetired  ComplianceReason = "TEMPLATE_RETIRED"  // Version archived
+	ReasonImageRetired     ComplianceReason = "IMAGE_RETIRED"
+	ReasonImageVulnerable  ComplianceReason = "IMAGE_VULNERABLE"
+)
+
+// ComplianceFinding is one reason with its detail.
+type ComplianceFinding struct {
+	Reason     ComplianceReason `json:"reason"`
+	Severity   AdvisorySeverity `json:"severity"`
+	AdvisoryID string           `json:"advisory_id,omitempty"`
+	Detail     string           `json:"detail"`
+}
+
+// VMBuild is what a VM was built from, as recorded at creation.
+type VMBuild struct {
+	VMID            string         `json:"vm_id"`
+	Name            string         `json:"name"`
+	Namespace       string         `json:"namespace"`
+	ServiceID       string         `json:"service_id"`
+	TemplateID      string         `json:"template_id"`
+	TemplateName    string         `json:"template_name"`
+	TemplateVersion int            `json:"template_version"`
+	TemplateStatus  TemplateStatus `json:"template_status"`
+	ImageSource     string         `json:"image_source"`
+	ImageDigest     string         `json:"image_digest,omitempty"`
+	ProvisionedAt   *time.Time     `json:"provisioned_at,omitempty"`
+}
+
+// VMCompliance is one row of the compliance report.
+type VMCompliance struct {
+	VMBuild
+	Findings    []ComplianceFinding `json:"findings"`
+	MaxSeverity AdvisorySeverity    `json:"max_severity"`
+}
+
+// CheckCompliance returns the findings for b against the open advisories;
+// empty when b is compliant. A superseded template version is low
+// severity, an archived one medium.
+func CheckCompliance(b VMBuild, advisories []*ImageAdvisory) []ComplianceFinding {
+	var findings []ComplianceFinding
+	switch b.TemplateStatus {
+	case TemplateDeprecated:
+		findings = append(findings, ComplianceFinding{
+			Reason:   ReasonTemplateOutdated,
+			Severity: SeverityLow,
+			Detail:   fmt.Sprintf("%s v%d was superseded", b.TemplateName, b.TemplateVersion),
+		})
+	case TemplateArchived:
+		findings = append(findings, ComplianceFinding{
+			Reason:   ReasonTemplateRetired,
+			Severity: SeverityMedium,
+			Detail:   fmt.Sprintf("%s v%d was archived", b.TemplateName, b.TemplateVersion),
+		})
+	}
+	for _, a := range advisories {
+		if !a.Matches(b.ImageSource, b.ImageDigest) {
+			continue
+		}
+		reason := ReasonImageVulnerable
+		if a.Kind == AdvisoryRetired {
+			reason = ReasonImageRetired
+		}
+		detail := a.Summary
+		if a.Reference != "" {
+			detail = a.Reference + ": " + a.Summary
+		}
+		findings = append(findings, ComplianceFinding{
+			Reason:     reason,
+			Severity:   a.Severity,
+			AdvisoryID: a.ID,
+			Detail:     detail,
+		})
+	}
+	return findings
+}
+
+// NewVMCompliance builds a report row; nil when b is compliant.
+func NewVMCompliance(b VMBuild, advisories []*ImageAdvisory) *VMCompliance {
+	findings := CheckCompliance(b, advisories)
+	if len(findings) == 0 {
+		return nil
+	}
+	c := &VMCompliance{VMBuild: b, Findings: findings}
+	for _, f := range findings {
+		if f.Severity.Rank() > c.MaxSeverity.Rank() {
+			c.MaxSeverity = f.Severity
+		}
+	}
+	return c
+}
+
+// ComplianceFilter narrows the report. All fields are optional.
+type ComplianceFilter struct {
+	ServiceID    string           `json:"service_id,omitempty"`
+	TemplateName string           `json:"template_name,omitempty"`
+	Reason       ComplianceReason `json:"reason,omitempty"`
+	MinSeverity  AdvisorySeverity `json:"min_severity,omitempty"`
+	AdvisoryID   string           `json:"advisory_id,omitempty"` // VMs one advisory matches
+}
+
+// Validate checks the reason and severity are known values.
+func (f ComplianceFilter) Validate() error {
+	switch f.Reason {
+	case "", ReasonTemplateOutdated, ReasonTemplateRetired, ReasonImageRetired, ReasonImageVulnerable:
+	default:
+		return fmt.Errorf("unknown reason %q: %w", f.Reason, ErrInvalidComplianceFilter)
+	}
+	if f.MinSeverity != "" && f.MinSeverity.Rank() == 0 {
+		return fmt.Errorf("unknown severity %q: %w", f.MinSeverity, ErrInvalidComplianceFilter)
+	}
+	return nil
+}
+
+// Includes reports whether c passes the filter's reason, severity and
+// advisory; service and template are filtered by the query.
+func (f ComplianceFilter) Includes(c *VMCompliance) bool {
+	if f.MinSeverity != "" && c.MaxSeverity.Rank() < f.MinSeverity.Rank() {
+		return false
+	}
+	if f.Reason == "" && f.AdvisoryID == "" {
+		return true
+	}
+	for _, finding := range c.Findings {
+		if (f.Reason == "" || finding.Reason == f.Reason) &&
+			(f.AdvisoryID == "" || finding.AdvisoryID == f.AdvisoryID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors
+var (
+	ErrInvalidAdvisory         = domainerr.Invalid("INVALID_REQUEST", "invalid image advisory")
+	ErrAdvisoryNotOpen         = domainerr.Conflict("ADVISORY_NOT_OPEN", "advisory is already withdrawn")
+	ErrInvalidComplianceFilter = domainerr.Invalid("INVALID_REQUEST", "invalid compliance filter")
+)
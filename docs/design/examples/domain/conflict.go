@@ -0,0 +1,72 @@
+// Package domain provides domain models.
+//
+// This file defines conflict handling when changes made directly on the
+// cluster race platform operations.
+//
+// Provider updates carry the resourceVersion Shepherd last read
+// (VMSpec.ExpectedResourceVersion). A stale version fails with
+// provider.ErrConflict instead of silently overwriting the cluster. The
+// caller then re-reads: if only fields Shepherd does not manage changed,
+// it retries on the fresh version; if managed fields drifted, it stops with
+// a *ConflictError so the user can refresh and decide.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxConflictRetries bounds refresh-and-retry on unrelated concurrent writes
+// (status updates, third-party annotations).
+const MaxConflictRetries = 3
+
+// ConflictError reports that managed fields changed on the cluster since
+// Shepherd last read the object. Handlers map it to 409 RESOURCE_CONFLICT.
+type ConflictError struct {
+	Cluster         string   `json:"cluster"`
+	Namespace       string   `json:"namespace"`
+	Name            string   `json:"name"`
+	ExpectedVersion string   `json:"expected_version"`
+	CurrentVersion  string   `json:"current_version"`
+	Fields          []string `json:"fields"` // Managed fields that drifted
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s/%s on %s changed on the cluster (%s → %s): %s",
+		e.Namespace, e.Name, e.Cluster, e.ExpectedVersion, e.CurrentVersion, strings.Join(e.Fields, ", "))
+}
+
+// Is matches ErrResourceConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrResourceConflict
+}
+
+// ManagedFieldDrift lists the Shepherd-managed fields that differ between
+// the version Shepherd based its change on and the current object.
+func ManagedFieldDrift(base, current *VM) []string {
+	var fields []string
+	if base.CPU != current.CPU {
+		fields = append(fields, "cpu")
+	}
+	if base.MemoryMB != current.MemoryMB {
+		fields = append(fields, "memory_mb")
+	}
+	if base.DiskGB != current.DiskGB {
+		fields = append(fields, "disk_gb")
+	}
+	if base.Template != current.Template {
+		fields = append(fields, "template")
+	}
+	for _, k := range []string{LabelManagedBy, LabelSystem, LabelService, LabelInstance} {
+		if base.Labels[k] != current.Labels[k] {
+			fields = append(fields, "labels."+k)
+		}
+	}
+	return fields
+}
+
+// ErrResourceConflict is matched by every *ConflictError.
+var ErrResourceConflict = errors.New("resource changed on the cluster")
@@ -0,0 +1,81 @@
+// Package domain provides domain models.
+//
+// This file defines approval delegations.
+//
+// An approver who will be absent delegates their approval authority to
+// another user for a time window. During the window the delegate may
+// approve or reject every ticket the delegator is assigned to; both
+// identities are recorded. A delegation moves authority, it does not add
+// a person: the delegator and the delegate together count as one approval
+// of a ticket, and all separation-of-duties rules still apply to the
+// delegate (a delegate cannot approve their own request).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxDelegationPeriod bounds a single delegation; longer absences need a
+// new delegation or a change of role bindings.
+const MaxDelegationPeriod = 90 * 24 * time.Hour
+
+// Delegation grants DelegateID the approval authority of DelegatorID
+// between StartsAt and EndsAt (approval_delegations).
+type Delegation struct {
+	ID          string     `json:"id"`
+	DelegatorID string     `json:"delegator_id"`
+	DelegateID  string     `json:"delegate_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"` // Exclusive
+	Reason      string     `json:"reason"`  // e.g. "Vacation, 2026-08-01 to 2026-08-15"
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Validate checks a delegation before it is saved.
+func (d *Delegation) Validate(now time.Time) error {
+	if d.DelegateID == "" || d.DelegateID == d.DelegatorID {
+		return fmt.Errorf("delegate must be another user: %w", ErrInvalidDelegation)
+	}
+	if !d.EndsAt.After(d.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at: %w", ErrInvalidDelegation)
+	}
+	if !d.EndsAt.After(now) {
+		return fmt.Errorf("ends_at is in the past: %w", ErrInvalidDelegation)
+	}
+	if d.EndsAt.Sub(d.StartsAt) > MaxDelegationPeriod {
+		return fmt.Errorf("period exceeds %s: %w", MaxDelegationPeriod, ErrInvalidDelegation)
+	}
+	return nil
+}
+
+// ActiveAt reports whether the delegate may act at t.
+func (d *Delegation) ActiveAt(t time.Time) bool {
+	return d.RevokedAt == nil && !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
+
+// ActingApprover is who approves or rejects a ticket and, for a delegate,
+// whose assignment they act on.
+type ActingApprover struct {
+	UserID     string `json:"user_id"`
+	OnBehalfOf string `json:"on_behalf_of,omitempty"` // Delegator; empty when acting on own assignment
+}
+
+// Principal returns the user whose assignment the action uses.
+func (a ActingApprover) Principal() string {
+	if a.OnBehalfOf != "" {
+		return a.OnBehalfOf
+	}
+	return a.UserID
+}
+
+// Errors
+var (
+	ErrInvalidDelegation = domainerr.Invalid("INVALID_DELEGATION", "invalid delegation")
+	ErrNotDelegator      = domainerr.PermissionDenied("NOT_DELEGATOR", "only the delegator can revoke a delegation")
+)
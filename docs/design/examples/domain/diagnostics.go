@@ -0,0 +1,93 @@
+// Package domain provides domain models.
+//
+// This file defines the diagnostics bundle collected when VM provisioning
+// fails, so admins can triage without kubectl access to tenant clusters.
+//
+// The bundle is a snapshot taken right after the failure: VMI conditions,
+// Kubernetes events for the VM and its launcher pod and volumes,
+// DataVolume status, and scheduler messages. It is stored with the failed
+// DomainEvent (domain_event_diagnostics) and never sent to requesters;
+// K8s event messages may name nodes and other tenants' objects.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// Bundle size limits: a crash-looping VM can produce thousands of events.
+const (
+	MaxDiagnosticEvents     = 50   // Most recent kept
+	MaxDiagnosticMessageLen = 1024 // Per message, bytes
+)
+
+// DiagnosticsBundle is the triage snapshot of one failed provisioning.
+type DiagnosticsBundle struct {
+	EventID   string `json:"event_id"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	VMStatus      VMStatus              `json:"vm_status,omitempty"`
+	VMIConditions []DiagnosticCondition `json:"vmi_conditions,omitempty"`
+	DataVolumes   []DataVolumeStatus    `json:"data_volumes,omitempty"`
+	Events        []DiagnosticEvent     `json:"events,omitempty"`
+
+	// SchedulerMessages are the launcher pod's PodScheduled=False messages,
+	// e.g. "0/12 nodes are available: 12 Insufficient memory."
+	SchedulerMessages []string `json:"scheduler_messages,omitempty"`
+
+	// Errors lists parts that could not be collected (partial bundle).
+	Errors []string `json:"errors,omitempty"`
+
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// DiagnosticCondition is one status condition.
+type DiagnosticCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// DataVolumeStatus is the CDI import/clone state of one volume.
+type DataVolumeStatus struct {
+	Name       string                `json:"name"`
+	Phase      string                `json:"phase"`              // e.g. ImportInProgress, Failed
+	Progress   string                `json:"progress,omitempty"` // e.g. "42.5%"
+	Conditions []DiagnosticCondition `json:"conditions,omitempty"`
+}
+
+// DiagnosticEvent is one Kubernetes event.
+type DiagnosticEvent struct {
+	Object   string    `json:"object"` // Kind/name, e.g. "Pod/virt-launcher-x-abcde"
+	Type     string    `json:"type"`   // Normal, Warning
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Limit trims the bundle to the size limits, keeping the newest events.
+// Providers call it before returning.
+func (b *DiagnosticsBundle) Limit() {
+	if n := len(b.Events); n > MaxDiagnosticEvents {
+		b.Events = b.Events[n-MaxDiagnosticEvents:] // Sorted by LastSeen ascending
+	}
+	for i := range b.Events {
+		b.Events[i].Message = truncate(b.Events[i].Message, MaxDiagnosticMessageLen)
+	}
+	for i := range b.VMIConditions {
+		b.VMIConditions[i].Message = truncate(b.VMIConditions[i].Message, MaxDiagnosticMessageLen)
+	}
+	for i, m := range b.SchedulerMessages {
+		b.SchedulerMessages[i] = truncate(m, MaxDiagnosticMessageLen)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
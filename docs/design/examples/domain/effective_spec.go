@@ -0,0 +1,116 @@
+// Package domain provides domain models.
+//
+// This file defines how an approver's ModifiedSpec is applied to an event
+// payload, for every registered payload type.
+//
+// The payload stays immutable (ADR-0009); the ticket stores the
+// ModifiedSpec and every reader (approval, quota, spec diff, worker)
+// resolves the effective spec through GetEffectiveSpec. The merge is
+// field-level: each field set in ModifiedSpec replaces the payload field
+// of the same JSON name, all others are kept. A payload type opts in by
+// implementing Modifiable and lists the fields an approver may change:
+//
+//	Payload              Modifiable fields
+//	──────────────────────────────────────────────────────────
+//	VMCreationPayload    cpu, memory_mb, disk_gb, template_id,   (batch create items too)
+//	                     cluster_id, instance_size_id,
+//	                     instance_size_version, gpus
+//	VMModifyPayload      cpu, memory_mb
+//	all others           none: modified_by/modified_reason only
+//
+// Identity fields (VM, namespace, Service) are never modifiable (ADR-0017),
+// so a ModifiedSpec cannot move a request out of the approver's scope. A
+// field not listed for the payload fails the approval with
+// ErrSpecFieldNotModifiable instead of being ignored.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Modifiable is implemented by payloads an approver may change.
+type Modifiable interface {
+	EventPayload
+	// ModifiableFields returns the JSON names of the ModifiedSpec fields
+	// that apply to the payload.
+	ModifiableFields() []string
+}
+
+// GetEffectiveSpec returns the payload of an event of type t as it will
+// execute: originalPayload (decoded and upcast) with modifiedSpec (nil if
+// unmodified) merged field by field. T must be registered for t.
+//
+// The result is not validated, like any decoded payload; approval use
+// cases validate it before storing a modification.
+func GetEffectiveSpec[T EventPayload](t EventType, originalPayload, modifiedSpec []byte) (T, error) {
+	original, err := UnmarshalPayload[T](t, originalPayload)
+	if err != nil || modifiedSpec == nil {
+		return original, err
+	}
+	var mods ModifiedSpec
+	if err := json.Unmarshal(modifiedSpec, &mods); err != nil {
+		return original, fmt.Errorf("decode modified spec: %w", err)
+	}
+	effective, err := applyModifiedSpec(original, &mods)
+	if err != nil {
+		return original, err
+	}
+	return effective.(T), nil
+}
+
+// applyModifiedSpec merges the fields set in mods into p by JSON name and
+// returns a new payload of p's type.
+func applyModifiedSpec(p EventPayload, mods *ModifiedSpec) (EventPayload, error) {
+	set, err := jsonFields(mods)
+	if err != nil {
+		return nil, err
+	}
+	delete(set, "modified_by")
+	delete(set, "modified_reason")
+	if len(set) == 0 {
+		return p, nil
+	}
+
+	var allowed []string
+	if m, ok := p.(Modifiable); ok {
+		allowed = m.ModifiableFields()
+	}
+	fields, err := jsonFields(p)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Report the same field first every time
+	for _, name := range names {
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("%s of %T: %w", name, p, ErrSpecFieldNotModifiable)
+		}
+		fields[name] = set[name]
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encode effective spec: %w", err)
+	}
+	effective := reflect.New(reflect.TypeOf(p))
+	if err := json.Unmarshal(data, effective.Interface()); err != nil {
+		return nil, fmt.Errorf("decode effective spec: %w", err)
+	}
+	return effective.Elem().Interface().(EventPayload), nil
+}
+
+// Errors
+var (
+	ErrSpecFieldNotModifiable = domainerr.Invalid("SPEC_FIELD_NOT_MODIFIABLE", "field may not be modified by an approver")
+)
@@ -0,0 +1,167 @@
+// Package domain provides domain models.
+//
+// This file defines the emergency stop of every VM of a Service or System.
+//
+// For incident containment (compromised workload, runaway cost, data
+// leak), an owner stops everything in scope with one confirmation. Unlike
+// power requests, no ticket is created and nothing waits: the stops run on
+// the emergency River queue, are not held by change freezes (no ticket)
+// and have no maintenance window. Every stop is still audited, as the
+// stop itself and per VM.
+//
+// Execution follows the batch model (ADR-0015 §19): one parent record,
+// one child EMERGENCY_STOP_ITEM_REQUESTED event per VM, independent
+// execution per item. VMs stay stopped until someone starts them again.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// EmergencyStopPermission is the permission to stop every VM in a
+// Service or System. Granted to the owner and admin resource roles and
+// to platform admins.
+const EmergencyStopPermission = "vm:emergency_stop"
+
+// EmergencyStopStatus is the status of an emergency stop (parent record).
+type EmergencyStopStatus string
+
+const (
+	EmergencyStopInProgress     EmergencyStopStatus = "IN_PROGRESS"
+	EmergencyStopCompleted      EmergencyStopStatus = "COMPLETED"
+	EmergencyStopPartialSuccess EmergencyStopStatus = "PARTIAL_SUCCESS"
+	EmergencyStopFailed         EmergencyStopStatus = "FAILED"
+)
+
+// EmergencyStopItemStatus is the status of a single VM in an emergency stop.
+type EmergencyStopItemStatus string
+
+const (
+	EmergencyStopItemPending EmergencyStopItemStatus = "PENDING" // Job enqueued
+	EmergencyStopItemStopped EmergencyStopItemStatus = "STOPPED"
+	EmergencyStopItemFailed  EmergencyStopItemStatus = "FAILED"
+)
+
+// EmergencyStopRequest is the owner's confirmation. Confirm must repeat
+// the Service or System name, as deletion confirmation repeats the VM
+// name.
+type EmergencyStopRequest struct {
+	Confirm string `json:"confirm"`
+	Reason  string `json:"reason"` // e.g. incident reference
+}
+
+// Validate checks the request against the name of the scope.
+func (r EmergencyStopRequest) Validate(scopeName string) error {
+	if r.Reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrEmergencyStopNotConfirmed)
+	}
+	if r.Confirm != scopeName {
+		return fmt.Errorf("confirm must be %q: %w", scopeName, ErrEmergencyStopNotConfirmed)
+	}
+	return nil
+}
+
+// EmergencyStoppable reports whether a VM in status is stopped by an
+// emergency stop. Stopping is idempotent, so only VMs that are already
+// off or going away are skipped; a VM still being created is stopped too.
+func EmergencyStoppable(status VMStatus) bool {
+	switch status {
+	case VMStatusStopped, VMStatusDeleting, VMStatusDeleted:
+		return false
+	default:
+		return true
+	}
+}
+
+// EmergencyStop is the parent record of an emergency stop.
+type EmergencyStop struct {
+	ID          string       `json:"id"`
+	EventID     string       `json:"event_id"`   // EMERGENCY_STOP_REQUESTED event
+	ScopeType   ResourceType `json:"scope_type"` // service or system
+	ScopeID     string       `json:"scope_id"`
+	Reason      string       `json:"reason"`
+	RequestedBy string       `json:"requested_by"`
+
+	Status       EmergencyStopStatus `json:"status"`
+	TotalCount   int                 `json:"total_count"`
+	StoppedCount int                 `json:"stopped_count"`
+	FailedCount  int                 `json:"failed_count"`
+	PendingCount int                 `json:"pending_count"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CalculateStatus derives the parent status from item counters.
+// Same rules as NodeDrain.CalculateStatus.
+func (s *EmergencyStop) CalculateStatus() EmergencyStopStatus {
+	if s.PendingCount > 0 {
+		return EmergencyStopInProgress
+	}
+	if s.FailedCount == 0 {
+		return EmergencyStopCompleted
+	}
+	if s.StoppedCount == 0 {
+		return EmergencyStopFailed
+	}
+	return EmergencyStopPartialSuccess
+}
+
+// EmergencyStopItem tracks one VM in an emergency stop.
+type EmergencyStopItem struct {
+	ID           string                  `json:"id"`
+	StopID       string                  `json:"stop_id"`
+	EventID      string                  `json:"event_id"` // Child event executed by River
+	VMID         string                  `json:"vm_id"`
+	VMName       string                  `json:"vm_name"`
+	Namespace    string                  `json:"namespace"`
+	Cluster      string                  `json:"cluster"`
+	Status       EmergencyStopItemStatus `json:"status"`
+	ErrorMessage string                  `json:"error_message,omitempty"`
+}
+
+// EmergencyStopPayload is the payload of EMERGENCY_STOP_REQUESTED (parent event).
+type EmergencyStopPayload struct {
+	StopID    string       `json:"stop_id"`
+	ScopeType ResourceType `json:"scope_type"`
+	ScopeID   string       `json:"scope_id"`
+	Reason    string       `json:"reason"`
+	VMCount   int          `json:"vm_count"`
+}
+
+// Validate requires the stop and its scope.
+func (p EmergencyStopPayload) Validate() error {
+	return requireFields("stop_id", p.StopID, "scope_type", string(p.ScopeType))
+}
+
+// EmergencyStopItemPayload is the payload of EMERGENCY_STOP_ITEM_REQUESTED
+// (child event).
+type EmergencyStopItemPayload struct {
+	StopID    string `json:"stop_id"`
+	ItemID    string `json:"item_id"`
+	VMID      string `json:"vm_id"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	VMName    string `json:"vm_name"`
+}
+
+// Validate requires the stop item and the VM it stops.
+func (p EmergencyStopItemPayload) Validate() error {
+	return requireFields("stop_id", p.StopID, "item_id", p.ItemID, "vm_id", p.VMID,
+		"cluster", p.Cluster, "namespace", p.Namespace, "vm_name", p.VMName)
+}
+
+// Errors
+var (
+	ErrEmergencyStopNotConfirmed  = domainerr.Invalid("EMERGENCY_STOP_NOT_CONFIRMED", "emergency stop not confirmed")
+	ErrEmergencyStopInProgress    = domainerr.Conflict("EMERGENCY_STOP_IN_PROGRESS", "an emergency stop is already in progress for this scope")
+	ErrEmergencyStopNothingToStop = domainerr.Conflict("NOTHING_TO_STOP", "no VMs to stop")
+	ErrEmergencyStopForbidden     = domainerr.PermissionDenied("EMERGENCY_STOP_FORBIDDEN", "emergency stop requires owner or admin on the service or system")
+	ErrInvalidEmergencyStopScope  = errors.New("emergency stop scope must be a service or system")
+)
@@ -0,0 +1,167 @@
+// Package domain provides domain models.
+//
+// This file defines the per-Service deployment environment and the
+// approval policy attached to each environment.
+//
+// The deployment environment (dev/stage/prod) is a Service attribute and
+// drives governance. It is distinct from the namespace environment
+// (test/prod, ADR-0015 §15), which drives infrastructure placement: a
+// Service may only deploy into namespaces of the matching class.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// DeploymentEnvironment is a Service's environment.
+type DeploymentEnvironment string
+
+const (
+	EnvironmentDev   DeploymentEnvironment = "dev"
+	EnvironmentStage DeploymentEnvironment = "stage"
+	EnvironmentProd  DeploymentEnvironment = "prod"
+)
+
+// NamespaceEnvironment returns the namespace environment class the
+// Service may deploy into.
+func (e DeploymentEnvironment) NamespaceEnvironment() string {
+	if e == EnvironmentProd {
+		return "prod"
+	}
+	return "test"
+}
+
+// CheckNamespaceEnvironment rejects a request whose namespace does not
+// match the Service's environment class (a dev Service cannot land in prod).
+func CheckNamespaceEnvironment(service DeploymentEnvironment, namespaceEnv string) error {
+	if service.NamespaceEnvironment() != namespaceEnv {
+		return fmt.Errorf("%s service into %s namespace: %w", service, namespaceEnv, ErrEnvironmentMismatch)
+	}
+	return nil
+}
+
+// SizeLimit bounds a spec. Zero fields are not limited.
+type SizeLimit struct {
+	CPU      int `json:"cpu,omitempty"`
+	MemoryMB int `json:"memory_mb,omitempty"`
+	DiskGB   int `json:"disk_gb,omitempty"`
+}
+
+// Fits reports whether spec is within the limit.
+func (l *SizeLimit) Fits(spec *VMCreationPayload) bool {
+	return (l.CPU == 0 || spec.CPU <= l.CPU) &&
+		(l.MemoryMB == 0 || spec.MemoryMB <= l.MemoryMB) &&
+		(l.DiskGB == 0 || spec.DiskGB <= l.DiskGB)
+}
+
+// EnvironmentPolicy is the approval policy of one environment
+// (environment_policies, editable by platform admins).
+type EnvironmentPolicy struct {
+	Environment DeploymentEnvironment `json:"environment"`
+
+	// RequiredApprovals is the number of distinct approvers needed.
+	// 2 enforces the two-person rule.
+	RequiredApprovals int `json:"required_approvals"`
+
+	// AutoApproveLimit, if set, lets requests within it skip approval.
+	AutoApproveLimit *SizeLimit `json:"auto_approve_limit,omitempty"`
+
+	// MaxSize, if set, refuses requests above it at submission, before
+	// any approver sees them.
+	MaxSize *SizeLimit `json:"max_size,omitempty"`
+
+	// StagedApproval, if set, routes requests above its limit through
+	// ordered stages (e.g., team lead, then platform admin for large VMs).
+	StagedApproval *StagedApproval `json:"staged_approval,omitempty"`
+
+	// ApprovalSLA is how long a normal-priority ticket may stay pending
+	// before it is escalated. Zero uses DefaultApprovalSLA.
+	ApprovalSLA time.Duration `json:"approval_sla,omitempty"`
+}
+
+// DefaultEnvironmentPolicies are seeded on first start.
+var DefaultEnvironmentPolicies = []*EnvironmentPolicy{
+	{Environment: EnvironmentDev, RequiredApprovals: 1, AutoApproveLimit: &SizeLimit{CPU: 4, MemoryMB: 8192, DiskGB: 100}, ApprovalSLA: 72 * time.Hour},
+	{Environment: EnvironmentStage, RequiredApprovals: 1, ApprovalSLA: 24 * time.Hour},
+	{Environment: EnvironmentProd, RequiredApprovals: 2, ApprovalSLA: 8 * time.Hour},
+}
+
+// Validate checks a policy before it is saved. prod always requires
+// approval by two people; this is not configurable.
+func (p *EnvironmentPolicy) Validate() error {
+	if p.RequiredApprovals < 1 {
+		return fmt.Errorf("required_approvals must be at least 1: %w", ErrInvalidEnvironmentPolicy)
+	}
+	if p.ApprovalSLA < 0 {
+		return fmt.Errorf("approval_sla must not be negative: %w", ErrInvalidEnvironmentPolicy)
+	}
+	if p.Environment == EnvironmentProd {
+		if p.RequiredApprovals < 2 {
+			return fmt.Errorf("prod requires two approvers: %w", ErrInvalidEnvironmentPolicy)
+		}
+		if p.AutoApproveLimit != nil {
+			return fmt.Errorf("prod cannot auto-approve: %w", ErrInvalidEnvironmentPolicy)
+		}
+	}
+	if p.StagedApproval != nil {
+		if err := p.StagedApproval.Validate(); err != nil {
+			return err
+		}
+		if p.Environment == EnvironmentProd && TotalApprovals(p.StagedApproval.Stages) < 2 {
+			return fmt.Errorf("prod requires two approvers: %w", ErrInvalidEnvironmentPolicy)
+		}
+	}
+	return nil
+}
+
+// EnvironmentDecision is the outcome of evaluating a policy for a request.
+type EnvironmentDecision struct {
+	Environment       DeploymentEnvironment `json:"environment"`
+	AutoApprove       bool                  `json:"auto_approve"`
+	RequiredApprovals int                   `json:"required_approvals"` // Frozen on the ticket
+	Stages            []ApprovalStage       `json:"stages"`             // Frozen on the ticket
+	Staged            bool                  `json:"staged"`             // StagedApproval applies
+	Refused           bool                  `json:"refused"`            // Above MaxSize: not submitted
+	ApprovalSLA       time.Duration         `json:"approval_sla"`       // Base for the ticket's SLA due time
+}
+
+// AllowsAutoApproval reports whether any route may skip approval: never
+// in prod, never when the request needs staged approval.
+func (d EnvironmentDecision) AllowsAutoApproval() bool {
+	return d.Environment != EnvironmentProd && !d.Staged
+}
+
+// Evaluate decides how a request with spec is approved.
+func (p *EnvironmentPolicy) Evaluate(spec *VMCreationPayload) EnvironmentDecision {
+	d := EnvironmentDecision{
+		Environment:       p.Environment,
+		AutoApprove:       p.AutoApproveLimit != nil && p.AutoApproveLimit.Fits(spec),
+		RequiredApprovals: p.RequiredApprovals,
+		Stages:            DefaultStages(p.RequiredApprovals),
+		ApprovalSLA:       p.ApprovalSLA,
+	}
+	if p.StagedApproval != nil && !p.StagedApproval.Above.Fits(spec) {
+		d.AutoApprove = false
+		d.Stages = p.StagedApproval.Stages
+		d.Staged = true
+	}
+	if p.MaxSize != nil && !p.MaxSize.Fits(spec) {
+		d.AutoApprove = false
+		d.Refused = true
+	}
+	return d
+}
+
+// Errors
+var (
+	ErrEnvironmentMismatch      = domainerr.Unprocessable("ENVIRONMENT_MISMATCH", "namespace environment does not match service environment")
+	ErrInvalidEnvironmentPolicy = domainerr.Invalid("INVALID_ENVIRONMENT_POLICY", "invalid environment policy")
+	ErrApprovalRequired         = errors.New("environment policy requires approval")
+	ErrAboveEnvironmentMaxSize  = domainerr.Unprocessable("ABOVE_ENVIRONMENT_MAX_SIZE", "request exceeds the environment's maximum size")
+)
@@ -0,0 +1,164 @@
+// Package errors provides typed domain errors carrying a stable code
+// (Phase 1 §6), so handlers map them to an HTTP status by kind and the
+// frontend keys its i18n messages off the code instead of the English
+// message.
+//
+//	kind                 status
+//	NotFound             404
+//	Invalid              400
+//	PermissionDenied     403
+//	Conflict             409
+//	PreconditionFailed   409 (ADR-0023: precondition conflicts are 409)
+//	Gone                 410
+//	Unprocessable        422
+//	QuotaExceeded        429
+//
+// Sentinels are declared as *Error values and wrapped as usual
+// (fmt.Errorf("...: %w", ErrX)); errors.Is matches the sentinel a copy was
+// made from, so a copy with params (ErrX.With("version", 3)) still matches
+// ErrX, and sentinels sharing a code (INVALID_REQUEST) do not match each
+// other. Handlers find the code with errors.As, however deeply the error
+// is wrapped.
+//
+// The package shadows the standard library's errors; import it as
+// domainerr.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain/errors
+package errors
+
+import "errors"
+
+// Kind is the class of a domain error; it decides the HTTP status.
+type Kind string
+
+const (
+	KindNotFound           Kind = "not_found"
+	KindInvalid            Kind = "invalid"
+	KindPermissionDenied   Kind = "permission_denied"
+	KindConflict           Kind = "conflict"
+	KindPreconditionFailed Kind = "precondition_failed"
+	KindGone               Kind = "gone"
+	KindUnprocessable      Kind = "unprocessable"
+	KindQuotaExceeded      Kind = "quota_exceeded"
+)
+
+// Error is a domain error with a stable code. Code and Params are the
+// client contract (AppError, Phase 1 §6); Message is English, for logs
+// only, and never sent to clients.
+type Error struct {
+	Kind    Kind
+	Code    string                 // e.g. "POLICY_EXPRESSION_VERSION_CONFLICT"; never renamed
+	Message string                 // English, for logs
+	Params  map[string]interface{} // Interpolated by the frontend's message for Code
+
+	sentinel *Error // The sentinel a copy was made from; nil on sentinels
+}
+
+// New returns an error of kind with code.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NotFound returns a KindNotFound error.
+func NotFound(code, message string) *Error { return New(KindNotFound, code, message) }
+
+// Invalid returns a KindInvalid error.
+func Invalid(code, message string) *Error { return New(KindInvalid, code, message) }
+
+// PermissionDenied returns a KindPermissionDenied error.
+func PermissionDenied(code, message string) *Error { return New(KindPermissionDenied, code, message) }
+
+// Conflict returns a KindConflict error.
+func Conflict(code, message string) *Error { return New(KindConflict, code, message) }
+
+// PreconditionFailed returns a KindPreconditionFailed error.
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+// Gone returns a KindGone error.
+func Gone(code, message string) *Error { return New(KindGone, code, message) }
+
+// Unprocessable returns a KindUnprocessable error.
+func Unprocessable(code, message string) *Error { return New(KindUnprocessable, code, message) }
+
+// QuotaExceeded returns a KindQuotaExceeded error.
+func QuotaExceeded(code, message string) *Error { return New(KindQuotaExceeded, code, message) }
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is e's sentinel, so a copy made by With or
+// WithKind matches the sentinel it was made from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.root() == e.root()
+}
+
+func (e *Error) root() *Error {
+	if e.sentinel != nil {
+		return e.sentinel
+	}
+	return e
+}
+
+// With returns a copy of e with the param key set; e is not changed, so
+// it is safe on sentinels.
+func (e *Error) With(key string, value interface{}) *Error {
+	c := e.copy()
+	c.Params = make(map[string]interface{}, len(e.Params)+1)
+	for k, v := range e.Params {
+		c.Params[k] = v
+	}
+	c.Params[key] = value
+	return c
+}
+
+// WithKind returns a copy of e with another kind, for a code whose status
+// depends on where it is returned (NO_CLUSTER_FOR_SIZE: 422 at submission,
+// 409 at approval).
+func (e *Error) WithKind(kind Kind) *Error {
+	c := e.copy()
+	c.Kind = kind
+	return c
+}
+
+func (e *Error) copy() *Error {
+	c := *e
+	c.sentinel = e.root()
+	return &c
+}
+
+// MessageKey is the key of code in the frontend's message catalog. Error
+// responses carry it as "message" in place of the English Message.
+func MessageKey(code string) string {
+	return "errors." + code
+}
+
+// As returns the first *Error in err's chain.
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first *Error in err's chain, "" if there
+// is none.
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return ""
+}
+
+// CodeOf returns the code of the first *Error in err's chain, "" if there
+// is none.
+func CodeOf(err error) string {
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+	return ""
+}
@@ -11,6 +11,7 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -44,6 +45,53 @@ const (
 	EventVMRestartCompleted EventType = "VM_RESTART_COMPLETED"
 	EventVMRestartFailed    EventType = "VM_RESTART_FAILED"
 
+	// VM Snapshot Events (vm_snapshot.go)
+	EventVMSnapshotRequested EventType = "VM_SNAPSHOT_REQUESTED"
+	EventVMSnapshotCompleted EventType = "VM_SNAPSHOT_COMPLETED"
+	EventVMSnapshotFailed    EventType = "VM_SNAPSHOT_FAILED"
+
+	// VM Backup Events (vm_backup.go)
+	EventVMBackupRequested EventType = "VM_BACKUP_REQUESTED"
+
+	// VM Restore Events (vm_restore.go)
+	EventVMRestoreRequested EventType = "VM_RESTORE_REQUESTED"
+
+	// VM Clone Events (vm_clone.go)
+	EventVMCloneRequested EventType = "VM_CLONE_REQUESTED"
+	EventVMCloneCompleted EventType = "VM_CLONE_COMPLETED"
+	EventVMCloneFailed    EventType = "VM_CLONE_FAILED"
+
+	// VM Lease Events (no River job: the final approval extends the lease)
+	EventVMLeaseRenewalRequested EventType = "VM_LEASE_RENEWAL_REQUESTED"
+
+	// Live Migration Events
+	EventVMMigrationRequested EventType = "VM_MIGRATION_REQUESTED"
+	EventVMMigrationCompleted EventType = "VM_MIGRATION_COMPLETED"
+	EventVMMigrationFailed    EventType = "VM_MIGRATION_FAILED"
+
+	// Node Maintenance Events (parent + one item event per VM, by action)
+	EventNodeDrainRequested              EventType = "NODE_DRAIN_REQUESTED"
+	EventNodeDrainCompleted              EventType = "NODE_DRAIN_COMPLETED"
+	EventNodeDrainFailed                 EventType = "NODE_DRAIN_FAILED"
+	EventNodeDrainItemMigrationRequested EventType = "NODE_DRAIN_ITEM_MIGRATION_REQUESTED"
+	EventNodeDrainItemRestartRequested   EventType = "NODE_DRAIN_ITEM_RESTART_REQUESTED"
+
+	// Emergency Stop Events (parent + one item event per VM)
+	EventEmergencyStopRequested     EventType = "EMERGENCY_STOP_REQUESTED"
+	EventEmergencyStopCompleted     EventType = "EMERGENCY_STOP_COMPLETED"
+	EventEmergencyStopFailed        EventType = "EMERGENCY_STOP_FAILED"
+	EventEmergencyStopItemRequested EventType = "EMERGENCY_STOP_ITEM_REQUESTED"
+
+	// Cross-Cluster Relocation Events (cold migration)
+	EventVMRelocationRequested EventType = "VM_RELOCATION_REQUESTED"
+	EventVMRelocationCompleted EventType = "VM_RELOCATION_COMPLETED"
+	EventVMRelocationFailed    EventType = "VM_RELOCATION_FAILED"
+
+	// Cluster Decommission Events (parent of per-VM relocation events)
+	EventClusterDecommissionRequested EventType = "CLUSTER_DECOMMISSION_REQUESTED"
+	EventClusterDecommissionCompleted EventType = "CLUSTER_DECOMMISSION_COMPLETED"
+	EventClusterDecommissionCancelled EventType = "CLUSTER_DECOMMISSION_CANCELLED"
+
 	// VNC Console Events (ADR-0015 §18)
 	EventVNCAccessRequested EventType = "VNC_ACCESS_REQUESTED"
 	EventVNCAccessGranted   EventType = "VNC_ACCESS_GRANTED"
@@ -60,6 +108,7 @@ const (
 
 	// Request Lifecycle Events (ADR-0015 §10)
 	EventRequestCancelled EventType = "REQUEST_CANCELLED"
+	EventRequestExpired   EventType = "REQUEST_EXPIRED"
 
 	// Notification Events (ADR-0015 §20)
 	EventNotificationSent EventType = "NOTIFICATION_SENT"
@@ -83,22 +132,32 @@ const (
 	EventStatusCancelled  EventStatus = "CANCELLED"
 )
 
+// IsTerminal reports whether no further processing may happen.
+func (s EventStatus) IsTerminal() bool {
+	return s == EventStatusCompleted || s == EventStatusFailed || s == EventStatusCancelled
+}
+
 // DomainEvent represents an immutable domain event.
 //
 // Key Constraints (ADR-0009):
-// 1. Payload is IMMUTABLE (append-only)
-// 2. Modifications stored in ApprovalTicket.ModifiedSpec (full replacement, not diff)
-// 3. Worker calls GetEffectiveSpec() to get final config
+//  1. Payload is IMMUTABLE (append-only)
+//  2. Modifications stored in ApprovalTicket.ModifiedSpec
+//  3. Worker calls GetEffectiveSpec() to get final config, after Upcast()
+//     (which also decompresses the payload); see effective_spec.go
 type DomainEvent struct {
-	EventID       string      `json:"event_id"`
-	EventType     EventType   `json:"event_type"`
-	AggregateType string      `json:"aggregate_type"`
-	AggregateID   string      `json:"aggregate_id"`
-	Payload       []byte      `json:"payload"` // Immutable JSON
-	Status        EventStatus `json:"status"`
-	CreatedBy     string      `json:"created_by"`
-	CreatedAt     time.Time   `json:"created_at"`
-	ArchivedAt    *time.Time  `json:"archived_at"` // Soft archive for cleanup
+	EventID         string          `json:"event_id"`
+	EventType       EventType       `json:"event_type"`
+	AggregateType   string          `json:"aggregate_type"`
+	AggregateID     string          `json:"aggregate_id"`
+	Payload         []byte          `json:"payload"`          // Immutable JSON, as stored until Upcast
+	PayloadEncoding PayloadEncoding `json:"payload_encoding"` // Compression of the stored payload; see event_encoding.go
+	SchemaVersion   int             `json:"schema_version"`   // Payload version written; see event_upcast.go
+	Result          []byte          `json:"result,omitempty"` // Set once by the handler, e.g. the snapshot taken
+	Status          EventStatus     `json:"status"`
+	RequeueCount    int             `json:"requeue_count"` // Admin requeues after FAILED; see event_requeue.go
+	CreatedBy       string          `json:"created_by"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ArchivedAt      *time.Time      `json:"archived_at"` // Set when moved to domain_events_archive (event_archive.go)
 }
 
 // VMCreationPayload is the payload for VM creation events.
@@ -113,77 +172,77 @@ type DomainEvent struct {
 type VMCreationPayload struct {
 	ServiceID  string `json:"service_id"`
 	TemplateID string `json:"template_id"`
-	// NOTE: ClusterID is NOT in user request - selected during approval (master-flow.md)
-	// NOTE: Namespace is resolved from Service at execution time
-	CPU      int    `json:"cpu"`
-	MemoryMB int    `json:"memory_mb"`
-	DiskGB   int    `json:"disk_gb,omitempty"`
-	Reason   string `json:"reason"`
+	Namespace  string `json:"namespace"` // Immutable after submission
+	CPU        int    `json:"cpu"`
+	MemoryMB   int    `json:"memory_mb"`
+	DiskGB     int    `json:"disk_gb,omitempty"`
+	Reason     string `json:"reason"`
+	// NOTE: ClusterID and InstanceSizeID are never set by the requester;
+	// they are filled from the approver's ModifiedSpec (ADR-0017, ADR-0018).
+	// An empty ClusterID leaves the choice to weight-based selection
+	// (ADR-0015 §15).
+	ClusterID      string `json:"cluster_id,omitempty"`
+	InstanceSizeID string `json:"instance_size_id,omitempty"`
+	// InstanceSizeVersion is the version of InstanceSizeID the approval
+	// pinned; 0 for payloads approved before sizes were versioned.
+	InstanceSizeVersion int `json:"instance_size_version,omitempty"`
+	// GPUs are the approved size's GPUs (gpu.go), set with it by
+	// ApplyInstanceSize; never set without InstanceSizeID.
+	GPUs []GPUDevice `json:"gpus,omitempty"`
+	// AntiAffinityGroup is the batch ticket ID of a batch child whose batch
+	// requires node anti-affinity (batch_placement.go); set at submission,
+	// not modifiable.
+	AntiAffinityGroup string `json:"anti_affinity_group,omitempty"`
 	// NOTE: Name is platform-generated, not stored in payload (ADR-0015 §4)
-}
 
-// ToJSON converts payload to JSON bytes.
-func (p VMCreationPayload) ToJSON() []byte {
-	data, _ := json.Marshal(p)
-	return data
+	// Lease, when set, is copied onto the VM by the creation worker
+	// (vm_lease.go).
+	Lease *LeaseTerms `json:"lease,omitempty"`
 }
 
-// ModifiedSpec contains admin modifications.
-// This is a FULL replacement, not a diff.
-type ModifiedSpec struct {
-	CPU            *int    `json:"cpu,omitempty"`
-	MemoryMB       *int    `json:"memory_mb,omitempty"`
-	DiskGB         *int    `json:"disk_gb,omitempty"`
-	TemplateID     *string `json:"template_id,omitempty"`
-	ModifiedBy     string  `json:"modified_by"`
-	ModifiedReason string  `json:"modified_reason"`
-}
-
-// ToJSON converts modified spec to JSON bytes.
-func (m *ModifiedSpec) ToJSON() []byte {
-	if m == nil {
-		return nil
+// Validate requires the Service, template, namespace and reason; the
+// cluster is chosen at approval and is not part of the submission. GPUs
+// come only with an InstanceSize, so a requester cannot submit them.
+func (p VMCreationPayload) Validate() error {
+	if err := requireFields("service_id", p.ServiceID, "template_id", p.TemplateID, "namespace", p.Namespace, "reason", p.Reason); err != nil {
+		return err
 	}
-	data, _ := json.Marshal(m)
-	return data
-}
-
-// GetEffectiveSpec returns the final spec to use.
-// Uses ModifiedSpec if present, otherwise original payload.
-//
-// Key Pattern: Field-level override (merge), NOT full replacement.
-// Only non-nil fields in ModifiedSpec are applied to the original.
-// This allows admin to modify only specific fields while preserving others.
-func GetEffectiveSpec(originalPayload []byte, modifiedSpec []byte) (*VMCreationPayload, error) {
-	var original VMCreationPayload
-	if err := json.Unmarshal(originalPayload, &original); err != nil {
-		return nil, err
+	if len(p.GPUs) > 0 && p.InstanceSizeID == "" {
+		return fmt.Errorf("gpus without instance_size_id: %w", ErrInvalidGPURequest)
 	}
+	return ValidateGPUs(p.GPUs)
+}
 
-	// No modification, use original
-	if modifiedSpec == nil {
-		return &original, nil
-	}
+// ModifiableFields lets approvers resize the VM, pick another template
+// and choose where it runs.
+func (VMCreationPayload) ModifiableFields() []string {
+	return []string{"cpu", "memory_mb", "disk_gb", "template_id", "cluster_id", "instance_size_id", "instance_size_version", "gpus"}
+}
 
-	// Apply modifications (full field replacement)
-	var mods ModifiedSpec
-	if err := json.Unmarshal(modifiedSpec, &mods); err != nil {
-		return nil, err
-	}
+// ModifiedSpec contains admin modifications. Each field set replaces the
+// payload field of the same JSON name (effective_spec.go); fields a
+// payload does not list in ModifiableFields are rejected.
+type ModifiedSpec struct {
+	CPU                 *int        `json:"cpu,omitempty"`
+	MemoryMB            *int        `json:"memory_mb,omitempty"`
+	DiskGB              *int        `json:"disk_gb,omitempty"`
+	TemplateID          *string     `json:"template_id,omitempty"`
+	ClusterID           *string     `json:"cluster_id,omitempty"`            // Admin-selected cluster (ADR-0017)
+	InstanceSizeID      *string     `json:"instance_size_id,omitempty"`      // Sets cpu/memory_mb, see ApplyInstanceSize
+	InstanceSizeVersion *int        `json:"instance_size_version,omitempty"` // Pinned version; current when unset
+	GPUs                []GPUDevice `json:"gpus,omitempty"`                  // Set by ApplyInstanceSize only
+	ModifiedBy          string      `json:"modified_by"`
+	ModifiedReason      string      `json:"modified_reason"`
+}
 
-	result := original
-	if mods.CPU != nil {
-		result.CPU = *mods.CPU
-	}
-	if mods.MemoryMB != nil {
-		result.MemoryMB = *mods.MemoryMB
-	}
-	if mods.DiskGB != nil {
-		result.DiskGB = *mods.DiskGB
+// ToJSON converts modified spec to JSON bytes; nil for a nil spec.
+func (m *ModifiedSpec) ToJSON() ([]byte, error) {
+	if m == nil {
+		return nil, nil
 	}
-	if mods.TemplateID != nil {
-		result.TemplateID = *mods.TemplateID
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal modified spec: %w", err)
 	}
-
-	return &result, nil
+	return data, nil
 }
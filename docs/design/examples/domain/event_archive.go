@@ -0,0 +1,37 @@
+// Package domain provides domain models.
+//
+// This file defines event archival: finished events move out of the hot
+// domain_events table into domain_events_archive once they are older than
+// the archive window, so the table River workers and use cases hit stays
+// small while the history stays queryable.
+//
+// Archival is not deletion: the retention purge (retention.go) still
+// decides when an event is gone, in either table. Point lookups fall back
+// to the archive (repository/event_archive.go), and list and timeline
+// queries read both tables through the domain_events_all view.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// DefaultEventArchiveAfter is the archive window when not configured.
+const DefaultEventArchiveAfter = 30 * 24 * time.Hour
+
+// MinEventArchiveAfter keeps recent events hot: the quota sweep and
+// duplicate-delivery checks read them.
+const MinEventArchiveAfter = 7 * 24 * time.Hour
+
+// EventArchiveBatchSize bounds the rows moved per statement.
+const EventArchiveBatchSize = 1000
+
+// ArchivableEventStatuses are the statuses an event is archived in. FAILED
+// events stay hot: an admin may requeue them (event_requeue.go) and their
+// diagnostics bundle is read by ID.
+var ArchivableEventStatuses = []EventStatus{EventStatusCompleted, EventStatusCancelled}
+
+// EventArchiveCutoff returns the creation time before which finished
+// events are archived; after is raised to MinEventArchiveAfter.
+func EventArchiveCutoff(now time.Time, after time.Duration) time.Time {
+	return now.Add(-max(after, MinEventArchiveAfter))
+}
@@ -0,0 +1,121 @@
+// Package domain provides domain models.
+//
+// This file defines payload compression. Batch payloads list every item
+// (a batch delete snapshots hundreds of VMs), and PostgreSQL compresses
+// large JSONB values poorly and only past its TOAST threshold. Payloads
+// above PayloadCompressionThreshold are stored compressed, with the
+// encoding recorded next to them (domain_events.payload_encoding).
+//
+// Compression is below upcasting: readers decode first, then upcast
+// (DomainEvent.Upcast, usecase eventPayload), so handlers and
+// GetEffectiveSpec always see plain JSON of the current version.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// PayloadEncoding is how a stored payload is encoded.
+type PayloadEncoding string
+
+const (
+	PayloadEncodingIdentity PayloadEncoding = "identity" // Plain JSON (JSONB column)
+	PayloadEncodingGzip     PayloadEncoding = "gzip"
+	PayloadEncodingZstd     PayloadEncoding = "zstd"
+)
+
+// PayloadWriteEncoding is used for new payloads above the threshold. Both
+// gzip and zstd are always decoded, so changing it never strands stored
+// events.
+const PayloadWriteEncoding = PayloadEncodingZstd
+
+// PayloadCompressionThreshold is the JSON size from which payloads are
+// compressed. Smaller payloads stay JSONB: queryable and scrubbable in SQL.
+const PayloadCompressionThreshold = 8 << 10
+
+// MaxPayloadSize bounds a decoded payload, so a corrupt or hostile row
+// cannot exhaust memory.
+const MaxPayloadSize = 16 << 20
+
+// The zstd coders are safe for concurrent use and costly to create.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxPayloadSize))
+)
+
+// EncodePayload returns the payload as stored: compressed with
+// PayloadWriteEncoding when above the threshold and actually smaller,
+// else unchanged.
+func EncodePayload(payload []byte) ([]byte, PayloadEncoding, error) {
+	if len(payload) < PayloadCompressionThreshold {
+		return payload, PayloadEncodingIdentity, nil
+	}
+	if len(payload) > MaxPayloadSize {
+		return nil, "", fmt.Errorf("%d bytes: %w", len(payload), ErrPayloadTooLarge)
+	}
+
+	var stored []byte
+	switch PayloadWriteEncoding {
+	case PayloadEncodingZstd:
+		stored = zstdEncoder.EncodeAll(payload, nil)
+	case PayloadEncodingGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, "", fmt.Errorf("gzip payload: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", fmt.Errorf("gzip payload: %w", err)
+		}
+		stored = buf.Bytes()
+	}
+	if len(stored) >= len(payload) {
+		return payload, PayloadEncodingIdentity, nil
+	}
+	return stored, PayloadWriteEncoding, nil
+}
+
+// DecodePayload returns the JSON of a stored payload. An empty encoding
+// is identity: rows written before compression existed.
+func DecodePayload(enc PayloadEncoding, stored []byte) ([]byte, error) {
+	switch enc {
+	case "", PayloadEncodingIdentity:
+		return stored, nil
+	case PayloadEncodingZstd:
+		payload, err := zstdDecoder.DecodeAll(stored, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd payload: %w", err)
+		}
+		return payload, nil
+	case PayloadEncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(stored))
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		defer r.Close()
+		payload, err := io.ReadAll(io.LimitReader(r, MaxPayloadSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		if len(payload) > MaxPayloadSize {
+			return nil, ErrPayloadTooLarge
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("%q: %w", enc, ErrUnknownPayloadEncoding)
+	}
+}
+
+// Errors
+var (
+	ErrUnknownPayloadEncoding = errors.New("unknown event payload encoding")
+	ErrPayloadTooLarge        = errors.New("event payload too large")
+)
@@ -0,0 +1,124 @@
+// Package domain provides domain models.
+//
+// This file registers the typed payload of each event type.
+//
+// The registry is the single list consumers of the event stream are
+// given: /schemas/events publishes a JSON Schema per entry. An event type
+// with a payload must be registered here; an incompatible payload change
+// (field removed, renamed or retyped) bumps EventSchemaVersion and appends
+// an upcaster for the event type (event_upcast.go).
+//
+// It is also the only way payloads are encoded and decoded. Use cases
+// marshal through MarshalPayload, which validates, so a malformed payload
+// fails the submission inside its transaction instead of being stored and
+// failing the worker days later. Readers decode through UnmarshalPayload,
+// which does not validate: a rule tightened in a later release must not
+// strand events written before it (payloads are immutable, ADR-0009).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// EventSchemaVersion versions the published payload schemas. Additive
+// changes (new optional fields, new event types) do not bump it.
+const EventSchemaVersion = "v1"
+
+// EventPayload is implemented by every registered payload type. Validate
+// checks the payload is complete; business rules stay in the use case.
+type EventPayload interface {
+	Validate() error
+}
+
+// EventPayloads maps event types to a zero value of their payload type.
+// Completion/failure events carry no payload and are not listed.
+var EventPayloads = map[EventType]EventPayload{
+	EventVMCreationRequested:             VMCreationPayload{},
+	EventVMModifyRequested:               VMModifyPayload{},
+	EventVMDeletionRequested:             VMDeletionPayload{},
+	EventBatchCreateRequested:            BatchCreatePayload{},
+	EventBatchDeleteRequested:            BatchDeletePayload{},
+	EventNodeDrainRequested:              NodeDrainPayload{},
+	EventNodeDrainItemMigrationRequested: NodeDrainItemPayload{},
+	EventNodeDrainItemRestartRequested:   NodeDrainItemPayload{},
+	EventVMMigrationRequested:            MigrationPayload{},
+	EventClusterDecommissionRequested:    ClusterDecommissionPayload{},
+	EventVMRelocationRequested:           VMRelocationPayload{},
+	EventEmergencyStopRequested:          EmergencyStopPayload{},
+	EventEmergencyStopItemRequested:      EmergencyStopItemPayload{},
+	EventVMStartRequested:                PowerOperationPayload{},
+	EventVMStopRequested:                 PowerOperationPayload{},
+	EventVMRestartRequested:              PowerOperationPayload{},
+	EventVMSnapshotRequested:             SnapshotPayload{},
+	EventVMBackupRequested:               BackupPayload{},
+	EventVMRestoreRequested:              RestorePayload{},
+	EventVMCloneRequested:                ClonePayload{},
+	EventVMLeaseRenewalRequested:         LeaseRenewalPayload{},
+	EventVNCAccessRequested:              VNCAccessPayload{},
+	EventVNCAccessGranted:                VNCAccessDecisionPayload{},
+	EventVNCAccessDenied:                 VNCAccessDecisionPayload{},
+	EventVNCTokenRevoked:                 VNCTokenRevokedPayload{},
+	EventRequestCancelled:                RequestCancelledPayload{},
+	EventRequestExpired:                  RequestExpiredPayload{},
+}
+
+// payloadRegistered reports whether p's type is registered for t.
+func payloadRegistered(t EventType, p EventPayload) bool {
+	reg, ok := EventPayloads[t]
+	return ok && reflect.TypeOf(reg) == reflect.TypeOf(p)
+}
+
+// MarshalPayload validates p and encodes it as the payload of an event of
+// type t. p must be a payload type registered for t.
+func MarshalPayload(t EventType, p EventPayload) ([]byte, error) {
+	if !payloadRegistered(t, p) {
+		return nil, fmt.Errorf("%T is not registered for %s: %w", p, t, ErrInvalidEventPayload)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s payload: %w", t, err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s payload: %w", t, err)
+	}
+	return data, nil
+}
+
+// UnmarshalPayload decodes the payload of an event of type t into T, which
+// must be registered for t. data must already be decoded and upcast
+// (DomainEvent.Upcast).
+func UnmarshalPayload[T EventPayload](t EventType, data []byte) (T, error) {
+	var p T
+	if !payloadRegistered(t, p) {
+		return p, fmt.Errorf("%T is not registered for %s: %w", p, t, ErrInvalidEventPayload)
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("unmarshal %s payload: %w", t, err)
+	}
+	return p, nil
+}
+
+// requireFields rejects a payload with an empty required field; args are
+// pairs of JSON field name and value.
+func requireFields(fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			return fmt.Errorf("%s is required: %w", fields[i], ErrInvalidEventPayload)
+		}
+	}
+	return nil
+}
+
+// ModifiedSpecSchemaName is the schema name of ApprovalTicket.ModifiedSpec,
+// published next to the event payloads.
+const ModifiedSpecSchemaName = "ModifiedSpec"
+
+// Errors
+var (
+	ErrInvalidEventPayload = errors.New("invalid event payload")
+)
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validCreationPayload() VMCreationPayload {
+	return VMCreationPayload{
+		ServiceID:  "svc-1",
+		TemplateID: "tpl-ubuntu",
+		Namespace:  "team-a",
+		CPU:        2,
+		MemoryMB:   4096,
+		Reason:     "test",
+	}
+}
+
+func TestVMCreationPayloadValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		edit  func(p *VMCreationPayload)
+		valid bool
+	}{
+		{name: "complete", edit: func(p *VMCreationPayload) {}, valid: true},
+		{name: "no service", edit: func(p *VMCreationPayload) { p.ServiceID = "" }},
+		{name: "no template", edit: func(p *VMCreationPayload) { p.TemplateID = "" }},
+		{name: "no namespace", edit: func(p *VMCreationPayload) { p.Namespace = "" }},
+		{name: "no reason", edit: func(p *VMCreationPayload) { p.Reason = "" }},
+		{name: "gpus without size", edit: func(p *VMCreationPayload) { p.GPUs = []GPUDevice{{}} }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := validCreationPayload()
+			tc.edit(&p)
+			err := p.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+		})
+	}
+}
+
+// The write path rejects the payload before anything is stored.
+func TestMarshalPayloadRejectsEmptyReason(t *testing.T) {
+	p := validCreationPayload()
+	p.Reason = ""
+	_, err := MarshalPayload(EventVMCreationRequested, p)
+	require.ErrorIs(t, err, ErrInvalidEventPayload)
+	require.ErrorContains(t, err, "reason is required")
+}
@@ -0,0 +1,87 @@
+// Package domain provides domain models.
+//
+// This file defines the requeue of a FAILED event: a platform admin sends
+// it back to execution once the cause (an unreachable cluster, a missing
+// image) has been fixed, instead of asking the user to submit and approve
+// the same request again.
+//
+// A requeued event keeps its payload and ticket (ADR-0009): it goes back
+// to PROCESSING and a fresh EventJobArgs job is inserted in the same TX,
+// the same claim check as the approval. Each requeue is counted on the
+// event and audited with the admin who did it.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxEventRequeues bounds how often one event may be requeued; past it the
+// request has to be submitted again.
+const MaxEventRequeues = 3
+
+// MaxRequeueReasonLength bounds the free-text reason.
+const MaxRequeueReasonLength = 500
+
+// requeueableEvents are the event types whose handler state lives on the
+// event itself. Fan-out parents (drain, emergency stop, decommission,
+// batch) and their items have finalized their counters, and migrations
+// and relocations keep a terminal row of their own: they are started
+// again instead. Drain and emergency stop items have event types of their
+// own and decommission items are relocations, so none is listed.
+var requeueableEvents = map[EventType]bool{
+	EventVMCreationRequested: true,
+	EventVMModifyRequested:   true,
+	EventVMDeletionRequested: true,
+	EventVMStartRequested:    true,
+	EventVMStopRequested:     true,
+	EventVMRestartRequested:  true,
+	EventVMSnapshotRequested: true,
+	EventVMBackupRequested:   true,
+	EventVMRestoreRequested:  true,
+	EventVMCloneRequested:    true,
+}
+
+// RequeueRequest is the admin's input.
+type RequeueRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the reason.
+func (r RequeueRequest) Validate() error {
+	reason := strings.TrimSpace(r.Reason)
+	if reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrInvalidRequeueRequest)
+	}
+	if len(reason) > MaxRequeueReasonLength {
+		return fmt.Errorf("reason exceeds %d characters: %w", MaxRequeueReasonLength, ErrInvalidRequeueRequest)
+	}
+	return nil
+}
+
+// CheckRequeue reports whether e may be requeued. Batch children are
+// refused by the caller, which sees the ticket's parent.
+func CheckRequeue(e *DomainEvent) error {
+	if e.Status != EventStatusFailed {
+		return fmt.Errorf("event %s is %s: %w", e.EventID, e.Status, ErrEventNotFailed)
+	}
+	if !requeueableEvents[e.EventType] {
+		return fmt.Errorf("%s events cannot be requeued: %w", e.EventType, ErrEventNotRequeueable)
+	}
+	if e.RequeueCount >= MaxEventRequeues {
+		return fmt.Errorf("event requeued %d times: %w", e.RequeueCount, ErrRequeueLimitReached)
+	}
+	return nil
+}
+
+// Errors, typed with their codes (domain/errors/errors.go)
+var (
+	ErrEventNotFailed        = domainerr.Conflict("EVENT_NOT_FAILED", "event is not failed")
+	ErrEventNotRequeueable   = domainerr.Conflict("EVENT_NOT_REQUEUEABLE", "event cannot be requeued")
+	ErrRequeueLimitReached   = domainerr.Conflict("REQUEUE_LIMIT_REACHED", "event requeue limit reached")
+	ErrInvalidRequeueRequest = domainerr.Invalid("INVALID_REQUEST", "invalid requeue request")
+)
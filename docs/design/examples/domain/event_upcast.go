@@ -0,0 +1,83 @@
+// Package domain provides domain models.
+//
+// This file defines payload upcasting: how events written with an older
+// payload version are read after the payload struct changed.
+//
+// Payloads are immutable (ADR-0009), so a PENDING event submitted before
+// an upgrade keeps its old shape until it is approved and executed, which
+// may be days later. Every event records the version its payload was
+// written with (domain_events.schema_version); readers upgrade it step by
+// step to the current version before decoding, so handlers and
+// GetEffectiveSpec only ever see the current struct.
+//
+// An incompatible change to a payload (field removed, renamed or retyped)
+// appends an upcaster for its event type, e.g. memory_gb → memory_mb:
+//
+//	EventVMCreationRequested: {
+//		upcastMemoryGBToMB, // v1 → v2
+//	},
+//
+// Upcasters are never edited or removed once released: events of every
+// version they cover may still be pending. Event types shared by several
+// payloads (see EventPayloads) need an upcaster handling every shape.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayloadUpcaster rewrites a payload of version N into version N+1.
+// Pure: no I/O, deterministic, so re-reading an event gives the same result.
+type PayloadUpcaster func(payload []byte) ([]byte, error)
+
+// payloadUpcasters lists, per event type, the upcasters in order:
+// [0] upgrades v1 to v2, [1] v2 to v3, ... An event type without
+// entries is at version 1.
+var payloadUpcasters = map[EventType][]PayloadUpcaster{}
+
+// PayloadVersion returns the current payload version of an event type;
+// new events are written with it.
+func PayloadVersion(t EventType) int {
+	return len(payloadUpcasters[t]) + 1
+}
+
+// UpcastPayload upgrades a payload written with version to the current
+// version of its event type. A current payload is returned as is.
+func UpcastPayload(t EventType, version int, payload []byte) ([]byte, error) {
+	current := PayloadVersion(t)
+	if version < 1 || version > current {
+		// Newer than this binary: written by a newer replica during a rolling upgrade
+		return nil, fmt.Errorf("%s payload v%d (current v%d): %w", t, version, current, ErrUnknownPayloadVersion)
+	}
+	for v := version; v < current; v++ {
+		next, err := payloadUpcasters[t][v-1](payload)
+		if err != nil {
+			return nil, fmt.Errorf("upcast %s payload v%d: %w", t, v, err)
+		}
+		payload = next
+	}
+	return payload, nil
+}
+
+// Upcast decodes and upgrades the event's payload in memory; the stored
+// payload is never rewritten.
+func (e *DomainEvent) Upcast() error {
+	payload, err := DecodePayload(e.PayloadEncoding, e.Payload)
+	if err != nil {
+		return err
+	}
+	payload, err = UpcastPayload(e.EventType, e.SchemaVersion, payload)
+	if err != nil {
+		return err
+	}
+	e.Payload, e.PayloadEncoding, e.SchemaVersion = payload, PayloadEncodingIdentity, PayloadVersion(e.EventType)
+	return nil
+}
+
+// Errors
+var (
+	ErrUnknownPayloadVersion = errors.New("unknown event payload version")
+)
@@ -0,0 +1,78 @@
+// Package domain provides domain models.
+//
+// This file defines scheduled execution of approved requests.
+//
+// An approver may approve now and have the request execute later, e.g. in
+// a maintenance window. The planned time is stored on the ticket
+// (approval_tickets.execute_at) and the final approval inserts the River
+// job with ScheduledAt, in the same TX as today. Until the job starts, the
+// planned time can be moved, or brought forward to now.
+//
+// Everything decided at approval stays decided: the quota is reserved and
+// the VM counts as having a pending operation from approval until the
+// job runs. A change freeze covering the planned time still holds the job.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxExecutionDelay bounds how far ahead execution can be planned: the
+// quota reservation and the VM's pending-operation lock last until then.
+const MaxExecutionDelay = 30 * 24 * time.Hour
+
+// ValidateExecuteAt checks a planned execution time at now. Nil means
+// execute on final approval.
+func ValidateExecuteAt(at *time.Time, now time.Time) error {
+	if at == nil {
+		return nil
+	}
+	if !at.After(now) {
+		return fmt.Errorf("execute_at must be in the future: %w", ErrInvalidExecuteAt)
+	}
+	if at.Sub(now) > MaxExecutionDelay {
+		return fmt.Errorf("execute_at at most %s ahead: %w", MaxExecutionDelay, ErrInvalidExecuteAt)
+	}
+	return nil
+}
+
+// RescheduleExecutionRequest moves a planned execution. A null ExecuteAt
+// executes now.
+type RescheduleExecutionRequest struct {
+	ExecuteAt *time.Time `json:"execute_at"`
+}
+
+// Execution job states (river_job.state) in which the execution has not
+// started and can still be rescheduled.
+const (
+	ExecutionJobScheduled = "scheduled"
+	ExecutionJobAvailable = "available"
+)
+
+// CheckReschedule checks that a participant may move the planned execution
+// of an approved ticket: the approvers (or their delegates) and platform
+// admins, as long as the job has not started.
+func CheckReschedule(status TicketStatus, jobState string, p TicketParticipation) error {
+	if !p.Approver && p.DelegateOf == "" && !p.PlatformAdmin {
+		return ErrRescheduleForbidden
+	}
+	if status != TicketApproved {
+		return fmt.Errorf("ticket is %s: %w", status, ErrExecutionStarted)
+	}
+	if jobState != ExecutionJobScheduled && jobState != ExecutionJobAvailable {
+		return fmt.Errorf("job is %s: %w", jobState, ErrExecutionStarted)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInvalidExecuteAt    = domainerr.Invalid("INVALID_EXECUTE_AT", "invalid planned execution time")
+	ErrExecutionStarted    = domainerr.Conflict("EXECUTION_STARTED", "execution already started")
+	ErrRescheduleForbidden = domainerr.PermissionDenied("RESCHEDULE_FORBIDDEN", "only approvers and platform admins can reschedule execution")
+)
@@ -0,0 +1,129 @@
+// Package domain provides domain models.
+//
+// This file defines the GPUs of an InstanceSize and of the VMs created
+// from it. A size lists GPU models by the resource name KubeVirt's
+// permittedHostDevices advertises, with a count:
+//
+//	gpus: [{"device_name": "nvidia.com/GA102GL_A10", "count": 2}]
+//
+// The name is a PCI passthrough device or a mediated vGPU profile
+// ("nvidia.com/NVIDIA_A10-4Q"); KubeVirt requests both the same way, one
+// spec.domain.devices.gpus entry per GPU (ExpandGPUs). The approval
+// snapshots the size's GPUs into the payload like cpu and memory_mb, so
+// the final approval's headroom check, the worker and the VM list all see
+// the GPUs approved.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxGPUsPerVM bounds the GPUs of one size, over all its models.
+const MaxGPUsPerVM = 8
+
+// GPUDevice is Count GPUs of one model.
+type GPUDevice struct {
+	DeviceName string `json:"device_name"` // e.g. "nvidia.com/GA102GL_A10"
+	Count      int    `json:"count"`
+}
+
+// ValidateGPUs checks a size's GPU list: named models, each listed once,
+// counts of at least 1 and at most MaxGPUsPerVM GPUs in total.
+func ValidateGPUs(gpus []GPUDevice) error {
+	seen := make(map[string]bool, len(gpus))
+	for _, g := range gpus {
+		if g.DeviceName == "" {
+			return fmt.Errorf("gpu device_name is required: %w", ErrInvalidGPURequest)
+		}
+		if seen[g.DeviceName] {
+			return fmt.Errorf("gpu %s listed twice: %w", g.DeviceName, ErrInvalidGPURequest)
+		}
+		seen[g.DeviceName] = true
+		if g.Count < 1 {
+			return fmt.Errorf("gpu %s count %d: %w", g.DeviceName, g.Count, ErrInvalidGPURequest)
+		}
+	}
+	if total := TotalGPUs(gpus); total > MaxGPUsPerVM {
+		return fmt.Errorf("%d gpus, at most %d: %w", total, MaxGPUsPerVM, ErrInvalidGPURequest)
+	}
+	return nil
+}
+
+// TotalGPUs returns the number of GPUs over all models.
+func TotalGPUs(gpus []GPUDevice) int {
+	total := 0
+	for _, g := range gpus {
+		total += g.Count
+	}
+	return total
+}
+
+// GPUDeviceNames returns the models of gpus, in order.
+func GPUDeviceNames(gpus []GPUDevice) []string {
+	names := make([]string, len(gpus))
+	for i, g := range gpus {
+		names[i] = g.DeviceName
+	}
+	return names
+}
+
+// GPUAssignment is one spec.domain.devices.gpus entry of a VM.
+type GPUAssignment struct {
+	Name       string // Device name in the VM, "gpu0", "gpu1", ...
+	DeviceName string
+}
+
+// ExpandGPUs returns the devices.gpus entries for gpus, one per GPU,
+// numbered across models in order.
+func ExpandGPUs(gpus []GPUDevice) []GPUAssignment {
+	out := make([]GPUAssignment, 0, TotalGPUs(gpus))
+	for _, g := range gpus {
+		for i := 0; i < g.Count; i++ {
+			out = append(out, GPUAssignment{Name: fmt.Sprintf("gpu%d", len(out)), DeviceName: g.DeviceName})
+		}
+	}
+	return out
+}
+
+// CountGPUs is the inverse of ExpandGPUs: it groups a VM's devices.gpus
+// entries by model, in order of first appearance.
+func CountGPUs(assigned []GPUAssignment) []GPUDevice {
+	var out []GPUDevice
+	index := make(map[string]int)
+	for _, a := range assigned {
+		i, ok := index[a.DeviceName]
+		if !ok {
+			i = len(out)
+			index[a.DeviceName] = i
+			out = append(out, GPUDevice{DeviceName: a.DeviceName})
+		}
+		out[i].Count++
+	}
+	return out
+}
+
+// validateGPUs checks the size's GPUs (ValidateGPUs), that they agree
+// with requires_gpu, and that spec_overrides does not list GPUs too.
+// requires_gpu without gpus stays valid: any GPU model, as before GPUs
+// were listed.
+func (i *InstanceSize) validateGPUs() error {
+	if err := ValidateGPUs(i.GPUs); err != nil {
+		return err
+	}
+	if len(i.GPUs) > 0 && !i.RequiresGPU {
+		return fmt.Errorf("gpus set but requires_gpu is false: %w", ErrInvalidGPURequest)
+	}
+	if _, ok := i.SpecOverrides[gpuOverridePath]; ok && len(i.GPUs) > 0 {
+		return fmt.Errorf("gpus and spec_overrides %s are exclusive: %w", gpuOverridePath, ErrInvalidGPURequest)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInvalidGPURequest = domainerr.Invalid("INVALID_GPU_REQUEST", "invalid gpu request")
+)
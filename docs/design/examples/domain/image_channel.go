@@ -0,0 +1,158 @@
+// Package domain provides domain models.
+//
+// This file defines golden image channels and their promotion.
+//
+// Each image (e.g. "rhel9") has three channels, dev → staging → prod. A
+// channel points at one digest. The image pipeline records new builds on
+// dev; promoting copies the digest of a channel to the next one and needs
+// the approval of a second platform admin. Templates may reference a
+// channel instead of a fixed image source: the pointer is resolved when a
+// VM is created and the digest is pinned on the VM, so a later promotion
+// never changes existing VMs and reports show which VMs run which build.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ImageChannel is a stage of the promotion pipeline.
+type ImageChannel string
+
+const (
+	ImageChannelDev     ImageChannel = "dev"
+	ImageChannelStaging ImageChannel = "staging"
+	ImageChannelProd    ImageChannel = "prod"
+)
+
+// ImageChannels is the promotion order.
+var ImageChannels = []ImageChannel{ImageChannelDev, ImageChannelStaging, ImageChannelProd}
+
+// Previous returns the channel promoted from into c; false for dev and
+// unknown channels.
+func (c ImageChannel) Previous() (ImageChannel, bool) {
+	for i := 1; i < len(ImageChannels); i++ {
+		if ImageChannels[i] == c {
+			return ImageChannels[i-1], true
+		}
+	}
+	return "", false
+}
+
+// ImageChannelPointer is the digest a channel currently points at
+// (image_channels, one row per image and channel).
+type ImageChannelPointer struct {
+	Image      string       `json:"image"`
+	Channel    ImageChannel `json:"channel"`
+	Repository string       `json:"repository"` // e.g. registry.example.com/golden/rhel9
+	Digest     string       `json:"digest"`     // sha256:...
+	UpdatedBy  string       `json:"updated_by"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Source is the pinned containerDisk reference of the pointer.
+func (p *ImageChannelPointer) Source() string {
+	return p.Repository + "@" + p.Digest
+}
+
+// ImageBuild is a build recorded on the dev channel by the image pipeline.
+type ImageBuild struct {
+	Repository string `json:"repository"`
+	Digest     string `json:"digest"`
+}
+
+// Validate checks the build reference.
+func (b *ImageBuild) Validate() error {
+	if b.Repository == "" || strings.ContainsAny(b.Repository, "@ ") {
+		return fmt.Errorf("repository must be a reference without digest: %w", ErrInvalidImageBuild)
+	}
+	if !strings.HasPrefix(b.Digest, "sha256:") || len(b.Digest) != len("sha256:")+64 {
+		return fmt.Errorf("digest must be sha256:<64 hex>: %w", ErrInvalidImageBuild)
+	}
+	return nil
+}
+
+// PromotionStatus is the status of a promotion.
+type PromotionStatus string
+
+const (
+	PromotionPending    PromotionStatus = "PENDING"
+	PromotionApproved   PromotionStatus = "APPROVED"
+	PromotionRejected   PromotionStatus = "REJECTED"
+	PromotionSuperseded PromotionStatus = "SUPERSEDED" // Target channel moved before approval
+)
+
+// PromotionRequest is the body of a promotion request. The digest is the
+// one the previous channel points at when the request is made.
+type PromotionRequest struct {
+	Channel ImageChannel `json:"channel"` // Target: staging or prod
+	Reason  string       `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *PromotionRequest) Validate() error {
+	if _, ok := r.Channel.Previous(); !ok {
+		return fmt.Errorf("channel must be staging or prod: %w", ErrInvalidPromotion)
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// ImagePromotion moves a digest from one channel to the next.
+type ImagePromotion struct {
+	ID          string          `json:"id"`
+	Image       string          `json:"image"`
+	FromChannel ImageChannel    `json:"from_channel"`
+	ToChannel   ImageChannel    `json:"to_channel"`
+	Repository  string          `json:"repository"`
+	Digest      string          `json:"digest"`
+	Previous    string          `json:"previous_digest,omitempty"` // Target's digest at request time; empty if unset
+	Status      PromotionStatus `json:"status"`
+	Reason      string          `json:"reason"`
+	RequestedBy string          `json:"requested_by"`
+	RequestedAt time.Time       `json:"requested_at"`
+	DecidedBy   string          `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time      `json:"decided_at,omitempty"`
+}
+
+// CheckPromotionDecision enforces that only pending promotions are
+// decided, and never by their requester.
+func CheckPromotionDecision(p *ImagePromotion, decider string) error {
+	if p.Status != PromotionPending {
+		return fmt.Errorf("promotion is %s: %w", p.Status, ErrPromotionNotPending)
+	}
+	if decider == p.RequestedBy {
+		return ErrPromotionSelfApproval
+	}
+	return nil
+}
+
+// ImageDigestUsage is one row of the image report: how many VMs run a
+// digest, which channels point at it now, and its latest scans
+// (image_scan.go).
+type ImageDigestUsage struct {
+	Image    string              `json:"image"`
+	Digest   string              `json:"digest"`
+	VMCount  int                 `json:"vm_count"`
+	Channels []ImageChannel      `json:"channels"`        // Empty: no longer on any channel
+	Scans    []*ImageScanSummary `json:"scans,omitempty"` // Empty: never scanned
+}
+
+// Errors
+var (
+	ErrInvalidImageBuild     = domainerr.Invalid("INVALID_REQUEST", "invalid image build")
+	ErrInvalidPromotion      = domainerr.Invalid("INVALID_REQUEST", "invalid image promotion")
+	ErrImageChannelEmpty     = domainerr.Conflict("IMAGE_CHANNEL_EMPTY", "image channel has no digest yet")
+	ErrPromotionNoChange     = domainerr.Conflict("PROMOTION_NO_CHANGE", "target channel already points at this digest")
+	ErrPromotionPending      = domainerr.Conflict("PROMOTION_PENDING", "a promotion to this channel is already pending")
+	ErrPromotionNotPending   = domainerr.Conflict("PROMOTION_NOT_PENDING", "promotion is not pending")
+	ErrPromotionSelfApproval = domainerr.PermissionDenied("PROMOTION_SELF_APPROVAL", "promotion needs a second, different person")
+	ErrPromotionSuperseded   = domainerr.Conflict("PROMOTION_SUPERSEDED", "target channel changed since the promotion was requested")
+)
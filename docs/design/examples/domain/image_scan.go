@@ -0,0 +1,192 @@
+// Package domain provides domain models.
+//
+// This file defines the vulnerability feed: image scan results pushed by
+// a scanner (or a CVE feed bridge) and keyed by image digest.
+//
+// Each scan replaces the previous result of the same scanner for the
+// digest. A result at or above the policy's advisory threshold keeps one
+// open "vulnerable" advisory (compliance.go) on the digest, recorded by
+// the scanner, so catalog reports and the compliance report show the
+// affected images and VMs. A clean or less severe rescan withdraws it.
+// When a digest first reaches the notify or campaign threshold, the
+// owners of the affected Services are told and, if configured, a
+// remediation campaign (remediation.go) is started for it.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxScanFindings bounds the findings of one scan report.
+const MaxScanFindings = 5000
+
+// ScanFinding is one vulnerability found in an image.
+type ScanFinding struct {
+	ID       string           `json:"id"` // e.g. CVE-2026-1234
+	Severity AdvisorySeverity `json:"severity"`
+	Package  string           `json:"package"`
+	FixedIn  string           `json:"fixed_in,omitempty"` // Empty: no fix released yet
+}
+
+// ScanReport is the body of a scan webhook. The scanner is the signing
+// key's principal, never taken from the body.
+type ScanReport struct {
+	Digest    string        `json:"digest"`
+	ScannedAt time.Time     `json:"scanned_at"`
+	Findings  []ScanFinding `json:"findings"`
+}
+
+// Validate checks the report.
+func (r *ScanReport) Validate() error {
+	if !strings.HasPrefix(r.Digest, "sha256:") || len(r.Digest) != len("sha256:")+64 {
+		return fmt.Errorf("digest must be sha256:<64 hex>: %w", ErrInvalidScanReport)
+	}
+	if r.ScannedAt.IsZero() {
+		return fmt.Errorf("scanned_at is required: %w", ErrInvalidScanReport)
+	}
+	if len(r.Findings) > MaxScanFindings {
+		return fmt.Errorf("at most %d findings per report: %w", MaxScanFindings, ErrInvalidScanReport)
+	}
+	for _, f := range r.Findings {
+		if f.ID == "" || len(f.ID) > 64 {
+			return fmt.Errorf("finding id must be 1-64 characters: %w", ErrInvalidScanReport)
+		}
+		if f.Severity.Rank() == 0 {
+			return fmt.Errorf("finding %s: unknown severity %q: %w", f.ID, f.Severity, ErrInvalidScanReport)
+		}
+	}
+	return nil
+}
+
+// ImageScanSummary is the latest scan of a digest by one scanner
+// (image_scans), shown on catalog reports.
+type ImageScanSummary struct {
+	Digest      string                   `json:"digest"`
+	Scanner     string                   `json:"scanner"`
+	ScannedAt   time.Time                `json:"scanned_at"`
+	MaxSeverity AdvisorySeverity         `json:"max_severity,omitempty"` // Empty: no findings
+	Counts      map[AdvisorySeverity]int `json:"counts"`
+	Fixable     int                      `json:"fixable"` // Findings with a fixed version
+	TopFinding  string                   `json:"top_finding,omitempty"`
+	AdvisoryID  string                   `json:"advisory_id,omitempty"` // Advisory kept for this scan
+	ReceivedAt  time.Time                `json:"received_at"`
+
+	Findings []ScanFinding `json:"findings,omitempty"` // Only when fetched per digest
+}
+
+// Summarize counts the report's findings by severity. TopFinding is the
+// first of the most severe.
+func (r *ScanReport) Summarize(scanner string, receivedAt time.Time) *ImageScanSummary {
+	s := &ImageScanSummary{
+		Digest:     r.Digest,
+		Scanner:    scanner,
+		ScannedAt:  r.ScannedAt,
+		Counts:     make(map[AdvisorySeverity]int),
+		ReceivedAt: receivedAt,
+	}
+	for _, f := range r.Findings {
+		s.Counts[f.Severity]++
+		if f.FixedIn != "" {
+			s.Fixable++
+		}
+		if f.Severity.Rank() > s.MaxSeverity.Rank() {
+			s.MaxSeverity = f.Severity
+			s.TopFinding = f.ID
+		}
+	}
+	return s
+}
+
+// SameAdvisory reports whether s would keep the advisory recorded for
+// previous: same top finding and severity. Counts changing alone do not
+// replace it.
+func (s *ImageScanSummary) SameAdvisory(previous *ImageScanSummary) bool {
+	return previous != nil && previous.AdvisoryID != "" &&
+		previous.MaxSeverity == s.MaxSeverity && previous.TopFinding == s.TopFinding
+}
+
+// AdvisorySummary is the summary of the advisory kept for s, e.g.
+// "trivy: 2 critical, 5 high (4 fixable)".
+func (s *ImageScanSummary) AdvisorySummary() string {
+	var parts []string
+	for i := len(severityOrder) - 1; i >= 0; i-- {
+		if n := s.Counts[severityOrder[i]]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, severityOrder[i]))
+		}
+	}
+	return fmt.Sprintf("%s: %s (%d fixable)", s.Scanner, strings.Join(parts, ", "), s.Fixable)
+}
+
+var severityOrder = []AdvisorySeverity{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// VulnerabilityPolicy decides what a scan triggers
+// (governance.vulnerability_*). Each threshold applies at or above its
+// severity; an empty threshold disables its step.
+type VulnerabilityPolicy struct {
+	AdvisoryAt AdvisorySeverity `json:"advisory_at"` // Keep an open advisory on the digest
+	NotifyAt   AdvisorySeverity `json:"notify_at"`   // Tell the owners of affected Services
+	CampaignAt AdvisorySeverity `json:"campaign_at"` // Start a rebuild campaign
+
+	// CampaignConfirmation is how rebuilt VMs take over (owner or
+	// health_probe).
+	CampaignConfirmation ReplacementConfirmation `json:"campaign_confirmation"`
+}
+
+// Validate checks the thresholds: notifications and campaigns act on the
+// advisory, so they cannot be below its threshold.
+func (p VulnerabilityPolicy) Validate() error {
+	for _, s := range []AdvisorySeverity{p.AdvisoryAt, p.NotifyAt, p.CampaignAt} {
+		if s != "" && s.Rank() == 0 {
+			return fmt.Errorf("unknown severity %q: %w", s, ErrInvalidVulnerabilityPolicy)
+		}
+	}
+	if (p.NotifyAt != "" || p.CampaignAt != "") && p.AdvisoryAt == "" {
+		return fmt.Errorf("notify_at and campaign_at need advisory_at: %w", ErrInvalidVulnerabilityPolicy)
+	}
+	if p.NotifyAt != "" && p.NotifyAt.Rank() < p.AdvisoryAt.Rank() ||
+		p.CampaignAt != "" && p.CampaignAt.Rank() < p.AdvisoryAt.Rank() {
+		return fmt.Errorf("notify_at and campaign_at must be at or above advisory_at: %w", ErrInvalidVulnerabilityPolicy)
+	}
+	if p.CampaignAt != "" && p.CampaignConfirmation != ConfirmByOwner && p.CampaignConfirmation != ConfirmByHealthProbe {
+		return fmt.Errorf("campaign_at needs campaign_confirmation owner or health_probe: %w", ErrInvalidVulnerabilityPolicy)
+	}
+	return nil
+}
+
+// reaches reports whether severity is at or above threshold; never for an
+// empty threshold or severity.
+func reaches(severity, threshold AdvisorySeverity) bool {
+	return threshold != "" && severity != "" && severity.Rank() >= threshold.Rank()
+}
+
+// ScanEscalation is what a new scan triggers, given the severity of the
+// previous one: each step fires when the digest first reaches its
+// threshold, so repeated scans of an unchanged image stay quiet.
+type ScanEscalation struct {
+	Advisory bool // Keep an open advisory
+	Notify   bool
+	Campaign bool
+}
+
+// Escalate compares the new scan's severity with the previous one
+// (empty: first scan, or previous below every threshold).
+func (p VulnerabilityPolicy) Escalate(previous, current AdvisorySeverity) ScanEscalation {
+	return ScanEscalation{
+		Advisory: reaches(current, p.AdvisoryAt),
+		Notify:   reaches(current, p.NotifyAt) && !reaches(previous, p.NotifyAt),
+		Campaign: reaches(current, p.CampaignAt) && !reaches(previous, p.CampaignAt),
+	}
+}
+
+// Errors
+var (
+	ErrInvalidScanReport          = domainerr.Invalid("INVALID_REQUEST", "invalid image scan report")
+	ErrInvalidVulnerabilityPolicy = errors.New("invalid vulnerability policy")
+)
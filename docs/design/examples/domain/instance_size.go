@@ -8,8 +8,12 @@
 package domain
 
 import (
-	"errors"
+	"fmt"
 	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
 )
 
 // InstanceSize represents a predefined VM resource configuration (ADR-0018 Hybrid Model).
@@ -20,9 +24,11 @@ import (
 // - Core scheduling fields are stored in indexed columns for query performance
 // - spec_overrides stores remaining KubeVirt-specific configuration as JSONB
 // - Immutability: VMs snapshot InstanceSize at approval time (changes don't affect existing VMs)
+// - Versions: every edit writes an immutable InstanceSizeVersion (instance_size_version.go)
 type InstanceSize struct {
 	ID          string `json:"id"`
-	Name        string `json:"name"` // Globally unique, e.g., "small", "medium-gpu"
+	Name        string `json:"name"`    // Globally unique, e.g., "small", "medium-gpu"
+	Version     int    `json:"version"` // Current version (instance_sizes.current_version)
 	Description string `json:"description,omitempty"`
 
 	// ============================================================
@@ -43,6 +49,10 @@ type InstanceSize struct {
 	HugepagesSize     string `json:"hugepages_size,omitempty"` // e.g., "2Mi", "1Gi"
 	DedicatedCPU      bool   `json:"dedicated_cpu"`            // True if dedicatedCpuPlacement required
 
+	// GPUs are the GPU models and counts a VM of this size gets (gpu.go);
+	// requires RequiresGPU. Stored as JSONB, filterable by device name.
+	GPUs []GPUDevice `json:"gpus,omitempty"`
+
 	// Overcommit Configuration (ADR-0018 §481-486)
 	// Uses request/limit model, NOT ratio model
 	CPUOvercommit *OvercommitConfig `json:"cpu_overcommit,omitempty"`
@@ -57,6 +67,11 @@ type InstanceSize struct {
 	// Enabled flag for soft-delete
 	Enabled bool `json:"enabled"`
 
+	// PushToClusters publishes this size to every KubeVirt cluster as a
+	// native VirtualMachineClusterInstancetype named "shepherd-{name}".
+	// Off by default: the platform does not need native objects to work.
+	PushToClusters bool `json:"push_to_clusters"`
+
 	// Metadata
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -82,12 +97,48 @@ type OvercommitConfig struct {
 }
 
 // IsGuaranteedQoS returns true if this config results in Guaranteed QoS.
-// Guaranteed QoS requires: request == limit (or overcommit disabled)
+// Guaranteed QoS requires: request == limit (or overcommit disabled).
+// Values are compared as quantities, so "8Gi" equals "8192Mi"; a
+// malformed value is not Guaranteed (Validate rejects it).
 func (c *OvercommitConfig) IsGuaranteedQoS() bool {
 	if c == nil || !c.Enabled {
 		return true // No overcommit means Guaranteed QoS
 	}
-	return c.Request == c.Limit
+	request, err := parseQuantity(c.Request)
+	if err != nil {
+		return false
+	}
+	limit, err := parseQuantity(c.Limit)
+	if err != nil {
+		return false
+	}
+	return request.Cmp(limit) == 0
+}
+
+// Validate checks an enabled config against the size's advertised amount
+// (CPUCores or Memory): request and limit must be positive quantities,
+// request <= limit, and limit == advertised, since users see the limit
+// as the size (ADR-0018 §5). resourceName ("cpu", "memory") is used in
+// errors. A nil or disabled config is valid.
+func (c *OvercommitConfig) Validate(resourceName string, advertised resource.Quantity) error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	request, err := parseQuantity(c.Request)
+	if err != nil || request.Sign() <= 0 {
+		return fmt.Errorf("%s overcommit request %q: %w", resourceName, c.Request, ErrInvalidResourceQuantity)
+	}
+	limit, err := parseQuantity(c.Limit)
+	if err != nil || limit.Sign() <= 0 {
+		return fmt.Errorf("%s overcommit limit %q: %w", resourceName, c.Limit, ErrInvalidResourceQuantity)
+	}
+	if request.Cmp(limit) > 0 {
+		return fmt.Errorf("%s overcommit request %s exceeds limit %s: %w", resourceName, c.Request, c.Limit, ErrInvalidOvercommit)
+	}
+	if limit.Cmp(advertised) != 0 {
+		return fmt.Errorf("%s overcommit limit %s differs from the size's %s: %w", resourceName, c.Limit, advertised.String(), ErrInvalidOvercommit)
+	}
+	return nil
 }
 
 // ValidateWithDedicatedCPU checks if overcommit is compatible with dedicated CPU.
@@ -118,10 +169,13 @@ func ValidateWithDedicatedCPU(dedicatedCPU bool, cpuOvercommit, memOvercommit *O
 // does NOT affect existing VMs (ADR-0018 §Immutability).
 type InstanceSizeSnapshot struct {
 	Name        string `json:"name"`
+	Version     int    `json:"version"` // InstanceSizeVersion the snapshot was taken from
 	CPUCores    int    `json:"cpu_cores"`
 	Memory      string `json:"memory"`
 	RequiresGPU bool   `json:"requires_gpu,omitempty"`
 
+	GPUs []GPUDevice `json:"gpus,omitempty"`
+
 	// Final computed request/limit values (after overcommit applied)
 	FinalCPURequest string `json:"final_cpu_request"`
 	FinalCPULimit   string `json:"final_cpu_limit"`
@@ -134,40 +188,87 @@ type InstanceSizeSnapshot struct {
 	SnapshotAt time.Time `json:"snapshot_at"`
 }
 
+// ValidateResources checks CPUCores, Memory and both overcommit configs,
+// that dedicated CPU keeps Guaranteed QoS, and the GPUs.
+func (i *InstanceSize) ValidateResources() error {
+	if _, _, err := i.resources(); err != nil {
+		return err
+	}
+	return i.validateGPUs()
+}
+
+// resources returns the advertised CPU and memory as quantities after
+// validating the size's resources.
+func (i *InstanceSize) resources() (cpu, memory resource.Quantity, err error) {
+	if i.CPUCores < 1 {
+		return cpu, memory, fmt.Errorf("cpu_cores %d: %w", i.CPUCores, ErrInvalidResourceQuantity)
+	}
+	cpu = *resource.NewQuantity(int64(i.CPUCores), resource.DecimalSI)
+	if _, err := parseMemoryMB(i.Memory); err != nil {
+		return cpu, memory, fmt.Errorf("memory: %w", err)
+	}
+	memory = resource.MustParse(i.Memory) // Parsed above
+	if err := i.CPUOvercommit.Validate("cpu", cpu); err != nil {
+		return cpu, memory, err
+	}
+	if err := i.MemOvercommit.Validate("memory", memory); err != nil {
+		return cpu, memory, err
+	}
+	if err := ValidateWithDedicatedCPU(i.DedicatedCPU, i.CPUOvercommit, i.MemOvercommit); err != nil {
+		return cpu, memory, err
+	}
+	return cpu, memory, nil
+}
+
 // ToSnapshot creates an immutable snapshot of this InstanceSize.
-// The final request/limit values are computed based on overcommit settings.
-func (i *InstanceSize) ToSnapshot() *InstanceSizeSnapshot {
+// The final request/limit values are computed as quantities from the
+// overcommit settings and written in canonical form ("12", "500m",
+// "16Gi"). now is the snapshot time, from the caller's Clock. Fails if
+// the size's resources are invalid (ValidateResources).
+func (i *InstanceSize) ToSnapshot(now time.Time) (*InstanceSizeSnapshot, error) {
+	cpu, memory, err := i.resources()
+	if err != nil {
+		return nil, fmt.Errorf("instance size %s: %w", i.Name, err)
+	}
+
 	snapshot := &InstanceSizeSnapshot{
 		Name:          i.Name,
+		Version:       i.Version,
 		CPUCores:      i.CPUCores,
 		Memory:        i.Memory,
 		RequiresGPU:   i.RequiresGPU,
+		GPUs:          i.GPUs,
 		SpecOverrides: i.SpecOverrides,
-		SnapshotAt:    time.Now(),
+		SnapshotAt:    now,
 	}
+	snapshot.FinalCPURequest, snapshot.FinalCPULimit = finalRequestLimit(i.CPUOvercommit, cpu)
+	snapshot.FinalMemRequest, snapshot.FinalMemLimit = finalRequestLimit(i.MemOvercommit, memory)
+	return snapshot, nil
+}
 
-	// Compute final CPU request/limit
-	if i.CPUOvercommit != nil && i.CPUOvercommit.Enabled {
-		snapshot.FinalCPURequest = i.CPUOvercommit.Request
-		snapshot.FinalCPULimit = i.CPUOvercommit.Limit
-	} else {
-		// No overcommit: request == limit (Guaranteed QoS)
-		cpuStr := string(rune(i.CPUCores + '0')) // Simple int to string for example
-		snapshot.FinalCPURequest = cpuStr
-		snapshot.FinalCPULimit = cpuStr
+// finalRequestLimit returns the request and limit of one resource: the
+// overcommit values when enabled, otherwise advertised for both
+// (Guaranteed QoS). c must have passed Validate.
+func finalRequestLimit(c *OvercommitConfig, advertised resource.Quantity) (request, limit string) {
+	if c == nil || !c.Enabled {
+		return advertised.String(), advertised.String()
 	}
+	r := resource.MustParse(c.Request)
+	l := resource.MustParse(c.Limit)
+	return r.String(), l.String()
+}
 
-	// Compute final Memory request/limit
-	if i.MemOvercommit != nil && i.MemOvercommit.Enabled {
-		snapshot.FinalMemRequest = i.MemOvercommit.Request
-		snapshot.FinalMemLimit = i.MemOvercommit.Limit
-	} else {
-		// No overcommit: request == limit (Guaranteed QoS)
-		snapshot.FinalMemRequest = i.Memory
-		snapshot.FinalMemLimit = i.Memory
-	}
+// mebibyte is one MiB in bytes.
+const mebibyte = 1024 * 1024
 
-	return snapshot
+// parseQuantity parses a Kubernetes quantity, without panicking on
+// malformed input as resource.MustParse does.
+func parseQuantity(s string) (resource.Quantity, error) {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return q, fmt.Errorf("%q: %w", s, ErrInvalidResourceQuantity)
+	}
+	return q, nil
 }
 
 // Errors
@@ -176,5 +277,13 @@ var (
 	// ErrDedicatedCPURequiresGuaranteedQoS is returned when attempting to
 	// use overcommit with dedicated CPU placement.
 	// Per KubeVirt documentation, dedicatedCpuPlacement requires Guaranteed QoS.
-	ErrDedicatedCPURequiresGuaranteedQoS = errors.New("dedicated CPU requires Guaranteed QoS (request must equal limit)")
+	ErrDedicatedCPURequiresGuaranteedQoS = domainerr.Invalid("DEDICATED_CPU_REQUIRES_GUARANTEED_QOS", "dedicated CPU requires Guaranteed QoS (request must equal limit)")
+
+	// ErrInvalidResourceQuantity is returned for CPU or memory values that
+	// are not positive Kubernetes quantities.
+	ErrInvalidResourceQuantity = domainerr.Invalid("INVALID_RESOURCE_QUANTITY", "invalid resource quantity")
+
+	// ErrInvalidOvercommit is returned when an overcommit request exceeds
+	// its limit or the limit differs from the size's advertised amount.
+	ErrInvalidOvercommit = domainerr.Invalid("INVALID_OVERCOMMIT", "inconsistent overcommit configuration")
 )
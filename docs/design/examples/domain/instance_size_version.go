@@ -0,0 +1,207 @@
+// Package domain provides domain models.
+//
+// This file defines InstanceSize versions. An InstanceSize row is the
+// current version of the size; every edit writes a new immutable
+// InstanceSizeVersion and bumps InstanceSize.Version, so an edit never
+// changes what an earlier version meant:
+//
+//	instance_sizes "medium"   current_version 3
+//	instance_size_versions    (medium, 1) 2 vCPU / 4Gi
+//	                          (medium, 2) 4 vCPU / 8Gi
+//	                          (medium, 3) 4 vCPU / 8Gi, dedicated CPU
+//
+// Approval pins the version (ModifiedSpec.InstanceSizeVersion), the
+// creation worker records it on the VM (vms.instance_size_version,
+// AnnotationInstanceSizeVersion), and the outdated report lists VMs
+// running on a version older than the current one.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// InstanceSizeVersion is one immutable version of an InstanceSize
+// (instance_size_versions, primary key size_id + version). It holds
+// every field that shapes a VM; Description, Enabled and PushToClusters
+// are recorded too, so the history shows every edit.
+type InstanceSizeVersion struct {
+	SizeID  string `json:"size_id"`
+	Version int    `json:"version"` // 1 for the size as created
+	Name    string `json:"name"`
+
+	Description       string                 `json:"description,omitempty"`
+	CPUCores          int                    `json:"cpu_cores"`
+	Memory            string                 `json:"memory"`
+	RequiresGPU       bool                   `json:"requires_gpu"`
+	RequiresSRIOV     bool                   `json:"requires_sriov"`
+	RequiresHugepages bool                   `json:"requires_hugepages"`
+	HugepagesSize     string                 `json:"hugepages_size,omitempty"`
+	DedicatedCPU      bool                   `json:"dedicated_cpu"`
+	GPUs              []GPUDevice            `json:"gpus,omitempty"`
+	CPUOvercommit     *OvercommitConfig      `json:"cpu_overcommit,omitempty"`
+	MemOvercommit     *OvercommitConfig      `json:"mem_overcommit,omitempty"`
+	SpecOverrides     map[string]interface{} `json:"spec_overrides,omitempty"`
+	Enabled           bool                   `json:"enabled"`
+	PushToClusters    bool                   `json:"push_to_clusters"`
+
+	ChangedBy    string    `json:"changed_by"`
+	ChangeReason string    `json:"change_reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewInstanceSizeVersion records size as it is now, at size.Version.
+func NewInstanceSizeVersion(size *InstanceSize, changedBy, reason string, at time.Time) *InstanceSizeVersion {
+	return &InstanceSizeVersion{
+		SizeID:            size.ID,
+		Version:           size.Version,
+		Name:              size.Name,
+		Description:       size.Description,
+		CPUCores:          size.CPUCores,
+		Memory:            size.Memory,
+		RequiresGPU:       size.RequiresGPU,
+		RequiresSRIOV:     size.RequiresSRIOV,
+		RequiresHugepages: size.RequiresHugepages,
+		HugepagesSize:     size.HugepagesSize,
+		DedicatedCPU:      size.DedicatedCPU,
+		GPUs:              size.GPUs,
+		CPUOvercommit:     size.CPUOvercommit,
+		MemOvercommit:     size.MemOvercommit,
+		SpecOverrides:     size.SpecOverrides,
+		Enabled:           size.Enabled,
+		PushToClusters:    size.PushToClusters,
+		ChangedBy:         changedBy,
+		ChangeReason:      reason,
+		CreatedAt:         at,
+	}
+}
+
+// Size returns the InstanceSize as it was at this version. enabled is
+// the size's current flag: a disabled size cannot be picked at any version.
+func (v *InstanceSizeVersion) Size(enabled bool) *InstanceSize {
+	return &InstanceSize{
+		ID:                v.SizeID,
+		Version:           v.Version,
+		Name:              v.Name,
+		Description:       v.Description,
+		CPUCores:          v.CPUCores,
+		Memory:            v.Memory,
+		RequiresGPU:       v.RequiresGPU,
+		RequiresSRIOV:     v.RequiresSRIOV,
+		RequiresHugepages: v.RequiresHugepages,
+		HugepagesSize:     v.HugepagesSize,
+		DedicatedCPU:      v.DedicatedCPU,
+		GPUs:              v.GPUs,
+		CPUOvercommit:     v.CPUOvercommit,
+		MemOvercommit:     v.MemOvercommit,
+		SpecOverrides:     v.SpecOverrides,
+		Enabled:           enabled,
+		PushToClusters:    v.PushToClusters,
+		CreatedAt:         v.CreatedAt,
+		UpdatedAt:         v.CreatedAt,
+	}
+}
+
+// InstanceSizeEdit is the body of an InstanceSize update. It replaces
+// every editable field (PUT semantics); the name identifies the size and
+// its pushed instancetype and cannot change.
+//
+// ExpectedVersion is the version the admin edited: an edit based on an
+// older version fails instead of silently discarding the other change.
+type InstanceSizeEdit struct {
+	ExpectedVersion int    `json:"expected_version"`
+	Reason          string `json:"reason"`
+
+	Description       string                 `json:"description,omitempty"`
+	CPUCores          int                    `json:"cpu_cores"`
+	Memory            string                 `json:"memory"`
+	RequiresGPU       bool                   `json:"requires_gpu"`
+	RequiresSRIOV     bool                   `json:"requires_sriov"`
+	RequiresHugepages bool                   `json:"requires_hugepages"`
+	HugepagesSize     string                 `json:"hugepages_size,omitempty"`
+	DedicatedCPU      bool                   `json:"dedicated_cpu"`
+	GPUs              []GPUDevice            `json:"gpus,omitempty"`
+	CPUOvercommit     *OvercommitConfig      `json:"cpu_overcommit,omitempty"`
+	MemOvercommit     *OvercommitConfig      `json:"mem_overcommit,omitempty"`
+	SpecOverrides     map[string]interface{} `json:"spec_overrides,omitempty"`
+	Enabled           bool                   `json:"enabled"`
+	PushToClusters    bool                   `json:"push_to_clusters"`
+}
+
+// Apply returns current with the edit applied, at the next version,
+// after checking its resources (InstanceSize.ValidateResources).
+// current is not changed.
+func (e *InstanceSizeEdit) Apply(current *InstanceSize, at time.Time) (*InstanceSize, error) {
+	if e.ExpectedVersion != current.Version {
+		return nil, fmt.Errorf("instance size %s is at version %d, edit is based on %d: %w",
+			current.Name, current.Version, e.ExpectedVersion, ErrInstanceSizeVersionConflict)
+	}
+	if e.Reason == "" || len(e.Reason) > 500 {
+		return nil, fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidInstanceSize)
+	}
+
+	next := *current
+	next.Version = current.Version + 1
+	next.Description = e.Description
+	next.CPUCores = e.CPUCores
+	next.Memory = e.Memory
+	next.RequiresGPU = e.RequiresGPU
+	next.RequiresSRIOV = e.RequiresSRIOV
+	next.RequiresHugepages = e.RequiresHugepages
+	next.HugepagesSize = e.HugepagesSize
+	next.DedicatedCPU = e.DedicatedCPU
+	next.GPUs = e.GPUs
+	next.CPUOvercommit = e.CPUOvercommit
+	next.MemOvercommit = e.MemOvercommit
+	next.SpecOverrides = e.SpecOverrides
+	next.Enabled = e.Enabled
+	next.PushToClusters = e.PushToClusters
+	next.UpdatedAt = at
+	if err := next.ValidateResources(); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// OutdatedSizeVM is a VM created from an older version of its
+// InstanceSize than the current one. Its resources are those of the
+// pinned version; the current ones are shown for comparison.
+type OutdatedSizeVM struct {
+	VMID      string `json:"vm_id"`
+	VMName    string `json:"vm_name"`
+	Namespace string `json:"namespace"`
+	ServiceID string `json:"service_id"`
+	Cluster   string `json:"cluster"`
+
+	SizeID         string `json:"size_id"`
+	SizeName       string `json:"size_name"`
+	PinnedVersion  int    `json:"pinned_version"`
+	CurrentVersion int    `json:"current_version"`
+
+	PinnedCPUCores  int    `json:"pinned_cpu_cores"`
+	PinnedMemory    string `json:"pinned_memory"`
+	CurrentCPUCores int    `json:"current_cpu_cores"`
+	CurrentMemory   string `json:"current_memory"`
+}
+
+// VersionsBehind returns how many edits the VM's size has had since the VM
+// was created.
+func (o *OutdatedSizeVM) VersionsBehind() int {
+	return o.CurrentVersion - o.PinnedVersion
+}
+
+// ResourcesChanged reports whether the edits since the pinned version
+// changed CPU or memory, rather than only other settings.
+func (o *OutdatedSizeVM) ResourcesChanged() bool {
+	return o.PinnedCPUCores != o.CurrentCPUCores || o.PinnedMemory != o.CurrentMemory
+}
+
+// Errors
+var (
+	ErrInvalidInstanceSize         = domainerr.Invalid("INVALID_INSTANCE_SIZE", "invalid instance size")
+	ErrInstanceSizeVersionConflict = domainerr.Conflict("INSTANCE_SIZE_VERSION_CONFLICT", "instance size was changed since the edited version")
+)
@@ -0,0 +1,129 @@
+// Package domain provides domain models.
+//
+// This file maps the InstanceSize catalog to native KubeVirt instancetypes
+// (cluster-scoped VirtualMachineClusterInstancetype) and plans the sync.
+//
+// Only objects carrying the managed-by label are ever updated or deleted;
+// admin-created instancetypes in the same cluster are left alone.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// InstanceTypeNamePrefix avoids collisions with admin-created instancetypes.
+const InstanceTypeNamePrefix = "shepherd-"
+
+// InstanceTypeFromSize builds the desired cluster-scoped instancetype for a size.
+func InstanceTypeFromSize(size *InstanceSize) (*InstanceType, error) {
+	memoryMB, err := parseMemoryMB(size.Memory)
+	if err != nil {
+		return nil, fmt.Errorf("instance size %s: %w", size.Name, err)
+	}
+
+	it := &InstanceType{
+		Name:          InstanceTypeNamePrefix + size.Name,
+		CPU:           size.CPUCores,
+		MemoryMB:      memoryMB,
+		DedicatedCPU:  size.DedicatedCPU,
+		HugepagesSize: size.HugepagesSize,
+		GPUs:          size.GPUs,
+		Labels: map[string]string{
+			LabelManagedBy: ManagedByValue,
+		},
+		Annotations: map[string]string{
+			AnnotationInstanceSizeID: size.ID,
+		},
+	}
+	it.Annotations[AnnotationSpecHash] = instanceTypeHash(it)
+	return it, nil
+}
+
+// InstanceTypeSyncPlan lists the changes needed to make a cluster match the catalog.
+type InstanceTypeSyncPlan struct {
+	Create []*InstanceType
+	Update []*InstanceType
+	Delete []string // Names
+}
+
+// IsEmpty reports whether the cluster is already in sync.
+func (p *InstanceTypeSyncPlan) IsEmpty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// PlanInstanceTypeSync diffs desired instancetypes against those in a cluster.
+// Unmanaged actual objects are ignored; a name clash with one is reported
+// as an error rather than overwritten.
+func PlanInstanceTypeSync(desired, actual []*InstanceType) (*InstanceTypeSyncPlan, error) {
+	current := make(map[string]*InstanceType, len(actual))
+	for _, it := range actual {
+		current[it.Name] = it
+	}
+
+	plan := &InstanceTypeSyncPlan{}
+	wanted := make(map[string]bool, len(desired))
+	for _, it := range desired {
+		wanted[it.Name] = true
+		existing, ok := current[it.Name]
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, it)
+		case existing.Labels[LabelManagedBy] != ManagedByValue:
+			return nil, fmt.Errorf("instancetype %s: %w", it.Name, ErrInstanceTypeNotManaged)
+		case existing.Annotations[AnnotationSpecHash] != it.Annotations[AnnotationSpecHash]:
+			plan.Update = append(plan.Update, it)
+		}
+	}
+
+	for _, it := range actual {
+		if it.Labels[LabelManagedBy] == ManagedByValue && !wanted[it.Name] {
+			plan.Delete = append(plan.Delete, it.Name)
+		}
+	}
+	sort.Strings(plan.Delete)
+
+	return plan, nil
+}
+
+// instanceTypeHash hashes the spec fields only (not metadata).
+func instanceTypeHash(it *InstanceType) string {
+	data, _ := json.Marshal(struct {
+		CPU           int         `json:"cpu"`
+		MemoryMB      int         `json:"memory_mb"`
+		DedicatedCPU  bool        `json:"dedicated_cpu"`
+		HugepagesSize string      `json:"hugepages_size"`
+		GPUs          []GPUDevice `json:"gpus,omitempty"` // Omitted when none: hashes of sizes without GPUs are unchanged
+	}{it.CPU, it.MemoryMB, it.DedicatedCPU, it.HugepagesSize, it.GPUs})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// parseMemoryMB converts a memory quantity ("512Mi", "16Gi", "1Ti",
+// "17179869184") to MiB. It must be a positive whole number of MiB.
+func parseMemoryMB(memory string) (int, error) {
+	q, err := parseQuantity(memory)
+	if err != nil || q.Sign() <= 0 || q.Value()%mebibyte != 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMemory, memory)
+	}
+	return int(q.Value() / mebibyte), nil
+}
+
+// Errors
+
+var (
+	// ErrInstanceTypeNotManaged is returned when a pushed name clashes with
+	// an instancetype the platform does not own.
+	ErrInstanceTypeNotManaged = errors.New("instancetype exists and is not platform-managed")
+
+	// ErrInvalidMemory is returned for memory values that are not a whole number of MiB.
+	ErrInvalidMemory = domainerr.Invalid("INVALID_RESOURCE_QUANTITY", "memory must be a positive whole number of MiB, e.g. 512Mi or 16Gi")
+)
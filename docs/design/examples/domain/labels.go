@@ -0,0 +1,58 @@
+// Package domain provides domain models.
+//
+// This file defines the platform-managed K8s labels (ADR-0015 §4, Phase 1 §Labels).
+// Users can NOT set these labels; they are written by the worker at creation time.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+// PlatformKeyPrefix prefixes every platform-managed label and annotation
+// key; templates and System metadata cannot set keys under it.
+const PlatformKeyPrefix = "kubevirt-shepherd.io/"
+
+// Platform-managed labels on KubeVirt objects.
+const (
+	LabelManagedBy = "kubevirt-shepherd.io/managed-by"
+	LabelSystem    = "kubevirt-shepherd.io/system"
+	LabelService   = "kubevirt-shepherd.io/service"
+	LabelInstance  = "kubevirt-shepherd.io/instance"
+	LabelTicketID  = "kubevirt-shepherd.io/ticket-id"
+	LabelCreatedBy = "kubevirt-shepherd.io/created-by"
+	LabelHostname  = "kubevirt-shepherd.io/hostname"
+	LabelBatch     = "kubevirt-shepherd.io/batch" // Batch ticket ID, node anti-affinity only
+
+	// ManagedByValue is the value of LabelManagedBy on platform-owned objects.
+	ManagedByValue = "kubevirt-shepherd"
+)
+
+// Platform-managed annotations on KubeVirt objects.
+const (
+	// AnnotationInstanceSizeID links a pushed instancetype or a VM to its InstanceSize.
+	AnnotationInstanceSizeID = "kubevirt-shepherd.io/instance-size-id"
+
+	// AnnotationInstanceSizeVersion is the InstanceSize version a VM was
+	// created from (instance_size_version.go). Set at creation, never updated.
+	AnnotationInstanceSizeVersion = "kubevirt-shepherd.io/instance-size-version"
+
+	// AnnotationSpecHash is the hash of the desired spec, used to detect drift.
+	AnnotationSpecHash = "kubevirt-shepherd.io/spec-hash"
+
+	// AnnotationFQDN is the VM's DNS name (ADR-0015 §16.4). Moves with
+	// LabelHostname to a blue/green replacement (vm_replacement.go).
+	AnnotationFQDN = "kubevirt-shepherd.io/fqdn"
+
+	// AnnotationImageDigest is the image digest the VM was built from
+	// (image_channel.go). Set at creation, never updated.
+	AnnotationImageDigest = "kubevirt-shepherd.io/image-digest"
+)
+
+// KubeVirt well-known labels read (never written) by the platform.
+const (
+	// LabelKubeVirtNodeName is set by KubeVirt on VMIs to the node they run on.
+	LabelKubeVirtNodeName = "kubevirt.io/nodeName"
+)
+
+// ManagedSelector returns the label selector matching platform-owned objects.
+func ManagedSelector() string {
+	return LabelManagedBy + "=" + ManagedByValue
+}
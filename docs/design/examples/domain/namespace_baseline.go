@@ -0,0 +1,189 @@
+// Package domain provides domain models.
+//
+// This file defines namespace baselines: the NetworkPolicies,
+// ResourceQuotas and LimitRanges every Shepherd namespace gets on a
+// cluster, one baseline per namespace environment (test/prod, ADR-0015
+// §15).
+//
+// Bodies are the object's spec as YAML and may use the sandboxed
+// ${shepherd.*} variables (internal/render), e.g. ${shepherd.vm.namespace}
+// in a NetworkPolicy selector. The baseline is applied when a namespace is
+// provisioned on a cluster (ADR-0017 JIT creation) and kept in place by a
+// periodic sync: objects are governed like pushed instancetypes, so an
+// edited or deleted object is restored and the drift is audited.
+//
+// Drift is judged on the fields the baseline sets only: the live spec is
+// projected onto the desired one (ProjectSpec) before hashing, so defaults
+// filled in by the API server never count as drift.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// BaselineKind is a kind of object a baseline may contain.
+type BaselineKind string
+
+const (
+	BaselineNetworkPolicy BaselineKind = "NetworkPolicy"
+	BaselineResourceQuota BaselineKind = "ResourceQuota"
+	BaselineLimitRange    BaselineKind = "LimitRange"
+)
+
+// MaxBaselineObjects bounds the objects of one baseline.
+const MaxBaselineObjects = 20
+
+var baselineObjectName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// NamespaceBaselineObject is one object of a baseline.
+type NamespaceBaselineObject struct {
+	Kind BaselineKind `json:"kind"`
+	Name string       `json:"name"` // e.g. shepherd-default-deny
+	Spec string       `json:"spec"` // YAML, ${shepherd.*} substituted per namespace
+}
+
+// NamespaceBaseline is the baseline of one namespace environment
+// (namespace_baselines). Version increases with every change.
+type NamespaceBaseline struct {
+	Environment string                    `json:"environment"` // test or prod
+	Objects     []NamespaceBaselineObject `json:"objects"`
+	Version     int                       `json:"version"`
+	UpdatedBy   string                    `json:"updated_by"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// Validate checks the baseline's shape; bodies are parsed when rendered.
+func (b *NamespaceBaseline) Validate() error {
+	if b.Environment != "test" && b.Environment != "prod" {
+		return fmt.Errorf("environment must be test or prod: %w", ErrInvalidNamespaceBaseline)
+	}
+	if len(b.Objects) > MaxBaselineObjects {
+		return fmt.Errorf("at most %d objects: %w", MaxBaselineObjects, ErrInvalidNamespaceBaseline)
+	}
+	seen := make(map[string]bool, len(b.Objects))
+	for _, o := range b.Objects {
+		switch o.Kind {
+		case BaselineNetworkPolicy, BaselineResourceQuota, BaselineLimitRange:
+		default:
+			return fmt.Errorf("unsupported kind %q: %w", o.Kind, ErrInvalidNamespaceBaseline)
+		}
+		if !baselineObjectName.MatchString(o.Name) {
+			return fmt.Errorf("%s name %q must be a DNS label: %w", o.Kind, o.Name, ErrInvalidNamespaceBaseline)
+		}
+		key := string(o.Kind) + "/" + o.Name
+		if seen[key] {
+			return fmt.Errorf("duplicate %s: %w", key, ErrInvalidNamespaceBaseline)
+		}
+		seen[key] = true
+		if o.Spec == "" {
+			return fmt.Errorf("%s: spec is required: %w", key, ErrInvalidNamespaceBaseline)
+		}
+	}
+	return nil
+}
+
+// GovernedObject is a baseline object in one namespace of a cluster:
+// desired when rendered from a baseline, actual when listed by the
+// provider.
+type GovernedObject struct {
+	Kind        BaselineKind           `json:"kind"`
+	Namespace   string                 `json:"namespace"`
+	Name        string                 `json:"name"`
+	Spec        map[string]interface{} `json:"spec"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"` // AnnotationSpecHash: hash applied
+}
+
+// Key identifies the object within its namespace.
+func (o *GovernedObject) Key() string {
+	return string(o.Kind) + "/" + o.Name
+}
+
+// SpecHash hashes a spec. encoding/json sorts map keys, so equal specs
+// hash equally.
+func SpecHash(spec map[string]interface{}) string {
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ProjectSpec keeps the parts of live that desired sets: mappings are
+// walked key by key, anything else (scalars, lists) is kept whole.
+func ProjectSpec(live, desired map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(desired))
+	for k, want := range desired {
+		got, ok := live[k]
+		if !ok {
+			continue
+		}
+		wantMap, wantIsMap := want.(map[string]interface{})
+		gotMap, gotIsMap := got.(map[string]interface{})
+		if wantIsMap && gotIsMap {
+			out[k] = ProjectSpec(gotMap, wantMap)
+			continue
+		}
+		out[k] = got
+	}
+	return out
+}
+
+// BaselineSyncPlan is what a namespace needs to match its baseline.
+type BaselineSyncPlan struct {
+	Apply   []*GovernedObject // Missing, outdated or drifted
+	Delete  []*GovernedObject // Managed but no longer in the baseline
+	Drifted []string          // Keys changed on the cluster since applied
+}
+
+// IsEmpty reports whether the namespace matches its baseline.
+func (p *BaselineSyncPlan) IsEmpty() bool {
+	return len(p.Apply) == 0 && len(p.Delete) == 0
+}
+
+// PlanBaselineSync diffs desired objects against the managed ones in the
+// namespace. An object is drifted when its live spec, projected onto the
+// desired one, no longer hashes to the hash recorded when it was applied;
+// it is outdated when the baseline changed since.
+func PlanBaselineSync(desired, actual []*GovernedObject) *BaselineSyncPlan {
+	current := make(map[string]*GovernedObject, len(actual))
+	for _, o := range actual {
+		current[o.Key()] = o
+	}
+
+	plan := &BaselineSyncPlan{}
+	wanted := make(map[string]bool, len(desired))
+	for _, o := range desired {
+		wanted[o.Key()] = true
+		existing, ok := current[o.Key()]
+		switch {
+		case !ok:
+			plan.Apply = append(plan.Apply, o)
+		case SpecHash(ProjectSpec(existing.Spec, o.Spec)) != existing.Annotations[AnnotationSpecHash]:
+			plan.Drifted = append(plan.Drifted, o.Key())
+			plan.Apply = append(plan.Apply, o)
+		case existing.Annotations[AnnotationSpecHash] != o.Annotations[AnnotationSpecHash]:
+			plan.Apply = append(plan.Apply, o)
+		}
+	}
+	for _, o := range actual {
+		if !wanted[o.Key()] {
+			plan.Delete = append(plan.Delete, o)
+		}
+	}
+	sort.Strings(plan.Drifted)
+	return plan
+}
+
+// Errors
+var (
+	ErrInvalidNamespaceBaseline = domainerr.Invalid("INVALID_NAMESPACE_BASELINE", "invalid namespace baseline")
+)
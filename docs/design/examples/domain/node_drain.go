@@ -0,0 +1,194 @@
+// Package domain provides domain models.
+//
+// This file defines node drain coordination for platform-managed VMs.
+// A drain moves every managed VM off a node: live migration where the VMI
+// reports LiveMigratable, otherwise a stop/start inside the maintenance window.
+//
+// Execution follows the batch model (ADR-0015 §19): one parent record,
+// one child DomainEvent per VM, independent execution per item.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// DrainAction is the action planned for a single VM.
+type DrainAction string
+
+const (
+	DrainActionLiveMigrate DrainAction = "LIVE_MIGRATE" // Zero downtime
+	DrainActionStopStart   DrainAction = "STOP_START"   // Downtime, runs inside maintenance window
+)
+
+// EventType returns the type of the item event carrying the action.
+func (a DrainAction) EventType() EventType {
+	if a == DrainActionStopStart {
+		return EventNodeDrainItemRestartRequested
+	}
+	return EventNodeDrainItemMigrationRequested
+}
+
+// NodeDrainStatus is the status of a drain (parent record).
+type NodeDrainStatus string
+
+const (
+	NodeDrainStatusInProgress     NodeDrainStatus = "IN_PROGRESS"
+	NodeDrainStatusCompleted      NodeDrainStatus = "COMPLETED"
+	NodeDrainStatusPartialSuccess NodeDrainStatus = "PARTIAL_SUCCESS"
+	NodeDrainStatusFailed         NodeDrainStatus = "FAILED"
+)
+
+// NodeDrainItemStatus is the status of a single VM in a drain.
+type NodeDrainItemStatus string
+
+const (
+	NodeDrainItemScheduled NodeDrainItemStatus = "SCHEDULED" // Waiting for maintenance window
+	NodeDrainItemPending   NodeDrainItemStatus = "PENDING"   // Job enqueued
+	NodeDrainItemRunning   NodeDrainItemStatus = "RUNNING"
+	NodeDrainItemCompleted NodeDrainItemStatus = "COMPLETED"
+	NodeDrainItemFailed    NodeDrainItemStatus = "FAILED"
+)
+
+// MaintenanceWindow is the period in which disruptive actions may run.
+type MaintenanceWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Validate checks the window is well-formed.
+func (w *MaintenanceWindow) Validate() error {
+	if w == nil {
+		return nil
+	}
+	if !w.End.After(w.Start) {
+		return ErrInvalidMaintenanceWindow
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the window.
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// ScheduleAt returns when a disruptive action should run.
+// Zero time means "run now" (no window, or window already open).
+func (w *MaintenanceWindow) ScheduleAt(now time.Time) time.Time {
+	if w == nil || w.Contains(now) {
+		return time.Time{}
+	}
+	return w.Start
+}
+
+// NodeDrain is the parent record of a drain operation.
+type NodeDrain struct {
+	ID          string             `json:"id"`
+	EventID     string             `json:"event_id"` // NODE_DRAIN_REQUESTED event
+	Cluster     string             `json:"cluster"`
+	NodeName    string             `json:"node_name"`
+	Window      *MaintenanceWindow `json:"window,omitempty"`
+	Reason      string             `json:"reason"`
+	RequestedBy string             `json:"requested_by"`
+
+	Status         NodeDrainStatus `json:"status"`
+	TotalCount     int             `json:"total_count"`
+	MigratedCount  int             `json:"migrated_count"`
+	RestartedCount int             `json:"restarted_count"`
+	FailedCount    int             `json:"failed_count"`
+	PendingCount   int             `json:"pending_count"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CalculateStatus derives the parent status from item counters.
+// Same rules as BatchApprovalTicket.CalculateStatus (ADR-0015 §19).
+func (d *NodeDrain) CalculateStatus() NodeDrainStatus {
+	if d.PendingCount > 0 {
+		return NodeDrainStatusInProgress
+	}
+	if d.FailedCount == 0 {
+		return NodeDrainStatusCompleted
+	}
+	if d.MigratedCount+d.RestartedCount == 0 {
+		return NodeDrainStatusFailed
+	}
+	return NodeDrainStatusPartialSuccess
+}
+
+// NodeDrainItem tracks one VM in a drain.
+type NodeDrainItem struct {
+	ID            string              `json:"id"`
+	DrainID       string              `json:"drain_id"`
+	EventID       string              `json:"event_id"` // Child event executed by River
+	VMName        string              `json:"vm_name"`
+	Namespace     string              `json:"namespace"`
+	ServiceID     string              `json:"service_id"`
+	Action        DrainAction         `json:"action"`
+	Status        NodeDrainItemStatus `json:"status"`
+	ScheduledAt   *time.Time          `json:"scheduled_at,omitempty"`
+	MigrationName string              `json:"migration_name,omitempty"` // VirtualMachineInstanceMigration, LIVE_MIGRATE only
+	ErrorMessage  string              `json:"error_message,omitempty"`
+}
+
+// PlanDrainAction picks the least disruptive action for a VM.
+func PlanDrainAction(vm *VM) DrainAction {
+	if vm.LiveMigratable {
+		return DrainActionLiveMigrate
+	}
+	return DrainActionStopStart
+}
+
+// NodeDrainPayload is the payload of NODE_DRAIN_REQUESTED (parent event).
+type NodeDrainPayload struct {
+	DrainID  string             `json:"drain_id"`
+	Cluster  string             `json:"cluster"`
+	NodeName string             `json:"node_name"`
+	Window   *MaintenanceWindow `json:"window,omitempty"`
+	Reason   string             `json:"reason"`
+	VMCount  int                `json:"vm_count"`
+}
+
+// Validate requires the drain and its node.
+func (p NodeDrainPayload) Validate() error {
+	return requireFields("drain_id", p.DrainID, "cluster", p.Cluster, "node_name", p.NodeName)
+}
+
+// NodeDrainItemPayload is the payload of a child event: NODE_DRAIN_ITEM_MIGRATION_REQUESTED
+// (live) or NODE_DRAIN_ITEM_RESTART_REQUESTED (stop/start), see DrainAction.EventType.
+type NodeDrainItemPayload struct {
+	DrainID   string      `json:"drain_id"`
+	ItemID    string      `json:"item_id"`
+	Cluster   string      `json:"cluster"`
+	Namespace string      `json:"namespace"`
+	VMName    string      `json:"vm_name"`
+	Action    DrainAction `json:"action"`
+}
+
+// Validate requires the drain item, its VM and a known action.
+func (p NodeDrainItemPayload) Validate() error {
+	if err := requireFields("drain_id", p.DrainID, "item_id", p.ItemID, "cluster", p.Cluster,
+		"namespace", p.Namespace, "vm_name", p.VMName); err != nil {
+		return err
+	}
+	if p.Action != DrainActionLiveMigrate && p.Action != DrainActionStopStart {
+		return fmt.Errorf("unknown action %q: %w", p.Action, ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+// Errors
+
+var (
+	// ErrInvalidMaintenanceWindow is returned when End is not after Start.
+	ErrInvalidMaintenanceWindow = domainerr.Invalid("INVALID_MAINTENANCE_WINDOW", "maintenance window end must be after start")
+
+	// ErrStopStartOutsideWindow is returned when non-migratable VMs exist
+	// but no maintenance window was given and Force is not set.
+	ErrStopStartOutsideWindow = domainerr.Unprocessable("MAINTENANCE_WINDOW_REQUIRED", "node has VMs that cannot live-migrate; a maintenance window is required")
+)
@@ -0,0 +1,98 @@
+// Package domain provides domain models.
+//
+// This file defines the notification model per ADR-0015 §20.
+// V1 delivers to the platform-internal inbox; the sender interface is
+// decoupled so Email/Webhook/Slack senders can be added later.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationType defines the type of notification (ADR-0015 §20).
+type NotificationType string
+
+const (
+	NotificationApprovalRequired NotificationType = "APPROVAL_REQUIRED"
+	NotificationRequestApproved  NotificationType = "REQUEST_APPROVED"
+	NotificationRequestRejected  NotificationType = "REQUEST_REJECTED"
+	NotificationApprovalOverdue  NotificationType = "APPROVAL_OVERDUE" // SLA escalation
+	NotificationTicketComment    NotificationType = "TICKET_COMMENT"
+	NotificationRequestExpired   NotificationType = "REQUEST_EXPIRED"
+	NotificationVMCreated        NotificationType = "VM_CREATED"
+	NotificationVMDeleted        NotificationType = "VM_DELETED"
+
+	// Node maintenance
+	NotificationNodeDrainScheduled NotificationType = "NODE_DRAIN_SCHEDULED"
+	NotificationNodeDrainCompleted NotificationType = "NODE_DRAIN_COMPLETED"
+
+	// Incident containment
+	NotificationEmergencyStopStarted   NotificationType = "EMERGENCY_STOP_STARTED"
+	NotificationEmergencyStopCompleted NotificationType = "EMERGENCY_STOP_COMPLETED"
+
+	// Blue/green replacement (vm_replacement.go), to the requester
+	NotificationReplacementReady    NotificationType = "VM_REPLACEMENT_READY" // Awaiting confirmation
+	NotificationReplacementFinished NotificationType = "VM_REPLACEMENT_FINISHED"
+
+	// Power operations (vm_power.go), to the requester
+	NotificationPowerOperationFinished NotificationType = "VM_POWER_OPERATION_FINISHED"
+
+	// Snapshots (vm_snapshot.go), to the requester
+	NotificationSnapshotFinished NotificationType = "VM_SNAPSHOT_FINISHED"
+
+	// Backups (vm_backup.go), to the requester
+	NotificationBackupFinished NotificationType = "VM_BACKUP_FINISHED"
+
+	// Restores (vm_restore.go), to the requester
+	NotificationRestoreFinished NotificationType = "VM_RESTORE_FINISHED"
+
+	// Clones (vm_clone.go), to the requester
+	NotificationCloneFinished NotificationType = "VM_CLONE_FINISHED"
+
+	// Admin-initiated migrations (vm_migration.go), to the requesting admin
+	NotificationMigrationFinished NotificationType = "VM_MIGRATION_FINISHED"
+
+	// Cross-cluster relocations (vm_relocation.go), to the requesting
+	// admin; decommission items report through the decommission
+	NotificationRelocationReady    NotificationType = "VM_RELOCATION_READY" // Awaiting cutover
+	NotificationRelocationFinished NotificationType = "VM_RELOCATION_FINISHED"
+
+	// VM leases (vm_lease.go): expiry to the Service owners, renewal to
+	// the requester
+	NotificationLeaseExpiring NotificationType = "VM_LEASE_EXPIRING"
+	NotificationLeaseExpired  NotificationType = "VM_LEASE_EXPIRED"
+	NotificationLeaseRenewed  NotificationType = "VM_LEASE_RENEWED"
+
+	// Vulnerability feed (image_scan.go), to the owners of affected
+	// Services
+	NotificationImageVulnerable NotificationType = "IMAGE_VULNERABLE"
+)
+
+// Notification is a single inbox entry.
+type Notification struct {
+	ID              string           `json:"id"`
+	Recipient       string           `json:"recipient"` // Username
+	Type            NotificationType `json:"type"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	RelatedTicketID string           `json:"related_ticket_id,omitempty"`
+	Priority        Priority         `json:"priority,omitempty"` // Ticket priority; emergency is sent immediately, bypassing digests
+	Read            bool             `json:"read"`
+	CreatedAt       time.Time        `json:"created_at"`
+	ReadAt          *time.Time       `json:"read_at,omitempty"`
+}
+
+// NotificationSender delivers notifications.
+//
+// V1: InboxNotificationSender (database)
+// Future: EmailNotificationSender, WebhookNotificationSender, SlackNotificationSender
+//
+// An EmailNotificationSender embeds action links (see action_link.go) in
+// APPROVAL_REQUIRED mails, issued per recipient.
+type NotificationSender interface {
+	Send(ctx context.Context, notification *Notification) error
+	SendBatch(ctx context.Context, notifications []*Notification) error
+}
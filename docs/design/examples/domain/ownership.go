@@ -0,0 +1,89 @@
+// Package domain provides domain models.
+//
+// This file defines VM ownership metadata written onto KubeVirt objects.
+//
+// Labels carry names for selection (Phase 1 §2); ownership annotations carry
+// the immutable IDs Shepherd recorded at creation. Every mutating provider
+// call compares them with the database record, so Shepherd refuses to touch
+// an object that merely has the expected name: a VM created directly on the
+// cluster, by another Shepherd installation, or for a different ticket.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Ownership annotations (written at creation, never updated).
+const (
+	AnnotationInstallationID = "kubevirt-shepherd.io/installation-id"
+	AnnotationOwnerSystemID  = "kubevirt-shepherd.io/owner-system-id"
+	AnnotationOwnerServiceID = "kubevirt-shepherd.io/owner-service-id"
+	AnnotationRequestedBy    = "kubevirt-shepherd.io/requested-by"
+	AnnotationOwnerTicketID  = "kubevirt-shepherd.io/owner-ticket-id"
+)
+
+// Ownership identifies the Shepherd record that owns a cluster object.
+type Ownership struct {
+	InstallationID string `json:"installation_id"` // Distinguishes Shepherd instances sharing a cluster
+	SystemID       string `json:"system_id"`
+	ServiceID      string `json:"service_id"`
+	RequestedBy    string `json:"requested_by"`
+	TicketID       string `json:"ticket_id"`
+}
+
+// Annotations returns the ownership annotations to write at creation.
+func (o *Ownership) Annotations() map[string]string {
+	return map[string]string{
+		AnnotationInstallationID: o.InstallationID,
+		AnnotationOwnerSystemID:  o.SystemID,
+		AnnotationOwnerServiceID: o.ServiceID,
+		AnnotationRequestedBy:    o.RequestedBy,
+		AnnotationOwnerTicketID:  o.TicketID,
+	}
+}
+
+// OwnershipFromAnnotations reads ownership from an object's annotations.
+// Missing annotations yield empty fields (and fail verification).
+func OwnershipFromAnnotations(annotations map[string]string) *Ownership {
+	return &Ownership{
+		InstallationID: annotations[AnnotationInstallationID],
+		SystemID:       annotations[AnnotationOwnerSystemID],
+		ServiceID:      annotations[AnnotationOwnerServiceID],
+		RequestedBy:    annotations[AnnotationRequestedBy],
+		TicketID:       annotations[AnnotationOwnerTicketID],
+	}
+}
+
+// VerifyOwnership checks an object's ownership against the expected record.
+//
+// RequestedBy is informational and not compared. TicketID is compared only
+// when expected carries one: VMs adopted or relocated keep their original
+// ticket, and the record is authoritative about which it is.
+func VerifyOwnership(expected, actual *Ownership) error {
+	mismatch := func(field, want, got string) error {
+		return fmt.Errorf("%s: want %q, got %q: %w", field, want, got, ErrNotOwned)
+	}
+
+	if actual.InstallationID != expected.InstallationID {
+		return mismatch("installation", expected.InstallationID, actual.InstallationID)
+	}
+	if actual.SystemID != expected.SystemID {
+		return mismatch("system", expected.SystemID, actual.SystemID)
+	}
+	if actual.ServiceID != expected.ServiceID {
+		return mismatch("service", expected.ServiceID, actual.ServiceID)
+	}
+	if expected.TicketID != "" && actual.TicketID != expected.TicketID {
+		return mismatch("ticket", expected.TicketID, actual.TicketID)
+	}
+	return nil
+}
+
+// ErrNotOwned is returned when a cluster object's ownership annotations do
+// not match Shepherd's record. Never retried: the object must be reconciled
+// (adopted or renamed) by an admin.
+var ErrNotOwned = domainerr.Conflict("VM_NOT_OWNED", "cluster object is not owned by this Shepherd record")
@@ -0,0 +1,330 @@
+// Package domain provides domain models.
+//
+// This file defines policies written as expressions (policy-as-code),
+// for conditions the built-in approval rule fields cannot express: the
+// requester's department, System labels, time windows, or any
+// combination of them. Expressions are CEL, evaluated against PolicyInput:
+//
+//	request.type == "CREATE_VM" && request.cpu <= 8 &&
+//	    requester.department == "Engineering" &&
+//	    now.getDayOfWeek("Europe/Berlin") in [1, 2, 3, 4, 5]
+//
+// Two kinds of policy:
+//
+//	approval   auto_approve or manual, ordered with the approval rules
+//	           by priority; the environment policy still caps the route
+//	guardrail  refuses the request at submission with Message
+//
+// Policies are stored in policy_expressions and versioned like
+// InstanceSizes: every edit writes an immutable PolicyExpressionVersion,
+// so routing is explainable afterwards ("policy x v3"). An expression
+// that fails to evaluate does not loosen anything: an approval policy
+// routes to manual, a guardrail refuses.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// PolicyKind is what a policy expression decides.
+type PolicyKind string
+
+const (
+	PolicyKindApproval  PolicyKind = "approval"
+	PolicyKindGuardrail PolicyKind = "guardrail"
+)
+
+// PolicyLanguage is the language of a policy expression. Only CEL is
+// supported; it is recorded so another language can be added later.
+type PolicyLanguage string
+
+const PolicyLanguageCEL PolicyLanguage = "cel"
+
+// MaxPolicyExpressionLength bounds the source of one expression.
+const MaxPolicyExpressionLength = 4096
+
+// PolicyExpression is the current version of a policy (policy_expressions).
+type PolicyExpression struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`     // Unique; fixed at creation
+	Kind       PolicyKind     `json:"kind"`     // Fixed at creation
+	Language   PolicyLanguage `json:"language"` // Fixed at creation
+	Expression string         `json:"expression"`
+	Priority   int            `json:"priority"` // Lower runs first; shared with approval rules
+	Enabled    bool           `json:"enabled"`
+
+	Action  ApprovalAction `json:"action,omitempty"`  // Approval policies: taken when the expression holds
+	Message string         `json:"message,omitempty"` // Guardrails: shown to the requester when refused
+
+	Version   int       `json:"version"` // 1 for the policy as created
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the policy's fields. Whether the expression compiles to
+// a bool is checked by the policy engine (service/policy_engine.go).
+func (p *PolicyExpression) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required: %w", ErrInvalidPolicyExpression)
+	}
+	if p.Language != PolicyLanguageCEL {
+		return fmt.Errorf("unsupported language %q: %w", p.Language, ErrInvalidPolicyExpression)
+	}
+	if p.Expression == "" || len(p.Expression) > MaxPolicyExpressionLength {
+		return fmt.Errorf("expression must be 1-%d characters: %w", MaxPolicyExpressionLength, ErrInvalidPolicyExpression)
+	}
+	switch p.Kind {
+	case PolicyKindApproval:
+		if p.Action != ApprovalActionAuto && p.Action != ApprovalActionManual {
+			return fmt.Errorf("unknown action %q: %w", p.Action, ErrInvalidPolicyExpression)
+		}
+	case PolicyKindGuardrail:
+		if p.Action != "" {
+			return fmt.Errorf("guardrails take no action: %w", ErrInvalidPolicyExpression)
+		}
+		if p.Message == "" {
+			return fmt.Errorf("guardrails need a message: %w", ErrInvalidPolicyExpression)
+		}
+	default:
+		return fmt.Errorf("unknown kind %q: %w", p.Kind, ErrInvalidPolicyExpression)
+	}
+	return nil
+}
+
+// Ref names the policy at its version, e.g. "policy after-hours v3".
+func (p *PolicyExpression) Ref() string {
+	return fmt.Sprintf("policy %s v%d", p.Name, p.Version)
+}
+
+// PolicyExpressionVersion is one immutable version of a policy
+// (policy_expression_versions, primary key policy_id + version).
+type PolicyExpressionVersion struct {
+	PolicyExpression
+	ChangeReason string `json:"change_reason"`
+}
+
+// PolicyExpressionEdit is the body of a policy update. It replaces every
+// editable field (PUT semantics); the name, kind and language cannot
+// change.
+// ExpectedVersion is the version the admin edited.
+type PolicyExpressionEdit struct {
+	ExpectedVersion int    `json:"expected_version"`
+	Reason          string `json:"reason"`
+
+	Expression string         `json:"expression"`
+	Priority   int            `json:"priority"`
+	Enabled    bool           `json:"enabled"`
+	Action     ApprovalAction `json:"action,omitempty"`
+	Message    string         `json:"message,omitempty"`
+}
+
+// Apply returns current with the edit applied, at the next version.
+// current is not changed.
+func (e *PolicyExpressionEdit) Apply(current *PolicyExpression, actor string, at time.Time) (*PolicyExpression, error) {
+	if e.ExpectedVersion != current.Version {
+		return nil, fmt.Errorf("policy %s is at version %d, edit is based on %d: %w",
+			current.Name, current.Version, e.ExpectedVersion, ErrPolicyExpressionVersionConflict.With("version", current.Version))
+	}
+	if e.Reason == "" || len(e.Reason) > 500 {
+		return nil, fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidPolicyExpression)
+	}
+
+	next := *current
+	next.Version = current.Version + 1
+	next.Expression = e.Expression
+	next.Priority = e.Priority
+	next.Enabled = e.Enabled
+	next.Action = e.Action
+	next.Message = e.Message
+	next.UpdatedBy = actor
+	next.UpdatedAt = at
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// MaxDryRunInputs bounds the sample inputs of one dry run.
+const MaxDryRunInputs = 100
+
+// PolicyDryRun evaluates a draft policy against sample inputs before it is
+// saved. Nothing is written, and the draft need not be enabled.
+type PolicyDryRun struct {
+	Policy PolicyExpression `json:"policy"`
+	Inputs []PolicyInput    `json:"inputs"`
+}
+
+// Validate checks the draft as if it were saved, and the number of inputs.
+func (d *PolicyDryRun) Validate() error {
+	if len(d.Inputs) == 0 || len(d.Inputs) > MaxDryRunInputs {
+		return fmt.Errorf("dry run needs 1-%d inputs: %w", MaxDryRunInputs, ErrInvalidPolicyExpression)
+	}
+	return d.Policy.Validate()
+}
+
+// PolicyEffect is what a policy result does to a request.
+type PolicyEffect string
+
+const (
+	PolicyEffectNone        PolicyEffect = "none" // Did not decide; later rules and policies apply
+	PolicyEffectAutoApprove PolicyEffect = "auto_approve"
+	PolicyEffectManual      PolicyEffect = "manual"
+	PolicyEffectRefused     PolicyEffect = "refused"
+)
+
+// PolicyInput is what expressions are evaluated against. Each field is a
+// CEL variable; the JSON names are the CEL field names.
+type PolicyInput struct {
+	Request   PolicyRequest   `json:"request"`
+	Requester PolicyRequester `json:"requester"`
+	Service   PolicyService   `json:"service"`
+	Now       time.Time       `json:"now"` // Submission time, a CEL timestamp
+}
+
+// PolicyRequest is the request as routed (ApprovalRequest).
+type PolicyRequest struct {
+	Type        string `json:"type"` // CREATE_VM, MODIFY_VM, ...
+	Namespace   string `json:"namespace"`
+	Environment string `json:"environment"` // The Service's deployment environment
+	CPU         int    `json:"cpu"`         // Resulting size for MODIFY_VM
+	MemoryMB    int    `json:"memory_mb"`
+	DiskGB      int    `json:"disk_gb"`
+}
+
+// PolicyRequester is who submitted the request. Department and Groups are
+// the IdP attributes synced at login; Roles are as for approval rules.
+type PolicyRequester struct {
+	ID         string   `json:"id"`
+	Department string   `json:"department"`
+	Groups     []string `json:"groups"`
+	Roles      []string `json:"roles"`
+}
+
+// PolicyService is the Service the request is for, with its System's
+// labels (system_metadata.go).
+type PolicyService struct {
+	ID     string            `json:"id"`
+	System string            `json:"system"` // System ID
+	Labels map[string]string `json:"labels"`
+}
+
+// Activation returns the CEL variables for in. Lists and maps are never
+// nil, so expressions need no has() checks on them.
+func (in *PolicyInput) Activation() map[string]interface{} {
+	groups, roles, labels := in.Requester.Groups, in.Requester.Roles, in.Service.Labels
+	if groups == nil {
+		groups = []string{}
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"type":        in.Request.Type,
+			"namespace":   in.Request.Namespace,
+			"environment": in.Request.Environment,
+			"cpu":         in.Request.CPU,
+			"memory_mb":   in.Request.MemoryMB,
+			"disk_gb":     in.Request.DiskGB,
+		},
+		"requester": map[string]interface{}{
+			"id":         in.Requester.ID,
+			"department": in.Requester.Department,
+			"groups":     groups,
+			"roles":      roles,
+		},
+		"service": map[string]interface{}{
+			"id":     in.Service.ID,
+			"system": in.Service.System,
+			"labels": labels,
+		},
+		"now": in.Now,
+	}
+}
+
+// PolicyResult is the outcome of one policy for one input.
+type PolicyResult struct {
+	Policy  *PolicyExpression `json:"policy"`
+	Matched bool              `json:"matched"`
+	Error   string            `json:"error,omitempty"` // Evaluation failed
+}
+
+// Decides reports whether the result takes effect: the expression held,
+// or failed to evaluate (approval: manual, guardrail: refused).
+func (r PolicyResult) Decides() bool {
+	return r.Matched || r.Error != ""
+}
+
+// Effect returns what the result does to the request, before the
+// environment policy caps an auto-approval.
+func (r PolicyResult) Effect() PolicyEffect {
+	switch {
+	case !r.Decides():
+		return PolicyEffectNone
+	case r.Policy.Kind == PolicyKindGuardrail:
+		return PolicyEffectRefused
+	case r.Error == "" && r.Policy.Action == ApprovalActionAuto:
+		return PolicyEffectAutoApprove
+	default:
+		return PolicyEffectManual
+	}
+}
+
+// route returns the approval route of a deciding approval result.
+func (r PolicyResult) route() ApprovalRoute {
+	if r.Error != "" {
+		return ApprovalRoute{AutoApprove: false, RuleID: r.Policy.ID, Reason: r.Policy.Ref() + " failed to evaluate"}
+	}
+	return ApprovalRoute{AutoApprove: r.Policy.Action == ApprovalActionAuto, RuleID: r.Policy.ID, Reason: r.Policy.Ref()}
+}
+
+// CheckGuardrails returns a *GuardrailDenied for the first deciding
+// guardrail by priority, nil if none refuses. results holds every
+// enabled policy's result, approval ones included.
+func CheckGuardrails(results []PolicyResult) error {
+	var denied *PolicyResult
+	for i, r := range results {
+		if r.Policy.Kind != PolicyKindGuardrail || !r.Decides() {
+			continue
+		}
+		if denied == nil || r.Policy.Priority < denied.Policy.Priority {
+			denied = &results[i]
+		}
+	}
+	if denied == nil {
+		return nil
+	}
+	message := denied.Policy.Message
+	if denied.Error != "" {
+		message = "policy could not be evaluated"
+	}
+	return &GuardrailDenied{PolicyID: denied.Policy.ID, Policy: denied.Policy.Name, Version: denied.Policy.Version, Message: message}
+}
+
+// GuardrailDenied is returned when a guardrail refuses a request at
+// submission (422 POLICY_GUARDRAIL_DENIED). No ticket is created.
+type GuardrailDenied struct {
+	PolicyID string `json:"policy_id"`
+	Policy   string `json:"policy"`
+	Version  int    `json:"version"`
+	Message  string `json:"message"`
+}
+
+func (e *GuardrailDenied) Error() string {
+	return fmt.Sprintf("refused by policy %s v%d: %s", e.Policy, e.Version, e.Message)
+}
+
+// Errors, typed with their codes (domain/errors/errors.go)
+var (
+	ErrInvalidPolicyExpression         = domainerr.Invalid("INVALID_POLICY_EXPRESSION", "invalid policy expression")
+	ErrPolicyExpressionVersionConflict = domainerr.Conflict("POLICY_EXPRESSION_VERSION_CONFLICT", "policy was changed since the edited version")
+	ErrPolicyExpressionNameTaken       = domainerr.Conflict("POLICY_EXPRESSION_NAME_TAKEN", "a policy with this name exists")
+)
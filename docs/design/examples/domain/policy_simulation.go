@@ -0,0 +1,204 @@
+// Package domain provides domain models.
+//
+// This file defines approval policy simulation: a draft change to the
+// environment policies and approval rules is replayed against the last N
+// requests to show how each would have been routed, before the draft is
+// saved:
+//
+//	auto_approved      enqueued without a ticket
+//	approval_required  ticket with the draft's approvals and stages
+//	rejected           refused at submission (above the policy's max_size)
+//
+// Each request is routed exactly as at submission (EnvironmentPolicy.Evaluate,
+// then RouteApproval) with the spec it was submitted with. Requester roles
+// are those of today, since role history is not kept. Nothing is written:
+// simulating neither saves the draft nor re-routes open tickets.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Simulation sizes (?limit=).
+const (
+	DefaultSimulationRequests = 200
+	MaxSimulationRequests     = 2000
+)
+
+// SimulationOutcome is how a request is (or was) routed.
+type SimulationOutcome string
+
+const (
+	SimulationAutoApproved     SimulationOutcome = "auto_approved"
+	SimulationApprovalRequired SimulationOutcome = "approval_required"
+	SimulationRejected         SimulationOutcome = "rejected"
+)
+
+// PolicyDraft is a proposed change to the approval policy. Policies
+// replace the current policy of their environment; the others stay.
+// Rules replace the whole rule set: nil keeps the current rules, an empty
+// list simulates having none.
+type PolicyDraft struct {
+	Policies []*EnvironmentPolicy `json:"policies,omitempty"`
+	Rules    []*ApprovalRule      `json:"rules"`
+}
+
+// Validate checks the draft as if it were saved.
+func (d *PolicyDraft) Validate() error {
+	if len(d.Policies) == 0 && d.Rules == nil {
+		return fmt.Errorf("draft changes neither policies nor rules: %w", ErrInvalidPolicySimulation)
+	}
+	seen := make(map[DeploymentEnvironment]bool, len(d.Policies))
+	for _, p := range d.Policies {
+		if seen[p.Environment] {
+			return fmt.Errorf("environment %s appears twice: %w", p.Environment, ErrInvalidPolicySimulation)
+		}
+		seen[p.Environment] = true
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range d.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply returns the policies and rules in effect with the draft saved,
+// given the current ones. current is not changed.
+func (d *PolicyDraft) Apply(current map[DeploymentEnvironment]*EnvironmentPolicy, rules []*ApprovalRule) (map[DeploymentEnvironment]*EnvironmentPolicy, []*ApprovalRule) {
+	policies := make(map[DeploymentEnvironment]*EnvironmentPolicy, len(current))
+	for env, p := range current {
+		policies[env] = p
+	}
+	for _, p := range d.Policies {
+		policies[p.Environment] = p
+	}
+	if d.Rules != nil {
+		rules = d.Rules
+	}
+	return policies, rules
+}
+
+// SimulatedRequest is a historical CREATE_VM or MODIFY_VM request as it
+// was submitted, with how it was routed then.
+type SimulatedRequest struct {
+	TicketID    string                `json:"ticket_id"`
+	RequestType string                `json:"request_type"`
+	ServiceID   string                `json:"service_id"`
+	Environment DeploymentEnvironment `json:"environment"` // Frozen on the ticket
+	Namespace   string                `json:"namespace"`
+	RequestedBy string                `json:"requested_by"`
+	CreatedAt   time.Time             `json:"created_at"`
+
+	// Spec is the submitted spec (resize: the resulting size), before
+	// any approver modification.
+	Spec VMCreationPayload `json:"-"`
+
+	// RequesterRoles are set by the caller when a rule conditions on roles.
+	RequesterRoles []string `json:"-"`
+
+	Status       TicketStatus `json:"status"`
+	AutoApproved bool         `json:"auto_approved"` // required_approvals = 0
+}
+
+// Actual returns how the request was routed at submission. Requests
+// refused then never got a ticket, so history has no rejected ones.
+func (r *SimulatedRequest) Actual() SimulationOutcome {
+	if r.AutoApproved {
+		return SimulationAutoApproved
+	}
+	return SimulationApprovalRequired
+}
+
+// SimulatedRoute is the draft's outcome for one request.
+type SimulatedRoute struct {
+	Request   *SimulatedRequest `json:"request"`
+	Actual    SimulationOutcome `json:"actual"`
+	Simulated SimulationOutcome `json:"simulated"`
+	Changed   bool              `json:"changed"`
+
+	RequiredApprovals int    `json:"required_approvals,omitempty"` // approval_required only
+	Staged            bool   `json:"staged,omitempty"`
+	RuleID            string `json:"rule_id,omitempty"` // Rule that decided, if any
+	Reason            string `json:"reason"`
+}
+
+// PolicySimulation is the result of replaying requests under a draft.
+type PolicySimulation struct {
+	Requests int                       `json:"requests"`
+	Outcomes map[SimulationOutcome]int `json:"outcomes"` // Under the draft
+	Changed  int                       `json:"changed"`  // Outcome differs from the actual one
+
+	// Transitions counts changed requests by "actual→simulated".
+	Transitions map[string]int   `json:"transitions"`
+	Routes      []SimulatedRoute `json:"routes"`
+}
+
+// SimulatePolicy routes each request with policies and rules, the result
+// of PolicyDraft.Apply. A request whose environment has no policy is
+// reported as rejected, as submission would fail.
+func SimulatePolicy(policies map[DeploymentEnvironment]*EnvironmentPolicy, rules []*ApprovalRule, requests []*SimulatedRequest) *PolicySimulation {
+	sim := &PolicySimulation{
+		Requests:    len(requests),
+		Outcomes:    make(map[SimulationOutcome]int),
+		Transitions: make(map[string]int),
+		Routes:      make([]SimulatedRoute, 0, len(requests)),
+	}
+	for _, req := range requests {
+		route := simulateRoute(policies[req.Environment], rules, req)
+		sim.Outcomes[route.Simulated]++
+		if route.Changed {
+			sim.Changed++
+			sim.Transitions[string(route.Actual)+"→"+string(route.Simulated)]++
+		}
+		sim.Routes = append(sim.Routes, route)
+	}
+	return sim
+}
+
+func simulateRoute(policy *EnvironmentPolicy, rules []*ApprovalRule, req *SimulatedRequest) SimulatedRoute {
+	route := SimulatedRoute{Request: req, Actual: req.Actual(), Simulated: SimulationRejected}
+	if policy == nil {
+		route.Reason = fmt.Sprintf("no policy for %s", req.Environment)
+		route.Changed = true
+		return route
+	}
+	decision := policy.Evaluate(&req.Spec)
+	if decision.Refused {
+		route.Reason = fmt.Sprintf("above %s max size", req.Environment)
+		route.Changed = true
+		return route
+	}
+
+	r := RouteApproval(rules, ApprovalRequest{
+		RequestType:    req.RequestType,
+		Namespace:      req.Namespace,
+		CPU:            req.Spec.CPU,
+		MemoryMB:       req.Spec.MemoryMB,
+		RequesterRoles: req.RequesterRoles,
+	}, decision)
+	route.RuleID = r.RuleID
+	route.Reason = r.Reason
+	if r.AutoApprove {
+		route.Simulated = SimulationAutoApproved
+	} else {
+		route.Simulated = SimulationApprovalRequired
+		route.RequiredApprovals = TotalApprovals(decision.Stages)
+		route.Staged = decision.Staged
+	}
+	route.Changed = route.Simulated != route.Actual
+	return route
+}
+
+// Errors
+var (
+	ErrInvalidPolicySimulation = domainerr.Invalid("INVALID_POLICY_SIMULATION", "invalid policy simulation")
+)
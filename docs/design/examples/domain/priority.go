@@ -0,0 +1,114 @@
+// Package domain provides domain models.
+//
+// This file defines request priority.
+//
+// Priority is chosen by the requester and gated by permission on the
+// Service. It orders the approver inbox, sets notification urgency and
+// selects the River queue the approved execution runs on; each queue has
+// its own workers, so a backlog of normal requests cannot delay an
+// emergency. Emergency use is reported per requester to spot abuse.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Priority is a request's priority.
+type Priority string
+
+const (
+	PriorityNormal    Priority = "normal"
+	PriorityHigh      Priority = "high"
+	PriorityEmergency Priority = "emergency"
+)
+
+// River queues per priority (river.queues.* sets workers per queue).
+const (
+	QueueNormal    = "default"
+	QueueHigh      = "high"
+	QueueEmergency = "emergency"
+)
+
+// ParsePriority validates a requested priority. Empty means normal.
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(s); p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityNormal, PriorityHigh, PriorityEmergency:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%q: %w", s, ErrInvalidPriority)
+	}
+}
+
+// Rank orders the approver inbox (higher first); stored as
+// approval_tickets.priority_rank.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityEmergency:
+		return 2
+	case PriorityHigh:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Queue returns the River queue for the approved execution.
+func (p Priority) Queue() string {
+	switch p {
+	case PriorityEmergency:
+		return QueueEmergency
+	case PriorityHigh:
+		return QueueHigh
+	default:
+		return QueueNormal
+	}
+}
+
+// Permission returns the permission required on the Service to request p,
+// or "" if none is needed.
+func (p Priority) Permission() string {
+	switch p {
+	case PriorityEmergency:
+		return "request:priority_emergency"
+	case PriorityHigh:
+		return "request:priority_high"
+	default:
+		return ""
+	}
+}
+
+// Emergency abuse thresholds for the usage report.
+const (
+	EmergencyReportMinCount = 3    // Below this, never flagged
+	EmergencyReportMaxRatio = 0.25 // Emergency share of a requester's requests
+)
+
+// EmergencyUsage summarizes one requester's priority use over a window.
+type EmergencyUsage struct {
+	UserID    string `json:"user_id"`
+	Total     int    `json:"total"`
+	Emergency int    `json:"emergency"`
+	Rejected  int    `json:"rejected"` // Emergency requests that were rejected
+}
+
+// Flagged reports whether the requester's emergency use needs review.
+func (u *EmergencyUsage) Flagged() bool {
+	if u.Emergency < EmergencyReportMinCount {
+		return false
+	}
+	return float64(u.Emergency)/float64(u.Total) > EmergencyReportMaxRatio ||
+		u.Rejected > u.Emergency/2
+}
+
+// Errors
+var (
+	ErrInvalidPriority   = errors.New("invalid priority")
+	ErrPriorityForbidden = domainerr.PermissionDenied("PRIORITY_FORBIDDEN", "not permitted to request this priority")
+)
@@ -0,0 +1,124 @@
+// Package domain provides domain models.
+//
+// This file defines Service quota reservations.
+//
+// Shepherd-level quota only (CPU/memory/disk/VM count per Service); Kubernetes
+// ResourceQuota stays a K8s admin concern (ADR-0015 §9). Quota is reserved
+// when a ticket is approved, in the same TX as the River job insert, and is
+// either CONSUMED (the VM now counts as usage) or RELEASED (execution failed,
+// was cancelled, or the reservation was orphaned). A HELD reservation with no
+// live event or job is leakage; the sweep job releases it.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// ReservationStatus is the lifecycle state of a quota reservation.
+type ReservationStatus string
+
+const (
+	ReservationHeld     ReservationStatus = "HELD"
+	ReservationConsumed ReservationStatus = "CONSUMED" // Terminal: VM exists, counted as usage
+	ReservationReleased ReservationStatus = "RELEASED" // Terminal: quota returned
+)
+
+// ReleaseReason records why a reservation was released.
+type ReleaseReason string
+
+const (
+	ReleaseExecutionFailed ReleaseReason = "execution_failed"
+	ReleaseCancelled       ReleaseReason = "cancelled"
+	ReleaseOrphaned        ReleaseReason = "orphaned" // No live event/job (sweep)
+)
+
+// QuotaReservation holds Service quota for an approved request.
+type QuotaReservation struct {
+	ID        string `json:"id"`
+	ServiceID string `json:"service_id"`
+	TicketID  string `json:"ticket_id"`
+	EventID   string `json:"event_id"`
+
+	CPU      int `json:"cpu"`
+	MemoryMB int `json:"memory_mb"`
+	DiskGB   int `json:"disk_gb"`
+	VMCount  int `json:"vm_count"`
+
+	Status        ReservationStatus `json:"status"`
+	ReleaseReason ReleaseReason     `json:"release_reason,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	SettledAt     *time.Time        `json:"settled_at,omitempty"` // CONSUMED or RELEASED
+}
+
+// NewQuotaReservation reserves the effective (post-modification) spec.
+func NewQuotaReservation(id, ticketID, eventID string, spec *VMCreationPayload) *QuotaReservation {
+	return &QuotaReservation{
+		ID:        id,
+		ServiceID: spec.ServiceID,
+		TicketID:  ticketID,
+		EventID:   eventID,
+		CPU:       spec.CPU,
+		MemoryMB:  spec.MemoryMB,
+		DiskGB:    spec.DiskGB,
+		VMCount:   1,
+		Status:    ReservationHeld,
+	}
+}
+
+// NewResizeReservation reserves only the increase of a resize; decreases
+// reserve nothing and are returned to the Service once applied.
+func NewResizeReservation(id, ticketID, eventID string, p VMModifyPayload) *QuotaReservation {
+	return &QuotaReservation{
+		ID:        id,
+		ServiceID: p.ServiceID,
+		TicketID:  ticketID,
+		EventID:   eventID,
+		CPU:       max(p.CPU-p.FromCPU, 0),
+		MemoryMB:  max(p.MemoryMB-p.FromMemoryMB, 0),
+		Status:    ReservationHeld,
+	}
+}
+
+// HeldReservation is a HELD reservation joined with its execution state,
+// as returned by the sweep query.
+type HeldReservation struct {
+	Reservation *QuotaReservation
+
+	// EventStatus is empty when the DomainEvent no longer exists.
+	EventStatus EventStatus
+
+	// HasLiveJob: an EventJobArgs job for the event is available,
+	// scheduled, running or retryable.
+	HasLiveJob bool
+}
+
+// ReservationAction is the sweep decision for one reservation.
+type ReservationAction string
+
+const (
+	ReservationKeep    ReservationAction = "keep"
+	ReservationConsume ReservationAction = "consume"
+	ReservationRelease ReservationAction = "release"
+)
+
+// DecideReservation returns what the sweep should do with a HELD reservation.
+//
+// Reservations younger than grace are kept: the approval TX may have committed
+// moments before River picks up the job.
+func DecideReservation(h HeldReservation, grace time.Duration, now time.Time) (ReservationAction, ReleaseReason) {
+	switch h.EventStatus {
+	case EventStatusCompleted:
+		// Handler should have consumed it; settle the straggler
+		return ReservationConsume, ""
+	case EventStatusFailed:
+		return ReservationRelease, ReleaseExecutionFailed
+	case EventStatusCancelled:
+		return ReservationRelease, ReleaseCancelled
+	}
+
+	if h.HasLiveJob || now.Sub(h.Reservation.CreatedAt) < grace {
+		return ReservationKeep, ""
+	}
+	// Event missing, or PENDING/PROCESSING with no job left to move it
+	return ReservationRelease, ReleaseOrphaned
+}
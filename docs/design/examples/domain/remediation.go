@@ -0,0 +1,116 @@
+// Package domain provides domain models.
+//
+// This file defines remediation campaigns: one request per non-compliant
+// VM (compliance.go), submitted in bulk by a platform admin.
+//
+// A campaign is a list of ordinary requests, not a new approval path:
+// rebuilds are blue/green replacements onto the active template version
+// (vm_replacement.go), modifications are MODIFY_VM requests. Each goes
+// through its own approval, so owners and approvers see every VM change.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxCampaignVMs bounds the VMs of one campaign. A filter matching more
+// takes the most severe first; start another campaign for the rest.
+const MaxCampaignVMs = 200
+
+// RemediationAction is the request submitted per VM.
+type RemediationAction string
+
+const (
+	RemediationRebuild RemediationAction = "rebuild" // Blue/green replacement
+	RemediationModify  RemediationAction = "modify"  // MODIFY_VM, e.g. more memory for a patched image
+)
+
+// CampaignRequest is the body of a new campaign.
+type CampaignRequest struct {
+	Name   string            `json:"name"`
+	Filter ComplianceFilter  `json:"filter"`
+	Action RemediationAction `json:"action"`
+
+	// Rebuild only: how green takes over
+	Confirmation ReplacementConfirmation `json:"confirmation,omitempty"`
+
+	// Modify only: the new size; zero keeps the current value
+	CPU      int `json:"cpu,omitempty"`
+	MemoryMB int `json:"memory_mb,omitempty"`
+
+	Reason string `json:"reason"` // Prefixed with the campaign name on each request
+}
+
+// Validate checks the request.
+func (r *CampaignRequest) Validate() error {
+	if r.Name == "" || len(r.Name) > 100 {
+		return fmt.Errorf("name must be 1-100 characters: %w", ErrInvalidCampaign)
+	}
+	if r.Reason == "" || len(r.Reason) > 400 {
+		return fmt.Errorf("reason must be 1-400 characters: %w", ErrInvalidCampaign)
+	}
+	if err := r.Filter.Validate(); err != nil {
+		return err
+	}
+	switch r.Action {
+	case RemediationRebuild:
+		if r.Confirmation != ConfirmByOwner && r.Confirmation != ConfirmByHealthProbe {
+			return fmt.Errorf("rebuild needs confirmation owner or health_probe: %w", ErrInvalidCampaign)
+		}
+	case RemediationModify:
+		if r.CPU <= 0 && r.MemoryMB <= 0 {
+			return fmt.Errorf("modify needs cpu or memory_mb: %w", ErrInvalidCampaign)
+		}
+	default:
+		return fmt.Errorf("action must be rebuild or modify: %w", ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// RemediationCampaign is a started campaign (remediation_campaigns).
+type RemediationCampaign struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Filter    ComplianceFilter  `json:"filter"` // JSONB
+	Action    RemediationAction `json:"action"`
+	Reason    string            `json:"reason"`
+	Submitted int               `json:"submitted"`
+	Failed    int               `json:"failed"`
+	Truncated bool              `json:"truncated"` // More VMs matched than MaxCampaignVMs
+	CreatedBy string            `json:"created_by"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	Items []*RemediationItem `json:"items,omitempty"`
+}
+
+// RemediationItemStatus is the outcome of submitting one VM's request.
+type RemediationItemStatus string
+
+const (
+	RemediationSubmitted RemediationItemStatus = "SUBMITTED"
+	RemediationFailed    RemediationItemStatus = "FAILED" // Not submitted; Error says why
+)
+
+// RemediationItem is one VM of a campaign (remediation_campaign_items).
+// TicketStatus is read from the request's ticket when the campaign is
+// fetched, so progress is always current.
+type RemediationItem struct {
+	VMID          string                `json:"vm_id"`
+	VMName        string                `json:"vm_name"`
+	Status        RemediationItemStatus `json:"status"`
+	TicketID      string                `json:"ticket_id,omitempty"`
+	ReplacementID string                `json:"replacement_id,omitempty"` // Rebuild only
+	TicketStatus  string                `json:"ticket_status,omitempty"`
+	Error         string                `json:"error,omitempty"`
+}
+
+// Errors
+var (
+	ErrInvalidCampaign = domainerr.Invalid("INVALID_REQUEST", "invalid remediation campaign")
+	ErrCampaignEmpty   = domainerr.Unprocessable("CAMPAIGN_EMPTY", "no non-compliant vm matches the filter")
+)
@@ -0,0 +1,75 @@
+// Package domain provides domain models.
+//
+// This file defines Service-level request defaults: values a Service's
+// owners configure to pre-populate VM requests for that Service.
+//
+// Defaults are suggestions, never constraints: the user's explicit values
+// always win, and a default that has gone stale (template archived, size
+// disabled, namespace deregistered) is dropped with a reason instead of
+// failing the form. Resolution happens server-side so the UI form, the
+// preview endpoint and the submit path all see the same values.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import "time"
+
+// ServiceRequestDefaults is stored one-to-one with Service.
+type ServiceRequestDefaults struct {
+	ServiceID string `json:"service_id"`
+
+	// TemplateName, not ID: resolves to the currently active version,
+	// so publishing a new template version does not stale the default.
+	TemplateName     string `json:"template_name,omitempty"`
+	InstanceSizeName string `json:"instance_size_name,omitempty"`
+	Namespace        string `json:"namespace,omitempty"`
+
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultSource tells the UI where a field's value came from.
+type DefaultSource string
+
+const (
+	DefaultSourceUser    DefaultSource = "user"    // Explicitly provided in the request
+	DefaultSourceService DefaultSource = "service" // Filled from Service defaults
+	DefaultSourceNone    DefaultSource = "none"    // No value; user must choose
+)
+
+// ResolvedField is one form field after default resolution.
+type ResolvedField struct {
+	Value  string        `json:"value,omitempty"`
+	Source DefaultSource `json:"source"`
+
+	// Ignored explains why a configured default was not applied.
+	Ignored string `json:"ignored,omitempty"`
+}
+
+// ResolvedRequest is the request with defaults applied, per field.
+type ResolvedRequest struct {
+	ServiceID    string        `json:"service_id"`
+	TemplateID   ResolvedField `json:"template_id"`
+	InstanceSize ResolvedField `json:"instance_size_id"`
+	Namespace    ResolvedField `json:"namespace"`
+}
+
+// Complete reports whether every required field has a value.
+func (r *ResolvedRequest) Complete() bool {
+	return r.TemplateID.Value != "" && r.InstanceSize.Value != "" && r.Namespace.Value != ""
+}
+
+// ResolveField applies a default to one field.
+// userValue wins; otherwise defaultValue is used unless staleReason is set.
+func ResolveField(userValue, defaultValue, staleReason string) ResolvedField {
+	switch {
+	case userValue != "":
+		return ResolvedField{Value: userValue, Source: DefaultSourceUser}
+	case defaultValue == "":
+		return ResolvedField{Source: DefaultSourceNone}
+	case staleReason != "":
+		return ResolvedField{Source: DefaultSourceNone, Ignored: staleReason}
+	default:
+		return ResolvedField{Value: defaultValue, Source: DefaultSourceService}
+	}
+}
@@ -89,4 +89,9 @@ type PermissionChecker interface {
 	// Only users with "owner" or "admin" role on the resource can grant permissions.
 	// Note: owner can grant any role; admin cannot grant owner role.
 	CanGrant(granterID, resourceType, resourceID, targetRole string) (bool, error)
+
+	// ResolveScope expands every binding that grants action into an AccessScope
+	// (global, system-scoped and resource-level, with environment limits).
+	// List endpoints push it into the query instead of post-filtering.
+	ResolveScope(userID, action string) (*AccessScope, error)
 }
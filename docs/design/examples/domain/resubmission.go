@@ -0,0 +1,104 @@
+// Package domain provides domain models.
+//
+// This file defines resubmission of rejected requests.
+//
+// A requester whose ticket was rejected may resubmit it with edits: the
+// new request starts from the rejected ticket's original payload, goes
+// through the normal submission path (environment policy, approval
+// rules, fresh approvers) and links back to the rejected ticket. The
+// chain of linked tickets is the history of the negotiation: each round's
+// request, who rejected it and why.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Resubmittable reports whether tickets of requestType can be resubmitted:
+// the request types whose spec the requester can edit.
+func Resubmittable(requestType string) bool {
+	switch requestType {
+	case "CREATE_VM", "MODIFY_VM":
+		return true
+	default:
+		return false
+	}
+}
+
+// ResubmitEdits are the requester's changes to the rejected request. Nil
+// fields keep the original value; Reason is always restated, usually
+// answering the reject reason.
+type ResubmitEdits struct {
+	CPU        *int    `json:"cpu,omitempty"`
+	MemoryMB   *int    `json:"memory_mb,omitempty"`
+	TemplateID *string `json:"template_id,omitempty"` // CREATE_VM only
+	Reason     string  `json:"reason"`
+}
+
+// Validate checks the edits against the request type.
+func (e ResubmitEdits) Validate(requestType string) error {
+	if e.Reason == "" || len(e.Reason) > MaxRejectReasonLen {
+		return fmt.Errorf("reason is required, at most %d characters: %w", MaxRejectReasonLen, ErrInvalidResubmission)
+	}
+	if requestType == "MODIFY_VM" && e.TemplateID != nil {
+		return fmt.Errorf("only cpu and memory_mb can change on a resize: %w", ErrInvalidResubmission)
+	}
+	return nil
+}
+
+// ApplyToCreation returns the original creation payload with the edits.
+func (e ResubmitEdits) ApplyToCreation(p VMCreationPayload) VMCreationPayload {
+	if e.CPU != nil {
+		p.CPU = *e.CPU
+	}
+	if e.MemoryMB != nil {
+		p.MemoryMB = *e.MemoryMB
+	}
+	if e.TemplateID != nil {
+		p.TemplateID = *e.TemplateID
+	}
+	p.Reason = e.Reason
+	return p
+}
+
+// CheckResubmit checks that userID may resubmit a ticket: only the
+// requester, only once rejected, and not a batch child (the batch is
+// resubmitted as a whole by submitting a new batch).
+func CheckResubmit(status TicketStatus, requestType, createdBy, parentTicketID, userID string) error {
+	if createdBy != userID {
+		return ErrNotRequester
+	}
+	if status != TicketRejected {
+		return ErrTicketNotRejected
+	}
+	if !Resubmittable(requestType) || parentTicketID != "" {
+		return fmt.Errorf("%s: %w", requestType, ErrResubmitUnsupported)
+	}
+	return nil
+}
+
+// ResubmissionRound is one ticket of a resubmission chain, oldest first.
+type ResubmissionRound struct {
+	TicketID        string          `json:"ticket_id"`
+	ResubmittedFrom string          `json:"resubmitted_from,omitempty"`
+	Status          TicketStatus    `json:"status"`
+	Payload         json.RawMessage `json:"payload"` // The round's request as submitted
+	RequestReason   string          `json:"request_reason"`
+	RejectedBy      string          `json:"rejected_by,omitempty"`
+	RejectReason    string          `json:"reject_reason,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// Errors
+var (
+	ErrTicketNotRejected   = domainerr.Conflict("TICKET_NOT_REJECTED", "only rejected tickets can be resubmitted")
+	ErrResubmitUnsupported = domainerr.Conflict("RESUBMIT_UNSUPPORTED", "request cannot be resubmitted")
+	ErrAlreadyResubmitted  = domainerr.Conflict("ALREADY_RESUBMITTED", "ticket already resubmitted")
+	ErrInvalidResubmission = domainerr.Invalid("INVALID_REQUEST", "invalid resubmission")
+)
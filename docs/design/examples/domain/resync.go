@@ -0,0 +1,96 @@
+// Package domain provides domain models.
+//
+// This file defines the admin-triggered VM resync: a full relist of every
+// cluster reconciled against the platform DB, for recovery after watcher
+// outages or DB restores. The watcher only applies changes it sees; the
+// resync repairs what it missed.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ResyncStatus is the status of a resync run.
+type ResyncStatus string
+
+const (
+	ResyncRunning   ResyncStatus = "RUNNING"
+	ResyncCompleted ResyncStatus = "COMPLETED" // Terminal: every cluster done (some may have errors)
+	ResyncCancelled ResyncStatus = "CANCELLED" // Terminal
+)
+
+// Resync is one resync run. At most one runs at a time.
+type Resync struct {
+	ID          string       `json:"id"`
+	Status      ResyncStatus `json:"status"`
+	Reason      string       `json:"reason"`
+	RequestedBy string       `json:"requested_by"`
+	StartedAt   time.Time    `json:"started_at"` // VMs created after this are never marked missing
+	FinishedAt  *time.Time   `json:"finished_at,omitempty"`
+}
+
+// ResyncCluster is the progress of one cluster in a run. Continue is the
+// list cursor, so a restarted job resumes at the next page.
+type ResyncCluster struct {
+	ResyncID string `json:"resync_id"`
+	Cluster  string `json:"cluster"`
+	Continue string `json:"-"`
+	Listed   int    `json:"listed"`
+	Updated  int    `json:"updated"` // DB rows changed to match the cluster
+	Missing  int    `json:"missing"` // In DB, not on the cluster
+	Orphans  int    `json:"orphans"` // Managed by this installation, not in DB → pending adoption
+	Foreign  int    `json:"foreign"` // Owned by another installation, ignored
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"` // Last error; the cluster is retried by River
+}
+
+// ResyncAction is what reconciling one VM does to the DB.
+type ResyncAction string
+
+const (
+	ResyncUnchanged ResyncAction = "unchanged"
+	ResyncUpdate    ResyncAction = "update"  // Observed fields differ
+	ResyncOrphan    ResyncAction = "orphan"  // No DB row
+	ResyncForeign   ResyncAction = "foreign" // Other installation's object
+)
+
+// PlanResync decides how to reconcile a VM listed from a cluster with its
+// DB row (nil if none). installationID is this installation's ID.
+// Only observed fields (status, placement, sizing) are compared:
+// governance fields in the DB are the source of truth and never
+// overwritten from the cluster.
+func PlanResync(db, live *VM, installationID string) ResyncAction {
+	owner := OwnershipFromAnnotations(live.Annotations)
+	if owner.InstallationID != installationID {
+		return ResyncForeign
+	}
+	if db == nil {
+		return ResyncOrphan
+	}
+	if db.Status != live.Status || db.IP != live.IP || db.NodeName != live.NodeName ||
+		db.CPU != live.CPU || db.MemoryMB != live.MemoryMB ||
+		db.ResourceVersion != live.ResourceVersion {
+		return ResyncUpdate
+	}
+	return ResyncUnchanged
+}
+
+// MissingStatus is the status a DB VM gets when a completed cluster list
+// did not contain it: a pending deletion finished while the watcher was
+// down; anything else is flagged UNKNOWN for an admin, never deleted.
+func MissingStatus(db VMStatus) VMStatus {
+	if db == VMStatusDeleting {
+		return VMStatusDeleted
+	}
+	return VMStatusUnknown
+}
+
+// Errors
+var (
+	ErrResyncInProgress = domainerr.Conflict("RESYNC_IN_PROGRESS", "a resync is already running")
+	ErrResyncFinished   = domainerr.Conflict("RESYNC_FINISHED", "resync already finished")
+)
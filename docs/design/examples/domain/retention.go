@@ -0,0 +1,180 @@
+// Package domain provides domain models.
+//
+// This file defines data retention policies and legal holds.
+//
+// Each record type has one retention window, set by platform admins
+// within a per-type minimum (compliance floor) and enforced by a periodic
+// purge job. A legal hold exempts one resource, and everything recorded
+// about it, from every purge until the hold is released. Holds on a
+// Service or System cover its VMs.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// RetentionRecordType is a class of records purged under one policy.
+type RetentionRecordType string
+
+const (
+	RetentionEvents            RetentionRecordType = "events"             // domain_events in a terminal status
+	RetentionAuditLogs         RetentionRecordType = "audit_logs"         // audit_logs
+	RetentionNotifications     RetentionRecordType = "notifications"      // Inbox notifications
+	RetentionJobHistory        RetentionRecordType = "job_history"        // Finalized river_job rows
+	RetentionConsoleRecordings RetentionRecordType = "console_recordings" // Console session recordings and their blobs
+	RetentionAPIUsage          RetentionRecordType = "api_usage"          // Daily API usage per principal and token
+)
+
+// RetentionRecordTypes lists every record type, one purge job each.
+var RetentionRecordTypes = []RetentionRecordType{
+	RetentionEvents,
+	RetentionAuditLogs,
+	RetentionNotifications,
+	RetentionJobHistory,
+	RetentionConsoleRecordings,
+	RetentionAPIUsage,
+}
+
+// retentionBounds is the default window (used until an admin sets one)
+// and the minimum window of a record type.
+type retentionBounds struct {
+	defaultDays int
+	minDays     int
+}
+
+var retentionDefaults = map[RetentionRecordType]retentionBounds{
+	RetentionEvents:            {defaultDays: 180, minDays: 30},
+	RetentionAuditLogs:         {defaultDays: 365, minDays: 365}, // Phase 4 §7: production ≥ 1 year
+	RetentionNotifications:     {defaultDays: 90, minDays: 7},
+	RetentionJobHistory:        {defaultDays: 7, minDays: 1},
+	RetentionConsoleRecordings: {defaultDays: 90, minDays: 30},
+	RetentionAPIUsage:          {defaultDays: 90, minDays: 30},
+}
+
+// SensitiveAuditRetention is kept for sensitive audit actions whatever
+// the audit_logs window (Phase 4 §7: *.delete, approval.*, rbac.*).
+const SensitiveAuditRetention = 3 * 365 * 24 * time.Hour
+
+// SensitiveAuditPatterns are the sensitive audit actions as SQL LIKE
+// patterns, passed to the audit purge.
+var SensitiveAuditPatterns = []string{"%.delete", "approval.%", "rbac.%"}
+
+// Known reports whether t is a record type with a purge job.
+func (t RetentionRecordType) Known() bool {
+	_, ok := retentionDefaults[t]
+	return ok
+}
+
+// RetentionPolicy is the window of one record type (retention_policies).
+// Records older than the window are purged unless under legal hold.
+type RetentionPolicy struct {
+	RecordType RetentionRecordType `json:"record_type"`
+	WindowDays int                 `json:"window_days"`
+	MinDays    int                 `json:"min_days"`             // Floor, not stored
+	Default    bool                `json:"default"`              // No admin setting yet
+	UpdatedBy  string              `json:"updated_by,omitempty"` // Empty for defaults
+	UpdatedAt  time.Time           `json:"updated_at,omitempty"`
+}
+
+// DefaultRetentionPolicy returns the built-in policy of t.
+func DefaultRetentionPolicy(t RetentionRecordType) RetentionPolicy {
+	b := retentionDefaults[t]
+	return RetentionPolicy{RecordType: t, WindowDays: b.defaultDays, MinDays: b.minDays, Default: true}
+}
+
+// Window returns the retention window.
+func (p RetentionPolicy) Window() time.Duration {
+	return time.Duration(p.WindowDays) * 24 * time.Hour
+}
+
+// Cutoff returns, as of now, the time before which records are purged.
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.Add(-p.Window())
+}
+
+// Validate checks a policy before it is saved: the window may be raised
+// freely but never set below the record type's minimum.
+func (p RetentionPolicy) Validate() error {
+	b, ok := retentionDefaults[p.RecordType]
+	if !ok {
+		return fmt.Errorf("record type %q: %w", p.RecordType, ErrUnknownRecordType)
+	}
+	if p.WindowDays < b.minDays {
+		return fmt.Errorf("%s window %d days, minimum %d: %w", p.RecordType, p.WindowDays, b.minDays, ErrRetentionBelowMinimum)
+	}
+	return nil
+}
+
+// Legal hold resource types, named as audit log resource types.
+const (
+	HoldResourceVM       = "vm"
+	HoldResourceService  = "service"  // Also covers the Service's VMs
+	HoldResourceSystem   = "system"   // Also covers the System's Services' VMs
+	HoldResourceApproval = "approval" // The ticket, its event and notifications
+	HoldResourceUser     = "user"     // Records the user acted in or received
+)
+
+var holdResourceTypes = map[string]bool{
+	HoldResourceVM:       true,
+	HoldResourceService:  true,
+	HoldResourceSystem:   true,
+	HoldResourceApproval: true,
+	HoldResourceUser:     true,
+}
+
+// LegalHold exempts a resource from retention purges (legal_holds).
+// Released holds are kept as history; at most one active hold exists
+// per resource.
+type LegalHold struct {
+	ID           string     `json:"id"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   string     `json:"resource_id"`
+	Reason       string     `json:"reason"`
+	CaseRef      string     `json:"case_ref,omitempty"` // External case or matter number
+	PlacedBy     string     `json:"placed_by"`
+	PlacedAt     time.Time  `json:"placed_at"`
+	ReleasedBy   string     `json:"released_by,omitempty"`
+	ReleasedAt   *time.Time `json:"released_at,omitempty"`
+}
+
+// Validate checks a hold before it is placed.
+func (h *LegalHold) Validate() error {
+	if !holdResourceTypes[h.ResourceType] {
+		return fmt.Errorf("resource type %q: %w", h.ResourceType, ErrInvalidLegalHold)
+	}
+	if h.ResourceID == "" {
+		return fmt.Errorf("resource id is required: %w", ErrInvalidLegalHold)
+	}
+	if h.Reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrInvalidLegalHold)
+	}
+	return nil
+}
+
+// Active reports whether the hold still exempts its resource.
+func (h *LegalHold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// ConsoleRecordingStore holds console recording blobs (object storage).
+// The purge deletes the blobs before their console_recordings rows, so a
+// failed delete is retried on the next run instead of orphaning blobs.
+type ConsoleRecordingStore interface {
+	// Delete removes the blobs; keys already gone are not an error.
+	Delete(ctx context.Context, keys []string) error
+}
+
+// Errors
+var (
+	ErrUnknownRecordType     = domainerr.NotFound("NOT_FOUND", "unknown retention record type")
+	ErrRetentionBelowMinimum = domainerr.Invalid("RETENTION_BELOW_MINIMUM", "retention window below minimum")
+	ErrInvalidLegalHold      = domainerr.Invalid("INVALID_LEGAL_HOLD", "invalid legal hold")
+	ErrLegalHoldExists       = domainerr.Conflict("LEGAL_HOLD_EXISTS", "resource already under legal hold")
+	ErrLegalHoldReleased     = domainerr.Conflict("LEGAL_HOLD_RELEASED", "legal hold already released")
+)
@@ -0,0 +1,190 @@
+// Package domain provides domain models.
+//
+// This file defines the runtime requirements report served on
+// /health/requirements: what the platform requires of its database and
+// clusters, what was detected, and whether the installation meets it.
+// Deployment tooling polls it after an install or upgrade (e.g. a Helm
+// test) and proceeds once Ready is true.
+//
+// Required requirements decide Ready; recommended ones only disable the
+// platform features that depend on them (capability matrix, ADR-0014).
+// Cluster values come from the last capability detection, not a live
+// call, so polling does not reach the clusters; a cluster never detected
+// reports unknown and keeps the report not ready until detection runs.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequirementLevel is how much a requirement matters.
+type RequirementLevel string
+
+const (
+	RequirementRequired    RequirementLevel = "required"    // Unmet: the platform does not work
+	RequirementRecommended RequirementLevel = "recommended" // Unmet: some features are unavailable
+)
+
+// RequirementStatus is the outcome of one requirement.
+type RequirementStatus string
+
+const (
+	RequirementMet     RequirementStatus = "met"
+	RequirementUnmet   RequirementStatus = "unmet"
+	RequirementUnknown RequirementStatus = "unknown" // Not detected yet
+)
+
+// Requirement is one required-vs-detected line of the report.
+type Requirement struct {
+	Name     string            `json:"name"` // e.g. "postgresql", "extension:plpgsql", "feature_gate:Snapshot"
+	Level    RequirementLevel  `json:"level"`
+	Required string            `json:"required"`
+	Detected string            `json:"detected,omitempty"`
+	Status   RequirementStatus `json:"status"`
+	Message  string            `json:"message,omitempty"` // Consequence when unmet, or a note when met
+}
+
+// ClusterRequirements are the requirements of one cluster.
+type ClusterRequirements struct {
+	Cluster      string        `json:"cluster"`
+	DetectedAt   *time.Time    `json:"detected_at,omitempty"` // Capability detection the values come from
+	Requirements []Requirement `json:"requirements"`
+}
+
+// RuntimeRequirements is the report. Ready is true when every required
+// requirement is met.
+type RuntimeRequirements struct {
+	Ready     bool                  `json:"ready"`
+	CheckedAt time.Time             `json:"checked_at"`
+	Database  []Requirement         `json:"database"`
+	Clusters  []ClusterRequirements `json:"clusters"`
+}
+
+// MinPostgreSQLMajor is the oldest supported PostgreSQL major version
+// (DEPENDENCIES.md).
+const MinPostgreSQLMajor = 18
+
+// RequiredPostgreSQLExtensions must be installed in the database. The
+// schema uses no contrib extension; plpgsql backs its triggers (e.g. VM
+// status history).
+var RequiredPostgreSQLExtensions = []string{"plpgsql"}
+
+// NewRuntimeRequirements assembles the report and computes Ready.
+func NewRuntimeRequirements(database []Requirement, clusters []ClusterRequirements, checkedAt time.Time) *RuntimeRequirements {
+	r := &RuntimeRequirements{Ready: true, CheckedAt: checkedAt, Database: database, Clusters: clusters}
+	all := append([]Requirement(nil), database...)
+	for _, c := range clusters {
+		all = append(all, c.Requirements...)
+	}
+	for _, req := range all {
+		if req.Level == RequirementRequired && req.Status != RequirementMet {
+			r.Ready = false
+		}
+	}
+	return r
+}
+
+// DatabaseRequirements checks the server version (server_version_num,
+// e.g. 180001) and the installed extensions.
+func DatabaseRequirements(serverVersionNum int, serverVersion string, extensions []string) []Requirement {
+	version := Requirement{
+		Name:     "postgresql",
+		Level:    RequirementRequired,
+		Required: fmt.Sprintf(">= %d", MinPostgreSQLMajor),
+		Detected: serverVersion,
+		Status:   RequirementMet,
+	}
+	if serverVersionNum/10000 < MinPostgreSQLMajor {
+		version.Status = RequirementUnmet
+		version.Message = "upgrade PostgreSQL"
+	}
+	reqs := []Requirement{version}
+
+	for _, ext := range RequiredPostgreSQLExtensions {
+		req := Requirement{Name: "extension:" + ext, Level: RequirementRequired, Required: "installed", Status: RequirementMet, Detected: "installed"}
+		if !containsString(extensions, ext) {
+			req.Status, req.Detected = RequirementUnmet, "missing"
+			req.Message = fmt.Sprintf("CREATE EXTENSION %s, then re-run migrations", ext)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// KubeVirtRequirements checks a cluster's detected capabilities (nil if
+// never detected) against the supported KubeVirt range, CDI and the
+// feature gates of the capability matrix.
+func KubeVirtRequirements(caps *ClusterCapabilities) []Requirement {
+	supported := MinSupportedKubeVirt + " - " + MaxSupportedKubeVirt
+	gates := matrixFeatureGates()
+	if caps == nil {
+		reqs := []Requirement{
+			{Name: "kubevirt", Level: RequirementRequired, Required: supported, Status: RequirementUnknown, Message: "capabilities not detected yet"},
+			{Name: "cdi", Level: RequirementRecommended, Required: "installed", Status: RequirementUnknown},
+		}
+		for _, gate := range gates {
+			reqs = append(reqs, Requirement{Name: "feature_gate:" + gate.name, Level: RequirementRecommended, Required: "enabled", Status: RequirementUnknown})
+		}
+		return reqs
+	}
+
+	kv := Requirement{Name: "kubevirt", Level: RequirementRequired, Required: supported, Detected: caps.KubeVirtVersion, Status: RequirementMet}
+	minor := minorOf(caps.KubeVirtVersion)
+	switch {
+	case minor == "":
+		kv.Status, kv.Message = RequirementUnmet, "unparseable version"
+	case compareMinor(minor, MinSupportedKubeVirt) < 0:
+		kv.Status, kv.Message = RequirementUnmet, "upgrade KubeVirt"
+	case compareMinor(minor, MaxSupportedKubeVirt) > 0:
+		kv.Message = "newer than tested " + MaxSupportedKubeVirt
+	}
+
+	cdi := Requirement{Name: "cdi", Level: RequirementRecommended, Required: "installed", Detected: caps.CDIVersion, Status: RequirementMet}
+	if tested, ok := cdiCompatibility[minor]; ok {
+		cdi.Required = strings.Join(tested, " or ")
+	}
+	if caps.CDIVersion == "" {
+		cdi.Status, cdi.Detected, cdi.Message = RequirementUnmet, "missing", "snapshot and export unavailable"
+	} else if tested, ok := cdiCompatibility[minor]; ok && !containsString(tested, minorOf(caps.CDIVersion)) {
+		cdi.Message = "not tested with KubeVirt " + caps.KubeVirtVersion
+	}
+
+	reqs := []Requirement{kv, cdi}
+	for _, gate := range gates {
+		req := Requirement{Name: "feature_gate:" + gate.name, Level: RequirementRecommended, Required: "enabled", Detected: "enabled", Status: RequirementMet}
+		if !containsString(caps.EnabledFeatures, gate.name) {
+			req.Status, req.Detected = RequirementUnmet, "disabled"
+			req.Message = "unavailable: " + strings.Join(gate.features, ", ")
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+type featureGate struct {
+	name     string
+	features []string // Platform features needing the gate
+}
+
+// matrixFeatureGates lists the KubeVirt feature gates of the capability
+// matrix, sorted, with the platform features that need each.
+func matrixFeatureGates() []featureGate {
+	byGate := map[string][]string{}
+	for f, req := range featureRequirements {
+		for _, gate := range req.Features {
+			byGate[gate] = append(byGate[gate], string(f))
+		}
+	}
+	gates := make([]featureGate, 0, len(byGate))
+	for name, features := range byGate {
+		sort.Strings(features)
+		gates = append(gates, featureGate{name: name, features: features})
+	}
+	sort.Slice(gates, func(i, j int) bool { return gates[i].name < gates[j].name })
+	return gates
+}
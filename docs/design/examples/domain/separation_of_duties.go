@@ -0,0 +1,136 @@
+// Package domain provides domain models.
+//
+// This file defines separation-of-duties (SoD) rules for approvals.
+//
+// Every approval passes the same checks, whichever path (UI, bulk, external
+// callback) it arrives from. A failed check is a typed *SoDViolation, so
+// callers can map it to 403 and the audit trail can record which rule fired.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// SoDRule identifies which separation-of-duties rule rejected an approval.
+type SoDRule string
+
+const (
+	SoDRuleSelfApproval SoDRule = "self_approval"         // approver == requester
+	SoDRuleNotAssigned  SoDRule = "not_assigned"          // not in approval_ticket_approvers
+	SoDRuleMissingRole  SoDRule = "missing_approval_role" // binding revoked/expired since assignment
+	SoDRuleRepeat       SoDRule = "repeat_approver"       // already approved an earlier stage
+)
+
+// ApprovalAttempt is the input to the SoD checks.
+type ApprovalAttempt struct {
+	TicketID    string
+	ServiceID   string
+	RequesterID string
+	ApproverID  string // The person acting, also when delegated
+
+	// OnBehalfOf: the delegator whose assignment the approver uses (see
+	// Delegation); empty when acting on their own assignment.
+	OnBehalfOf string
+
+	// Assigned: approver, or the delegator, appears in
+	// approval_ticket_approvers for the ticket's current stage.
+	Assigned bool
+
+	// ApprovedEarlierStage: approver or delegator already approved an
+	// earlier stage. Each stage needs different people (team lead ≠
+	// platform admin).
+	ApprovedEarlierStage bool
+
+	// Permission: live approval:approve check on the Service (global or
+	// inherited owner/admin binding) of the approver or, when delegated,
+	// the delegator. Re-checked at approval time because assignment is
+	// frozen at submission.
+	Permission *Permission
+}
+
+// SoDViolation is the typed error returned when an approval is rejected.
+type SoDViolation struct {
+	Rule        SoDRule `json:"rule"`
+	TicketID    string  `json:"ticket_id"`
+	ApproverID  string  `json:"approver_id"`
+	OnBehalfOf  string  `json:"on_behalf_of,omitempty"`
+	RequesterID string  `json:"requester_id"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+func (v *SoDViolation) Error() string {
+	return fmt.Sprintf("separation of duties: %s (ticket %s, approver %s)", v.Rule, v.TicketID, v.ApproverID)
+}
+
+// Is lets callers match on the rule-specific sentinels with errors.Is.
+func (v *SoDViolation) Is(target error) bool {
+	switch target {
+	case ErrSelfApproval:
+		return v.Rule == SoDRuleSelfApproval
+	case ErrNotAssignedApprover:
+		return v.Rule == SoDRuleNotAssigned
+	case ErrMissingApprovalRole:
+		return v.Rule == SoDRuleMissingRole
+	case ErrRepeatApprover:
+		return v.Rule == SoDRuleRepeat
+	}
+	return false
+}
+
+// Unwrap returns ErrSoDViolation with the rule as a param, so handlers
+// write it with writeError (403 SOD_VIOLATION).
+func (v *SoDViolation) Unwrap() error {
+	return ErrSoDViolation.With("rule", v.Rule)
+}
+
+// CheckSeparationOfDuties returns the first violated rule, or nil.
+// Order matters: self-approval is reported even if the user is also unassigned.
+func CheckSeparationOfDuties(a ApprovalAttempt) *SoDViolation {
+	violation := func(rule SoDRule, reason string) *SoDViolation {
+		return &SoDViolation{
+			Rule:        rule,
+			TicketID:    a.TicketID,
+			ApproverID:  a.ApproverID,
+			OnBehalfOf:  a.OnBehalfOf,
+			RequesterID: a.RequesterID,
+			Reason:      reason,
+		}
+	}
+
+	if a.ApproverID == a.RequesterID {
+		return violation(SoDRuleSelfApproval, "")
+	}
+	if a.ApprovedEarlierStage {
+		return violation(SoDRuleRepeat, "")
+	}
+	if !a.Assigned {
+		return violation(SoDRuleNotAssigned, "")
+	}
+	if a.Permission == nil || !a.Permission.Allowed {
+		reason := ""
+		if a.Permission != nil {
+			reason = a.Permission.Reason
+		}
+		return violation(SoDRuleMissingRole, reason)
+	}
+	return nil
+}
+
+// Errors
+var (
+	// ErrMissingApprovalRole is returned when the approver no longer holds an
+	// approval role on the ticket's resource.
+	ErrMissingApprovalRole = errors.New("approver lacks approval role for resource")
+
+	// ErrRepeatApprover is returned when the approver already approved an
+	// earlier stage of the ticket.
+	ErrRepeatApprover = errors.New("approver already approved an earlier stage")
+
+	// ErrSoDViolation is the coded error every *SoDViolation unwraps to.
+	ErrSoDViolation = domainerr.PermissionDenied("SOD_VIOLATION", "separation of duties violated")
+)
@@ -0,0 +1,102 @@
+// Package domain provides domain models.
+//
+// This file defines the signature scheme for machine-to-machine calls:
+// external approval callbacks (Phase 4 §9) and token-authenticated
+// automation. Each caller has a key ID and an HMAC secret (stored
+// AES-256-GCM encrypted, ADR-0019).
+//
+// A signature covers the timestamp, a caller-chosen nonce, the method, the
+// path and the body hash. The timestamp bounds how long a captured request
+// stays valid; the nonce, remembered for that window, makes it single-use.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signed request headers.
+const (
+	HeaderSignatureKeyID = "X-Shepherd-Key-Id"
+	HeaderTimestamp      = "X-Shepherd-Timestamp" // Unix seconds
+	HeaderNonce          = "X-Shepherd-Nonce"     // 16-128 chars, unique per request
+	HeaderSignature      = "X-Shepherd-Signature" // "v1=" + hex(HMAC-SHA256)
+)
+
+const (
+	signatureVersion = "v1="
+	minNonceLen      = 16
+	maxNonceLen      = 128
+)
+
+// SignedRequest is the signed part of an HTTP request.
+type SignedRequest struct {
+	Timestamp int64
+	Nonce     string
+	Method    string
+	Path      string // Path and raw query, as sent
+	Body      []byte
+}
+
+// canonical returns the string the signature is computed over:
+//
+//	timestamp \n nonce \n METHOD \n path \n hex(sha256(body))
+func (r *SignedRequest) canonical() string {
+	sum := sha256.Sum256(r.Body)
+	return strings.Join([]string{
+		strconv.FormatInt(r.Timestamp, 10),
+		r.Nonce,
+		strings.ToUpper(r.Method),
+		r.Path,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+// Sign returns the X-Shepherd-Signature value. Used by Shepherd when
+// calling out, and by integration tests and client SDKs.
+func (r *SignedRequest) Sign(secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(r.canonical()))
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and freshness. Nonce uniqueness is checked
+// separately against the persisted cache, and only after Verify succeeds,
+// so unsigned requests cannot fill the cache.
+//
+// maxSkew applies in both directions: callers with a fast clock are
+// accepted, but the nonce is then remembered for longer (see NonceExpiry).
+func (r *SignedRequest) Verify(secret []byte, signature string, maxSkew time.Duration, now time.Time) error {
+	if len(r.Nonce) < minNonceLen || len(r.Nonce) > maxNonceLen {
+		return ErrSignatureInvalid
+	}
+	// Constant-time compare; also rejects unknown versions
+	if !hmac.Equal([]byte(signature), []byte(r.Sign(secret))) {
+		return ErrSignatureInvalid
+	}
+	ts := time.Unix(r.Timestamp, 0)
+	if ts.Before(now.Add(-maxSkew)) || ts.After(now.Add(maxSkew)) {
+		return ErrRequestExpired
+	}
+	return nil
+}
+
+// NonceExpiry is when a nonce may be forgotten: once the request's
+// timestamp falls out of the accepted window.
+func (r *SignedRequest) NonceExpiry(maxSkew time.Duration) time.Time {
+	return time.Unix(r.Timestamp, 0).Add(maxSkew)
+}
+
+// Errors
+var (
+	ErrSignatureInvalid = errors.New("request signature invalid")
+	ErrRequestExpired   = errors.New("request timestamp outside accepted window")
+	ErrReplayedRequest  = errors.New("request nonce already used")
+)
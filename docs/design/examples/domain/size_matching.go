@@ -0,0 +1,169 @@
+// Package domain provides domain models.
+//
+// This file defines InstanceSize-to-cluster capability matching
+// (ADR-0018 §Cluster Capability Matching). A size's hardware flags are
+// matched against the hardware detected on each cluster (ADR-0014):
+//
+//	requires_gpu        a GPU device the nodes advertise; every model in gpus
+//	                    (or, for older sizes, in spec_overrides devices.gpus)
+//	requires_sriov      an SR-IOV network attachment definition
+//	requires_hugepages  allocatable hugepages of hugepages_size (any size if unset)
+//	dedicated_cpu       a node with the CPU manager (label cpumanager=true)
+//
+// The matcher is used twice: early, when a request is submitted with a
+// size no cluster of its environment can host, and at approval, to filter
+// the admin's cluster dropdown and to check the picked cluster. Detection
+// lags the cluster by up to one refresh interval; the dry run at creation
+// remains the final check.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ClusterHardware is the hardware the schedulable nodes of a cluster
+// offer, detected with the capabilities (ClusterCapabilities.Hardware).
+// A device counts when at least one node has it allocatable.
+type ClusterHardware struct {
+	GPUDevices    []string `json:"gpu_devices,omitempty"`    // Permitted host devices, e.g. "nvidia.com/GA102GL_A10"
+	Hugepages     []string `json:"hugepages,omitempty"`      // Page sizes, e.g. "2Mi", "1Gi"
+	SRIOVNetworks []string `json:"sriov_networks,omitempty"` // SR-IOV NetworkAttachmentDefinitions
+	DedicatedCPU  bool     `json:"dedicated_cpu"`            // A node is labeled cpumanager=true
+}
+
+// gpuOverridePath is where a size names its GPU devices (ADR-0018 mask).
+const gpuOverridePath = "spec.template.spec.domain.devices.gpus"
+
+// SizeRequirements is the hardware a VM of an InstanceSize needs.
+type SizeRequirements struct {
+	GPU           bool     `json:"gpu,omitempty"`
+	GPUDevices    []string `json:"gpu_devices,omitempty"` // Specific models, from gpus or spec_overrides
+	SRIOV         bool     `json:"sriov,omitempty"`
+	Hugepages     bool     `json:"hugepages,omitempty"`
+	HugepagesSize string   `json:"hugepages_size,omitempty"` // Empty: any page size
+	DedicatedCPU  bool     `json:"dedicated_cpu,omitempty"`
+}
+
+// Requirements returns the hardware a VM of the size needs.
+func (i *InstanceSize) Requirements() SizeRequirements {
+	r := SizeRequirements{
+		GPU:          i.RequiresGPU,
+		SRIOV:        i.RequiresSRIOV,
+		Hugepages:    i.RequiresHugepages,
+		DedicatedCPU: i.DedicatedCPU,
+	}
+	if r.Hugepages {
+		r.HugepagesSize = i.HugepagesSize
+	}
+	r.GPUDevices = GPUDeviceNames(i.GPUs)
+	if gpus, ok := i.SpecOverrides[gpuOverridePath].([]interface{}); ok {
+		for _, gpu := range gpus {
+			if g, ok := gpu.(map[string]interface{}); ok {
+				if name, ok := g["deviceName"].(string); ok && name != "" {
+					r.GPUDevices = append(r.GPUDevices, name)
+				}
+			}
+		}
+	}
+	r.GPU = r.GPU || len(r.GPUDevices) > 0
+	return r
+}
+
+// IsZero reports whether the size needs no special hardware, so every
+// cluster can host it.
+func (r SizeRequirements) IsZero() bool {
+	return !r.GPU && !r.SRIOV && !r.Hugepages && !r.DedicatedCPU
+}
+
+// Missing returns what hw lacks for r, empty if it can host the size.
+// hw nil (not detected yet) lacks everything r requires.
+func (r SizeRequirements) Missing(hw *ClusterHardware) []string {
+	if r.IsZero() {
+		return nil
+	}
+	if hw == nil {
+		return []string{"hardware not detected yet"}
+	}
+
+	var missing []string
+	if r.GPU {
+		if len(hw.GPUDevices) == 0 {
+			missing = append(missing, "gpu")
+		}
+		for _, d := range r.GPUDevices {
+			if len(hw.GPUDevices) > 0 && !containsString(hw.GPUDevices, d) {
+				missing = append(missing, "gpu "+d)
+			}
+		}
+	}
+	if r.SRIOV && len(hw.SRIOVNetworks) == 0 {
+		missing = append(missing, "sriov")
+	}
+	if r.Hugepages {
+		switch {
+		case r.HugepagesSize == "" && len(hw.Hugepages) == 0:
+			missing = append(missing, "hugepages")
+		case r.HugepagesSize != "" && !containsString(hw.Hugepages, r.HugepagesSize):
+			missing = append(missing, "hugepages "+r.HugepagesSize)
+		}
+	}
+	if r.DedicatedCPU && !hw.DedicatedCPU {
+		missing = append(missing, "dedicated_cpu")
+	}
+	return missing
+}
+
+// SizeMatch tells whether one cluster can host a size, and if not, why.
+type SizeMatch struct {
+	ClusterID string   `json:"cluster_id"`
+	Cluster   string   `json:"cluster"`
+	Hosts     bool     `json:"hosts"`
+	Missing   []string `json:"missing,omitempty"`
+}
+
+// MatchCluster matches r against c's detected hardware.
+func (r SizeRequirements) MatchCluster(c *Cluster) SizeMatch {
+	var hw *ClusterHardware
+	if c.Capabilities != nil {
+		hw = c.Capabilities.Hardware
+	}
+	missing := r.Missing(hw)
+	return SizeMatch{ClusterID: c.ID, Cluster: c.Name, Hosts: len(missing) == 0, Missing: missing}
+}
+
+// FilterClustersForSize returns the clusters of clusters that can host a
+// size with requirements r, in order. Callers pass placement candidates
+// (FilterPlacementCandidates): the approval dropdown and weight-based
+// selection both only offer clusters that can host the approved size.
+func FilterClustersForSize(r SizeRequirements, clusters []*Cluster) []*Cluster {
+	if r.IsZero() {
+		return clusters
+	}
+	result := make([]*Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		if r.MatchCluster(c).Hosts {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// CheckClusterHostsSize checks that an approver-picked cluster can host
+// the picked size.
+func CheckClusterHostsSize(r SizeRequirements, c *Cluster) error {
+	if m := r.MatchCluster(c); !m.Hosts {
+		return fmt.Errorf("cluster %s lacks %s: %w", c.Name, strings.Join(m.Missing, ", "), ErrClusterLacksCapability)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrClusterLacksCapability = domainerr.Conflict("CLUSTER_LACKS_CAPABILITY", "cluster cannot host the instance size")
+	ErrNoClusterForSize       = domainerr.Unprocessable("NO_CLUSTER_FOR_SIZE", "no cluster of the environment can host the instance size")
+)
@@ -0,0 +1,136 @@
+// Package domain provides domain models.
+//
+// This file defines the field-level diff between a request as submitted
+// and as it will execute after an approver's ModifiedSpec.
+//
+// The diff is computed from the same merge the worker uses
+// (GetEffectiveSpec, effective_spec.go), so reviewers and requesters see
+// exactly what will run, not a re-derived approximation. Fields are compared by JSON name; unchanged fields are
+// left out of Changes.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// SpecChange is one field whose effective value differs from the request.
+// Values are raw JSON; a field absent on one side is null.
+type SpecChange struct {
+	Field    string          `json:"field"` // JSON name, e.g. "memory_mb"
+	Original json.RawMessage `json:"original"`
+	Modified json.RawMessage `json:"modified"`
+}
+
+// SpecDiff is a ticket's original payload, its effective spec and the
+// changed fields. Changes is empty when no ModifiedSpec was set.
+type SpecDiff struct {
+	TicketID       string       `json:"ticket_id"`
+	RequestType    string       `json:"request_type"`
+	Original       any          `json:"original"`
+	Effective      any          `json:"effective"`
+	Changes        []SpecChange `json:"changes"`
+	ModifiedBy     string       `json:"modified_by,omitempty"`
+	ModifiedReason string       `json:"modified_reason,omitempty"`
+}
+
+// DiffSpec returns the SpecDiff for a ticket of requestType from its
+// event's type and payload and its ModifiedSpec (nil if unmodified).
+// Payload types that are not Modifiable return ErrSpecDiffUnsupported.
+func DiffSpec(requestType string, eventType EventType, payload, modifiedSpec []byte) (*SpecDiff, error) {
+	registered, ok := EventPayloads[eventType].(Modifiable)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", requestType, ErrSpecDiffUnsupported)
+	}
+	decoded := reflect.New(reflect.TypeOf(registered))
+	if err := json.Unmarshal(payload, decoded.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", eventType, err)
+	}
+	original := decoded.Elem().Interface().(EventPayload)
+
+	var mods *ModifiedSpec
+	effective := original
+	if modifiedSpec != nil {
+		mods = &ModifiedSpec{}
+		if err := json.Unmarshal(modifiedSpec, mods); err != nil {
+			return nil, fmt.Errorf("decode modified spec: %w", err)
+		}
+		var err error
+		if effective, err = applyModifiedSpec(original, mods); err != nil {
+			return nil, fmt.Errorf("apply modified spec: %w", err)
+		}
+	}
+
+	changes, err := diffFields(original, effective)
+	if err != nil {
+		return nil, err
+	}
+	diff := &SpecDiff{
+		RequestType: requestType,
+		Original:    original,
+		Effective:   effective,
+		Changes:     changes,
+	}
+	if mods != nil {
+		diff.ModifiedBy = mods.ModifiedBy
+		diff.ModifiedReason = mods.ModifiedReason
+	}
+	return diff, nil
+}
+
+// diffFields compares the JSON encodings of a and b field by field, in
+// field name order.
+func diffFields(a, b any) ([]SpecChange, error) {
+	fa, err := jsonFields(a)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := jsonFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(fa)+len(fb))
+	for name := range fa {
+		names = append(names, name)
+	}
+	for name := range fb {
+		if _, ok := fa[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []SpecChange{}
+	for _, name := range names {
+		if bytes.Equal(fa[name], fb[name]) {
+			continue
+		}
+		changes = append(changes, SpecChange{Field: name, Original: fa[name], Modified: fb[name]})
+	}
+	return changes, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decode spec fields: %w", err)
+	}
+	return fields, nil
+}
+
+// Errors
+var (
+	ErrSpecDiffUnsupported = domainerr.Conflict("SPEC_DIFF_UNSUPPORTED", "request type has no modifiable spec")
+)
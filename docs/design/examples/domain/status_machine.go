@@ -0,0 +1,111 @@
+// Package domain provides domain models.
+//
+// This file defines the allowed VMStatus and EventStatus transitions.
+// Status writes go through CheckVMTransition or CheckEventTransition
+// (repository/status_transition.go), whether they come from the watcher,
+// a resync, a worker or a use case, so an out-of-order or stale update is
+// refused instead of silently overwriting a newer state: a late watch
+// event cannot bring a DELETED VM back to RUNNING, a redelivered job
+// cannot move a COMPLETED event back to PROCESSING.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// vmTransitions lists, per status, the statuses a VM may move to besides
+// UNKNOWN and FAILED, which any status except DELETED may move to.
+// UNKNOWN may move anywhere: it is what the platform records when it lost
+// track, and the next observation is the truth.
+var vmTransitions = map[VMStatus][]VMStatus{
+	VMStatusCreating:  {VMStatusPending, VMStatusRunning, VMStatusStopped, VMStatusDeleting},
+	VMStatusPending:   {VMStatusCreating, VMStatusRunning, VMStatusStopped, VMStatusDeleting},
+	VMStatusRunning:   {VMStatusStopping, VMStatusStopped, VMStatusPending, VMStatusMigrating, VMStatusPaused, VMStatusDeleting},
+	VMStatusStopping:  {VMStatusStopped, VMStatusRunning, VMStatusDeleting},
+	VMStatusStopped:   {VMStatusPending, VMStatusRunning, VMStatusDeleting},
+	VMStatusMigrating: {VMStatusRunning, VMStatusStopping, VMStatusPaused, VMStatusDeleting},
+	VMStatusPaused:    {VMStatusRunning, VMStatusStopping, VMStatusStopped, VMStatusDeleting},
+	VMStatusFailed:    {VMStatusPending, VMStatusRunning, VMStatusStopped, VMStatusDeleting},
+	VMStatusDeleting:  {VMStatusDeleted},
+	VMStatusDeleted:   nil, // Terminal
+}
+
+// CheckVMTransition reports whether a VM may move from one status to
+// another. Staying in the same status is always allowed.
+func CheckVMTransition(from, to VMStatus) error {
+	if from == to {
+		return nil
+	}
+	switch {
+	case from == VMStatusDeleted:
+	case from == VMStatusUnknown:
+		return nil
+	case to == VMStatusUnknown, to == VMStatusFailed:
+		return nil
+	default:
+		for _, s := range vmTransitions[from] {
+			if s == to {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("vm %s → %s: %w", from, to, ErrInvalidStatusTransition)
+}
+
+// eventTransitions lists, per status, the statuses an event may move to.
+// COMPLETED and CANCELLED are final. FAILED → PROCESSING is the admin
+// requeue (event_requeue.go), the only way back from a terminal status.
+var eventTransitions = map[EventStatus][]EventStatus{
+	EventStatusPending:    {EventStatusProcessing, EventStatusFailed, EventStatusCancelled},
+	EventStatusProcessing: {EventStatusCompleted, EventStatusFailed, EventStatusCancelled},
+	EventStatusFailed:     {EventStatusProcessing},
+	EventStatusCompleted:  nil,
+	EventStatusCancelled:  nil,
+}
+
+// CheckEventTransition reports whether an event may move from one status
+// to another. Staying in the same status is always allowed, so a
+// redelivered completion is a no-op rather than an error.
+func CheckEventTransition(from, to EventStatus) error {
+	if from == to {
+		return nil
+	}
+	for _, s := range eventTransitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("event %s → %s: %w", from, to, ErrInvalidStatusTransition)
+}
+
+// TransitionSource is the path a status write came from.
+type TransitionSource string
+
+const (
+	TransitionWatcher TransitionSource = "watcher" // Observed on the cluster
+	TransitionResync  TransitionSource = "resync"  // Admin-triggered relist (resync.go)
+	TransitionWorker  TransitionSource = "worker"  // River job executing a request
+	TransitionRequest TransitionSource = "request" // Use case submitting or approving a request
+)
+
+// StatusTransition records one status write, applied or refused. Applied
+// VM transitions are kept in vm_status_history with their source;
+// refused ones are logged with the record.
+type StatusTransition struct {
+	Kind       string           `json:"kind"` // "vm" or "event"
+	ID         string           `json:"id"`   // VM or event ID
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Source     TransitionSource `json:"source"`
+	Refused    bool             `json:"refused,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// Errors
+var (
+	ErrInvalidStatusTransition = errors.New("status transition not allowed")
+)
@@ -0,0 +1,236 @@
+// Package domain provides domain models.
+//
+// This file defines per-System labels and annotations (cost center,
+// environment, compliance tier, ...) propagated to the System's VMs and to
+// the namespaces those VMs run in.
+//
+// System metadata is owner-configured, not user-per-request: the request
+// still cannot carry labels (ADR-0015 §4). Platform-managed keys always win
+// and can never be set through System metadata.
+//
+// Applied keys are recorded on each object (AnnotationSystemLabelKeys /
+// AnnotationSystemAnnotationKeys), so a later change can remove keys the
+// System dropped without touching labels set by anyone else.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Annotations recording which keys came from System metadata.
+const (
+	AnnotationSystemLabelKeys      = "kubevirt-shepherd.io/system-label-keys"
+	AnnotationSystemAnnotationKeys = "kubevirt-shepherd.io/system-annotation-keys"
+)
+
+// reservedPrefixes cannot be set through System metadata.
+var reservedPrefixes = []string{
+	PlatformKeyPrefix,
+	"kubevirt.io/",
+	"kubernetes.io/",
+	"k8s.io/",
+}
+
+var (
+	metadataKeyPattern = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	labelValuePattern  = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// maxAnnotationValueBytes keeps System annotations well under the 256 KiB
+// total K8s allows per object.
+const maxAnnotationValueBytes = 4096
+
+// SystemMetadata is stored one-to-one with System.
+type SystemMetadata struct {
+	SystemID    string            `json:"system_id"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	UpdatedBy   string            `json:"updated_by"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Validate rejects reserved and syntactically invalid keys and values.
+func (m *SystemMetadata) Validate() error {
+	for k, v := range m.Labels {
+		if err := validateMetadataKey(k); err != nil {
+			return err
+		}
+		if !labelValuePattern.MatchString(v) {
+			return fmt.Errorf("label %s value %q: %w", k, v, ErrInvalidMetadata)
+		}
+	}
+	for k, v := range m.Annotations {
+		if err := validateMetadataKey(k); err != nil {
+			return err
+		}
+		if len(v) > maxAnnotationValueBytes {
+			return fmt.Errorf("annotation %s exceeds %d bytes: %w", k, maxAnnotationValueBytes, ErrInvalidMetadata)
+		}
+	}
+	return nil
+}
+
+func validateMetadataKey(key string) error {
+	for _, p := range reservedPrefixes {
+		if strings.HasPrefix(key, p) || strings.Contains(key, "."+p) {
+			return fmt.Errorf("key %s: %w", key, ErrReservedMetadataKey)
+		}
+	}
+	if !metadataKeyPattern.MatchString(key) {
+		return fmt.Errorf("key %s: %w", key, ErrInvalidMetadata)
+	}
+	return nil
+}
+
+// ManagedMetadata is the desired System-owned metadata for one object.
+type ManagedMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Desired returns the metadata to apply to the System's VMs.
+func (m *SystemMetadata) Desired() ManagedMetadata {
+	return ManagedMetadata{Labels: m.Labels, Annotations: m.Annotations}
+}
+
+// MetadataPatch is a merge patch against one object's labels/annotations.
+// Bookkeeping annotations are included in SetAnnotations.
+type MetadataPatch struct {
+	SetLabels         map[string]string `json:"set_labels,omitempty"`
+	RemoveLabels      []string          `json:"remove_labels,omitempty"`
+	SetAnnotations    map[string]string `json:"set_annotations,omitempty"`
+	RemoveAnnotations []string          `json:"remove_annotations,omitempty"`
+}
+
+// Empty reports whether the patch changes nothing.
+func (p *MetadataPatch) Empty() bool {
+	return len(p.SetLabels) == 0 && len(p.RemoveLabels) == 0 &&
+		len(p.SetAnnotations) == 0 && len(p.RemoveAnnotations) == 0
+}
+
+// PlanMetadataPatch computes the patch that moves an object from its
+// current state to desired. Only keys previously applied by Shepherd
+// (per the bookkeeping annotations) are ever removed.
+func PlanMetadataPatch(currentLabels, currentAnnotations map[string]string, desired ManagedMetadata) *MetadataPatch {
+	p := &MetadataPatch{
+		SetLabels:      make(map[string]string),
+		SetAnnotations: make(map[string]string),
+	}
+
+	for k, v := range desired.Labels {
+		if currentLabels[k] != v {
+			p.SetLabels[k] = v
+		}
+	}
+	for _, k := range splitKeys(currentAnnotations[AnnotationSystemLabelKeys]) {
+		if _, keep := desired.Labels[k]; !keep {
+			p.RemoveLabels = append(p.RemoveLabels, k)
+		}
+	}
+
+	for k, v := range desired.Annotations {
+		if currentAnnotations[k] != v {
+			p.SetAnnotations[k] = v
+		}
+	}
+	for _, k := range splitKeys(currentAnnotations[AnnotationSystemAnnotationKeys]) {
+		if _, keep := desired.Annotations[k]; !keep {
+			p.RemoveAnnotations = append(p.RemoveAnnotations, k)
+		}
+	}
+
+	// Bookkeeping, only when it changes
+	if keys := joinKeys(desired.Labels); keys != currentAnnotations[AnnotationSystemLabelKeys] {
+		p.SetAnnotations[AnnotationSystemLabelKeys] = keys
+	}
+	if keys := joinKeys(desired.Annotations); keys != currentAnnotations[AnnotationSystemAnnotationKeys] {
+		p.SetAnnotations[AnnotationSystemAnnotationKeys] = keys
+	}
+	return p
+}
+
+// MergeForNamespace combines the metadata of every System with VMs in a
+// namespace. Namespaces are not owned by Systems (ADR-0017), so a key two
+// Systems disagree on is left out and reported as a conflict.
+func MergeForNamespace(systems []*SystemMetadata) (ManagedMetadata, []string) {
+	merged := ManagedMetadata{
+		Labels:      make(map[string]string),
+		Annotations: make(map[string]string),
+	}
+	conflicted := make(map[string]bool)
+
+	merge := func(dst map[string]string, src map[string]string, kind string) {
+		for k, v := range src {
+			if conflicted[kind+"/"+k] {
+				continue
+			}
+			if existing, ok := dst[k]; ok && existing != v {
+				delete(dst, k)
+				conflicted[kind+"/"+k] = true
+				continue
+			}
+			dst[k] = v
+		}
+	}
+	for _, s := range systems {
+		merge(merged.Labels, s.Labels, "label")
+		merge(merged.Annotations, s.Annotations, "annotation")
+	}
+
+	conflicts := make([]string, 0, len(conflicted))
+	for k := range conflicted {
+		conflicts = append(conflicts, k)
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// ApplyOnCreate merges System metadata into a new object's labels and
+// annotations. Platform labels already present are never overwritten.
+func ApplyOnCreate(labels, annotations map[string]string, desired ManagedMetadata) {
+	for k, v := range desired.Labels {
+		if _, exists := labels[k]; !exists {
+			labels[k] = v
+		}
+	}
+	for k, v := range desired.Annotations {
+		if _, exists := annotations[k]; !exists {
+			annotations[k] = v
+		}
+	}
+	annotations[AnnotationSystemLabelKeys] = joinKeys(desired.Labels)
+	annotations[AnnotationSystemAnnotationKeys] = joinKeys(desired.Annotations)
+}
+
+func joinKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func splitKeys(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// Errors
+
+var (
+	// ErrReservedMetadataKey is returned for keys under platform or K8s prefixes.
+	ErrReservedMetadataKey = errors.New("metadata key uses a reserved prefix")
+
+	// ErrInvalidMetadata is returned for keys or values K8s would reject.
+	ErrInvalidMetadata = errors.New("invalid label or annotation")
+)
@@ -0,0 +1,96 @@
+// Package domain provides domain models.
+//
+// This file defines the VM template (ADR-0007 lifecycle, ADR-0018 scope).
+// Templates carry an OS image source plus cloud-init and manifest bodies
+// rendered by the sandboxed renderer (internal/render).
+//
+// Golden cases pin the expected output for sample variables. Editing a draft
+// shows the diff against each golden case; the admin accepts the new output
+// before publishing, so every published change has been reviewed. Publishing
+// also lints the template (internal/render) and dry-runs it on a reference
+// cluster, so a broken template never reaches users.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// TemplateStatus is the template lifecycle state (ADR-0007).
+type TemplateStatus string
+
+const (
+	TemplateDraft      TemplateStatus = "draft"
+	TemplateActive     TemplateStatus = "active"
+	TemplateDeprecated TemplateStatus = "deprecated"
+	TemplateArchived   TemplateStatus = "archived"
+)
+
+// Template is one version of a named template.
+// Only one version per Name may be active.
+type Template struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Version     int            `json:"version"`
+	Status      TemplateStatus `json:"status"`
+	ImageSource string         `json:"image_source"` // DataVolume / ContainerDisk / PVC reference
+
+	// Image and ImageChannel, when set, replace ImageSource: the channel's
+	// digest is resolved when a VM is created (image_channel.go).
+	Image        string       `json:"image,omitempty"`
+	ImageChannel ImageChannel `json:"image_channel,omitempty"`
+
+	CloudInit string `json:"cloud_init"`         // Rendered into cloudInitNoCloud.userData
+	Manifest  string `json:"manifest,omitempty"` // Optional extra VM manifest fragment
+
+	GoldenCases []TemplateGoldenCase `json:"golden_cases"` // JSONB
+
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// Editable reports whether the template body may still change.
+func (t *Template) Editable() bool {
+	return t.Status == TemplateDraft
+}
+
+// TemplateVars are the values the renderer may substitute.
+type TemplateVars struct {
+	VMName      string `json:"vm_name"`
+	Namespace   string `json:"namespace"`
+	Hostname    string `json:"hostname"`
+	Instance    string `json:"instance"`
+	ServiceName string `json:"service_name"`
+	SystemName  string `json:"system_name"`
+	ClusterName string `json:"cluster_name"`
+	Environment string `json:"environment"`
+}
+
+// TemplateGoldenCase is a reviewed expected output for sample variables.
+type TemplateGoldenCase struct {
+	Name              string       `json:"name"`
+	Vars              TemplateVars `json:"vars"`
+	ExpectedCloudInit string       `json:"expected_cloud_init"`
+	ExpectedManifest  string       `json:"expected_manifest,omitempty"`
+}
+
+// Errors
+
+var (
+	// ErrTemplateNotDraft is returned when editing or publishing a non-draft template.
+	ErrTemplateNotDraft = domainerr.Conflict("TEMPLATE_NOT_DRAFT", "template is not a draft")
+
+	// ErrTemplateNoGoldenCases is returned when publishing without any golden case.
+	ErrTemplateNoGoldenCases = domainerr.Unprocessable("TEMPLATE_NO_GOLDEN_CASES", "template needs at least one golden case before publishing")
+
+	// ErrTemplateGoldenMismatch is returned when publishing with unreviewed output changes.
+	ErrTemplateGoldenMismatch = domainerr.Unprocessable("TEMPLATE_GOLDEN_MISMATCH", "rendered output differs from golden cases; review and accept the diff")
+
+	// ErrTemplateLintFailed is returned when publishing a template that fails
+	// linting or the dry-run on the reference cluster.
+	ErrTemplateLintFailed = domainerr.Unprocessable("TEMPLATE_LINT_FAILED", "template failed validation; see findings")
+)
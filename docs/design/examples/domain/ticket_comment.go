@@ -0,0 +1,90 @@
+// Package domain provides domain models.
+//
+// This file defines comment threads on approval tickets.
+//
+// Approvers ask requesters for clarification on the ticket instead of out
+// of band, so the discussion stays with the decision. Only participants
+// may read or write a thread: the requester, the assigned approvers (any
+// stage), active delegates of a current-stage approver and platform admins.
+// The author's role is captured when the comment is written; later role
+// changes do not rewrite history. Comments are append-only.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxCommentLength bounds a comment body, in characters.
+const MaxCommentLength = 4000
+
+// CommentRole is how the author took part in the ticket.
+type CommentRole string
+
+const (
+	CommentRoleRequester     CommentRole = "requester"
+	CommentRoleApprover      CommentRole = "approver"
+	CommentRoleDelegate      CommentRole = "delegate" // Acting for an assigned approver
+	CommentRolePlatformAdmin CommentRole = "platform_admin"
+)
+
+// TicketComment is one entry of a ticket's thread (ticket_comments).
+type TicketComment struct {
+	ID         string      `json:"id"`
+	TicketID   string      `json:"ticket_id"`
+	AuthorID   string      `json:"author_id"`
+	AuthorRole CommentRole `json:"author_role"`
+	OnBehalfOf string      `json:"on_behalf_of,omitempty"` // Delegator, for delegate comments
+	Body       string      `json:"body"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TicketParticipation is a user's relation to a ticket.
+type TicketParticipation struct {
+	Requester     bool
+	Approver      bool   // Assigned at any stage
+	DelegateOf    string // Current-stage approver the user is an active delegate of
+	PlatformAdmin bool
+}
+
+// Role returns the role the user takes part in as; the most specific
+// relation wins. Non-participants get ErrNotTicketParticipant.
+func (p TicketParticipation) Role() (CommentRole, error) {
+	switch {
+	case p.Requester:
+		return CommentRoleRequester, nil
+	case p.Approver:
+		return CommentRoleApprover, nil
+	case p.DelegateOf != "":
+		return CommentRoleDelegate, nil
+	case p.PlatformAdmin:
+		return CommentRolePlatformAdmin, nil
+	default:
+		return "", ErrNotTicketParticipant
+	}
+}
+
+// NormalizeCommentBody trims body and checks it is non-empty and within
+// MaxCommentLength.
+func NormalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("body is empty: %w", ErrInvalidComment)
+	}
+	if n := utf8.RuneCountInString(body); n > MaxCommentLength {
+		return "", fmt.Errorf("body has %d characters, limit %d: %w", n, MaxCommentLength, ErrInvalidComment)
+	}
+	return body, nil
+}
+
+// Errors
+var (
+	ErrInvalidComment       = domainerr.Invalid("INVALID_COMMENT", "invalid comment")
+	ErrNotTicketParticipant = domainerr.PermissionDenied("NOT_TICKET_PARTICIPANT", "only the requester, approvers and platform admins can take part in a ticket's comments")
+)
@@ -0,0 +1,89 @@
+// Package domain provides domain models.
+//
+// This file defines the expiration of stale pending tickets.
+//
+// A ticket nobody decides on does not stay pending forever: once it is
+// older than the TTL for its request type it becomes EXPIRED and its event
+// CANCELLED, exactly like a cancellation by the system, and the requester
+// is told to resubmit if still needed. The SLA (approval_sla.go) escalates
+// first; expiry is the end of the road.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultTicketTTL applies to request types without their own TTL.
+const DefaultTicketTTL = 14 * 24 * time.Hour
+
+// TicketTTL is how long tickets may stay PENDING_APPROVAL, measured from
+// submission (governance.ticket_ttl).
+type TicketTTL struct {
+	Default       time.Duration
+	ByRequestType map[string]time.Duration // e.g. "DELETE_VM": 72h
+}
+
+// For returns the TTL of requestType.
+func (t TicketTTL) For(requestType string) time.Duration {
+	if ttl, ok := t.ByRequestType[requestType]; ok {
+		return ttl
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return DefaultTicketTTL
+}
+
+// Cutoffs returns, as of now, the submission time before which pending
+// tickets have expired: one per configured request type (sorted, as
+// parallel slices for the sqlc query) and one for all other types.
+func (t TicketTTL) Cutoffs(now time.Time) (types []string, cutoffs []time.Time, other time.Time) {
+	for requestType := range t.ByRequestType {
+		types = append(types, requestType)
+	}
+	sort.Strings(types)
+	for _, requestType := range types {
+		cutoffs = append(cutoffs, now.Add(-t.For(requestType)))
+	}
+	return types, cutoffs, now.Add(-t.For(""))
+}
+
+// Validate rejects non-positive TTLs: expiry cannot be switched off per
+// type, only made long.
+func (t TicketTTL) Validate() error {
+	if t.Default < 0 {
+		return fmt.Errorf("default ttl %s: %w", t.Default, ErrInvalidTicketTTL)
+	}
+	for requestType, ttl := range t.ByRequestType {
+		if ttl <= 0 {
+			return fmt.Errorf("%s ttl %s: %w", requestType, ttl, ErrInvalidTicketTTL)
+		}
+	}
+	return nil
+}
+
+// RequestExpiredPayload is the payload of REQUEST_EXPIRED, recorded when
+// a pending ticket expires. The expired request's own event is set
+// CANCELLED in the same transaction.
+type RequestExpiredPayload struct {
+	TicketID    string        `json:"ticket_id"`
+	EventID     string        `json:"event_id"` // The expired request's event
+	RequestType string        `json:"request_type"`
+	SubmittedAt time.Time     `json:"submitted_at"`
+	TTL         time.Duration `json:"ttl"`
+}
+
+// Validate requires the ticket and its event.
+func (p RequestExpiredPayload) Validate() error {
+	return requireFields("ticket_id", p.TicketID, "event_id", p.EventID, "request_type", p.RequestType)
+}
+
+// Errors
+var (
+	ErrInvalidTicketTTL = errors.New("ticket ttl must be positive")
+)
@@ -0,0 +1,173 @@
+// Package domain provides domain models.
+//
+// This file defines usage trends: the VM count, vCPU, memory and storage
+// of each System and Service over time, so capacity owners see growth
+// without exporting data to a BI tool.
+//
+// A periodic job snapshots the live usage of every Service once per day
+// (usage_snapshots, one row per Service and day, overwritten until the
+// day ends). System figures are the sum of their Services' rows for the
+// same day. Usage counts VMs not DELETED, like the quota check
+// (approval_precondition.go), without HELD reservations: a trend shows
+// what exists, not what was approved.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// UsageScope is what a trend is computed for.
+type UsageScope string
+
+const (
+	UsageScopeSystem  UsageScope = "system"
+	UsageScopeService UsageScope = "service"
+)
+
+// UsageGranularity is the spacing of the points of a trend.
+type UsageGranularity string
+
+const (
+	UsageDaily   UsageGranularity = "day"
+	UsageWeekly  UsageGranularity = "week" // ISO weeks, starting Monday
+	UsageMonthly UsageGranularity = "month"
+)
+
+// ParseUsageScope validates ?scope=.
+func ParseUsageScope(s string) (UsageScope, error) {
+	switch scope := UsageScope(s); scope {
+	case UsageScopeSystem, UsageScopeService:
+		return scope, nil
+	}
+	return "", fmt.Errorf("scope must be system or service: %w", ErrInvalidUsageQuery)
+}
+
+// ParseUsageGranularity validates ?granularity=, daily by default.
+func ParseUsageGranularity(s string) (UsageGranularity, error) {
+	switch g := UsageGranularity(s); g {
+	case "":
+		return UsageDaily, nil
+	case UsageDaily, UsageWeekly, UsageMonthly:
+		return g, nil
+	}
+	return "", fmt.Errorf("granularity must be day, week or month: %w", ErrInvalidUsageQuery)
+}
+
+// UsageDay is the snapshot day t falls on (UTC midnight).
+func UsageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart is the start of the period of g that day falls in.
+func (g UsageGranularity) periodStart(day time.Time) time.Time {
+	switch g {
+	case UsageWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+		return day.AddDate(0, 0, -offset)
+	case UsageMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// UsagePoint is the usage of a scope at one point in time.
+type UsagePoint struct {
+	Period   time.Time `json:"period"` // Day, or start of the week/month
+	VMCount  int       `json:"vm_count"`
+	CPU      int       `json:"cpu"`
+	MemoryMB int       `json:"memory_mb"`
+	DiskGB   int       `json:"disk_gb"`
+}
+
+// UsageGrowth compares the last point of a trend with the first.
+type UsageGrowth struct {
+	VMCount  int `json:"vm_count"`
+	CPU      int `json:"cpu"`
+	MemoryMB int `json:"memory_mb"`
+	DiskGB   int `json:"disk_gb"`
+
+	// Relative growth in percent; nil when the first point is zero.
+	CPUPercent    *float64 `json:"cpu_percent"`
+	MemoryPercent *float64 `json:"memory_percent"`
+	DiskPercent   *float64 `json:"disk_percent"`
+}
+
+// UsageTrend is the report for one System or Service.
+type UsageTrend struct {
+	Scope       UsageScope       `json:"scope"`
+	ScopeID     string           `json:"scope_id"`
+	Granularity UsageGranularity `json:"granularity"`
+	Window      AnalyticsWindow  `json:"window"`
+	Points      []UsagePoint     `json:"points"`
+	Growth      UsageGrowth      `json:"growth"`
+}
+
+// NewUsageTrend assembles a trend from daily points sorted by day. Usage
+// is a level, not a flow: a week or month shows its last day, never a sum.
+// Days without a snapshot (job down) are skipped, not shown as zero.
+func NewUsageTrend(scope UsageScope, scopeID string, g UsageGranularity, w AnalyticsWindow, daily []UsagePoint) *UsageTrend {
+	t := &UsageTrend{Scope: scope, ScopeID: scopeID, Granularity: g, Window: w, Points: []UsagePoint{}}
+	for _, p := range daily {
+		p.Period = g.periodStart(p.Period)
+		if n := len(t.Points); n > 0 && t.Points[n-1].Period.Equal(p.Period) {
+			t.Points[n-1] = p
+			continue
+		}
+		t.Points = append(t.Points, p)
+	}
+
+	if len(t.Points) > 1 {
+		first, last := t.Points[0], t.Points[len(t.Points)-1]
+		t.Growth = UsageGrowth{
+			VMCount:       last.VMCount - first.VMCount,
+			CPU:           last.CPU - first.CPU,
+			MemoryMB:      last.MemoryMB - first.MemoryMB,
+			DiskGB:        last.DiskGB - first.DiskGB,
+			CPUPercent:    growthPercent(first.CPU, last.CPU),
+			MemoryPercent: growthPercent(first.MemoryMB, last.MemoryMB),
+			DiskPercent:   growthPercent(first.DiskGB, last.DiskGB),
+		}
+	}
+	return t
+}
+
+func growthPercent(from, to int) *float64 {
+	if from == 0 {
+		return nil
+	}
+	p := float64(to-from) / float64(from) * 100
+	return &p
+}
+
+// UsageCSVHeader is the header row of the CSV export.
+var UsageCSVHeader = []string{"scope", "scope_id", "period", "vm_count", "cpu", "memory_mb", "disk_gb"}
+
+// CSVRecords returns the points as CSV rows, header first.
+func (t *UsageTrend) CSVRecords() [][]string {
+	records := make([][]string, 0, len(t.Points)+1)
+	records = append(records, UsageCSVHeader)
+	for _, p := range t.Points {
+		records = append(records, []string{
+			string(t.Scope),
+			t.ScopeID,
+			p.Period.Format(time.DateOnly),
+			strconv.Itoa(p.VMCount),
+			strconv.Itoa(p.CPU),
+			strconv.Itoa(p.MemoryMB),
+			strconv.Itoa(p.DiskGB),
+		})
+	}
+	return records
+}
+
+// Errors
+var (
+	ErrInvalidUsageQuery = domainerr.Invalid("INVALID_USAGE_QUERY", "invalid usage query")
+)
@@ -0,0 +1,134 @@
+// Package domain provides domain models.
+//
+// This file defines user data export and anonymization (GDPR-style
+// access and erasure requests, HR offboarding).
+//
+// Records reference users by users.id, an opaque key. Anonymization keeps
+// that key, so tickets, approvals, events and audit logs stay linked and
+// still count (e.g. separation of duties on historical tickets), and
+// erases what identifies the person: username, email, display name, IdP
+// subject, and the name, IP and user agent copied into audit logs.
+// Anonymization is irreversible and refused while the user is under
+// legal hold (retention.go).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Export sections, in document order. profile is an object; every other
+// section is a JSON array of the user's records of that kind.
+const (
+	ExportSectionProfile        = "profile"
+	ExportSectionRoleBindings   = "role_bindings"
+	ExportSectionRequests       = "requests"      // Tickets the user submitted, with their event payloads
+	ExportSectionDecisions      = "decisions"     // Approvals and rejections the user gave
+	ExportSectionComments       = "comments"      // Ticket comments the user wrote
+	ExportSectionDelegations    = "delegations"   // Given and received
+	ExportSectionNotifications  = "notifications" // The user's inbox
+	ExportSectionChatIdentities = "chat_identities"
+	ExportSectionAuditLogs      = "audit_logs" // Actions the user performed
+)
+
+// ExportSections lists the sections of a user data export.
+var ExportSections = []string{
+	ExportSectionProfile,
+	ExportSectionRoleBindings,
+	ExportSectionRequests,
+	ExportSectionDecisions,
+	ExportSectionComments,
+	ExportSectionDelegations,
+	ExportSectionNotifications,
+	ExportSectionChatIdentities,
+	ExportSectionAuditLogs,
+}
+
+// AnonymizedDisplayName replaces the display name of anonymized users,
+// in users and in audit_logs.actor_name.
+const AnonymizedDisplayName = "Former user"
+
+// AnonymizedUsername returns the username given to an anonymized user:
+// unique (users.username is), derived from the opaque ID only.
+func AnonymizedUsername(userID string) string {
+	if len(userID) > 8 {
+		userID = userID[:8]
+	}
+	return "former-user-" + userID
+}
+
+// AnonymizeUserRequest is the confirmation an admin gives to anonymize a
+// user. Confirm must repeat the user's current username, as deletion
+// confirmation repeats the VM name.
+type AnonymizeUserRequest struct {
+	Confirm string `json:"confirm"`
+	Reason  string `json:"reason"` // e.g. HR offboarding ticket, erasure request reference
+}
+
+// Validate checks the request against the user's current username.
+func (r AnonymizeUserRequest) Validate(username string) error {
+	if r.Reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrAnonymizeNotConfirmed)
+	}
+	if r.Confirm != username {
+		return fmt.Errorf("confirm must be the username: %w", ErrAnonymizeNotConfirmed)
+	}
+	return nil
+}
+
+// ScrubJSON replaces every string value equal to one of erased (whole
+// values only), recursively through objects and arrays. Same rule as the
+// scrub_jsonb SQL function, for compressed event payloads, which SQL
+// cannot read.
+func ScrubJSON(doc []byte, erased []string, replacement string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber() // Keep numbers exactly as written
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	erased = slices.DeleteFunc(slices.Clone(erased), func(s string) bool { return s == "" })
+	return json.Marshal(scrubValue(v, erased, replacement))
+}
+
+func scrubValue(v any, erased []string, replacement string) any {
+	switch v := v.(type) {
+	case string:
+		if slices.Contains(erased, v) {
+			return replacement
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = scrubValue(e, erased, replacement)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = scrubValue(e, erased, replacement)
+		}
+	}
+	return v
+}
+
+// UserAnonymization reports what an anonymization changed (counts per
+// table), returned to the admin and recorded in the audit log.
+type UserAnonymization struct {
+	UserID       string           `json:"user_id"`
+	Username     string           `json:"username"` // The new, anonymized username
+	AnonymizedAt time.Time        `json:"anonymized_at"`
+	Changed      map[string]int64 `json:"changed"` // e.g. "audit_logs": 1520
+}
+
+// Errors
+var (
+	ErrAnonymizeNotConfirmed = domainerr.Invalid("ANONYMIZE_NOT_CONFIRMED", "anonymization not confirmed")
+	ErrUserUnderLegalHold    = domainerr.Conflict("USER_UNDER_LEGAL_HOLD", "user is under legal hold")
+	ErrUserHasOpenRequests   = domainerr.Conflict("USER_HAS_OPEN_REQUESTS", "user has pending or executing requests")
+	ErrUserAnonymized        = domainerr.Conflict("USER_ANONYMIZED", "user already anonymized")
+)
@@ -6,7 +6,21 @@
 // Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// MaxVMIndex is the highest instance index: the name keeps two digits
+// (ADR-0015 §16).
+const MaxVMIndex = 99
+
+// GenerateVMName returns the platform-generated VM name (ADR-0015 §4):
+// {namespace}-{system}-{service}-{index}, at most 50 characters given the
+// 15-character component limits.
+func GenerateVMName(namespace, systemName, serviceName string, index int) string {
+	return fmt.Sprintf("%s-%s-%s-%02d", namespace, systemName, serviceName, index)
+}
 
 // VMStatus represents the status of a VM.
 // Aligned with master-flow.md §Stage 2.F status definitions.
@@ -42,6 +56,10 @@ type VM struct {
 	Namespace string `json:"namespace"`
 	Cluster   string `json:"cluster"`
 
+	// ResourceVersion is the K8s resourceVersion at read time.
+	// Passed back as VMSpec.ExpectedResourceVersion on update.
+	ResourceVersion string `json:"resource_version,omitempty"`
+
 	// Governance Model (ADR-0015 §3)
 	// NOTE: No SystemID - obtain via ServiceID → Service.Edges.System
 	ServiceID string `json:"service_id"`
@@ -53,12 +71,52 @@ type VM struct {
 	DiskGB   int    `json:"disk_gb,omitempty"`
 	Template string `json:"template,omitempty"`
 
+	// ImageDigest is the image the VM was built from, pinned at creation
+	// (AnnotationImageDigest); empty for unpinned image sources.
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// ImageSource and ProvisionedAt are recorded by the creation worker
+	// for the compliance report (compliance.go); Template is the exact
+	// template version.
+	ImageSource   string     `json:"image_source,omitempty"`
+	ProvisionedAt *time.Time `json:"provisioned_at,omitempty"`
+
+	// InstanceSizeID and InstanceSizeVersion are the size version the
+	// approval pinned, recorded by the creation worker; empty for VMs
+	// sized by explicit resources (instance_size_version.go).
+	InstanceSizeID      string `json:"instance_size_id,omitempty"`
+	InstanceSizeVersion int    `json:"instance_size_version,omitempty"`
+
+	// GPUs are the VM's spec.domain.devices.gpus grouped by model
+	// (gpu.go), as mapped from the cluster and stored in vms.gpus.
+	GPUs []GPUDevice `json:"gpus,omitempty"`
+
+	// Hotplug ceilings (spec.domain.cpu.maxSockets, memory.maxGuest);
+	// zero when the VM was created without them.
+	MaxCPU      int `json:"max_cpu,omitempty"`
+	MaxMemoryMB int `json:"max_memory_mb,omitempty"`
+
+	// Volumes and Interfaces are the VM's actual disks and NICs as mapped
+	// from the cluster (vm_devices.go); IP is PrimaryIP(Interfaces).
+	Volumes    []Volume           `json:"volumes,omitempty"`
+	Interfaces []NetworkInterface `json:"interfaces,omitempty"`
+
+	// Lease is set for VMs created for temporary workloads (vm_lease.go).
+	Lease *VMLease `json:"lease,omitempty"`
+
 	// Status
 	Status        VMStatus `json:"status"`
 	StatusMessage string   `json:"status_message,omitempty"`
 	IP            string   `json:"ip,omitempty"`
 	NodeName      string   `json:"node_name,omitempty"`
 
+	// GuestAgentConnected mirrors the VMI AgentConnected condition.
+	GuestAgentConnected bool `json:"guest_agent_connected"`
+
+	// LiveMigratable mirrors the VMI LiveMigratable condition.
+	// False for VMs with RWO volumes, host devices, or no running VMI.
+	LiveMigratable bool `json:"live_migratable"`
+
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -88,7 +146,45 @@ type VMSpec struct {
 	ServiceID string `json:"service_id"`
 	// NOTE: No SystemID - inferred from ServiceID (ADR-0015 §3)
 	// NOTE: No Labels - platform-managed (ADR-0015 §4)
-	// NOTE: No CloudInit - template-defined only (ADR-0015 §4)
+
+	// SystemMetadata is filled by the worker from the System, never from
+	// the request (json:"-"); applied with ApplyOnCreate at creation.
+	SystemMetadata ManagedMetadata `json:"-"`
+
+	// ImageSource is resolved by the worker from the template, pinned by
+	// digest for channel templates (image_channel.go); ImageDigest is
+	// written as AnnotationImageDigest.
+	ImageSource string `json:"-"`
+	ImageDigest string `json:"-"`
+
+	// InstanceSizeID and InstanceSizeVersion come from the effective
+	// payload and are written as AnnotationInstanceSizeID and
+	// AnnotationInstanceSizeVersion.
+	InstanceSizeID      string `json:"-"`
+	InstanceSizeVersion int    `json:"-"`
+
+	// GPUs come from the effective payload (the approved size's GPUs);
+	// the provider adds one spec.domain.devices.gpus entry per GPU
+	// (ExpandGPUs).
+	GPUs []GPUDevice `json:"-"`
+
+	// AntiAffinityGroup comes from the effective payload. When set, the
+	// provider labels the VM with LabelBatch and requires its pod not to
+	// share a node with another pod of the same LabelBatch value.
+	AntiAffinityGroup string `json:"-"`
+
+	// Ownership is filled by the worker from the ticket and written as
+	// ownership annotations at creation (see ownership.go).
+	Ownership Ownership `json:"-"`
+
+	// ExpectedResourceVersion is the update precondition (see conflict.go).
+	// Ignored by CreateVM; UpdateVM fails with provider.ErrConflict on mismatch.
+	ExpectedResourceVersion string `json:"-"`
+
+	// CloudInit and Manifest are rendered from the template by the worker
+	// (internal/render), never taken from the request (ADR-0015 §4).
+	CloudInit CloudInit `json:"-"`
+	Manifest  string    `json:"-"`
 }
 
 // CloudInit contains cloud-init configuration.
@@ -144,20 +240,24 @@ type Migration struct {
 
 // InstanceType represents a VM instance type.
 type InstanceType struct {
-	Name        string            `json:"name"`
-	Namespace   string            `json:"namespace,omitempty"` // Empty for cluster-scoped
-	CPU         int               `json:"cpu"`
-	MemoryMB    int               `json:"memory_mb"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty"`
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace,omitempty"` // Empty for cluster-scoped
+	CPU           int               `json:"cpu"`
+	MemoryMB      int               `json:"memory_mb"`
+	DedicatedCPU  bool              `json:"dedicated_cpu,omitempty"`
+	HugepagesSize string            `json:"hugepages_size,omitempty"`
+	GPUs          []GPUDevice       `json:"gpus,omitempty"` // spec.gpus, expanded as for a VM
+	Labels        map[string]string `json:"labels,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
 }
 
 // Preference represents a VM preference.
 type Preference struct {
-	Name        string            `json:"name"`
-	Namespace   string            `json:"namespace,omitempty"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty"`
+	Name        string                 `json:"name"`
+	Namespace   string                 `json:"namespace,omitempty"` // Empty for cluster-scoped
+	Spec        map[string]interface{} `json:"spec,omitempty"`      // Passed through, not interpreted
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
 }
 
 // ConsoleConnection contains console connection info.
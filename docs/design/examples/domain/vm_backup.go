@@ -0,0 +1,140 @@
+// Package domain provides domain models.
+//
+// This file defines backup requests: a VM backup taken by the external
+// backup system the platform is configured with (Velero, Kasten or a
+// webhook), as a governed operation like a snapshot (vm_snapshot.go).
+//
+// Shepherd does not copy data itself. The backup provider does, off the
+// cluster; Shepherd names the backup, asks the provider to take it, waits
+// for it and records a restore point. Snapshots completed by the platform
+// record restore points too, so a VM's restore points list both, and
+// both appear on its timeline (vm_timeline.go). Restore requests
+// (vm_restore.go) take a VM back to one of them.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// BackupRequestType is the ticket request type of backup requests.
+// Backups need SnapshotPermission, like snapshots.
+const BackupRequestType = "BACKUP_VM"
+
+// BackupName is the platform-chosen name of the backup requested by
+// eventID: "<vm>-bkp-<first 8 characters of the event ID>", at most 63
+// characters. The provider is asked for this name, so a retried job finds
+// the backup it already started.
+func BackupName(vmName, eventID string) string {
+	return copyName(vmName, "bkp", eventID)
+}
+
+// BackupPayload is the payload of VM_BACKUP_REQUESTED. AggregateID is the
+// VM ID, so a backup in flight blocks deletion and resizing. Provider is
+// the backup provider configured at submission; a backup approved after
+// the configuration changed still goes to it, or fails.
+type BackupPayload struct {
+	VMID       string `json:"vm_id"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Cluster    string `json:"cluster"`
+	ServiceID  string `json:"service_id"`
+	BackupName string `json:"backup_name"`
+	Provider   string `json:"provider"`
+	Reason     string `json:"reason"`
+}
+
+// Validate requires the VM, the backup name and the provider.
+func (p BackupPayload) Validate() error {
+	return requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace,
+		"cluster", p.Cluster, "backup_name", p.BackupName, "provider", p.Provider)
+}
+
+// BackupTarget is what a backup provider is asked to back up.
+type BackupTarget struct {
+	BackupName string            `json:"backup_name"`
+	Cluster    string            `json:"cluster"`
+	Namespace  string            `json:"namespace"`
+	VMName     string            `json:"vm_name"`
+	Labels     map[string]string `json:"labels"` // Ownership labels, so the provider can select the VM's objects
+}
+
+// BackupPhase is the state of an external backup.
+type BackupPhase string
+
+const (
+	BackupInProgress BackupPhase = "in_progress"
+	BackupCompleted  BackupPhase = "completed"
+	BackupFailed     BackupPhase = "failed"
+)
+
+// ExternalBackup is a backup as reported by the backup provider.
+type ExternalBackup struct {
+	Name         string      `json:"name"`
+	Phase        BackupPhase `json:"phase"`
+	ExternalID   string      `json:"external_id,omitempty"` // Provider's ID of the restore point, e.g. a Kasten restore point name
+	SizeBytes    int64       `json:"size_bytes,omitempty"`
+	CompletedAt  *time.Time  `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty"` // Provider retention; nil if kept until deleted
+	ErrorMessage string      `json:"error_message,omitempty"`
+}
+
+// BackupResult is the result of a completed backup request.
+type BackupResult struct {
+	Provider       string     `json:"provider"`
+	BackupName     string     `json:"backup_name"`
+	RestorePointID string     `json:"restore_point_id"`
+	SizeBytes      int64      `json:"size_bytes,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r BackupResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal backup result: %w", err)
+	}
+	return data, nil
+}
+
+// RestorePointSource is where a restore point's data lives.
+type RestorePointSource string
+
+const (
+	RestorePointSnapshot RestorePointSource = "snapshot" // VirtualMachineSnapshot in the VM's cluster
+	RestorePointBackup   RestorePointSource = "backup"   // External backup system
+)
+
+// RestorePoint is a point a VM can be restored to, recorded when the
+// snapshot or backup request that made it completes.
+type RestorePoint struct {
+	ID         string             `json:"id"`
+	VMID       string             `json:"vm_id"`
+	Source     RestorePointSource `json:"source"`
+	Provider   string             `json:"provider,omitempty"` // Backup provider; empty for snapshots
+	Name       string             `json:"name"`               // Snapshot or backup name
+	ExternalID string             `json:"external_id,omitempty"`
+	EventID    string             `json:"event_id"` // Request that made it
+	SizeBytes  int64              `json:"size_bytes,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty"`
+}
+
+// CheckBackupProvider rejects a backup request when no backup provider is
+// configured.
+func CheckBackupProvider(provider string) error {
+	if provider == "" {
+		return fmt.Errorf("set backup.provider: %w", ErrBackupNotConfigured)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrBackupNotConfigured = domainerr.Conflict("BACKUP_NOT_CONFIGURED", "no backup provider configured")
+)
@@ -0,0 +1,140 @@
+// Package domain provides domain models.
+//
+// This file defines clone requests: a new VM copied from a live VM or from
+// one of its snapshots (VirtualMachineClone), taken as a governed
+// operation.
+//
+// A clone is a new VM, so it is governed like a creation: the target
+// Service's environment policy decides, approval rules for CLONE_VM may
+// auto-approve within it, and the clone's size is reserved against the
+// target Service's quota on approval. The clone lands in the source VM's
+// namespace and cluster (KubeVirt clones are namespace-local) and gets a
+// platform-generated name in the target Service (ADR-0015 §4), chosen
+// when the request is submitted so the approver sees it and a retried
+// job finds the clone it already started.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Clone permissions: copying a VM's disks exposes its data, so the source
+// Service needs vm:operate as for snapshots; the target Service needs
+// vm:create as for any new VM.
+const (
+	CloneSourcePermission = "vm:operate"
+	CloneTargetPermission = "vm:create"
+)
+
+// CloneRequestType is the ticket request type of clone requests.
+const CloneRequestType = "CLONE_VM"
+
+// CloneRequest is the body of a clone request.
+type CloneRequest struct {
+	TargetServiceID string `json:"target_service_id"` // Empty: the source VM's Service
+	SnapshotName    string `json:"snapshot_name,omitempty"`
+	Reason          string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *CloneRequest) Validate() error {
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidCloneRequest)
+	}
+	if len(r.SnapshotName) > 63 {
+		return fmt.Errorf("snapshot_name must be at most 63 characters: %w", ErrInvalidCloneRequest)
+	}
+	return nil
+}
+
+// CheckClone rejects a source VM that cannot be cloned in its status. A
+// live clone needs a running or stopped VM; a snapshot only needs the VM
+// to still exist, as its snapshots go with it.
+func CheckClone(status VMStatus, fromSnapshot bool) error {
+	if status == VMStatusDeleting || status == VMStatusDeleted {
+		return fmt.Errorf("cannot clone a %s vm: %w", status, ErrCloneNotAllowed)
+	}
+	if !fromSnapshot && status != VMStatusRunning && status != VMStatusStopped {
+		return fmt.Errorf("cannot clone a %s vm: %w", status, ErrCloneNotAllowed)
+	}
+	return nil
+}
+
+// VirtualMachineClone phases the clone job acts on; others mean still
+// in progress.
+const (
+	ClonePhaseSucceeded = "Succeeded"
+	ClonePhaseFailed    = "Failed"
+)
+
+// ClonePayload is the payload of VM_CLONE_REQUESTED. AggregateID is the
+// source VM ID, so a clone in flight blocks deleting or resizing the
+// source. The size is the source's, reserved against ServiceID.
+type ClonePayload struct {
+	SourceVMID   string `json:"source_vm_id"`
+	SourceName   string `json:"source_name"`
+	SnapshotName string `json:"snapshot_name,omitempty"` // Empty: clone the live VM
+
+	Namespace  string `json:"namespace"`
+	Cluster    string `json:"cluster"`
+	ServiceID  string `json:"service_id"` // Target Service
+	TargetName string `json:"target_name"`
+	Instance   string `json:"instance"` // e.g. "04"
+
+	CPU      int `json:"cpu"`
+	MemoryMB int `json:"memory_mb"`
+	DiskGB   int `json:"disk_gb"`
+
+	// GPUs are the source's GPUs; the clone gets the same.
+	GPUs []GPUDevice `json:"gpus,omitempty"`
+
+	Reason string `json:"reason"`
+}
+
+// Validate requires the source VM and the target.
+func (p ClonePayload) Validate() error {
+	return requireFields("source_vm_id", p.SourceVMID, "namespace", p.Namespace, "cluster", p.Cluster,
+		"service_id", p.ServiceID, "target_name", p.TargetName)
+}
+
+// Spec is the clone as a creation spec, for the environment policy and
+// quota.
+func (p ClonePayload) Spec() *VMCreationPayload {
+	return &VMCreationPayload{
+		ServiceID: p.ServiceID,
+		Namespace: p.Namespace,
+		CPU:       p.CPU,
+		MemoryMB:  p.MemoryMB,
+		DiskGB:    p.DiskGB,
+		GPUs:      p.GPUs,
+		Reason:    p.Reason,
+	}
+}
+
+// CloneResult is the result of a completed clone request.
+type CloneResult struct {
+	VMID string `json:"vm_id"`
+	Name string `json:"name"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r CloneResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal clone result: %w", err)
+	}
+	return data, nil
+}
+
+// Errors
+var (
+	ErrInvalidCloneRequest = domainerr.Invalid("INVALID_REQUEST", "invalid clone request")
+	ErrCloneForbidden      = domainerr.PermissionDenied("CLONE_FORBIDDEN", "clones require vm:operate on the source and vm:create on the target Service")
+	ErrCloneNotAllowed     = domainerr.Conflict("CLONE_NOT_ALLOWED", "clone not allowed in the vm's current status")
+	ErrVMIndexExhausted    = domainerr.Conflict("VM_INDEX_EXHAUSTED", "no instance index left for the Service in this namespace")
+)
@@ -0,0 +1,70 @@
+// Package domain provides domain models.
+//
+// This file defines VM deletion requests: tiered confirmation
+// (ADR-0015 §13.1) and the checks that keep a deletion from racing other
+// operations on the same VM.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// VMDeletionPayload is the payload of VM_DELETION_REQUESTED events.
+// AggregateID is the VM ID, so pending operations can be found per VM.
+type VMDeletionPayload struct {
+	VMID      string `json:"vm_id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	ServiceID string `json:"service_id"`
+	Reason    string `json:"reason"`
+}
+
+// Validate requires the VM being deleted.
+func (p VMDeletionPayload) Validate() error {
+	return requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace,
+		"cluster", p.Cluster, "service_id", p.ServiceID)
+}
+
+// CheckDeleteConfirmation enforces tiered confirmation: test VMs need
+// confirm=true, prod VMs need the VM name typed back exactly.
+func CheckDeleteConfirmation(vm *VM, namespaceEnv string, confirm bool, confirmName string) error {
+	if namespaceEnv == "prod" {
+		if confirmName != vm.Name {
+			return fmt.Errorf("confirm_name must match %s: %w", vm.Name, ErrDeleteNotConfirmed)
+		}
+		return nil
+	}
+	if !confirm {
+		return ErrDeleteNotConfirmed
+	}
+	return nil
+}
+
+// CheckNoOperationInFlight rejects a delete or modify request for a VM
+// that is being deleted or still has operations in flight (pendingOps:
+// PENDING/PROCESSING events on the VM). They would race on the cluster.
+func CheckNoOperationInFlight(status VMStatus, pendingOps int) error {
+	switch status {
+	case VMStatusDeleting, VMStatusDeleted:
+		return ErrVMDeletionInProgress
+	case VMStatusCreating, VMStatusMigrating:
+		return fmt.Errorf("vm is %s: %w", status, ErrVMOperationPending)
+	}
+	if pendingOps > 0 {
+		return fmt.Errorf("%d pending operations: %w", pendingOps, ErrVMOperationPending)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrDeleteNotConfirmed   = errors.New("deletion not confirmed")
+	ErrVMDeletionInProgress = domainerr.Conflict("VM_OPERATION_PENDING", "vm is already being deleted")
+	ErrVMOperationPending   = domainerr.Conflict("VM_OPERATION_PENDING", "vm has pending operations")
+)
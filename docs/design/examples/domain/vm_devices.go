@@ -0,0 +1,93 @@
+// Package domain provides domain models.
+//
+// This file defines the disks and network interfaces of a VM as read from
+// the cluster. They are filled by the provider mapper (provider/mapper.go)
+// and are read-only: VMSpec still carries only CPU, memory and DiskGB.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+// DiskBus is the bus a disk is attached to in the guest.
+type DiskBus string
+
+const (
+	DiskBusVirtio DiskBus = "virtio"
+	DiskBusSATA   DiskBus = "sata"
+	DiskBusSCSI   DiskBus = "scsi"
+	DiskBusUSB    DiskBus = "usb"
+)
+
+// VolumeSource is where a volume's data comes from.
+type VolumeSource string
+
+const (
+	VolumeSourceDataVolume    VolumeSource = "data_volume"
+	VolumeSourcePVC           VolumeSource = "pvc"
+	VolumeSourceContainerDisk VolumeSource = "container_disk"
+	VolumeSourceCloudInit     VolumeSource = "cloud_init"
+	VolumeSourceOther         VolumeSource = "other" // emptyDisk, hostDisk, secret, ...
+)
+
+// Volume is one disk of a VM (spec.template.spec.domain.devices.disks
+// joined with spec.template.spec.volumes by name).
+type Volume struct {
+	Name   string       `json:"name"`
+	Source VolumeSource `json:"source"`
+
+	// ClaimName is the PVC backing the volume (DataVolume or PVC sources).
+	ClaimName string `json:"claim_name,omitempty"`
+
+	// Bus is empty for CD-ROMs and volumes without a disk device.
+	Bus   DiskBus `json:"bus,omitempty"`
+	CDROM bool    `json:"cdrom,omitempty"`
+
+	// SizeBytes is the PVC capacity once bound, else the requested size;
+	// zero for sources without a claim.
+	SizeBytes    int64  `json:"size_bytes,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+
+	// BootOrder is the disk's bootOrder; zero when unset.
+	BootOrder int `json:"boot_order,omitempty"`
+}
+
+// InterfaceBinding is how a NIC is connected to its network.
+type InterfaceBinding string
+
+const (
+	InterfaceBindingMasquerade InterfaceBinding = "masquerade"
+	InterfaceBindingBridge     InterfaceBinding = "bridge"
+	InterfaceBindingSRIOV      InterfaceBinding = "sriov"
+	InterfaceBindingOther      InterfaceBinding = "other"
+)
+
+// PodNetwork is NetworkInterface.Network for the cluster pod network.
+const PodNetwork = "pod"
+
+// NetworkInterface is one NIC of a VM (spec interfaces joined with
+// spec.template.spec.networks and, while running, VMI status.interfaces).
+type NetworkInterface struct {
+	Name string `json:"name"`
+
+	// Network is PodNetwork or the Multus NetworkAttachmentDefinition
+	// ("namespace/name" or "name").
+	Network string           `json:"network"`
+	Binding InterfaceBinding `json:"binding"`
+	Model   string           `json:"model,omitempty"`
+
+	// MAC is the guest-reported MAC, else the one pinned in the spec.
+	MAC string `json:"mac,omitempty"`
+
+	// IPs are reported by the guest agent or DHCP; empty while stopped.
+	IPs []string `json:"ips,omitempty"`
+}
+
+// PrimaryIP returns the first IP of the first interface that has one,
+// which is what VM.IP reports.
+func PrimaryIP(ifaces []NetworkInterface) string {
+	for _, nic := range ifaces {
+		if len(nic.IPs) > 0 {
+			return nic.IPs[0]
+		}
+	}
+	return ""
+}
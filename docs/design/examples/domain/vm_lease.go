@@ -0,0 +1,135 @@
+// Package domain provides domain models.
+//
+// This file defines VM leases: an expiry date on VMs created for
+// temporary workloads (tests, demos, trainings).
+//
+// The lease is requested with the VM and kept on its row. Before expiry
+// the Service owners are warned once; at expiry the platform stops or
+// deletes the VM, as chosen at creation, through the ordinary power and
+// deletion flows with "system" as actor. A renewal moves the expiry later
+// and is a request like any other (RENEW_LEASE ticket, environment policy
+// approvals). It changes nothing on the cluster, so the final approval
+// extends the lease itself and no River job runs.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxLeaseDuration bounds how far ahead a lease may expire, at creation
+// and on renewal. Longer-lived VMs are created without a lease.
+const MaxLeaseDuration = 90 * 24 * time.Hour
+
+// LeaseRenewalPermission is needed on the VM's Service to request a
+// renewal: keeping a VM is requesting it again.
+const LeaseRenewalPermission = "vm:create"
+
+// LeaseRenewalRequestType is the ticket request type of renewals.
+const LeaseRenewalRequestType = "RENEW_LEASE"
+
+// LeaseAction is what happens to the VM at expiry.
+type LeaseAction string
+
+const (
+	LeaseStop   LeaseAction = "stop"   // Kept, stopped; a renewal lets the owner start it again
+	LeaseDelete LeaseAction = "delete" // Deleted, as if requested and approved
+)
+
+// LeaseTerms is the lease requested with a VM (CreateVMRequest.Lease).
+type LeaseTerms struct {
+	ExpiresAt time.Time   `json:"expires_at"`
+	Action    LeaseAction `json:"action"`
+}
+
+// Validate checks the terms at submission time now. nil (no lease) is
+// valid.
+func (t *LeaseTerms) Validate(now time.Time) error {
+	if t == nil {
+		return nil
+	}
+	if t.Action != LeaseStop && t.Action != LeaseDelete {
+		return fmt.Errorf("action must be stop or delete: %w", ErrInvalidLease)
+	}
+	return checkLeaseExpiry(t.ExpiresAt, now, ErrInvalidLease)
+}
+
+// VMLease is the lease of a VM (vms.lease_* columns).
+type VMLease struct {
+	ExpiresAt time.Time   `json:"expires_at"`
+	Action    LeaseAction `json:"action"`
+	WarnedAt  *time.Time  `json:"warned_at,omitempty"`  // Owners warned of the coming expiry
+	ExpiredAt *time.Time  `json:"expired_at,omitempty"` // Action taken; cleared by a renewal
+	Renewals  int         `json:"renewals"`
+}
+
+// LeaseRenewalRequest is the body of a renewal request.
+type LeaseRenewalRequest struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    string    `json:"reason"`
+}
+
+// Validate checks the request against the VM's lease and status at time
+// now.
+func (r *LeaseRenewalRequest) Validate(lease *VMLease, status VMStatus, now time.Time) error {
+	if lease == nil {
+		return ErrNoLease
+	}
+	if status == VMStatusDeleting || status == VMStatusDeleted {
+		return ErrVMDeletionInProgress
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidLeaseRenewal)
+	}
+	if !r.ExpiresAt.After(lease.ExpiresAt) {
+		return fmt.Errorf("expires_at must be after the current expiry %s: %w",
+			lease.ExpiresAt.Format(time.RFC3339), ErrInvalidLeaseRenewal)
+	}
+	return checkLeaseExpiry(r.ExpiresAt, now, ErrInvalidLeaseRenewal)
+}
+
+func checkLeaseExpiry(expiresAt, now time.Time, kind error) error {
+	if !expiresAt.After(now) {
+		return fmt.Errorf("expires_at must be in the future: %w", kind)
+	}
+	if expiresAt.Sub(now) > MaxLeaseDuration {
+		return fmt.Errorf("expires_at must be within %s: %w", MaxLeaseDuration, kind)
+	}
+	return nil
+}
+
+// LeaseRenewalPayload is the payload of VM_LEASE_RENEWAL_REQUESTED.
+// CurrentExpiresAt lets approvers see how much longer the VM would live.
+type LeaseRenewalPayload struct {
+	VMID             string    `json:"vm_id"`
+	Name             string    `json:"name"`
+	Namespace        string    `json:"namespace"`
+	ServiceID        string    `json:"service_id"`
+	CurrentExpiresAt time.Time `json:"current_expires_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	Reason           string    `json:"reason"`
+}
+
+// Validate requires the VM and the new expiry.
+func (p LeaseRenewalPayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace, "service_id", p.ServiceID); err != nil {
+		return err
+	}
+	if p.ExpiresAt.IsZero() {
+		return fmt.Errorf("expires_at is required: %w", ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrInvalidLease          = domainerr.Invalid("INVALID_REQUEST", "invalid lease")
+	ErrInvalidLeaseRenewal   = domainerr.Invalid("INVALID_REQUEST", "invalid lease renewal")
+	ErrNoLease               = domainerr.Conflict("VM_NO_LEASE", "vm has no lease")
+	ErrLeaseRenewalForbidden = domainerr.PermissionDenied("LEASE_RENEWAL_FORBIDDEN", "not allowed to renew the lease of this vm")
+	ErrLeaseRenewalPending   = domainerr.Conflict("LEASE_RENEWAL_PENDING", "a renewal of this lease is already pending")
+)
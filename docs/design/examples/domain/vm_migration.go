@@ -0,0 +1,140 @@
+// Package domain provides domain models.
+//
+// This file defines admin-initiated live migrations of single VMs, e.g.
+// to rebalance a cluster or move a VM off a suspect node without a drain.
+//
+// Migrations keep the VM running and change no resources, so platform
+// admins start them without approval. Each one is tracked in
+// vm_migrations: the worker records the VirtualMachineInstanceMigration it
+// started and its phase on every poll, which is the progress shown to the
+// admin. Cancelling is a request: a migration not started yet is cancelled
+// at once, a running one by the worker on its next poll. A migration that
+// completes first stays completed.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// Migration phases reported by the provider (VirtualMachineInstanceMigration).
+const (
+	MigrationPhaseSucceeded = "Succeeded"
+	MigrationPhaseFailed    = "Failed"
+)
+
+// VMMigrationStatus is the platform-side state of a migration.
+type VMMigrationStatus string
+
+const (
+	VMMigrationPending   VMMigrationStatus = "PENDING"   // Job not started yet
+	VMMigrationRunning   VMMigrationStatus = "RUNNING"   // VMIM created
+	VMMigrationSucceeded VMMigrationStatus = "SUCCEEDED" // VM runs on TargetNode
+	VMMigrationFailed    VMMigrationStatus = "FAILED"    // VM still runs on SourceNode
+	VMMigrationCancelled VMMigrationStatus = "CANCELLED"
+)
+
+// IsTerminal reports whether the migration is finished.
+func (s VMMigrationStatus) IsTerminal() bool {
+	return s == VMMigrationSucceeded || s == VMMigrationFailed || s == VMMigrationCancelled
+}
+
+// VMMigration is one admin-initiated migration (vm_migrations). ID is the
+// ID of its VM_MIGRATION_REQUESTED event.
+type VMMigration struct {
+	ID            string            `json:"id"`
+	VMID          string            `json:"vm_id"`
+	VMName        string            `json:"vm_name"`
+	Namespace     string            `json:"namespace"`
+	Cluster       string            `json:"cluster"`
+	Status        VMMigrationStatus `json:"status"`
+	Phase         string            `json:"phase,omitempty"`          // Provider phase while RUNNING
+	MigrationName string            `json:"migration_name,omitempty"` // VirtualMachineInstanceMigration
+	SourceNode    string            `json:"source_node,omitempty"`
+	TargetNode    string            `json:"target_node,omitempty"`
+	Reason        string            `json:"reason"`
+	RequestedBy   string            `json:"requested_by"`
+	ErrorMessage  string            `json:"error_message,omitempty"`
+
+	// CancelRequestedBy is set once an admin asked to cancel; the worker
+	// cancels the running migration on its next poll.
+	CancelRequestedBy string     `json:"cancel_requested_by,omitempty"`
+	CancelRequestedAt *time.Time `json:"cancel_requested_at,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// MigrationRequest is the body of a migration request.
+type MigrationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *MigrationRequest) Validate() error {
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidMigrationRequest)
+	}
+	return nil
+}
+
+// CheckMigration rejects a VM that cannot live-migrate: it must be running
+// and reported LiveMigratable by KubeVirt (shared storage, no host devices).
+func CheckMigration(vm *VM) error {
+	if vm.Status != VMStatusRunning {
+		return fmt.Errorf("cannot migrate a %s vm: %w", vm.Status, ErrMigrationNotAllowed)
+	}
+	if !vm.LiveMigratable {
+		return fmt.Errorf("vm is not live-migratable: %w", ErrMigrationNotAllowed)
+	}
+	return nil
+}
+
+// MigrationPayload is the payload of a standalone VM_MIGRATION_REQUESTED;
+// node drain items share the event type with NodeDrainItemPayload.
+// AggregateID is the VM ID, so a migration in flight blocks other
+// operations on the VM.
+type MigrationPayload struct {
+	MigrationID string `json:"migration_id"`
+	VMID        string `json:"vm_id"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Cluster     string `json:"cluster"`
+	Reason      string `json:"reason"`
+}
+
+// Validate requires the migration and its VM.
+func (p MigrationPayload) Validate() error {
+	return requireFields("migration_id", p.MigrationID, "vm_id", p.VMID, "name", p.Name,
+		"namespace", p.Namespace, "cluster", p.Cluster)
+}
+
+// MigrationResult is the result of a finished migration event.
+type MigrationResult struct {
+	MigrationName string            `json:"migration_name,omitempty"`
+	Status        VMMigrationStatus `json:"status"`
+	SourceNode    string            `json:"source_node,omitempty"`
+	TargetNode    string            `json:"target_node,omitempty"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r MigrationResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal migration result: %w", err)
+	}
+	return data, nil
+}
+
+// Errors
+var (
+	ErrInvalidMigrationRequest = domainerr.Invalid("INVALID_REQUEST", "invalid migration request")
+	ErrMigrationNotAllowed     = domainerr.Conflict("MIGRATION_NOT_ALLOWED", "live migration not allowed for the vm")
+	ErrMigrationFinished       = domainerr.Conflict("MIGRATION_FINISHED", "migration already finished")
+)
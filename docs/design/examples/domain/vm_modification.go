@@ -0,0 +1,137 @@
+// Package domain provides domain models.
+//
+// This file defines VM resize requests (CPU/memory) and how a resize is
+// applied.
+//
+// KubeVirt applies CPU and memory increases to a running VM by hotplug
+// (VMLiveUpdateFeatures, ADR-0014), which live-migrates the VM onto the
+// new topology. Anything else (decreases, exceeding the hotplug ceiling,
+// no live migration) takes effect only after a restart. The plan is
+// computed at submission and recomputed when an approver modifies the
+// spec, and shown on the ticket so the approver knows if approving causes
+// downtime.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ResizeMode is how a resize is applied.
+type ResizeMode string
+
+const (
+	ResizeLive    ResizeMode = "live"    // Hotplug, no downtime
+	ResizeRestart ResizeMode = "restart" // Applied at the next restart; the worker restarts the VM
+	ResizeOffline ResizeMode = "offline" // VM is stopped; applied at next start
+)
+
+// ResizePlan is the apply mode with the reasons a live resize is not
+// possible (empty for live).
+type ResizePlan struct {
+	Mode    ResizeMode `json:"mode"`
+	Reasons []string   `json:"reasons,omitempty"`
+}
+
+// ToJSON converts the plan to JSON bytes (approval_tickets.resize_plan);
+// nil for a nil plan.
+func (p *ResizePlan) ToJSON() ([]byte, error) {
+	if p == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal resize plan: %w", err)
+	}
+	return data, nil
+}
+
+// PlanResize decides how resizing vm to cpu/memoryMB is applied on a
+// cluster with caps. Zero cpu or memoryMB means unchanged.
+func PlanResize(vm *VM, cpu, memoryMB int, caps *ClusterCapabilities) ResizePlan {
+	if vm.Status == VMStatusStopped {
+		return ResizePlan{Mode: ResizeOffline}
+	}
+
+	var reasons []string
+	if cpu != 0 && cpu != vm.CPU {
+		switch {
+		case cpu < vm.CPU:
+			reasons = append(reasons, "CPU cannot be removed from a running VM")
+		case !caps.Supports(FeatureCPUHotplug):
+			reasons = append(reasons, "cluster: "+caps.Check(FeatureCPUHotplug).Reason)
+		case vm.MaxCPU > 0 && cpu > vm.MaxCPU:
+			reasons = append(reasons, "CPU exceeds the VM's hotplug maximum")
+		}
+	}
+	if memoryMB != 0 && memoryMB != vm.MemoryMB {
+		switch {
+		case memoryMB < vm.MemoryMB:
+			reasons = append(reasons, "memory cannot be removed from a running VM")
+		case !caps.Supports(FeatureMemoryHotplug):
+			reasons = append(reasons, "cluster: "+caps.Check(FeatureMemoryHotplug).Reason)
+		case vm.MaxMemoryMB > 0 && memoryMB > vm.MaxMemoryMB:
+			reasons = append(reasons, "memory exceeds the VM's hotplug maximum")
+		}
+	}
+	// Hotplug is carried out by live migration
+	if len(reasons) == 0 && !vm.LiveMigratable {
+		reasons = append(reasons, "VM is not live-migratable")
+	}
+
+	if len(reasons) > 0 {
+		return ResizePlan{Mode: ResizeRestart, Reasons: reasons}
+	}
+	return ResizePlan{Mode: ResizeLive}
+}
+
+// VMModifyPayload is the payload of VM_MODIFY_REQUESTED events.
+// AggregateID is the VM ID. Approver changes go to ModifiedSpec as for
+// creation; the worker applies the effective CPU/memory with
+// ExpectedResourceVersion as precondition (see conflict.go).
+type VMModifyPayload struct {
+	VMID      string `json:"vm_id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	ServiceID string `json:"service_id"`
+
+	// Current values at submission, for the approver diff and quota delta
+	FromCPU      int `json:"from_cpu"`
+	FromMemoryMB int `json:"from_memory_mb"`
+
+	CPU                     int    `json:"cpu"`
+	MemoryMB                int    `json:"memory_mb"`
+	ExpectedResourceVersion string `json:"expected_resource_version"`
+	Reason                  string `json:"reason"`
+}
+
+// ModifiableFields lets approvers change the requested CPU/memory; the
+// From values and the precondition stay as submitted.
+func (VMModifyPayload) ModifiableFields() []string {
+	return []string{"cpu", "memory_mb"}
+}
+
+// Validate requires the VM and rejects requests that change nothing.
+func (p VMModifyPayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace, "cluster", p.Cluster); err != nil {
+		return err
+	}
+	if p.CPU == p.FromCPU && p.MemoryMB == p.FromMemoryMB {
+		return ErrNoChange
+	}
+	if p.CPU <= 0 || p.MemoryMB <= 0 {
+		return ErrInvalidResize
+	}
+	return nil
+}
+
+// Errors
+var (
+	ErrNoChange      = domainerr.Invalid("INVALID_REQUEST", "modification changes nothing")
+	ErrInvalidResize = domainerr.Invalid("INVALID_REQUEST", "cpu and memory must be positive")
+)
@@ -0,0 +1,146 @@
+// Package domain provides domain models.
+//
+// This file defines power operations on a single VM: start, stop and
+// restart (ADR-0015 §6).
+//
+// Power operations change no resources, so the size limits of the
+// environment policy do not apply. Outside prod they run without approval
+// unless an approval rule for START_VM/STOP_VM/RESTART_VM routes them to
+// a ticket; in prod they always need the policy's approvals (ADR-0015 §7).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// PowerPermission is needed on the VM's Service.
+const PowerPermission = "vm:operate"
+
+// PowerAction is a power operation.
+type PowerAction string
+
+const (
+	PowerStart   PowerAction = "start"
+	PowerStop    PowerAction = "stop"
+	PowerRestart PowerAction = "restart"
+)
+
+// RequestType is the ticket request type of the action.
+func (a PowerAction) RequestType() string {
+	switch a {
+	case PowerStart:
+		return "START_VM"
+	case PowerStop:
+		return "STOP_VM"
+	default:
+		return "RESTART_VM"
+	}
+}
+
+// EventType is the event type requesting the action.
+func (a PowerAction) EventType() EventType {
+	switch a {
+	case PowerStart:
+		return EventVMStartRequested
+	case PowerStop:
+		return EventVMStopRequested
+	default:
+		return EventVMRestartRequested
+	}
+}
+
+// ResultStatus is the VM status once the action succeeded.
+func (a PowerAction) ResultStatus() VMStatus {
+	if a == PowerStop {
+		return VMStatusStopped
+	}
+	return VMStatusRunning
+}
+
+// AuditAction is the audit action recording the result on the VM.
+func (a PowerAction) AuditAction() string {
+	switch a {
+	case PowerStart:
+		return AuditVMStart
+	case PowerStop:
+		return AuditVMStop
+	default:
+		return AuditVMRestart
+	}
+}
+
+// PowerOperationRequest is the body of a power operation request.
+type PowerOperationRequest struct {
+	Action PowerAction `json:"action"`
+	Reason string      `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *PowerOperationRequest) Validate() error {
+	switch r.Action {
+	case PowerStart, PowerStop, PowerRestart:
+	default:
+		return fmt.Errorf("unknown action %q: %w", r.Action, ErrInvalidPowerRequest)
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidPowerRequest)
+	}
+	return nil
+}
+
+// CheckPowerAction rejects an action the VM's status does not allow:
+// only a stopped VM is started, only a running one stopped or restarted.
+func CheckPowerAction(status VMStatus, action PowerAction) error {
+	switch {
+	case action == PowerStart && status == VMStatusStopped:
+		return nil
+	case action != PowerStart && status == VMStatusRunning:
+		return nil
+	}
+	return fmt.Errorf("cannot %s a %s vm: %w", action, status, ErrPowerActionNotAllowed)
+}
+
+// PowerDecision adapts the environment decision for the VM's Service to a
+// power operation: approval outside prod only if a rule asks for it, and
+// a single stage (staged approval is for sizing decisions).
+func PowerDecision(env EnvironmentDecision) EnvironmentDecision {
+	env.AutoApprove = env.Environment != EnvironmentProd
+	env.Stages = DefaultStages(env.RequiredApprovals)
+	env.Staged = false
+	return env
+}
+
+// PowerOperationPayload is the payload of a standalone power operation.
+// AggregateID is the VM ID, so the operation counts as pending on the VM.
+type PowerOperationPayload struct {
+	VMID      string      `json:"vm_id"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Cluster   string      `json:"cluster"`
+	ServiceID string      `json:"service_id"`
+	Action    PowerAction `json:"action"`
+	Reason    string      `json:"reason"`
+}
+
+// Validate requires the VM and a known action.
+func (p PowerOperationPayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace, "cluster", p.Cluster); err != nil {
+		return err
+	}
+	switch p.Action {
+	case PowerStart, PowerStop, PowerRestart:
+		return nil
+	}
+	return fmt.Errorf("unknown action %q: %w", p.Action, ErrInvalidEventPayload)
+}
+
+// Errors
+var (
+	ErrInvalidPowerRequest   = domainerr.Invalid("INVALID_REQUEST", "invalid power operation request")
+	ErrPowerForbidden        = domainerr.PermissionDenied("POWER_FORBIDDEN", "power operations require vm:operate on the Service")
+	ErrPowerActionNotAllowed = domainerr.Conflict("POWER_ACTION_NOT_ALLOWED", "power action not allowed in the vm's current status")
+)
@@ -0,0 +1,231 @@
+// Package domain provides domain models.
+//
+// This file defines cross-cluster relocation (cold migration) of single
+// VMs, run as a supervised sequence of steps recorded in vm_relocations:
+//
+//	PENDING ──► STOPPING_SOURCE ──► COPYING_DISKS ──► CREATING_TARGET ──► AWAITING_CUTOVER ──► CUTTING_OVER ──► RETIRING_SOURCE ──► COMPLETED
+//	   └─────────────┴──────────────────┴──────────────────┴──────────────────┴──► ABORTED / FAILED
+//	                      (copy on the target removed, source started again if it was running)
+//
+// The VM keeps its identity on the target cluster: the same name,
+// namespace, labels, annotations and platform VM ID. What changes is the
+// cluster, the storage behind its disks and possibly its address; the
+// hostname's DNS record follows the address at cutover.
+//
+// Cutover is the point of no return. Before it the source VM is only
+// stopped, so an abort or a failure removes the copy and starts the
+// source again if it was running. From cutover on the platform record
+// names the target; a failure leaves the relocation FAILED for an admin to
+// finish by hand.
+//
+// Decommission items (cluster_decommission.go) run the same steps without
+// the AWAITING_CUTOVER hold.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// RelocationStatus is the step a relocation is at.
+type RelocationStatus string
+
+const (
+	RelocationPending         RelocationStatus = "PENDING"          // Job not started yet
+	RelocationStoppingSource  RelocationStatus = "STOPPING_SOURCE"  // Source stopped, definition captured
+	RelocationCopyingDisks    RelocationStatus = "COPYING_DISKS"    // Export on the source, imports on the target
+	RelocationCreatingTarget  RelocationStatus = "CREATING_TARGET"  // VM created on the target, not started
+	RelocationAwaitingCutover RelocationStatus = "AWAITING_CUTOVER" // Held for an admin to confirm
+	RelocationCuttingOver     RelocationStatus = "CUTTING_OVER"     // Address, DNS and platform record moved
+	RelocationRetiringSource  RelocationStatus = "RETIRING_SOURCE"  // Target started, source deleted
+	RelocationCompleted       RelocationStatus = "COMPLETED"
+	RelocationAborted         RelocationStatus = "ABORTED"
+	RelocationFailed          RelocationStatus = "FAILED"
+)
+
+// IsTerminal reports whether the relocation is finished.
+func (s RelocationStatus) IsTerminal() bool {
+	return s == RelocationCompleted || s == RelocationAborted || s == RelocationFailed
+}
+
+// BeforeCutover reports whether the source is still the VM of record, so
+// the relocation can be abandoned without loss.
+func (s RelocationStatus) BeforeCutover() bool {
+	switch s {
+	case RelocationPending, RelocationStoppingSource, RelocationCopyingDisks,
+		RelocationCreatingTarget, RelocationAwaitingCutover:
+		return true
+	}
+	return false
+}
+
+// Disk import phases reported by the provider (CDI DataVolume).
+const (
+	DiskImportSucceeded = "Succeeded"
+	DiskImportFailed    = "Failed"
+)
+
+// RelocationDisk is one disk being copied: the source claim exported, the
+// DataVolume importing it on the target, and the import's progress.
+type RelocationDisk struct {
+	Volume       string `json:"volume"`       // Volume name in the VM spec
+	SourceClaim  string `json:"source_claim"` // PVC on the source
+	DataVolume   string `json:"data_volume"`  // DataVolume on the target
+	SizeBytes    int64  `json:"size_bytes,omitempty"`
+	Phase        string `json:"phase,omitempty"`
+	Progress     string `json:"progress,omitempty"` // e.g. "42.5%"
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// VMRelocation is one relocation (vm_relocations). ID is the ID of its
+// VM_RELOCATION_REQUESTED event.
+type VMRelocation struct {
+	ID             string           `json:"id"`
+	DecommissionID string           `json:"decommission_id,omitempty"`
+	VMID           string           `json:"vm_id"`
+	VMName         string           `json:"vm_name"`
+	Namespace      string           `json:"namespace"`
+	ServiceID      string           `json:"service_id"`
+	SourceCluster  string           `json:"source_cluster"`
+	TargetCluster  string           `json:"target_cluster"`
+	Status         RelocationStatus `json:"status"`
+	Reason         string           `json:"reason"`
+	RequestedBy    string           `json:"requested_by"`
+
+	// HoldBeforeCutover stops at AWAITING_CUTOVER until an admin confirms.
+	HoldBeforeCutover bool `json:"hold_before_cutover"`
+
+	// Recorded while stopping the source: whether to start the VM again
+	// (on the target, or on the source after an abort), and its definition
+	// as recreated on the target (JSONB, without status).
+	WasRunning bool            `json:"was_running"`
+	Definition json.RawMessage `json:"-"`
+
+	ExportName string           `json:"export_name,omitempty"` // VirtualMachineExport on the source
+	Disks      []RelocationDisk `json:"disks,omitempty"`
+
+	// Stable identity moved at cutover
+	Hostname string `json:"hostname,omitempty"`
+	FQDN     string `json:"fqdn,omitempty"`
+	Address  string `json:"address,omitempty"` // Address serving the hostname after cutover
+
+	ConfirmedBy      string     `json:"confirmed_by,omitempty"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	AbortRequestedBy string     `json:"abort_requested_by,omitempty"`
+	AbortRequestedAt *time.Time `json:"abort_requested_at,omitempty"`
+	ErrorMessage     string     `json:"error_message,omitempty"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	StepStartedAt time.Time  `json:"step_started_at"` // When Status was entered
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// RelocationRequest is the body of a relocation request.
+type RelocationRequest struct {
+	TargetCluster     string `json:"target_cluster"`
+	HoldBeforeCutover *bool  `json:"hold_before_cutover,omitempty"` // Default true
+	Reason            string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *RelocationRequest) Validate() error {
+	if r.TargetCluster == "" {
+		return fmt.Errorf("target_cluster is required: %w", ErrInvalidRelocationRequest)
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidRelocationRequest)
+	}
+	return nil
+}
+
+// Hold returns whether to hold before cutover; true unless turned off.
+func (r *RelocationRequest) Hold() bool {
+	return r.HoldBeforeCutover == nil || *r.HoldBeforeCutover
+}
+
+// CheckRelocation rejects a VM that cannot be relocated: it must be
+// settled (RUNNING or STOPPED) and the target must be another cluster of
+// its environment that accepts placements (eligible, from
+// FilterPlacementCandidates).
+func CheckRelocation(vm *VM, target string, eligible []*Cluster) error {
+	if vm.Status != VMStatusRunning && vm.Status != VMStatusStopped {
+		return fmt.Errorf("cannot relocate a %s vm: %w", vm.Status, ErrRelocationNotAllowed)
+	}
+	if target == vm.Cluster {
+		return fmt.Errorf("vm already runs on %s: %w", target, ErrRelocationInvalidTarget)
+	}
+	for _, c := range eligible {
+		if c.Name == target {
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %s: %w", target, ErrRelocationInvalidTarget)
+}
+
+// RelocationExportName is the VirtualMachineExport of the relocation:
+// "<vm>-exp-<first 8 characters of the relocation ID>".
+func RelocationExportName(vmName, relocationID string) string {
+	return copyName(vmName, "exp", relocationID)
+}
+
+// RelocationDiskName is the DataVolume importing a claim on the target:
+// "<claim>-rlc-<first 8 characters of the relocation ID>". The VM's
+// recreated definition refers to it instead of the claim.
+func RelocationDiskName(claimName, relocationID string) string {
+	return copyName(claimName, "rlc", relocationID)
+}
+
+// VMExport is a VirtualMachineExport as reported by the provider: once
+// Ready, every volume is served at its URL for the importing cluster.
+type VMExport struct {
+	Name        string           `json:"name"`
+	Ready       bool             `json:"ready"`
+	TokenSecret string           `json:"token_secret,omitempty"` // Secret the target's imports authenticate with
+	Volumes     []ExportedVolume `json:"volumes,omitempty"`
+}
+
+// ExportedVolume is one claim served by an export.
+type ExportedVolume struct {
+	ClaimName string `json:"claim_name"`
+	URL       string `json:"url"`
+	Format    string `json:"format"` // raw, gzip
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+}
+
+// DiskImport is a DataVolume importing an exported volume.
+type DiskImport struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase"`
+	Progress     string `json:"progress,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// RelocationResult is the result of a finished relocation event.
+type RelocationResult struct {
+	Status        RelocationStatus `json:"status"`
+	SourceCluster string           `json:"source_cluster"`
+	TargetCluster string           `json:"target_cluster"`
+	Address       string           `json:"address,omitempty"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r RelocationResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal relocation result: %w", err)
+	}
+	return data, nil
+}
+
+// Errors
+var (
+	ErrInvalidRelocationRequest = domainerr.Invalid("INVALID_REQUEST", "invalid relocation request")
+	ErrRelocationNotAllowed     = domainerr.Conflict("RELOCATION_NOT_ALLOWED", "relocation not allowed for the vm")
+	ErrRelocationInvalidTarget  = domainerr.Unprocessable("RELOCATION_INVALID_TARGET", "target cluster is not eligible for the vm")
+	ErrRelocationInvalidState   = domainerr.Conflict("RELOCATION_INVALID_STATE", "relocation is not in the required state for this step")
+)
@@ -0,0 +1,151 @@
+// Package domain provides domain models.
+//
+// This file defines blue/green VM replacement: a replacement ("green") VM
+// is provisioned from a newer template version next to the existing
+// ("blue") one, and takes over once confirmed:
+//
+//	Start ──► PROVISIONING ──► AWAITING_CONFIRMATION ──► CUTTING_OVER ──► COMPLETED
+//	            │ (green create:       │ (owner confirms)      (hostname, address, DNS
+//	            │  approval, warm-up)  │                        moved; blue retired)
+//	            │                      └──► ABORTED (green retired, blue untouched)
+//	            └──► FAILED / ABORTED (green creation failed / rejected or cancelled)
+//
+// With the health_probe confirmation, a passing probe of green moves
+// straight to CUTTING_OVER; a probe that keeps failing falls back to the
+// owner.
+//
+// VM names are never reused (K8s names are immutable): green gets the next
+// instance index. What moves at cutover is the stable identity clients
+// use: the hostname label and FQDN annotation, a static address when blue
+// has one, and the DNS record of the hostname.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// ReplacementPermissions are both needed on the Service: green is a new
+// VM, and confirming retires blue.
+var ReplacementPermissions = []string{"vm:create", "vm:delete"}
+
+// ReplacementStatus is the status of a replacement.
+type ReplacementStatus string
+
+const (
+	ReplacementProvisioning         ReplacementStatus = "PROVISIONING"
+	ReplacementAwaitingConfirmation ReplacementStatus = "AWAITING_CONFIRMATION"
+	ReplacementCuttingOver          ReplacementStatus = "CUTTING_OVER"
+	ReplacementCompleted            ReplacementStatus = "COMPLETED"
+	ReplacementAborted              ReplacementStatus = "ABORTED"
+	ReplacementFailed               ReplacementStatus = "FAILED"
+)
+
+// IsTerminal reports whether the replacement has finished.
+func (s ReplacementStatus) IsTerminal() bool {
+	return s == ReplacementCompleted || s == ReplacementAborted || s == ReplacementFailed
+}
+
+// ReplacementConfirmation selects what lets green take over.
+type ReplacementConfirmation string
+
+const (
+	// ConfirmByOwner waits for an owner to confirm after checking green.
+	ConfirmByOwner ReplacementConfirmation = "owner"
+
+	// ConfirmByHealthProbe cuts over once green passes the warm-up checks
+	// (warmup.go), probed again after creation.
+	ConfirmByHealthProbe ReplacementConfirmation = "health_probe"
+)
+
+// ReplacementProbeTimeout bounds the health probe; after it the
+// replacement waits for the owner instead.
+const ReplacementProbeTimeout = 30 * time.Minute
+
+// StartReplacementRequest is the body of a replacement request.
+type StartReplacementRequest struct {
+	TemplateID   string                  `json:"template_id,omitempty"` // Default: active version of blue's template
+	Confirmation ReplacementConfirmation `json:"confirmation"`
+	Reason       string                  `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *StartReplacementRequest) Validate() error {
+	if r.Confirmation != ConfirmByOwner && r.Confirmation != ConfirmByHealthProbe {
+		return ErrInvalidReplacement
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return ErrInvalidReplacement
+	}
+	return nil
+}
+
+// Replaceable reports whether a VM in this status can be replaced.
+// Green is created from the VM's spec, so blue must be settled.
+func Replaceable(status VMStatus) bool {
+	return status == VMStatusRunning || status == VMStatusStopped
+}
+
+// VMReplacement is one blue/green replacement.
+type VMReplacement struct {
+	ID           string                  `json:"id"`
+	Status       ReplacementStatus       `json:"status"`
+	Confirmation ReplacementConfirmation `json:"confirmation"`
+	Reason       string                  `json:"reason"`
+	RequestedBy  string                  `json:"requested_by"`
+	ServiceID    string                  `json:"service_id"`
+
+	// Blue: the VM being replaced
+	BlueVMID       string `json:"blue_vm_id"`
+	BlueName       string `json:"blue_name"`
+	BlueTemplateID string `json:"blue_template_id"`
+
+	// Green: created through the normal create request
+	TemplateID      string `json:"template_id"`
+	CreationEventID string `json:"creation_event_id"`
+	CreationTicket  string `json:"creation_ticket_id"`
+	GreenVMID       string `json:"green_vm_id,omitempty"` // Set once created
+	GreenName       string `json:"green_name,omitempty"`
+
+	// GreenReadyAt is when green's creation completed; the health probe
+	// runs until ReplacementProbeTimeout after it.
+	GreenReadyAt *time.Time `json:"green_ready_at,omitempty"`
+
+	// Stable identity moved at cutover
+	Hostname string `json:"hostname"`          // Blue's hostname label
+	FQDN     string `json:"fqdn,omitempty"`    // Blue's FQDN annotation; DNS record updated when set
+	Address  string `json:"address,omitempty"` // Address serving the hostname after cutover
+
+	RetireEventID string `json:"retire_event_id,omitempty"` // Deletion of the VM retired (blue, or green on abort)
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedBy string     `json:"confirmed_by,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Errors
+
+var (
+	// ErrInvalidReplacement is returned for a malformed replacement request.
+	ErrInvalidReplacement = domainerr.Invalid("INVALID_REQUEST", "replacement needs confirmation owner or health_probe and a reason of 1-500 characters")
+
+	// ErrReplacementForbidden is returned without vm:create and vm:delete on the Service.
+	ErrReplacementForbidden = domainerr.PermissionDenied("REPLACEMENT_FORBIDDEN", "replacing a VM requires vm:create and vm:delete on its Service")
+
+	// ErrVMNotReplaceable is returned for VMs that are not RUNNING or STOPPED.
+	ErrVMNotReplaceable = domainerr.Conflict("VM_NOT_REPLACEABLE", "vm cannot be replaced in its current status")
+
+	// ErrReplacementSameTemplate is returned when the VM already runs the template version.
+	ErrReplacementSameTemplate = domainerr.Conflict("REPLACEMENT_SAME_TEMPLATE", "vm already uses this template version")
+
+	// ErrReplacementInProgress is returned when the VM already has an active replacement.
+	ErrReplacementInProgress = domainerr.Conflict("REPLACEMENT_IN_PROGRESS", "vm already has a replacement in progress")
+
+	// ErrReplacementInvalidState is returned when confirming or aborting out of order.
+	ErrReplacementInvalidState = domainerr.Conflict("REPLACEMENT_INVALID_STATE", "replacement is not in the required state for this step")
+)
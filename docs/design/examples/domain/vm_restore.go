@@ -0,0 +1,179 @@
+// Package domain provides domain models.
+//
+// This file defines restore requests: putting a VM's disks back to one of
+// its restore points (vm_backup.go), a platform snapshot or an external
+// backup.
+//
+// A restore overwrites the VM's current disks, so it is never
+// auto-approved and needs the VM stopped. Before anything is overwritten
+// the worker takes a safety snapshot of the VM as it is; the safety
+// snapshot becomes a restore point of its own, so a restore can be undone
+// by restoring again.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// RestorePermission is needed on the VM's Service.
+const RestorePermission = "vm:operate"
+
+// RestoreRequestType is the ticket request type of restore requests.
+const RestoreRequestType = "RESTORE_VM"
+
+// RestoreRequest is the body of a restore request.
+type RestoreRequest struct {
+	RestorePointID string `json:"restore_point_id"`
+	Reason         string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *RestoreRequest) Validate() error {
+	if r.RestorePointID == "" {
+		return fmt.Errorf("restore_point_id is required: %w", ErrInvalidRestoreRequest)
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidRestoreRequest)
+	}
+	return nil
+}
+
+// CheckRestore rejects a VM that cannot be restored in its status: disks
+// are only replaced while the VM is stopped.
+func CheckRestore(status VMStatus) error {
+	if status != VMStatusStopped {
+		return fmt.Errorf("cannot restore a %s vm, stop it first: %w", status, ErrRestoreNotAllowed)
+	}
+	return nil
+}
+
+// CheckRestorePoint rejects a restore point of another VM or one past
+// its provider's retention. Points of other VMs are reported like
+// missing ones.
+func CheckRestorePoint(rp *RestorePoint, vmID string, now time.Time) error {
+	if rp.VMID != vmID {
+		return fmt.Errorf("restore point %s: %w", rp.ID, ErrRestorePointUnavailable)
+	}
+	if rp.ExpiresAt != nil && !now.Before(*rp.ExpiresAt) {
+		return fmt.Errorf("restore point %s expired at %s: %w", rp.ID, rp.ExpiresAt.Format(time.RFC3339), ErrRestorePointUnavailable)
+	}
+	return nil
+}
+
+// SafetySnapshotName is the name of the snapshot taken before the restore
+// requested by eventID: "<vm>-pre-<first 8 characters of the event ID>".
+func SafetySnapshotName(vmName, eventID string) string {
+	return copyName(vmName, "pre", eventID)
+}
+
+// RestoreName is the name a backup provider is asked to restore under:
+// "<vm>-rst-<first 8 characters of the event ID>". Like BackupName it
+// lets a retried job find the restore it already started.
+func RestoreName(vmName, eventID string) string {
+	return copyName(vmName, "rst", eventID)
+}
+
+// RestorePayload is the payload of VM_RESTORE_REQUESTED. AggregateID is
+// the VM ID, so the VM cannot be started, resized or deleted while the
+// restore is pending. The restore point is copied in at submission, the
+// names are chosen then.
+type RestorePayload struct {
+	VMID      string `json:"vm_id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	ServiceID string `json:"service_id"`
+
+	RestorePointID string             `json:"restore_point_id"`
+	Source         RestorePointSource `json:"source"`
+	Provider       string             `json:"provider,omitempty"` // Backup provider; empty for snapshots
+	PointName      string             `json:"point_name"`         // Snapshot or backup name
+	ExternalID     string             `json:"external_id,omitempty"`
+
+	SafetySnapshotName string `json:"safety_snapshot_name"`
+	RestoreName        string `json:"restore_name,omitempty"` // Backups only
+	Reason             string `json:"reason"`
+}
+
+// Validate requires the VM, the restore point and the safety snapshot name.
+func (p RestorePayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace,
+		"cluster", p.Cluster, "restore_point_id", p.RestorePointID, "point_name", p.PointName,
+		"safety_snapshot_name", p.SafetySnapshotName); err != nil {
+		return err
+	}
+	if p.Source == RestorePointBackup {
+		return requireFields("provider", p.Provider, "restore_name", p.RestoreName)
+	}
+	return nil
+}
+
+// NewRestorePayload builds the payload restoring vm to rp.
+func NewRestorePayload(vm *VM, rp *RestorePoint, eventID, reason string) RestorePayload {
+	p := RestorePayload{
+		VMID:               vm.ID,
+		Name:               vm.Name,
+		Namespace:          vm.Namespace,
+		Cluster:            vm.Cluster,
+		ServiceID:          vm.ServiceID,
+		RestorePointID:     rp.ID,
+		Source:             rp.Source,
+		Provider:           rp.Provider,
+		PointName:          rp.Name,
+		ExternalID:         rp.ExternalID,
+		SafetySnapshotName: SafetySnapshotName(vm.Name, eventID),
+		Reason:             reason,
+	}
+	if rp.Source == RestorePointBackup {
+		p.RestoreName = RestoreName(vm.Name, eventID)
+	}
+	return p
+}
+
+// RestoreTarget is what a backup provider is asked to restore.
+type RestoreTarget struct {
+	RestoreName string `json:"restore_name"`
+	BackupName  string `json:"backup_name"`
+	ExternalID  string `json:"external_id,omitempty"`
+	Cluster     string `json:"cluster"`
+	Namespace   string `json:"namespace"`
+	VMName      string `json:"vm_name"`
+}
+
+// ExternalRestore is a restore as reported by the backup provider. Phase
+// uses the backup phases.
+type ExternalRestore struct {
+	Name         string      `json:"name"`
+	Phase        BackupPhase `json:"phase"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+}
+
+// RestoreResult is the result of a completed restore request. The safety
+// snapshot is the restore point recorded with the request's event ID.
+type RestoreResult struct {
+	RestorePointID     string `json:"restore_point_id"`
+	SafetySnapshotName string `json:"safety_snapshot_name"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r RestoreResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal restore result: %w", err)
+	}
+	return data, nil
+}
+
+// Errors
+var (
+	ErrInvalidRestoreRequest   = domainerr.Invalid("INVALID_REQUEST", "invalid restore request")
+	ErrRestoreForbidden        = domainerr.PermissionDenied("RESTORE_FORBIDDEN", "restores require vm:operate on the Service")
+	ErrRestoreNotAllowed       = domainerr.Conflict("RESTORE_NOT_ALLOWED", "restore not allowed in the vm's current status")
+	ErrRestorePointUnavailable = domainerr.Unprocessable("RESTORE_POINT_UNAVAILABLE", "restore point not available for this vm")
+)
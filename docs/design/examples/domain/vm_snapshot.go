@@ -0,0 +1,111 @@
+// Package domain provides domain models.
+//
+// This file defines snapshot requests: a point-in-time snapshot of a
+// single VM (VirtualMachineSnapshot), taken as a governed operation.
+//
+// Snapshots use storage, so they are approved like any other request:
+// the environment policy decides, approval rules for SNAPSHOT_VM may
+// auto-approve within it. The snapshot name is chosen by the platform
+// when the request is submitted, so a retried job finds the snapshot it
+// already started instead of taking a second one. The snapshot taken is
+// recorded as the event's result (DomainEvent.Result); the payload stays
+// what was approved.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// SnapshotPermission is needed on the VM's Service.
+const SnapshotPermission = "vm:operate"
+
+// SnapshotRequestType is the ticket request type of snapshot requests.
+const SnapshotRequestType = "SNAPSHOT_VM"
+
+// SnapshotRequest is the body of a snapshot request.
+type SnapshotRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *SnapshotRequest) Validate() error {
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidSnapshotRequest)
+	}
+	return nil
+}
+
+// CheckSnapshot rejects a VM that cannot be snapshotted in its status:
+// running VMs are snapshotted online, stopped VMs offline.
+func CheckSnapshot(status VMStatus) error {
+	if status != VMStatusRunning && status != VMStatusStopped {
+		return fmt.Errorf("cannot snapshot a %s vm: %w", status, ErrSnapshotNotAllowed)
+	}
+	return nil
+}
+
+// SnapshotName is the platform-chosen name of the snapshot requested by
+// eventID: "<vm>-snap-<first 8 characters of the event ID>", at most 63
+// characters.
+func SnapshotName(vmName, eventID string) string {
+	return copyName(vmName, "snap", eventID)
+}
+
+// copyName is "<vm>-<kind>-<first 8 characters of eventID>", with the VM
+// name cut so the result fits 63 characters.
+func copyName(vmName, kind, eventID string) string {
+	suffix := eventID
+	if len(suffix) > 8 {
+		suffix = suffix[:8]
+	}
+	suffix = "-" + kind + "-" + suffix
+	if len(vmName)+len(suffix) > 63 {
+		vmName = vmName[:63-len(suffix)]
+	}
+	return vmName + suffix
+}
+
+// SnapshotPayload is the payload of VM_SNAPSHOT_REQUESTED. AggregateID is
+// the VM ID, so a snapshot in flight blocks deletion and resizing.
+type SnapshotPayload struct {
+	VMID         string `json:"vm_id"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Cluster      string `json:"cluster"`
+	ServiceID    string `json:"service_id"`
+	SnapshotName string `json:"snapshot_name"`
+	Reason       string `json:"reason"`
+}
+
+// Validate requires the VM and the snapshot name.
+func (p SnapshotPayload) Validate() error {
+	return requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace,
+		"cluster", p.Cluster, "snapshot_name", p.SnapshotName)
+}
+
+// SnapshotResult is the result of a completed snapshot request.
+type SnapshotResult struct {
+	SnapshotName string `json:"snapshot_name"`
+	SizeBytes    int64  `json:"size_bytes"`
+}
+
+// ToJSON converts the result to JSON bytes.
+func (r SnapshotResult) ToJSON() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot result: %w", err)
+	}
+	return data, nil
+}
+
+// Errors
+var (
+	ErrInvalidSnapshotRequest = domainerr.Invalid("INVALID_REQUEST", "invalid snapshot request")
+	ErrSnapshotForbidden      = domainerr.PermissionDenied("SNAPSHOT_FORBIDDEN", "snapshots require vm:operate on the Service")
+	ErrSnapshotNotAllowed     = domainerr.Conflict("SNAPSHOT_NOT_ALLOWED", "snapshot not allowed in the vm's current status")
+)
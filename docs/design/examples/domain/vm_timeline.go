@@ -0,0 +1,112 @@
+// Package domain provides domain models.
+//
+// This file defines the VM timeline behind the UI's activity tab: the
+// VM's domain events, the decisions on their approval tickets, the VM's
+// status transitions and its restore points (vm_backup.go), merged in
+// chronological order.
+//
+// The timeline is read-only and assembled by one query: nothing is
+// written for it except vm_status_history, which the vms trigger appends
+// to. Events are included whether live or archived, and whatever their
+// aggregate type (a lease renewal's aggregate is the VMLease, with the
+// VM's ID), plus the creation event through the VM's creation ticket.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// TimelineKind is the kind of a timeline entry.
+type TimelineKind string
+
+const (
+	TimelineEvent        TimelineKind = "event"         // A request or operation on the VM, with its current status
+	TimelineDecision     TimelineKind = "decision"      // An approval, rejection, cancellation or expiry of its ticket
+	TimelineStatus       TimelineKind = "status"        // A VM status transition
+	TimelineRestorePoint TimelineKind = "restore_point" // A snapshot or backup the VM can be restored to
+)
+
+// TimelineDecisionType is what a decision entry records.
+type TimelineDecisionType string
+
+const (
+	DecisionApproved  TimelineDecisionType = "approved" // One approver; a ticket needing two has two entries
+	DecisionRejected  TimelineDecisionType = "rejected"
+	DecisionCancelled TimelineDecisionType = "cancelled" // By the requester
+	DecisionExpired   TimelineDecisionType = "expired"   // By the TTL job; Actor is "system"
+)
+
+// TimelineEntry is one line of the activity tab. Fields not relevant to
+// the kind are empty.
+type TimelineEntry struct {
+	Cursor     string       `json:"cursor"`
+	Kind       TimelineKind `json:"kind"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Actor      string       `json:"actor,omitempty"`
+	OnBehalfOf string       `json:"on_behalf_of,omitempty"` // Delegator when a delegate decided
+
+	EventID   string    `json:"event_id,omitempty"`
+	EventType EventType `json:"event_type,omitempty"`
+	TicketID  string    `json:"ticket_id,omitempty"`
+
+	Decision TimelineDecisionType `json:"decision,omitempty"`
+	Stage    *int                 `json:"stage,omitempty"` // Approvals of staged tickets
+
+	FromStatus string `json:"from_status,omitempty"` // Status entries; empty for the first
+	Status     string `json:"status,omitempty"`      // Event status now, or the VM's new status
+	Reason     string `json:"reason,omitempty"`      // Rejection or cancellation reason, or status message
+
+	RestorePoint *RestorePoint `json:"restore_point,omitempty"` // Restore point entries; EventID is the request that made it
+}
+
+// TimelinePage is one page of a timeline, oldest first. Next is empty on
+// the last page.
+type TimelinePage struct {
+	Entries []*TimelineEntry `json:"entries"`
+	Next    string           `json:"next,omitempty"`
+}
+
+// TimelineCursor is a position in a timeline: the entry's time, then a key
+// unique within the VM's timeline (kind prefix plus row key), so entries
+// at the same instant keep a stable order.
+type TimelineCursor struct {
+	At  time.Time
+	Key string
+}
+
+// String encodes the cursor; clients treat it as opaque.
+func (c TimelineCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.At.UnixMicro(), 10) + ":" + c.Key))
+}
+
+// ParseTimelineCursor decodes a cursor; empty means "from the start".
+func ParseTimelineCursor(s string) (TimelineCursor, error) {
+	if s == "" {
+		return TimelineCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TimelineCursor{}, ErrInvalidTimelineCursor
+	}
+	at, key, ok := strings.Cut(string(raw), ":")
+	if !ok || key == "" {
+		return TimelineCursor{}, ErrInvalidTimelineCursor
+	}
+	micros, err := strconv.ParseInt(at, 10, 64)
+	if err != nil {
+		return TimelineCursor{}, ErrInvalidTimelineCursor
+	}
+	return TimelineCursor{At: time.UnixMicro(micros).UTC(), Key: key}, nil
+}
+
+// Errors
+var (
+	ErrInvalidTimelineCursor = domainerr.Invalid("INVALID_TIMELINE_CURSOR", "invalid timeline cursor")
+)
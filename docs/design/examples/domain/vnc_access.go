@@ -0,0 +1,228 @@
+// Package domain provides domain models.
+//
+// This file defines VNC console access (ADR-0015 §18): a short-lived,
+// single-use, user-bound token per granted request.
+//
+// A request is approved like a power operation: at once outside prod,
+// by the environment policy's approvers in prod. The grant is stored
+// (vnc_access_tokens); the token handed to the user carries only the
+// grant ID, a hash of the user ID and the expiry, signed with the
+// platform's VNC token key. Nothing secret is stored, so the token can be
+// fetched again until it is used; the console proxy redeems it once, for
+// the user it was issued to.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// VNCPermission is needed on the VM's Service.
+const VNCPermission = "vnc:access"
+
+// VNCAccessRequestType is the ticket request type of VNC access requests.
+const VNCAccessRequestType = "VNC_ACCESS"
+
+// MaxVNCTokenTTL bounds the time a grant stays usable (ADR-0015 §18).
+const MaxVNCTokenTTL = 2 * time.Hour
+
+// VNCAccessRequest is the body of a VNC access request.
+type VNCAccessRequest struct {
+	DurationMinutes int    `json:"duration_minutes"` // Token lifetime from the grant, 1-120
+	Reason          string `json:"reason"`
+}
+
+// Validate checks the request.
+func (r *VNCAccessRequest) Validate() error {
+	if r.DurationMinutes <= 0 || time.Duration(r.DurationMinutes)*time.Minute > MaxVNCTokenTTL {
+		return fmt.Errorf("duration_minutes must be 1-%d: %w", int(MaxVNCTokenTTL/time.Minute), ErrInvalidVNCRequest)
+	}
+	if r.Reason == "" || len(r.Reason) > 500 {
+		return fmt.Errorf("reason must be 1-500 characters: %w", ErrInvalidVNCRequest)
+	}
+	return nil
+}
+
+// Duration is the requested token lifetime.
+func (r *VNCAccessRequest) Duration() time.Duration {
+	return time.Duration(r.DurationMinutes) * time.Minute
+}
+
+// CheckVNCAccess rejects a VM without a console: only running VMs have one.
+func CheckVNCAccess(status VMStatus) error {
+	if status != VMStatusRunning {
+		return fmt.Errorf("vm is %s: %w", status, ErrVNCNotRunning)
+	}
+	return nil
+}
+
+// VNCDecision adapts the environment decision for the VM's Service to a
+// VNC request, as for power operations: approval outside prod only if a
+// rule asks for it, and a single stage.
+func VNCDecision(env EnvironmentDecision) EnvironmentDecision {
+	return PowerDecision(env)
+}
+
+// VNCAccessToken is a grant (vnc_access_tokens). It is usable while not
+// expired, used or revoked.
+type VNCAccessToken struct {
+	TokenID   string     `json:"token_id"`
+	VMID      string     `json:"vm_id"`
+	UserID    string     `json:"user_id"`
+	TicketID  string     `json:"ticket_id"` // Approval ticket reference
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // nil = not yet used
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy string     `json:"revoked_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CheckUsable reports why the grant can no longer be redeemed, if so.
+func (t *VNCAccessToken) CheckUsable(now time.Time) error {
+	switch {
+	case t.RevokedAt != nil:
+		return ErrVNCTokenRevoked
+	case t.UsedAt != nil:
+		return ErrVNCTokenUsed
+	case !now.Before(t.ExpiresAt):
+		return ErrVNCTokenExpired
+	}
+	return nil
+}
+
+// vncTokenClaims is the signed token payload.
+type vncTokenClaims struct {
+	ID   string `json:"id"`
+	User string `json:"usr"` // vncUserHash(UserID)
+	Exp  int64  `json:"exp"`
+}
+
+// SignVNCToken returns the token for a grant:
+// base64url(claims) "." base64url(HMAC-SHA256(key, claims)).
+func SignVNCToken(key []byte, t *VNCAccessToken) (string, error) {
+	claims, err := json.Marshal(vncTokenClaims{ID: t.TokenID, User: vncUserHash(t.UserID), Exp: t.ExpiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(vncTokenMAC(key, payload)), nil
+}
+
+// ParseVNCToken verifies a token presented by userID and returns the
+// grant ID it names. A token issued to another user is invalid. The
+// caller must still load the grant and call CheckUsable.
+func ParseVNCToken(key []byte, token, userID string, now time.Time) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrVNCTokenInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, vncTokenMAC(key, payload)) {
+		return "", ErrVNCTokenInvalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrVNCTokenInvalid
+	}
+	var claims vncTokenClaims
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.ID == "" {
+		return "", ErrVNCTokenInvalid
+	}
+	if claims.User != vncUserHash(userID) {
+		return "", ErrVNCTokenInvalid
+	}
+	if now.Unix() >= claims.Exp {
+		return "", ErrVNCTokenExpired
+	}
+	return claims.ID, nil
+}
+
+func vncTokenMAC(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// vncUserHash binds a token to its user without putting the user ID in it.
+func vncUserHash(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
+
+// VNCAccessPayload is the payload of VNC_ACCESS_REQUESTED. AggregateType
+// is VMConsole, so a pending request does not block operations on the VM.
+type VNCAccessPayload struct {
+	VMID            string `json:"vm_id"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Cluster         string `json:"cluster"`
+	ServiceID       string `json:"service_id"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Reason          string `json:"reason"`
+}
+
+// Validate requires the VM and a positive duration.
+func (p VNCAccessPayload) Validate() error {
+	if err := requireFields("vm_id", p.VMID, "name", p.Name, "namespace", p.Namespace, "cluster", p.Cluster); err != nil {
+		return err
+	}
+	if p.DurationMinutes <= 0 {
+		return fmt.Errorf("duration_minutes must be positive: %w", ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+// VNCAccessDecisionPayload is the payload of VNC_ACCESS_GRANTED and
+// VNC_ACCESS_DENIED. TokenID and ExpiresAt are set when granted; TicketID
+// is empty when the request was denied before a ticket existed (no
+// vnc:access on the Service).
+type VNCAccessDecisionPayload struct {
+	VMID      string     `json:"vm_id"`
+	UserID    string     `json:"user_id"`
+	TicketID  string     `json:"ticket_id,omitempty"`
+	TokenID   string     `json:"token_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	DecidedBy string     `json:"decided_by"` // "system" when auto-approved or denied by RBAC
+	Reason    string     `json:"reason,omitempty"`
+}
+
+// Validate requires the VM, the user and who decided.
+func (p VNCAccessDecisionPayload) Validate() error {
+	return requireFields("vm_id", p.VMID, "user_id", p.UserID, "decided_by", p.DecidedBy)
+}
+
+// VNCTokenRevokedPayload is the payload of VNC_TOKEN_REVOKED.
+type VNCTokenRevokedPayload struct {
+	TokenID   string `json:"token_id"`
+	VMID      string `json:"vm_id"`
+	UserID    string `json:"user_id"`
+	RevokedBy string `json:"revoked_by"`
+}
+
+// Validate requires the token, its VM and user and who revoked it.
+func (p VNCTokenRevokedPayload) Validate() error {
+	return requireFields("token_id", p.TokenID, "vm_id", p.VMID, "user_id", p.UserID, "revoked_by", p.RevokedBy)
+}
+
+// Errors
+var (
+	ErrInvalidVNCRequest = domainerr.Invalid("INVALID_REQUEST", "invalid vnc access request")
+	ErrVNCForbidden      = domainerr.PermissionDenied("VNC_FORBIDDEN", "vnc access requires vnc:access on the Service")
+	ErrVNCNotRunning     = domainerr.Conflict("VNC_VM_NOT_RUNNING", "vnc console is only available for running vms")
+	ErrVNCRevokeDenied   = domainerr.PermissionDenied("VNC_FORBIDDEN", "only the token holder or a platform admin can revoke a vnc token")
+
+	ErrVNCTokenInvalid = errors.New("vnc token invalid")
+	ErrVNCTokenExpired = domainerr.Gone("VNC_TOKEN_UNUSABLE", "vnc token expired")
+	ErrVNCTokenUsed    = domainerr.Gone("VNC_TOKEN_UNUSABLE", "vnc token already used")
+	ErrVNCTokenRevoked = domainerr.Gone("VNC_TOKEN_UNUSABLE", "vnc token revoked")
+)
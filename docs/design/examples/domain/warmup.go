@@ -0,0 +1,60 @@
+// Package domain provides domain models.
+//
+// This file defines post-provision warm-up checks.
+//
+// A created VM is RUNNING once KubeVirt schedules and boots it, which says
+// nothing about whether the guest finished booting. With warm-up enabled,
+// the creation event completes only when the guest is usable: the guest
+// agent is connected and/or a TCP port (SSH by default) accepts
+// connections.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/domain
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// WarmupCheck names a single check.
+type WarmupCheck string
+
+const (
+	WarmupCheckRunning    WarmupCheck = "running"
+	WarmupCheckGuestAgent WarmupCheck = "guest_agent"
+	WarmupCheckPort       WarmupCheck = "port"
+)
+
+// WarmupResult is the outcome of one check in one attempt.
+type WarmupResult struct {
+	Check  WarmupCheck `json:"check"`
+	Passed bool        `json:"passed"`
+	Detail string      `json:"detail,omitempty"` // Why it failed, e.g. "10.0.0.5:22: connection refused"
+}
+
+// WarmupReport is the outcome of one attempt.
+type WarmupReport struct {
+	Results   []WarmupResult `json:"results"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
+// Ready reports whether every check passed.
+func (r *WarmupReport) Ready() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary lists the failed checks, for the event error message.
+func (r *WarmupReport) Summary() string {
+	var failed []string
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, string(res.Check)+": "+res.Detail)
+		}
+	}
+	return strings.Join(failed, "; ")
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// ActionLinkHandler serves email action links. The routes are outside
+// session authentication: the signed token is the credential.
+//
+//	GET  /api/v1/action-links/:token          → link + ticket summary (no side effect)
+//	POST /api/v1/action-links/:token/confirm  → apply approve/reject
+//
+// The web page for a link calls GET, redirects view links to the ticket,
+// and shows a confirm button (and reason field for reject) otherwise.
+type ActionLinkHandler struct {
+	links *service.ActionLinkService
+}
+
+// NewActionLinkHandler creates a new action link handler.
+func NewActionLinkHandler(links *service.ActionLinkService) *ActionLinkHandler {
+	return &ActionLinkHandler{links: links}
+}
+
+type confirmActionLinkBody struct {
+	Reason string `json:"reason"`
+}
+
+// Inspect validates a link.
+func (h *ActionLinkHandler) Inspect(c *gin.Context) {
+	link, ticket, err := h.links.Inspect(c.Request.Context(), c.Param("token"))
+	// ACTION_LINK_INVALID is 404 for tampered and unknown links alike
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"action":     link.Action,
+		"expires_at": link.ExpiresAt,
+		"ticket": gin.H{
+			"ticket_id":      ticket.TicketID,
+			"request_type":   ticket.RequestType,
+			"request_reason": ticket.RequestReason,
+			"created_by":     ticket.CreatedBy,
+			"created_at":     ticket.CreatedAt,
+		},
+	})
+}
+
+// Confirm applies the link's decision.
+func (h *ActionLinkHandler) Confirm(c *gin.Context) {
+	var body confirmActionLinkBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	link, err := h.links.Confirm(c.Request.Context(), c.Param("token"), body.Reason)
+	// SOD_VIOLATION, or APPROVAL_PRECONDITION_FAILED: the link is spent and
+	// the approver continues in the web UI
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ticket_id": link.TicketID, "action": link.Action})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// APIUsageHandler exposes API usage and per-token quotas
+// (domain/api_usage.go).
+//
+//	GET    /api/v1/api-usage?days=30                     → caller's daily usage per token, with quotas
+//	GET    /api/v1/admin/api-usage?user_id=&days=30      → everyone's, or one principal's
+//	GET    /api/v1/admin/api-quotas                      → every quota
+//	PUT    /api/v1/admin/api-quotas/:key_id              → set a signing key's quota
+//	DELETE /api/v1/admin/api-quotas/:key_id              → remove it
+type APIUsageHandler struct {
+	usage *usecase.APIUsageUseCase
+}
+
+// NewAPIUsageHandler creates a new API usage handler.
+func NewAPIUsageHandler(usage *usecase.APIUsageUseCase) *APIUsageHandler {
+	return &APIUsageHandler{usage: usage}
+}
+
+type setAPIQuotaBody struct {
+	PerMinute int64  `json:"per_minute"`
+	PerDay    int64  `json:"per_day"`
+	Reason    string `json:"reason" binding:"required"`
+}
+
+// Mine returns the caller's usage.
+func (h *APIUsageHandler) Mine(c *gin.Context) {
+	h.report(c, c.GetString("user_id"))
+}
+
+// All returns everyone's usage, or ?user_id='s.
+func (h *APIUsageHandler) All(c *gin.Context) {
+	h.report(c, c.Query("user_id"))
+}
+
+func (h *APIUsageHandler) report(c *gin.Context, userID string) {
+	days, err := domain.ParseAPIUsageDays(c.Query("days"))
+	if writeError(c, err) {
+		return
+	}
+	usage, err := h.usage.Usage(c.Request.Context(), userID, days)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// ListQuotas returns every quota.
+func (h *APIUsageHandler) ListQuotas(c *gin.Context) {
+	quotas, err := h.usage.Quotas(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": quotas})
+}
+
+// SetQuota sets the quota of a signing key.
+func (h *APIUsageHandler) SetQuota(c *gin.Context) {
+	var body setAPIQuotaBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	quota, err := h.usage.SetQuota(c.Request.Context(), domain.APIQuota{
+		KeyID:     c.Param("key_id"),
+		PerMinute: body.PerMinute,
+		PerDay:    body.PerDay,
+		Reason:    body.Reason,
+	}, c.GetString("user_id"))
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.JSON(http.StatusOK, quota)
+	}
+}
+
+// RemoveQuota removes the quota of a signing key.
+func (h *APIUsageHandler) RemoveQuota(c *gin.Context) {
+	err := h.usage.RemoveQuota(c.Request.Context(), c.Param("key_id"), c.GetString("user_id"))
+	// NOT_FOUND when the key has no quota
+	if writeError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ApprovalRuleHandler manages the approval routing rules (platform admin).
+//
+//	POST   /api/v1/admin/approval-rules      → create rule
+//	GET    /api/v1/admin/approval-rules      → all rules, priority order
+//	PUT    /api/v1/admin/approval-rules/:id  → replace rule
+//	DELETE /api/v1/admin/approval-rules/:id  → delete rule
+//
+// Changes apply to the next submission; open tickets are not re-routed.
+type ApprovalRuleHandler struct {
+	ruleRepo repository.ApprovalRuleRepository
+	clock    domain.Clock
+	ids      domain.IDGenerator
+}
+
+// NewApprovalRuleHandler creates a new approval rule handler.
+func NewApprovalRuleHandler(ruleRepo repository.ApprovalRuleRepository, clock domain.Clock, ids domain.IDGenerator) *ApprovalRuleHandler {
+	return &ApprovalRuleHandler{ruleRepo: ruleRepo, clock: clock, ids: ids}
+}
+
+type approvalRuleBody struct {
+	Name           string                `json:"name" binding:"required"`
+	Priority       int                   `json:"priority"`
+	Enabled        bool                  `json:"enabled"`
+	RequestType    string                `json:"request_type"`
+	Namespace      string                `json:"namespace"`
+	RequesterRoles []string              `json:"requester_roles"`
+	MaxCPU         int                   `json:"max_cpu"`
+	MaxMemoryMB    int                   `json:"max_memory_mb"`
+	Action         domain.ApprovalAction `json:"action" binding:"required"`
+}
+
+func (b *approvalRuleBody) rule(id, userID string, now time.Time) *domain.ApprovalRule {
+	return &domain.ApprovalRule{
+		ID:             id,
+		Name:           b.Name,
+		Priority:       b.Priority,
+		Enabled:        b.Enabled,
+		RequestType:    b.RequestType,
+		Namespace:      b.Namespace,
+		RequesterRoles: b.RequesterRoles,
+		MaxCPU:         b.MaxCPU,
+		MaxMemoryMB:    b.MaxMemoryMB,
+		Action:         b.Action,
+		CreatedBy:      userID,
+		CreatedAt:      now,
+	}
+}
+
+// Create adds a rule.
+func (h *ApprovalRuleHandler) Create(c *gin.Context) {
+	var body approvalRuleBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	rule := body.rule(h.ids.NewID(), c.GetString("user_id"), h.clock.Now())
+	if writeError(c, rule.Validate()) {
+		return
+	}
+	if err := h.ruleRepo.Create(c.Request.Context(), rule); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// List returns every rule, disabled ones included.
+func (h *ApprovalRuleHandler) List(c *gin.Context) {
+	rules, err := h.ruleRepo.List(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": rules})
+}
+
+// Update replaces a rule.
+func (h *ApprovalRuleHandler) Update(c *gin.Context) {
+	var body approvalRuleBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	rule := body.rule(c.Param("id"), c.GetString("user_id"), h.clock.Now())
+	if writeError(c, rule.Validate()) {
+		return
+	}
+	err := h.ruleRepo.Update(c.Request.Context(), rule)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// Delete removes a rule.
+func (h *ApprovalRuleHandler) Delete(c *gin.Context) {
+	err := h.ruleRepo.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// BulkApprovalHandler approves many pending tickets at once (platform
+// admin route group).
+//
+//	POST /api/v1/admin/approvals/bulk-approve  → 200 with one result per ticket
+//
+// Always 200 once the selection is valid: each ticket succeeds or fails
+// on its own, and the caller reads the per-ticket results.
+type BulkApprovalHandler struct {
+	bulk *usecase.BulkApproveUseCase
+}
+
+// NewBulkApprovalHandler creates a new bulk approval handler.
+func NewBulkApprovalHandler(bulk *usecase.BulkApproveUseCase) *BulkApprovalHandler {
+	return &BulkApprovalHandler{bulk: bulk}
+}
+
+// Per-ticket outcomes.
+const (
+	bulkApproved = "APPROVED" // Final approval, execution enqueued
+	bulkPending  = "PENDING"  // Approval recorded, more approvals required
+	bulkFailed   = "FAILED"   // Not approved, see code
+)
+
+type bulkApprovalItem struct {
+	TicketID string                   `json:"ticket_id"`
+	Outcome  string                   `json:"outcome"`
+	Progress *domain.ApprovalProgress `json:"progress,omitempty"`
+	Code     string                   `json:"code,omitempty"`
+	Message  string                   `json:"message,omitempty"`
+}
+
+type bulkApprovalResponse struct {
+	Approved int                `json:"approved"`
+	Pending  int                `json:"pending"`
+	Failed   int                `json:"failed"`
+	Results  []bulkApprovalItem `json:"results"`
+}
+
+// Approve approves the selected tickets as the current user.
+func (h *BulkApprovalHandler) Approve(c *gin.Context) {
+	var body domain.BulkApprovalSelection
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	results, err := h.bulk.Execute(ctx, body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+
+	resp := bulkApprovalResponse{Results: make([]bulkApprovalItem, 0, len(results))}
+	for _, r := range results {
+		item := bulkApprovalItem{TicketID: r.TicketID, Progress: r.Progress}
+		switch {
+		case r.Err != nil:
+			item.Outcome = bulkFailed
+			item.Code = bulkApprovalErrorCode(r.Err)
+			item.Message = domainerr.MessageKey(item.Code)
+			logger.InfoCtx(ctx, "Bulk approval item failed",
+				zap.String("ticket_id", r.TicketID),
+				zap.String("code", item.Code),
+				zap.Error(r.Err),
+			)
+			resp.Failed++
+		case r.Progress.Approved:
+			item.Outcome = bulkApproved
+			resp.Approved++
+		default:
+			item.Outcome = bulkPending
+			resp.Pending++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// bulkApprovalErrorCode is the code the single-ticket endpoints would
+// return for err.
+func bulkApprovalErrorCode(err error) string {
+	// TICKET_NOT_PENDING, SOD_VIOLATION, APPROVAL_PRECONDITION_FAILED and
+	// other typed errors
+	if code := domainerr.CodeOf(err); code != "" {
+		return code
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return "NOT_FOUND"
+	}
+	return "INTERNAL_ERROR"
+}
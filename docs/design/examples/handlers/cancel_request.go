@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// CancelRequestHandler lets requesters withdraw pending requests.
+//
+//	POST /api/v1/approvals/:id/cancel  → 204 (requester only, PENDING_APPROVAL only)
+type CancelRequestHandler struct {
+	cancel *usecase.CancelRequestUseCase
+}
+
+// NewCancelRequestHandler creates a new cancel request handler.
+func NewCancelRequestHandler(cancel *usecase.CancelRequestUseCase) *CancelRequestHandler {
+	return &CancelRequestHandler{cancel: cancel}
+}
+
+type cancelRequestBody struct {
+	Reason string `json:"reason"` // Optional
+}
+
+// Cancel cancels the ticket on behalf of the current user.
+func (h *CancelRequestHandler) Cancel(c *gin.Context) {
+	var body cancelRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) { // Body optional
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	err := h.cancel.Execute(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body.Reason)
+	if writeError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ChangeFreezeHandler manages the freeze calendar and emergency overrides.
+//
+//	POST   /api/v1/admin/change-freezes                     → create period (platform admin)
+//	GET    /api/v1/admin/change-freezes?from=                → periods ending after from
+//	DELETE /api/v1/admin/change-freezes/:id                  → delete period
+//	POST   /api/v1/approvals/:id/freeze-override             → request override
+//	POST   /api/v1/approvals/:id/freeze-override/approve     → approve (freeze:override)
+type ChangeFreezeHandler struct {
+	freezeRepo repository.ChangeFreezeRepository
+	overrides  *usecase.FreezeOverrideUseCase
+	clock      domain.Clock
+	ids        domain.IDGenerator
+}
+
+// NewChangeFreezeHandler creates a new change freeze handler.
+func NewChangeFreezeHandler(
+	freezeRepo repository.ChangeFreezeRepository,
+	overrides *usecase.FreezeOverrideUseCase,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ChangeFreezeHandler {
+	return &ChangeFreezeHandler{freezeRepo: freezeRepo, overrides: overrides, clock: clock, ids: ids}
+}
+
+type createFreezeBody struct {
+	Name         string    `json:"name" binding:"required"`
+	Start        time.Time `json:"start" binding:"required"`
+	End          time.Time `json:"end" binding:"required"`
+	Environments []string  `json:"environments"`
+	Reason       string    `json:"reason" binding:"required"`
+}
+
+type freezeOverrideBody struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Create adds a freeze period.
+func (h *ChangeFreezeHandler) Create(c *gin.Context) {
+	var body createFreezeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	period := &domain.FreezePeriod{
+		ID:           h.ids.NewID(),
+		Name:         body.Name,
+		Start:        body.Start,
+		End:          body.End,
+		Environments: body.Environments,
+		Reason:       body.Reason,
+		CreatedBy:    c.GetString("user_id"),
+		CreatedAt:    h.clock.Now(),
+	}
+	if writeError(c, period.Validate()) {
+		return
+	}
+	if err := h.freezeRepo.Create(c.Request.Context(), period); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, period)
+}
+
+// List returns periods ending after ?from (default now).
+func (h *ChangeFreezeHandler) List(c *gin.Context) {
+	from := h.clock.Now()
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+			return
+		}
+		from = t
+	}
+	periods, err := h.freezeRepo.ListEndingAfter(c.Request.Context(), from)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": periods})
+}
+
+// Delete removes a period. Held jobs notice at their next check.
+func (h *ChangeFreezeHandler) Delete(c *gin.Context) {
+	err := h.freezeRepo.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RequestOverride asks for an emergency override.
+func (h *ChangeFreezeHandler) RequestOverride(c *gin.Context) {
+	var body freezeOverrideBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	err := h.overrides.Request(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body.Reason)
+	if writeError(c, err) {
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// ApproveOverride approves a requested override.
+func (h *ChangeFreezeHandler) ApproveOverride(c *gin.Context) {
+	err := h.overrides.Approve(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
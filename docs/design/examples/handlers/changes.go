@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// streamHeartbeat is how often the stream sends a comment when idle. A
+// client that sees nothing for twice as long assumes a buffering proxy
+// and falls back to long-polling.
+const streamHeartbeat = 10 * time.Second
+
+// ChangesHandler serves the status change feed (domain/change_feed.go).
+//
+//	GET /api/v1/changes?cursor=&timeout=25s  → long-poll: {changes, cursor}
+//	GET /api/v1/changes/stream?cursor=       → Server-Sent Events (also resumes from Last-Event-ID)
+//
+// Clients negotiate: they open the stream and switch to long-polling with
+// their last cursor if no heartbeat arrives within 2×streamHeartbeat or
+// the stream fails. Both return 410 CHANGE_CURSOR_EXPIRED when the cursor
+// is past retention; the client reloads and starts with an empty cursor.
+type ChangesHandler struct {
+	feed *service.ChangeFeedService
+}
+
+// NewChangesHandler creates a new changes handler.
+func NewChangesHandler(feed *service.ChangeFeedService) *ChangesHandler {
+	return &ChangesHandler{feed: feed}
+}
+
+// Poll waits for changes after ?cursor, up to ?timeout.
+func (h *ChangesHandler) Poll(c *gin.Context) {
+	timeout, err := domain.ParseLongPollTimeout(c.Query("timeout"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	page, err := h.feed.Poll(c.Request.Context(), c.GetString("user_id"), c.Query("cursor"), timeout)
+	if writeError(c, err) {
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, page)
+}
+
+// Stream sends each change as an SSE event whose id is its cursor, so a
+// reconnecting EventSource resumes by itself.
+func (h *ChangesHandler) Stream(c *gin.Context) {
+	cursor := c.Query("cursor")
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		cursor = id
+	}
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+
+	// First poll before the headers, so errors are still plain JSON
+	page, err := h.feed.Poll(ctx, userID, cursor, 0)
+	if writeError(c, err) {
+		return
+	}
+
+	// The stream outlives server.write_timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no") // nginx: do not buffer
+
+	c.Stream(func(w io.Writer) bool {
+		for _, ch := range page.Changes {
+			data, _ := json.Marshal(ch)
+			fmt.Fprintf(w, "id: %s\nevent: change\ndata: %s\n\n", ch.Cursor, data)
+		}
+		if len(page.Changes) == 0 {
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		page, err = h.feed.Poll(ctx, userID, page.Cursor, streamHeartbeat)
+		if err != nil {
+			// Expired or closed: the client reconnects or falls back
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ComplianceHandler exposes patch compliance (platform admin only).
+//
+//	GET    /api/v1/admin/compliance/vms                → non-compliant VMs (?service_id, template_name, reason, min_severity, advisory_id)
+//	GET    /api/v1/admin/image-advisories              → open advisories
+//	POST   /api/v1/admin/image-advisories              → 201 + advisory
+//	DELETE /api/v1/admin/image-advisories/:id          → 204, advisory withdrawn
+//	POST   /api/v1/admin/remediation-campaigns         → 201 + campaign with per-VM outcome
+//	GET    /api/v1/admin/remediation-campaigns/:id     → campaign with current ticket statuses
+type ComplianceHandler struct {
+	compliance *usecase.ComplianceUseCase
+	campaigns  *usecase.RemediationCampaignUseCase
+}
+
+// NewComplianceHandler creates a new compliance handler.
+func NewComplianceHandler(compliance *usecase.ComplianceUseCase, campaigns *usecase.RemediationCampaignUseCase) *ComplianceHandler {
+	return &ComplianceHandler{compliance: compliance, campaigns: campaigns}
+}
+
+// Report lists the non-compliant VMs, most severe first.
+func (h *ComplianceHandler) Report(c *gin.Context) {
+	report, err := h.compliance.Report(c.Request.Context(), domain.ComplianceFilter{
+		ServiceID:    c.Query("service_id"),
+		TemplateName: c.Query("template_name"),
+		Reason:       domain.ComplianceReason(c.Query("reason")),
+		MinSeverity:  domain.AdvisorySeverity(c.Query("min_severity")),
+		AdvisoryID:   c.Query("advisory_id"),
+	})
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": report})
+}
+
+// ListAdvisories lists the open advisories.
+func (h *ComplianceHandler) ListAdvisories(c *gin.Context) {
+	advisories, err := h.compliance.ListAdvisories(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": advisories})
+}
+
+// RecordAdvisory flags an image as retired or vulnerable.
+func (h *ComplianceHandler) RecordAdvisory(c *gin.Context) {
+	var body domain.ImageAdvisory
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	a, err := h.compliance.RecordAdvisory(c.Request.Context(), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// WithdrawAdvisory withdraws an advisory.
+func (h *ComplianceHandler) WithdrawAdvisory(c *gin.Context) {
+	err := h.compliance.WithdrawAdvisory(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StartCampaign submits one request per matching VM.
+func (h *ComplianceHandler) StartCampaign(c *gin.Context) {
+	var body domain.CampaignRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	campaign, err := h.campaigns.Start(c.Request.Context(), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// GetCampaign returns a campaign and its progress.
+func (h *ComplianceHandler) GetCampaign(c *gin.Context) {
+	campaign, err := h.campaigns.Get(c.Request.Context(), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// DelegationHandler manages the current user's approval delegations.
+//
+//	POST   /api/v1/delegations      → delegate my approvals (201)
+//	GET    /api/v1/delegations      → given by me and to me, not yet ended
+//	DELETE /api/v1/delegations/:id  → revoke (delegator only, 204)
+type DelegationHandler struct {
+	delegations    *usecase.DelegationUseCase
+	delegationRepo repository.DelegationRepository
+	clock          domain.Clock
+}
+
+// NewDelegationHandler creates a new delegation handler.
+func NewDelegationHandler(delegations *usecase.DelegationUseCase, delegationRepo repository.DelegationRepository, clock domain.Clock) *DelegationHandler {
+	return &DelegationHandler{delegations: delegations, delegationRepo: delegationRepo, clock: clock}
+}
+
+type createDelegationBody struct {
+	DelegateID string    `json:"delegate_id" binding:"required"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at" binding:"required"`
+	Reason     string    `json:"reason" binding:"required"`
+}
+
+// Create delegates the current user's approval authority.
+func (h *DelegationHandler) Create(c *gin.Context) {
+	var body createDelegationBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	d, err := h.delegations.Create(c.Request.Context(), usecase.CreateDelegationRequest{
+		DelegatorID: c.GetString("user_id"),
+		DelegateID:  body.DelegateID,
+		StartsAt:    body.StartsAt,
+		EndsAt:      body.EndsAt,
+		Reason:      body.Reason,
+	})
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, d)
+}
+
+// List returns the delegations the current user gave or received that
+// have not ended or been revoked.
+func (h *DelegationHandler) List(c *gin.Context) {
+	userID, now := c.GetString("user_id"), h.clock.Now()
+	given, err := h.delegationRepo.ListCurrentByDelegator(c.Request.Context(), userID, now)
+	if writeError(c, err) {
+		return
+	}
+	received, err := h.delegationRepo.ListCurrentByDelegate(c.Request.Context(), userID, now)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"given": given, "received": received})
+}
+
+// Revoke ends a delegation.
+func (h *DelegationHandler) Revoke(c *gin.Context) {
+	err := h.delegations.Revoke(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// DiagnosticsHandler serves provisioning diagnostics bundles (platform
+// admin only; bundles may reference other tenants' objects).
+//
+//	GET /api/v1/admin/events/:id/diagnostics
+type DiagnosticsHandler struct {
+	eventRepo repository.DomainEventRepository
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler.
+func NewDiagnosticsHandler(eventRepo repository.DomainEventRepository) *DiagnosticsHandler {
+	return &DiagnosticsHandler{eventRepo: eventRepo}
+}
+
+// Get returns the bundle collected for a failed event.
+func (h *DiagnosticsHandler) Get(c *gin.Context) {
+	bundle, err := h.eventRepo.GetDiagnostics(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		// Not failed, not a creation, or collection still running
+		c.JSON(http.StatusNotFound, gin.H{"code": "DIAGNOSTICS_NOT_FOUND", "message": domainerr.MessageKey("DIAGNOSTICS_NOT_FOUND")})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// EmergencyStopHandler stops every VM of a Service or System (owners,
+// admins and platform admins).
+//
+//	POST /api/v1/services/:id/emergency-stop  → 202 + emergency_stop_id
+//	POST /api/v1/systems/:id/emergency-stop   → 202 + emergency_stop_id
+//	GET  /api/v1/emergency-stops/:id          → progress (readers of the Service/System)
+type EmergencyStopHandler struct {
+	emergencyStop *usecase.EmergencyStopUseCase
+}
+
+// NewEmergencyStopHandler creates a new emergency stop handler.
+func NewEmergencyStopHandler(emergencyStop *usecase.EmergencyStopUseCase) *EmergencyStopHandler {
+	return &EmergencyStopHandler{emergencyStop: emergencyStop}
+}
+
+// StopService stops every VM of the Service.
+func (h *EmergencyStopHandler) StopService(c *gin.Context) {
+	h.stop(c, domain.ResourceTypeService)
+}
+
+// StopSystem stops every VM of every Service of the System.
+func (h *EmergencyStopHandler) StopSystem(c *gin.Context) {
+	h.stop(c, domain.ResourceTypeSystem)
+}
+
+func (h *EmergencyStopHandler) stop(c *gin.Context, scopeType domain.ResourceType) {
+	var body domain.EmergencyStopRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	result, err := h.emergencyStop.Execute(c.Request.Context(), scopeType, c.Param("id"), c.GetString("user_id"), body)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"emergency_stop_id": result.StopID,
+		"event_id":          result.EventID,
+		"items":             result.Items,
+		"skipped":           result.Skipped,
+	})
+}
+
+// Get returns stop progress (counters + per-VM status).
+func (h *EmergencyStopHandler) Get(c *gin.Context) {
+	stop, items, err := h.emergencyStop.Progress(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"emergency_stop": stop,
+		"status":         stop.CalculateStatus(),
+		"items":          items,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// kindStatus maps a domain error kind to its HTTP status
+// (domain/errors/errors.go).
+var kindStatus = map[domainerr.Kind]int{
+	domainerr.KindNotFound:           http.StatusNotFound,
+	domainerr.KindInvalid:            http.StatusBadRequest,
+	domainerr.KindPermissionDenied:   http.StatusForbidden,
+	domainerr.KindConflict:           http.StatusConflict,
+	domainerr.KindPreconditionFailed: http.StatusConflict,
+	domainerr.KindGone:               http.StatusGone,
+	domainerr.KindUnprocessable:      http.StatusUnprocessableEntity,
+	domainerr.KindQuotaExceeded:      http.StatusTooManyRequests,
+}
+
+// writeError writes err, if any, and reports whether it did. Every
+// handler's error path ends here.
+//
+// A typed domain error is written with its code, params and the code's
+// message key (domainerr.MessageKey); the frontend picks the message by
+// code. Its English Message and the wrapped chain are logged, never
+// written. repository.ErrNotFound is 404 NOT_FOUND. Anything else is
+// logged and written as 500 INTERNAL_ERROR: the chain can name tables,
+// hosts or IDs, so internal details never reach the client.
+func writeError(c *gin.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	ctx := c.Request.Context()
+	if e, ok := domainerr.As(err); ok {
+		status, known := kindStatus[e.Kind]
+		if !known {
+			status = http.StatusInternalServerError
+		}
+		logger.InfoCtx(ctx, "Request rejected",
+			zap.String("path", c.FullPath()),
+			zap.String("code", e.Code),
+			zap.String("message", e.Message),
+			zap.Error(err),
+		)
+		body := gin.H{"code": e.Code, "message": domainerr.MessageKey(e.Code)}
+		if len(e.Params) > 0 {
+			body["params"] = e.Params
+		}
+		c.JSON(status, body)
+		return true
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND", "message": domainerr.MessageKey("NOT_FOUND")})
+		return true
+	}
+	logger.ErrorCtx(ctx, "Request failed", zap.String("path", c.FullPath()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"code": "INTERNAL_ERROR", "message": domainerr.MessageKey("INTERNAL_ERROR")})
+	return true
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// EventRequeueHandler sends failed events back to execution (platform
+// admin only, domain/event_requeue.go).
+//
+//	POST /api/v1/admin/events/:id/requeue  → event, PROCESSING with a fresh job
+type EventRequeueHandler struct {
+	requeue *usecase.RequeueEventUseCase
+}
+
+// NewEventRequeueHandler creates a new event requeue handler.
+func NewEventRequeueHandler(requeue *usecase.RequeueEventUseCase) *EventRequeueHandler {
+	return &EventRequeueHandler{requeue: requeue}
+}
+
+// Requeue requeues the event; the body carries the required reason.
+func (h *EventRequeueHandler) Requeue(c *gin.Context) {
+	var req domain.RequeueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	event, err := h.requeue.Execute(c.Request.Context(), c.Param("id"), req, c.GetString("user_id"))
+	// Typed: INVALID_REQUEST, EVENT_NOT_FAILED, EVENT_NOT_REQUEUEABLE,
+	// REQUEUE_LIMIT_REACHED, and APPROVAL_PRECONDITION_FAILED when the
+	// quota released on failure has been used by others since
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
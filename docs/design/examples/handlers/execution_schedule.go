@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ExecutionScheduleHandler moves the planned execution of approved tickets.
+// The plan itself is set with the approval (optional execute_at).
+//
+//	PUT /api/v1/approvals/:id/execution-schedule  → ticket with new execute_at (approvers, platform admins)
+type ExecutionScheduleHandler struct {
+	reschedule *usecase.RescheduleExecutionUseCase
+}
+
+// NewExecutionScheduleHandler creates a new execution schedule handler.
+func NewExecutionScheduleHandler(reschedule *usecase.RescheduleExecutionUseCase) *ExecutionScheduleHandler {
+	return &ExecutionScheduleHandler{reschedule: reschedule}
+}
+
+// Reschedule moves the execution to execute_at, or to now when null.
+func (h *ExecutionScheduleHandler) Reschedule(c *gin.Context) {
+	var body domain.RescheduleExecutionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	ticket, err := h.reschedule.Execute(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body.ExecuteAt)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
@@ -4,13 +4,18 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 
 	"kv-shepherd.io/shepherd/ent"
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
 )
 
 // WorkerStatus is an interface for checking worker health.
@@ -19,12 +24,46 @@ type WorkerStatus interface {
 	LastHeartbeat() time.Time
 }
 
+// LeaderStatus reports a leader-elected component's role on this replica.
+// Implemented by infrastructure.LeaderElection.
+type LeaderStatus interface {
+	Name() string
+	IsLeader() bool
+	Since() time.Time
+}
+
+// LifecycleStatus reports the replica's start/drain phase.
+// Implemented by infrastructure.Lifecycle.
+type LifecycleStatus interface {
+	Phase() string // starting, ready, draining, stopped
+	PhaseSince() time.Time
+	DrainDeadline() time.Time
+}
+
+// DatabaseStatus reports the database pools through failovers.
+// Implemented by infrastructure.DatabaseMonitor.
+type DatabaseStatus interface {
+	State() string // ok, failover
+	StateSince() time.Time
+	LastError() error
+}
+
+// RequirementsChecker reports required vs detected runtime capabilities.
+// Implemented by service.RuntimeRequirementsChecker.
+type RequirementsChecker interface {
+	Check(ctx context.Context) (*domain.RuntimeRequirements, error)
+}
+
 // HealthHandler handles health check endpoints.
 type HealthHandler struct {
 	client           *ent.Client
 	pool             *pgxpool.Pool
 	riverWorker      WorkerStatus   // Injected in Phase 4
 	resourceWatchers []WorkerStatus // One per cluster
+	watcherLeaders   []LeaderStatus // Same order as resourceWatchers; nil entry = not elected
+	lifecycle        LifecycleStatus
+	database         DatabaseStatus
+	requirements     RequirementsChecker
 }
 
 // NewHealthHandler creates a new health check handler.
@@ -41,13 +80,34 @@ func (h *HealthHandler) SetRiverWorker(w WorkerStatus) {
 	h.riverWorker = w
 }
 
+// SetLifecycle sets the replica lifecycle. Ready fails outside the ready phase.
+func (h *HealthHandler) SetLifecycle(l LifecycleStatus) {
+	h.lifecycle = l
+}
+
+// SetDatabaseMonitor sets the failover monitor. Ready fails while the
+// pools reconnect to a new primary.
+func (h *HealthHandler) SetDatabaseMonitor(d DatabaseStatus) {
+	h.database = d
+}
+
+// SetRequirements sets the runtime requirements checker behind
+// /health/requirements.
+func (h *HealthHandler) SetRequirements(r RequirementsChecker) {
+	h.requirements = r
+}
+
 // AddResourceWatcher adds a ResourceWatcher reference (called in Phase 2).
-func (h *HealthHandler) AddResourceWatcher(w WorkerStatus) {
+// election is the watcher's LeaderElection when running multiple replicas:
+// followers do not run the watcher, so its heartbeat is not checked there.
+func (h *HealthHandler) AddResourceWatcher(w WorkerStatus, election LeaderStatus) {
 	h.resourceWatchers = append(h.resourceWatchers, w)
+	h.watcherLeaders = append(h.watcherLeaders, election)
 }
 
 // Live is the liveness probe - checks if process is responsive.
 // Kubernetes uses this to determine if pod should be restarted.
+// Stays ok while draining: a restart would drop the running work.
 func (h *HealthHandler) Live(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ok",
@@ -62,8 +122,34 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	checks := make(map[string]interface{})
 	allHealthy := true
 
+	// ========== Lifecycle Check ==========
+	// Starting and draining replicas are healthy but must not get traffic
+	phase := "ready"
+	if h.lifecycle != nil {
+		phase = h.lifecycle.Phase()
+		lc := map[string]interface{}{
+			"phase": phase,
+			"since": h.lifecycle.PhaseSince().Format(time.RFC3339),
+		}
+		if deadline := h.lifecycle.DrainDeadline(); !deadline.IsZero() {
+			lc["deadline"] = deadline.Format(time.RFC3339)
+		}
+		checks["lifecycle"] = lc
+	}
+
 	// ========== Database Check ==========
-	if err := h.pool.Ping(ctx); err != nil {
+	// During a failover the replica stays live but takes no traffic
+	if h.database != nil && h.database.State() != "ok" {
+		db := map[string]interface{}{
+			"status": h.database.State(),
+			"since":  h.database.StateSince().Format(time.RFC3339),
+		}
+		if err := h.database.LastError(); err != nil {
+			db["error"] = err.Error()
+		}
+		checks["database"] = db
+		allHealthy = false
+	} else if err := h.pool.Ping(ctx); err != nil {
 		checks["database"] = map[string]interface{}{
 			"status": "error",
 			"error":  err.Error(),
@@ -103,6 +189,18 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		watchersHealthy := true
 
 		for i, watcher := range h.resourceWatchers {
+			if election := h.watcherLeaders[i]; election != nil && !election.IsLeader() {
+				// Another replica runs this watcher
+				watchersStatus = append(watchersStatus, map[string]interface{}{
+					"index":     i,
+					"component": election.Name(),
+					"role":      "follower",
+					"status":    "standby",
+					"since":     election.Since().Format(time.RFC3339),
+				})
+				continue
+			}
+
 			healthy := watcher.IsHealthy()
 			lastHeartbeat := watcher.LastHeartbeat()
 			heartbeatAge := time.Since(lastHeartbeat)
@@ -114,6 +212,7 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 
 			watchersStatus = append(watchersStatus, map[string]interface{}{
 				"index":            i,
+				"role":             roleOf(h.watcherLeaders[i]),
 				"status":           boolToStatus(healthy),
 				"last_heartbeat":   lastHeartbeat.Format(time.RFC3339),
 				"heartbeat_age_ms": heartbeatAge.Milliseconds(),
@@ -136,16 +235,52 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	}
 
 	status := http.StatusOK
-	if !allHealthy {
+	if !allHealthy || phase != "ready" {
 		status = http.StatusServiceUnavailable
 	}
 
+	overall := boolToHealthStatus(allHealthy)
+	if phase != "ready" {
+		overall = phase
+	}
+
 	c.JSON(status, gin.H{
-		"status": boolToHealthStatus(allHealthy),
+		"status": overall,
 		"checks": checks,
 	})
 }
 
+// Requirements reports what the platform requires of the database and
+// each cluster against what was detected, for deployment tooling to poll
+// after install or upgrade. 200 when every required requirement is met,
+// 503 otherwise (recommended ones never fail it), so `curl --fail` works
+// as a Helm test. Unlike Ready it does not depend on this replica's phase.
+func (h *HealthHandler) Requirements(c *gin.Context) {
+	report, err := h.requirements.Check(c.Request.Context())
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), "Requirements check failed", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code":    "REQUIREMENTS_UNAVAILABLE",
+			"message": domainerr.MessageKey("REQUIREMENTS_UNAVAILABLE"),
+		})
+		return
+	}
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// roleOf reports "leader" for elected and unelected (single replica)
+// components alike: both run here.
+func roleOf(election LeaderStatus) string {
+	if election != nil && !election.IsLeader() {
+		return "follower"
+	}
+	return "leader"
+}
+
 func boolToStatus(b bool) string {
 	if b {
 		return "ok"
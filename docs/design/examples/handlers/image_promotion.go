@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ImagePromotionHandler exposes golden image channels (platform admin only).
+//
+//	PUT  /api/v1/admin/images/:image/channels/dev         → record a build (image pipeline)
+//	POST /api/v1/admin/images/:image/promotions           → 201 + pending promotion
+//	POST /api/v1/admin/image-promotions/:id/approve       → channel moved (second admin)
+//	POST /api/v1/admin/image-promotions/:id/reject        → promotion closed
+//	GET  /api/v1/admin/images/:image/report               → VM count, channels and latest scans per digest
+//	GET  /api/v1/admin/images/:image/digests/:digest/vms  → VMs built from the digest
+type ImagePromotionHandler struct {
+	promotions *usecase.ImagePromotionUseCase
+	vmRepo     repository.VMRepository
+}
+
+// NewImagePromotionHandler creates a new image promotion handler.
+func NewImagePromotionHandler(promotions *usecase.ImagePromotionUseCase, vmRepo repository.VMRepository) *ImagePromotionHandler {
+	return &ImagePromotionHandler{promotions: promotions, vmRepo: vmRepo}
+}
+
+// RecordBuild points the dev channel at a new build.
+func (h *ImagePromotionHandler) RecordBuild(c *gin.Context) {
+	var body domain.ImageBuild
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	pointer, err := h.promotions.RecordBuild(c.Request.Context(), c.Param("image"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, pointer)
+}
+
+// Request asks for a promotion into the channel of the body.
+func (h *ImagePromotionHandler) Request(c *gin.Context) {
+	var body domain.PromotionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	p, err := h.promotions.Request(c.Request.Context(), c.Param("image"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// Approve moves the channel.
+func (h *ImagePromotionHandler) Approve(c *gin.Context) {
+	p, err := h.promotions.Approve(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Reject closes the promotion.
+func (h *ImagePromotionHandler) Reject(c *gin.Context) {
+	p, err := h.promotions.Reject(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Report returns the digests of the image in use or on a channel.
+func (h *ImagePromotionHandler) Report(c *gin.Context) {
+	report, err := h.promotions.Report(c.Request.Context(), c.Param("image"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": report})
+}
+
+// VMs lists the VMs built from a digest, e.g. to follow up on a CVE.
+func (h *ImagePromotionHandler) VMs(c *gin.Context) {
+	vms, err := h.vmRepo.ListByImageDigest(c.Request.Context(), c.Param("digest"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": vms})
+}
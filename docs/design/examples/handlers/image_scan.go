@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ImageScanHandler takes the vulnerability feed.
+//
+//	POST /api/v1/webhooks/image-scans         → 200 + stored scan (signed request, scanner key)
+//	GET  /api/v1/admin/image-scans/:digest    → latest scan per scanner, with findings (platform admin)
+//
+// The webhook route sits behind middleware.SignedRequest: the scanner is
+// the signing key's principal, so one scanner cannot overwrite another's
+// results.
+type ImageScanHandler struct {
+	scans *usecase.ImageScanUseCase
+}
+
+// NewImageScanHandler creates a new image scan handler.
+func NewImageScanHandler(scans *usecase.ImageScanUseCase) *ImageScanHandler {
+	return &ImageScanHandler{scans: scans}
+}
+
+// Record stores a scan report.
+func (h *ImageScanHandler) Record(c *gin.Context) {
+	var body domain.ScanReport
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	scan, err := h.scans.Record(c.Request.Context(), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, scan)
+}
+
+// Get returns the latest scans of a digest.
+func (h *ImageScanHandler) Get(c *gin.Context) {
+	scans, err := h.scans.Get(c.Request.Context(), c.Param("digest"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": scans})
+}
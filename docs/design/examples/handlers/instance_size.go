@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// InstanceSizeVersionHandler edits InstanceSizes as versions and reports
+// VMs on outdated ones (platform admin route group).
+//
+//	PUT /api/v1/admin/instance-sizes/:name                     → next version (expected_version required)
+//	GET /api/v1/admin/instance-sizes/:name/versions            → history, newest first
+//	GET /api/v1/admin/instance-sizes/:name/versions/:version   → one version
+//	GET /api/v1/admin/reports/outdated-instance-sizes?size=    → VMs on an older version
+type InstanceSizeVersionHandler struct {
+	sizes *usecase.InstanceSizeUseCase
+}
+
+// NewInstanceSizeVersionHandler creates a new instance size version handler.
+func NewInstanceSizeVersionHandler(sizes *usecase.InstanceSizeUseCase) *InstanceSizeVersionHandler {
+	return &InstanceSizeVersionHandler{sizes: sizes}
+}
+
+// Update applies the edit as the size's next version.
+func (h *InstanceSizeVersionHandler) Update(c *gin.Context) {
+	var edit domain.InstanceSizeEdit
+	if err := c.ShouldBindJSON(&edit); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	size, err := h.sizes.Update(c.Request.Context(), c.Param("name"), &edit, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, size)
+}
+
+// Versions lists the size's versions.
+func (h *InstanceSizeVersionHandler) Versions(c *gin.Context) {
+	versions, err := h.sizes.ListVersions(c.Request.Context(), c.Param("name"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": versions})
+}
+
+// Version returns one version of the size.
+func (h *InstanceSizeVersionHandler) Version(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": "version must be a positive integer"})
+		return
+	}
+
+	v, err := h.sizes.GetVersion(c.Request.Context(), c.Param("name"), version)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// Outdated lists the VMs running on an older version of their size.
+func (h *InstanceSizeVersionHandler) Outdated(c *gin.Context) {
+	vms, err := h.sizes.OutdatedVMs(c.Request.Context(), c.Query("size"))
+	if writeError(c, err) {
+		return
+	}
+
+	items := make([]gin.H, 0, len(vms))
+	for _, vm := range vms {
+		items = append(items, gin.H{
+			"vm":                vm,
+			"versions_behind":   vm.VersionsBehind(),
+			"resources_changed": vm.ResourcesChanged(),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
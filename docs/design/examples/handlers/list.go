@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// ListHandler serves permission-scoped list endpoints.
+//
+//	GET /api/v1/vms        → VMs the caller may read (?gpu=true, ?gpu_device=)
+//	GET /api/v1/approvals  → tickets in scope + caller's own (?status=)
+//	GET /api/v1/events     → events in scope + caller's own
+//	GET /api/v1/vms/:id/timeline → events, ticket decisions and status changes of a VM, oldest first
+//	GET /api/v1/vms/:id/restore-points → unexpired snapshots and backups of a VM, newest first
+//
+// Filtering happens in the query; "next" is only set when more rows in
+// scope exist, so page sizes and cursors never reveal hidden rows.
+type ListHandler struct {
+	queries *service.ScopedQueryService
+}
+
+// NewListHandler creates a new list handler.
+func NewListHandler(queries *service.ScopedQueryService) *ListHandler {
+	return &ListHandler{queries: queries}
+}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+func pageFrom(c *gin.Context) repository.Page {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return repository.Page{Limit: limit, After: c.Query("after")}
+}
+
+// VMs lists VMs.
+func (h *ListHandler) VMs(c *gin.Context) {
+	query := repository.VMQuery{GPUDevice: c.Query("gpu_device")}
+	if v := c.Query("gpu"); v != "" {
+		gpu, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": "gpu must be true or false"})
+			return
+		}
+		query.GPU = gpu
+	}
+
+	items, next, err := h.queries.ListVMs(c.Request.Context(), c.GetString("user_id"), query, pageFrom(c))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "next": next})
+}
+
+// Approvals lists approval tickets.
+func (h *ListHandler) Approvals(c *gin.Context) {
+	items, next, err := h.queries.ListTickets(c.Request.Context(), c.GetString("user_id"), c.Query("status"), pageFrom(c))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "next": next})
+}
+
+// Events lists domain events.
+func (h *ListHandler) Events(c *gin.Context) {
+	items, next, err := h.queries.ListEvents(c.Request.Context(), c.GetString("user_id"), pageFrom(c))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "next": next})
+}
+
+// Timeline returns a page of the VM's activity (?after=&limit=).
+func (h *ListHandler) Timeline(c *gin.Context) {
+	page, err := h.queries.VMTimeline(c.Request.Context(), c.GetString("user_id"), c.Param("id"), pageFrom(c))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// RestorePoints lists what the VM can be restored to.
+func (h *ListHandler) RestorePoints(c *gin.Context) {
+	items, err := h.queries.VMRestorePoints(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
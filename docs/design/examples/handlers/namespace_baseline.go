@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// NamespaceBaselineHandler manages the NetworkPolicy/ResourceQuota/LimitRange
+// baseline of each namespace environment (platform admin route group).
+//
+//	GET /api/v1/admin/namespace-baselines/:environment  → baseline (test or prod)
+//	PUT /api/v1/admin/namespace-baselines/:environment  → replace objects, synced to every namespace
+type NamespaceBaselineHandler struct {
+	baselines *usecase.NamespaceBaselineUseCase
+}
+
+// NewNamespaceBaselineHandler creates a new namespace baseline handler.
+func NewNamespaceBaselineHandler(baselines *usecase.NamespaceBaselineUseCase) *NamespaceBaselineHandler {
+	return &NamespaceBaselineHandler{baselines: baselines}
+}
+
+type updateNamespaceBaselineBody struct {
+	Objects []domain.NamespaceBaselineObject `json:"objects"`
+}
+
+// Get returns the environment's baseline.
+func (h *NamespaceBaselineHandler) Get(c *gin.Context) {
+	b, err := h.baselines.Get(c.Request.Context(), c.Param("environment"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// Update replaces the environment's objects.
+func (h *NamespaceBaselineHandler) Update(c *gin.Context) {
+	var body updateNamespaceBaselineBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	b, err := h.baselines.Update(c.Request.Context(), c.Param("environment"), body.Objects, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// NodeDrainHandler exposes node drain coordination (platform admin only).
+//
+//	POST /api/v1/admin/clusters/:cluster/nodes/:node/drain  → 202 + drain_id
+//	GET  /api/v1/admin/node-drains/:id                      → progress
+type NodeDrainHandler struct {
+	drainNode *usecase.DrainNodeUseCase
+	drainRepo repository.NodeDrainRepository
+}
+
+// NewNodeDrainHandler creates a new node drain handler.
+func NewNodeDrainHandler(drainNode *usecase.DrainNodeUseCase, drainRepo repository.NodeDrainRepository) *NodeDrainHandler {
+	return &NodeDrainHandler{
+		drainNode: drainNode,
+		drainRepo: drainRepo,
+	}
+}
+
+type drainNodeBody struct {
+	WindowStart *time.Time `json:"window_start"`
+	WindowEnd   *time.Time `json:"window_end"`
+	Force       bool       `json:"force"`
+	Reason      string     `json:"reason" binding:"required"`
+}
+
+// Drain starts a drain and returns 202 Accepted (ADR-0006).
+func (h *NodeDrainHandler) Drain(c *gin.Context) {
+	var body drainNodeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	var window *domain.MaintenanceWindow
+	if body.WindowStart != nil && body.WindowEnd != nil {
+		window = &domain.MaintenanceWindow{Start: *body.WindowStart, End: *body.WindowEnd}
+	}
+
+	result, err := h.drainNode.Execute(c.Request.Context(), usecase.DrainNodeRequest{
+		Cluster:     c.Param("cluster"),
+		NodeName:    c.Param("node"),
+		Window:      window,
+		Force:       body.Force,
+		Reason:      body.Reason,
+		RequestedBy: c.GetString("user_id"),
+	})
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"drain_id": result.DrainID,
+		"event_id": result.EventID,
+		"items":    result.Items,
+	})
+}
+
+// Get returns drain progress (counters + per-VM status).
+func (h *NodeDrainHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	drain, err := h.drainRepo.Get(ctx, c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+
+	items, err := h.drainRepo.ListItems(ctx, drain.ID)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"drain":  drain,
+		"status": drain.CalculateStatus(),
+		"items":  items,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// PolicyExpressionHandler manages CEL approval and guardrail policies
+// (platform admin route group).
+//
+//	GET  /api/v1/admin/policies               → every policy at its current version
+//	POST /api/v1/admin/policies               → version 1 (expression must compile)
+//	PUT  /api/v1/admin/policies/:id           → next version (expected_version required)
+//	GET  /api/v1/admin/policies/:id/versions  → history, newest first
+//	POST /api/v1/admin/policies/dry-run       → draft evaluated per input; nothing saved
+type PolicyExpressionHandler struct {
+	policies *usecase.PolicyExpressionUseCase
+}
+
+// NewPolicyExpressionHandler creates a new policy expression handler.
+func NewPolicyExpressionHandler(policies *usecase.PolicyExpressionUseCase) *PolicyExpressionHandler {
+	return &PolicyExpressionHandler{policies: policies}
+}
+
+// List returns the policies, disabled ones included.
+func (h *PolicyExpressionHandler) List(c *gin.Context) {
+	policies, err := h.policies.List(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": policies})
+}
+
+// Create saves a new policy. The name, kind and language are fixed from
+// here on.
+func (h *PolicyExpressionHandler) Create(c *gin.Context) {
+	var p domain.PolicyExpression
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	created, err := h.policies.Create(c.Request.Context(), &p, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update applies the edit as the policy's next version.
+func (h *PolicyExpressionHandler) Update(c *gin.Context) {
+	var edit domain.PolicyExpressionEdit
+	if err := c.ShouldBindJSON(&edit); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	p, err := h.policies.Update(c.Request.Context(), c.Param("id"), &edit, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Versions lists the policy's versions.
+func (h *PolicyExpressionHandler) Versions(c *gin.Context) {
+	versions, err := h.policies.ListVersions(c.Request.Context(), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": versions})
+}
+
+// DryRun evaluates a draft policy against the sample inputs of the body,
+// a domain.PolicyDryRun. Each item tells whether the expression held and
+// what it would do (domain.PolicyEffect).
+func (h *PolicyExpressionHandler) DryRun(c *gin.Context) {
+	var run domain.PolicyDryRun
+	if err := c.ShouldBindJSON(&run); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	results, err := h.policies.DryRun(c.Request.Context(), &run)
+	if writeError(c, err) {
+		return
+	}
+
+	items := make([]gin.H, 0, len(results))
+	for i, r := range results {
+		items = append(items, gin.H{
+			"input":   i,
+			"matched": r.Matched,
+			"error":   r.Error,
+			"effect":  r.Effect(),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
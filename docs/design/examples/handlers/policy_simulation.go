@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// PolicySimulationHandler replays recent requests under a draft approval
+// policy before it is saved (platform admin route group).
+//
+//	POST /api/v1/admin/approval-policy/simulate?limit=&environment=  → outcome per request and totals
+//
+// The body is a domain.PolicyDraft: environment policies and/or the full
+// rule set, in the form the policy and rule APIs accept.
+type PolicySimulationHandler struct {
+	simulations *usecase.PolicySimulationUseCase
+}
+
+// NewPolicySimulationHandler creates a new policy simulation handler.
+func NewPolicySimulationHandler(simulations *usecase.PolicySimulationUseCase) *PolicySimulationHandler {
+	return &PolicySimulationHandler{simulations: simulations}
+}
+
+// Simulate routes the last ?limit requests (default 200, at most 2000)
+// under the draft.
+func (h *PolicySimulationHandler) Simulate(c *gin.Context) {
+	var draft domain.PolicyDraft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": "limit must be an integer"})
+			return
+		}
+		limit = n
+	}
+
+	sim, err := h.simulations.Simulate(c.Request.Context(), &draft, domain.DeploymentEnvironment(c.Query("environment")), limit)
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.JSON(http.StatusOK, sim)
+	}
+}
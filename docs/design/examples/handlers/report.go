@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// defaultReportWindow is used when ?since is omitted.
+const defaultReportWindow = 30 * 24 * time.Hour
+
+// ReportHandler serves governance reports (platform admin only).
+//
+//	GET /api/v1/admin/reports/emergency-usage?since=&flagged=true
+//	GET /api/v1/admin/reports/approvals-by-environment?since=
+//	GET /api/v1/admin/reports/approval-sla
+//	GET /api/v1/admin/reports/approval-analytics?window=7d|30d|90d  (or ?from=&to=)
+//	GET /api/v1/admin/reports/usage-trends?scope=system|service&id=&granularity=day|week|month&window=  (&format=csv)
+type ReportHandler struct {
+	ticketRepo repository.ApprovalTicketRepository
+	usageRepo  repository.UsageRepository
+	clock      domain.Clock
+}
+
+// NewReportHandler creates a new report handler.
+func NewReportHandler(ticketRepo repository.ApprovalTicketRepository, usageRepo repository.UsageRepository, clock domain.Clock) *ReportHandler {
+	return &ReportHandler{ticketRepo: ticketRepo, usageRepo: usageRepo, clock: clock}
+}
+
+// reportSince parses ?since (RFC 3339), defaulting to 30 days before now.
+func reportSince(c *gin.Context, now time.Time) (time.Time, bool) {
+	v := c.Query("since")
+	if v == "" {
+		return now.Add(-defaultReportWindow), true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// EmergencyUsage lists requesters with emergency requests since ?since,
+// most emergencies first.
+func (h *ReportHandler) EmergencyUsage(c *gin.Context) {
+	since, ok := reportSince(c, h.clock.Now())
+	if !ok {
+		return
+	}
+
+	usage, err := h.ticketRepo.EmergencyUsage(c.Request.Context(), since)
+	if writeError(c, err) {
+		return
+	}
+
+	onlyFlagged := c.Query("flagged") == "true"
+	items := make([]gin.H, 0, len(usage))
+	for _, u := range usage {
+		if onlyFlagged && !u.Flagged() {
+			continue
+		}
+		items = append(items, gin.H{"usage": u, "flagged": u.Flagged()})
+	}
+	c.JSON(http.StatusOK, gin.H{"since": since, "items": items})
+}
+
+// ApprovalsByEnvironment summarizes tickets per deployment environment:
+// totals, auto-approved, pending, rejected and median time to approval.
+func (h *ReportHandler) ApprovalsByEnvironment(c *gin.Context) {
+	since, ok := reportSince(c, h.clock.Now())
+	if !ok {
+		return
+	}
+	rows, err := h.ticketRepo.ApprovalsByEnvironment(c.Request.Context(), since)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"since": since, "items": rows})
+}
+
+// ApprovalSLA counts the tickets pending now per environment and priority:
+// pending, overdue (past sla_due_at) and escalated, for the admin
+// dashboard. Not windowed: it reports the current backlog.
+func (h *ReportHandler) ApprovalSLA(c *gin.Context) {
+	rows, err := h.ticketRepo.SLAOverdueCounts(c.Request.Context(), h.clock.Now())
+	if writeError(c, err) {
+		return
+	}
+
+	var overdue, escalated int
+	for _, r := range rows {
+		overdue += r.Overdue
+		escalated += r.Escalated
+	}
+	c.JSON(http.StatusOK, gin.H{"overdue": overdue, "escalated": escalated, "items": rows})
+}
+
+// ApprovalAnalytics returns the approval funnel per request type, the
+// auto-approval rate, decision times per approver group and the most
+// frequent rejection reasons for the window (domain/approval_analytics.go).
+func (h *ReportHandler) ApprovalAnalytics(c *gin.Context) {
+	w, err := domain.ParseAnalyticsWindow(c.Query("window"), c.Query("from"), c.Query("to"), h.clock.Now())
+	if writeError(c, err) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	volume, err := h.ticketRepo.ApprovalVolume(ctx, w)
+	if writeError(c, err) {
+		return
+	}
+	times, err := h.ticketRepo.DecisionTimes(ctx, w)
+	if writeError(c, err) {
+		return
+	}
+	reasons, err := h.ticketRepo.RejectionReasons(ctx, w)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, domain.NewApprovalAnalytics(w, volume, times, reasons))
+}
+
+// UsageTrend returns the usage of one System or Service over the window
+// (domain/usage_trend.go), as JSON or, with ?format=csv, as a download.
+func (h *ReportHandler) UsageTrend(c *gin.Context) {
+	scope, err := domain.ParseUsageScope(c.Query("scope"))
+	if writeError(c, err) {
+		return
+	}
+	granularity, err := domain.ParseUsageGranularity(c.Query("granularity"))
+	if writeError(c, err) {
+		return
+	}
+	id := c.Query("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_USAGE_QUERY", "message": "id is required"})
+		return
+	}
+	w, err := domain.ParseAnalyticsWindow(c.Query("window"), c.Query("from"), c.Query("to"), h.clock.Now())
+	if writeError(c, err) {
+		return
+	}
+
+	// One point per day with a snapshot, summed over the System's Services
+	daily, err := h.usageRepo.DailyUsage(c.Request.Context(), scope, id, w)
+	if writeError(c, err) {
+		return
+	}
+	trend := domain.NewUsageTrend(scope, id, granularity, w, daily)
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, trend)
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s-%s.csv"`, scope, id, granularity))
+	c.Status(http.StatusOK)
+	if err := csv.NewWriter(c.Writer).WriteAll(trend.CSVRecords()); err != nil {
+		// Headers are sent; the client sees a truncated file
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ResubmitRequestHandler lets requesters resubmit rejected requests and
+// shows reviewers the rounds before.
+//
+//	POST /api/v1/approvals/:id/resubmit  → 201 new ticket (requester only, REJECTED only)
+//	GET  /api/v1/approvals/:id/history   → resubmission chain, oldest first (participants only)
+type ResubmitRequestHandler struct {
+	resubmit *usecase.ResubmitRequestUseCase
+}
+
+// NewResubmitRequestHandler creates a new resubmit handler.
+func NewResubmitRequestHandler(resubmit *usecase.ResubmitRequestUseCase) *ResubmitRequestHandler {
+	return &ResubmitRequestHandler{resubmit: resubmit}
+}
+
+// Resubmit submits the edited request on behalf of the current user.
+func (h *ResubmitRequestHandler) Resubmit(c *gin.Context) {
+	var body domain.ResubmitEdits
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.resubmit.Execute(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket_id":        res.TicketID,
+		"event_id":         res.EventID,
+		"resubmitted_from": res.ResubmittedFrom,
+		"route":            res.Route,
+	})
+}
+
+// History returns the resubmission chain for the current user.
+func (h *ResubmitRequestHandler) History(c *gin.Context) {
+	rounds, err := h.resubmit.History(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": rounds})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// ResyncHandler exposes the bulk VM resync (platform admin only).
+//
+//	POST /api/v1/admin/resyncs             → 202 + resync_id
+//	GET  /api/v1/admin/resyncs/:id         → progress per cluster
+//	POST /api/v1/admin/resyncs/:id/cancel  → 204
+type ResyncHandler struct {
+	resync *usecase.ResyncVMsUseCase
+}
+
+// NewResyncHandler creates a new resync handler.
+func NewResyncHandler(resync *usecase.ResyncVMsUseCase) *ResyncHandler {
+	return &ResyncHandler{resync: resync}
+}
+
+type startResyncBody struct {
+	Clusters []string `json:"clusters"` // Empty = all clusters
+	Reason   string   `json:"reason" binding:"required"`
+}
+
+// Start starts a resync and returns 202 Accepted (ADR-0006).
+func (h *ResyncHandler) Start(c *gin.Context) {
+	var body startResyncBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	run, err := h.resync.Start(c.Request.Context(), usecase.StartResyncRequest{
+		Clusters:    body.Clusters,
+		Reason:      body.Reason,
+		RequestedBy: c.GetString("user_id"),
+	})
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"code": "CLUSTER_NOT_FOUND", "message": domainerr.MessageKey("CLUSTER_NOT_FOUND")})
+		return
+	case err != nil:
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"resync_id": run.ID})
+}
+
+// Get returns the run and the counters of each cluster.
+func (h *ResyncHandler) Get(c *gin.Context) {
+	run, clusters, err := h.resync.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND"})
+		return
+	}
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resync":   run,
+		"clusters": clusters,
+	})
+}
+
+// Cancel stops a running resync.
+func (h *ResyncHandler) Cancel(c *gin.Context) {
+	err := h.resync.Cancel(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
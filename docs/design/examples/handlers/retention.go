@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// RetentionHandler manages retention policies and legal holds (platform
+// admin route group).
+//
+//	GET    /api/v1/admin/retention-policies               → every record type, defaults included
+//	PUT    /api/v1/admin/retention-policies/:record_type  → set the window
+//	GET    /api/v1/admin/legal-holds                      → active holds
+//	POST   /api/v1/admin/legal-holds                      → place a hold
+//	DELETE /api/v1/admin/legal-holds/:id                  → release a hold
+type RetentionHandler struct {
+	retention *usecase.RetentionUseCase
+}
+
+// NewRetentionHandler creates a new retention handler.
+func NewRetentionHandler(retention *usecase.RetentionUseCase) *RetentionHandler {
+	return &RetentionHandler{retention: retention}
+}
+
+type setRetentionPolicyBody struct {
+	WindowDays int `json:"window_days" binding:"required,min=1"`
+}
+
+type placeLegalHoldBody struct {
+	ResourceType string `json:"resource_type" binding:"required"`
+	ResourceID   string `json:"resource_id" binding:"required"`
+	Reason       string `json:"reason" binding:"required"`
+	CaseRef      string `json:"case_ref"`
+}
+
+// ListPolicies returns the policy of every record type.
+func (h *RetentionHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.retention.Policies(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": policies})
+}
+
+// SetPolicy sets the window of a record type.
+func (h *RetentionHandler) SetPolicy(c *gin.Context) {
+	var body setRetentionPolicyBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	recordType := domain.RetentionRecordType(c.Param("record_type"))
+	policy, err := h.retention.SetPolicy(c.Request.Context(), recordType, body.WindowDays, c.GetString("user_id"))
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// ListHolds returns the active legal holds.
+func (h *RetentionHandler) ListHolds(c *gin.Context) {
+	holds, err := h.retention.ActiveHolds(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": holds})
+}
+
+// PlaceHold places a legal hold as the current user.
+func (h *RetentionHandler) PlaceHold(c *gin.Context) {
+	var body placeLegalHoldBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+	hold, err := h.retention.PlaceHold(c.Request.Context(), usecase.PlaceLegalHoldRequest{
+		ResourceType: body.ResourceType,
+		ResourceID:   body.ResourceID,
+		Reason:       body.Reason,
+		CaseRef:      body.CaseRef,
+		ActorID:      c.GetString("user_id"),
+	})
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.JSON(http.StatusCreated, hold)
+	}
+}
+
+// ReleaseHold releases a legal hold as the current user.
+func (h *RetentionHandler) ReleaseHold(c *gin.Context) {
+	err := h.retention.ReleaseHold(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/invopop/jsonschema"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// SchemaHandler publishes JSON Schemas for event payloads and ModifiedSpec,
+// generated from domain.EventPayloads at startup. Public (no secrets, no
+// tenant data) so external consumers can validate and generate code.
+//
+//	GET /schemas/events                  → index: version and schema URLs
+//	GET /schemas/events/:version/:name   → schema (name: event type or ModifiedSpec)
+type SchemaHandler struct {
+	index   gin.H
+	schemas map[string][]byte // name → schema JSON, current version only
+}
+
+// NewSchemaHandler generates all schemas. baseURL is the external API URL,
+// used for the schemas' $id.
+func NewSchemaHandler(baseURL string) (*SchemaHandler, error) {
+	r := &jsonschema.Reflector{
+		DoNotReference: true, // Self-contained schemas, one file per type
+		ExpandedStruct: true,
+	}
+
+	types := make(map[string]any, len(domain.EventPayloads)+1)
+	for t, v := range domain.EventPayloads {
+		types[string(t)] = v
+	}
+	types[domain.ModifiedSpecSchemaName] = domain.ModifiedSpec{}
+
+	h := &SchemaHandler{schemas: make(map[string][]byte, len(types))}
+	var entries []gin.H
+	for name, v := range types {
+		url := fmt.Sprintf("%s/schemas/events/%s/%s", baseURL, domain.EventSchemaVersion, name)
+		s := r.Reflect(v)
+		s.ID = jsonschema.ID(url)
+		s.Title = name
+
+		data, err := json.Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("marshal schema %s: %w", name, err)
+		}
+		h.schemas[name] = data
+		entries = append(entries, gin.H{"name": name, "url": url})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i]["name"].(string) < entries[j]["name"].(string)
+	})
+	h.index = gin.H{"version": domain.EventSchemaVersion, "schemas": entries}
+	return h, nil
+}
+
+// Index lists the published schemas.
+func (h *SchemaHandler) Index(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, h.index)
+}
+
+// Get returns one schema. Only the current version is served; a consumer
+// pinned to an older version gets 404 SCHEMA_VERSION_GONE after a bump.
+func (h *SchemaHandler) Get(c *gin.Context) {
+	if c.Param("version") != domain.EventSchemaVersion {
+		c.JSON(http.StatusNotFound, gin.H{"code": "SCHEMA_VERSION_GONE", "message": "current version is " + domain.EventSchemaVersion})
+		return
+	}
+	data, ok := h.schemas[c.Param("name")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"code": "SCHEMA_NOT_FOUND", "message": "unknown schema " + c.Param("name")})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/schema+json", data)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// SizeMatchingHandler lists the clusters that can host an InstanceSize,
+// for the approver's cluster dropdown (approval:approve route group).
+//
+//	GET /api/v1/instance-sizes/:name/clusters?environment=test|prod  → per cluster: hosts, missing
+//
+// Clusters that cannot host the size are listed with what they lack, so
+// the dropdown can show them disabled with a reason.
+type SizeMatchingHandler struct {
+	matcher *service.SizeMatchingService
+}
+
+// NewSizeMatchingHandler creates a new size matching handler.
+func NewSizeMatchingHandler(matcher *service.SizeMatchingService) *SizeMatchingHandler {
+	return &SizeMatchingHandler{matcher: matcher}
+}
+
+// Clusters matches the size against the environment's placement candidates.
+func (h *SizeMatchingHandler) Clusters(c *gin.Context) {
+	environment := c.Query("environment")
+	if environment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": "environment is required"})
+		return
+	}
+
+	matches, err := h.matcher.Match(c.Request.Context(), c.Param("name"), environment)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": matches})
+}
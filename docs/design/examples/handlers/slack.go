@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// SlackHandler receives Slack interactivity callbacks. The route is outside
+// session authentication; requests are verified with the Slack signing
+// secret (v0 HMAC, 5 minute timestamp window) instead.
+//
+//	POST /api/v1/integrations/slack/interactions
+//
+// Slack expects an answer within 3 seconds. Decisions are a single DB
+// transaction, so they run inline; the VM work itself is already async.
+type SlackHandler struct {
+	approvals     *service.SlackApprovalService
+	signingSecret string // slack.signing_secret
+	workspaceID   string // slack.workspace_id
+}
+
+// NewSlackHandler creates a new Slack handler.
+func NewSlackHandler(approvals *service.SlackApprovalService, signingSecret, workspaceID string) *SlackHandler {
+	return &SlackHandler{approvals: approvals, signingSecret: signingSecret, workspaceID: workspaceID}
+}
+
+// Interactions handles block actions and view submissions.
+func (h *SlackHandler) Interactions(c *gin.Context) {
+	verifier, err := slack.NewSecretsVerifier(c.Request.Header, h.signingSecret)
+	if err != nil {
+		c.Status(http.StatusUnauthorized) // Missing or stale timestamp
+		return
+	}
+	body, err := io.ReadAll(io.TeeReader(io.LimitReader(c.Request.Body, 1<<20), &verifier))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := verifier.Ensure(); err != nil {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	form, err := parseSlackForm(body)
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	var cb slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form), &cb); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if cb.Team.ID != h.workspaceID {
+		logger.WarnCtx(c.Request.Context(), "Slack interaction from unexpected workspace", zap.String("team_id", cb.Team.ID))
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	ctx := c.Request.Context()
+	switch cb.Type {
+	case slack.InteractionTypeBlockActions:
+		err = h.approvals.HandleBlockAction(ctx, &cb)
+	case slack.InteractionTypeViewSubmission:
+		var resp *slack.ViewSubmissionResponse
+		resp, err = h.approvals.HandleViewSubmission(ctx, &cb)
+		if err == nil && resp != nil {
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+	}
+	if err != nil {
+		// Slack shows a generic failure; details stay in our logs
+		logger.ErrorCtx(ctx, "Slack interaction failed", zap.String("type", string(cb.Type)), zap.Error(err))
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// parseSlackForm extracts the "payload" field of the form-encoded body.
+func parseSlackForm(body []byte) (string, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", err
+	}
+	return values.Get("payload"), nil
+}
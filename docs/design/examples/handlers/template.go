@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/render"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// TemplateHandler exposes template preview and publishing (platform admin only).
+//
+//	POST /api/v1/admin/templates/:id/preview        → rendered output + diff vs active + golden results + lint
+//	POST /api/v1/admin/templates/:id/golden/accept  → accept current output as golden
+//	POST /api/v1/admin/templates/:id/publish        → draft → active (golden, lint and dry-run gates)
+type TemplateHandler struct {
+	publish      *usecase.PublishTemplateUseCase
+	templateRepo repository.TemplateRepository
+}
+
+// NewTemplateHandler creates a new template handler.
+func NewTemplateHandler(publish *usecase.PublishTemplateUseCase, templateRepo repository.TemplateRepository) *TemplateHandler {
+	return &TemplateHandler{
+		publish:      publish,
+		templateRepo: templateRepo,
+	}
+}
+
+// Preview renders the template with sample variables.
+// Read-only and deterministic: the same body and vars always give the same hash.
+func (h *TemplateHandler) Preview(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var vars domain.TemplateVars
+	if err := c.ShouldBindJSON(&vars); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	t, err := h.templateRepo.Get(ctx, c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+
+	// TEMPLATE_RENDER_FAILED, with the offending variables as params
+	cloudInit, manifest, err := render.RenderTemplate(t, vars)
+	if writeError(c, err) {
+		return
+	}
+
+	resp := gin.H{
+		"cloud_init":     cloudInit,
+		"manifest":       manifest,
+		"hash":           render.Hash(cloudInit + "\n---\n" + manifest),
+		"golden_results": render.CheckGolden(t),
+		"lint":           render.Lint(t, vars), // The dry-run only runs at publish
+	}
+
+	// Diff against the currently active version of the same name, if any
+	active, err := h.templateRepo.GetActive(ctx, t.Name)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+	case err != nil:
+		writeError(c, err)
+		return
+	case active.ID != t.ID:
+		if activeCloudInit, activeManifest, err := render.RenderTemplate(active, vars); err == nil {
+			resp["active_version"] = active.Version
+			resp["cloud_init_diff"] = render.Diff(activeCloudInit, cloudInit)
+			resp["manifest_diff"] = render.Diff(activeManifest, manifest)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptGolden records the current render as golden after review.
+func (h *TemplateHandler) AcceptGolden(c *gin.Context) {
+	results, err := h.publish.AcceptGolden(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.writeError(c, err, nil)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"accepted": results})
+}
+
+// Publish activates a draft whose golden cases all match and that passes
+// linting and the dry-run.
+func (h *TemplateHandler) Publish(c *gin.Context) {
+	checks, err := h.publish.Execute(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.writeError(c, err, checks)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": domain.TemplateActive})
+}
+
+// writeError writes err like the package's writeError, adding the golden
+// results or lint findings the author needs to fix a refused publish.
+func (h *TemplateHandler) writeError(c *gin.Context, err error, checks *usecase.TemplateChecks) {
+	switch {
+	case errors.Is(err, domain.ErrTemplateGoldenMismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "TEMPLATE_GOLDEN_MISMATCH", "message": domainerr.MessageKey("TEMPLATE_GOLDEN_MISMATCH"), "golden_results": checks.Golden})
+	case errors.Is(err, domain.ErrTemplateLintFailed):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "TEMPLATE_LINT_FAILED", "message": domainerr.MessageKey("TEMPLATE_LINT_FAILED"), "findings": checks.Findings})
+	default:
+		writeError(c, err)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// TicketCommentHandler serves a ticket's comment thread (participants only).
+//
+//	GET  /api/v1/approvals/:id/comments  → thread, oldest first
+//	POST /api/v1/approvals/:id/comments  → add a comment (201)
+type TicketCommentHandler struct {
+	comments *usecase.TicketCommentUseCase
+}
+
+// NewTicketCommentHandler creates a new ticket comment handler.
+func NewTicketCommentHandler(comments *usecase.TicketCommentUseCase) *TicketCommentHandler {
+	return &TicketCommentHandler{comments: comments}
+}
+
+type createCommentBody struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// Create adds a comment by the current user.
+func (h *TicketCommentHandler) Create(c *gin.Context) {
+	var body createCommentBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	comment, err := h.comments.Create(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body.Body)
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// List returns the thread.
+func (h *TicketCommentHandler) List(c *gin.Context) {
+	comments, err := h.comments.List(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": comments})
+}
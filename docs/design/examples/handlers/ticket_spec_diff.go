@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// TicketSpecDiffHandler serves the original-vs-effective spec of a ticket
+// (participants only).
+//
+//	GET /api/v1/approvals/:id/spec-diff  → original, effective, changed fields
+type TicketSpecDiffHandler struct {
+	diffs *usecase.TicketSpecDiffUseCase
+}
+
+// NewTicketSpecDiffHandler creates a new spec diff handler.
+func NewTicketSpecDiffHandler(diffs *usecase.TicketSpecDiffUseCase) *TicketSpecDiffHandler {
+	return &TicketSpecDiffHandler{diffs: diffs}
+}
+
+// Get returns the diff for the current user.
+func (h *TicketSpecDiffHandler) Get(c *gin.Context) {
+	diff, err := h.diffs.Get(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// UserPrivacyHandler exports and anonymizes user data (platform admin
+// route group).
+//
+//	GET  /api/v1/admin/users/:id/data-export  → JSON document, streamed as an attachment
+//	POST /api/v1/admin/users/:id/anonymize    → 200 counts per table (irreversible)
+type UserPrivacyHandler struct {
+	privacy *usecase.UserPrivacyUseCase
+}
+
+// NewUserPrivacyHandler creates a new user privacy handler.
+func NewUserPrivacyHandler(privacy *usecase.UserPrivacyUseCase) *UserPrivacyHandler {
+	return &UserPrivacyHandler{privacy: privacy}
+}
+
+// Export streams the user's data export.
+func (h *UserPrivacyHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("id")
+
+	// Headers are only sent with the first byte, so a missing user or a
+	// failed audit still gets its own status
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="user-`+userID+`-export.json"`)
+	w := &firstWriteTracker{ResponseWriter: c.Writer}
+
+	err := h.privacy.Export(ctx, userID, c.GetString("user_id"), w)
+	switch {
+	case err == nil:
+	case w.written:
+		// Too late for a status: the client sees a truncated document
+		logger.WarnCtx(logger.WithResource(ctx, "user", userID), "User data export aborted", zap.Error(err))
+	default:
+		writeError(c, err)
+	}
+}
+
+// firstWriteTracker records whether the response body has started.
+type firstWriteTracker struct {
+	gin.ResponseWriter
+	written bool
+}
+
+func (w *firstWriteTracker) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Anonymize anonymizes the user as the current user.
+func (h *UserPrivacyHandler) Anonymize(c *gin.Context) {
+	var body domain.AnonymizeUserRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.privacy.Anonymize(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body)
+	switch {
+	case err != nil:
+		writeError(c, err)
+	default:
+		c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMCloneHandler takes clone requests (vm:operate on the source Service,
+// vm:create on the target Service).
+//
+//	POST /api/v1/vms/:id/clones  → 202 + event_id, ticket_id, target_name and route
+//
+// The clone's VM ID is on the event's result once it completed.
+type VMCloneHandler struct {
+	clones *usecase.CloneVMUseCase
+}
+
+// NewVMCloneHandler creates a new clone handler.
+func NewVMCloneHandler(clones *usecase.CloneVMUseCase) *VMCloneHandler {
+	return &VMCloneHandler{clones: clones}
+}
+
+// Clone requests a clone of the VM, or of one of its snapshots.
+func (h *VMCloneHandler) Clone(c *gin.Context) {
+	var body domain.CloneRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.clones.Submit(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	var denied *domain.GuardrailDenied
+	switch {
+	case errors.As(err, &denied):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "POLICY_GUARDRAIL_DENIED", "message": denied.Message, "policy": denied.Policy, "version": denied.Version})
+		return
+	case err != nil:
+		// Includes APPROVAL_PRECONDITION_FAILED: auto-approved, but the
+		// target Service's quota is used up
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"event_id":    res.EventID,
+		"ticket_id":   res.TicketID,
+		"target_name": res.TargetName,
+		"route":       res.Route,
+	})
+}
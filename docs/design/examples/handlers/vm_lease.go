@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMLeaseHandler takes renewal requests for VM leases (vm:create on the
+// Service).
+//
+//	POST /api/v1/vms/:id/lease/renewals  → 202 + event_id and ticket_id
+//
+// The renewal ticket is approved like any other request; the lease is
+// extended by its final approval.
+type VMLeaseHandler struct {
+	renewals *usecase.LeaseRenewalUseCase
+}
+
+// NewVMLeaseHandler creates a new lease handler.
+func NewVMLeaseHandler(renewals *usecase.LeaseRenewalUseCase) *VMLeaseHandler {
+	return &VMLeaseHandler{renewals: renewals}
+}
+
+// RequestRenewal submits a renewal request.
+func (h *VMLeaseHandler) RequestRenewal(c *gin.Context) {
+	var body domain.LeaseRenewalRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.renewals.RequestRenewal(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"event_id":  res.EventID,
+		"ticket_id": res.TicketID,
+	})
+}
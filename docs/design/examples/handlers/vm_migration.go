@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMMigrationHandler exposes live migration of single VMs (platform admin only).
+//
+//	POST /api/v1/admin/vms/:id/migrations     → 202 + migration
+//	GET  /api/v1/admin/migrations/:id         → progress: status, phase, nodes
+//	POST /api/v1/admin/migrations/:id/cancel  → 202 + migration (cancelled by the worker if running)
+type VMMigrationHandler struct {
+	migrations *usecase.MigrateVMUseCase
+}
+
+// NewVMMigrationHandler creates a new migration handler.
+func NewVMMigrationHandler(migrations *usecase.MigrateVMUseCase) *VMMigrationHandler {
+	return &VMMigrationHandler{migrations: migrations}
+}
+
+// Migrate starts a live migration of the VM.
+func (h *VMMigrationHandler) Migrate(c *gin.Context) {
+	var body domain.MigrationRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	m, err := h.migrations.Submit(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, m)
+}
+
+// Get returns the migration's progress.
+func (h *VMMigrationHandler) Get(c *gin.Context) {
+	m, err := h.migrations.Get(c.Request.Context(), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// Cancel asks to cancel the migration.
+func (h *VMMigrationHandler) Cancel(c *gin.Context) {
+	m, err := h.migrations.Cancel(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, m)
+}
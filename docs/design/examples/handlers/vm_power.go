@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMPowerHandler starts, stops and restarts VMs (vm:operate on the Service).
+//
+//	POST /api/v1/vms/:id/power  → 202 + event_id, ticket_id and route
+//
+// Auto-approved operations run at once; the others wait for approval of
+// their ticket like any other request.
+type VMPowerHandler struct {
+	power *usecase.PowerOperationUseCase
+}
+
+// NewVMPowerHandler creates a new power handler.
+func NewVMPowerHandler(power *usecase.PowerOperationUseCase) *VMPowerHandler {
+	return &VMPowerHandler{power: power}
+}
+
+// Power requests the power operation.
+func (h *VMPowerHandler) Power(c *gin.Context) {
+	var body domain.PowerOperationRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.power.Submit(c.Request.Context(), usecase.PowerOperationRequest{
+		VMID:        c.Param("id"),
+		Action:      body.Action,
+		Reason:      body.Reason,
+		RequestedBy: c.GetString("user_id"),
+	})
+	var denied *domain.GuardrailDenied
+	switch {
+	case errors.As(err, &denied):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "POLICY_GUARDRAIL_DENIED", "message": denied.Message, "policy": denied.Policy, "version": denied.Version})
+		return
+	case err != nil:
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"event_id":  res.EventID,
+		"ticket_id": res.TicketID,
+		"route":     res.Route,
+	})
+}
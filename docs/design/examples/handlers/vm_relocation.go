@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMRelocationHandler exposes cross-cluster relocation of single VMs
+// (platform admin only).
+//
+//	POST /api/v1/admin/vms/:id/relocations       → 202 + relocation
+//	GET  /api/v1/admin/relocations               → relocations in progress, decommission items included
+//	GET  /api/v1/admin/relocations/:id           → progress: status, step_started_at, disks
+//	POST /api/v1/admin/relocations/:id/confirm   → 202 + relocation (cut over by the worker)
+//	POST /api/v1/admin/relocations/:id/abort     → 202 + relocation (rolled back by the worker)
+type VMRelocationHandler struct {
+	relocations *usecase.RelocateVMUseCase
+}
+
+// NewVMRelocationHandler creates a new relocation handler.
+func NewVMRelocationHandler(relocations *usecase.RelocateVMUseCase) *VMRelocationHandler {
+	return &VMRelocationHandler{relocations: relocations}
+}
+
+// Relocate starts relocating the VM to another cluster.
+func (h *VMRelocationHandler) Relocate(c *gin.Context) {
+	var body domain.RelocationRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	r, err := h.relocations.Submit(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, r)
+}
+
+// List returns the relocations in progress.
+func (h *VMRelocationHandler) List(c *gin.Context) {
+	items, err := h.relocations.ListActive(c.Request.Context())
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Get returns the relocation's progress.
+func (h *VMRelocationHandler) Get(c *gin.Context) {
+	r, err := h.relocations.Get(c.Request.Context(), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// Confirm lets a held relocation cut over.
+func (h *VMRelocationHandler) Confirm(c *gin.Context) {
+	r, err := h.relocations.ConfirmCutover(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, r)
+}
+
+// Abort asks to abandon the relocation.
+func (h *VMRelocationHandler) Abort(c *gin.Context) {
+	r, err := h.relocations.Abort(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, r)
+}
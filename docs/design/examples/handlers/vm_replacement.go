@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMReplacementHandler replaces VMs blue/green (vm:create and vm:delete on
+// the Service).
+//
+//	POST /api/v1/vms/:id/replace                → 202 + replacement_id, green's ticket
+//	GET  /api/v1/vm-replacements/:id            → replacement status (readers of the Service)
+//	POST /api/v1/vm-replacements/:id/confirm    → cut over to green
+//	POST /api/v1/vm-replacements/:id/abort      → keep blue, retire green
+type VMReplacementHandler struct {
+	replacements *usecase.VMReplacementUseCase
+}
+
+// NewVMReplacementHandler creates a new replacement handler.
+func NewVMReplacementHandler(replacements *usecase.VMReplacementUseCase) *VMReplacementHandler {
+	return &VMReplacementHandler{replacements: replacements}
+}
+
+// Start requests a replacement of the VM.
+func (h *VMReplacementHandler) Start(c *gin.Context) {
+	var body domain.StartReplacementRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.replacements.Start(c.Request.Context(), c.Param("id"), c.GetString("user_id"), body)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"replacement_id": res.ReplacementID,
+		"event_id":       res.EventID,
+		"ticket_id":      res.TicketID,
+		"route":          res.Route,
+	})
+}
+
+// Get returns the replacement.
+func (h *VMReplacementHandler) Get(c *gin.Context) {
+	r, err := h.replacements.Get(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// Confirm lets green take over.
+func (h *VMReplacementHandler) Confirm(c *gin.Context) {
+	r, err := h.replacements.Confirm(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, r)
+}
+
+// Abort keeps blue and retires green.
+func (h *VMReplacementHandler) Abort(c *gin.Context) {
+	r, err := h.replacements.Abort(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+	c.JSON(http.StatusAccepted, r)
+}
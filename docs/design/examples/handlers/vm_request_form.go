@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/service"
+)
+
+// VMRequestFormHandler serves the VM request form with Service defaults applied.
+//
+//	GET  /api/v1/services/:id/vm-request/ui-schema → form fields + resolved defaults (ETag, 304)
+//	POST /api/v1/services/:id/vm-request/preview   → resolved request for user input
+//	PUT  /api/v1/services/:id/request-defaults     → update (service:update)
+type VMRequestFormHandler struct {
+	defaults *service.RequestDefaultsService
+	forms    *service.RequestFormService
+	sizes    *service.SizeMatchingService
+	clock    domain.Clock
+}
+
+// NewVMRequestFormHandler creates a new handler.
+func NewVMRequestFormHandler(
+	defaults *service.RequestDefaultsService,
+	forms *service.RequestFormService,
+	sizes *service.SizeMatchingService,
+	clock domain.Clock,
+) *VMRequestFormHandler {
+	return &VMRequestFormHandler{defaults: defaults, forms: forms, sizes: sizes, clock: clock}
+}
+
+// UISchema returns the form definition with each field pre-populated.
+// Clients revalidate with If-None-Match; the tag changes with any catalog
+// the form is built from.
+func (h *VMRequestFormHandler) UISchema(c *gin.Context) {
+	form, etag, err := h.forms.Form(c.Request.Context(), c.Param("id"))
+	if writeError(c, err) {
+		return
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache") // Always revalidate
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+type previewBody struct {
+	TemplateID     string `json:"template_id"`
+	InstanceSizeID string `json:"instance_size_id"`
+	Namespace      string `json:"namespace"`
+}
+
+// Preview resolves user input against Service defaults without submitting.
+func (h *VMRequestFormHandler) Preview(c *gin.Context) {
+	var body previewBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	resolved, err := h.defaults.Resolve(c.Request.Context(), service.RequestInput{
+		ServiceID:      c.Param("id"),
+		TemplateID:     body.TemplateID,
+		InstanceSizeID: body.InstanceSizeID,
+		Namespace:      body.Namespace,
+	})
+	if writeError(c, err) {
+		return
+	}
+
+	// Same check as submission: a size no cluster can host is reported
+	// before the requester submits, not after an approver looks at it;
+	// size_error carries the code
+	sizeError := ""
+	if resolved.InstanceSize.Value != "" {
+		err := h.sizes.CheckForService(c.Request.Context(), resolved.ServiceID, resolved.InstanceSize.Value)
+		switch {
+		case errors.Is(err, domain.ErrNoClusterForSize):
+			sizeError = domainerr.CodeOf(err)
+		case errors.Is(err, repository.ErrNotFound):
+			sizeError = "NOT_FOUND"
+		case err != nil:
+			writeError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"request":    resolved,
+		"complete":   resolved.Complete() && sizeError == "",
+		"size_error": sizeError,
+	})
+}
+
+type requestDefaultsBody struct {
+	TemplateName     string `json:"template_name"`
+	InstanceSizeName string `json:"instance_size_name"`
+	Namespace        string `json:"namespace"`
+}
+
+// UpdateDefaults stores the Service's defaults.
+// Route is guarded by RequirePermission("service:update") on the Service.
+func (h *VMRequestFormHandler) UpdateDefaults(c *gin.Context) {
+	var body requestDefaultsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	d := &domain.ServiceRequestDefaults{
+		ServiceID:        c.Param("id"),
+		TemplateName:     body.TemplateName,
+		InstanceSizeName: body.InstanceSizeName,
+		Namespace:        body.Namespace,
+		UpdatedBy:        c.GetString("user_id"),
+		UpdatedAt:        h.clock.Now(),
+	}
+	err := h.defaults.Update(c.Request.Context(), d)
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMRestoreHandler takes restore requests (vm:operate on the Service).
+// Restore points are listed by ListHandler.RestorePoints.
+//
+//	POST /api/v1/vms/:id/restores → 202 + event_id, ticket_id and safety_snapshot_name
+type VMRestoreHandler struct {
+	restores *usecase.RestoreVMUseCase
+}
+
+// NewVMRestoreHandler creates a new restore handler.
+func NewVMRestoreHandler(restores *usecase.RestoreVMUseCase) *VMRestoreHandler {
+	return &VMRestoreHandler{restores: restores}
+}
+
+// Restore requests restoring the VM to one of its restore points.
+func (h *VMRestoreHandler) Restore(c *gin.Context) {
+	var body domain.RestoreRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.restores.Submit(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	if writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"event_id":             res.EventID,
+		"ticket_id":            res.TicketID,
+		"safety_snapshot_name": res.SafetySnapshotName,
+	})
+}
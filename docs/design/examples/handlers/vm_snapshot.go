@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VMSnapshotHandler takes snapshot and backup requests (vm:operate on the
+// Service).
+//
+//	POST /api/v1/vms/:id/snapshots  → 202 + event_id, ticket_id, snapshot_name and route
+//	POST /api/v1/vms/:id/backups    → 202 + event_id, ticket_id, backup_name and route
+//
+// The snapshot's size, or the backup's restore point, is on the event's
+// result once it completed.
+type VMSnapshotHandler struct {
+	snapshots *usecase.SnapshotVMUseCase
+}
+
+// NewVMSnapshotHandler creates a new snapshot handler.
+func NewVMSnapshotHandler(snapshots *usecase.SnapshotVMUseCase) *VMSnapshotHandler {
+	return &VMSnapshotHandler{snapshots: snapshots}
+}
+
+// Snapshot requests a snapshot of the VM.
+func (h *VMSnapshotHandler) Snapshot(c *gin.Context) {
+	h.submit(c, "snapshot_name", h.snapshots.Submit)
+}
+
+// Backup requests a backup of the VM by the configured backup provider.
+func (h *VMSnapshotHandler) Backup(c *gin.Context) {
+	h.submit(c, "backup_name", h.snapshots.SubmitBackup)
+}
+
+func (h *VMSnapshotHandler) submit(c *gin.Context, nameKey string,
+	submit func(context.Context, string, domain.SnapshotRequest, string) (*usecase.SnapshotVMResult, error)) {
+	var body domain.SnapshotRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := submit(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	var denied *domain.GuardrailDenied
+	switch {
+	case errors.As(err, &denied):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "POLICY_GUARDRAIL_DENIED", "message": denied.Message, "policy": denied.Policy, "version": denied.Version})
+		return
+	case err != nil:
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"event_id":  res.EventID,
+		"ticket_id": res.TicketID,
+		nameKey:     res.Name,
+		"route":     res.Route,
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/usecase"
+)
+
+// VNCAccessHandler takes VNC console access requests (vnc:access on the
+// Service).
+//
+//	POST   /api/v1/vms/:id/vnc/requests           → 201 + token when auto-approved, 202 + ticket_id otherwise
+//	GET    /api/v1/vnc/requests/:ticket_id/token  → token of the approved request (requester only)
+//	DELETE /api/v1/vnc/tokens/:id                 → 204, grant revoked (holder or platform admin)
+//
+// The token is redeemed by the console proxy (RFC-0011), not here. VNC
+// tickets are approved like any other and rejected via the common
+// rejection endpoint.
+type VNCAccessHandler struct {
+	vnc *usecase.VNCAccessUseCase
+}
+
+// NewVNCAccessHandler creates a new VNC access handler.
+func NewVNCAccessHandler(vnc *usecase.VNCAccessUseCase) *VNCAccessHandler {
+	return &VNCAccessHandler{vnc: vnc}
+}
+
+// Request submits a console access request.
+func (h *VNCAccessHandler) Request(c *gin.Context) {
+	var body domain.VNCAccessRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	res, err := h.vnc.Request(c.Request.Context(), c.Param("id"), body, c.GetString("user_id"))
+	if writeVNCError(c, err) {
+		return
+	}
+	if res.Grant == nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"event_id":  res.EventID,
+			"ticket_id": res.TicketID,
+			"route":     res.Route,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket_id":  res.TicketID,
+		"token_id":   res.Grant.TokenID,
+		"token":      res.Token,
+		"expires_at": res.Grant.ExpiresAt,
+	})
+}
+
+// Token returns the token of an approved request.
+func (h *VNCAccessHandler) Token(c *gin.Context) {
+	token, grant, err := h.vnc.Token(c.Request.Context(), c.Param("ticket_id"), c.GetString("user_id"))
+	if writeVNCError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"token_id":   grant.TokenID,
+		"token":      token,
+		"expires_at": grant.ExpiresAt,
+	})
+}
+
+// Revoke ends a grant.
+func (h *VNCAccessHandler) Revoke(c *gin.Context) {
+	err := h.vnc.Revoke(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if writeVNCError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// writeVNCError writes err, if any, and reports whether it did.
+func writeVNCError(c *gin.Context, err error) bool {
+	var denied *domain.GuardrailDenied
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &denied):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"code": "POLICY_GUARDRAIL_DENIED", "message": denied.Message, "policy": denied.Policy, "version": denied.Version})
+	default:
+		writeError(c, err)
+	}
+	return true
+}
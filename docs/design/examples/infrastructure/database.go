@@ -8,18 +8,27 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
 
 	"entgo.io/ent/dialect"
 	entsql "entgo.io/ent/dialect/sql"
 	"github.com/riverqueue/river"
 	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivertype"
 
 	"kv-shepherd.io/shepherd/ent"
 	"kv-shepherd.io/shepherd/internal/config"
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
 	"kv-shepherd.io/shepherd/internal/repository/sqlc"
 )
 
@@ -38,21 +47,20 @@ type DatabaseClients struct {
 	// SqlcQueries is the sqlc query client (for core transactions)
 	SqlcQueries *sqlc.Queries
 
-	// WorkerPool is optional: separate pool for PgBouncer scenarios
-	// nil means reuse Pool
+	// WorkerPool is optional: direct (session) connections next to a Pool
+	// going through PgBouncer. Required in PgBouncer mode; nil means
+	// reuse Pool. See GetWorkerPool for what must use it.
 	WorkerPool *pgxpool.Pool
 }
 
 // NewDatabaseClients creates database clients with shared connection pool.
 func NewDatabaseClients(ctx context.Context, cfg config.DatabaseConfig) (*DatabaseClients, error) {
-	// Build PostgreSQL DSN
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database,
-	)
+	if cfg.PgBouncer && cfg.WorkerHost == "" {
+		return nil, errors.New("database.pgbouncer requires database.worker_host: River, listeners and leader election need session connections")
+	}
 
 	// Parse pool configuration
-	poolConfig, err := pgxpool.ParseConfig(dsn)
+	poolConfig, err := pgxpool.ParseConfig(databaseDSN(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("parse pool config: %w", err)
 	}
@@ -61,16 +69,35 @@ func NewDatabaseClients(ctx context.Context, cfg config.DatabaseConfig) (*Databa
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
 
+	if cfg.PgBouncer {
+		// Transaction pooling runs each transaction on any server
+		// connection, so a statement prepared on one is unknown on the
+		// next ("prepared statement does not exist"). Exec mode sends
+		// unnamed statements, parsed on every execution; nothing is
+		// cached per connection.
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+		poolConfig.ConnConfig.StatementCacheCapacity = 0
+		poolConfig.ConnConfig.DescriptionCacheCapacity = 0
+	}
+
 	// Create shared connection pool
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create pool: %w", err)
 	}
 
-	// Verify connection
-	if err := pool.Ping(ctx); err != nil {
+	// Verify connection; a failover in progress is waited out, not a crash
+	if err := waitForDatabase(ctx, pool, cfg.StartupTimeout); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("ping database: %w", err)
+		return nil, err
+	}
+
+	// Optional RLS mode: refuse to start without the policies in place
+	if cfg.RowLevelSecurity {
+		if err := repository.VerifyRLS(ctx, pool); err != nil {
+			pool.Close()
+			return nil, err
+		}
 	}
 
 	// Ent Client: reuse pgxpool via stdlib.OpenDBFromPool
@@ -81,11 +108,12 @@ func NewDatabaseClients(ctx context.Context, cfg config.DatabaseConfig) (*Databa
 	// sqlc Queries: use pgxpool directly
 	sqlcQueries := sqlc.New(pool)
 
-	// Optional: separate WorkerPool for PgBouncer
+	// Optional: separate WorkerPool for PgBouncer, direct to PostgreSQL
 	var workerPool *pgxpool.Pool
 	if cfg.WorkerHost != "" {
-		workerDSN := fmt.Sprintf("postgres://%s:%s@%s:%d/%s",
-			cfg.User, cfg.Password, cfg.WorkerHost, cfg.WorkerPort, cfg.Database)
+		workerDSN := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?target_session_attrs=%s&connect_timeout=%d",
+			cfg.User, cfg.Password, cfg.WorkerHost, cfg.WorkerPort, cfg.Database,
+			cfg.TargetSessionAttrs, int(cfg.ConnectTimeout.Seconds()))
 		workerPool, err = pgxpool.New(ctx, workerDSN)
 		if err != nil {
 			pool.Close()
@@ -101,8 +129,61 @@ func NewDatabaseClients(ctx context.Context, cfg config.DatabaseConfig) (*Databa
 	}, nil
 }
 
+// databaseDSN builds the connection string. With several hosts pgx tries
+// them in order and, with target_session_attrs=read-write, skips standbys,
+// so a promoted replica is found without a DNS change.
+func databaseDSN(cfg config.DatabaseConfig) string {
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)}
+	}
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=disable&target_session_attrs=%s&connect_timeout=%d",
+		cfg.User, cfg.Password, strings.Join(hosts, ","), cfg.Database,
+		cfg.TargetSessionAttrs, int(cfg.ConnectTimeout.Seconds()),
+	)
+}
+
+// waitForDatabase pings until the primary answers or timeout passes. A
+// managed failover leaves no primary for up to a minute; exiting would
+// only restart the pod into the same wait, with growing backoff.
+func waitForDatabase(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := time.Second
+	for {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.Warn("Database not reachable, retrying",
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("ping database: %w", err)
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, 10*time.Second)
+	}
+}
+
 // GetWorkerPool returns the worker connection pool.
 // Returns WorkerPool if configured, otherwise returns shared Pool.
+//
+// Everything relying on session state takes its connections from here,
+// never from Pool, so it keeps working when Pool goes through PgBouncer:
+//
+//	River client          LISTEN for job notifications, leader election
+//	LeaderElection        session advisory lock on a hijacked connection
+//	RunCatalogListener    LISTEN
+//	RunChangeListener     LISTEN
+//
+// Use cases stay on Pool: River's InsertTx joins their transaction, and
+// transaction-scoped state (SET LOCAL, pg_advisory_xact_lock, NOTIFY) is
+// safe under transaction pooling.
 func (c *DatabaseClients) GetWorkerPool() *pgxpool.Pool {
 	if c.WorkerPool != nil {
 		return c.WorkerPool
@@ -110,14 +191,24 @@ func (c *DatabaseClients) GetWorkerPool() *pgxpool.Pool {
 	return c.Pool
 }
 
-// NewRiverClient creates a River queue client.
-func (c *DatabaseClients) NewRiverClient(workers *river.Workers, cfg config.RiverConfig) (*river.Client[pgx.Tx], error) {
+// NewRiverClient creates a River queue client. middleware wraps every job:
+// jobs.LogContextMiddleware always, first; chaos.WorkerMiddleware in
+// resilience tests.
+func (c *DatabaseClients) NewRiverClient(workers *river.Workers, cfg config.RiverConfig, middleware ...rivertype.Middleware) (*river.Client[pgx.Tx], error) {
 	return river.NewClient(riverpgxv5.New(c.GetWorkerPool()), &river.Config{
+		Middleware: middleware,
 		Queues: map[string]river.QueueConfig{
-			river.QueueDefault: {MaxWorkers: cfg.MaxWorkers},
+			river.QueueDefault:    {MaxWorkers: cfg.MaxWorkers},
+			domain.QueueHigh:      {MaxWorkers: cfg.HighPriorityWorkers},
+			domain.QueueEmergency: {MaxWorkers: cfg.EmergencyPriorityWorkers},
 		},
-		Workers:                     workers,
-		CompletedJobRetentionPeriod: cfg.CompletedJobRetentionPeriod,
+		Workers: workers,
+		// River's cleaner is off (-1): finalized jobs are job history,
+		// purged under its retention policy and legal holds
+		// (jobs.RetentionPurgeWorker).
+		CompletedJobRetentionPeriod: -1,
+		CancelledJobRetentionPeriod: -1,
+		DiscardedJobRetentionPeriod: -1,
 	})
 }
 
@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// DatabaseState is the pools' view of the primary, reported by
+// /health/ready.
+type DatabaseState string
+
+const (
+	DatabaseOK       DatabaseState = "ok"
+	DatabaseFailover DatabaseState = "failover" // Primary unreachable or demoted; pools reset, reconnecting
+)
+
+// DatabaseMonitor carries the pools through a managed PostgreSQL failover
+// (Phase 0 §6 Managed PostgreSQL Failover).
+//
+//	Primary fails                         Monitor (every health_check_interval)
+//	──────────────────────────────────────────────────────────────────────────
+//	connections error or hang             probe fails → failover, pool.Reset()
+//	standby promoted, DNS record moves    new connections resolve the name again
+//	old primary back as a standby         probe sees pg_is_in_recovery → Reset
+//	probe succeeds on the new primary     ok
+//
+// Pooled connections never re-resolve: one opened before the failover keeps
+// talking to the old address for up to max_conn_lifetime, and an old
+// primary that rejoins as a standby accepts it and fails every write.
+// Reset closes them all; pgx dials again with target_session_attrs and
+// connect_timeout, so new connections land on the primary.
+//
+// The replica stays alive throughout: /health/ready returns 503 while in
+// failover, so no traffic is routed here, and /health/live stays 200, so
+// Kubernetes does not restart it into a crash loop. River and the leader
+// elections retry on their own.
+type DatabaseMonitor struct {
+	pools    []*pgxpool.Pool
+	interval time.Duration
+
+	mu      sync.RWMutex
+	state   DatabaseState
+	since   time.Time
+	lastErr error
+}
+
+// NewDatabaseMonitor creates a monitor for the clients' pools. interval
+// comes from database.health_check_interval.
+func (c *DatabaseClients) NewDatabaseMonitor(interval time.Duration) *DatabaseMonitor {
+	pools := []*pgxpool.Pool{c.Pool}
+	if c.WorkerPool != nil {
+		pools = append(pools, c.WorkerPool)
+	}
+	return &DatabaseMonitor{
+		pools:    pools,
+		interval: interval,
+		state:    DatabaseOK,
+		since:    time.Now(),
+	}
+}
+
+// State returns the current state.
+func (m *DatabaseMonitor) State() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return string(m.state)
+}
+
+// StateSince returns when the current state began.
+func (m *DatabaseMonitor) StateSince() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.since
+}
+
+// LastError returns the error of the last failed probe; nil when ok.
+func (m *DatabaseMonitor) LastError() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr
+}
+
+// Run probes the pools until ctx is done.
+func (m *DatabaseMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *DatabaseMonitor) check(ctx context.Context) {
+	var failed error
+	for _, pool := range m.pools {
+		if err := probePrimary(ctx, pool, m.interval); err != nil {
+			// Drop every connection, idle ones included; those in use
+			// are closed when released
+			pool.Reset()
+			failed = err
+		}
+	}
+	m.transition(failed)
+}
+
+var errDemoted = errors.New("connected to a standby")
+
+// probePrimary checks a pooled connection still reaches a writable primary.
+func probePrimary(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var inRecovery bool
+	if err := pool.QueryRow(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return fmt.Errorf("probe primary: %w", err)
+	}
+	if inRecovery {
+		return errDemoted
+	}
+	return nil
+}
+
+func (m *DatabaseMonitor) transition(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastErr = err
+
+	switch {
+	case err != nil && m.state == DatabaseOK:
+		m.state, m.since = DatabaseFailover, time.Now()
+		logger.Warn("Database failover detected, connections reset", zap.Error(err))
+	case err == nil && m.state == DatabaseFailover:
+		logger.Info("Database primary reachable again", zap.Duration("failover", time.Since(m.since)))
+		m.state, m.since = DatabaseOK, time.Now()
+	}
+}
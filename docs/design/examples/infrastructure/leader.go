@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/panjf2000/ants/v2"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// LeaderElection runs a component on exactly one replica at a time, using
+// a PostgreSQL session advisory lock (no extra infrastructure).
+//
+// What needs it:
+//
+//	Component                      Singleton via
+//	─────────────────────────────────────────────────────────────
+//	ResourceWatcher (per cluster)  LeaderElection("watcher:<cluster>")
+//	River periodic jobs            River's own leader election
+//	River workers                  none: every replica works the queues
+//	Catalog cache listener         none: every replica keeps its own cache
+//
+// The lock is held on a dedicated connection taken out of GetWorkerPool()
+// (count one per election in max_conns): a session lock taken through
+// PgBouncer in transaction pooling would stay on a server connection
+// other clients then use. PostgreSQL releases it when that connection
+// dies, so a crashed leader is replaced after at most one check interval.
+// The old leader notices at its next ping and stops, but the two may
+// overlap briefly: components must write idempotently (e.g., the watcher
+// applies a VM only if its resourceVersion is newer).
+//
+// The component runs on the general worker pool (Pools.General), whose
+// panic handler logs a panic; the election then treats the component as
+// stopped and contends again.
+type LeaderElection struct {
+	pool     *pgxpool.Pool
+	workers  *ants.Pool
+	name     string
+	key      int64
+	interval time.Duration
+
+	leader atomic.Bool
+	since  atomic.Int64 // Unix nanoseconds of the last role change
+}
+
+// NewLeaderElection creates an election for the named component.
+// interval comes from server.leader_check_interval; workers is
+// Pools.General.
+func NewLeaderElection(pool *pgxpool.Pool, workers *ants.Pool, name string, interval time.Duration) *LeaderElection {
+	h := fnv.New64a()
+	h.Write([]byte("shepherd:" + name))
+
+	l := &LeaderElection{
+		pool:     pool,
+		workers:  workers,
+		name:     name,
+		key:      int64(h.Sum64()),
+		interval: interval,
+	}
+	l.since.Store(time.Now().UnixNano())
+	return l
+}
+
+// Name returns the component name.
+func (l *LeaderElection) Name() string { return l.name }
+
+// IsLeader reports whether this replica currently runs the component.
+func (l *LeaderElection) IsLeader() bool { return l.leader.Load() }
+
+// Since returns when the role last changed.
+func (l *LeaderElection) Since() time.Time { return time.Unix(0, l.since.Load()) }
+
+// Run contends for leadership until ctx is done and runs fn while leading.
+// fn must return when its context is cancelled (leadership lost). If fn
+// returns on its own, the lock is released and contended for again.
+func (l *LeaderElection) Run(ctx context.Context, fn func(ctx context.Context) error) {
+	for ctx.Err() == nil {
+		err := l.lead(ctx, fn)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !errors.Is(err, errNotLeader) {
+			logger.Warn("Leadership lost",
+				zap.String("component", l.name),
+				zap.Error(err),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.interval):
+		}
+	}
+}
+
+var (
+	errNotLeader         = errors.New("lock held by another replica")
+	errComponentPanicked = errors.New("component panicked")
+)
+
+func (l *LeaderElection) lead(ctx context.Context, fn func(ctx context.Context) error) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// Session lock: the connection must never go back to the pool.
+	// Closing it releases the lock.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		return fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !acquired {
+		return errNotLeader
+	}
+
+	l.setLeader(true)
+	defer l.setLeader(false)
+	logger.Info("Leadership acquired", zap.String("component", l.name))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	err = l.workers.Submit(func() {
+		err := errComponentPanicked // Unless fn returns
+		defer func() { done <- err }()
+		err = fn(runCtx)
+	})
+	if err != nil {
+		return fmt.Errorf("submit component: %w", err)
+	}
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("component stopped: %w", err)
+			}
+			return nil
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-ticker.C:
+			// Lock connection gone: PostgreSQL has released the lock and
+			// another replica may already lead. Stop before doing more.
+			if err := conn.Ping(ctx); err != nil {
+				cancel()
+				<-done
+				return fmt.Errorf("ping lock connection: %w", err)
+			}
+		}
+	}
+}
+
+func (l *LeaderElection) setLeader(leader bool) {
+	l.leader.Store(leader)
+	l.since.Store(time.Now().UnixNano())
+}
@@ -0,0 +1,203 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/panjf2000/ants/v2"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// Phase is a replica's position in its lifecycle, reported by /health/ready.
+type Phase string
+
+const (
+	PhaseStarting Phase = "starting" // Warm-ups running, not ready
+	PhaseReady    Phase = "ready"
+	PhaseDraining Phase = "draining" // SIGTERM received, finishing work
+	PhaseStopped  Phase = "stopped"
+)
+
+// Lifecycle coordinates a replica's start and drain for rolling deploys
+// (Phase 3 §5 Zero-Downtime Deploys).
+//
+//	New replica                        Old replica (SIGTERM)
+//	─────────────────────────────────────────────────────────────────
+//	starting: warm-ups (catalog cache) draining: /health/ready → 503
+//	ready:    /health/ready → 200      wait drain_delay (still serving)
+//	          takes over watchers  ←── resign leader elections
+//	                                   HTTP + River drain until deadline
+//	                                   stopped
+//
+// Kubernetes' terminationGracePeriodSeconds must exceed
+// server.drain_delay + server.shutdown_timeout.
+type Lifecycle struct {
+	drainDelay time.Duration
+	timeout    time.Duration
+	workers    *ants.Pool // Pools.General, released after Drain returns
+
+	mu       sync.RWMutex
+	phase    Phase
+	since    time.Time
+	deadline time.Time // Set while draining
+	warmups  []warmup
+}
+
+type warmup struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewLifecycle creates a lifecycle in the starting phase. drainDelay and
+// timeout come from server.drain_delay and server.shutdown_timeout;
+// workers is Pools.General.
+func NewLifecycle(drainDelay, timeout time.Duration, workers *ants.Pool) *Lifecycle {
+	return &Lifecycle{
+		drainDelay: drainDelay,
+		timeout:    timeout,
+		workers:    workers,
+		phase:      PhaseStarting,
+		since:      time.Now(),
+	}
+}
+
+// AddWarmup registers a step that must succeed before the replica is ready,
+// e.g. loading the catalog cache so the first requests do not all miss.
+func (l *Lifecycle) AddWarmup(name string, fn func(ctx context.Context) error) {
+	l.warmups = append(l.warmups, warmup{name: name, fn: fn})
+}
+
+// Start runs the warm-ups in order and marks the replica ready. On error
+// the replica stays starting; the startup probe fails and Kubernetes
+// restarts it while the old replicas keep serving.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for _, w := range l.warmups {
+		began := time.Now()
+		if err := w.fn(ctx); err != nil {
+			return fmt.Errorf("warm up %s: %w", w.name, err)
+		}
+		logger.Info("Warm-up done",
+			zap.String("step", w.name),
+			zap.Duration("took", time.Since(began)),
+		)
+	}
+	l.setPhase(PhaseReady, time.Time{})
+	return nil
+}
+
+// Phase returns the current phase.
+func (l *Lifecycle) Phase() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return string(l.phase)
+}
+
+// PhaseSince returns when the current phase began.
+func (l *Lifecycle) PhaseSince() time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.since
+}
+
+// DrainDeadline returns when a drain gives up on running work; zero unless
+// draining.
+func (l *Lifecycle) DrainDeadline() time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.deadline
+}
+
+// DrainTargets are the components a drain stops, in this order.
+type DrainTargets struct {
+	// StopWatchers cancels the leader elections' Run contexts. Closing the
+	// lock connections releases the locks for the new replicas.
+	StopWatchers context.CancelFunc
+	WatchersDone <-chan struct{} // Closed when every Run has returned
+
+	HTTP  *http.Server
+	River *river.Client[pgx.Tx]
+}
+
+// Drain stops the replica without dropping work:
+//
+//  1. /health/ready fails; HTTP keeps serving for drain_delay while the
+//     endpoint removal propagates to Services and ingresses.
+//  2. Leader elections resign, so watchers move without waiting for the
+//     lock connection to time out.
+//  3. HTTP finishes in-flight requests and River finishes running jobs,
+//     concurrently, until shutdown_timeout.
+//  4. Jobs still running at the deadline are cancelled. River retries them
+//     on another replica; handlers are idempotent (Multiple Replicas).
+func (l *Lifecycle) Drain(t DrainTargets) error {
+	deadline := time.Now().Add(l.drainDelay + l.timeout)
+	l.setPhase(PhaseDraining, deadline)
+	logger.Info("Draining", zap.Time("deadline", deadline))
+
+	time.Sleep(l.drainDelay)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	if t.StopWatchers != nil {
+		t.StopWatchers()
+		select {
+		case <-t.WatchersDone:
+		case <-ctx.Done():
+			logger.Warn("Watchers did not stop before the drain deadline")
+		}
+	}
+
+	var wg sync.WaitGroup
+	httpErr, riverErr := errDrainPanicked, errDrainPanicked // Unless the step returns
+	wg.Add(2)
+	if err := l.workers.Submit(func() {
+		defer wg.Done()
+		httpErr = t.HTTP.Shutdown(ctx)
+	}); err != nil {
+		wg.Done()
+		httpErr = fmt.Errorf("submit: %w", err)
+	}
+	if err := l.workers.Submit(func() {
+		defer wg.Done()
+		// Stops fetching and waits for running jobs
+		if riverErr = t.River.Stop(ctx); errors.Is(riverErr, context.DeadlineExceeded) {
+			logger.Warn("Cancelling jobs still running at the drain deadline")
+			cancelCtx, cancelStop := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelStop()
+			riverErr = t.River.StopAndCancel(cancelCtx)
+		}
+	}); err != nil {
+		wg.Done()
+		riverErr = fmt.Errorf("submit: %w", err)
+	}
+	wg.Wait()
+
+	l.setPhase(PhaseStopped, time.Time{})
+	if httpErr != nil {
+		return fmt.Errorf("drain http: %w", httpErr)
+	}
+	if riverErr != nil {
+		return fmt.Errorf("drain river: %w", riverErr)
+	}
+	return nil
+}
+
+// errDrainPanicked is a drain step's error when it panicked; the worker
+// pool's panic handler logs the panic.
+var errDrainPanicked = errors.New("drain step panicked")
+
+func (l *Lifecycle) setPhase(phase Phase, deadline time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.phase = phase
+	l.since = time.Now()
+	l.deadline = deadline
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// AuditArchiveArgs writes unarchived audit logs to the archive bucket
+// (domain/audit_archive.go). Registered only with audit_archive.enabled.
+//
+// Not event-driven: platform maintenance, like the retention purge.
+type AuditArchiveArgs struct{}
+
+// Kind returns the River job kind.
+func (AuditArchiveArgs) Kind() string { return "audit_archive" }
+
+// InsertOpts keeps at most one run per period: batches are numbered in
+// sequence, so two runs would only contend for the same number.
+func (AuditArchiveArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewAuditArchivePeriodicJob schedules the archive every
+// audit_archive.batch_interval (default 5m).
+func NewAuditArchivePeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return AuditArchiveArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// AuditArchiver writes audit log batches and anchors to the archive.
+// Implemented by usecase.AuditArchiveUseCase.
+type AuditArchiver interface {
+	// Archive writes the backlog and returns the batches written, also
+	// those written before an error.
+	Archive(ctx context.Context) ([]*domain.AuditBatch, error)
+
+	// Anchor anchors the batches since the last anchor; nil if none.
+	Anchor(ctx context.Context) (*domain.AuditAnchor, error)
+}
+
+// AuditArchiveWorker runs AuditArchiver.Archive.
+type AuditArchiveWorker struct {
+	river.WorkerDefaults[AuditArchiveArgs]
+
+	archive AuditArchiver
+}
+
+// NewAuditArchiveWorker creates a new worker.
+func NewAuditArchiveWorker(archive AuditArchiver) *AuditArchiveWorker {
+	return &AuditArchiveWorker{archive: archive}
+}
+
+// Timeout bounds a run; the batches committed before it stand.
+func (w *AuditArchiveWorker) Timeout(*river.Job[AuditArchiveArgs]) time.Duration {
+	return 10 * time.Minute
+}
+
+// Work archives the backlog. An error retries the job; the audit purge
+// keeps unarchived audit logs meanwhile, so nothing is lost while the
+// bucket is unreachable.
+func (w *AuditArchiveWorker) Work(ctx context.Context, job *river.Job[AuditArchiveArgs]) error {
+	batches, err := w.archive.Archive(ctx)
+	for _, b := range batches {
+		logger.InfoCtx(ctx, "Audit logs archived",
+			zap.Int64("batch", b.Seq),
+			zap.Int("records", b.Records),
+			zap.String("chain", b.Chain),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("archive audit logs: %w", err)
+	}
+	return nil
+}
+
+// AuditArchiveAnchorArgs anchors the batches archived since the last
+// anchor. Registered only with audit_archive.enabled.
+type AuditArchiveAnchorArgs struct{}
+
+// Kind returns the River job kind.
+func (AuditArchiveAnchorArgs) Kind() string { return "audit_archive_anchor" }
+
+// InsertOpts keeps at most one anchor per period.
+func (AuditArchiveAnchorArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Hour},
+	}
+}
+
+// NewAuditArchiveAnchorPeriodicJob schedules anchoring every
+// audit_archive.anchor_interval (default 24h).
+func NewAuditArchiveAnchorPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return AuditArchiveAnchorArgs{}, nil
+		},
+		nil, // Not on start: a rolling deploy would anchor on every replica start
+	)
+}
+
+// AuditArchiveAnchorWorker runs AuditArchiver.Anchor.
+type AuditArchiveAnchorWorker struct {
+	river.WorkerDefaults[AuditArchiveAnchorArgs]
+
+	archive AuditArchiver
+}
+
+// NewAuditArchiveAnchorWorker creates a new worker.
+func NewAuditArchiveAnchorWorker(archive AuditArchiver) *AuditArchiveAnchorWorker {
+	return &AuditArchiveAnchorWorker{archive: archive}
+}
+
+// Work writes the anchor. The anchored digest is logged as well as
+// audited, for log pipelines that keep their own copy.
+func (w *AuditArchiveAnchorWorker) Work(ctx context.Context, job *river.Job[AuditArchiveAnchorArgs]) error {
+	anchor, err := w.archive.Anchor(ctx)
+	if err != nil {
+		return fmt.Errorf("anchor audit archive: %w", err)
+	}
+	if anchor != nil {
+		logger.InfoCtx(ctx, "Audit archive anchored",
+			zap.Int64("anchor", anchor.Seq),
+			zap.Int64("to_batch", anchor.ToBatch),
+			zap.String("chain", anchor.Chain),
+			zap.Bool("timestamped", len(anchor.Timestamp) > 0),
+		)
+	}
+	return nil
+}
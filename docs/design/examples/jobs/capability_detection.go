@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// CapabilityDetectionArgs re-detects cluster capabilities (ADR-0014).
+//
+// Not event-driven (no DomainEvent): this is platform maintenance, like
+// the schema cache refresh. Enqueued:
+//   - at cluster registration with ClusterID set (InsertTx, same TX as the cluster row)
+//   - periodically with ClusterID empty (all non-archived clusters)
+type CapabilityDetectionArgs struct {
+	ClusterID string `json:"cluster_id,omitempty"`
+}
+
+// Kind returns the River job kind.
+func (CapabilityDetectionArgs) Kind() string { return "capability_detection" }
+
+// InsertOpts deduplicates overlapping runs for the same target.
+func (CapabilityDetectionArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewCapabilityDetectionPeriodicJob schedules detection for all clusters.
+// interval comes from k8s.capability_refresh_interval.
+func NewCapabilityDetectionPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return CapabilityDetectionArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// CapabilityDetectionWorker detects and stores cluster capabilities.
+type CapabilityDetectionWorker struct {
+	river.WorkerDefaults[CapabilityDetectionArgs]
+
+	detector    provider.CapabilityDetector
+	clusterRepo repository.ClusterRepository
+}
+
+// NewCapabilityDetectionWorker creates a new worker.
+func NewCapabilityDetectionWorker(
+	detector provider.CapabilityDetector,
+	clusterRepo repository.ClusterRepository,
+) *CapabilityDetectionWorker {
+	return &CapabilityDetectionWorker{
+		detector:    detector,
+		clusterRepo: clusterRepo,
+	}
+}
+
+// Work detects one cluster, or all clusters when ClusterID is empty.
+func (w *CapabilityDetectionWorker) Work(ctx context.Context, job *river.Job[CapabilityDetectionArgs]) error {
+	if job.Args.ClusterID != "" {
+		cluster, err := w.clusterRepo.Get(ctx, job.Args.ClusterID)
+		if err != nil {
+			return fmt.Errorf("get cluster: %w", err)
+		}
+		// Registration relies on this result, retry on failure
+		return w.detect(ctx, cluster)
+	}
+
+	clusters, err := w.clusterRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list clusters: %w", err)
+	}
+	for _, cluster := range clusters {
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		// One unreachable cluster must not block the others;
+		// previous capabilities stay in place until the next run.
+		ctx := logger.WithCluster(ctx, cluster.Name)
+		if err := w.detect(ctx, cluster); err != nil {
+			logger.WarnCtx(ctx, "Capability detection failed", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (w *CapabilityDetectionWorker) detect(ctx context.Context, cluster *domain.Cluster) error {
+	// KubeVirt/CDI detection does not apply to other provider types
+	if cluster.ProviderType != "" && cluster.ProviderType != provider.ProviderTypeKubeVirt {
+		return nil
+	}
+
+	caps, err := w.detector.Detect(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("detect capabilities: %w", err)
+	}
+
+	// Warn only when warnings change, not on every run
+	if len(caps.Warnings) > 0 && (cluster.Capabilities == nil ||
+		strings.Join(cluster.Capabilities.Warnings, "\n") != strings.Join(caps.Warnings, "\n")) {
+		logger.WarnCtx(ctx, "Unsupported KubeVirt version skew",
+			zap.String("kubevirt_version", caps.KubeVirtVersion),
+			zap.String("cdi_version", caps.CDIVersion),
+			zap.Strings("warnings", caps.Warnings),
+		)
+	}
+
+	if err := w.clusterRepo.UpdateCapabilities(ctx, cluster.ID, caps); err != nil {
+		return fmt.Errorf("update capabilities: %w", err)
+	}
+	return nil
+}
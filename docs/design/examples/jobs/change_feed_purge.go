@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ChangeFeedPurgeArgs deletes status changes older than
+// domain.ChangeFeedRetention.
+//
+// Not event-driven: platform maintenance, like the nonce purge.
+type ChangeFeedPurgeArgs struct{}
+
+// Kind returns the River job kind.
+func (ChangeFeedPurgeArgs) Kind() string { return "change_feed_purge" }
+
+// InsertOpts keeps at most one purge per period.
+func (ChangeFeedPurgeArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewChangeFeedPurgePeriodicJob schedules the purge hourly: the feed is
+// append-only and busy, a day's backlog is enough to delete at once.
+func NewChangeFeedPurgePeriodicJob() *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(time.Hour),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return ChangeFeedPurgeArgs{}, nil
+		},
+		nil,
+	)
+}
+
+// ChangeFeedPurgeWorker deletes expired changes.
+type ChangeFeedPurgeWorker struct {
+	river.WorkerDefaults[ChangeFeedPurgeArgs]
+
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+}
+
+// NewChangeFeedPurgeWorker creates a new worker.
+func NewChangeFeedPurgeWorker(sqlcQueries *sqlc.Queries, clock domain.Clock) *ChangeFeedPurgeWorker {
+	return &ChangeFeedPurgeWorker{sqlcQueries: sqlcQueries, clock: clock}
+}
+
+// Work deletes the expired changes and, in the same statement, moves the
+// purged-through watermark, so a client resuming inside the purged range
+// is told to reload (410) instead of silently missing changes.
+func (w *ChangeFeedPurgeWorker) Work(ctx context.Context, job *river.Job[ChangeFeedPurgeArgs]) error {
+	n, err := w.sqlcQueries.PurgeStatusChanges(ctx, w.clock.Now().Add(-domain.ChangeFeedRetention))
+	if err != nil {
+		return fmt.Errorf("purge status changes: %w", err)
+	}
+	if n > 0 {
+		logger.InfoCtx(ctx, "Purged expired status changes", zap.Int64("count", n))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// DiagnosticsArgs collects a diagnostics bundle for a failed VM creation.
+// Inserted by EventJobWorker when a VM_CREATION_REQUESTED event fails for
+// good; collection runs separately so a slow cluster cannot hold up the
+// event worker.
+type DiagnosticsArgs struct {
+	EventID string `json:"event_id"`
+}
+
+// Kind returns the River job kind.
+func (DiagnosticsArgs) Kind() string { return "vm_diagnostics" }
+
+// InsertOpts keeps one collection per event.
+func (DiagnosticsArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true},
+	}
+}
+
+// DiagnosticsCollector resolves the cluster's DiagnosticsProvider.
+// Implemented by provider.Registry.
+type DiagnosticsCollector interface {
+	Diagnostics(cluster string) (provider.DiagnosticsProvider, error)
+}
+
+// DiagnosticsWorker collects and stores the bundle.
+type DiagnosticsWorker struct {
+	river.WorkerDefaults[DiagnosticsArgs]
+
+	collector DiagnosticsCollector
+	vmRepo    repository.VMRepository
+	eventRepo repository.DomainEventRepository
+	clock     domain.Clock
+}
+
+// NewDiagnosticsWorker creates a new worker.
+func NewDiagnosticsWorker(
+	collector DiagnosticsCollector,
+	vmRepo repository.VMRepository,
+	eventRepo repository.DomainEventRepository,
+	clock domain.Clock,
+) *DiagnosticsWorker {
+	return &DiagnosticsWorker{collector: collector, vmRepo: vmRepo, eventRepo: eventRepo, clock: clock}
+}
+
+// Work collects the bundle once. A bundle is always stored, even when the
+// VM never got a name or placement, so admins see why nothing was collected.
+func (w *DiagnosticsWorker) Work(ctx context.Context, job *river.Job[DiagnosticsArgs]) error {
+	bundle := &domain.DiagnosticsBundle{EventID: job.Args.EventID}
+
+	// VM record written when the worker allocated the name and cluster
+	vm, err := w.vmRepo.GetByCreationEvent(ctx, job.Args.EventID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		bundle.Errors = append(bundle.Errors, "failed before placement: no VM to inspect")
+	case err != nil:
+		return fmt.Errorf("get vm: %w", err) // Retry
+	default:
+		bundle = w.collect(ctx, job.Args.EventID, vm)
+	}
+
+	bundle.CollectedAt = w.clock.Now()
+	if err := w.eventRepo.SaveDiagnostics(ctx, bundle); err != nil {
+		return fmt.Errorf("save diagnostics: %w", err)
+	}
+	return nil
+}
+
+func (w *DiagnosticsWorker) collect(ctx context.Context, eventID string, vm *domain.VM) *domain.DiagnosticsBundle {
+	fallback := &domain.DiagnosticsBundle{
+		EventID:   eventID,
+		Cluster:   vm.Cluster,
+		Namespace: vm.Namespace,
+		Name:      vm.Name,
+	}
+	dp, err := w.collector.Diagnostics(vm.Cluster)
+	if err != nil {
+		fallback.Errors = append(fallback.Errors, err.Error())
+		return fallback
+	}
+	bundle, err := dp.CollectDiagnostics(ctx, vm.Cluster, vm.Namespace, vm.Name)
+	if err != nil {
+		// Cluster unreachable: store what is known, do not retry into a dead cluster
+		fallback.Errors = append(fallback.Errors, "collect: "+err.Error())
+		return fallback
+	}
+	bundle.EventID = eventID
+	bundle.Limit()
+	return bundle
+}
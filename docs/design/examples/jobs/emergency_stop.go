@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// EmergencyStopItemHandler stops one VM of an emergency stop.
+//
+// Registered for the child event type created by EmergencyStopUseCase:
+//
+//	dispatcher.Register(domain.EventEmergencyStopItemRequested, emergencyStopHandler)
+//
+// Provider errors are retried by River; after the last attempt the item is
+// recorded as failed (FinalFailureHandler), so the stop always completes.
+type EmergencyStopItemHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	power       provider.InfrastructureProvider
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewEmergencyStopItemHandler creates a new handler.
+func NewEmergencyStopItemHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	power provider.InfrastructureProvider,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *EmergencyStopItemHandler {
+	return &EmergencyStopItemHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		power:       power,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle stops the VM. Idempotent: stopping a stopped VM is a no-op, and
+// a finished item is not counted twice.
+func (h *EmergencyStopItemHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.EmergencyStopItemPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode emergency stop item payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	err = h.power.StopVM(ctx, p.Cluster, p.Namespace, p.VMName)
+	switch {
+	case err == nil, errors.Is(err, provider.ErrNotFound):
+		// Gone from the cluster counts as stopped: nothing is running
+		return h.finishItem(ctx, event, p, domain.EmergencyStopItemStopped, "")
+	case errors.Is(err, domain.ErrNotOwned):
+		// Retrying cannot help
+		return h.finishItem(ctx, event, p, domain.EmergencyStopItemFailed, err.Error())
+	default:
+		return fmt.Errorf("stop vm: %w", err) // Retry
+	}
+}
+
+// HandleFinalFailure records the item as failed once River gives up.
+func (h *EmergencyStopItemHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.EmergencyStopItemPayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode emergency stop item payload: %w", err)
+	}
+	return h.finishItem(ctx, event, p, domain.EmergencyStopItemFailed, cause.Error())
+}
+
+// finishItem records the item result with its audit log, and closes the
+// stop when it was the last item, in one TX.
+func (h *EmergencyStopItemHandler) finishItem(ctx context.Context, event *domain.DomainEvent, p domain.EmergencyStopItemPayload, status domain.EmergencyStopItemStatus, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	// Item status + parent counters in one statement (no lost updates).
+	// No row: the item already finished (retry after crash).
+	row, err := sqlcTx.FinishEmergencyStopItem(ctx, sqlc.FinishEmergencyStopItemParams{
+		ItemID:       p.ItemID,
+		Status:       string(status),
+		ErrorMessage: errMsg,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finish emergency stop item: %w", err)
+	}
+
+	eventStatus := domain.EventStatusCompleted
+	if status == domain.EmergencyStopItemFailed {
+		eventStatus = domain.EventStatusFailed
+	}
+	if err := sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+	}); err != nil {
+		return fmt.Errorf("update item event: %w", err)
+	}
+
+	// Per-VM audit, attributed to the owner who stopped it
+	details, _ := json.Marshal(map[string]interface{}{
+		"emergency_stop_id": p.StopID,
+		"result":            status,
+		"error":             errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMStop,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	stop := domain.EmergencyStop{
+		ID:           row.ID,
+		EventID:      row.EventID,
+		RequestedBy:  row.RequestedBy,
+		TotalCount:   int(row.TotalCount),
+		StoppedCount: int(row.StoppedCount),
+		FailedCount:  int(row.FailedCount),
+		PendingCount: int(row.PendingCount),
+	}
+	stopStatus := stop.CalculateStatus()
+	if stopStatus != domain.EmergencyStopInProgress {
+		// Last item: close the parent and its event
+		parentStatus := domain.EventStatusCompleted
+		if stopStatus == domain.EmergencyStopFailed {
+			parentStatus = domain.EventStatusFailed
+		}
+		if err := sqlcTx.CompleteEmergencyStop(ctx, sqlc.CompleteEmergencyStopParams{
+			ID:          stop.ID,
+			Status:      string(stopStatus),
+			CompletedAt: h.clock.Now(),
+		}); err != nil {
+			return fmt.Errorf("complete emergency stop: %w", err)
+		}
+		if err := sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+			EventID: stop.EventID,
+			Status:  string(parentStatus),
+		}); err != nil {
+			return fmt.Errorf("update emergency stop event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if status == domain.EmergencyStopItemFailed {
+		logger.WarnCtx(ctx, "Emergency stop of VM failed",
+			zap.String("emergency_stop_id", p.StopID),
+			zap.String("error", errMsg),
+		)
+	}
+	if stopStatus == domain.EmergencyStopInProgress {
+		return nil
+	}
+
+	// Best-effort after commit: the stop is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: stop.RequestedBy,
+		Type:      domain.NotificationEmergencyStopCompleted,
+		Title:     fmt.Sprintf("Emergency stop %s", stopStatus),
+		Content: fmt.Sprintf("Stopped: %d, Failed: %d (of %d)",
+			stop.StoppedCount, stop.FailedCount, stop.TotalCount),
+		Priority:  domain.PriorityEmergency,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send emergency stop notification failed", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// EventArchiveArgs moves finished events older than the archive window
+// into domain_events_archive (domain/event_archive.go).
+//
+// Not event-driven: platform maintenance, like the retention purge.
+type EventArchiveArgs struct{}
+
+// Kind returns the River job kind.
+func (EventArchiveArgs) Kind() string { return "event_archive" }
+
+// InsertOpts keeps at most one run per period.
+func (EventArchiveArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Hour},
+	}
+}
+
+// NewEventArchivePeriodicJob schedules archival. interval comes from
+// governance.event_archive_interval (default 24h).
+func NewEventArchivePeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return EventArchiveArgs{}, nil
+		},
+		nil, // Not on start: a rolling deploy would archive on every replica start
+	)
+}
+
+// EventArchiveWorker moves events in batches. Each batch is one statement
+// (DELETE ... RETURNING feeding the INSERT), so an event is never in both
+// tables or in neither.
+type EventArchiveWorker struct {
+	river.WorkerDefaults[EventArchiveArgs]
+
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	after       time.Duration
+}
+
+// NewEventArchiveWorker creates a new worker. after comes from
+// governance.event_archive_after.
+func NewEventArchiveWorker(sqlcQueries *sqlc.Queries, clock domain.Clock, after time.Duration) *EventArchiveWorker {
+	return &EventArchiveWorker{sqlcQueries: sqlcQueries, clock: clock, after: after}
+}
+
+// Timeout bounds a run; the batches moved before it stand.
+func (w *EventArchiveWorker) Timeout(*river.Job[EventArchiveArgs]) time.Duration {
+	return 30 * time.Minute
+}
+
+// Work archives until nothing old enough is left or the timeout. An error
+// retries the job; batches already moved are not redone.
+func (w *EventArchiveWorker) Work(ctx context.Context, job *river.Job[EventArchiveArgs]) error {
+	now := w.clock.Now()
+	cutoff := domain.EventArchiveCutoff(now, w.after)
+
+	statuses := make([]string, len(domain.ArchivableEventStatuses))
+	for i, s := range domain.ArchivableEventStatuses {
+		statuses[i] = string(s)
+	}
+
+	var archived int64
+	for {
+		n, err := w.sqlcQueries.ArchiveDomainEvents(ctx, sqlc.ArchiveDomainEventsParams{
+			Cutoff:     cutoff,
+			Statuses:   statuses,
+			ArchivedAt: now,
+			Limit:      domain.EventArchiveBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("archive events: %w", err)
+		}
+		archived += n
+		if n < domain.EventArchiveBatchSize {
+			break
+		}
+	}
+
+	if archived > 0 {
+		logger.InfoCtx(ctx, "Finished events archived",
+			zap.Int64("archived", archived),
+			zap.Time("cutoff", cutoff),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Package jobs provides River job definitions and workers.
+//
+// ADR-0006: All write operations are executed asynchronously by River workers.
+// ADR-0009: Jobs carry only the EventID (Claim Check); the worker loads the
+// full payload from the DomainEvent table.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/jobs
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// EventJobArgs is the ONLY job argument type for event-driven work.
+//
+// Deprecated pattern: operation-specific args (CreateVMArgs, DeleteVMArgs, ...).
+// Use EventJobArgs and dispatch on DomainEvent.EventType instead.
+type EventJobArgs struct {
+	EventID string `json:"event_id"`
+}
+
+// Kind returns the River job kind.
+func (EventJobArgs) Kind() string { return "event_job" }
+
+// EventHandler executes the K8s side of a single event type.
+// Handlers run OUTSIDE any DB transaction (ADR-0012: no K8s calls in TX).
+type EventHandler interface {
+	Handle(ctx context.Context, event *domain.DomainEvent) error
+}
+
+// FinalFailureHandler is implemented by handlers whose events are items
+// of a parent with progress counters (emergency stop). Called once River
+// gives up on the event, so the item counts as failed instead of staying
+// pending forever.
+type FinalFailureHandler interface {
+	HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error
+}
+
+// EventDispatcher routes events to handlers by EventType.
+type EventDispatcher struct {
+	handlers map[domain.EventType]EventHandler
+}
+
+// NewEventDispatcher creates an empty dispatcher.
+func NewEventDispatcher() *EventDispatcher {
+	return &EventDispatcher{handlers: make(map[domain.EventType]EventHandler)}
+}
+
+// Register binds a handler to an event type (called from the composition root).
+func (d *EventDispatcher) Register(eventType domain.EventType, h EventHandler) {
+	d.handlers[eventType] = h
+}
+
+// Dispatch executes the handler registered for the event's type.
+func (d *EventDispatcher) Dispatch(ctx context.Context, event *domain.DomainEvent) error {
+	h, ok := d.handlers[event.EventType]
+	if !ok {
+		// Unknown type will never succeed, do not retry
+		return river.JobCancel(fmt.Errorf("no handler for event type %s", event.EventType))
+	}
+	err := h.Handle(ctx, event)
+	if errors.Is(err, domain.ErrNotOwned) {
+		// Name collision with an object Shepherd does not own: retrying cannot help
+		return river.JobCancel(err)
+	}
+	if errors.Is(err, domain.ErrResourceConflict) {
+		// Managed fields changed on the cluster: the user must refresh and resubmit
+		return river.JobCancel(err)
+	}
+	return err
+}
+
+// GiveUp tells the event's handler that River will not retry the event.
+// A no-op for handlers that are not a FinalFailureHandler.
+func (d *EventDispatcher) GiveUp(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	h, ok := d.handlers[event.EventType].(FinalFailureHandler)
+	if !ok {
+		return nil
+	}
+	return h.HandleFinalFailure(ctx, event, cause)
+}
+
+// freezeRecheckInterval caps how long a held job sleeps, so overrides and
+// calendar edits take effect without waiting for the original lift time.
+const freezeRecheckInterval = 15 * time.Minute
+
+// FreezeGate holds executions during change freezes.
+// Implemented by service.ChangeFreezeGate.
+type FreezeGate interface {
+	// Hold returns when the event may run; zero means now.
+	Hold(ctx context.Context, event *domain.DomainEvent) (time.Time, error)
+}
+
+// EventJobWorker loads the DomainEvent and dispatches it.
+type EventJobWorker struct {
+	river.WorkerDefaults[EventJobArgs]
+	eventRepo  repository.DomainEventRepository
+	quotaRepo  repository.QuotaReservationRepository
+	freeze     FreezeGate
+	dispatcher *EventDispatcher
+}
+
+// NewEventJobWorker creates a new event job worker.
+func NewEventJobWorker(
+	eventRepo repository.DomainEventRepository,
+	quotaRepo repository.QuotaReservationRepository,
+	freeze FreezeGate,
+	dispatcher *EventDispatcher,
+) *EventJobWorker {
+	return &EventJobWorker{
+		eventRepo:  eventRepo,
+		quotaRepo:  quotaRepo,
+		freeze:     freeze,
+		dispatcher: dispatcher,
+	}
+}
+
+// Work is called by River for each EventJobArgs job.
+func (w *EventJobWorker) Work(ctx context.Context, job *river.Job[EventJobArgs]) error {
+	ctx = logger.WithEvent(ctx, job.Args.EventID)
+	event, err := w.eventRepo.Get(ctx, job.Args.EventID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Event deleted, cancel job (no retry). Nothing is left to mark
+		// failed, but a reservation may still hold quota.
+		w.releaseQuota(ctx, job.Args.EventID)
+		return river.JobCancel(fmt.Errorf("event not found: %s", job.Args.EventID))
+	}
+	if err != nil {
+		return fmt.Errorf("load event: %w", err) // Retry
+	}
+
+	// Duplicate delivery: with several replicas River may rescue a job
+	// from a replica that finished the event but died before River
+	// recorded the job as completed. Handlers must still be idempotent
+	// for the window before the event is marked terminal.
+	if event.Status.IsTerminal() {
+		logger.InfoCtx(ctx, "Event already finished, skipping",
+			zap.String("status", string(event.Status)),
+		)
+		return nil
+	}
+
+	// Old payloads are decompressed and upgraded before any handler
+	// decodes them
+	if err := event.Upcast(); err != nil {
+		if errors.Is(err, domain.ErrUnknownPayloadVersion) || errors.Is(err, domain.ErrUnknownPayloadEncoding) {
+			// Written by a newer replica during a rolling upgrade: retry,
+			// an upgraded replica will pick it up
+			return err
+		}
+		w.giveUp(ctx, event, err)
+		return river.JobCancel(err)
+	}
+
+	// Change freeze: snooze, not fail. River does not count snoozes
+	// against MaxAttempts, so a long freeze cannot exhaust retries.
+	lift, err := w.freeze.Hold(ctx, event)
+	if err != nil {
+		return fmt.Errorf("check change freeze: %w", err)
+	}
+	if !lift.IsZero() {
+		logger.InfoCtx(ctx, "Execution held by change freeze",
+			zap.Time("lifts_at", lift),
+		)
+		return river.JobSnooze(min(time.Until(lift), freezeRecheckInterval))
+	}
+
+	err = w.dispatcher.Dispatch(ctx, event)
+	if err != nil && (job.Attempt >= job.MaxAttempts || isJobCancel(err)) {
+		// Last attempt or cancelled: River discards the job and nothing
+		// will move this event again
+		w.giveUp(ctx, event, err)
+	}
+	return err
+}
+
+// giveUp finalizes an event River will not run again. Its quota is
+// released now instead of waiting for the sweep, its handler records the
+// final failure, and the event is marked FAILED so an admin can requeue
+// it. Best-effort, like releaseQuota: the job outcome stays cause.
+func (w *EventJobWorker) giveUp(ctx context.Context, event *domain.DomainEvent, cause error) {
+	w.releaseQuota(ctx, event.EventID)
+	if err := w.dispatcher.GiveUp(ctx, event, cause); err != nil {
+		logger.WarnCtx(ctx, "Failed to record final failure", zap.Error(err))
+	}
+	// A no-op when the handler already moved the event to a final status
+	if err := w.eventRepo.Fail(ctx, event.EventID, cause.Error()); err != nil {
+		logger.WarnCtx(ctx, "Failed to mark event failed", zap.Error(err))
+	}
+	if event.EventType == domain.EventVMCreationRequested {
+		w.requestDiagnostics(ctx, event.EventID)
+	}
+}
+
+// isJobCancel reports whether err tells River not to retry the job.
+func isJobCancel(err error) bool {
+	var cancel *rivertype.JobCancelError
+	return errors.As(err, &cancel)
+}
+
+// requestDiagnostics schedules the diagnostics bundle for a failed creation.
+// Best-effort: a missing bundle must not change the job outcome.
+func (w *EventJobWorker) requestDiagnostics(ctx context.Context, eventID string) {
+	client := river.ClientFromContext[pgx.Tx](ctx)
+	if _, err := client.Insert(ctx, DiagnosticsArgs{EventID: eventID}, nil); err != nil {
+		logger.WarnCtx(ctx, "Failed to schedule diagnostics collection", zap.Error(err))
+	}
+}
+
+// releaseQuota releases a HELD reservation for a permanently failed event.
+// Best-effort: the quota sweep releases it later if this write fails.
+func (w *EventJobWorker) releaseQuota(ctx context.Context, eventID string) {
+	if _, err := w.quotaRepo.ReleaseByEvent(ctx, eventID, domain.ReleaseExecutionFailed); err != nil {
+		logger.WarnCtx(ctx, "Failed to release quota reservation", zap.Error(err))
+	}
+}
@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// InstanceTypeSyncArgs pushes InstanceSizes with PushToClusters into clusters
+// as native VirtualMachineClusterInstancetypes.
+//
+// Enqueued:
+//   - by InstanceSize admin create/update/delete (InsertTx, same TX as the row)
+//   - periodically to correct drift (someone edited or deleted a pushed object)
+type InstanceTypeSyncArgs struct {
+	ClusterID string `json:"cluster_id,omitempty"` // Empty = all clusters
+}
+
+// Kind returns the River job kind.
+func (InstanceTypeSyncArgs) Kind() string { return "instancetype_sync" }
+
+// InsertOpts collapses bursts of catalog edits into one run.
+func (InstanceTypeSyncArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewInstanceTypeSyncPeriodicJob schedules drift correction for all clusters.
+// interval comes from k8s.instancetype_sync_interval.
+func NewInstanceTypeSyncPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return InstanceTypeSyncArgs{}, nil
+		},
+		nil,
+	)
+}
+
+// InstanceTypeSyncWorker reconciles pushed instancetypes per cluster.
+type InstanceTypeSyncWorker struct {
+	river.WorkerDefaults[InstanceTypeSyncArgs]
+
+	providers   *provider.Registry
+	clusterRepo repository.ClusterRepository
+	sizeRepo    repository.InstanceSizeRepository
+}
+
+// NewInstanceTypeSyncWorker creates a new worker.
+func NewInstanceTypeSyncWorker(
+	providers *provider.Registry,
+	clusterRepo repository.ClusterRepository,
+	sizeRepo repository.InstanceSizeRepository,
+) *InstanceTypeSyncWorker {
+	return &InstanceTypeSyncWorker{
+		providers:   providers,
+		clusterRepo: clusterRepo,
+		sizeRepo:    sizeRepo,
+	}
+}
+
+// Work syncs one cluster, or all clusters when ClusterID is empty.
+func (w *InstanceTypeSyncWorker) Work(ctx context.Context, job *river.Job[InstanceTypeSyncArgs]) error {
+	desired, err := w.desired(ctx)
+	if err != nil {
+		return err
+	}
+
+	if job.Args.ClusterID != "" {
+		cluster, err := w.clusterRepo.Get(ctx, job.Args.ClusterID)
+		if err != nil {
+			return fmt.Errorf("get cluster: %w", err)
+		}
+		return w.sync(ctx, cluster, desired)
+	}
+
+	clusters, err := w.clusterRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list clusters: %w", err)
+	}
+	for _, cluster := range clusters {
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		// One failing cluster must not block the others; next run retries
+		ctx := logger.WithCluster(ctx, cluster.Name)
+		if err := w.sync(ctx, cluster, desired); err != nil {
+			logger.WarnCtx(ctx, "Instancetype sync failed", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// desired builds the instancetypes for enabled sizes marked PushToClusters.
+func (w *InstanceTypeSyncWorker) desired(ctx context.Context) ([]*domain.InstanceType, error) {
+	sizes, err := w.sizeRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list instance sizes: %w", err)
+	}
+
+	result := make([]*domain.InstanceType, 0, len(sizes))
+	for _, size := range sizes {
+		if !size.PushToClusters {
+			continue
+		}
+		it, err := domain.InstanceTypeFromSize(size)
+		if err != nil {
+			// Bad catalog entry: cancel, retrying cannot fix it
+			return nil, river.JobCancel(err)
+		}
+		result = append(result, it)
+	}
+	return result, nil
+}
+
+func (w *InstanceTypeSyncWorker) sync(ctx context.Context, cluster *domain.Cluster, desired []*domain.InstanceType) error {
+	itp, err := w.providers.InstanceTypes(cluster.Name)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return nil // Provider has no instancetype concept
+	}
+	if err != nil {
+		return err
+	}
+
+	actual, err := itp.ListClusterInstanceTypes(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("list cluster instancetypes: %w", err)
+	}
+
+	plan, err := domain.PlanInstanceTypeSync(desired, actual)
+	if err != nil {
+		return err
+	}
+	if plan.IsEmpty() {
+		return nil
+	}
+
+	for _, it := range plan.Create {
+		if _, err := itp.CreateInstanceType(ctx, cluster.Name, it); err != nil && !errors.Is(err, provider.ErrAlreadyExists) {
+			return fmt.Errorf("create instancetype %s: %w", it.Name, err)
+		}
+	}
+	for _, it := range plan.Update {
+		if _, err := itp.UpdateInstanceType(ctx, cluster.Name, it); err != nil {
+			return fmt.Errorf("update instancetype %s: %w", it.Name, err)
+		}
+	}
+	for _, name := range plan.Delete {
+		// VMs referencing a deleted instancetype keep running: KubeVirt
+		// stores a ControllerRevision of the spec at VM creation.
+		if err := itp.DeleteInstanceType(ctx, cluster.Name, "", name); err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return fmt.Errorf("delete instancetype %s: %w", name, err)
+		}
+	}
+
+	logger.InfoCtx(ctx, "Instancetypes synced",
+		zap.Int("created", len(plan.Create)),
+		zap.Int("updated", len(plan.Update)),
+		zap.Int("deleted", len(plan.Delete)),
+	)
+	return nil
+}
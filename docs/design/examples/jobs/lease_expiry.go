@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// LeaseExpiryArgs warns of and enforces VM lease expiry
+// (domain/vm_lease.go).
+//
+// Not event-driven: platform maintenance, like the ticket expiry.
+type LeaseExpiryArgs struct{}
+
+// Kind returns the River job kind.
+func (LeaseExpiryArgs) Kind() string { return "lease_expiry" }
+
+// InsertOpts keeps at most one run per period.
+func (LeaseExpiryArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewLeaseExpiryPeriodicJob schedules the lease check.
+// interval comes from governance.lease_expiry_interval.
+func NewLeaseExpiryPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return LeaseExpiryArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// LeaseExpirer warns of and enforces one batch of lease expiries and
+// returns how many leases it warned and expired. Implemented by
+// usecase.ExpireLeasesUseCase.
+type LeaseExpirer interface {
+	Execute(ctx context.Context) (warned, expired int, err error)
+}
+
+// LeaseExpiryWorker runs the LeaseExpirer.
+type LeaseExpiryWorker struct {
+	river.WorkerDefaults[LeaseExpiryArgs]
+
+	expire LeaseExpirer
+}
+
+// NewLeaseExpiryWorker creates a new worker.
+func NewLeaseExpiryWorker(expire LeaseExpirer) *LeaseExpiryWorker {
+	return &LeaseExpiryWorker{expire: expire}
+}
+
+// Work handles one batch. An error retries the job; leases warned or
+// expired before it are marked and not touched again.
+func (w *LeaseExpiryWorker) Work(ctx context.Context, job *river.Job[LeaseExpiryArgs]) error {
+	warned, expired, err := w.expire.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("expire leases: %w", err)
+	}
+	if warned > 0 || expired > 0 {
+		logger.InfoCtx(ctx, "VM leases processed", zap.Int("warned", warned), zap.Int("expired", expired))
+	}
+	return nil
+}
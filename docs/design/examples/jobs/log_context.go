@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// LogContextMiddleware puts the job ID and kind in the log context of
+// every job, so worker log lines can be traced back to the River job.
+// Installed first in river.Config.Middleware (NewRiverClient), ahead of
+// any other middleware. Workers add what they handle on top, e.g.
+// EventJobWorker the event ID.
+type LogContextMiddleware struct {
+	river.MiddlewareDefaults
+}
+
+// Work runs the job with the job fields attached.
+func (LogContextMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(ctx context.Context) error) error {
+	ctx = logger.With(ctx,
+		zap.Int64(logger.FieldJobID, job.ID),
+		zap.String(logger.FieldJobKind, job.Kind),
+		zap.Int("attempt", job.Attempt),
+	)
+	return doInner(ctx)
+}
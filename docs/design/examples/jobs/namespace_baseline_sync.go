@@ -0,0 +1,170 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// NamespaceBaselineSyncer syncs one namespace's governed objects.
+// Implemented by service.NamespaceProvisioner.
+type NamespaceBaselineSyncer interface {
+	Sync(ctx context.Context, cluster, namespace string) (*domain.BaselineSyncPlan, error)
+}
+
+// NamespaceBaselineSyncArgs reconciles namespace baselines (NetworkPolicy,
+// ResourceQuota, LimitRange) in every Shepherd namespace on a cluster.
+//
+// Enqueued:
+//   - by NamespaceBaselineUseCase.Update (InsertTx, same TX as the row)
+//   - periodically to correct drift (someone edited or deleted a governed object)
+type NamespaceBaselineSyncArgs struct {
+	ClusterID string `json:"cluster_id,omitempty"` // Empty = all clusters
+}
+
+// Kind returns the River job kind.
+func (NamespaceBaselineSyncArgs) Kind() string { return "namespace_baseline_sync" }
+
+// InsertOpts collapses bursts of baseline edits into one run.
+func (NamespaceBaselineSyncArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewNamespaceBaselineSyncPeriodicJob schedules drift correction for all
+// clusters. interval comes from k8s.namespace_baseline_sync_interval.
+func NewNamespaceBaselineSyncPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return NamespaceBaselineSyncArgs{}, nil
+		},
+		nil,
+	)
+}
+
+// NamespaceBaselineSyncWorker reconciles the namespaces hosting Shepherd
+// VMs. Drift, an object changed on the cluster since it was applied, is
+// restored and audited as a "system" action.
+type NamespaceBaselineSyncWorker struct {
+	river.WorkerDefaults[NamespaceBaselineSyncArgs]
+
+	syncer      NamespaceBaselineSyncer
+	clusterRepo repository.ClusterRepository
+	vmRepo      repository.VMRepository
+	sqlcQueries *sqlc.Queries
+	ids         domain.IDGenerator
+}
+
+// NewNamespaceBaselineSyncWorker creates a new worker.
+func NewNamespaceBaselineSyncWorker(
+	syncer NamespaceBaselineSyncer,
+	clusterRepo repository.ClusterRepository,
+	vmRepo repository.VMRepository,
+	sqlcQueries *sqlc.Queries,
+	ids domain.IDGenerator,
+) *NamespaceBaselineSyncWorker {
+	return &NamespaceBaselineSyncWorker{
+		syncer:      syncer,
+		clusterRepo: clusterRepo,
+		vmRepo:      vmRepo,
+		sqlcQueries: sqlcQueries,
+		ids:         ids,
+	}
+}
+
+// Work syncs one cluster, or all clusters when ClusterID is empty.
+func (w *NamespaceBaselineSyncWorker) Work(ctx context.Context, job *river.Job[NamespaceBaselineSyncArgs]) error {
+	if job.Args.ClusterID != "" {
+		cluster, err := w.clusterRepo.Get(ctx, job.Args.ClusterID)
+		if err != nil {
+			return fmt.Errorf("get cluster: %w", err)
+		}
+		return w.sync(logger.WithCluster(ctx, cluster.Name), cluster)
+	}
+
+	clusters, err := w.clusterRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list clusters: %w", err)
+	}
+	for _, cluster := range clusters {
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		// One failing cluster must not block the others; next run retries
+		ctx := logger.WithCluster(ctx, cluster.Name)
+		if err := w.sync(ctx, cluster); err != nil {
+			logger.WarnCtx(ctx, "Namespace baseline sync failed", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (w *NamespaceBaselineSyncWorker) sync(ctx context.Context, cluster *domain.Cluster) error {
+	namespaces, err := w.vmRepo.NamespacesOnCluster(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("list cluster namespaces: %w", err)
+	}
+
+	var applied, deleted, failed int
+	for _, ns := range namespaces {
+		plan, err := w.syncer.Sync(ctx, cluster.Name, ns)
+		if err != nil {
+			failed++
+			logger.WarnCtx(ctx, "Namespace baseline sync failed", zap.String("namespace", ns), zap.Error(err))
+			continue
+		}
+		applied += len(plan.Apply)
+		deleted += len(plan.Delete)
+		if len(plan.Drifted) > 0 {
+			w.recordDrift(ctx, cluster.Name, ns, plan.Drifted)
+		}
+	}
+
+	if applied > 0 || deleted > 0 {
+		logger.InfoCtx(ctx, "Namespace baselines synced",
+			zap.Int("namespaces", len(namespaces)),
+			zap.Int("applied", applied),
+			zap.Int("deleted", deleted),
+		)
+	}
+	if failed > 0 {
+		return fmt.Errorf("namespace baseline sync failed on %d of %d namespaces", failed, len(namespaces))
+	}
+	return nil
+}
+
+// recordDrift audits objects that were changed on the cluster and have
+// been restored. Best-effort: the restore already happened.
+func (w *NamespaceBaselineSyncWorker) recordDrift(ctx context.Context, cluster, namespace string, drifted []string) {
+	logger.WarnCtx(ctx, "Namespace baseline drift restored",
+		zap.String("namespace", namespace),
+		zap.Strings("objects", drifted),
+	)
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"cluster": cluster,
+		"objects": drifted,
+	})
+	if err := w.sqlcQueries.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           w.ids.NewID(),
+		Action:       domain.AuditNamespaceBaselineDrift,
+		ActorID:      "system",
+		ResourceType: "namespace",
+		ResourceID:   namespace,
+		ResourceName: namespace,
+		Details:      details,
+	}); err != nil {
+		logger.WarnCtx(ctx, "Audit namespace baseline drift failed", zap.Error(err))
+	}
+}
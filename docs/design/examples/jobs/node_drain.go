@@ -0,0 +1,157 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// migrationPollInterval is how long to snooze while a live migration runs.
+const migrationPollInterval = 15 * time.Second
+
+// NodeDrainItemHandler executes one VM of a node drain.
+//
+// Registered for both child event types created by DrainNodeUseCase:
+//
+//	dispatcher.Register(domain.EventNodeDrainItemMigrationRequested, drainHandler)
+//	dispatcher.Register(domain.EventNodeDrainItemRestartRequested, drainHandler)
+type NodeDrainItemHandler struct {
+	migrations provider.MigrationProvider
+	power      provider.InfrastructureProvider
+	eventRepo  repository.DomainEventRepository
+	drainRepo  repository.NodeDrainRepository
+	notifier   domain.NotificationSender
+	clock      domain.Clock
+	ids        domain.IDGenerator
+}
+
+// NewNodeDrainItemHandler creates a new handler.
+func NewNodeDrainItemHandler(
+	migrations provider.MigrationProvider,
+	power provider.InfrastructureProvider,
+	eventRepo repository.DomainEventRepository,
+	drainRepo repository.NodeDrainRepository,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *NodeDrainItemHandler {
+	return &NodeDrainItemHandler{
+		migrations: migrations,
+		power:      power,
+		eventRepo:  eventRepo,
+		drainRepo:  drainRepo,
+		notifier:   notifier,
+		clock:      clock,
+		ids:        ids,
+	}
+}
+
+// Handle runs the planned action. Idempotent: River may retry at any point.
+func (h *NodeDrainItemHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.NodeDrainItemPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode drain item payload: %w", err))
+	}
+
+	item, err := h.drainRepo.GetItem(ctx, p.ItemID)
+	if err != nil {
+		return fmt.Errorf("get drain item: %w", err)
+	}
+	if item.Status == domain.NodeDrainItemCompleted || item.Status == domain.NodeDrainItemFailed {
+		return nil // Already finished (retry after crash)
+	}
+
+	switch p.Action {
+	case domain.DrainActionLiveMigrate:
+		return h.handleMigration(ctx, event, item, p)
+	case domain.DrainActionStopStart:
+		if err := h.drainRepo.UpdateItemStatus(ctx, item.ID, domain.NodeDrainItemRunning); err != nil {
+			return fmt.Errorf("mark item running: %w", err)
+		}
+		// Node is cordoned, so the restarted VMI is scheduled on another node
+		if err := h.power.RestartVM(ctx, p.Cluster, p.Namespace, p.VMName); err != nil {
+			return fmt.Errorf("restart vm: %w", err) // Retry
+		}
+		return h.finishItem(ctx, event, item, domain.NodeDrainItemCompleted, "")
+	default:
+		return river.JobCancel(fmt.Errorf("unknown drain action %q", p.Action))
+	}
+}
+
+// handleMigration starts the migration once, then snoozes until it finishes.
+func (h *NodeDrainItemHandler) handleMigration(ctx context.Context, event *domain.DomainEvent, item *domain.NodeDrainItem, p domain.NodeDrainItemPayload) error {
+	if item.MigrationName == "" {
+		mig, err := h.migrations.MigrateVM(ctx, p.Cluster, p.Namespace, p.VMName)
+		if err != nil {
+			return fmt.Errorf("migrate vm: %w", err) // Retry
+		}
+		if err := h.drainRepo.SetItemMigration(ctx, item.ID, mig.Name); err != nil {
+			return fmt.Errorf("record migration: %w", err)
+		}
+		return river.JobSnooze(migrationPollInterval)
+	}
+
+	mig, err := h.migrations.GetMigration(ctx, p.Cluster, p.Namespace, item.MigrationName)
+	if err != nil {
+		return fmt.Errorf("get migration: %w", err)
+	}
+
+	switch mig.Status {
+	case "Succeeded":
+		return h.finishItem(ctx, event, item, domain.NodeDrainItemCompleted, "")
+	case "Failed":
+		return h.finishItem(ctx, event, item, domain.NodeDrainItemFailed, mig.ErrorMessage)
+	default:
+		return river.JobSnooze(migrationPollInterval)
+	}
+}
+
+// finishItem records the item result and closes the drain when it was the last item.
+func (h *NodeDrainItemHandler) finishItem(ctx context.Context, event *domain.DomainEvent, item *domain.NodeDrainItem, status domain.NodeDrainItemStatus, errMsg string) error {
+	// Item status + parent counters updated in one statement (no lost updates)
+	drain, err := h.drainRepo.FinishItem(ctx, item.ID, status, errMsg)
+	if err != nil {
+		return fmt.Errorf("finish drain item: %w", err)
+	}
+
+	eventStatus := domain.EventStatusCompleted
+	if status == domain.NodeDrainItemFailed {
+		eventStatus = domain.EventStatusFailed
+	}
+	if err := h.eventRepo.UpdateStatus(ctx, event.EventID, eventStatus); err != nil {
+		return fmt.Errorf("update item event: %w", err)
+	}
+
+	drainStatus := drain.CalculateStatus()
+	if drainStatus == domain.NodeDrainStatusInProgress {
+		return nil
+	}
+
+	// Last item: close the parent event and tell the requesting admin
+	parentStatus := domain.EventStatusCompleted
+	if drainStatus == domain.NodeDrainStatusFailed {
+		parentStatus = domain.EventStatusFailed
+	}
+	if err := h.drainRepo.Complete(ctx, drain.ID, drainStatus); err != nil {
+		return fmt.Errorf("complete drain: %w", err)
+	}
+	if err := h.eventRepo.UpdateStatus(ctx, drain.EventID, parentStatus); err != nil {
+		return fmt.Errorf("update drain event: %w", err)
+	}
+
+	return h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: drain.RequestedBy,
+		Type:      domain.NotificationNodeDrainCompleted,
+		Title:     fmt.Sprintf("Node drain %s/%s: %s", drain.Cluster, drain.NodeName, drainStatus),
+		Content: fmt.Sprintf("Migrated: %d, Restarted: %d, Failed: %d (of %d)",
+			drain.MigratedCount, drain.RestartedCount, drain.FailedCount, drain.TotalCount),
+		CreatedAt: h.clock.Now(),
+	})
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// NoncePurgeArgs deletes expired signed-request nonces.
+//
+// Not event-driven: platform maintenance, like the quota sweep.
+type NoncePurgeArgs struct{}
+
+// Kind returns the River job kind.
+func (NoncePurgeArgs) Kind() string { return "nonce_purge" }
+
+// InsertOpts keeps at most one purge per period.
+func (NoncePurgeArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewNoncePurgePeriodicJob schedules the purge once per accepted skew
+// window (server.signature_max_skew): nonces live at most twice that long.
+func NewNoncePurgePeriodicJob(maxSkew time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(maxSkew),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return NoncePurgeArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// NoncePurgeWorker deletes expired nonces.
+type NoncePurgeWorker struct {
+	river.WorkerDefaults[NoncePurgeArgs]
+
+	nonces *repository.NonceCache
+}
+
+// NewNoncePurgeWorker creates a new worker.
+func NewNoncePurgeWorker(nonces *repository.NonceCache) *NoncePurgeWorker {
+	return &NoncePurgeWorker{nonces: nonces}
+}
+
+// Work deletes expired rows. Missing a run is harmless: Claim already
+// treats expired rows as free.
+func (w *NoncePurgeWorker) Work(ctx context.Context, job *river.Job[NoncePurgeArgs]) error {
+	n, err := w.nonces.PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		logger.InfoCtx(ctx, "Purged expired nonces", zap.Int64("count", n))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// QuotaSweepArgs settles HELD quota reservations whose execution has ended
+// or can no longer progress, so failed and cancelled requests do not leak quota.
+//
+// Not event-driven: platform maintenance, like capability detection.
+type QuotaSweepArgs struct{}
+
+// Kind returns the River job kind.
+func (QuotaSweepArgs) Kind() string { return "quota_sweep" }
+
+// InsertOpts keeps at most one sweep per period.
+func (QuotaSweepArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewQuotaSweepPeriodicJob schedules the sweep.
+// interval comes from governance.quota_sweep_interval.
+func NewQuotaSweepPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return QuotaSweepArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// QuotaSweepWorker releases or consumes stale reservations.
+type QuotaSweepWorker struct {
+	river.WorkerDefaults[QuotaSweepArgs]
+
+	quotaRepo repository.QuotaReservationRepository
+	grace     time.Duration // governance.quota_reservation_grace
+	clock     domain.Clock
+}
+
+// NewQuotaSweepWorker creates a new worker.
+func NewQuotaSweepWorker(quotaRepo repository.QuotaReservationRepository, grace time.Duration, clock domain.Clock) *QuotaSweepWorker {
+	return &QuotaSweepWorker{quotaRepo: quotaRepo, grace: grace, clock: clock}
+}
+
+// Work evaluates every HELD reservation once.
+//
+// Settle is conditional (WHERE status = 'HELD'), so a sweep racing the
+// event handler or a second sweep is a no-op rather than a double release.
+func (w *QuotaSweepWorker) Work(ctx context.Context, job *river.Job[QuotaSweepArgs]) error {
+	held, err := w.quotaRepo.ListHeld(ctx)
+	if err != nil {
+		return fmt.Errorf("list held reservations: %w", err)
+	}
+
+	now := w.clock.Now()
+	var released, consumed int
+	for _, h := range held {
+		action, reason := domain.DecideReservation(h, w.grace, now)
+
+		var status domain.ReservationStatus
+		switch action {
+		case domain.ReservationConsume:
+			status = domain.ReservationConsumed
+		case domain.ReservationRelease:
+			status = domain.ReservationReleased
+		default:
+			continue
+		}
+
+		settled, err := w.quotaRepo.Settle(ctx, h.Reservation.ID, status, reason)
+		if err != nil {
+			return fmt.Errorf("settle reservation %s: %w", h.Reservation.ID, err) // Retry; settled rows are skipped next time
+		}
+		if !settled {
+			continue
+		}
+		if status == domain.ReservationReleased {
+			released++
+			logger.InfoCtx(logger.WithEvent(ctx, h.Reservation.EventID), "Quota reservation released",
+				zap.String("reservation_id", h.Reservation.ID),
+				zap.String("service_id", h.Reservation.ServiceID),
+				zap.String("reason", string(reason)),
+			)
+		} else {
+			consumed++
+		}
+	}
+
+	logger.InfoCtx(ctx, "Quota sweep finished",
+		zap.Int("held", len(held)),
+		zap.Int("released", released),
+		zap.Int("consumed", consumed),
+	)
+	return nil
+}
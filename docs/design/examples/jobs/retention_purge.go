@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// RetentionPurgeArgs purges the expired records of one record type
+// (domain/retention.go).
+//
+// Not event-driven: platform maintenance, like the nonce purge. One job
+// per record type, so a slow audit purge does not hold up the others.
+type RetentionPurgeArgs struct {
+	RecordType domain.RetentionRecordType `json:"record_type"`
+}
+
+// Kind returns the River job kind.
+func (RetentionPurgeArgs) Kind() string { return "retention_purge" }
+
+// InsertOpts keeps at most one run per record type and period.
+func (RetentionPurgeArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Hour},
+	}
+}
+
+// NewRetentionPurgePeriodicJobs schedules one purge per record type.
+// interval comes from governance.retention_purge_interval (default 24h).
+func NewRetentionPurgePeriodicJobs(interval time.Duration) []*river.PeriodicJob {
+	periodic := make([]*river.PeriodicJob, 0, len(domain.RetentionRecordTypes))
+	for _, recordType := range domain.RetentionRecordTypes {
+		args := RetentionPurgeArgs{RecordType: recordType}
+		periodic = append(periodic, river.NewPeriodicJob(
+			river.PeriodicInterval(interval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return args, nil
+			},
+			nil, // Not on start: a rolling deploy would purge on every replica start
+		))
+	}
+	return periodic
+}
+
+// RecordPurger purges the expired records of one record type and returns
+// how many it deleted. Implemented by usecase.PurgeRecordsUseCase.
+type RecordPurger interface {
+	Execute(ctx context.Context, recordType domain.RetentionRecordType) (int64, error)
+}
+
+// RetentionPurgeWorker runs the RecordPurger.
+type RetentionPurgeWorker struct {
+	river.WorkerDefaults[RetentionPurgeArgs]
+
+	purge RecordPurger
+}
+
+// NewRetentionPurgeWorker creates a new worker.
+func NewRetentionPurgeWorker(purge RecordPurger) *RetentionPurgeWorker {
+	return &RetentionPurgeWorker{purge: purge}
+}
+
+// Timeout bounds a run; the batches purged before it stand.
+func (w *RetentionPurgeWorker) Timeout(*river.Job[RetentionPurgeArgs]) time.Duration {
+	return 30 * time.Minute
+}
+
+// Work purges until nothing expired is left or the timeout. An error
+// retries the job; batches already purged are not redone.
+func (w *RetentionPurgeWorker) Work(ctx context.Context, job *river.Job[RetentionPurgeArgs]) error {
+	deleted, err := w.purge.Execute(ctx, job.Args.RecordType)
+	if err != nil {
+		return fmt.Errorf("purge %s: %w", job.Args.RecordType, err)
+	}
+	if deleted > 0 {
+		logger.InfoCtx(ctx, "Expired records purged",
+			zap.String("record_type", string(job.Args.RecordType)),
+			zap.Int64("deleted", deleted),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// SystemMetadataSyncArgs propagates a System's labels/annotations to its
+// VMs and to the namespaces they run in.
+//
+// Enqueued by UpdateSystemMetadataUseCase (InsertTx, same TX as the row).
+// New VMs get the metadata at creation (VMSpec.SystemMetadata), so this job
+// only handles changes.
+type SystemMetadataSyncArgs struct {
+	SystemID string `json:"system_id"`
+}
+
+// Kind returns the River job kind.
+func (SystemMetadataSyncArgs) Kind() string { return "system_metadata_sync" }
+
+// InsertOpts collapses rapid edits: the worker always applies the latest row.
+func (SystemMetadataSyncArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: 30 * time.Second},
+	}
+}
+
+// SystemMetadataSyncWorker applies System metadata with bulk provider patches.
+type SystemMetadataSyncWorker struct {
+	river.WorkerDefaults[SystemMetadataSyncArgs]
+
+	providers    *provider.Registry
+	metadataRepo repository.SystemMetadataRepository
+	vmRepo       repository.VMRepository
+}
+
+// NewSystemMetadataSyncWorker creates a new worker.
+func NewSystemMetadataSyncWorker(
+	providers *provider.Registry,
+	metadataRepo repository.SystemMetadataRepository,
+	vmRepo repository.VMRepository,
+) *SystemMetadataSyncWorker {
+	return &SystemMetadataSyncWorker{
+		providers:    providers,
+		metadataRepo: metadataRepo,
+		vmRepo:       vmRepo,
+	}
+}
+
+// Work patches every cluster hosting the System's VMs.
+// Patches are idempotent (planned per object), so retries are safe.
+func (w *SystemMetadataSyncWorker) Work(ctx context.Context, job *river.Job[SystemMetadataSyncArgs]) error {
+	meta, err := w.metadataRepo.Get(ctx, job.Args.SystemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Cleared: apply the empty set so previously applied keys are removed
+		meta = &domain.SystemMetadata{SystemID: job.Args.SystemID}
+	} else if err != nil {
+		return fmt.Errorf("get system metadata: %w", err)
+	}
+
+	systemName, err := w.metadataRepo.SystemName(ctx, job.Args.SystemID)
+	if err != nil {
+		return fmt.Errorf("get system name: %w", err)
+	}
+
+	placements, err := w.vmRepo.NamespacesForSystem(ctx, job.Args.SystemID) // cluster → namespaces
+	if err != nil {
+		return fmt.Errorf("list system namespaces: %w", err)
+	}
+
+	var failed int
+	ctx = logger.WithResource(ctx, "system", job.Args.SystemID)
+	for cluster, namespaces := range placements {
+		ctx := logger.WithCluster(ctx, cluster)
+		if err := w.syncCluster(ctx, cluster, systemName, meta, namespaces); err != nil {
+			failed++
+			logger.WarnCtx(ctx, "System metadata sync failed", zap.Error(err))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("system metadata sync failed on %d of %d clusters", failed, len(placements))
+	}
+	return nil
+}
+
+func (w *SystemMetadataSyncWorker) syncCluster(ctx context.Context, cluster, systemName string, meta *domain.SystemMetadata, namespaces []string) error {
+	mp, err := w.providers.Metadata(cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return nil // Provider cannot carry labels; nothing to sync
+	}
+	if err != nil {
+		return err
+	}
+
+	selector := domain.ManagedSelector() + "," + domain.LabelSystem + "=" + systemName
+	patched, err := mp.ApplyVMMetadata(ctx, cluster, selector, meta.Desired())
+	if err != nil {
+		return fmt.Errorf("patch vms: %w", err)
+	}
+
+	for _, ns := range namespaces {
+		// Every System with VMs in the namespace contributes; disagreeing keys are skipped
+		systems, err := w.metadataRepo.ListForNamespace(ctx, cluster, ns)
+		if err != nil {
+			return fmt.Errorf("list namespace systems: %w", err)
+		}
+		desired, conflicts := domain.MergeForNamespace(systems)
+		if len(conflicts) > 0 {
+			logger.WarnCtx(ctx, "Conflicting System metadata on namespace",
+				zap.String("namespace", ns),
+				zap.Strings("keys", conflicts),
+			)
+		}
+		if err := mp.ApplyNamespaceMetadata(ctx, cluster, ns, desired); err != nil {
+			return fmt.Errorf("patch namespace %s: %w", ns, err)
+		}
+	}
+
+	logger.InfoCtx(ctx, "System metadata synced",
+		zap.String("system", systemName),
+		zap.Int("vms_patched", patched),
+		zap.Int("namespaces", len(namespaces)),
+	)
+	return nil
+}
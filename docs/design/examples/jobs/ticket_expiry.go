@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// TicketExpiryArgs expires pending tickets past their TTL
+// (domain/ticket_expiry.go).
+//
+// Not event-driven: platform maintenance, like the SLA check.
+type TicketExpiryArgs struct{}
+
+// Kind returns the River job kind.
+func (TicketExpiryArgs) Kind() string { return "ticket_expiry" }
+
+// InsertOpts keeps at most one run per period.
+func (TicketExpiryArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewTicketExpiryPeriodicJob schedules the expiry run.
+// interval comes from governance.ticket_expiry_interval.
+func NewTicketExpiryPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return TicketExpiryArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// TicketExpirer expires one batch of stale pending tickets and returns
+// how many it expired. Implemented by usecase.ExpireTicketsUseCase.
+type TicketExpirer interface {
+	Execute(ctx context.Context) (int, error)
+}
+
+// TicketExpiryWorker runs the TicketExpirer.
+type TicketExpiryWorker struct {
+	river.WorkerDefaults[TicketExpiryArgs]
+
+	expire TicketExpirer
+}
+
+// NewTicketExpiryWorker creates a new worker.
+func NewTicketExpiryWorker(expire TicketExpirer) *TicketExpiryWorker {
+	return &TicketExpiryWorker{expire: expire}
+}
+
+// Work expires one batch. An error retries the job; tickets expired
+// before it are not touched again (no longer pending).
+func (w *TicketExpiryWorker) Work(ctx context.Context, job *river.Job[TicketExpiryArgs]) error {
+	expired, err := w.expire.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("expire tickets: %w", err)
+	}
+	if expired > 0 {
+		logger.InfoCtx(ctx, "Stale tickets expired", zap.Int("expired", expired))
+	}
+	return nil
+}
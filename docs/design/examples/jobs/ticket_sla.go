@@ -0,0 +1,203 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ticketSLABatchSize bounds the tickets escalated per run; the rest are
+// picked up by the next run.
+const ticketSLABatchSize = 200
+
+// TicketSLAArgs escalates pending tickets past their SLA due time
+// (domain/approval_sla.go).
+//
+// Not event-driven: platform maintenance, like the quota sweep.
+type TicketSLAArgs struct{}
+
+// Kind returns the River job kind.
+func (TicketSLAArgs) Kind() string { return "ticket_sla" }
+
+// InsertOpts keeps at most one check per period.
+func (TicketSLAArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewTicketSLAPeriodicJob schedules the check.
+// interval comes from governance.sla_check_interval.
+func NewTicketSLAPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return TicketSLAArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// TicketSLAWorker flags overdue tickets and notifies their approvers.
+type TicketSLAWorker struct {
+	river.WorkerDefaults[TicketSLAArgs]
+
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	ticketRepo  repository.ApprovalTicketRepository
+	roleRepo    repository.RoleBindingRepository
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewTicketSLAWorker creates a new worker.
+func NewTicketSLAWorker(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	ticketRepo repository.ApprovalTicketRepository,
+	roleRepo repository.RoleBindingRepository,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *TicketSLAWorker {
+	return &TicketSLAWorker{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		ticketRepo:  ticketRepo,
+		roleRepo:    roleRepo,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Work escalates each overdue ticket once.
+//
+// The flag is set conditionally (WHERE status = 'PENDING_APPROVAL' AND
+// escalated_at IS NULL) in the same TX as the audit record, so a ticket
+// approved meanwhile, or escalated by a racing run, is skipped.
+// Notifications are sent after commit, best effort.
+func (w *TicketSLAWorker) Work(ctx context.Context, job *river.Job[TicketSLAArgs]) error {
+	now := w.clock.Now()
+	overdue, err := w.ticketRepo.ListOverdue(ctx, now, ticketSLABatchSize)
+	if err != nil {
+		return fmt.Errorf("list overdue tickets: %w", err)
+	}
+	if len(overdue) == 0 {
+		return nil
+	}
+
+	// Escalation pool: global holders of approval:approve
+	platformApprovers, err := w.roleRepo.ListUsersWithPermission(ctx, "approval:approve")
+	if err != nil {
+		return fmt.Errorf("list platform approvers: %w", err)
+	}
+
+	var escalated int
+	for _, ticket := range overdue {
+		ok, err := w.escalate(ctx, ticket, now)
+		if err != nil {
+			return fmt.Errorf("escalate ticket %s: %w", ticket.TicketID, err) // Retry; escalated tickets are skipped next time
+		}
+		if !ok {
+			continue
+		}
+		escalated++
+		w.notify(ctx, ticket, platformApprovers, now)
+	}
+
+	logger.InfoCtx(ctx, "Ticket SLA check finished",
+		zap.Int("overdue", len(overdue)),
+		zap.Int("escalated", escalated),
+	)
+	return nil
+}
+
+// escalate sets escalated_at and writes approval.escalated in one TX.
+// It returns false if the ticket is no longer pending or already escalated.
+func (w *TicketSLAWorker) escalate(ctx context.Context, ticket *domain.ApprovalTicket, now time.Time) (bool, error) {
+	tx, err := w.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := w.sqlcQueries.WithTx(tx)
+
+	rows, err := sqlcTx.MarkTicketEscalated(ctx, sqlc.MarkTicketEscalatedParams{
+		TicketID:    ticket.TicketID,
+		EscalatedAt: now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("mark escalated: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	err = sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           w.ids.NewID(),
+		Action:       domain.AuditApprovalEscalated,
+		ActorID:      "system",
+		ResourceType: "approval",
+		ResourceID:   ticket.TicketID,
+		Details: map[string]interface{}{
+			"priority":    string(ticket.Priority),
+			"environment": string(ticket.Environment),
+			"stage":       ticket.CurrentStage,
+			"sla_due_at":  ticket.SLADueAt,
+			"overdue_by":  now.Sub(*ticket.SLADueAt).Round(time.Minute).String(),
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
+
+// notify tells the current stage's approvers and the platform approvers.
+// The requester is never notified as an approver.
+func (w *TicketSLAWorker) notify(ctx context.Context, ticket *domain.ApprovalTicket, platformApprovers []string, now time.Time) {
+	assigned, err := w.ticketRepo.ListStageApprovers(ctx, ticket.TicketID, ticket.CurrentStage)
+	if err != nil {
+		logger.WarnCtx(ctx, "List stage approvers failed", zap.Error(err))
+	}
+
+	seen := map[string]bool{ticket.CreatedBy: true}
+	var notifications []*domain.Notification
+	for _, recipient := range append(assigned, platformApprovers...) {
+		if seen[recipient] {
+			continue
+		}
+		seen[recipient] = true
+		notifications = append(notifications, &domain.Notification{
+			ID:              w.ids.NewID(),
+			Recipient:       recipient,
+			Type:            domain.NotificationApprovalOverdue,
+			Title:           fmt.Sprintf("Approval overdue: %s %s", ticket.Environment, ticket.RequestType),
+			Content:         fmt.Sprintf("Requested by %s, due %s.\nReason: %s", ticket.CreatedBy, ticket.SLADueAt.Format(time.RFC3339), ticket.RequestReason),
+			RelatedTicketID: ticket.TicketID,
+			Priority:        ticket.Priority,
+			CreatedAt:       now,
+		})
+	}
+
+	if err := w.notifier.SendBatch(ctx, notifications); err != nil {
+		logger.WarnCtx(ctx, "Send escalation notifications failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// UsageSnapshotArgs records today's usage of every Service
+// (domain/usage_trend.go).
+//
+// Not event-driven: platform maintenance, like the quota sweep. It runs
+// several times a day and overwrites today's rows, so a day's snapshot is
+// its last run and a missed run only costs freshness.
+type UsageSnapshotArgs struct{}
+
+// Kind returns the River job kind.
+func (UsageSnapshotArgs) Kind() string { return "usage_snapshot" }
+
+// InsertOpts keeps at most one snapshot per period.
+func (UsageSnapshotArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true, ByPeriod: time.Minute},
+	}
+}
+
+// NewUsageSnapshotPeriodicJob schedules the snapshot. interval comes from
+// governance.usage_snapshot_interval (default 1h).
+func NewUsageSnapshotPeriodicJob(interval time.Duration) *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(interval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return UsageSnapshotArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
+
+// UsageSnapshotWorker upserts one usage_snapshots row per Service.
+type UsageSnapshotWorker struct {
+	river.WorkerDefaults[UsageSnapshotArgs]
+
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+}
+
+// NewUsageSnapshotWorker creates a new worker.
+func NewUsageSnapshotWorker(sqlcQueries *sqlc.Queries, clock domain.Clock) *UsageSnapshotWorker {
+	return &UsageSnapshotWorker{sqlcQueries: sqlcQueries, clock: clock}
+}
+
+// Work snapshots all Services in one statement, so a day never holds a
+// mix of runs. Services without VMs get a zero row: the trend then shows
+// the drop to zero instead of a gap.
+func (w *UsageSnapshotWorker) Work(ctx context.Context, job *river.Job[UsageSnapshotArgs]) error {
+	day := domain.UsageDay(w.clock.Now())
+	// INSERT ... SELECT ... ON CONFLICT (day, service_id) DO UPDATE
+	n, err := w.sqlcQueries.SnapshotServiceUsage(ctx, day)
+	if err != nil {
+		return fmt.Errorf("snapshot service usage: %w", err)
+	}
+	logger.InfoCtx(ctx, "Usage snapshot recorded",
+		zap.Time("day", day),
+		zap.Int64("services", n),
+	)
+	return nil
+}
@@ -0,0 +1,229 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// BackupHandler has the configured backup provider take the backups
+// requested via SnapshotVMUseCase.SubmitBackup (domain/vm_backup.go).
+//
+// Like SnapshotHandler it looks the backup up by its payload name first
+// and starts it only if missing, then snoozes until the provider reports
+// it completed or failed. A completed backup becomes a restore point, in
+// the same TX as the event's result and the audit log.
+type BackupHandler struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	backups      provider.BackupProvider // nil if backup.provider is empty
+	pollInterval time.Duration           // backup.poll_interval
+	notifier     domain.NotificationSender
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewBackupHandler creates a new handler.
+func NewBackupHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	backups provider.BackupProvider,
+	pollInterval time.Duration,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *BackupHandler {
+	return &BackupHandler{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		backups:      backups,
+		pollInterval: pollInterval,
+		notifier:     notifier,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// Handle starts the backup, or checks on the one already started.
+func (h *BackupHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.BackupPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode backup payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	// Approved before the provider was changed or removed: never send it elsewhere
+	if h.backups == nil || h.backups.Name() != p.Provider {
+		return h.finish(ctx, event, p, nil, fmt.Sprintf("backup provider %s is no longer configured", p.Provider))
+	}
+
+	backup, err := h.backups.GetBackup(ctx, p.Cluster, p.BackupName)
+	if errors.Is(err, provider.ErrNotFound) {
+		backup, err = h.backups.StartBackup(ctx, domain.BackupTarget{
+			BackupName: p.BackupName,
+			Cluster:    p.Cluster,
+			Namespace:  p.Namespace,
+			VMName:     p.Name,
+			Labels: map[string]string{
+				domain.LabelManagedBy: domain.ManagedByValue,
+				domain.LabelService:   p.ServiceID,
+			},
+		})
+		if errors.Is(err, provider.ErrAlreadyExists) {
+			// Started by a concurrent delivery: look again next time
+			return river.JobSnooze(h.pollInterval)
+		}
+	}
+	switch {
+	case err != nil:
+		return fmt.Errorf("backup vm: %w", err) // Retry
+	case backup.Phase == domain.BackupFailed:
+		msg := backup.ErrorMessage
+		if msg == "" {
+			msg = "backup failed"
+		}
+		return h.finish(ctx, event, p, nil, msg)
+	case backup.Phase != domain.BackupCompleted:
+		return river.JobSnooze(h.pollInterval)
+	}
+	return h.finish(ctx, event, p, backup, "")
+}
+
+// HandleFinalFailure records the backup as failed once River gives up.
+func (h *BackupHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.BackupPayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode backup payload: %w", err)
+	}
+	return h.finish(ctx, event, p, nil, cause.Error())
+}
+
+// finish records the outcome with its audit log in one TX: on success the
+// restore point and the event's result. errMsg is empty on success.
+func (h *BackupHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.BackupPayload, backup *domain.ExternalBackup, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	eventStatus := domain.EventStatusCompleted
+	var result []byte
+	if errMsg != "" {
+		eventStatus = domain.EventStatusFailed
+	} else {
+		rp := domain.RestorePoint{
+			ID:         h.ids.NewID(),
+			VMID:       p.VMID,
+			Source:     domain.RestorePointBackup,
+			Provider:   p.Provider,
+			Name:       p.BackupName,
+			ExternalID: backup.ExternalID,
+			EventID:    event.EventID,
+			SizeBytes:  backup.SizeBytes,
+			CreatedAt:  h.clock.Now(),
+			ExpiresAt:  backup.ExpiresAt,
+		}
+		if backup.CompletedAt != nil {
+			rp.CreatedAt = *backup.CompletedAt
+		}
+		if err := createRestorePoint(ctx, sqlcTx, rp); err != nil {
+			return err
+		}
+		result, err = domain.BackupResult{
+			Provider:       p.Provider,
+			BackupName:     p.BackupName,
+			RestorePointID: rp.ID,
+			SizeBytes:      rp.SizeBytes,
+			ExpiresAt:      rp.ExpiresAt,
+		}.ToJSON()
+		if err != nil {
+			return err
+		}
+	}
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  result,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"event_id":    event.EventID,
+		"backup_name": p.BackupName,
+		"provider":    p.Provider,
+		"result":      eventStatus,
+		"error":       errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMBackup,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Backup failed",
+			zap.String("backup", p.BackupName),
+			zap.String("provider", p.Provider),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationBackupFinished,
+		Title:     fmt.Sprintf("Backup %s: %s", p.BackupName, eventStatus),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send backup notification failed", zap.Error(err))
+	}
+	return nil
+}
+
+// createRestorePoint records a restore point of a completed snapshot or
+// backup request, or of a restore request's safety snapshot.
+func createRestorePoint(ctx context.Context, sqlcTx *sqlc.Queries, rp domain.RestorePoint) error {
+	err := sqlcTx.CreateRestorePoint(ctx, sqlc.CreateRestorePointParams{
+		ID:         rp.ID,
+		VMID:       rp.VMID,
+		Source:     string(rp.Source),
+		Provider:   rp.Provider,
+		Name:       rp.Name,
+		ExternalID: rp.ExternalID,
+		EventID:    rp.EventID,
+		SizeBytes:  rp.SizeBytes,
+		CreatedAt:  rp.CreatedAt,
+		ExpiresAt:  rp.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("create restore point: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// clonePollInterval is how long a job sleeps while its clone is in
+// progress.
+const clonePollInterval = 15 * time.Second
+
+// CloneProviders resolves the cluster's CloneProvider.
+// Implemented by provider.Registry.
+type CloneProviders interface {
+	Clones(cluster string) (provider.CloneProvider, error)
+}
+
+// CloneHandler makes the clones requested via CloneVMUseCase.
+//
+// The clone object is named after the target VM, fixed in the payload, so
+// a retry or duplicate delivery finds the clone already started and waits
+// for it instead of starting another. Once it succeeds the VM is recorded
+// in the target Service and the quota reservation consumed, in one TX.
+type CloneHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clones      CloneProviders
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewCloneHandler creates a new handler.
+func NewCloneHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	clones CloneProviders,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *CloneHandler {
+	return &CloneHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		clones:      clones,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle starts the clone, or checks on the one already started.
+func (h *CloneHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.ClonePayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode clone payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.SourceVMID)
+
+	cp, err := h.clones.Clones(p.Cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return h.finish(ctx, event, p, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get clone provider: %w", err)
+	}
+
+	clone, err := cp.GetClone(ctx, p.Cluster, p.Namespace, p.TargetName)
+	if errors.Is(err, provider.ErrNotFound) {
+		if p.SnapshotName != "" {
+			_, err = cp.CloneFromSnapshot(ctx, p.Cluster, p.Namespace, p.SnapshotName, p.TargetName)
+		} else {
+			_, err = cp.CloneVM(ctx, p.Cluster, p.Namespace, p.SourceName, p.TargetName)
+		}
+		if err == nil {
+			return river.JobSnooze(clonePollInterval)
+		}
+	}
+	switch {
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		// Source or snapshot gone, or the name is taken: retrying cannot help
+		return h.finish(ctx, event, p, err.Error())
+	case err != nil:
+		return fmt.Errorf("clone vm: %w", err) // Retry
+	case clone.Status == domain.ClonePhaseFailed:
+		return h.finish(ctx, event, p, "clone failed")
+	case clone.Status != domain.ClonePhaseSucceeded:
+		return river.JobSnooze(clonePollInterval)
+	}
+	return h.finish(ctx, event, p, "")
+}
+
+// HandleFinalFailure records the clone as failed once River gives up.
+func (h *CloneHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.ClonePayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode clone payload: %w", err)
+	}
+	return h.finish(ctx, event, p, cause.Error())
+}
+
+// finish records the outcome with its audit log in one TX. On success the
+// clone is recorded as a VM of the target Service, with the source's
+// build record (template version, image), and the reservation becomes
+// usage; on failure the reservation is released. errMsg is empty on
+// success.
+func (h *CloneHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.ClonePayload, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	eventStatus := domain.EventStatusCompleted
+	reservation, releaseReason := domain.ReservationConsumed, domain.ReleaseReason("")
+	var result []byte
+	if errMsg != "" {
+		eventStatus = domain.EventStatusFailed
+		reservation, releaseReason = domain.ReservationReleased, domain.ReleaseExecutionFailed
+	} else {
+		vmID := h.ids.NewID()
+		// INSERT ... SELECT: namespace, cluster, size, template_id,
+		// image_source and image_digest are copied from the source row
+		if err := sqlcTx.CreateClonedVM(ctx, sqlc.CreateClonedVMParams{
+			ID:            vmID,
+			SourceVMID:    p.SourceVMID,
+			Name:          p.TargetName,
+			ServiceID:     p.ServiceID,
+			Instance:      p.Instance,
+			Status:        string(domain.VMStatusStopped), // Clone targets are created halted
+			ProvisionedAt: h.clock.Now(),
+			CreatedBy:     event.CreatedBy,
+		}); err != nil {
+			return fmt.Errorf("create vm: %w", err)
+		}
+		result, err = domain.CloneResult{VMID: vmID, Name: p.TargetName}.ToJSON()
+		if err != nil {
+			return err
+		}
+	}
+
+	// status = $2, result = $3; result is written once, never overwritten
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  result,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	// WHERE status = 'HELD': a no-op if the sweep settled it first
+	if err := sqlcTx.SettleQuotaReservationByEvent(ctx, sqlc.SettleQuotaReservationByEventParams{
+		EventID: event.EventID,
+		Status:  string(reservation),
+		Reason:  string(releaseReason),
+	}); err != nil {
+		return fmt.Errorf("settle quota reservation: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"event_id":      event.EventID,
+		"target_name":   p.TargetName,
+		"service_id":    p.ServiceID,
+		"snapshot_name": p.SnapshotName,
+		"result":        eventStatus,
+		"error":         errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMClone,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.SourceVMID,
+		ResourceName: p.SourceName,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Clone failed",
+			zap.String("target", p.TargetName),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationCloneFinished,
+		Title:     fmt.Sprintf("Clone of %s to %s: %s", p.SourceName, p.TargetName, eventStatus),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send clone notification failed", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// MigrationProviders resolves the cluster's MigrationProvider.
+// Implemented by provider.Registry.
+type MigrationProviders interface {
+	Migrations(cluster string) (provider.MigrationProvider, error)
+}
+
+// MigrationHandler runs the migrations requested via MigrateVMUseCase.
+//
+// The VirtualMachineInstanceMigration is created once and recorded on the
+// vm_migrations row; a retry or duplicate delivery polls the recorded one
+// instead of starting another. Each poll records the phase and target
+// node, and cancels the migration if an admin asked to.
+type MigrationHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	migrations  MigrationProviders
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewMigrationHandler creates a new handler.
+func NewMigrationHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	migrations MigrationProviders,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *MigrationHandler {
+	return &MigrationHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		migrations:  migrations,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle starts the migration, or polls the one already started.
+func (h *MigrationHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.MigrationPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode migration payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	row, err := h.sqlcQueries.GetVMMigration(ctx, p.MigrationID)
+	if err != nil {
+		return fmt.Errorf("get vm migration: %w", err)
+	}
+	if domain.VMMigrationStatus(row.Status).IsTerminal() {
+		return nil // Cancelled before it started
+	}
+
+	mp, err := h.migrations.Migrations(p.Cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return h.finish(ctx, event, p, domain.VMMigrationFailed, nil, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get migration provider: %w", err)
+	}
+
+	if row.MigrationName == "" {
+		return h.start(ctx, event, p, mp)
+	}
+
+	mig, err := mp.GetMigration(ctx, p.Cluster, p.Namespace, row.MigrationName)
+	if err != nil {
+		return fmt.Errorf("get migration: %w", err) // Retry
+	}
+	switch {
+	case mig.Status == domain.MigrationPhaseSucceeded:
+		return h.finish(ctx, event, p, domain.VMMigrationSucceeded, mig, "")
+	case mig.Status == domain.MigrationPhaseFailed && row.CancelRequestedAt != nil:
+		return h.finish(ctx, event, p, domain.VMMigrationCancelled, mig, "")
+	case mig.Status == domain.MigrationPhaseFailed:
+		return h.finish(ctx, event, p, domain.VMMigrationFailed, mig, mig.ErrorMessage)
+	case row.CancelRequestedAt != nil:
+		// Aborts the VMIM; it reports Failed on a later poll, or Succeeded
+		// if it completed first. Idempotent: an abort already sent is a no-op.
+		if err := mp.CancelMigration(ctx, p.Cluster, p.Namespace, row.MigrationName); err != nil {
+			return fmt.Errorf("cancel migration: %w", err) // Retry
+		}
+	}
+
+	if err := h.sqlcQueries.UpdateVMMigrationProgress(ctx, sqlc.UpdateVMMigrationProgressParams{
+		ID:         p.MigrationID,
+		Phase:      mig.Status,
+		TargetNode: mig.TargetNode,
+	}); err != nil {
+		logger.WarnCtx(ctx, "Record migration progress failed", zap.Error(err))
+	}
+	return river.JobSnooze(migrationPollInterval)
+}
+
+// start creates the VMIM and records it. A cancel that won the row lock
+// meanwhile aborts the VMIM just created.
+func (h *MigrationHandler) start(ctx context.Context, event *domain.DomainEvent, p domain.MigrationPayload, mp provider.MigrationProvider) error {
+	mig, err := mp.MigrateVM(ctx, p.Cluster, p.Namespace, p.Name)
+	switch {
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		// Retrying cannot help
+		return h.finish(ctx, event, p, domain.VMMigrationFailed, nil, err.Error())
+	case err != nil:
+		return fmt.Errorf("migrate vm: %w", err) // Retry
+	}
+
+	// Conditional on status = 'PENDING'
+	rows, err := h.sqlcQueries.StartVMMigration(ctx, sqlc.StartVMMigrationParams{
+		ID:            p.MigrationID,
+		MigrationName: mig.Name,
+		Phase:         mig.Status,
+		SourceNode:    mig.SourceNode,
+		StartedAt:     h.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+	if rows == 0 {
+		if err := mp.CancelMigration(ctx, p.Cluster, p.Namespace, mig.Name); err != nil {
+			return fmt.Errorf("cancel migration: %w", err) // Retry until aborted
+		}
+		return nil
+	}
+	return river.JobSnooze(migrationPollInterval)
+}
+
+// HandleFinalFailure records the migration as failed once River gives up.
+func (h *MigrationHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.MigrationPayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode migration payload: %w", err)
+	}
+	return h.finish(ctx, event, p, domain.VMMigrationFailed, nil, cause.Error())
+}
+
+// finish records the outcome on the migration and its event, with the
+// audit log, in one TX. mig is nil when no VMIM was started.
+func (h *MigrationHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.MigrationPayload, status domain.VMMigrationStatus, mig *domain.Migration, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	result := domain.MigrationResult{Status: status}
+	if mig != nil {
+		result.MigrationName, result.SourceNode, result.TargetNode = mig.Name, mig.SourceNode, mig.TargetNode
+	}
+	// Conditional on a non-terminal status: a duplicate delivery changes nothing
+	rows, err := sqlcTx.FinishVMMigration(ctx, sqlc.FinishVMMigrationParams{
+		ID:           p.MigrationID,
+		Status:       string(status),
+		TargetNode:   result.TargetNode,
+		ErrorMessage: errMsg,
+		CompletedAt:  h.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("finish vm migration: %w", err)
+	}
+	if rows == 0 {
+		return nil
+	}
+
+	eventStatus := domain.EventStatusCompleted
+	switch status {
+	case domain.VMMigrationFailed:
+		eventStatus = domain.EventStatusFailed
+	case domain.VMMigrationCancelled:
+		eventStatus = domain.EventStatusCancelled
+	}
+	resultJSON, err := result.ToJSON()
+	if err != nil {
+		return err
+	}
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  resultJSON,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"migration_id":   p.MigrationID,
+		"migration_name": result.MigrationName,
+		"source_node":    result.SourceNode,
+		"target_node":    result.TargetNode,
+		"result":         status,
+		"error":          errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMMigration,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Migration failed",
+			zap.String("migration", result.MigrationName),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationMigrationFinished,
+		Title:     fmt.Sprintf("Migration of %s: %s", p.Name, status),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send migration notification failed", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// PowerOperationHandler executes standalone power operations created by
+// PowerOperationUseCase.
+//
+// Provider errors are retried by River; after the last attempt the
+// operation is recorded as failed (FinalFailureHandler). A VM that is gone
+// from the cluster or not owned by Shepherd fails at once.
+type PowerOperationHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	power       provider.InfrastructureProvider
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewPowerOperationHandler creates a new handler.
+func NewPowerOperationHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	power provider.InfrastructureProvider,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *PowerOperationHandler {
+	return &PowerOperationHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		power:       power,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle runs the action. Idempotent: starting a running VM or stopping a
+// stopped one is a no-op for the provider; a restart may repeat after a
+// crash, which is acceptable for a restart.
+func (h *PowerOperationHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.PowerOperationPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode power operation payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	switch p.Action {
+	case domain.PowerStart:
+		err = h.power.StartVM(ctx, p.Cluster, p.Namespace, p.Name)
+	case domain.PowerStop:
+		err = h.power.StopVM(ctx, p.Cluster, p.Namespace, p.Name)
+	case domain.PowerRestart:
+		err = h.power.RestartVM(ctx, p.Cluster, p.Namespace, p.Name)
+	default:
+		return river.JobCancel(fmt.Errorf("unknown power action %q", p.Action))
+	}
+
+	switch {
+	case err == nil:
+		return h.finish(ctx, event, p, "")
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		// Retrying cannot help
+		return h.finish(ctx, event, p, err.Error())
+	default:
+		return fmt.Errorf("%s vm: %w", p.Action, err) // Retry
+	}
+}
+
+// HandleFinalFailure records the operation as failed once River gives up.
+func (h *PowerOperationHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.PowerOperationPayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode power operation payload: %w", err)
+	}
+	return h.finish(ctx, event, p, cause.Error())
+}
+
+// finish records the result with its audit log in one TX: on success the
+// VM takes the action's result status. errMsg is empty on success. A
+// status the state machine refuses (e.g. the VM was deleted meanwhile)
+// fails the operation instead of being written.
+func (h *PowerOperationHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.PowerOperationPayload, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	now := h.clock.Now()
+	if errMsg == "" {
+		_, err := repository.TransitionVMStatus(ctx, tx, p.VMID, p.Action.ResultStatus(), "", domain.TransitionWorker, now)
+		switch {
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			errMsg = err.Error()
+		case err != nil:
+			return fmt.Errorf("update vm: %w", err)
+		}
+	}
+	eventStatus := domain.EventStatusCompleted
+	if errMsg != "" {
+		eventStatus = domain.EventStatusFailed
+	}
+	if _, err := repository.TransitionEventStatus(ctx, tx, event.EventID, eventStatus, nil, domain.TransitionWorker, now); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"event_id": event.EventID,
+		"result":   eventStatus,
+		"error":    errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       p.Action.AuditAction(),
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Power operation failed",
+			zap.String("action", string(p.Action)),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationPowerOperationFinished,
+		Title:     fmt.Sprintf("%s %s: %s", p.Action, p.Name, eventStatus),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send power operation notification failed", zap.Error(err))
+	}
+	return nil
+}
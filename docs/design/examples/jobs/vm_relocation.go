@@ -0,0 +1,574 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+const relocationPollInterval = 15 * time.Second
+
+// RelocationProviders resolves what a relocation calls on both clusters.
+// Implemented by provider.Registry: its VM calls are ownership-checked
+// against Shepherd's record, which names the source until cutover and the
+// target after it. For reaches a cluster's provider without that check,
+// for the source VM retired after cutover.
+type RelocationProviders interface {
+	provider.InfrastructureProvider
+	For(cluster string) (provider.InfrastructureProvider, error)
+	Relocations(cluster string) (provider.RelocationProvider, error)
+}
+
+// VMRelocationHandler executes VM_RELOCATION_REQUESTED events, standalone
+// (RelocateVMUseCase) or items of a decommission, one step per delivery
+// (domain/vm_relocation.go):
+//
+//	PENDING, STOPPING_SOURCE  record the source, stop it, capture its definition
+//	COPYING_DISKS             export on the source, import on the target, record progress
+//	CREATING_TARGET           create the VM on the target, stopped
+//	AWAITING_CUTOVER          wait for confirm or abort
+//	CUTTING_OVER              hand over the address, update DNS, move the platform record
+//	RETIRING_SOURCE           start the target if the source ran, delete the source
+//
+// Every step is recorded on the vm_relocations row, conditional on the
+// step it leaves, and every provider call is idempotent, so a retry or a
+// duplicate delivery repeats the current step. Waiting is a snooze.
+// Before cutover an abort request or a failure rolls back: the copy is
+// removed and the source started again if it was running.
+//
+//	dispatcher.Register(domain.EventVMRelocationRequested, relocationHandler)
+type VMRelocationHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	relocations repository.VMRelocationRepository
+	providers   RelocationProviders
+	handover    IdentityHandover
+	dns         DNSUpdater
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewVMRelocationHandler creates a new handler.
+func NewVMRelocationHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	relocations repository.VMRelocationRepository,
+	providers RelocationProviders,
+	handover IdentityHandover,
+	dns DNSUpdater,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *VMRelocationHandler {
+	return &VMRelocationHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		relocations: relocations,
+		providers:   providers,
+		handover:    handover,
+		dns:         dns,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle runs the relocation's current step.
+func (h *VMRelocationHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.VMRelocationPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode relocation payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.SourceCluster), "vm", p.VMID)
+
+	r, err := h.get(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	if r.Status.IsTerminal() {
+		return nil // Aborted before it started
+	}
+	if r.AbortRequestedAt != nil && r.Status.BeforeCutover() {
+		return h.rollback(ctx, event, r, domain.RelocationAborted, "aborted by "+r.AbortRequestedBy)
+	}
+
+	src, err := h.providers.Relocations(r.SourceCluster)
+	if err == nil {
+		_, err = h.providers.Relocations(r.TargetCluster)
+	}
+	if errors.Is(err, provider.ErrCapabilityUnsupported) && r.Status.BeforeCutover() {
+		return h.rollback(ctx, event, r, domain.RelocationFailed, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get relocation provider: %w", err)
+	}
+
+	switch r.Status {
+	case domain.RelocationPending, domain.RelocationStoppingSource:
+		return h.stopSource(ctx, event, r, src)
+	case domain.RelocationCopyingDisks:
+		return h.copyDisks(ctx, event, r, src)
+	case domain.RelocationCreatingTarget:
+		return h.createTarget(ctx, r)
+	case domain.RelocationCuttingOver:
+		return h.cutover(ctx, r)
+	case domain.RelocationRetiringSource:
+		return h.retireSource(ctx, event, r, src)
+	default:
+		// AWAITING_CUTOVER: confirm moves it on, abort rolls it back
+		return river.JobSnooze(relocationPollInterval)
+	}
+}
+
+// stopSource records the source as found, stops it and captures its
+// definition and disks.
+func (h *VMRelocationHandler) stopSource(ctx context.Context, event *domain.DomainEvent, r *domain.VMRelocation, src provider.RelocationProvider) error {
+	vm, err := h.providers.GetVM(ctx, r.SourceCluster, r.Namespace, r.VMName)
+	if errors.Is(err, provider.ErrNotFound) {
+		return h.rollback(ctx, event, r, domain.RelocationFailed, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get vm: %w", err)
+	}
+
+	if r.Status == domain.RelocationPending {
+		// Recorded once: a retry must not take the VM it stopped for one
+		// that was stopped before
+		r.WasRunning = vm.Status == domain.VMStatusRunning
+		r.Hostname, r.FQDN = vm.Labels[domain.LabelHostname], vm.Annotations[domain.AnnotationFQDN]
+		if err := h.advance(ctx, r, domain.RelocationStoppingSource); err != nil {
+			return err
+		}
+	}
+
+	if vm.Status != domain.VMStatusStopped {
+		err := h.providers.StopVM(ctx, r.SourceCluster, r.Namespace, r.VMName)
+		if errors.Is(err, domain.ErrNotOwned) {
+			return h.rollback(ctx, event, r, domain.RelocationFailed, err.Error())
+		}
+		if err != nil {
+			return fmt.Errorf("stop vm: %w", err) // Retry
+		}
+		return river.JobSnooze(relocationPollInterval)
+	}
+
+	def, err := src.GetDefinition(ctx, r.SourceCluster, r.Namespace, r.VMName)
+	if err != nil {
+		return fmt.Errorf("get vm definition: %w", err)
+	}
+	r.Definition = def
+	r.ExportName = domain.RelocationExportName(r.VMName, r.ID)
+	r.Disks = nil
+	for _, v := range vm.Volumes {
+		// Container disks and cloud-init travel in the definition
+		if v.ClaimName == "" {
+			continue
+		}
+		r.Disks = append(r.Disks, domain.RelocationDisk{
+			Volume:      v.Name,
+			SourceClaim: v.ClaimName,
+			DataVolume:  domain.RelocationDiskName(v.ClaimName, r.ID),
+			SizeBytes:   v.SizeBytes,
+		})
+	}
+	if err := h.advance(ctx, r, domain.RelocationCopyingDisks); err != nil {
+		return err
+	}
+	return river.JobSnooze(relocationPollInterval)
+}
+
+// copyDisks exports the source's disks and imports each on the target,
+// recording the imports' progress until all succeeded.
+func (h *VMRelocationHandler) copyDisks(ctx context.Context, event *domain.DomainEvent, r *domain.VMRelocation, src provider.RelocationProvider) error {
+	export, err := src.ExportVM(ctx, r.SourceCluster, r.Namespace, r.VMName, r.ExportName)
+	if err != nil {
+		return fmt.Errorf("export vm: %w", err) // Retry
+	}
+	if !export.Ready {
+		return river.JobSnooze(relocationPollInterval)
+	}
+	tgt, err := h.providers.Relocations(r.TargetCluster)
+	if err != nil {
+		return fmt.Errorf("get relocation provider: %w", err)
+	}
+
+	done := true
+	for i := range r.Disks {
+		d := &r.Disks[i]
+		vol, ok := exportedVolume(export, d.SourceClaim)
+		if !ok {
+			return h.rollback(ctx, event, r, domain.RelocationFailed, fmt.Sprintf("claim %s missing from export %s", d.SourceClaim, export.Name))
+		}
+		imp, err := tgt.ImportDisk(ctx, r.TargetCluster, r.Namespace, d.DataVolume, export, vol)
+		if err != nil {
+			return fmt.Errorf("import disk %s: %w", d.Volume, err) // Retry
+		}
+		d.Phase, d.Progress, d.ErrorMessage = imp.Phase, imp.Progress, imp.ErrorMessage
+		switch imp.Phase {
+		case domain.DiskImportFailed:
+			return h.rollback(ctx, event, r, domain.RelocationFailed, fmt.Sprintf("import disk %s: %s", d.Volume, imp.ErrorMessage))
+		case domain.DiskImportSucceeded:
+		default:
+			done = false
+		}
+	}
+
+	if !done {
+		if err := h.sqlcQueries.UpdateVMRelocationDisks(ctx, sqlc.UpdateVMRelocationDisksParams{
+			ID:    r.ID,
+			Disks: r.Disks, // JSONB
+		}); err != nil {
+			logger.WarnCtx(ctx, "Record relocation progress failed", zap.Error(err))
+		}
+		return river.JobSnooze(relocationPollInterval)
+	}
+	if err := h.advance(ctx, r, domain.RelocationCreatingTarget); err != nil {
+		return err
+	}
+	return river.JobSnooze(relocationPollInterval)
+}
+
+// createTarget creates the VM on the target, stopped, on the imported
+// disks; then holds for confirmation or moves straight on.
+func (h *VMRelocationHandler) createTarget(ctx context.Context, r *domain.VMRelocation) error {
+	tgt, err := h.providers.Relocations(r.TargetCluster)
+	if err != nil {
+		return fmt.Errorf("get relocation provider: %w", err)
+	}
+	disks := make(map[string]string, len(r.Disks))
+	for _, d := range r.Disks {
+		disks[d.SourceClaim] = d.DataVolume
+	}
+	if _, err := tgt.CreateFromDefinition(ctx, r.TargetCluster, r.Namespace, r.Definition, disks); err != nil {
+		return fmt.Errorf("create vm on target: %w", err) // Retry
+	}
+
+	if !r.HoldBeforeCutover {
+		if err := h.advance(ctx, r, domain.RelocationCuttingOver); err != nil {
+			return err
+		}
+		return h.cutover(ctx, r)
+	}
+	if err := h.advance(ctx, r, domain.RelocationAwaitingCutover); err != nil {
+		return err
+	}
+	h.notify(ctx, r, domain.NotificationRelocationReady,
+		fmt.Sprintf("Relocation of %s to %s is ready for cutover", r.VMName, r.TargetCluster),
+		"Disks are copied and the VM is created on the target, stopped. Confirm the cutover or abort the relocation.")
+	return river.JobSnooze(relocationPollInterval)
+}
+
+// cutover moves the address and DNS record to the target and, in one TX,
+// points the platform record at the target cluster.
+func (h *VMRelocationHandler) cutover(ctx context.Context, r *domain.VMRelocation) error {
+	source, err := h.providers.GetVM(ctx, r.SourceCluster, r.Namespace, r.VMName)
+	if err != nil {
+		return fmt.Errorf("get vm: %w", err)
+	}
+	target, err := h.providers.GetVM(ctx, r.TargetCluster, r.Namespace, r.VMName)
+	if err != nil {
+		return fmt.Errorf("get vm on target: %w", err)
+	}
+
+	address, err := h.handover.HandOver(ctx, source, target, r.Hostname, r.FQDN)
+	if err != nil {
+		return fmt.Errorf("hand over identity: %w", err)
+	}
+	if r.FQDN != "" {
+		if err := h.dns.SetRecord(ctx, r.FQDN, address); err != nil {
+			return fmt.Errorf("update dns: %w", err)
+		}
+	}
+	r.Address = address
+
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+	n, err := h.advanceTx(ctx, sqlcTx, r, domain.RelocationRetiringSource)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		// Watch events of the source no longer match the record
+		if err := sqlcTx.MoveVMToCluster(ctx, sqlc.MoveVMToClusterParams{
+			ID:      r.VMID,
+			Cluster: r.TargetCluster,
+		}); err != nil {
+			return fmt.Errorf("move vm record: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return river.JobSnooze(relocationPollInterval)
+}
+
+// retireSource starts the target if the source was running, then deletes
+// the source and its export.
+func (h *VMRelocationHandler) retireSource(ctx context.Context, event *domain.DomainEvent, r *domain.VMRelocation, src provider.RelocationProvider) error {
+	if r.WasRunning {
+		vm, err := h.providers.GetVM(ctx, r.TargetCluster, r.Namespace, r.VMName)
+		if err != nil {
+			return fmt.Errorf("get vm on target: %w", err)
+		}
+		if vm.Status != domain.VMStatusRunning {
+			if err := h.providers.StartVM(ctx, r.TargetCluster, r.Namespace, r.VMName); err != nil {
+				return fmt.Errorf("start vm on target: %w", err) // Retry
+			}
+		}
+	}
+
+	sp, err := h.providers.For(r.SourceCluster)
+	if err != nil {
+		return fmt.Errorf("get source provider: %w", err)
+	}
+	if err := sp.DeleteVM(ctx, r.SourceCluster, r.Namespace, r.VMName); err != nil && !errors.Is(err, provider.ErrNotFound) {
+		return fmt.Errorf("delete source vm: %w", err) // Retry
+	}
+	if err := src.DeleteExport(ctx, r.SourceCluster, r.Namespace, r.ExportName); err != nil && !errors.Is(err, provider.ErrNotFound) {
+		return fmt.Errorf("delete export: %w", err)
+	}
+	return h.finish(ctx, event, r, domain.RelocationCompleted, "")
+}
+
+// rollback removes what the relocation created on both clusters, starts
+// the source again if it was running, and finishes with status. Only
+// valid before cutover; every call tolerates objects already gone.
+func (h *VMRelocationHandler) rollback(ctx context.Context, event *domain.DomainEvent, r *domain.VMRelocation, status domain.RelocationStatus, reason string) error {
+	if tgt, err := h.providers.Relocations(r.TargetCluster); err == nil {
+		tp, err := h.providers.For(r.TargetCluster)
+		if err != nil {
+			return fmt.Errorf("get target provider: %w", err)
+		}
+		if err := tp.DeleteVM(ctx, r.TargetCluster, r.Namespace, r.VMName); err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return fmt.Errorf("delete vm on target: %w", err)
+		}
+		for _, d := range r.Disks {
+			if err := tgt.DeleteDiskImport(ctx, r.TargetCluster, r.Namespace, d.DataVolume); err != nil && !errors.Is(err, provider.ErrNotFound) {
+				return fmt.Errorf("delete disk import %s: %w", d.Volume, err)
+			}
+		}
+	}
+	if src, err := h.providers.Relocations(r.SourceCluster); err == nil && r.ExportName != "" {
+		if err := src.DeleteExport(ctx, r.SourceCluster, r.Namespace, r.ExportName); err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return fmt.Errorf("delete export: %w", err)
+		}
+	}
+	if r.WasRunning {
+		err := h.providers.StartVM(ctx, r.SourceCluster, r.Namespace, r.VMName)
+		if err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return fmt.Errorf("start source vm: %w", err)
+		}
+	}
+	return h.finish(ctx, event, r, status, reason)
+}
+
+// HandleFinalFailure records the relocation as failed once River gives
+// up, after rolling back if it had not cut over yet. A failed rollback is
+// logged: the admin sees what is left in the recorded steps.
+func (h *VMRelocationHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	r, err := h.get(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	if r.Status.BeforeCutover() {
+		err := h.rollback(ctx, event, r, domain.RelocationFailed, cause.Error())
+		if err == nil {
+			return nil
+		}
+		logger.WarnCtx(ctx, "Relocation rollback failed", zap.String("relocation", r.ID), zap.Error(err))
+	}
+	return h.finish(ctx, event, r, domain.RelocationFailed, cause.Error())
+}
+
+// advance records the next step with what the current one found. If the
+// relocation moved meanwhile (abort, concurrent delivery), nothing is
+// written and the job snoozes to read it again.
+func (h *VMRelocationHandler) advance(ctx context.Context, r *domain.VMRelocation, next domain.RelocationStatus) error {
+	n, err := h.advanceTx(ctx, h.sqlcQueries, r, next)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return river.JobSnooze(relocationPollInterval)
+	}
+	return nil
+}
+
+// advanceTx moves r from its status to next, conditional on the status;
+// 0 rows means a concurrent delivery or an abort moved it first.
+func (h *VMRelocationHandler) advanceTx(ctx context.Context, q *sqlc.Queries, r *domain.VMRelocation, next domain.RelocationStatus) (int64, error) {
+	n, err := q.AdvanceVMRelocation(ctx, sqlc.AdvanceVMRelocationParams{
+		ID:            r.ID,
+		From:          string(r.Status),
+		To:            string(next),
+		WasRunning:    r.WasRunning,
+		Definition:    r.Definition,
+		ExportName:    r.ExportName,
+		Disks:         r.Disks, // JSONB
+		Hostname:      r.Hostname,
+		FQDN:          r.FQDN,
+		Address:       r.Address,
+		StepStartedAt: h.clock.Now(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("update relocation: %w", err)
+	}
+	if n > 0 {
+		r.Status = next
+	}
+	return n, nil
+}
+
+// finish records the outcome on the relocation and its event, with the
+// audit log and the decommission's counters, in one TX.
+func (h *VMRelocationHandler) finish(ctx context.Context, event *domain.DomainEvent, r *domain.VMRelocation, status domain.RelocationStatus, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	// Conditional on a non-terminal status: a duplicate delivery changes nothing
+	rows, err := sqlcTx.FinishVMRelocation(ctx, sqlc.FinishVMRelocationParams{
+		ID:           r.ID,
+		Status:       string(status),
+		Address:      r.Address,
+		ErrorMessage: errMsg,
+		CompletedAt:  h.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("finish vm relocation: %w", err)
+	}
+	if rows == 0 {
+		return nil
+	}
+
+	eventStatus := domain.EventStatusCompleted
+	switch status {
+	case domain.RelocationFailed:
+		eventStatus = domain.EventStatusFailed
+	case domain.RelocationAborted:
+		eventStatus = domain.EventStatusCancelled
+	}
+	result, err := domain.RelocationResult{
+		Status:        status,
+		SourceCluster: r.SourceCluster,
+		TargetCluster: r.TargetCluster,
+		Address:       r.Address,
+	}.ToJSON()
+	if err != nil {
+		return err
+	}
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  result,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	// Counters updated in one statement; when PendingCount reaches 0 the
+	// decommission moves to READY_TO_ARCHIVE for admin review
+	if r.DecommissionID != "" {
+		if err := sqlcTx.RecordRelocationResult(ctx, sqlc.RecordRelocationResultParams{
+			DecommissionID: r.DecommissionID,
+			Success:        status == domain.RelocationCompleted,
+		}); err != nil {
+			return fmt.Errorf("record decommission progress: %w", err)
+		}
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"relocation_id":   r.ID,
+		"decommission_id": r.DecommissionID,
+		"source_cluster":  r.SourceCluster,
+		"target_cluster":  r.TargetCluster,
+		"address":         r.Address,
+		"result":          status,
+		"error":           errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMRelocation,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   r.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Relocation did not complete",
+			zap.String("relocation", r.ID),
+			zap.String("status", string(status)),
+			zap.String("error", errMsg),
+		)
+	}
+	h.notify(ctx, r, domain.NotificationRelocationFinished,
+		fmt.Sprintf("Relocation of %s to %s: %s", r.VMName, r.TargetCluster, status), errMsg)
+	return nil
+}
+
+// notify tells the requester of a standalone relocation; decommission
+// items report through the decommission. Best-effort after commit.
+func (h *VMRelocationHandler) notify(ctx context.Context, r *domain.VMRelocation, typ domain.NotificationType, title, content string) {
+	if r.DecommissionID != "" {
+		return
+	}
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: r.RequestedBy,
+		Type:      typ,
+		Title:     title,
+		Content:   content,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send relocation notification failed", zap.Error(err))
+	}
+}
+
+func (h *VMRelocationHandler) get(ctx context.Context, id string) (*domain.VMRelocation, error) {
+	r, err := h.relocations.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, river.JobCancel(fmt.Errorf("relocation %s: %w", id, err))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vm relocation: %w", err)
+	}
+	return r, nil
+}
+
+func exportedVolume(export *domain.VMExport, claim string) (domain.ExportedVolume, bool) {
+	for _, v := range export.Volumes {
+		if v.ClaimName == claim {
+			return v, true
+		}
+	}
+	return domain.ExportedVolume{}, false
+}
@@ -0,0 +1,357 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// VMReplacementArgs advances one blue/green replacement
+// (domain/vm_replacement.go).
+//
+// Inserted with the replacement (in green's create request TX), and again
+// by confirm and abort. Not unique: a confirm must run after the earlier
+// job completed.
+type VMReplacementArgs struct {
+	ReplacementID string `json:"replacement_id"`
+}
+
+// Kind returns the River job kind.
+func (VMReplacementArgs) Kind() string { return "vm_replacement" }
+
+// IdentityHandover moves a VM's stable identity to its replacement: the
+// hostname label and FQDN annotation, and a static address when blue has
+// one (blue is stopped first so the address is free). Returns the address
+// the hostname is served on afterwards. Must be idempotent.
+// Implemented per network type (pod network, Multus static IPs).
+type IdentityHandover interface {
+	HandOver(ctx context.Context, blue, green *domain.VM, hostname, fqdn string) (string, error)
+}
+
+// DNSUpdater points a DNS name at an address. Implemented per DNS backend
+// (e.g. RFC 2136, external-dns DNSEndpoint); a no-op when dns.enabled is
+// false.
+type DNSUpdater interface {
+	SetRecord(ctx context.Context, fqdn, address string) error
+}
+
+// VMRetirer deletes a VM through the deletion path and returns the
+// deletion event ("" when already deleting). Implemented by
+// usecase.VMReplacementUseCase.
+type VMRetirer interface {
+	Retire(ctx context.Context, r *domain.VMReplacement, vmID string) (string, error)
+}
+
+// VMReplacementWorker drives a replacement by its status:
+//
+//	PROVISIONING   follow green's creation event, then probe or wait for the owner
+//	CUTTING_OVER   hand over identity, update DNS, retire blue
+//	ABORTED        retire green (if created)
+//
+// Waiting is a snooze, not an error: River does not count snoozes against
+// MaxAttempts, so a long approval cannot exhaust retries.
+type VMReplacementWorker struct {
+	river.WorkerDefaults[VMReplacementArgs]
+
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	replacements repository.VMReplacementRepository
+	eventRepo    repository.DomainEventRepository
+	vmRepo       repository.VMRepository
+	verifier     WarmupVerifier
+	handover     IdentityHandover
+	dns          DNSUpdater
+	retirer      VMRetirer
+	notifier     domain.NotificationSender
+	clock        domain.Clock
+	ids          domain.IDGenerator
+	interval     time.Duration // replacement.poll_interval
+}
+
+// NewVMReplacementWorker creates a new worker.
+func NewVMReplacementWorker(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	replacements repository.VMReplacementRepository,
+	eventRepo repository.DomainEventRepository,
+	vmRepo repository.VMRepository,
+	verifier WarmupVerifier,
+	handover IdentityHandover,
+	dns DNSUpdater,
+	retirer VMRetirer,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+	interval time.Duration,
+) *VMReplacementWorker {
+	return &VMReplacementWorker{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		replacements: replacements,
+		eventRepo:    eventRepo,
+		vmRepo:       vmRepo,
+		verifier:     verifier,
+		handover:     handover,
+		dns:          dns,
+		retirer:      retirer,
+		notifier:     notifier,
+		clock:        clock,
+		ids:          ids,
+		interval:     interval,
+	}
+}
+
+// Work advances the replacement one step.
+func (w *VMReplacementWorker) Work(ctx context.Context, job *river.Job[VMReplacementArgs]) error {
+	r, err := w.replacements.Get(ctx, job.Args.ReplacementID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return river.JobCancel(fmt.Errorf("replacement not found: %s", job.Args.ReplacementID))
+	}
+	if err != nil {
+		return fmt.Errorf("load replacement: %w", err)
+	}
+	ctx = logger.WithResource(ctx, "vm", r.BlueVMID)
+
+	switch r.Status {
+	case domain.ReplacementProvisioning:
+		return w.provision(ctx, r)
+	case domain.ReplacementCuttingOver:
+		err = w.cutover(ctx, r)
+		if err != nil && job.Attempt >= job.MaxAttempts {
+			// Last attempt: blue is retired only after DNS moved, so it
+			// may still be serving; the owner takes over from here
+			if ferr := w.finish(ctx, r, domain.ReplacementFailed, err.Error()); ferr != nil {
+				logger.WarnCtx(ctx, "Failed to record replacement failure", zap.Error(ferr))
+			}
+		}
+		return err
+	case domain.ReplacementAborted:
+		return w.retireGreen(ctx, r)
+	default:
+		// AWAITING_CONFIRMATION: confirm or abort inserts the next job
+		return nil
+	}
+}
+
+// provision follows green's creation, which includes warm-up when enabled.
+func (w *VMReplacementWorker) provision(ctx context.Context, r *domain.VMReplacement) error {
+	if r.GreenVMID == "" {
+		event, err := w.eventRepo.Get(ctx, r.CreationEventID)
+		if err != nil {
+			return fmt.Errorf("get creation event: %w", err)
+		}
+		switch event.Status {
+		case domain.EventStatusCompleted:
+		case domain.EventStatusFailed:
+			return w.finish(ctx, r, domain.ReplacementFailed, "replacement VM creation failed")
+		case domain.EventStatusCancelled:
+			// Rejected, cancelled or expired: blue stays
+			return w.finish(ctx, r, domain.ReplacementAborted, "replacement VM request "+string(event.Status))
+		default:
+			return river.JobSnooze(w.interval)
+		}
+
+		green, err := w.vmRepo.GetByCreationEvent(ctx, r.CreationEventID)
+		if err != nil {
+			return fmt.Errorf("get replacement vm: %w", err)
+		}
+		now := w.clock.Now()
+		if err := w.replacements.RecordGreen(ctx, r.ID, green.ID, green.Name, now); err != nil {
+			return fmt.Errorf("record replacement vm: %w", err)
+		}
+		r.GreenVMID, r.GreenName, r.GreenReadyAt = green.ID, green.Name, &now
+	}
+
+	if r.Confirmation == domain.ConfirmByHealthProbe {
+		green, err := w.vmRepo.Get(ctx, r.GreenVMID)
+		if err != nil {
+			return fmt.Errorf("get replacement vm: %w", err)
+		}
+		report, err := w.verifier.Verify(ctx, green.Cluster, green.Namespace, green.Name)
+		if err != nil {
+			return fmt.Errorf("probe replacement vm: %w", err)
+		}
+		if report.Ready() {
+			return w.advance(ctx, r, domain.ReplacementCuttingOver)
+		}
+		if w.clock.Now().Before(r.GreenReadyAt.Add(domain.ReplacementProbeTimeout)) {
+			return river.JobSnooze(w.interval)
+		}
+		logger.WarnCtx(ctx, "Replacement health probe timed out, waiting for owner",
+			zap.String("green", green.Name),
+			zap.String("failed_checks", report.Summary()),
+		)
+	}
+	return w.advance(ctx, r, domain.ReplacementAwaitingConfirmation)
+}
+
+// advance moves a PROVISIONING replacement on. CUTTING_OVER continues in a
+// new job, inserted in the same TX; AWAITING_CONFIRMATION notifies the
+// requester.
+func (w *VMReplacementWorker) advance(ctx context.Context, r *domain.VMReplacement, next domain.ReplacementStatus) error {
+	tx, err := w.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := w.sqlcQueries.WithTx(tx)
+	n, err := sqlcTx.AdvanceVMReplacement(ctx, sqlc.AdvanceVMReplacementParams{
+		ID:   r.ID,
+		From: string(domain.ReplacementProvisioning),
+		To:   string(next),
+	})
+	if err != nil {
+		return fmt.Errorf("update replacement: %w", err)
+	}
+	if n == 0 {
+		return nil // Moved on meanwhile (retry after crash)
+	}
+	if next == domain.ReplacementCuttingOver {
+		client := river.ClientFromContext[pgx.Tx](ctx)
+		if _, err := client.InsertTx(ctx, tx, VMReplacementArgs{ReplacementID: r.ID}, nil); err != nil {
+			return fmt.Errorf("insert river job: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if next == domain.ReplacementAwaitingConfirmation {
+		w.notify(ctx, r, domain.NotificationReplacementReady,
+			fmt.Sprintf("Replacement %s of %s is ready", r.GreenName, r.BlueName),
+			"Check the new VM, then confirm the cutover or abort the replacement.")
+	}
+	return nil
+}
+
+// cutover moves the identity to green and retires blue. Every step is
+// idempotent, so a retry repeats the whole sequence.
+func (w *VMReplacementWorker) cutover(ctx context.Context, r *domain.VMReplacement) error {
+	blue, err := w.vmRepo.Get(ctx, r.BlueVMID)
+	if err != nil {
+		return fmt.Errorf("get vm: %w", err)
+	}
+	green, err := w.vmRepo.Get(ctx, r.GreenVMID)
+	if err != nil {
+		return fmt.Errorf("get replacement vm: %w", err)
+	}
+
+	address, err := w.handover.HandOver(ctx, blue, green, r.Hostname, r.FQDN)
+	if err != nil {
+		return fmt.Errorf("hand over identity: %w", err)
+	}
+	if r.FQDN != "" {
+		if err := w.dns.SetRecord(ctx, r.FQDN, address); err != nil {
+			return fmt.Errorf("update dns: %w", err)
+		}
+	}
+	r.Address = address
+
+	// Blue goes only after clients were pointed at green
+	if r.RetireEventID == "" {
+		eventID, err := w.retirer.Retire(ctx, r, r.BlueVMID)
+		if err != nil {
+			return err
+		}
+		r.RetireEventID = eventID
+	}
+	return w.finish(ctx, r, domain.ReplacementCompleted, "")
+}
+
+// retireGreen deletes green after an abort; blue was never touched.
+func (w *VMReplacementWorker) retireGreen(ctx context.Context, r *domain.VMReplacement) error {
+	if r.CompletedAt != nil {
+		return nil // Aborted before green existed, or already retired
+	}
+	eventID, err := w.retirer.Retire(ctx, r, r.GreenVMID)
+	if err != nil {
+		return err
+	}
+	r.RetireEventID = eventID
+	return w.finish(ctx, r, domain.ReplacementAborted, "aborted by "+r.ConfirmedBy)
+}
+
+// finish records the final status with its audit log in one TX, then
+// notifies the requester.
+func (w *VMReplacementWorker) finish(ctx context.Context, r *domain.VMReplacement, status domain.ReplacementStatus, reason string) error {
+	tx, err := w.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := w.sqlcQueries.WithTx(tx)
+	if err := sqlcTx.FinishVMReplacement(ctx, sqlc.FinishVMReplacementParams{
+		ID:            r.ID,
+		Status:        string(status),
+		Address:       r.Address,
+		RetireEventID: r.RetireEventID,
+		FailureReason: reason,
+		CompletedAt:   w.clock.Now(),
+	}); err != nil {
+		return fmt.Errorf("finish replacement: %w", err)
+	}
+
+	action := domain.AuditVMReplacementCompleted
+	if status == domain.ReplacementFailed {
+		action = domain.AuditVMReplacementFailed
+	} else if status == domain.ReplacementAborted {
+		action = domain.AuditVMReplacementAborted
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"replacement_id":  r.ID,
+		"green_vm_id":     r.GreenVMID,
+		"address":         r.Address,
+		"retire_event_id": r.RetireEventID,
+		"reason":          reason,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           w.ids.NewID(),
+		Action:       action,
+		ActorID:      r.RequestedBy,
+		ResourceType: "vm",
+		ResourceID:   r.BlueVMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	content := reason
+	if status == domain.ReplacementCompleted {
+		content = fmt.Sprintf("%s now serves %s; %s is being deleted.", r.GreenName, r.Hostname, r.BlueName)
+	}
+	w.notify(ctx, r, domain.NotificationReplacementFinished,
+		fmt.Sprintf("Replacement of %s %s", r.BlueName, status), content)
+	return nil
+}
+
+// notify tells the requester. Best-effort after commit.
+func (w *VMReplacementWorker) notify(ctx context.Context, r *domain.VMReplacement, typ domain.NotificationType, title, content string) {
+	if err := w.notifier.Send(ctx, &domain.Notification{
+		ID:        w.ids.NewID(),
+		Recipient: r.RequestedBy,
+		Type:      typ,
+		Title:     title,
+		Content:   content,
+		CreatedAt: w.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send replacement notification failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RestoreHandler restores VMs as requested via RestoreVMUseCase
+// (domain/vm_restore.go), in two steps that each survive a retry:
+//
+//  1. Safety snapshot: looked up by its payload name and taken only if
+//     missing, like SnapshotHandler; the job snoozes until it is ready to
+//     use, then records it as a restore point. Nothing is overwritten
+//     before that.
+//  2. Restore: a snapshot point is restored in the cluster; restoring the
+//     same snapshot again yields the same disks, so a retry is harmless.
+//     A backup point goes back through the backup provider under the
+//     payload's restore name, polled like BackupHandler.
+//
+// The VM stays stopped; the requester starts it.
+type RestoreHandler struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	snapshots    SnapshotProviders
+	backups      provider.BackupProvider // nil if backup.provider is empty
+	pollInterval time.Duration           // backup.poll_interval
+	notifier     domain.NotificationSender
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewRestoreHandler creates a new handler.
+func NewRestoreHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	snapshots SnapshotProviders,
+	backups provider.BackupProvider,
+	pollInterval time.Duration,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *RestoreHandler {
+	return &RestoreHandler{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		snapshots:    snapshots,
+		backups:      backups,
+		pollInterval: pollInterval,
+		notifier:     notifier,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// Handle takes the safety snapshot, then restores.
+func (h *RestoreHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.RestorePayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode restore payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	// No safety snapshot, no restore
+	sp, err := h.snapshots.Snapshots(p.Cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return h.finish(ctx, event, p, "safety snapshot: "+err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get snapshot provider: %w", err)
+	}
+	if done, err := h.safetySnapshot(ctx, sp, event, p); !done {
+		return err
+	}
+
+	if p.Source == domain.RestorePointBackup {
+		return h.restoreBackup(ctx, event, p)
+	}
+	_, err = sp.RestoreFromSnapshot(ctx, p.Cluster, p.Namespace, p.PointName, p.Name)
+	switch {
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		// Snapshot or VM gone: retrying cannot help
+		return h.finish(ctx, event, p, err.Error())
+	case err != nil:
+		return fmt.Errorf("restore from snapshot: %w", err) // Retry
+	}
+	return h.finish(ctx, event, p, "")
+}
+
+// safetySnapshot takes the safety snapshot or checks on it. done is true
+// once it is ready and recorded; otherwise err is what Handle returns
+// (snooze, retry, or the recorded failure).
+func (h *RestoreHandler) safetySnapshot(ctx context.Context, sp provider.SnapshotProvider, event *domain.DomainEvent, p domain.RestorePayload) (done bool, err error) {
+	snap, err := sp.GetSnapshot(ctx, p.Cluster, p.Namespace, p.SafetySnapshotName)
+	if errors.Is(err, provider.ErrNotFound) {
+		snap, err = sp.CreateSnapshot(ctx, p.Cluster, p.Namespace, p.Name, p.SafetySnapshotName)
+	}
+	switch {
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		return false, h.finish(ctx, event, p, "safety snapshot: "+err.Error())
+	case err != nil:
+		return false, fmt.Errorf("safety snapshot: %w", err) // Retry
+	case snap.ErrorMessage != "":
+		return false, h.finish(ctx, event, p, "safety snapshot: "+snap.ErrorMessage)
+	case !snap.ReadyToUse:
+		return false, river.JobSnooze(snapshotPollInterval)
+	}
+
+	// Recorded before the restore starts, so it is listed even if the
+	// restore fails; ON CONFLICT (event_id) makes the retry a no-op
+	err = createRestorePoint(ctx, h.sqlcQueries, domain.RestorePoint{
+		ID:        h.ids.NewID(),
+		VMID:      p.VMID,
+		Source:    domain.RestorePointSnapshot,
+		Name:      snap.Name,
+		EventID:   event.EventID,
+		SizeBytes: snap.SizeBytes,
+		CreatedAt: snap.CreatedAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restoreBackup starts the backup provider's restore, or checks on it.
+func (h *RestoreHandler) restoreBackup(ctx context.Context, event *domain.DomainEvent, p domain.RestorePayload) error {
+	if h.backups == nil || h.backups.Name() != p.Provider {
+		return h.finish(ctx, event, p, fmt.Sprintf("backup provider %s is no longer configured", p.Provider))
+	}
+
+	restore, err := h.backups.GetRestore(ctx, p.Cluster, p.RestoreName)
+	if errors.Is(err, provider.ErrNotFound) {
+		restore, err = h.backups.StartRestore(ctx, domain.RestoreTarget{
+			RestoreName: p.RestoreName,
+			BackupName:  p.PointName,
+			ExternalID:  p.ExternalID,
+			Cluster:     p.Cluster,
+			Namespace:   p.Namespace,
+			VMName:      p.Name,
+		})
+		if errors.Is(err, provider.ErrAlreadyExists) {
+			// Started by a concurrent delivery: look again next time
+			return river.JobSnooze(h.pollInterval)
+		}
+	}
+	switch {
+	case err != nil:
+		return fmt.Errorf("restore backup: %w", err) // Retry
+	case restore.Phase == domain.BackupFailed:
+		msg := restore.ErrorMessage
+		if msg == "" {
+			msg = "restore failed"
+		}
+		return h.finish(ctx, event, p, msg)
+	case restore.Phase != domain.BackupCompleted:
+		return river.JobSnooze(h.pollInterval)
+	}
+	return h.finish(ctx, event, p, "")
+}
+
+// HandleFinalFailure records the restore as failed once River gives up.
+func (h *RestoreHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.RestorePayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode restore payload: %w", err)
+	}
+	return h.finish(ctx, event, p, cause.Error())
+}
+
+// finish records the outcome with its audit log in one TX. errMsg is
+// empty on success.
+func (h *RestoreHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.RestorePayload, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	eventStatus := domain.EventStatusCompleted
+	var result []byte
+	if errMsg != "" {
+		eventStatus = domain.EventStatusFailed
+	} else {
+		result, err = domain.RestoreResult{
+			RestorePointID:     p.RestorePointID,
+			SafetySnapshotName: p.SafetySnapshotName,
+		}.ToJSON()
+		if err != nil {
+			return err
+		}
+	}
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  result,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"event_id":             event.EventID,
+		"restore_point_id":     p.RestorePointID,
+		"source":               p.Source,
+		"point_name":           p.PointName,
+		"safety_snapshot_name": p.SafetySnapshotName,
+		"result":               eventStatus,
+		"error":                errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMRestore,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Restore failed",
+			zap.String("restore_point", p.RestorePointID),
+			zap.String("safety_snapshot", p.SafetySnapshotName),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationRestoreFinished,
+		Title:     fmt.Sprintf("Restore of %s to %s: %s", p.Name, p.PointName, eventStatus),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send restore notification failed", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// VMResyncArgs relists one cluster for a resync run.
+//
+// Enqueued by ResyncVMsUseCase.Start, one job per cluster (InsertTx,
+// same TX as the resync row).
+type VMResyncArgs struct {
+	ResyncID string `json:"resync_id"`
+	Cluster  string `json:"cluster"`
+}
+
+// Kind returns the River job kind.
+func (VMResyncArgs) Kind() string { return "vm_resync" }
+
+// InsertOpts keeps one job per cluster and run.
+func (VMResyncArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true},
+	}
+}
+
+// VMResyncWorker reconciles one page of a cluster per Work call, then
+// snoozes: the API server sees at most pageSize VMs per interval per
+// cluster, and progress survives restarts through the saved cursor.
+type VMResyncWorker struct {
+	river.WorkerDefaults[VMResyncArgs]
+
+	providers      *provider.Registry
+	resyncRepo     repository.ResyncRepository
+	vmRepo         repository.VMRepository
+	adoptionRepo   repository.PendingAdoptionRepository
+	installationID string
+	pageSize       int
+	interval       time.Duration
+	clock          domain.Clock
+}
+
+// NewVMResyncWorker creates a new worker. pageSize and interval come from
+// k8s.resync_page_size and k8s.resync_page_interval.
+func NewVMResyncWorker(
+	providers *provider.Registry,
+	resyncRepo repository.ResyncRepository,
+	vmRepo repository.VMRepository,
+	adoptionRepo repository.PendingAdoptionRepository,
+	installationID string,
+	pageSize int,
+	interval time.Duration,
+	clock domain.Clock,
+) *VMResyncWorker {
+	return &VMResyncWorker{
+		providers:      providers,
+		resyncRepo:     resyncRepo,
+		vmRepo:         vmRepo,
+		adoptionRepo:   adoptionRepo,
+		installationID: installationID,
+		pageSize:       pageSize,
+		interval:       interval,
+		clock:          clock,
+	}
+}
+
+// Work reconciles the next page. Idempotent: a retried page is compared
+// again and unchanged VMs are no-ops.
+func (w *VMResyncWorker) Work(ctx context.Context, job *river.Job[VMResyncArgs]) error {
+	run, err := w.resyncRepo.Get(ctx, job.Args.ResyncID)
+	if err != nil {
+		return fmt.Errorf("get resync: %w", err)
+	}
+	if run.Status != domain.ResyncRunning {
+		return nil // Cancelled: stop without touching more rows
+	}
+
+	rc, err := w.resyncRepo.GetCluster(ctx, run.ID, job.Args.Cluster)
+	if err != nil {
+		return fmt.Errorf("get resync cluster: %w", err)
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, rc.Cluster), "resync", run.ID)
+	if rc.Done {
+		return nil
+	}
+
+	// Taken before the list: a DB row the watcher wrote after this is newer
+	// than anything on the page and must not be overwritten.
+	listedAt := w.clock.Now()
+	page, err := w.providers.ListVMs(ctx, rc.Cluster, "", provider.ListOptions{
+		LabelSelector: domain.ManagedSelector(),
+		Limit:         w.pageSize,
+		Continue:      rc.Continue,
+	})
+	if errors.Is(err, provider.ErrInvalidContinue) {
+		// Cursor expired (410) between pages: relist from the start.
+		// Pages already seen are reconciled again, which is harmless.
+		rc.Continue = ""
+		if err := w.resyncRepo.SaveClusterProgress(ctx, rc); err != nil {
+			return fmt.Errorf("reset resync cursor: %w", err)
+		}
+		return river.JobSnooze(w.interval)
+	}
+	if err != nil {
+		rc.Error = err.Error()
+		if saveErr := w.resyncRepo.SaveClusterProgress(ctx, rc); saveErr != nil {
+			logger.WarnCtx(ctx, "Failed to record resync error", zap.Error(saveErr))
+		}
+		return fmt.Errorf("list vms: %w", err) // Retry
+	}
+
+	if err := w.reconcile(ctx, run, rc, page.Items, listedAt); err != nil {
+		return err
+	}
+
+	rc.Continue = page.Continue
+	rc.Error = ""
+	if page.Continue != "" {
+		if err := w.resyncRepo.SaveClusterProgress(ctx, rc); err != nil {
+			return fmt.Errorf("save resync progress: %w", err)
+		}
+		return river.JobSnooze(w.interval)
+	}
+
+	return w.finish(ctx, run, rc)
+}
+
+// reconcile applies one page to the DB and updates rc's counters.
+func (w *VMResyncWorker) reconcile(ctx context.Context, run *domain.Resync, rc *domain.ResyncCluster, live []*domain.VM, listedAt time.Time) error {
+	names := make([]string, 0, len(live))
+	for _, vm := range live {
+		names = append(names, vm.Name)
+	}
+	rows, err := w.vmRepo.GetByNames(ctx, rc.Cluster, names)
+	if err != nil {
+		return fmt.Errorf("get vms by name: %w", err)
+	}
+
+	seen := make([]string, 0, len(live))
+	for _, vm := range live {
+		rc.Listed++
+		db := rows[vm.Name]
+
+		switch domain.PlanResync(db, vm, w.installationID) {
+		case domain.ResyncForeign:
+			rc.Foreign++
+			continue
+		case domain.ResyncOrphan:
+			// Same path as the periodic discovery scan; idempotent on k8s_uid
+			if err := w.adoptionRepo.RecordDiscovered(ctx, vm); err != nil {
+				return fmt.Errorf("record pending adoption %s: %w", vm.Name, err)
+			}
+			rc.Orphans++
+			continue
+		case domain.ResyncUpdate:
+			// Conditional on updated_at < listedAt: the watcher wins races
+			applied, err := w.vmRepo.ApplyObserved(ctx, db.ID, vm, listedAt)
+			if err != nil {
+				return fmt.Errorf("apply observed state %s: %w", vm.Name, err)
+			}
+			if applied {
+				rc.Updated++
+			}
+		}
+		seen = append(seen, db.ID)
+	}
+
+	if err := w.vmRepo.MarkResyncSeen(ctx, run.ID, seen); err != nil {
+		return fmt.Errorf("mark vms seen: %w", err)
+	}
+	return nil
+}
+
+// finish flags DB VMs the completed list did not contain, then closes the
+// cluster and, if it was the last one, the run.
+func (w *VMResyncWorker) finish(ctx context.Context, run *domain.Resync, rc *domain.ResyncCluster) error {
+	// Created after the run started: may not have existed at list time
+	unseen, err := w.vmRepo.ListResyncUnseen(ctx, rc.Cluster, run.ID, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("list unseen vms: %w", err)
+	}
+	for _, vm := range unseen {
+		// Through the state machine (repository.TransitionVMStatus, source
+		// resync): a VM the watcher moved on meanwhile is left as it is
+		err := w.vmRepo.UpdateStatus(ctx, vm.ID, domain.MissingStatus(vm.Status))
+		if errors.Is(err, domain.ErrInvalidStatusTransition) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("update missing vm %s: %w", vm.Name, err)
+		}
+		rc.Missing++
+	}
+
+	rc.Done = true
+	// Cluster row + run status in one statement: the last cluster to
+	// finish completes the run, concurrent finishes cannot both miss it
+	completed, err := w.resyncRepo.FinishCluster(ctx, rc)
+	if err != nil {
+		return fmt.Errorf("finish resync cluster: %w", err)
+	}
+
+	logger.InfoCtx(ctx, "Resync cluster finished",
+		zap.Int("listed", rc.Listed),
+		zap.Int("updated", rc.Updated),
+		zap.Int("missing", rc.Missing),
+		zap.Int("orphans", rc.Orphans),
+		zap.Bool("run_completed", completed),
+	)
+	return nil
+}
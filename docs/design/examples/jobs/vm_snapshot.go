@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// snapshotPollInterval is how long a job sleeps while its snapshot is
+// not ready to use.
+const snapshotPollInterval = 15 * time.Second
+
+// SnapshotProviders resolves the cluster's SnapshotProvider.
+// Implemented by provider.Registry.
+type SnapshotProviders interface {
+	Snapshots(cluster string) (provider.SnapshotProvider, error)
+}
+
+// SnapshotHandler takes the snapshots requested via SnapshotVMUseCase.
+//
+// The snapshot name is fixed in the payload, so a retry or duplicate
+// delivery finds the snapshot already started and waits for it instead of
+// taking another. The job snoozes until the snapshot is ready to use,
+// then records its name and size as the event's result, and the snapshot
+// as a restore point of the VM (domain/vm_backup.go).
+type SnapshotHandler struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	snapshots   SnapshotProviders
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewSnapshotHandler creates a new handler.
+func NewSnapshotHandler(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	snapshots SnapshotProviders,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *SnapshotHandler {
+	return &SnapshotHandler{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		snapshots:   snapshots,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Handle starts the snapshot, or checks on the one already started.
+func (h *SnapshotHandler) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p, err := domain.UnmarshalPayload[domain.SnapshotPayload](event.EventType, event.Payload)
+	if err != nil {
+		return river.JobCancel(fmt.Errorf("decode snapshot payload: %w", err))
+	}
+	ctx = logger.WithResource(logger.WithCluster(ctx, p.Cluster), "vm", p.VMID)
+
+	sp, err := h.snapshots.Snapshots(p.Cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return h.finish(ctx, event, p, nil, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("get snapshot provider: %w", err)
+	}
+
+	snap, err := sp.GetSnapshot(ctx, p.Cluster, p.Namespace, p.SnapshotName)
+	if errors.Is(err, provider.ErrNotFound) {
+		snap, err = sp.CreateSnapshot(ctx, p.Cluster, p.Namespace, p.Name, p.SnapshotName)
+	}
+	switch {
+	case errors.Is(err, provider.ErrNotFound), errors.Is(err, domain.ErrNotOwned):
+		// Retrying cannot help
+		return h.finish(ctx, event, p, nil, err.Error())
+	case err != nil:
+		return fmt.Errorf("snapshot vm: %w", err) // Retry
+	case snap.ErrorMessage != "":
+		return h.finish(ctx, event, p, nil, snap.ErrorMessage)
+	case !snap.ReadyToUse:
+		return river.JobSnooze(snapshotPollInterval)
+	}
+	return h.finish(ctx, event, p, snap, "")
+}
+
+// HandleFinalFailure records the snapshot as failed once River gives up.
+func (h *SnapshotHandler) HandleFinalFailure(ctx context.Context, event *domain.DomainEvent, cause error) error {
+	p, err := domain.UnmarshalPayload[domain.SnapshotPayload](event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("decode snapshot payload: %w", err)
+	}
+	return h.finish(ctx, event, p, nil, cause.Error())
+}
+
+// finish records the outcome with its audit log in one TX: on success
+// the snapshot's name and size become the event's result and the
+// snapshot a restore point. errMsg is empty on success.
+func (h *SnapshotHandler) finish(ctx context.Context, event *domain.DomainEvent, p domain.SnapshotPayload, snap *domain.Snapshot, errMsg string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := h.sqlcQueries.WithTx(tx)
+
+	eventStatus := domain.EventStatusCompleted
+	var result []byte
+	if errMsg != "" {
+		eventStatus = domain.EventStatusFailed
+	} else {
+		result, err = domain.SnapshotResult{SnapshotName: snap.Name, SizeBytes: snap.SizeBytes}.ToJSON()
+		if err != nil {
+			return err
+		}
+		err = createRestorePoint(ctx, sqlcTx, domain.RestorePoint{
+			ID:        h.ids.NewID(),
+			VMID:      p.VMID,
+			Source:    domain.RestorePointSnapshot,
+			Name:      snap.Name,
+			EventID:   event.EventID,
+			SizeBytes: snap.SizeBytes,
+			CreatedAt: snap.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// status = $2, result = $3; result is written once, never overwritten
+	if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+		EventID: event.EventID,
+		Status:  string(eventStatus),
+		Result:  result,
+	}); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"event_id":      event.EventID,
+		"snapshot_name": p.SnapshotName,
+		"result":        eventStatus,
+		"error":         errMsg,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           h.ids.NewID(),
+		Action:       domain.AuditVMSnapshot,
+		ActorID:      event.CreatedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	if errMsg != "" {
+		logger.WarnCtx(ctx, "Snapshot failed",
+			zap.String("snapshot", p.SnapshotName),
+			zap.String("error", errMsg),
+		)
+	}
+
+	// Best-effort after commit: the result is recorded either way
+	if err := h.notifier.Send(ctx, &domain.Notification{
+		ID:        h.ids.NewID(),
+		Recipient: event.CreatedBy,
+		Type:      domain.NotificationSnapshotFinished,
+		Title:     fmt.Sprintf("Snapshot %s: %s", p.SnapshotName, eventStatus),
+		Content:   errMsg,
+		CreatedAt: h.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(ctx, "Send snapshot notification failed", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// VMWarmupArgs verifies a newly created VM is usable before its creation
+// event completes (warmup.enabled).
+//
+// Inserted by the VM_CREATION_REQUESTED handler once the provider reports
+// the VM created; the handler leaves the event PROCESSING and has already
+// consumed the quota reservation, since the VM exists either way.
+type VMWarmupArgs struct {
+	EventID   string    `json:"event_id" river:"unique"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Deadline  time.Time `json:"deadline"` // warmup.timeout after creation
+}
+
+// Kind returns the River job kind.
+func (VMWarmupArgs) Kind() string { return "vm_warmup" }
+
+// InsertOpts keeps one warm-up per event across handler retries
+// (unique by EventID only: Deadline differs per retry).
+func (VMWarmupArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{ByArgs: true},
+	}
+}
+
+// NewVMWarmupArgs creates warm-up args with the deadline timeout after now.
+func NewVMWarmupArgs(eventID, cluster, namespace, name string, timeout time.Duration, now time.Time) VMWarmupArgs {
+	return VMWarmupArgs{
+		EventID:   eventID,
+		Cluster:   cluster,
+		Namespace: namespace,
+		Name:      name,
+		Deadline:  now.Add(timeout),
+	}
+}
+
+// WarmupVerifier runs one warm-up attempt.
+// Implemented by service.WarmupVerifier.
+type WarmupVerifier interface {
+	Verify(ctx context.Context, cluster, namespace, name string) (*domain.WarmupReport, error)
+}
+
+// VMWarmupWorker polls the checks until they pass or the deadline passes.
+type VMWarmupWorker struct {
+	river.WorkerDefaults[VMWarmupArgs]
+
+	verifier  WarmupVerifier
+	eventRepo repository.DomainEventRepository
+	interval  time.Duration // warmup.interval
+	clock     domain.Clock
+}
+
+// NewVMWarmupWorker creates a new worker.
+func NewVMWarmupWorker(verifier WarmupVerifier, eventRepo repository.DomainEventRepository, interval time.Duration, clock domain.Clock) *VMWarmupWorker {
+	return &VMWarmupWorker{verifier: verifier, eventRepo: eventRepo, interval: interval, clock: clock}
+}
+
+// Work runs one attempt. Not ready yet is a snooze, not an error: River
+// does not count snoozes against MaxAttempts, so only provider failures
+// use up retries.
+func (w *VMWarmupWorker) Work(ctx context.Context, job *river.Job[VMWarmupArgs]) error {
+	a := job.Args
+	ctx = logger.WithCluster(logger.WithEvent(ctx, a.EventID), a.Cluster)
+	report, err := w.verifier.Verify(ctx, a.Cluster, a.Namespace, a.Name)
+	if err != nil {
+		return fmt.Errorf("verify warm-up: %w", err)
+	}
+
+	if report.Ready() {
+		if err := w.eventRepo.UpdateStatus(ctx, a.EventID, domain.EventStatusCompleted); err != nil {
+			return fmt.Errorf("complete creation event: %w", err)
+		}
+		return nil
+	}
+
+	if w.clock.Now().Before(a.Deadline) {
+		return river.JobSnooze(w.interval)
+	}
+
+	// The VM is kept for inspection: it exists and its quota is consumed.
+	// The requester sees why it is not usable and may delete it.
+	logger.WarnCtx(ctx, "VM warm-up timed out",
+		zap.String("vm", a.Namespace+"/"+a.Name),
+		zap.String("failed_checks", report.Summary()),
+	)
+	if err := w.eventRepo.Fail(ctx, a.EventID, "warm-up timed out: "+report.Summary()); err != nil {
+		return fmt.Errorf("fail creation event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Field names shared by every log line that carries them. Use the With*
+// helpers rather than these keys directly, so one identifier is never
+// logged under two names (ticket vs ticket_id).
+const (
+	FieldUserID       = "user_id"
+	FieldRequestID    = "request_id"
+	FieldEventID      = "event_id"
+	FieldTicketID     = "ticket_id"
+	FieldCluster      = "cluster"
+	FieldResourceType = "resource_type"
+	FieldResourceID   = "resource_id"
+	FieldJobID        = "job_id"
+	FieldJobKind      = "job_kind"
+)
+
+type ctxKey struct{}
+
+// ctxFields is the immutable field set carried by a context. Each With
+// copies it, so a field added in a callee never leaks to the caller.
+type ctxFields []zap.Field
+
+// With returns a context whose *Ctx log lines carry fields. A field with
+// the same key as one already carried replaces it (innermost wins, e.g.
+// a batch child's ticket over the batch's).
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	prev, _ := ctx.Value(ctxKey{}).(ctxFields)
+	next := make(ctxFields, 0, len(prev)+len(fields))
+	for _, f := range prev {
+		if !hasKey(fields, f.Key) {
+			next = append(next, f)
+		}
+	}
+	next = append(next, fields...)
+	return context.WithValue(ctx, ctxKey{}, next)
+}
+
+// WithUser attaches the acting user (the authenticated principal).
+func WithUser(ctx context.Context, userID string) context.Context {
+	return withString(ctx, FieldUserID, userID)
+}
+
+// WithRequestID attaches the HTTP request ID (X-Request-ID).
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return withString(ctx, FieldRequestID, requestID)
+}
+
+// WithEvent attaches the DomainEvent being handled.
+func WithEvent(ctx context.Context, eventID string) context.Context {
+	return withString(ctx, FieldEventID, eventID)
+}
+
+// WithTicket attaches the approval ticket being decided.
+func WithTicket(ctx context.Context, ticketID string) context.Context {
+	return withString(ctx, FieldTicketID, ticketID)
+}
+
+// WithCluster attaches the cluster (name, as VM.Cluster) operated on.
+func WithCluster(ctx context.Context, cluster string) context.Context {
+	return withString(ctx, FieldCluster, cluster)
+}
+
+// WithResource attaches the resource operated on, typed as in audit logs
+// (e.g. "vm", "service").
+func WithResource(ctx context.Context, resourceType, resourceID string) context.Context {
+	if resourceID == "" {
+		return ctx
+	}
+	return With(ctx, zap.String(FieldResourceType, resourceType), zap.String(FieldResourceID, resourceID))
+}
+
+// Fields returns the fields carried by ctx, e.g. to build a child logger
+// for a library.
+func Fields(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(ctxKey{}).(ctxFields)
+	return fields
+}
+
+// FromContext returns the logger with ctx's fields attached.
+func FromContext(ctx context.Context) *zap.Logger {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return L()
+	}
+	return L().With(fields...)
+}
+
+// DebugCtx logs at debug level with ctx's fields.
+func DebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	pkg.Load().Debug(msg, merge(ctx, fields)...)
+}
+
+// InfoCtx logs at info level with ctx's fields.
+func InfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	pkg.Load().Info(msg, merge(ctx, fields)...)
+}
+
+// WarnCtx logs at warn level with ctx's fields.
+func WarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	pkg.Load().Warn(msg, merge(ctx, fields)...)
+}
+
+// ErrorCtx logs at error level with ctx's fields.
+func ErrorCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	pkg.Load().Error(msg, merge(ctx, fields)...)
+}
+
+func withString(ctx context.Context, key, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	return With(ctx, zap.String(key, value))
+}
+
+// merge puts ctx's fields before the call's own; explicit fields with the
+// same key win, as with With.
+func merge(ctx context.Context, fields []zap.Field) []zap.Field {
+	carried := Fields(ctx)
+	if len(carried) == 0 {
+		return fields
+	}
+	out := make([]zap.Field, 0, len(carried)+len(fields))
+	for _, f := range carried {
+		if !hasKey(fields, f.Key) {
+			out = append(out, f)
+		}
+	}
+	return append(out, fields...)
+}
+
+func hasKey(fields []zap.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Package logger provides the process-wide zap logger.
+//
+// Package functions (Info, Warn, ...) log through one logger set up by
+// Init; the level is a zap.AtomicLevel, so log.level hot-reloads
+// (Phase 0 §3). The *Ctx variants (context.go) add the fields carried by
+// the context: user, request, event, cluster, resource.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/pkg/logger
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	level = zap.NewAtomicLevel()
+	base  atomic.Pointer[zap.Logger] // Returned by L
+	pkg   atomic.Pointer[zap.Logger] // Package functions: caller skips the wrapper
+)
+
+func init() {
+	set(zap.NewNop()) // Until Init: tests and tools log nothing
+}
+
+func set(l *zap.Logger) {
+	base.Store(l)
+	pkg.Store(l.WithOptions(zap.AddCallerSkip(1)))
+}
+
+// Init builds the logger from log.level and log.format ("json" or
+// "console"). Called once at startup, before anything logs.
+func Init(lvl, format string) error {
+	if err := SetLevel(lvl); err != nil {
+		return err
+	}
+	cfg := zap.NewProductionConfig()
+	if format == "console" {
+		cfg = zap.NewDevelopmentConfig()
+	}
+	cfg.Level = level
+	l, err := cfg.Build()
+	if err != nil {
+		return fmt.Errorf("build logger: %w", err)
+	}
+	set(l)
+	return nil
+}
+
+// SetLevel changes the level of the running logger (config hot-reload).
+func SetLevel(lvl string) error {
+	parsed, err := zapcore.ParseLevel(lvl)
+	if err != nil {
+		return fmt.Errorf("parse log level: %w", err)
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
+// L returns the logger, e.g. for libraries that take a *zap.Logger.
+func L() *zap.Logger { return base.Load() }
+
+// Debug logs at debug level.
+func Debug(msg string, fields ...zap.Field) { pkg.Load().Debug(msg, fields...) }
+
+// Info logs at info level.
+func Info(msg string, fields ...zap.Field) { pkg.Load().Info(msg, fields...) }
+
+// Warn logs at warn level.
+func Warn(msg string, fields ...zap.Field) { pkg.Load().Warn(msg, fields...) }
+
+// Error logs at error level.
+func Error(msg string, fields ...zap.Field) { pkg.Load().Error(msg, fields...) }
+
+// Sync flushes buffered entries; deferred in main.
+func Sync() error { return L().Sync() }
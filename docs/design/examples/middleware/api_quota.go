@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// APIQuota meters every authenticated call and rejects calls of a signing
+// key over its quota (domain/api_usage.go) with 429 API_QUOTA_EXCEEDED
+// and Retry-After.
+//
+// Runs after authentication (session or SignedRequest), which set
+// "user_id" and, for signed calls, "signing_key_id"; and after the global
+// rate limiter, so calls it rejects are not counted against a token.
+//
+// Fails open: if the shared counters cannot be reached the call proceeds,
+// so metering never takes the API down with the database it protects.
+func APIQuota(meter *repository.APIMeter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		token := c.GetString("signing_key_id")
+		if token == "" {
+			token = domain.SessionToken
+		}
+		now := time.Now()
+
+		if quota, ok := meter.Quota(token); ok {
+			ctx := c.Request.Context()
+			minute, day, err := meter.Consume(ctx, token, now)
+			if err != nil {
+				logger.WarnCtx(ctx, "API quota check failed, allowing call", zap.Error(err))
+			} else if retry, over := quota.Exceeded(minute, day, now); over {
+				meter.Record(now, userID, token, true)
+				seconds := int(math.Ceil(retry.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(seconds))
+				code := domain.ErrAPIQuotaExceeded.Code
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"code":    code,
+					"message": domainerr.MessageKey(code),
+					"params": gin.H{
+						"key_id":      token,
+						"per_minute":  quota.PerMinute,
+						"per_day":     quota.PerDay,
+						"retry_after": seconds,
+					},
+				})
+				return
+			}
+		}
+
+		meter.Record(now, userID, token, false)
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// HeaderRequestID carries the request ID in both directions.
+const HeaderRequestID = "X-Request-ID"
+
+// validRequestID bounds caller-supplied IDs: they end up in every log
+// line of the request, so no control characters or unbounded length.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID gives each request an ID (the caller's X-Request-ID if well
+// formed, a new UUID otherwise), returns it in the response header and
+// puts it in the log context. First in the chain, so requests rejected
+// by later middleware are logged with it too.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = uuid.New().String()
+		}
+		c.Header(HeaderRequestID, id)
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// LogPrincipal adds the authenticated user to the log context. Installed
+// right after the authentication middleware of each route group (JWT or
+// SignedRequest), which sets "user_id".
+func LogPrincipal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID := c.GetString("user_id"); userID != "" {
+			c.Request = c.Request.WithContext(logger.WithUser(c.Request.Context(), userID))
+		}
+		c.Next()
+	}
+}
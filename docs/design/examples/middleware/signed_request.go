@@ -0,0 +1,131 @@
+// Package middleware provides HTTP middleware.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/api/middleware
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// maxSignedBodyBytes bounds the body read for hashing. Callback and
+// automation payloads are small; larger bodies are rejected before any
+// secret lookup.
+const maxSignedBodyBytes = 1 << 20
+
+// SigningKeyResolver returns the decrypted HMAC secret for a key ID and
+// the principal the request acts as (service account user ID or external
+// approval system ID). Unknown or disabled keys return repository.ErrNotFound.
+type SigningKeyResolver interface {
+	SigningSecret(ctx context.Context, keyID string) (secret []byte, principal string, err error)
+}
+
+// NonceClaimer is satisfied by repository.NonceCache.
+type NonceClaimer interface {
+	Claim(ctx context.Context, keyID, nonce string, expiresAt time.Time) (bool, error)
+}
+
+// SignedRequest authenticates webhook callbacks and automation calls
+// signed per domain/signed_request.go, and rejects replays.
+//
+// Order matters: signature and timestamp are verified before the nonce is
+// claimed, so forged or stale requests never write to the nonce cache.
+// maxSkew comes from server.signature_max_skew.
+//
+// On success "user_id" is set to the key's principal, so handlers and
+// audit logging treat the call like any other authenticated request.
+func SignedRequest(keys SigningKeyResolver, nonces NonceClaimer, maxSkew time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID := c.GetHeader(domain.HeaderSignatureKeyID)
+		ts, err := strconv.ParseInt(c.GetHeader(domain.HeaderTimestamp), 10, 64)
+		if keyID == "" || err != nil {
+			reject(c, http.StatusUnauthorized, "SIGNATURE_INVALID", domain.ErrSignatureInvalid)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBodyBytes+1))
+		if err != nil {
+			reject(c, http.StatusBadRequest, "INVALID_REQUEST", err)
+			return
+		}
+		if len(body) > maxSignedBodyBytes {
+			reject(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", errors.New("signed request body too large"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body)) // Handlers read it again
+
+		ctx := c.Request.Context()
+		secret, principal, err := keys.SigningSecret(ctx, keyID)
+		if errors.Is(err, repository.ErrNotFound) {
+			// Same response as a bad signature: do not reveal which key IDs exist
+			reject(c, http.StatusUnauthorized, "SIGNATURE_INVALID", domain.ErrSignatureInvalid)
+			return
+		}
+		if err != nil {
+			reject(c, http.StatusInternalServerError, "INTERNAL_ERROR", err)
+			return
+		}
+
+		req := &domain.SignedRequest{
+			Timestamp: ts,
+			Nonce:     c.GetHeader(domain.HeaderNonce),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.RequestURI(),
+			Body:      body,
+		}
+		switch err := req.Verify(secret, c.GetHeader(domain.HeaderSignature), maxSkew, time.Now()); {
+		case errors.Is(err, domain.ErrRequestExpired):
+			reject(c, http.StatusUnauthorized, "REQUEST_EXPIRED", err)
+			return
+		case err != nil:
+			reject(c, http.StatusUnauthorized, "SIGNATURE_INVALID", err)
+			return
+		}
+
+		fresh, err := nonces.Claim(ctx, keyID, req.Nonce, req.NonceExpiry(maxSkew))
+		if err != nil {
+			reject(c, http.StatusInternalServerError, "INTERNAL_ERROR", err)
+			return
+		}
+		if !fresh {
+			reject(c, http.StatusConflict, "REQUEST_REPLAYED", domain.ErrReplayedRequest)
+			return
+		}
+
+		c.Set("user_id", principal)
+		c.Set("signing_key_id", keyID)
+		c.Next()
+	}
+}
+
+// reject aborts with code and its message key. err is logged with the
+// key ID and nonce, never written, as in the handlers' writeError.
+func reject(c *gin.Context, status int, code string, err error) {
+	ctx := c.Request.Context()
+	fields := []zap.Field{
+		zap.String("key_id", c.GetHeader(domain.HeaderSignatureKeyID)),
+		zap.String("nonce", c.GetHeader(domain.HeaderNonce)),
+		zap.String("path", c.Request.URL.RequestURI()),
+		zap.String("code", code),
+		zap.Error(err),
+	}
+	if status >= http.StatusInternalServerError {
+		logger.ErrorCtx(ctx, "Signed request failed", fields...)
+	} else {
+		logger.WarnCtx(ctx, "Signed request rejected", fields...)
+	}
+	c.AbortWithStatusJSON(status, gin.H{"code": code, "message": domainerr.MessageKey(code)})
+}
@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Client implements API over HTTP with a platform-admin token.
+//
+//	POST /api/v1/systems                           → 201 {id} | 409
+//	GET  /api/v1/systems?name=                     → lookup after 409
+//	POST /api/v1/systems/{id}/services             → 201 {id} | 409
+//	GET  /api/v1/systems/{id}/services?name=       → lookup after 409
+//	POST /api/v1/admin/pending-adoptions           → 201 | 409 (same source + legacy_id)
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient creates a Client.
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	return &Client{baseURL: baseURL, token: token, http: httpClient}
+}
+
+// importDescription marks objects created by the tool.
+const importDescription = "Imported by shepherd-migrate"
+
+// EnsureSystem creates the System or returns the existing one's ID.
+func (c *Client) EnsureSystem(ctx context.Context, name string) (string, error) {
+	return c.ensure(ctx, "/api/v1/systems", map[string]any{"name": name, "description": importDescription}, name)
+}
+
+// EnsureService creates the Service or returns the existing one's ID.
+func (c *Client) EnsureService(ctx context.Context, systemID, name string) (string, error) {
+	return c.ensure(ctx, "/api/v1/systems/"+url.PathEscape(systemID)+"/services", map[string]any{"name": name, "description": importDescription}, name)
+}
+
+// EnsureAdoptionCandidate registers a pending adoption. An existing
+// candidate for the same legacy VM is left as is (it may already be
+// reviewed).
+func (c *Client) EnsureAdoptionCandidate(ctx context.Context, serviceID string, cand *Candidate) error {
+	body := map[string]any{
+		"source":     "LEGACY_IMPORT",
+		"service_id": serviceID,
+		"candidate":  cand,
+	}
+	status, _, err := c.do(ctx, http.MethodPost, "/api/v1/admin/pending-adoptions", body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusCreated && status != http.StatusConflict {
+		return fmt.Errorf("create pending adoption %s: HTTP %d", cand.LegacyID, status)
+	}
+	return nil
+}
+
+// ensure POSTs to collection; on 409 it looks the object up by name.
+func (c *Client) ensure(ctx context.Context, collection string, body map[string]any, name string) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	status, data, err := c.do(ctx, http.MethodPost, collection, body)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case http.StatusCreated:
+		if err := json.Unmarshal(data, &created); err != nil {
+			return "", fmt.Errorf("decode %s: %w", collection, err)
+		}
+		return created.ID, nil
+	case http.StatusConflict:
+		return c.lookup(ctx, collection, name)
+	default:
+		return "", fmt.Errorf("create %s in %s: HTTP %d: %s", name, collection, status, data)
+	}
+}
+
+func (c *Client) lookup(ctx context.Context, collection, name string) (string, error) {
+	var list struct {
+		Items []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	status, data, err := c.do(ctx, http.MethodGet, collection+"?name="+url.QueryEscape(name), nil)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("lookup %s in %s: HTTP %d", name, collection, status)
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return "", fmt.Errorf("decode %s: %w", collection, err)
+	}
+	for _, item := range list.Items {
+		if item.Name == name {
+			return item.ID, nil
+		}
+	}
+	// 409 without a match: deleted concurrently; the next run retries.
+	return "", fmt.Errorf("%s in %s: %w", name, collection, ErrAlreadyExists)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any) (int, []byte, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return 0, nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, &buf)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(resp.Body); err != nil {
+		return 0, nil, fmt.Errorf("%s %s: read body: %w", method, path, err)
+	}
+	return resp.StatusCode, out.Bytes(), nil
+}
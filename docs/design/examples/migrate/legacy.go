@@ -0,0 +1,165 @@
+// Package migrate imports inventories of legacy VM platforms (oVirt,
+// vSphere) into Shepherd: Systems, Services and adoption candidates.
+//
+// The tool only talks to the public API (no database access), so every
+// governance rule (name validation, permissions, audit) applies as for a
+// human admin. Flow:
+//
+//	Source (CSV export / platform API) → []LegacyVM
+//	Mapper (rules file)                → Plan (systems, services, candidates, skipped)
+//	Runner (--dry-run | apply)         → API calls, progress file for resume
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/migrate
+package migrate
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Platform is a legacy virtualization platform.
+type Platform string
+
+const (
+	PlatformOVirt   Platform = "ovirt"
+	PlatformVSphere Platform = "vsphere"
+)
+
+// LegacyVM is one VM of a legacy inventory, normalized across platforms.
+type LegacyVM struct {
+	Platform Platform          `json:"platform"`
+	ID       string            `json:"id"` // oVirt VM ID / vSphere instance UUID
+	Name     string            `json:"name"`
+	Folder   string            `json:"folder"` // oVirt cluster / vSphere folder path
+	CPU      int               `json:"cpu"`
+	MemoryMB int               `json:"memory_mb"`
+	DiskGB   int               `json:"disk_gb"`
+	OS       string            `json:"os"`
+	Owner    string            `json:"owner"` // Login of the owner, if recorded
+	Tags     map[string]string `json:"tags"`  // oVirt tags / vSphere tag category → tag
+}
+
+// Source reads a legacy inventory. CSVSource covers exports; API sources
+// (oVirt REST, vSphere govmomi) implement the same interface.
+type Source interface {
+	Read(ctx context.Context) ([]LegacyVM, error)
+}
+
+// csvColumns maps each platform's export headers to LegacyVM fields.
+// Headers are those of the platforms' stock inventory exports.
+var csvColumns = map[Platform]map[string]string{
+	PlatformOVirt: {
+		"id": "id", "name": "name", "cluster": "folder", "cpu": "cpu",
+		"memory_mb": "memory_mb", "disk_gb": "disk_gb", "os": "os", "owner": "owner",
+	},
+	PlatformVSphere: {
+		"instanceuuid": "id", "name": "name", "folder": "folder", "numcpu": "cpu",
+		"memorymb": "memory_mb", "provisionedspacegb": "disk_gb", "guestos": "os", "owner": "owner",
+	},
+}
+
+// tagPrefix marks tag columns: "tag:app" → Tags["app"].
+const tagPrefix = "tag:"
+
+// CSVSource reads a platform inventory export.
+type CSVSource struct {
+	Platform Platform
+	Reader   io.Reader
+}
+
+// Read parses the export. A malformed row fails the whole read: a partial
+// inventory would silently drop VMs from the migration.
+func (s *CSVSource) Read(ctx context.Context) ([]LegacyVM, error) {
+	columns, ok := csvColumns[s.Platform]
+	if !ok {
+		return nil, fmt.Errorf("platform %q: %w", s.Platform, ErrUnknownPlatform)
+	}
+
+	r := csv.NewReader(s.Reader)
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	var vms []LegacyVM
+	for line := 2; ; line++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		row, err := r.Read()
+		if err == io.EOF {
+			return vms, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		vm := LegacyVM{Platform: s.Platform, Tags: map[string]string{}}
+		for i, h := range header {
+			h = strings.ToLower(strings.TrimSpace(h))
+			v := strings.TrimSpace(row[i])
+			if tag, ok := strings.CutPrefix(h, tagPrefix); ok {
+				if v != "" {
+					vm.Tags[tag] = v
+				}
+				continue
+			}
+			if err := vm.set(columns[h], v); err != nil {
+				return nil, fmt.Errorf("line %d column %s: %w", line, h, err)
+			}
+		}
+		if vm.ID == "" || vm.Name == "" {
+			return nil, fmt.Errorf("line %d: id and name are required: %w", line, ErrInvalidInventory)
+		}
+		vms = append(vms, vm)
+	}
+}
+
+// set assigns one normalized field. Unknown columns are ignored.
+func (vm *LegacyVM) set(field, v string) error {
+	var err error
+	switch field {
+	case "id":
+		vm.ID = v
+	case "name":
+		vm.Name = v
+	case "folder":
+		vm.Folder = v
+	case "os":
+		vm.OS = v
+	case "owner":
+		vm.Owner = v
+	case "cpu":
+		vm.CPU, err = atoi(v)
+	case "memory_mb":
+		vm.MemoryMB, err = atoi(v)
+	case "disk_gb":
+		vm.DiskGB, err = atoi(v)
+	}
+	return err
+}
+
+// atoi parses an optional size; exports leave unknown sizes empty.
+// Fractional sizes (vSphere reports "40.5" GB) are rounded up.
+func atoi(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("%q: %w", v, ErrInvalidInventory)
+	}
+	return int(math.Ceil(f)), nil
+}
+
+// Errors
+var (
+	ErrUnknownPlatform  = errors.New("unknown legacy platform")
+	ErrInvalidInventory = errors.New("invalid legacy inventory")
+)
@@ -0,0 +1,174 @@
+package migrate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule derives one governance name from a legacy VM: the first non-empty
+// of Tag, folder segment FolderSegment (1-based, "/"-separated) and Fixed.
+type Rule struct {
+	Tag           string `yaml:"tag"`
+	FolderSegment int    `yaml:"folder_segment"`
+	Fixed         string `yaml:"fixed"`
+}
+
+func (r Rule) resolve(vm *LegacyVM) string {
+	if v := vm.Tags[r.Tag]; r.Tag != "" && v != "" {
+		return v
+	}
+	if r.FolderSegment > 0 {
+		segments := strings.Split(strings.Trim(vm.Folder, "/"), "/")
+		if r.FolderSegment <= len(segments) && segments[r.FolderSegment-1] != "" {
+			return segments[r.FolderSegment-1]
+		}
+	}
+	return r.Fixed
+}
+
+// Rules is the mapping file (--mapping), for example:
+//
+//	system:    {tag: app-group, folder_segment: 2}
+//	service:   {tag: app}
+//	namespace: {tag: env, fixed: legacy-test}
+//	skip_tags: {migrate: "no"}
+type Rules struct {
+	System    Rule              `yaml:"system"`
+	Service   Rule              `yaml:"service"`
+	Namespace Rule              `yaml:"namespace"` // Target namespace of the future VM
+	SkipTags  map[string]string `yaml:"skip_tags"`
+}
+
+// Candidate is a legacy VM to be registered as a pending adoption
+// (Phase 2 §7, source LEGACY_IMPORT).
+type Candidate struct {
+	Platform   Platform `json:"platform"`
+	LegacyID   string   `json:"legacy_id"`
+	LegacyName string   `json:"legacy_name"` // Original name, kept for the reviewer
+	System     string   `json:"system"`
+	Service    string   `json:"service"`
+	Instance   string   `json:"instance"`
+	Namespace  string   `json:"namespace"`
+	CPU        int      `json:"cpu"`
+	MemoryMB   int      `json:"memory_mb"`
+	DiskGB     int      `json:"disk_gb"`
+	OS         string   `json:"os,omitempty"`
+	Owner      string   `json:"owner,omitempty"`
+}
+
+// Skipped is a legacy VM that is not imported, with the reason.
+type Skipped struct {
+	LegacyID string `json:"legacy_id"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+// Plan is the outcome of mapping, printed as-is by --dry-run.
+// Systems and Services are sorted; Services are "system/service".
+type Plan struct {
+	Systems    []string          `json:"systems"`
+	Services   []string          `json:"services"`
+	Candidates []Candidate       `json:"candidates"`
+	Renamed    map[string]string `json:"renamed,omitempty"` // Original → normalized, for review
+	Skipped    []Skipped         `json:"skipped,omitempty"`
+}
+
+// Name limits (Phase 1 §2: RFC 1035, System/Service/Namespace ≤ 15).
+const (
+	maxGroupNameLen = 15
+	maxInstanceLen  = 63
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalize turns a legacy name into an RFC 1035 name of at most max
+// characters, or "" if nothing usable remains.
+func normalize(name string, max int) string {
+	n := invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	n = strings.TrimLeft(n, "-0123456789") // Must start with a letter
+	if len(n) > max {
+		n = n[:max]
+	}
+	return strings.TrimRight(n, "-")
+}
+
+// Map builds the import plan. Nothing is dropped silently: VMs that
+// cannot be mapped are listed in Skipped.
+func (r *Rules) Map(vms []LegacyVM) *Plan {
+	plan := &Plan{Renamed: map[string]string{}}
+	systems := map[string]bool{}
+	services := map[string]bool{}
+	instances := map[string]string{} // system/service/instance → legacy ID
+
+	name := func(original string, max int) string {
+		n := normalize(original, max)
+		if n != "" && n != original {
+			plan.Renamed[original] = n
+		}
+		return n
+	}
+
+	for i := range vms {
+		vm := &vms[i]
+		if reason := r.skipReason(vm); reason != "" {
+			plan.Skipped = append(plan.Skipped, Skipped{LegacyID: vm.ID, Name: vm.Name, Reason: reason})
+			continue
+		}
+
+		c := Candidate{
+			Platform:   vm.Platform,
+			LegacyID:   vm.ID,
+			LegacyName: vm.Name,
+			System:     name(r.System.resolve(vm), maxGroupNameLen),
+			Service:    name(r.Service.resolve(vm), maxGroupNameLen),
+			Instance:   name(vm.Name, maxInstanceLen),
+			Namespace:  name(r.Namespace.resolve(vm), maxGroupNameLen),
+			CPU:        vm.CPU,
+			MemoryMB:   vm.MemoryMB,
+			DiskGB:     vm.DiskGB,
+			OS:         vm.OS,
+			Owner:      vm.Owner,
+		}
+		if c.System == "" || c.Service == "" || c.Instance == "" || c.Namespace == "" {
+			plan.Skipped = append(plan.Skipped, Skipped{LegacyID: vm.ID, Name: vm.Name, Reason: "no system, service, instance or namespace after mapping"})
+			continue
+		}
+
+		key := c.System + "/" + c.Service + "/" + c.Instance
+		if other, ok := instances[key]; ok {
+			// Two legacy names normalized to the same identity
+			reason := fmt.Sprintf("identity %s already taken by legacy VM %s", key, other)
+			plan.Skipped = append(plan.Skipped, Skipped{LegacyID: vm.ID, Name: vm.Name, Reason: reason})
+			continue
+		}
+		instances[key] = vm.ID
+
+		systems[c.System] = true
+		services[c.System+"/"+c.Service] = true
+		plan.Candidates = append(plan.Candidates, c)
+	}
+
+	plan.Systems = sortedKeys(systems)
+	plan.Services = sortedKeys(services)
+	return plan
+}
+
+func (r *Rules) skipReason(vm *LegacyVM) string {
+	for k, v := range r.SkipTags {
+		if vm.Tags[k] == v {
+			return fmt.Sprintf("tag %s=%s", k, v)
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
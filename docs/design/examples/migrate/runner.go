@@ -0,0 +1,180 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// API is the subset of the Shepherd API the import drives. Every call is
+// idempotent: an existing object (409) is looked up and returned, so a
+// run interrupted between the API call and the progress write is safe
+// to resume.
+type API interface {
+	EnsureSystem(ctx context.Context, name string) (id string, err error)
+	EnsureService(ctx context.Context, systemID, name string) (id string, err error)
+	EnsureAdoptionCandidate(ctx context.Context, serviceID string, c *Candidate) error
+}
+
+// Progress records completed steps in a JSON file so a later run resumes
+// where the previous one stopped. Keys are "system/<name>",
+// "service/<system>/<service>" and "candidate/<platform>/<legacy id>";
+// values are the created object's ID.
+type Progress struct {
+	path string
+	Done map[string]string `json:"done"`
+}
+
+// LoadProgress reads path, or starts empty if it does not exist yet.
+func LoadProgress(path string) (*Progress, error) {
+	p := &Progress{path: path, Done: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read progress: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parse progress %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// mark records a step and writes the file (temp file + rename, so a crash
+// never leaves a truncated progress file).
+func (p *Progress) mark(key, id string) error {
+	p.Done[key] = id
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), ".shepherd-migrate-*")
+	if err != nil {
+		return fmt.Errorf("write progress: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write progress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write progress: %w", err)
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
+
+// Failure is a step that failed in this run; it is retried by the next one.
+type Failure struct {
+	Step  string `json:"step"`
+	Error string `json:"error"`
+}
+
+// Report summarizes a run.
+type Report struct {
+	Done    int       `json:"done"`    // Completed in this run
+	Resumed int       `json:"resumed"` // Already completed by a previous run
+	Failed  []Failure `json:"failed,omitempty"`
+}
+
+// Runner applies a Plan through the API.
+type Runner struct {
+	api      API
+	progress *Progress
+	logger   *zap.Logger
+}
+
+// NewRunner creates a Runner.
+func NewRunner(api API, progress *Progress, logger *zap.Logger) *Runner {
+	return &Runner{api: api, progress: progress, logger: logger}
+}
+
+// Run creates Systems, then Services, then adoption candidates. A failed
+// step does not stop the run; its dependents fail with it and everything
+// is retried on the next run. Only progress-file errors abort, since
+// continuing without recording progress would make resume unreliable.
+func (r *Runner) Run(ctx context.Context, plan *Plan) (*Report, error) {
+	report := &Report{}
+
+	step := func(key string, do func() (string, error)) (string, error) {
+		if id, ok := r.progress.Done[key]; ok {
+			report.Resumed++
+			return id, nil
+		}
+		id, err := do()
+		if err != nil {
+			r.logger.Warn("Migration step failed", zap.String("step", key), zap.Error(err))
+			report.Failed = append(report.Failed, Failure{Step: key, Error: err.Error()})
+			return "", err
+		}
+		if err := r.progress.mark(key, id); err != nil {
+			return "", fmt.Errorf("%s: %w", key, ErrProgressLost)
+		}
+		report.Done++
+		return id, nil
+	}
+
+	systemIDs := map[string]string{}
+	for _, name := range plan.Systems {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		id, err := step("system/"+name, func() (string, error) {
+			return r.api.EnsureSystem(ctx, name)
+		})
+		if errors.Is(err, ErrProgressLost) {
+			return report, err
+		}
+		systemIDs[name] = id
+	}
+
+	serviceIDs := map[string]string{}
+	for _, key := range plan.Services {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		system, service, _ := strings.Cut(key, "/")
+		id, err := step("service/"+key, func() (string, error) {
+			if systemIDs[system] == "" {
+				return "", fmt.Errorf("system %s: %w", system, ErrDependencyFailed)
+			}
+			return r.api.EnsureService(ctx, systemIDs[system], service)
+		})
+		if errors.Is(err, ErrProgressLost) {
+			return report, err
+		}
+		serviceIDs[key] = id
+	}
+
+	for i := range plan.Candidates {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		c := &plan.Candidates[i]
+		_, err := step(fmt.Sprintf("candidate/%s/%s", c.Platform, c.LegacyID), func() (string, error) {
+			serviceID := serviceIDs[c.System+"/"+c.Service]
+			if serviceID == "" {
+				return "", fmt.Errorf("service %s/%s: %w", c.System, c.Service, ErrDependencyFailed)
+			}
+			return c.LegacyID, r.api.EnsureAdoptionCandidate(ctx, serviceID, c)
+		})
+		if errors.Is(err, ErrProgressLost) {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// Errors
+var (
+	ErrDependencyFailed = errors.New("parent step failed")
+	ErrProgressLost     = errors.New("progress file not writable, aborting")
+	ErrAlreadyExists    = errors.New("already exists")
+)
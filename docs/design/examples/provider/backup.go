@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// BackupProvider delegates VM backups to an external backup system. One
+// is configured per installation (backup.provider), not per cluster:
+//
+//	velero    velero.io/v1 Backup in the cluster's Velero namespace,
+//	          includedNamespaces = the VM's namespace, labelSelector =
+//	          the VM's ownership labels (KubeVirt Velero plugin)
+//	kasten    actions.kio.kasten.io/v1alpha1 BackupAction on the VM,
+//	          restore point name as ExternalID
+//	webhook   WebhookBackupProvider: any system behind a small HTTP API
+//
+// Restores go back through the provider that took the backup (a velero
+// Restore, a Kasten RestoreAction of the restore point). Backups and
+// restores are addressed by platform-chosen names, so asking for one
+// twice is harmless.
+type BackupProvider interface {
+	// Name is the configured provider name, recorded in the payload and
+	// on restore points.
+	Name() string
+
+	// StartBackup asks the backup system to back up target. Returns the
+	// backup as started (typically in progress); ErrAlreadyExists if a
+	// backup of that name exists.
+	StartBackup(ctx context.Context, target domain.BackupTarget) (*domain.ExternalBackup, error)
+
+	// GetBackup returns the backup's current state; ErrNotFound if it was
+	// never started.
+	GetBackup(ctx context.Context, cluster, backupName string) (*domain.ExternalBackup, error)
+
+	// StartRestore asks the backup system to restore target.BackupName
+	// over the VM's disks; the VM is stopped. ErrAlreadyExists if a
+	// restore of that name exists.
+	StartRestore(ctx context.Context, target domain.RestoreTarget) (*domain.ExternalRestore, error)
+
+	// GetRestore returns the restore's current state; ErrNotFound if it
+	// was never started.
+	GetRestore(ctx context.Context, cluster, restoreName string) (*domain.ExternalRestore, error)
+}
+
+// WebhookBackupProvider calls a backup system's HTTP API:
+//
+//	POST {url}/backups                        body: domain.BackupTarget → 201/202 + ExternalBackup, 409 if the name exists
+//	GET  {url}/backups/{name}?cluster={name}                           → 200 + ExternalBackup, 404 if unknown
+//	POST {url}/restores                       body: domain.RestoreTarget → 201/202 + ExternalRestore, 409 if the name exists
+//	GET  {url}/restores/{name}?cluster={name}                          → 200 + ExternalRestore, 404 if unknown
+//
+// Requests are signed with the X-Shepherd-* headers (domain/signed_request.go),
+// so the receiver can verify them the same way Shepherd verifies callbacks.
+type WebhookBackupProvider struct {
+	baseURL string
+	keyID   string
+	secret  []byte
+	http    *http.Client
+}
+
+// NewWebhookBackupProvider creates a webhook provider. keyID and secret
+// identify Shepherd to the receiver.
+func NewWebhookBackupProvider(baseURL, keyID string, secret []byte, httpClient *http.Client) *WebhookBackupProvider {
+	return &WebhookBackupProvider{baseURL: baseURL, keyID: keyID, secret: secret, http: httpClient}
+}
+
+// Name returns "webhook".
+func (p *WebhookBackupProvider) Name() string { return "webhook" }
+
+// StartBackup posts the target.
+func (p *WebhookBackupProvider) StartBackup(ctx context.Context, target domain.BackupTarget) (*domain.ExternalBackup, error) {
+	body, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	status, resp, err := p.do(ctx, http.MethodPost, "/backups", body)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusCreated, http.StatusAccepted, http.StatusOK:
+		return decodeBackup(resp)
+	case http.StatusConflict:
+		return nil, fmt.Errorf("backup %s: %w", target.BackupName, ErrAlreadyExists)
+	}
+	return nil, fmt.Errorf("start backup %s: status %d: %s", target.BackupName, status, resp)
+}
+
+// GetBackup reads one backup.
+func (p *WebhookBackupProvider) GetBackup(ctx context.Context, cluster, backupName string) (*domain.ExternalBackup, error) {
+	path := "/backups/" + url.PathEscape(backupName) + "?cluster=" + url.QueryEscape(cluster)
+	status, resp, err := p.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		return decodeBackup(resp)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("backup %s: %w", backupName, ErrNotFound)
+	}
+	return nil, fmt.Errorf("get backup %s: status %d: %s", backupName, status, resp)
+}
+
+// StartRestore posts the target.
+func (p *WebhookBackupProvider) StartRestore(ctx context.Context, target domain.RestoreTarget) (*domain.ExternalRestore, error) {
+	body, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	status, resp, err := p.do(ctx, http.MethodPost, "/restores", body)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusCreated, http.StatusAccepted, http.StatusOK:
+		return decodeRestore(resp)
+	case http.StatusConflict:
+		return nil, fmt.Errorf("restore %s: %w", target.RestoreName, ErrAlreadyExists)
+	}
+	return nil, fmt.Errorf("start restore %s: status %d: %s", target.RestoreName, status, resp)
+}
+
+// GetRestore reads one restore.
+func (p *WebhookBackupProvider) GetRestore(ctx context.Context, cluster, restoreName string) (*domain.ExternalRestore, error) {
+	path := "/restores/" + url.PathEscape(restoreName) + "?cluster=" + url.QueryEscape(cluster)
+	status, resp, err := p.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		return decodeRestore(resp)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("restore %s: %w", restoreName, ErrNotFound)
+	}
+	return nil, fmt.Errorf("get restore %s: status %d: %s", restoreName, status, resp)
+}
+
+func (p *WebhookBackupProvider) do(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, nil, err
+	}
+	signed := domain.SignedRequest{
+		Timestamp: time.Now().Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+		Method:    method,
+		Path:      path,
+		Body:      body,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(domain.HeaderSignatureKeyID, p.keyID)
+	req.Header.Set(domain.HeaderTimestamp, strconv.FormatInt(signed.Timestamp, 10))
+	req.Header.Set(domain.HeaderNonce, signed.Nonce)
+	req.Header.Set(domain.HeaderSignature, signed.Sign(p.secret))
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(resp.Body); err != nil {
+		return 0, nil, fmt.Errorf("%s %s: read body: %w", method, path, err)
+	}
+	return resp.StatusCode, out.Bytes(), nil
+}
+
+func decodeBackup(data []byte) (*domain.ExternalBackup, error) {
+	var b domain.ExternalBackup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("decode backup: %w", err)
+	}
+	return &b, nil
+}
+
+func decodeRestore(data []byte) (*domain.ExternalRestore, error) {
+	var r domain.ExternalRestore
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decode restore: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// CapabilityDetector detects KubeVirt/CDI versions, feature gates and node
+// hardware (ADR-0014).
+type CapabilityDetector interface {
+	Detect(ctx context.Context, cluster string) (*domain.ClusterCapabilities, error)
+}
+
+// ClusterInfoReader reads raw version data from a cluster.
+// Implemented by the KubeVirt provider on top of the cluster's clientsets.
+type ClusterInfoReader interface {
+	// KubeVirtVersion returns the version from ServerVersion().Get().
+	KubeVirtVersion(ctx context.Context, cluster string) (string, error)
+
+	// CDIVersion returns the CDI CR status.observedVersion; "" if CDI is not installed.
+	CDIVersion(ctx context.Context, cluster string) (string, error)
+
+	// FeatureGates returns the KubeVirt CR
+	// spec.configuration.developerConfiguration.featureGates.
+	FeatureGates(ctx context.Context, cluster string) ([]string, error)
+
+	// NodeHardware returns what the schedulable nodes offer: KubeVirt CR
+	// permittedHostDevices with a node allocatable, allocatable
+	// hugepages-* sizes, NetworkAttachmentDefinitions with an SR-IOV
+	// resourceName annotation, and whether a node has cpumanager=true.
+	NodeHardware(ctx context.Context, cluster string) (*domain.ClusterHardware, error)
+}
+
+// KubeVirtCapabilityDetector is the default CapabilityDetector.
+type KubeVirtCapabilityDetector struct {
+	reader ClusterInfoReader
+}
+
+// NewKubeVirtCapabilityDetector creates a new detector.
+func NewKubeVirtCapabilityDetector(reader ClusterInfoReader) *KubeVirtCapabilityDetector {
+	return &KubeVirtCapabilityDetector{reader: reader}
+}
+
+// Detect reads raw cluster data and evaluates it against the GA table.
+// Version skew is reported in Warnings, never as an error: an old cluster
+// keeps serving the features it supports.
+func (d *KubeVirtCapabilityDetector) Detect(ctx context.Context, cluster string) (*domain.ClusterCapabilities, error) {
+	kubevirtVersion, err := d.reader.KubeVirtVersion(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get kubevirt version: %w", err)
+	}
+
+	cdiVersion, err := d.reader.CDIVersion(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get cdi version: %w", err)
+	}
+
+	gates, err := d.reader.FeatureGates(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get feature gates: %w", err)
+	}
+
+	caps := domain.NewClusterCapabilities(kubevirtVersion, cdiVersion, gates, time.Now())
+
+	// Without hardware the cluster only hosts sizes needing none
+	hw, err := d.reader.NodeHardware(ctx, cluster)
+	if err != nil {
+		caps.Warnings = append(caps.Warnings, fmt.Sprintf("node hardware not detected: %v", err))
+		return caps, nil
+	}
+	caps.Hardware = hw
+	return caps, nil
+}
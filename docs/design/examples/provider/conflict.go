@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// UpdateWithPrecondition applies spec only if the VM has not changed in
+// ways Shepherd cares about since base was read (see domain/conflict.go).
+//
+// On ErrConflict the VM is re-read:
+//   - managed fields drifted → *domain.ConflictError (user refreshes and
+//     resubmits; never retried automatically)
+//   - only unmanaged fields changed → retry on the fresh resourceVersion,
+//     at most domain.MaxConflictRetries times
+//
+// base is the VM as the user saw it when requesting the change; its
+// ResourceVersion is the initial precondition.
+func UpdateWithPrecondition(ctx context.Context, p InfrastructureProvider, cluster, namespace, name string, base *domain.VM, spec *domain.VMSpec) (*domain.VM, error) {
+	s := *spec // Caller's spec is not modified
+	s.ExpectedResourceVersion = base.ResourceVersion
+
+	for attempt := 1; ; attempt++ {
+		vm, err := p.UpdateVM(ctx, cluster, namespace, name, &s)
+		if !errors.Is(err, ErrConflict) {
+			return vm, err
+		}
+
+		current, err := p.GetVM(ctx, cluster, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("refresh after conflict: %w", err)
+		}
+		if fields := domain.ManagedFieldDrift(base, current); len(fields) > 0 {
+			return nil, &domain.ConflictError{
+				Cluster:         cluster,
+				Namespace:       namespace,
+				Name:            name,
+				ExpectedVersion: base.ResourceVersion,
+				CurrentVersion:  current.ResourceVersion,
+				Fields:          fields,
+			}
+		}
+		if attempt >= domain.MaxConflictRetries {
+			// Object is busy (e.g. status churn); let the job retry later
+			return nil, fmt.Errorf("update %s/%s after %d attempts: %w", namespace, name, attempt, ErrConflict)
+		}
+		s.ExpectedResourceVersion = current.ResourceVersion
+	}
+}
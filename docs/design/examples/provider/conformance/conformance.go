@@ -0,0 +1,269 @@
+// Package conformance is the behavior contract every InfrastructureProvider
+// implementation (KubeVirt, mock, libvirt, ...) must satisfy.
+// Behaviors of optional capabilities (ADR-0024) are skipped when the
+// provider does not implement them.
+//
+// It is a library, not a _test.go file, so each implementation runs the
+// same table from its own tests:
+//
+//	// internal/provider/conformance/conformance_test.go (runs in CI)
+//	func TestMockProviderConformance(t *testing.T) {
+//	    conformance.Run(t, func(t *testing.T) conformance.Target {
+//	        return conformance.Target{Provider: provider.NewMockProvider(), Cluster: "mock", Namespace: "conformance"}
+//	    })
+//	}
+//
+//	// internal/provider/kubevirt_envtest_test.go (//go:build envtest, optional)
+//	// Same call with a provider backed by envtest + KubeVirt CRDs.
+//
+// Behaviors only use the public interface; implementation details
+// (caching, informers) are out of scope.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/provider/conformance
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+)
+
+// Target is one freshly initialized provider under test.
+// Namespace MUST be empty when returned; the suite creates what it needs.
+type Target struct {
+	Provider  provider.InfrastructureProvider
+	Cluster   string
+	Namespace string
+}
+
+// Factory returns a new Target per behavior, isolating behaviors from each other.
+type Factory func(t *testing.T) Target
+
+// behavior is one row of the conformance table.
+type behavior struct {
+	name string
+	run  func(t *testing.T, tgt Target)
+}
+
+// missing is a name no behavior ever creates.
+const missing = "conformance-missing"
+
+var behaviors = []behavior{
+	// Errors for missing resources: every lookup/mutation by name wraps ErrNotFound
+	{"GetVM/missing", func(t *testing.T, tgt Target) {
+		_, err := tgt.Provider.GetVM(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"UpdateVM/missing", func(t *testing.T, tgt Target) {
+		_, err := tgt.Provider.UpdateVM(context.Background(), tgt.Cluster, tgt.Namespace, missing, newSpec())
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"DeleteVM/missing", func(t *testing.T, tgt Target) {
+		err := tgt.Provider.DeleteVM(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"StartVM/missing", func(t *testing.T, tgt Target) {
+		err := tgt.Provider.StartVM(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"StopVM/missing", func(t *testing.T, tgt Target) {
+		err := tgt.Provider.StopVM(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"GetSnapshot/missing", func(t *testing.T, tgt Target) {
+		p := requireCapability[provider.SnapshotProvider](t, tgt)
+		_, err := p.GetSnapshot(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"GetClone/missing", func(t *testing.T, tgt Target) {
+		p := requireCapability[provider.CloneProvider](t, tgt)
+		_, err := p.GetClone(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"GetMigration/missing", func(t *testing.T, tgt Target) {
+		p := requireCapability[provider.MigrationProvider](t, tgt)
+		_, err := p.GetMigration(context.Background(), tgt.Cluster, tgt.Namespace, missing)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+
+	// Lifecycle round trip
+	{"CreateVM/roundtrip", func(t *testing.T, tgt Target) {
+		ctx := context.Background()
+		spec := newSpec()
+		created, err := tgt.Provider.CreateVM(ctx, tgt.Cluster, tgt.Namespace, spec)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Name)
+
+		got, err := tgt.Provider.GetVM(ctx, tgt.Cluster, tgt.Namespace, created.Name)
+		require.NoError(t, err)
+		require.Equal(t, tgt.Cluster, got.Cluster)
+		require.Equal(t, tgt.Namespace, got.Namespace)
+		require.Equal(t, spec.CPU, got.CPU)
+		require.Equal(t, spec.MemoryMB, got.MemoryMB)
+		require.Equal(t, spec.ServiceID, got.ServiceID)
+	}},
+	{"CreateVM/platform-labels", func(t *testing.T, tgt Target) {
+		created, err := tgt.Provider.CreateVM(context.Background(), tgt.Cluster, tgt.Namespace, newSpec())
+		require.NoError(t, err)
+		// Labels are platform-managed (ADR-0015 §4), never taken from the spec
+		require.Equal(t, domain.ManagedByValue, created.Labels[domain.LabelManagedBy])
+	}},
+	{"CreateVM/ownership-annotations", func(t *testing.T, tgt Target) {
+		ctx := context.Background()
+		spec := newSpec()
+		created, err := tgt.Provider.CreateVM(ctx, tgt.Cluster, tgt.Namespace, spec)
+		require.NoError(t, err)
+
+		// Registry ownership checks depend on these surviving a GET
+		got, err := tgt.Provider.GetVM(ctx, tgt.Cluster, tgt.Namespace, created.Name)
+		require.NoError(t, err)
+		require.NoError(t, domain.VerifyOwnership(&spec.Ownership, domain.OwnershipFromAnnotations(got.Annotations)))
+	}},
+	{"UpdateVM/stale-resource-version", func(t *testing.T, tgt Target) {
+		ctx := context.Background()
+		created, err := tgt.Provider.CreateVM(ctx, tgt.Cluster, tgt.Namespace, newSpec())
+		require.NoError(t, err)
+		require.NotEmpty(t, created.ResourceVersion)
+
+		first := newSpec()
+		first.ExpectedResourceVersion = created.ResourceVersion
+		first.CPU = 4
+		_, err = tgt.Provider.UpdateVM(ctx, tgt.Cluster, tgt.Namespace, created.Name, first)
+		require.NoError(t, err)
+
+		// Same (now stale) version must not overwrite the first update
+		stale := newSpec()
+		stale.ExpectedResourceVersion = created.ResourceVersion
+		_, err = tgt.Provider.UpdateVM(ctx, tgt.Cluster, tgt.Namespace, created.Name, stale)
+		require.ErrorIs(t, err, provider.ErrConflict)
+	}},
+	{"DeleteVM/then-get", func(t *testing.T, tgt Target) {
+		ctx := context.Background()
+		created, err := tgt.Provider.CreateVM(ctx, tgt.Cluster, tgt.Namespace, newSpec())
+		require.NoError(t, err)
+		require.NoError(t, tgt.Provider.DeleteVM(ctx, tgt.Cluster, tgt.Namespace, created.Name))
+
+		_, err = tgt.Provider.GetVM(ctx, tgt.Cluster, tgt.Namespace, created.Name)
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	}},
+	{"ValidateSpec/no-side-effects", func(t *testing.T, tgt Target) {
+		ctx := context.Background()
+		_, err := tgt.Provider.ValidateSpec(ctx, tgt.Cluster, tgt.Namespace, newSpec())
+		require.NoError(t, err)
+
+		list, err := tgt.Provider.ListVMs(ctx, tgt.Cluster, tgt.Namespace, provider.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, list.Items, "dry run must not create resources (ADR-0011)")
+	}},
+
+	// Pagination semantics
+	{"ListVMs/empty", func(t *testing.T, tgt Target) {
+		list, err := tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace, provider.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+		require.Empty(t, list.Continue)
+	}},
+	{"ListVMs/no-limit-returns-all", func(t *testing.T, tgt Target) {
+		names := seedVMs(t, tgt, 3)
+		list, err := tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace, provider.ListOptions{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, names, vmNames(list.Items))
+		require.Empty(t, list.Continue)
+	}},
+	{"ListVMs/pages-cover-all-once", func(t *testing.T, tgt Target) {
+		names := seedVMs(t, tgt, 5)
+		var seen []string
+		opts := provider.ListOptions{Limit: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 10, "pagination does not terminate")
+			list, err := tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace, opts)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(list.Items), opts.Limit)
+			seen = append(seen, vmNames(list.Items)...)
+			if list.Continue == "" {
+				break
+			}
+			opts.Continue = list.Continue
+		}
+		// No VM skipped, none returned twice
+		require.ElementsMatch(t, names, seen)
+	}},
+	{"ListVMs/invalid-continue", func(t *testing.T, tgt Target) {
+		_, err := tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace,
+			provider.ListOptions{Limit: 1, Continue: "not-a-token"})
+		require.ErrorIs(t, err, provider.ErrInvalidContinue)
+	}},
+	{"ListVMs/label-selector", func(t *testing.T, tgt Target) {
+		names := seedVMs(t, tgt, 2)
+		list, err := tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace,
+			provider.ListOptions{LabelSelector: domain.ManagedSelector()})
+		require.NoError(t, err)
+		require.ElementsMatch(t, names, vmNames(list.Items))
+
+		list, err = tgt.Provider.ListVMs(context.Background(), tgt.Cluster, tgt.Namespace,
+			provider.ListOptions{LabelSelector: domain.LabelManagedBy + "=someone-else"})
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+	}},
+}
+
+// Run executes every behavior as a subtest against a fresh Target.
+func Run(t *testing.T, newTarget Factory) {
+	t.Helper()
+	for _, b := range behaviors {
+		b := b
+		t.Run(b.name, func(t *testing.T) {
+			b.run(t, newTarget(t))
+		})
+	}
+}
+
+// requireCapability skips the behavior when the provider lacks a capability.
+func requireCapability[T any](t *testing.T, tgt Target) T {
+	t.Helper()
+	p, ok := tgt.Provider.(T)
+	if !ok {
+		t.Skipf("provider %s does not implement %T", tgt.Provider.Type(), (*T)(nil))
+	}
+	return p
+}
+
+func newSpec() *domain.VMSpec {
+	return &domain.VMSpec{
+		CPU:       2,
+		MemoryMB:  2048,
+		Template:  "conformance",
+		ServiceID: "conformance-service",
+		Ownership: domain.Ownership{
+			InstallationID: "conformance",
+			SystemID:       "conformance-system",
+			ServiceID:      "conformance-service",
+			RequestedBy:    "conformance",
+			TicketID:       "conformance-ticket",
+		},
+	}
+}
+
+func seedVMs(t *testing.T, tgt Target, n int) []string {
+	t.Helper()
+	names := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		vm, err := tgt.Provider.CreateVM(context.Background(), tgt.Cluster, tgt.Namespace, newSpec())
+		require.NoError(t, err, fmt.Sprintf("seed vm %d", i))
+		names = append(names, vm.Name)
+	}
+	return names
+}
+
+func vmNames(vms []*domain.VM) []string {
+	names := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		names = append(names, vm.Name)
+	}
+	return names
+}
@@ -0,0 +1,14 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/provider/conformance"
+)
+
+func TestMockProviderConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Target {
+		return conformance.Target{Provider: provider.NewMockProvider(), Cluster: "mock", Namespace: "conformance"}
+	})
+}
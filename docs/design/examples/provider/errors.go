@@ -0,0 +1,40 @@
+package provider
+
+import "errors"
+
+// Provider errors. Implementations MUST wrap these (fmt.Errorf("...: %w", ErrNotFound))
+// instead of leaking backend errors (e.g., apierrors.IsNotFound), so callers
+// and the conformance suite can use errors.Is regardless of the backend.
+var (
+	// ErrNotFound is returned when the addressed resource does not exist.
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrAlreadyExists is returned when creating a resource whose name is taken.
+	ErrAlreadyExists = errors.New("resource already exists")
+
+	// ErrConflict is returned when an update precondition
+	// (VMSpec.ExpectedResourceVersion) no longer matches the object.
+	ErrConflict = errors.New("resource version conflict")
+
+	// ErrForbidden is returned when the platform's service account lacks
+	// the RBAC permission for a call (e.g. creating a namespace, ADR-0017).
+	ErrForbidden = errors.New("forbidden by cluster RBAC")
+
+	// ErrIncompatibleSchema is returned by the mapper when a cluster object
+	// lacks fields the domain model cannot do without (e.g. name).
+	ErrIncompatibleSchema = errors.New("incompatible resource schema")
+
+	// ErrInvalidContinue is returned when a ListOptions.Continue token
+	// is malformed or has expired.
+	ErrInvalidContinue = errors.New("invalid or expired continue token")
+
+	// ErrCapabilityUnsupported is returned when a cluster's provider does not
+	// implement an optional capability (e.g., snapshots on a libvirt host).
+	ErrCapabilityUnsupported = errors.New("capability not supported by provider")
+
+	// ErrUnknownProviderType is returned when no factory is registered for a provider type.
+	ErrUnknownProviderType = errors.New("unknown provider type")
+
+	// ErrClusterNotAttached is returned when no provider is attached to a cluster.
+	ErrClusterNotAttached = errors.New("no provider attached to cluster")
+)
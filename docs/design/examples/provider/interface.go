@@ -14,6 +14,9 @@ import (
 
 // InfrastructureProvider is the base interface for all infrastructure providers.
 // Supports VM lifecycle, snapshots, clones, and migrations.
+//
+// Non-KubeVirt providers (e.g., libvirt, cloud VMs) implement this interface
+// plus whichever capability interfaces they support; see Registry.
 type InfrastructureProvider interface {
 	// Metadata
 	Name() string
@@ -62,12 +65,49 @@ type MigrationProvider interface {
 	CancelMigration(ctx context.Context, cluster, namespace, name string) error
 }
 
+// RelocationProvider moves stopped VMs between clusters (cold migration,
+// domain/vm_relocation.go). Disks travel through a VirtualMachineExport on
+// the source cluster and CDI DataVolumes importing from it on the target.
+// Create methods are idempotent: called again with the same name they
+// return the existing object, so a retried step does not copy twice.
+type RelocationProvider interface {
+	// ExportVM exports the stopped VM's disks; Ready once they are served.
+	ExportVM(ctx context.Context, cluster, namespace, vmName, exportName string) (*domain.VMExport, error)
+	DeleteExport(ctx context.Context, cluster, namespace, exportName string) error
+
+	// GetDefinition returns the VM's definition to recreate it elsewhere:
+	// the VirtualMachine without status, resourceVersion and UID, labels
+	// and annotations kept.
+	GetDefinition(ctx context.Context, cluster, namespace, name string) ([]byte, error)
+
+	// ImportDisk creates the DataVolume name importing vol from export;
+	// called again it reports the import's current phase and progress.
+	ImportDisk(ctx context.Context, cluster, namespace, name string, export *domain.VMExport, vol domain.ExportedVolume) (*domain.DiskImport, error)
+	DeleteDiskImport(ctx context.Context, cluster, namespace, name string) error
+
+	// CreateFromDefinition creates the VM stopped from definition, with
+	// each claim replaced by its DataVolume (claim name → DataVolume name).
+	CreateFromDefinition(ctx context.Context, cluster, namespace string, definition []byte, disks map[string]string) (*domain.VM, error)
+}
+
 // InstanceTypeProvider provides instance type and preference capabilities.
+//
+// Write methods are scoped by the object's Namespace: empty targets the
+// cluster-scoped kind (VirtualMachineClusterInstancetype/Preference),
+// non-empty the namespaced kind. Delete takes namespace with the same rule.
 type InstanceTypeProvider interface {
 	ListInstanceTypes(ctx context.Context, cluster, namespace string) ([]*domain.InstanceType, error)
 	ListClusterInstanceTypes(ctx context.Context, cluster string) ([]*domain.InstanceType, error)
 	ListPreferences(ctx context.Context, cluster, namespace string) ([]*domain.Preference, error)
 	ListClusterPreferences(ctx context.Context, cluster string) ([]*domain.Preference, error)
+
+	CreateInstanceType(ctx context.Context, cluster string, it *domain.InstanceType) (*domain.InstanceType, error)
+	UpdateInstanceType(ctx context.Context, cluster string, it *domain.InstanceType) (*domain.InstanceType, error)
+	DeleteInstanceType(ctx context.Context, cluster, namespace, name string) error
+
+	CreatePreference(ctx context.Context, cluster string, p *domain.Preference) (*domain.Preference, error)
+	UpdatePreference(ctx context.Context, cluster string, p *domain.Preference) (*domain.Preference, error)
+	DeletePreference(ctx context.Context, cluster, namespace, name string) error
 }
 
 // ConsoleProvider provides console access capabilities.
@@ -76,6 +116,51 @@ type ConsoleProvider interface {
 	GetSerialConsole(ctx context.Context, cluster, namespace, name string) (*domain.ConsoleConnection, error)
 }
 
+// NodeProvider provides node maintenance capabilities.
+// Used by node drain coordination; the platform never deletes nodes.
+type NodeProvider interface {
+	CordonNode(ctx context.Context, cluster, nodeName string) error
+	UncordonNode(ctx context.Context, cluster, nodeName string) error
+}
+
+// MetadataProvider applies System-owned labels/annotations in bulk.
+// Implementations plan each object's patch with domain.PlanMetadataPatch,
+// so keys set by others are never touched.
+type MetadataProvider interface {
+	// ApplyVMMetadata patches every VM matching selector, across namespaces.
+	// Returns the number of VMs actually changed.
+	ApplyVMMetadata(ctx context.Context, cluster, selector string, desired domain.ManagedMetadata) (int, error)
+
+	// ApplyNamespaceMetadata patches one namespace.
+	ApplyNamespaceMetadata(ctx context.Context, cluster, namespace string, desired domain.ManagedMetadata) error
+}
+
+// DiagnosticsProvider collects triage data for a VM that failed to
+// provision. Read-only; partial results are returned with Errors set
+// rather than failing the whole collection.
+type DiagnosticsProvider interface {
+	CollectDiagnostics(ctx context.Context, cluster, namespace, name string) (*domain.DiagnosticsBundle, error)
+}
+
+// NamespaceProvider provisions Shepherd namespaces (ADR-0017 JIT
+// creation) and the governed objects of their baseline
+// (domain/namespace_baseline.go). Only objects carrying the managed-by
+// label are listed or deleted. Returns ErrForbidden when the platform's
+// service account may not create the namespace or object.
+type NamespaceProvider interface {
+	// EnsureNamespace creates the namespace if missing; an existing one is
+	// left as is.
+	EnsureNamespace(ctx context.Context, cluster, namespace string, labels map[string]string) error
+
+	ListGovernedObjects(ctx context.Context, cluster, namespace string) ([]*domain.GovernedObject, error)
+
+	// ApplyGovernedObject creates or replaces the object (server-side
+	// apply, field manager "shepherd").
+	ApplyGovernedObject(ctx context.Context, cluster string, obj *domain.GovernedObject) error
+
+	DeleteGovernedObject(ctx context.Context, cluster, namespace string, kind domain.BaselineKind, name string) error
+}
+
 // KubeVirtProvider is the combined interface for KubeVirt operations.
 // Embeds all capability interfaces.
 type KubeVirtProvider interface {
@@ -85,6 +170,10 @@ type KubeVirtProvider interface {
 	MigrationProvider
 	InstanceTypeProvider
 	ConsoleProvider
+	NodeProvider
+	MetadataProvider
+	DiagnosticsProvider
+	NamespaceProvider
 }
 
 // ListOptions contains options for list operations.
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// Mapper translates KubeVirt objects into domain types (Anti-Corruption
+// Layer, see domain/vm.go). Only the fields the domain needs are read, and
+// every optional field is nil-checked: a missing field yields a zero value,
+// never a panic.
+type Mapper struct{}
+
+// NewMapper creates a new Mapper.
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// MapVM maps a VirtualMachine and, while it runs, its VMI. vmi may be nil.
+// claims holds the PVCs referenced by the VM's volumes, keyed by claim
+// name; the provider reads them from the same informer cache. A missing
+// claim (not yet created by CDI) falls back to the DataVolumeTemplate.
+func (m *Mapper) MapVM(vm *kubevirtv1.VirtualMachine, vmi *kubevirtv1.VirtualMachineInstance, claims map[string]*corev1.PersistentVolumeClaim) (*domain.VM, error) {
+	// Critical fields must exist
+	if vm == nil || vm.Name == "" || vm.Namespace == "" {
+		return nil, fmt.Errorf("map vm: %w", ErrIncompatibleSchema)
+	}
+
+	out := &domain.VM{
+		Name:            vm.Name,
+		Namespace:       vm.Namespace,
+		ResourceVersion: vm.ResourceVersion,
+		Labels:          vm.Labels,
+		Annotations:     vm.Annotations,
+		CreatedAt:       vm.CreationTimestamp.Time,
+	}
+	if vm.Spec.Template == nil {
+		return out, nil
+	}
+
+	out.Volumes = m.mapVolumes(vm, claims)
+	out.Interfaces = m.mapInterfaces(vm, vmi)
+	out.GPUs = m.mapGPUs(vm)
+	out.IP = domain.PrimaryIP(out.Interfaces)
+	if vmi != nil {
+		out.NodeName = vmi.Status.NodeName
+	}
+	return out, nil
+}
+
+// mapVolumes joins spec.template.spec.volumes with the disk devices of the
+// same name. Volumes without a disk device (e.g. filesystems) keep an
+// empty Bus.
+func (m *Mapper) mapVolumes(vm *kubevirtv1.VirtualMachine, claims map[string]*corev1.PersistentVolumeClaim) []domain.Volume {
+	spec := vm.Spec.Template.Spec
+	disks := make(map[string]kubevirtv1.Disk, len(spec.Domain.Devices.Disks))
+	for _, d := range spec.Domain.Devices.Disks {
+		disks[d.Name] = d
+	}
+	templates := make(map[string]kubevirtv1.DataVolumeTemplateSpec, len(vm.Spec.DataVolumeTemplates))
+	for _, t := range vm.Spec.DataVolumeTemplates {
+		templates[t.Name] = t
+	}
+
+	volumes := make([]domain.Volume, 0, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		vol := domain.Volume{Name: v.Name, Source: domain.VolumeSourceOther}
+		switch {
+		case v.DataVolume != nil:
+			vol.Source, vol.ClaimName = domain.VolumeSourceDataVolume, v.DataVolume.Name
+		case v.PersistentVolumeClaim != nil:
+			vol.Source, vol.ClaimName = domain.VolumeSourcePVC, v.PersistentVolumeClaim.ClaimName
+		case v.ContainerDisk != nil:
+			vol.Source = domain.VolumeSourceContainerDisk
+		case v.CloudInitNoCloud != nil, v.CloudInitConfigDrive != nil:
+			vol.Source = domain.VolumeSourceCloudInit
+		}
+
+		if d, ok := disks[v.Name]; ok {
+			switch {
+			case d.Disk != nil:
+				vol.Bus = domain.DiskBus(d.Disk.Bus)
+			case d.LUN != nil:
+				vol.Bus = domain.DiskBus(d.LUN.Bus)
+			case d.CDRom != nil:
+				vol.Bus, vol.CDROM = domain.DiskBus(d.CDRom.Bus), true
+			}
+			if d.BootOrder != nil {
+				vol.BootOrder = int(*d.BootOrder)
+			}
+		}
+
+		if vol.ClaimName != "" {
+			if pvc, ok := claims[vol.ClaimName]; ok && pvc != nil {
+				vol.SizeBytes, vol.StorageClass = claimSize(pvc), deref(pvc.Spec.StorageClassName)
+			} else if t, ok := templates[vol.ClaimName]; ok {
+				vol.SizeBytes, vol.StorageClass = templateSize(t)
+			}
+		}
+		volumes = append(volumes, vol)
+	}
+	return volumes
+}
+
+// claimSize is the bound capacity, else the requested size.
+func claimSize(pvc *corev1.PersistentVolumeClaim) int64 {
+	if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return q.Value()
+	}
+	if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return q.Value()
+	}
+	return 0
+}
+
+// templateSize reads the requested size and storage class of a
+// DataVolumeTemplate (spec.storage, or the older spec.pvc).
+func templateSize(t kubevirtv1.DataVolumeTemplateSpec) (int64, string) {
+	switch {
+	case t.Spec.Storage != nil:
+		q := t.Spec.Storage.Resources.Requests[corev1.ResourceStorage]
+		return q.Value(), deref(t.Spec.Storage.StorageClassName)
+	case t.Spec.PVC != nil:
+		q := t.Spec.PVC.Resources.Requests[corev1.ResourceStorage]
+		return q.Value(), deref(t.Spec.PVC.StorageClassName)
+	}
+	return 0, ""
+}
+
+// mapInterfaces joins spec interfaces with their networks by name, then
+// overlays the guest-reported MAC and IPs from the VMI.
+func (m *Mapper) mapInterfaces(vm *kubevirtv1.VirtualMachine, vmi *kubevirtv1.VirtualMachineInstance) []domain.NetworkInterface {
+	spec := vm.Spec.Template.Spec
+	networks := make(map[string]kubevirtv1.Network, len(spec.Networks))
+	for _, n := range spec.Networks {
+		networks[n.Name] = n
+	}
+	var status map[string]kubevirtv1.VirtualMachineInstanceNetworkInterface
+	if vmi != nil {
+		status = make(map[string]kubevirtv1.VirtualMachineInstanceNetworkInterface, len(vmi.Status.Interfaces))
+		for _, s := range vmi.Status.Interfaces {
+			status[s.Name] = s
+		}
+	}
+
+	ifaces := make([]domain.NetworkInterface, 0, len(spec.Domain.Devices.Interfaces))
+	for _, i := range spec.Domain.Devices.Interfaces {
+		nic := domain.NetworkInterface{
+			Name:    i.Name,
+			Model:   i.Model,
+			MAC:     i.MacAddress,
+			Binding: domain.InterfaceBindingOther,
+		}
+		switch {
+		case i.Masquerade != nil:
+			nic.Binding = domain.InterfaceBindingMasquerade
+		case i.Bridge != nil:
+			nic.Binding = domain.InterfaceBindingBridge
+		case i.SRIOV != nil:
+			nic.Binding = domain.InterfaceBindingSRIOV
+		}
+
+		if n, ok := networks[i.Name]; ok {
+			switch {
+			case n.Pod != nil:
+				nic.Network = domain.PodNetwork
+			case n.Multus != nil:
+				nic.Network = n.Multus.NetworkName
+			}
+		}
+
+		if s, ok := status[i.Name]; ok {
+			if s.MAC != "" {
+				nic.MAC = s.MAC
+			}
+			nic.IPs = s.IPs
+			if len(nic.IPs) == 0 && s.IP != "" {
+				nic.IPs = []string{s.IP}
+			}
+		}
+		ifaces = append(ifaces, nic)
+	}
+	return ifaces
+}
+
+// mapGPUs groups spec.template.spec.domain.devices.gpus by deviceName.
+func (m *Mapper) mapGPUs(vm *kubevirtv1.VirtualMachine) []domain.GPUDevice {
+	gpus := vm.Spec.Template.Spec.Domain.Devices.GPUs
+	assigned := make([]domain.GPUAssignment, len(gpus))
+	for i, g := range gpus {
+		assigned[i] = domain.GPUAssignment{Name: g.Name, DeviceName: g.DeviceName}
+	}
+	return domain.CountGPUs(assigned)
+}
+
+// KubeVirtGPUs returns the devices.gpus entries CreateVM sets for
+// VMSpec.GPUs, one per GPU.
+func KubeVirtGPUs(gpus []domain.GPUDevice) []kubevirtv1.GPU {
+	var out []kubevirtv1.GPU
+	for _, a := range domain.ExpandGPUs(gpus) {
+		out = append(out, kubevirtv1.GPU{Name: a.Name, DeviceName: a.DeviceName})
+	}
+	return out
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// ProviderTypeMock is the type of MockProvider.
+const ProviderTypeMock = "mock"
+
+// MockProvider is an in-memory InfrastructureProvider for tests that need
+// no cluster (Phase 2 §9). It implements the base interface only, so
+// capability checks see a provider without snapshots, clones or
+// migrations. It must pass internal/provider/conformance like any other
+// implementation (conformance_test.go).
+type MockProvider struct {
+	mu      sync.RWMutex
+	vms     map[string]*domain.VM // Keyed by cluster/namespace/name
+	nextID  int                   // Generated names
+	version int                   // Last resourceVersion handed out
+}
+
+// NewMockProvider returns an empty MockProvider.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{vms: map[string]*domain.VM{}}
+}
+
+// Seed stores copies of vms as they are, replacing VMs of the same name.
+func (p *MockProvider) Seed(vms []*domain.VM) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, vm := range vms {
+		c := *vm
+		p.vms[vmKey(c.Cluster, c.Namespace, c.Name)] = &c
+	}
+}
+
+// Reset removes every VM.
+func (p *MockProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.vms = map[string]*domain.VM{}
+}
+
+func (p *MockProvider) Name() string { return "mock" }
+func (p *MockProvider) Type() string { return ProviderTypeMock }
+
+func (p *MockProvider) GetVM(ctx context.Context, cluster, namespace, name string) (*domain.VM, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	vm, ok := p.vms[vmKey(cluster, namespace, name)]
+	if !ok {
+		return nil, fmt.Errorf("get vm %s/%s: %w", namespace, name, ErrNotFound)
+	}
+	c := *vm
+	return &c, nil
+}
+
+// ListVMs returns the namespace's VMs by name. The continue token is the
+// last name of the previous page, so pages never repeat or skip a VM.
+// Only equality selectors ("k=v,k2=v2") are supported.
+func (p *MockProvider) ListVMs(ctx context.Context, cluster, namespace string, opts ListOptions) (*domain.VMList, error) {
+	after := ""
+	if opts.Continue != "" {
+		var ok bool
+		after, ok = strings.CutPrefix(opts.Continue, "after:")
+		if !ok {
+			return nil, fmt.Errorf("list vms: %w", ErrInvalidContinue)
+		}
+	}
+	match, err := parseEqualitySelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	var items []*domain.VM
+	for _, vm := range p.vms {
+		if vm.Cluster != cluster || vm.Namespace != namespace || vm.Name <= after || !match(vm.Labels) {
+			continue
+		}
+		c := *vm
+		items = append(items, &c)
+	}
+	p.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	list := &domain.VMList{Items: items, Total: len(items)}
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		list.Items = items[:opts.Limit]
+		list.Continue = "after:" + list.Items[opts.Limit-1].Name
+	}
+	return list, nil
+}
+
+// CreateVM stores a stopped VM with a generated name, the platform labels
+// and the ownership annotations, as the KubeVirt provider writes them.
+func (p *MockProvider) CreateVM(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.VM, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	now := time.Now()
+	vm := &domain.VM{
+		ID:              strconv.Itoa(p.nextID),
+		Name:            fmt.Sprintf("mock-%04d", p.nextID),
+		Namespace:       namespace,
+		Cluster:         cluster,
+		ResourceVersion: p.nextVersion(),
+		ServiceID:       spec.ServiceID,
+		Status:          domain.VMStatusStopped,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Labels: map[string]string{
+			domain.LabelManagedBy: domain.ManagedByValue,
+			domain.LabelService:   spec.Ownership.ServiceID,
+			domain.LabelSystem:    spec.Ownership.SystemID,
+			domain.LabelTicketID:  spec.Ownership.TicketID,
+		},
+		Annotations: spec.Ownership.Annotations(),
+	}
+	applySpec(vm, spec)
+	p.vms[vmKey(cluster, namespace, vm.Name)] = vm
+	c := *vm
+	return &c, nil
+}
+
+// UpdateVM applies spec's resources, failing with ErrConflict when
+// ExpectedResourceVersion is set and stale.
+func (p *MockProvider) UpdateVM(ctx context.Context, cluster, namespace, name string, spec *domain.VMSpec) (*domain.VM, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	vm, ok := p.vms[vmKey(cluster, namespace, name)]
+	if !ok {
+		return nil, fmt.Errorf("update vm %s/%s: %w", namespace, name, ErrNotFound)
+	}
+	if spec.ExpectedResourceVersion != "" && spec.ExpectedResourceVersion != vm.ResourceVersion {
+		return nil, fmt.Errorf("update vm %s/%s: %w", namespace, name, ErrConflict)
+	}
+	applySpec(vm, spec)
+	vm.ResourceVersion = p.nextVersion()
+	vm.UpdatedAt = time.Now()
+	c := *vm
+	return &c, nil
+}
+
+func (p *MockProvider) DeleteVM(ctx context.Context, cluster, namespace, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := vmKey(cluster, namespace, name)
+	if _, ok := p.vms[key]; !ok {
+		return fmt.Errorf("delete vm %s/%s: %w", namespace, name, ErrNotFound)
+	}
+	delete(p.vms, key)
+	return nil
+}
+
+func (p *MockProvider) StartVM(ctx context.Context, cluster, namespace, name string) error {
+	return p.setStatus(cluster, namespace, name, domain.VMStatusRunning)
+}
+
+func (p *MockProvider) StopVM(ctx context.Context, cluster, namespace, name string) error {
+	return p.setStatus(cluster, namespace, name, domain.VMStatusStopped)
+}
+
+func (p *MockProvider) RestartVM(ctx context.Context, cluster, namespace, name string) error {
+	return p.setStatus(cluster, namespace, name, domain.VMStatusRunning)
+}
+
+func (p *MockProvider) PauseVM(ctx context.Context, cluster, namespace, name string) error {
+	return p.setStatus(cluster, namespace, name, domain.VMStatusPaused)
+}
+
+func (p *MockProvider) UnpauseVM(ctx context.Context, cluster, namespace, name string) error {
+	return p.setStatus(cluster, namespace, name, domain.VMStatusRunning)
+}
+
+// ValidateSpec accepts any spec with resources; it stores nothing.
+func (p *MockProvider) ValidateSpec(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.ValidationResult, error) {
+	result := &domain.ValidationResult{Valid: true}
+	if spec.CPU <= 0 || spec.MemoryMB <= 0 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "cpu and memory_mb must be positive")
+	}
+	return result, nil
+}
+
+func (p *MockProvider) setStatus(cluster, namespace, name string, status domain.VMStatus) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	vm, ok := p.vms[vmKey(cluster, namespace, name)]
+	if !ok {
+		return fmt.Errorf("set vm %s/%s %s: %w", namespace, name, status, ErrNotFound)
+	}
+	vm.Status = status
+	vm.ResourceVersion = p.nextVersion()
+	return nil
+}
+
+// nextVersion must be called with mu held.
+func (p *MockProvider) nextVersion() string {
+	p.version++
+	return strconv.Itoa(p.version)
+}
+
+func vmKey(cluster, namespace, name string) string {
+	return cluster + "/" + namespace + "/" + name
+}
+
+func applySpec(vm *domain.VM, spec *domain.VMSpec) {
+	vm.CPU = spec.CPU
+	vm.MemoryMB = spec.MemoryMB
+	vm.DiskGB = spec.DiskGB
+	vm.Template = spec.Template
+}
+
+// parseEqualitySelector returns a matcher for "k=v,k2=v2"; empty matches all.
+func parseEqualitySelector(selector string) (func(map[string]string) bool, error) {
+	want := map[string]string{}
+	if selector != "" {
+		for _, term := range strings.Split(selector, ",") {
+			k, v, ok := strings.Cut(term, "=")
+			if !ok {
+				return nil, fmt.Errorf("label selector %q: only equality terms are supported", selector)
+			}
+			want[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return func(labels map[string]string) bool {
+		for k, v := range want {
+			if labels[k] != v {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// Provider types. Stored in clusters.provider_type; "kubevirt" is the default.
+const (
+	ProviderTypeKubeVirt = "kubevirt"
+)
+
+// Capability names reported by capability discovery.
+// A provider has a capability when it implements the matching narrow interface (ADR-0024).
+const (
+	CapabilitySnapshot     = "snapshot"
+	CapabilityClone        = "clone"
+	CapabilityMigration    = "migration"
+	CapabilityRelocation   = "relocation"
+	CapabilityInstanceType = "instance_type"
+	CapabilityConsole      = "console"
+	CapabilityNode         = "node"
+	CapabilityMetadata     = "metadata"
+	CapabilityDiagnostics  = "diagnostics"
+	CapabilityNamespace    = "namespace"
+)
+
+// Factory builds the provider for one registered cluster (or other VM estate).
+// Registered per provider type in the composition root (ADR-0013):
+//
+//	registry.RegisterType(provider.ProviderTypeKubeVirt, kubevirt.NewProvider)
+//	registry.RegisterType("libvirt", libvirt.NewProvider)
+type Factory func(ctx context.Context, cluster *domain.Cluster, creds CredentialProvider) (InfrastructureProvider, error)
+
+// Registry routes provider calls to the implementation attached to each cluster,
+// so one Shepherd instance can govern heterogeneous VM estates.
+//
+// Registry itself implements InfrastructureProvider by dispatching on the
+// cluster argument; use cases keep depending on InfrastructureProvider and
+// need no changes. Optional capabilities are reached through the typed
+// accessors below and return ErrCapabilityUnsupported when absent.
+//
+// Mutating VM calls are ownership-checked here, once for every backend:
+// the target object's ownership annotations must match Shepherd's record
+// (see domain.VerifyOwnership), otherwise the call fails with domain.ErrNotOwned.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+	providers map[string]InfrastructureProvider // cluster name → provider
+	ownership OwnershipResolver
+}
+
+// OwnershipResolver returns Shepherd's ownership record for a VM.
+// Implemented by the VM repository; returns nil, nil when Shepherd has no
+// record (the VM is then treated as not owned).
+type OwnershipResolver interface {
+	ExpectedOwnership(ctx context.Context, cluster, namespace, name string) (*domain.Ownership, error)
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(ownership OwnershipResolver) *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+		providers: make(map[string]InfrastructureProvider),
+		ownership: ownership,
+	}
+}
+
+// RegisterType binds a provider type to its factory. Called once at startup.
+func (r *Registry) RegisterType(providerType string, f Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[providerType] = f
+}
+
+// Attach builds and attaches the provider for a cluster.
+// Called at cluster registration and at startup for every non-archived cluster.
+func (r *Registry) Attach(ctx context.Context, cluster *domain.Cluster, creds CredentialProvider) error {
+	providerType := cluster.ProviderType
+	if providerType == "" {
+		providerType = ProviderTypeKubeVirt
+	}
+
+	r.mu.RLock()
+	f, ok := r.factories[providerType]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("provider type %q: %w", providerType, ErrUnknownProviderType)
+	}
+
+	p, err := f(ctx, cluster, creds)
+	if err != nil {
+		return fmt.Errorf("build %s provider for cluster %s: %w", providerType, cluster.Name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[cluster.Name] = p
+	return nil
+}
+
+// Detach removes a cluster's provider (cluster archived).
+func (r *Registry) Detach(cluster string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.providers, cluster)
+}
+
+// For returns the provider attached to a cluster.
+func (r *Registry) For(cluster string) (InfrastructureProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", cluster, ErrClusterNotAttached)
+	}
+	return p, nil
+}
+
+// Capabilities lists the optional capabilities of a cluster's provider (sorted).
+// Exposed on the admin cluster API next to the ADR-0014 feature matrix.
+func (r *Registry) Capabilities(cluster string) ([]string, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var caps []string
+	if _, ok := p.(SnapshotProvider); ok {
+		caps = append(caps, CapabilitySnapshot)
+	}
+	if _, ok := p.(CloneProvider); ok {
+		caps = append(caps, CapabilityClone)
+	}
+	if _, ok := p.(MigrationProvider); ok {
+		caps = append(caps, CapabilityMigration)
+	}
+	if _, ok := p.(RelocationProvider); ok {
+		caps = append(caps, CapabilityRelocation)
+	}
+	if _, ok := p.(InstanceTypeProvider); ok {
+		caps = append(caps, CapabilityInstanceType)
+	}
+	if _, ok := p.(ConsoleProvider); ok {
+		caps = append(caps, CapabilityConsole)
+	}
+	if _, ok := p.(NodeProvider); ok {
+		caps = append(caps, CapabilityNode)
+	}
+	if _, ok := p.(MetadataProvider); ok {
+		caps = append(caps, CapabilityMetadata)
+	}
+	if _, ok := p.(DiagnosticsProvider); ok {
+		caps = append(caps, CapabilityDiagnostics)
+	}
+	if _, ok := p.(NamespaceProvider); ok {
+		caps = append(caps, CapabilityNamespace)
+	}
+	sort.Strings(caps)
+	return caps, nil
+}
+
+// Snapshots returns the cluster's SnapshotProvider.
+// CreateSnapshot and RestoreFromSnapshot are ownership-checked.
+func (r *Registry) Snapshots(cluster string) (SnapshotProvider, error) {
+	sp, err := capabilityOf[SnapshotProvider](r, cluster, CapabilitySnapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &ownedSnapshots{SnapshotProvider: sp, registry: r}, nil
+}
+
+// Clones returns the cluster's CloneProvider.
+func (r *Registry) Clones(cluster string) (CloneProvider, error) {
+	return capabilityOf[CloneProvider](r, cluster, CapabilityClone)
+}
+
+// Migrations returns the cluster's MigrationProvider.
+// MigrateVM is ownership-checked.
+func (r *Registry) Migrations(cluster string) (MigrationProvider, error) {
+	mp, err := capabilityOf[MigrationProvider](r, cluster, CapabilityMigration)
+	if err != nil {
+		return nil, err
+	}
+	return &ownedMigrations{MigrationProvider: mp, registry: r}, nil
+}
+
+// Relocations returns the cluster's RelocationProvider. Not
+// ownership-checked: the VM created on the target carries the source's
+// ownership annotations, and Shepherd's record names the target only
+// after cutover.
+func (r *Registry) Relocations(cluster string) (RelocationProvider, error) {
+	return capabilityOf[RelocationProvider](r, cluster, CapabilityRelocation)
+}
+
+// InstanceTypes returns the cluster's InstanceTypeProvider.
+func (r *Registry) InstanceTypes(cluster string) (InstanceTypeProvider, error) {
+	return capabilityOf[InstanceTypeProvider](r, cluster, CapabilityInstanceType)
+}
+
+// Consoles returns the cluster's ConsoleProvider.
+func (r *Registry) Consoles(cluster string) (ConsoleProvider, error) {
+	return capabilityOf[ConsoleProvider](r, cluster, CapabilityConsole)
+}
+
+// Nodes returns the cluster's NodeProvider.
+func (r *Registry) Nodes(cluster string) (NodeProvider, error) {
+	return capabilityOf[NodeProvider](r, cluster, CapabilityNode)
+}
+
+// Metadata returns the cluster's MetadataProvider.
+func (r *Registry) Metadata(cluster string) (MetadataProvider, error) {
+	return capabilityOf[MetadataProvider](r, cluster, CapabilityMetadata)
+}
+
+// Diagnostics returns the cluster's DiagnosticsProvider.
+func (r *Registry) Diagnostics(cluster string) (DiagnosticsProvider, error) {
+	return capabilityOf[DiagnosticsProvider](r, cluster, CapabilityDiagnostics)
+}
+
+// Namespaces returns the cluster's NamespaceProvider.
+func (r *Registry) Namespaces(cluster string) (NamespaceProvider, error) {
+	return capabilityOf[NamespaceProvider](r, cluster, CapabilityNamespace)
+}
+
+func capabilityOf[T any](r *Registry, cluster, capability string) (T, error) {
+	var zero T
+	p, err := r.For(cluster)
+	if err != nil {
+		return zero, err
+	}
+	c, ok := p.(T)
+	if !ok {
+		return zero, fmt.Errorf("cluster %s does not support %s: %w", cluster, capability, ErrCapabilityUnsupported)
+	}
+	return c, nil
+}
+
+// ownedSnapshots verifies the VM before snapshot mutations.
+type ownedSnapshots struct {
+	SnapshotProvider
+	registry *Registry
+}
+
+func (o *ownedSnapshots) CreateSnapshot(ctx context.Context, cluster, namespace, vmName, snapshotName string) (*domain.Snapshot, error) {
+	if _, err := o.registry.owned(ctx, cluster, namespace, vmName); err != nil {
+		return nil, err
+	}
+	return o.SnapshotProvider.CreateSnapshot(ctx, cluster, namespace, vmName, snapshotName)
+}
+
+func (o *ownedSnapshots) RestoreFromSnapshot(ctx context.Context, cluster, namespace, snapshotName, targetVMName string) (*domain.VM, error) {
+	if _, err := o.registry.owned(ctx, cluster, namespace, targetVMName); err != nil {
+		return nil, err
+	}
+	return o.SnapshotProvider.RestoreFromSnapshot(ctx, cluster, namespace, snapshotName, targetVMName)
+}
+
+// ownedMigrations verifies the VM before starting a migration.
+type ownedMigrations struct {
+	MigrationProvider
+	registry *Registry
+}
+
+func (o *ownedMigrations) MigrateVM(ctx context.Context, cluster, namespace, name string) (*domain.Migration, error) {
+	if _, err := o.registry.owned(ctx, cluster, namespace, name); err != nil {
+		return nil, err
+	}
+	return o.MigrationProvider.MigrateVM(ctx, cluster, namespace, name)
+}
+
+// ========== InfrastructureProvider (routing) ==========
+
+// Name returns the registry name.
+func (r *Registry) Name() string { return "registry" }
+
+// Type returns "router"; per-cluster types are in clusters.provider_type.
+func (r *Registry) Type() string { return "router" }
+
+// GetVM routes to the cluster's provider.
+func (r *Registry) GetVM(ctx context.Context, cluster, namespace, name string) (*domain.VM, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetVM(ctx, cluster, namespace, name)
+}
+
+// owned returns the cluster's provider after verifying that the VM is
+// owned by Shepherd's record. Costs one GET per mutation; mutations are
+// rare and asynchronous (ADR-0006), collisions are not.
+func (r *Registry) owned(ctx context.Context, cluster, namespace, name string) (InfrastructureProvider, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.verifyOwned(ctx, p, cluster, namespace, name); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *Registry) verifyOwned(ctx context.Context, p InfrastructureProvider, cluster, namespace, name string) error {
+	expected, err := r.ownership.ExpectedOwnership(ctx, cluster, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get ownership record: %w", err)
+	}
+	if expected == nil {
+		return fmt.Errorf("vm %s/%s on %s has no Shepherd record: %w", namespace, name, cluster, domain.ErrNotOwned)
+	}
+	vm, err := p.GetVM(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	if err := domain.VerifyOwnership(expected, domain.OwnershipFromAnnotations(vm.Annotations)); err != nil {
+		return fmt.Errorf("vm %s/%s on %s: %w", namespace, name, cluster, err)
+	}
+	return nil
+}
+
+// ListVMs routes to the cluster's provider.
+func (r *Registry) ListVMs(ctx context.Context, cluster, namespace string, opts ListOptions) (*domain.VMList, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return p.ListVMs(ctx, cluster, namespace, opts)
+}
+
+// CreateVM routes to the cluster's provider.
+func (r *Registry) CreateVM(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.VM, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return p.CreateVM(ctx, cluster, namespace, spec)
+}
+
+// UpdateVM routes to the cluster's provider after the ownership check.
+func (r *Registry) UpdateVM(ctx context.Context, cluster, namespace, name string, spec *domain.VMSpec) (*domain.VM, error) {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return p.UpdateVM(ctx, cluster, namespace, name, spec)
+}
+
+// DeleteVM routes to the cluster's provider after the ownership check.
+func (r *Registry) DeleteVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.DeleteVM(ctx, cluster, namespace, name)
+}
+
+// StartVM routes to the cluster's provider after the ownership check.
+func (r *Registry) StartVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.StartVM(ctx, cluster, namespace, name)
+}
+
+// StopVM routes to the cluster's provider after the ownership check.
+func (r *Registry) StopVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.StopVM(ctx, cluster, namespace, name)
+}
+
+// RestartVM routes to the cluster's provider after the ownership check.
+func (r *Registry) RestartVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.RestartVM(ctx, cluster, namespace, name)
+}
+
+// PauseVM routes to the cluster's provider after the ownership check.
+func (r *Registry) PauseVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.PauseVM(ctx, cluster, namespace, name)
+}
+
+// UnpauseVM routes to the cluster's provider after the ownership check.
+func (r *Registry) UnpauseVM(ctx context.Context, cluster, namespace, name string) error {
+	p, err := r.owned(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	return p.UnpauseVM(ctx, cluster, namespace, name)
+}
+
+// ValidateSpec routes to the cluster's provider.
+func (r *Registry) ValidateSpec(ctx context.Context, cluster, namespace string, spec *domain.VMSpec) (*domain.ValidationResult, error) {
+	p, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return p.ValidateSpec(ctx, cluster, namespace, spec)
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// RenderBaseline renders a namespace baseline for one namespace of a
+// cluster. Bodies see ${shepherd.vm.namespace}, ${shepherd.cluster.name}
+// and ${shepherd.environment}; the VM and Service variables are empty.
+//
+// Each object gets the managed-by label and the hash of its rendered spec,
+// which the sync compares with the live object to detect drift.
+func RenderBaseline(b *domain.NamespaceBaseline, cluster, namespace string) ([]*domain.GovernedObject, error) {
+	vars := domain.TemplateVars{
+		Namespace:   namespace,
+		ClusterName: cluster,
+		Environment: b.Environment,
+	}
+
+	objects := make([]*domain.GovernedObject, 0, len(b.Objects))
+	for _, o := range b.Objects {
+		out, err := Render(o.Spec, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", o.Kind, o.Name, err)
+		}
+		var spec map[string]interface{}
+		if err := yaml.Unmarshal([]byte(out), &spec); err != nil {
+			return nil, fmt.Errorf("%s/%s: spec is not a YAML mapping: %w", o.Kind, o.Name, domain.ErrInvalidNamespaceBaseline)
+		}
+		objects = append(objects, &domain.GovernedObject{
+			Kind:      o.Kind,
+			Namespace: namespace,
+			Name:      o.Name,
+			Spec:      spec,
+			Labels: map[string]string{
+				domain.LabelManagedBy: domain.ManagedByValue,
+			},
+			Annotations: map[string]string{
+				domain.AnnotationSpecHash: domain.SpecHash(spec),
+			},
+		})
+	}
+	return objects, nil
+}
+
+// ValidateBaseline checks a baseline before it is saved: its shape, and
+// that every body renders to a YAML mapping for a sample namespace.
+func ValidateBaseline(b *domain.NamespaceBaseline) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	if _, err := RenderBaseline(b, "sample-cluster", "sample-namespace"); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidNamespaceBaseline, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package render
+
+import "kv-shepherd.io/shepherd/internal/domain"
+
+// GoldenResult is the outcome of rendering one golden case.
+type GoldenResult struct {
+	Name          string     `json:"name"`
+	Passed        bool       `json:"passed"`
+	CloudInitDiff []DiffLine `json:"cloud_init_diff,omitempty"` // Golden → current
+	ManifestDiff  []DiffLine `json:"manifest_diff,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// CheckGolden renders the template for every golden case and diffs the
+// output against the reviewed expectation.
+func CheckGolden(t *domain.Template) []GoldenResult {
+	results := make([]GoldenResult, 0, len(t.GoldenCases))
+	for _, gc := range t.GoldenCases {
+		r := GoldenResult{Name: gc.Name}
+
+		cloudInit, manifest, err := RenderTemplate(t, gc.Vars)
+		if err != nil {
+			r.Error = err.Error()
+			results = append(results, r)
+			continue
+		}
+
+		if d := Diff(gc.ExpectedCloudInit, cloudInit); HasChanges(d) {
+			r.CloudInitDiff = d
+		}
+		if d := Diff(gc.ExpectedManifest, manifest); HasChanges(d) {
+			r.ManifestDiff = d
+		}
+		r.Passed = r.CloudInitDiff == nil && r.ManifestDiff == nil
+		results = append(results, r)
+	}
+	return results
+}
+
+// AllPassed reports whether every golden case matched.
+func AllPassed(results []GoldenResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// AcceptGolden replaces every golden expectation with the current output.
+// Called when the admin has reviewed the diff in the editor.
+func AcceptGolden(t *domain.Template) error {
+	for i := range t.GoldenCases {
+		cloudInit, manifest, err := RenderTemplate(t, t.GoldenCases[i].Vars)
+		if err != nil {
+			return err
+		}
+		t.GoldenCases[i].ExpectedCloudInit = cloudInit
+		t.GoldenCases[i].ExpectedManifest = manifest
+	}
+	return nil
+}
+
+// RenderTemplate renders both bodies of a template.
+func RenderTemplate(t *domain.Template, vars domain.TemplateVars) (cloudInit, manifest string, err error) {
+	if cloudInit, err = Render(t.CloudInit, vars); err != nil {
+		return "", "", err
+	}
+	if manifest, err = Render(t.Manifest, vars); err != nil {
+		return "", "", err
+	}
+	return cloudInit, manifest, nil
+}
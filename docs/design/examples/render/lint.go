@@ -0,0 +1,142 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// LintCheck names the check that produced a finding.
+type LintCheck string
+
+const (
+	LintSchema    LintCheck = "schema"          // Template fields and manifest shape
+	LintForbidden LintCheck = "forbidden_field" // Platform-controlled fields (ADR-0015 §4)
+	LintCloudInit LintCheck = "cloud_init"      // #cloud-config header and YAML syntax
+	LintDryRun    LintCheck = "dry_run"         // Server-side dry-run on the reference cluster
+)
+
+// LintFinding is one problem that blocks publishing.
+type LintFinding struct {
+	Check   LintCheck `json:"check"`
+	Path    string    `json:"path,omitempty"` // e.g. spec.template.metadata.labels
+	Message string    `json:"message"`
+}
+
+// cloudConfigHeader must be the first line of a cloud-init body;
+// cloud-init ignores user data without it.
+const cloudConfigHeader = "#cloud-config"
+
+// forbiddenManifestPaths are set by the platform only (ADR-0015 §4): a
+// fragment setting them could rename or move the VM, or forge the
+// governance labels that ownership and quota rely on.
+var forbiddenManifestPaths = []string{
+	"metadata.name",
+	"metadata.namespace",
+	"metadata.labels",
+	"metadata.ownerReferences",
+	"spec.template.metadata.labels",
+}
+
+// Lint checks a template without any I/O, on the output rendered with
+// vars (the first golden case at publish time). It returns every finding,
+// not just the first; none means the template may go to the dry-run.
+func Lint(t *domain.Template, vars domain.TemplateVars) []LintFinding {
+	var findings []LintFinding
+	add := func(check LintCheck, path, format string, args ...interface{}) {
+		findings = append(findings, LintFinding{Check: check, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case t.ImageSource == "" && t.Image == "":
+		add(LintSchema, "image_source", "set image_source, or image and image_channel")
+	case t.ImageSource != "" && t.Image != "":
+		add(LintSchema, "image_source", "image_source and image are exclusive")
+	case t.Image != "" && !validChannel(t.ImageChannel):
+		add(LintSchema, "image_channel", "unknown image channel %q", t.ImageChannel)
+	}
+
+	cloudInit, manifest, err := RenderTemplate(t, vars)
+	if err != nil {
+		add(LintSchema, "", "render: %v", err)
+		return findings
+	}
+
+	if strings.TrimSpace(cloudInit) == "" {
+		add(LintCloudInit, "cloud_init", "cloud_init is required")
+	} else {
+		if first, _, _ := strings.Cut(cloudInit, "\n"); strings.TrimSpace(first) != cloudConfigHeader {
+			add(LintCloudInit, "cloud_init", "first line must be %s", cloudConfigHeader)
+		}
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(cloudInit), &doc); err != nil {
+			add(LintCloudInit, "cloud_init", "invalid YAML: %v", err)
+		}
+	}
+
+	if strings.TrimSpace(manifest) == "" {
+		return findings
+	}
+	var fragment map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &fragment); err != nil {
+		add(LintSchema, "manifest", "invalid YAML mapping: %v", err)
+		return findings
+	}
+	if kind, ok := fragment["kind"]; ok && kind != "VirtualMachine" {
+		add(LintSchema, "kind", "manifest fragment must be a VirtualMachine, got %v", kind)
+	}
+	for _, path := range forbiddenManifestPaths {
+		if _, ok := lookup(fragment, path); ok {
+			add(LintForbidden, path, "%s is platform-managed", path)
+		}
+	}
+	for _, path := range []string{"metadata.annotations", "spec.template.metadata.annotations"} {
+		annotations, _ := lookup(fragment, path)
+		m, _ := annotations.(map[string]interface{})
+		for key := range m {
+			if strings.HasPrefix(key, domain.PlatformKeyPrefix) {
+				add(LintForbidden, path+"."+key, "%s annotations are platform-managed", domain.PlatformKeyPrefix)
+			}
+		}
+	}
+	// Cloud-init comes from the cloud_init body only, so it is reviewed
+	// through golden cases and never hidden in the manifest
+	volumes, _ := lookup(fragment, "spec.template.spec.volumes")
+	list, _ := volumes.([]interface{})
+	for i, v := range list {
+		volume, _ := v.(map[string]interface{})
+		for _, source := range []string{"cloudInitNoCloud", "cloudInitConfigDrive"} {
+			if _, ok := volume[source]; ok {
+				add(LintForbidden, fmt.Sprintf("spec.template.spec.volumes[%d].%s", i, source), "cloud-init belongs in cloud_init")
+			}
+		}
+	}
+	return findings
+}
+
+// lookup walks a dotted path through nested mappings.
+func lookup(doc map[string]interface{}, path string) (interface{}, bool) {
+	var node interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func validChannel(c domain.ImageChannel) bool {
+	for _, ch := range domain.ImageChannels {
+		if ch == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Package render is the sandboxed renderer for cloud-init and manifest templates.
+//
+// ADR-0018 removed Go Template from templates: no functions, no conditionals,
+// no loops. The only construct is substitution of allowlisted platform
+// variables written as ${shepherd.<name>}. Everything else is literal text,
+// so shell variables (${HOME}) and cloud-init Jinja pass through untouched.
+//
+// Sandbox guarantees:
+//   - No I/O: rendering is a pure function of (body, vars); no network, files, env, clock
+//   - Deterministic: same input → byte-identical output (Hash is stable)
+//   - Injection-safe: values containing newlines or YAML control characters are rejected
+//   - Bounded: template and output size are capped
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/render
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+)
+
+// MaxTemplateBytes caps both template body and rendered output.
+const MaxTemplateBytes = 64 * 1024
+
+// values maps variable names to TemplateVars fields.
+// Adding an entry here (and a field to domain.TemplateVars) is the ONLY
+// way to expose new data to templates.
+func values(v domain.TemplateVars) map[string]string {
+	return map[string]string{
+		"vm.name":      v.VMName,
+		"vm.namespace": v.Namespace,
+		"vm.hostname":  v.Hostname,
+		"vm.instance":  v.Instance,
+		"service.name": v.ServiceName,
+		"system.name":  v.SystemName,
+		"cluster.name": v.ClusterName,
+		"environment":  v.Environment,
+	}
+}
+
+// Variables lists allowed variable names, sorted (for editor autocompletion).
+func Variables() []string {
+	vals := values(domain.TemplateVars{})
+	names := make([]string, 0, len(vals))
+	for name := range vals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	placeholder = regexp.MustCompile(`\$\{shepherd\.([^}]*)\}`)
+
+	// Platform values are DNS labels or simple identifiers; anything else
+	// could break out of a YAML scalar.
+	safeValue = regexp.MustCompile(`^[A-Za-z0-9._-]*$`)
+)
+
+// Validate checks a template body without rendering it (used before save).
+// Returns every unknown variable, not just the first.
+func Validate(body string) error {
+	if len(body) > MaxTemplateBytes {
+		return ErrTemplateTooLarge
+	}
+
+	allowed := values(domain.TemplateVars{})
+	var unknown []string
+	for _, m := range placeholder.FindAllStringSubmatch(body, -1) {
+		if _, ok := allowed[m[1]]; !ok {
+			unknown = append(unknown, m[1])
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownVariable.With("variables", unknown), strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Render substitutes variables into body.
+func Render(body string, vars domain.TemplateVars) (string, error) {
+	if err := Validate(body); err != nil {
+		return "", err
+	}
+
+	vals := values(vars)
+	for name, v := range vals {
+		if !safeValue.MatchString(v) {
+			return "", fmt.Errorf("%w: %s", ErrUnsafeValue.With("variable", name), name)
+		}
+	}
+
+	out := placeholder.ReplaceAllStringFunc(body, func(m string) string {
+		return vals[placeholder.FindStringSubmatch(m)[1]]
+	})
+	if len(out) > MaxTemplateBytes {
+		return "", ErrTemplateTooLarge
+	}
+	return out, nil
+}
+
+// Hash returns a stable content hash of rendered output.
+func Hash(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
+
+// Errors
+
+var (
+	// ErrUnknownVariable is returned for ${shepherd.x} where x is not in
+	// Vars; params: variables.
+	ErrUnknownVariable = domainerr.Unprocessable("TEMPLATE_RENDER_FAILED", "unknown template variable")
+
+	// ErrUnsafeValue is returned when a variable value could inject YAML;
+	// params: variable.
+	ErrUnsafeValue = domainerr.Unprocessable("TEMPLATE_RENDER_FAILED", "variable value contains unsafe characters")
+
+	// ErrTemplateTooLarge is returned when body or output exceeds MaxTemplateBytes.
+	ErrTemplateTooLarge = domainerr.Unprocessable("TEMPLATE_RENDER_FAILED", "template exceeds maximum size")
+)
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// APIMeter counts API calls per principal and token (domain/api_usage.go)
+// and enforces per-token quotas.
+//
+//	CREATE TABLE api_usage_daily (
+//	    day      DATE        NOT NULL,
+//	    user_id  VARCHAR(64) NOT NULL,
+//	    token    VARCHAR(64) NOT NULL, -- Signing key ID or 'session'
+//	    requests BIGINT      NOT NULL DEFAULT 0,
+//	    rejected BIGINT      NOT NULL DEFAULT 0,
+//	    PRIMARY KEY (day, user_id, token)
+//	);
+//
+//	CREATE TABLE api_quotas (
+//	    key_id     VARCHAR(64) PRIMARY KEY, -- Signing key
+//	    per_minute BIGINT      NOT NULL DEFAULT 0,
+//	    per_day    BIGINT      NOT NULL DEFAULT 0,
+//	    reason     TEXT        NOT NULL,
+//	    updated_by VARCHAR(64) NOT NULL,
+//	    updated_at TIMESTAMPTZ NOT NULL
+//	);
+//
+//	-- One row per key and window kind, reset when the window moves on
+//	CREATE UNLOGGED TABLE api_quota_counters (
+//	    key_id       VARCHAR(64) NOT NULL,
+//	    window_kind  VARCHAR(8)  NOT NULL, -- minute, day
+//	    window_start TIMESTAMPTZ NOT NULL,
+//	    requests     BIGINT      NOT NULL,
+//	    PRIMARY KEY (key_id, window_kind)
+//	);
+//
+// Metering is cheap: calls are counted in memory and added to
+// api_usage_daily in one statement per flush, so usage lags by at most
+// the flush interval and a crash loses at most one interval. Enforcement
+// is exact: calls of a token with a quota increment the shared counters
+// in PostgreSQL, so the quota holds across replicas. UNLOGGED like the
+// nonce cache: a crash only resets the current windows.
+type APIMeter struct {
+	pool *pgxpool.Pool
+
+	mu      sync.Mutex
+	pending map[apiUsageKey]*apiUsageCount
+
+	quotas atomic.Pointer[map[string]domain.APIQuota]
+}
+
+type apiUsageKey struct {
+	day    time.Time
+	userID string
+	token  string
+}
+
+type apiUsageCount struct {
+	requests int64
+	rejected int64
+}
+
+// NewAPIMeter creates a meter on the shared pool. Quotas are loaded by Run.
+func NewAPIMeter(pool *pgxpool.Pool) *APIMeter {
+	m := &APIMeter{pool: pool, pending: make(map[apiUsageKey]*apiUsageCount)}
+	m.quotas.Store(&map[string]domain.APIQuota{})
+	return m
+}
+
+// Record counts one call; rejected calls were refused by the token's quota.
+func (m *APIMeter) Record(now time.Time, userID, token string, rejected bool) {
+	_, day := domain.APIQuotaWindows(now)
+	key := apiUsageKey{day: day, userID: userID, token: token}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.pending[key]
+	if c == nil {
+		c = &apiUsageCount{}
+		m.pending[key] = c
+	}
+	c.requests++
+	if rejected {
+		c.rejected++
+	}
+}
+
+// Quota returns the quota of a signing key, as of the last reload.
+func (m *APIMeter) Quota(keyID string) (domain.APIQuota, bool) {
+	q, ok := (*m.quotas.Load())[keyID]
+	return q, ok
+}
+
+// Consume counts a call against the key's shared windows and returns the
+// counts including it. Rejected calls count too: a script retrying in a
+// loop stays rejected until the window ends.
+//
+// Replica clocks may differ slightly: a window start older than the
+// stored one counts in the stored window instead of resetting it.
+func (m *APIMeter) Consume(ctx context.Context, keyID string, now time.Time) (minute, day int64, err error) {
+	minuteStart, dayStart := domain.APIQuotaWindows(now)
+	rows, err := m.pool.Query(ctx,
+		`INSERT INTO api_quota_counters AS c (key_id, window_kind, window_start, requests)
+		 VALUES ($1, 'minute', $2, 1), ($1, 'day', $3, 1)
+		 ON CONFLICT (key_id, window_kind) DO UPDATE SET
+		     requests     = CASE WHEN EXCLUDED.window_start > c.window_start THEN 1 ELSE c.requests + 1 END,
+		     window_start = GREATEST(c.window_start, EXCLUDED.window_start)
+		 RETURNING window_kind, requests`,
+		keyID, minuteStart, dayStart,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("consume api quota: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var kind string
+		var n int64
+		if err := rows.Scan(&kind, &n); err != nil {
+			return 0, 0, fmt.Errorf("consume api quota: %w", err)
+		}
+		if kind == "minute" {
+			minute = n
+		} else {
+			day = n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("consume api quota: %w", err)
+	}
+	return minute, day, nil
+}
+
+// Run flushes usage and reloads quotas every interval until ctx is done,
+// then flushes once more. interval comes from
+// governance.api_usage_flush_interval; quota changes take effect on every
+// replica within it.
+func (m *APIMeter) Run(ctx context.Context, interval time.Duration) {
+	m.refresh(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Drain: the replica is stopping, keep what was counted
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := m.flush(flushCtx); err != nil {
+				logger.Warn("Final API usage flush failed", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			m.refresh(ctx)
+		}
+	}
+}
+
+func (m *APIMeter) refresh(ctx context.Context) {
+	if err := m.flush(ctx); err != nil {
+		logger.Warn("API usage flush failed", zap.Error(err))
+	}
+	if err := m.loadQuotas(ctx); err != nil {
+		// Keep enforcing the quotas loaded last
+		logger.Warn("API quota reload failed", zap.Error(err))
+	}
+}
+
+// flush adds the pending counts to api_usage_daily. On failure they are
+// put back and retried with the next flush.
+func (m *APIMeter) flush(ctx context.Context) error {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[apiUsageKey]*apiUsageCount)
+	m.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var (
+		days              []time.Time
+		users, tokens     []string
+		requests, rejects []int64
+	)
+	for k, c := range pending {
+		days = append(days, k.day)
+		users = append(users, k.userID)
+		tokens = append(tokens, k.token)
+		requests = append(requests, c.requests)
+		rejects = append(rejects, c.rejected)
+	}
+	_, err := m.pool.Exec(ctx,
+		`INSERT INTO api_usage_daily AS u (day, user_id, token, requests, rejected)
+		 SELECT * FROM unnest($1::date[], $2::text[], $3::text[], $4::bigint[], $5::bigint[])
+		 ON CONFLICT (day, user_id, token) DO UPDATE SET
+		     requests = u.requests + EXCLUDED.requests,
+		     rejected = u.rejected + EXCLUDED.rejected`,
+		days, users, tokens, requests, rejects,
+	)
+	if err != nil {
+		m.mu.Lock()
+		for k, c := range pending {
+			if cur := m.pending[k]; cur != nil {
+				cur.requests += c.requests
+				cur.rejected += c.rejected
+			} else {
+				m.pending[k] = c
+			}
+		}
+		m.mu.Unlock()
+		return fmt.Errorf("flush api usage: %w", err)
+	}
+	return nil
+}
+
+func (m *APIMeter) loadQuotas(ctx context.Context) error {
+	rows, err := m.pool.Query(ctx,
+		`SELECT key_id, per_minute, per_day, reason, updated_by, updated_at FROM api_quotas`)
+	if err != nil {
+		return fmt.Errorf("list api quotas: %w", err)
+	}
+	defer rows.Close()
+	quotas := make(map[string]domain.APIQuota)
+	for rows.Next() {
+		var q domain.APIQuota
+		if err := rows.Scan(&q.KeyID, &q.PerMinute, &q.PerDay, &q.Reason, &q.UpdatedBy, &q.UpdatedAt); err != nil {
+			return fmt.Errorf("scan api quota: %w", err)
+		}
+		quotas[q.KeyID] = q
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list api quotas: %w", err)
+	}
+	m.quotas.Store(&quotas)
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/testutil/factory"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+// startMeter runs m until the returned stop is called; stop waits for the
+// final flush.
+func startMeter(t *testing.T, m *repository.APIMeter, interval time.Duration) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(ctx, interval)
+	}()
+	stop = func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// usage returns the flushed counts of one api_usage_daily row, zeros if
+// there is none.
+func usage(t *testing.T, db *pgtest.DB, day time.Time, userID, token string) (requests, rejected int64) {
+	t.Helper()
+	err := db.Pool.QueryRow(context.Background(),
+		`SELECT requests, rejected FROM api_usage_daily WHERE day = $1 AND user_id = $2 AND token = $3`,
+		day, userID, token,
+	).Scan(&requests, &rejected)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0
+	}
+	require.NoError(t, err)
+	return requests, rejected
+}
+
+func TestAPIMeter_Consume(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.New(t)
+	m := repository.NewAPIMeter(db.Pool)
+	start := factory.Now // 09:00:00 UTC
+
+	consume := func(t *testing.T, keyID string, now time.Time) (int64, int64) {
+		t.Helper()
+		minute, day, err := m.Consume(ctx, keyID, now)
+		require.NoError(t, err)
+		return minute, day
+	}
+	expect := func(t *testing.T, wantMinute, wantDay int64, keyID string, now time.Time) {
+		t.Helper()
+		minute, day := consume(t, keyID, now)
+		require.Equal(t, wantMinute, minute, "minute")
+		require.Equal(t, wantDay, day, "day")
+	}
+
+	t.Run("counts in the current windows", func(t *testing.T) {
+		expect(t, 1, 1, "key-a", start)
+		expect(t, 2, 2, "key-a", start.Add(30*time.Second))
+		expect(t, 3, 3, "key-a", start.Add(59*time.Second))
+	})
+	t.Run("keys are counted apart", func(t *testing.T) {
+		expect(t, 1, 1, "key-b", start.Add(59*time.Second))
+	})
+	t.Run("minute rollover", func(t *testing.T) {
+		expect(t, 1, 4, "key-a", start.Add(time.Minute))
+		expect(t, 2, 5, "key-a", start.Add(time.Minute+time.Second))
+	})
+	t.Run("late replica clock counts in the stored window", func(t *testing.T) {
+		// A call stamped in the previous minute does not reset the window
+		expect(t, 3, 6, "key-a", start.Add(59*time.Second))
+	})
+	t.Run("day rollover", func(t *testing.T) {
+		midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, time.UTC)
+		expect(t, 1, 1, "key-a", midnight)
+		expect(t, 2, 2, "key-a", midnight.Add(time.Second))
+	})
+	t.Run("day is the UTC day", func(t *testing.T) {
+		east := time.FixedZone("UTC+9", 9*3600)
+		// 08:59 UTC+9 the next day is 23:59 UTC on the same UTC day as start
+		late := time.Date(start.Year(), start.Month(), start.Day()+1, 8, 59, 0, 0, east)
+		expect(t, 1, 1, "key-c", start)
+		expect(t, 1, 2, "key-c", late)
+	})
+}
+
+func TestAPIMeter_Record(t *testing.T) {
+	db := pgtest.New(t)
+	m := repository.NewAPIMeter(db.Pool)
+	day := time.Date(factory.Now.Year(), factory.Now.Month(), factory.Now.Day(), 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	m.Record(factory.Now, "alice", "key-1", false)
+	m.Record(factory.Now.Add(time.Minute), "alice", "key-1", false)
+	m.Record(factory.Now.Add(2*time.Minute), "alice", "key-1", true)
+	m.Record(factory.Now, "alice", "session", false)
+	m.Record(factory.Now, "bob", "key-1", false)
+	m.Record(nextDay.Add(time.Second), "alice", "key-1", true) // Past midnight UTC: the next day's row
+
+	stop := startMeter(t, m, time.Hour)
+	stop() // Final flush
+
+	cases := []struct {
+		day                        time.Time
+		user, token                string
+		wantRequests, wantRejected int64
+	}{
+		{day, "alice", "key-1", 3, 1},
+		{day, "alice", "session", 1, 0},
+		{day, "bob", "key-1", 1, 0},
+		{nextDay, "alice", "key-1", 1, 1},
+	}
+	for _, c := range cases {
+		requests, rejected := usage(t, db, c.day, c.user, c.token)
+		require.Equal(t, c.wantRequests, requests, "%s %s %s requests", c.day.Format(time.DateOnly), c.user, c.token)
+		require.Equal(t, c.wantRejected, rejected, "%s %s %s rejected", c.day.Format(time.DateOnly), c.user, c.token)
+	}
+
+	t.Run("later flushes add to the row", func(t *testing.T) {
+		m.Record(factory.Now, "alice", "key-1", false)
+		stop := startMeter(t, m, time.Hour)
+		stop()
+		requests, rejected := usage(t, db, day, "alice", "key-1")
+		require.EqualValues(t, 4, requests)
+		require.EqualValues(t, 1, rejected)
+	})
+}
+
+func TestAPIMeter_Quota(t *testing.T) {
+	db := pgtest.New(t)
+	m := repository.NewAPIMeter(db.Pool)
+
+	_, err := db.Pool.Exec(context.Background(),
+		`INSERT INTO api_quotas (key_id, per_minute, per_day, reason, updated_by, updated_at)
+		 VALUES ('key-1', 60, 10000, 'ci runner', 'admin', $1)`, factory.Now)
+	require.NoError(t, err)
+
+	_, ok := m.Quota("key-1")
+	require.False(t, ok, "quotas are loaded by Run")
+
+	stop := startMeter(t, m, time.Hour)
+	require.Eventually(t, func() bool { _, ok := m.Quota("key-1"); return ok }, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	q, _ := m.Quota("key-1")
+	require.EqualValues(t, 60, q.PerMinute)
+	require.EqualValues(t, 10000, q.PerDay)
+	require.Equal(t, "ci runner", q.Reason)
+	_, ok = m.Quota("key-2")
+	require.False(t, ok)
+}
+
+func TestAPIMeter_Run(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.New(t)
+	m := repository.NewAPIMeter(db.Pool)
+	day := time.Date(factory.Now.Year(), factory.Now.Month(), factory.Now.Day(), 0, 0, 0, 0, time.UTC)
+
+	startMeter(t, m, 50*time.Millisecond)
+
+	t.Run("flushes every interval", func(t *testing.T) {
+		m.Record(factory.Now, "alice", "key-1", false)
+		require.Eventually(t, func() bool {
+			requests, _ := usage(t, db, day, "alice", "key-1")
+			return requests == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+	t.Run("quota changes apply within an interval", func(t *testing.T) {
+		_, err := db.Pool.Exec(ctx,
+			`INSERT INTO api_quotas (key_id, per_minute, per_day, reason, updated_by, updated_at)
+			 VALUES ('key-1', 10, 100, 'limit', 'admin', $1)`, factory.Now)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { _, ok := m.Quota("key-1"); return ok }, 5*time.Second, 10*time.Millisecond)
+
+		_, err = db.Pool.Exec(ctx, `DELETE FROM api_quotas WHERE key_id = 'key-1'`)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { _, ok := m.Quota("key-1"); return !ok }, 5*time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// RecordBatchItemResult updates the parent of a batch child when the
+// child's event reaches a terminal status. DomainEventRepository calls it
+// in the transaction that moves the event to COMPLETED or FAILED, so the
+// parent counters (the batch progress record) never disagree with the
+// children.
+//
+// Idempotent: the child ticket moves to SUCCESS/FAILED only from
+// APPROVED/EXECUTING, so a redelivered completion does not count twice.
+// Events without a batch parent are a no-op.
+func RecordBatchItemResult(ctx context.Context, tx pgx.Tx, eventID string, succeeded bool) error {
+	status := domain.TicketFailed
+	if succeeded {
+		status = domain.TicketSuccess
+	}
+
+	var parentID string
+	err := tx.QueryRow(ctx,
+		`UPDATE approval_tickets SET status = $2, updated_at = now()
+		 WHERE event_id = $1 AND parent_ticket_id IS NOT NULL AND status IN ('APPROVED', 'EXECUTING')
+		 RETURNING parent_ticket_id`,
+		eventID, string(status),
+	).Scan(&parentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finish batch child: %w", err)
+	}
+
+	success, failed := 0, 1
+	if succeeded {
+		success, failed = 1, 0
+	}
+	var t domain.BatchApprovalTicket
+	err = tx.QueryRow(ctx,
+		`UPDATE batch_approval_tickets
+		 SET success_count = success_count + $2, failed_count = failed_count + $3, pending_count = pending_count - 1
+		 WHERE ticket_id = $1
+		 RETURNING event_id, success_count, failed_count, pending_count`,
+		parentID, success, failed,
+	).Scan(&t.EventID, &t.SuccessCount, &t.FailedCount, &t.PendingCount)
+	if err != nil {
+		return fmt.Errorf("update batch progress: %w", err)
+	}
+	if t.PendingCount > 0 {
+		return nil
+	}
+
+	// Last child: the parent and its event become terminal.
+	// Children are all decided here, since none is pending.
+	batchStatus := t.CalculateStatus(0)
+	eventStatus := domain.EventStatusCompleted
+	if batchStatus == domain.BatchFailed {
+		eventStatus = domain.EventStatusFailed
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE batch_approval_tickets SET status = $2 WHERE ticket_id = $1`,
+		parentID, string(batchStatus),
+	); err != nil {
+		return fmt.Errorf("update batch status: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE domain_events SET status = $2 WHERE event_id = $1`,
+		t.EventID, string(eventStatus),
+	); err != nil {
+		return fmt.Errorf("update batch event: %w", err)
+	}
+	return nil
+}
+
+// RecordBatchItemPlacement records where a batch child was created: the
+// cluster, and the node its VMI was first scheduled to (empty while the
+// VM has not run). The creation worker calls it in the TX that completes
+// the child's event, and again with the node once the VMI is scheduled;
+// the first node recorded is kept, so a later live migration does not
+// rewrite the placement the batch was checked against.
+// Events without a batch parent are a no-op.
+func RecordBatchItemPlacement(ctx context.Context, tx pgx.Tx, eventID, cluster, node string) error {
+	if _, err := tx.Exec(ctx,
+		`UPDATE approval_tickets
+		 SET placed_cluster = $2, placed_node = COALESCE(placed_node, NULLIF($3, ''))
+		 WHERE event_id = $1 AND parent_ticket_id IS NOT NULL`,
+		eventID, cluster, node,
+	); err != nil {
+		return fmt.Errorf("record batch item placement: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// CatalogTable is a catalog cached by CatalogCache. Invalidation is per
+// table: catalogs are small and change rarely, so reloading a whole table
+// after a change is cheaper than tracking which keys (ID, name, active
+// version) a row was cached under.
+type CatalogTable string
+
+const (
+	CatalogInstanceSizes       CatalogTable = "instance_sizes"
+	CatalogTemplates           CatalogTable = "templates"
+	CatalogClusters            CatalogTable = "clusters"
+	CatalogEnvironmentPolicies CatalogTable = "environment_policies"
+	CatalogServiceDefaults     CatalogTable = "service_request_defaults" // Read by the request form
+	CatalogNamespaces          CatalogTable = "namespace_registry"       // Read by the request form
+)
+
+var catalogTables = []CatalogTable{
+	CatalogInstanceSizes, CatalogTemplates, CatalogClusters, CatalogEnvironmentPolicies,
+	CatalogServiceDefaults, CatalogNamespaces,
+}
+
+// CatalogChannel is the LISTEN/NOTIFY channel; the payload is
+// "<CatalogTable>:<version>".
+const CatalogChannel = "shepherd_catalog_changed"
+
+// maxHotKeys bounds the keys per table re-warmed after a change.
+const maxHotKeys = 1000
+
+// CatalogCache is an in-process read-through cache for catalog reads on
+// the request validation path.
+//
+// Consistency across replicas: every catalog write calls
+// NotifyCatalogChanged in its transaction; PostgreSQL delivers the
+// notification on commit to every replica's listener (including the
+// writer's), which bumps the table generation. Keys embed the generation,
+// so a load that started before the change is stored under a dead key and
+// never served. TTL bounds staleness if a notification is lost without
+// the listener noticing.
+//
+// Versions: each write also bumps the table's row in catalog_versions, so
+// unlike the per-replica generations, versions are the same on every
+// replica and can make up ETags (Version, ETag).
+//
+// Warming: keys served since the last change of a table are remembered
+// with their loader, and RunCatalogWarmer reloads them after the next
+// change, so the first request after a catalog edit does not pay for the
+// rebuild.
+//
+// Cached values are shared between requests and MUST be treated as
+// read-only by callers.
+type CatalogCache struct {
+	cache    *ristretto.Cache[string, any]
+	ttl      time.Duration
+	gens     map[CatalogTable]*atomic.Uint64
+	versions map[CatalogTable]*atomic.Int64
+
+	mu    sync.Mutex
+	hot   map[CatalogTable]map[string]func(context.Context) error
+	dirty map[CatalogTable]bool
+	wake  chan struct{}
+}
+
+// NewCatalogCache creates a cache holding up to maxEntries values.
+func NewCatalogCache(maxEntries int64, ttl time.Duration) (*CatalogCache, error) {
+	cache, err := ristretto.NewCache(&ristretto.Config[string, any]{
+		NumCounters: maxEntries * 10, // ristretto recommendation
+		MaxCost:     maxEntries,      // Cost 1 per entry
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create catalog cache: %w", err)
+	}
+	c := &CatalogCache{
+		cache:    cache,
+		ttl:      ttl,
+		gens:     make(map[CatalogTable]*atomic.Uint64, len(catalogTables)),
+		versions: make(map[CatalogTable]*atomic.Int64, len(catalogTables)),
+		hot:      make(map[CatalogTable]map[string]func(context.Context) error, len(catalogTables)),
+		dirty:    make(map[CatalogTable]bool, len(catalogTables)),
+		wake:     make(chan struct{}, 1),
+	}
+	for _, t := range catalogTables {
+		c.gens[t] = new(atomic.Uint64)
+		c.versions[t] = new(atomic.Int64)
+	}
+	return c, nil
+}
+
+// Invalidate drops every cached value of table and schedules its hot
+// keys for warming.
+func (c *CatalogCache) Invalidate(table CatalogTable) {
+	c.gens[table].Add(1)
+	c.markDirty(table)
+}
+
+// InvalidateAll drops everything, after the listener may have missed
+// notifications.
+func (c *CatalogCache) InvalidateAll() {
+	for t, g := range c.gens {
+		g.Add(1)
+		c.markDirty(t)
+	}
+}
+
+// Version returns the cluster-wide version of table, as last seen by
+// this replica's listener.
+func (c *CatalogCache) Version(table CatalogTable) int64 {
+	return c.versions[table].Load()
+}
+
+// ETag returns a weak ETag over the versions of tables, for a response
+// built from them. Read it BEFORE loading the response: a change landing
+// in between then yields an old tag on a new body (one extra 200 later),
+// never a new tag on an old body.
+func (c *CatalogCache) ETag(tables ...CatalogTable) string {
+	parts := make([]string, len(tables))
+	for i, t := range tables {
+		parts[i] = strconv.FormatInt(c.Version(t), 10)
+	}
+	return `W/"` + strings.Join(parts, ".") + `"`
+}
+
+// setVersion records a version seen in a notification or loaded on
+// connect. Versions only grow: a late notification is ignored.
+func (c *CatalogCache) setVersion(table CatalogTable, version int64) {
+	v := c.versions[table]
+	for {
+		old := v.Load()
+		if version <= old || v.CompareAndSwap(old, version) {
+			return
+		}
+	}
+}
+
+// cachedLoad returns the cached value for (table, key) or calls load.
+// Errors, including ErrNotFound, are not cached: a row created after a
+// miss must be visible on the next request.
+func cachedLoad[T any](ctx context.Context, c *CatalogCache, table CatalogTable, key string, load func(context.Context) (T, error)) (T, error) {
+	return LoadCached(ctx, c, []CatalogTable{table}, key, load)
+}
+
+// LoadCached is cachedLoad for a value built from several tables, e.g. a
+// request form: it is dropped, and re-warmed, when any of them changes.
+// key must be unique across callers.
+func LoadCached[T any](ctx context.Context, c *CatalogCache, tables []CatalogTable, key string, load func(context.Context) (T, error)) (T, error) {
+	var k strings.Builder
+	for _, t := range tables {
+		fmt.Fprintf(&k, "%s/%d/", t, c.gens[t].Load())
+	}
+	k.WriteString(key)
+
+	if v, ok := c.cache.Get(k.String()); ok {
+		return v.(T), nil
+	}
+	v, err := load(ctx)
+	if err != nil {
+		return v, err
+	}
+	c.cache.SetWithTTL(k.String(), v, 1, c.ttl)
+	c.remember(tables, key, func(ctx context.Context) error {
+		_, err := LoadCached(ctx, c, tables, key, load)
+		return err
+	})
+	return v, nil
+}
+
+// NotifyCatalogChanged bumps table's version and queues its invalidation
+// on all replicas. Call it in the writing transaction: the version and
+// the notification only take effect if the transaction commits.
+func NotifyCatalogChanged(ctx context.Context, tx pgx.Tx, table CatalogTable) error {
+	const q = `WITH v AS (
+		UPDATE catalog_versions SET version = version + 1 WHERE catalog = $2 RETURNING version
+	) SELECT pg_notify($1, $2 || ':' || version) FROM v`
+	if _, err := tx.Exec(ctx, q, CatalogChannel, string(table)); err != nil {
+		return fmt.Errorf("notify catalog change: %w", err)
+	}
+	return nil
+}
+
+// Listener reconnect backoff.
+const (
+	listenRetryMin = time.Second
+	listenRetryMax = 30 * time.Second
+)
+
+// RunCatalogListener applies invalidations from other replicas until ctx
+// is done. It holds one connection taken out of pool (LISTEN state must
+// not leak back into it); pass DatabaseClients.GetWorkerPool(), as LISTEN
+// does not work through PgBouncer in transaction pooling. After any connection loss the whole
+// cache is dropped, since notifications sent meanwhile are lost.
+func RunCatalogListener(ctx context.Context, pool *pgxpool.Pool, cache *CatalogCache) {
+	backoff := listenRetryMin
+	for ctx.Err() == nil {
+		err := listenCatalog(ctx, pool, cache, func() { backoff = listenRetryMin })
+		if ctx.Err() != nil {
+			return
+		}
+		cache.InvalidateAll()
+		logger.Warn("Catalog cache listener disconnected, cache flushed",
+			zap.Duration("retry_in", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenRetryMax)
+	}
+}
+
+func listenCatalog(ctx context.Context, pool *pgxpool.Pool, cache *CatalogCache, connected func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+CatalogChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	// Changes committed while disconnected were never delivered
+	if err := loadCatalogVersions(ctx, conn, cache); err != nil {
+		return err
+	}
+	cache.InvalidateAll()
+	connected()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		name, version, _ := strings.Cut(n.Payload, ":")
+		table := CatalogTable(name)
+		if _, ok := cache.gens[table]; !ok {
+			logger.Warn("Unknown catalog in invalidation", zap.String("table", n.Payload))
+			cache.InvalidateAll()
+			continue
+		}
+		if v, err := strconv.ParseInt(version, 10, 64); err == nil {
+			cache.setVersion(table, v)
+		}
+		cache.Invalidate(table)
+	}
+}
+
+// loadCatalogVersions reads every table's version, after a (re)connect.
+func loadCatalogVersions(ctx context.Context, conn *pgx.Conn, cache *CatalogCache) error {
+	rows, err := conn.Query(ctx, `SELECT catalog, version FROM catalog_versions`)
+	if err != nil {
+		return fmt.Errorf("load catalog versions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var version int64
+		if err := rows.Scan(&name, &version); err != nil {
+			return fmt.Errorf("scan catalog version: %w", err)
+		}
+		if _, ok := cache.versions[CatalogTable(name)]; ok {
+			cache.setVersion(CatalogTable(name), version)
+		}
+	}
+	return rows.Err()
+}
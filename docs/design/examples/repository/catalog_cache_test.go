@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalogCache(t *testing.T) *CatalogCache {
+	t.Helper()
+	c, err := NewCatalogCache(1000, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(c.cache.Close)
+	return c
+}
+
+// loadCounter is a loader that counts its calls and returns the call
+// number, so a test can tell a cached value from a reload.
+type loadCounter struct {
+	calls int
+	err   error
+}
+
+func (l *loadCounter) load(ctx context.Context) (int, error) {
+	l.calls++
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.calls, nil
+}
+
+// cachedGet reads key through the cache and waits for ristretto's
+// asynchronous set, so the next read sees the stored value.
+func cachedGet(t *testing.T, c *CatalogCache, table CatalogTable, key string, l *loadCounter) int {
+	t.Helper()
+	v, err := cachedLoad(context.Background(), c, table, key, l.load)
+	require.NoError(t, err)
+	c.cache.Wait()
+	return v
+}
+
+func TestCatalogCache_Invalidate(t *testing.T) {
+	c := newTestCatalogCache(t)
+	var sizes, templates loadCounter
+
+	require.Equal(t, 1, cachedGet(t, c, CatalogInstanceSizes, "enabled", &sizes), "miss loads")
+	require.Equal(t, 1, cachedGet(t, c, CatalogInstanceSizes, "enabled", &sizes), "hit is served from the cache")
+	require.Equal(t, 1, cachedGet(t, c, CatalogTemplates, "enabled", &templates))
+
+	c.Invalidate(CatalogInstanceSizes)
+	require.Equal(t, 2, cachedGet(t, c, CatalogInstanceSizes, "enabled", &sizes), "invalidated table reloads")
+	require.Equal(t, 1, cachedGet(t, c, CatalogTemplates, "enabled", &templates), "other tables keep their values")
+}
+
+func TestCatalogCache_InvalidateAll(t *testing.T) {
+	c := newTestCatalogCache(t)
+	counters := map[CatalogTable]*loadCounter{}
+	for _, table := range catalogTables {
+		counters[table] = &loadCounter{}
+		require.Equal(t, 1, cachedGet(t, c, table, "k", counters[table]))
+	}
+
+	c.InvalidateAll()
+	for _, table := range catalogTables {
+		require.Equal(t, 2, cachedGet(t, c, table, "k", counters[table]), table)
+	}
+}
+
+func TestCachedLoad_ErrorsNotCached(t *testing.T) {
+	c := newTestCatalogCache(t)
+	l := &loadCounter{err: ErrNotFound}
+
+	_, err := cachedLoad(context.Background(), c, CatalogClusters, "id:c-1", l.load)
+	require.ErrorIs(t, err, ErrNotFound)
+	c.cache.Wait()
+
+	// The row was created after the miss: the next read must see it
+	l.err = nil
+	require.Equal(t, 2, cachedGet(t, c, CatalogClusters, "id:c-1", l))
+}
+
+func TestCachedLoad_StaleLoadNotServed(t *testing.T) {
+	c := newTestCatalogCache(t)
+	var calls int
+	// The table changes while the value is being loaded: the value is
+	// stored under the old generation and never served.
+	stale := func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			c.Invalidate(CatalogTemplates)
+		}
+		return calls, nil
+	}
+	for want := 1; want <= 2; want++ {
+		v, err := cachedLoad(context.Background(), c, CatalogTemplates, "active:ubuntu", stale)
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+		c.cache.Wait()
+	}
+	v, err := cachedLoad(context.Background(), c, CatalogTemplates, "active:ubuntu", stale)
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+}
+
+func TestCachedLoad_DifferentKeys(t *testing.T) {
+	c := newTestCatalogCache(t)
+	var small, large loadCounter
+	require.Equal(t, 1, cachedGet(t, c, CatalogInstanceSizes, "name:small", &small))
+	require.Equal(t, 1, cachedGet(t, c, CatalogInstanceSizes, "name:large", &large))
+	require.Equal(t, 1, small.calls)
+	require.Equal(t, 1, large.calls)
+}
+
+func TestCatalogCache_Version(t *testing.T) {
+	c := newTestCatalogCache(t)
+	require.Zero(t, c.Version(CatalogTemplates))
+
+	c.setVersion(CatalogTemplates, 5)
+	require.EqualValues(t, 5, c.Version(CatalogTemplates))
+
+	// A late notification never moves the version back
+	c.setVersion(CatalogTemplates, 3)
+	require.EqualValues(t, 5, c.Version(CatalogTemplates))
+	require.Zero(t, c.Version(CatalogInstanceSizes))
+}
+
+func TestCatalogCache_ETag(t *testing.T) {
+	c := newTestCatalogCache(t)
+	require.Equal(t, `W/"0.0"`, c.ETag(CatalogServiceDefaults, CatalogTemplates))
+
+	c.setVersion(CatalogServiceDefaults, 4)
+	c.setVersion(CatalogTemplates, 7)
+	require.Equal(t, `W/"4.7"`, c.ETag(CatalogServiceDefaults, CatalogTemplates))
+	require.Equal(t, `W/"7.4"`, c.ETag(CatalogTemplates, CatalogServiceDefaults), "one part per table, in the order given")
+
+	// Invalidation alone does not change the tag: only versions do
+	c.Invalidate(CatalogTemplates)
+	require.Equal(t, `W/"4.7"`, c.ETag(CatalogServiceDefaults, CatalogTemplates))
+}
+
+func TestLoadCached(t *testing.T) {
+	ctx := context.Background()
+	tables := []CatalogTable{CatalogServiceDefaults, CatalogTemplates, CatalogInstanceSizes}
+	c := newTestCatalogCache(t)
+	var form loadCounter
+	get := func() int {
+		t.Helper()
+		v, err := LoadCached(ctx, c, tables, "form:svc-1", form.load)
+		require.NoError(t, err)
+		c.cache.Wait()
+		return v
+	}
+
+	require.Equal(t, 1, get(), "miss")
+	require.Equal(t, 1, get(), "hit")
+
+	c.Invalidate(CatalogClusters)
+	require.Equal(t, 1, get(), "a table the value does not depend on changed")
+
+	for i, table := range tables {
+		c.Invalidate(table)
+		require.Equal(t, i+2, get(), "%s changed", table)
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// Read-through decorators for the catalog repositories. Only the reads on
+// the request validation path are cached; every other method passes
+// through to the embedded repository. Writes are not wrapped: the write
+// methods call NotifyCatalogChanged in their transaction, which reaches
+// this replica's cache through RunCatalogListener like any other.
+//
+// Wired in the composition root when cache.catalog.enabled, into the
+// services and handlers that validate requests. Use cases and jobs that
+// lock and update catalog rows (decommission, capability detection) keep
+// the uncached repository:
+//
+//	cachedSizes := repository.NewCachedInstanceSizeRepository(sizeRepo, catalogCache)
+//	defaults := service.NewRequestDefaultsService(defaultsRepo, cachedTemplates, cachedSizes, namespaceRepo)
+
+// CachedInstanceSizeRepository caches InstanceSize lookups.
+type CachedInstanceSizeRepository struct {
+	InstanceSizeRepository
+	cache *CatalogCache
+}
+
+// NewCachedInstanceSizeRepository wraps next.
+func NewCachedInstanceSizeRepository(next InstanceSizeRepository, cache *CatalogCache) *CachedInstanceSizeRepository {
+	return &CachedInstanceSizeRepository{InstanceSizeRepository: next, cache: cache}
+}
+
+// GetByName returns the InstanceSize named name.
+func (r *CachedInstanceSizeRepository) GetByName(ctx context.Context, name string) (*domain.InstanceSize, error) {
+	return cachedLoad(ctx, r.cache, CatalogInstanceSizes, "name:"+name, func(ctx context.Context) (*domain.InstanceSize, error) {
+		return r.InstanceSizeRepository.GetByName(ctx, name)
+	})
+}
+
+// ListEnabled returns the enabled InstanceSizes.
+func (r *CachedInstanceSizeRepository) ListEnabled(ctx context.Context) ([]*domain.InstanceSize, error) {
+	return cachedLoad(ctx, r.cache, CatalogInstanceSizes, "enabled", r.InstanceSizeRepository.ListEnabled)
+}
+
+// CachedTemplateRepository caches template lookups.
+type CachedTemplateRepository struct {
+	TemplateRepository
+	cache *CatalogCache
+}
+
+// NewCachedTemplateRepository wraps next.
+func NewCachedTemplateRepository(next TemplateRepository, cache *CatalogCache) *CachedTemplateRepository {
+	return &CachedTemplateRepository{TemplateRepository: next, cache: cache}
+}
+
+// Get returns the template version with the given ID.
+func (r *CachedTemplateRepository) Get(ctx context.Context, id string) (*domain.Template, error) {
+	return cachedLoad(ctx, r.cache, CatalogTemplates, "id:"+id, func(ctx context.Context) (*domain.Template, error) {
+		return r.TemplateRepository.Get(ctx, id)
+	})
+}
+
+// GetActive returns the active version of the template named name.
+func (r *CachedTemplateRepository) GetActive(ctx context.Context, name string) (*domain.Template, error) {
+	return cachedLoad(ctx, r.cache, CatalogTemplates, "active:"+name, func(ctx context.Context) (*domain.Template, error) {
+		return r.TemplateRepository.GetActive(ctx, name)
+	})
+}
+
+// CachedClusterRepository caches cluster and capability lookups.
+type CachedClusterRepository struct {
+	ClusterRepository
+	cache *CatalogCache
+}
+
+// NewCachedClusterRepository wraps next.
+func NewCachedClusterRepository(next ClusterRepository, cache *CatalogCache) *CachedClusterRepository {
+	return &CachedClusterRepository{ClusterRepository: next, cache: cache}
+}
+
+// Get returns the cluster with the given ID.
+func (r *CachedClusterRepository) Get(ctx context.Context, id string) (*domain.Cluster, error) {
+	return cachedLoad(ctx, r.cache, CatalogClusters, "id:"+id, func(ctx context.Context) (*domain.Cluster, error) {
+		return r.ClusterRepository.Get(ctx, id)
+	})
+}
+
+// GetCapabilities returns the detected capabilities of cluster.
+func (r *CachedClusterRepository) GetCapabilities(ctx context.Context, cluster string) (*domain.ClusterCapabilities, error) {
+	return cachedLoad(ctx, r.cache, CatalogClusters, "caps:"+cluster, func(ctx context.Context) (*domain.ClusterCapabilities, error) {
+		return r.ClusterRepository.GetCapabilities(ctx, cluster)
+	})
+}
+
+// CachedEnvironmentPolicyRepository caches environment policies.
+type CachedEnvironmentPolicyRepository struct {
+	EnvironmentPolicyRepository
+	cache *CatalogCache
+}
+
+// NewCachedEnvironmentPolicyRepository wraps next.
+func NewCachedEnvironmentPolicyRepository(next EnvironmentPolicyRepository, cache *CatalogCache) *CachedEnvironmentPolicyRepository {
+	return &CachedEnvironmentPolicyRepository{EnvironmentPolicyRepository: next, cache: cache}
+}
+
+// Get returns the policy of env.
+func (r *CachedEnvironmentPolicyRepository) Get(ctx context.Context, env domain.DeploymentEnvironment) (*domain.EnvironmentPolicy, error) {
+	return cachedLoad(ctx, r.cache, CatalogEnvironmentPolicies, string(env), func(ctx context.Context) (*domain.EnvironmentPolicy, error) {
+		return r.EnvironmentPolicyRepository.Get(ctx, env)
+	})
+}
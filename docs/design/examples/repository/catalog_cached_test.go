@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// Stub repositories: each method counts its calls and returns a new
+// value per call, or ErrNotFound for the name "missing". Methods the
+// decorators do not cache are left to the nil embedded interface.
+
+type stubInstanceSizes struct {
+	InstanceSizeRepository
+	calls int
+}
+
+func (s *stubInstanceSizes) GetByName(ctx context.Context, name string) (*domain.InstanceSize, error) {
+	s.calls++
+	if name == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.InstanceSize{Name: name, Version: s.calls}, nil
+}
+
+func (s *stubInstanceSizes) ListEnabled(ctx context.Context) ([]*domain.InstanceSize, error) {
+	s.calls++
+	return []*domain.InstanceSize{{Name: "small", Version: s.calls}}, nil
+}
+
+type stubTemplates struct {
+	TemplateRepository
+	calls int
+}
+
+func (s *stubTemplates) Get(ctx context.Context, id string) (*domain.Template, error) {
+	s.calls++
+	if id == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.Template{ID: id, Version: s.calls}, nil
+}
+
+func (s *stubTemplates) GetActive(ctx context.Context, name string) (*domain.Template, error) {
+	s.calls++
+	if name == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.Template{Name: name, Version: s.calls}, nil
+}
+
+type stubClusters struct {
+	ClusterRepository
+	calls int
+}
+
+func (s *stubClusters) Get(ctx context.Context, id string) (*domain.Cluster, error) {
+	s.calls++
+	if id == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.Cluster{ID: id}, nil
+}
+
+func (s *stubClusters) GetCapabilities(ctx context.Context, cluster string) (*domain.ClusterCapabilities, error) {
+	s.calls++
+	if cluster == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.ClusterCapabilities{KubeVirtVersion: "v1.7.0"}, nil
+}
+
+type stubEnvironmentPolicies struct {
+	EnvironmentPolicyRepository
+	calls int
+}
+
+func (s *stubEnvironmentPolicies) Get(ctx context.Context, env domain.DeploymentEnvironment) (*domain.EnvironmentPolicy, error) {
+	s.calls++
+	if env == "missing" {
+		return nil, ErrNotFound
+	}
+	return &domain.EnvironmentPolicy{Environment: env, RequiredApprovals: s.calls}, nil
+}
+
+// checkReadThrough reads key through get: a miss loads from the
+// repository, a repeat is served from the cache (the same value), and a
+// change to table loads again. calls is the repository's call counter.
+func checkReadThrough[T any](t *testing.T, c *CatalogCache, table CatalogTable, calls *int, get func() (*T, error)) {
+	t.Helper()
+
+	first, err := get()
+	require.NoError(t, err)
+	require.Equal(t, 1, *calls, "miss")
+	c.cache.Wait()
+
+	hit, err := get()
+	require.NoError(t, err)
+	require.Equal(t, 1, *calls, "hit")
+	require.Same(t, first, hit, "hit returns the cached value")
+
+	c.Invalidate(table)
+	reloaded, err := get()
+	require.NoError(t, err)
+	require.Equal(t, 2, *calls, "miss after invalidation")
+	require.NotSame(t, first, reloaded)
+}
+
+// checkNotFoundNotCached requires every read of a missing row to reach
+// the repository.
+func checkNotFoundNotCached[T any](t *testing.T, c *CatalogCache, calls *int, get func() (T, error)) {
+	t.Helper()
+	for want := 1; want <= 2; want++ {
+		_, err := get()
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Equal(t, want, *calls)
+		c.cache.Wait()
+	}
+}
+
+func TestCachedInstanceSizeRepository_GetByName(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubInstanceSizes{}
+		r := NewCachedInstanceSizeRepository(next, c)
+		checkReadThrough(t, c, CatalogInstanceSizes, &next.calls, func() (*domain.InstanceSize, error) {
+			return r.GetByName(ctx, "small")
+		})
+	})
+	t.Run("keyed by name", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubInstanceSizes{}
+		r := NewCachedInstanceSizeRepository(next, c)
+		small, err := r.GetByName(ctx, "small")
+		require.NoError(t, err)
+		c.cache.Wait()
+		large, err := r.GetByName(ctx, "large")
+		require.NoError(t, err)
+		require.Equal(t, 2, next.calls)
+		require.Equal(t, "small", small.Name)
+		require.Equal(t, "large", large.Name)
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubInstanceSizes{}
+		r := NewCachedInstanceSizeRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.InstanceSize, error) {
+			return r.GetByName(ctx, "missing")
+		})
+	})
+	t.Run("other table changed", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubInstanceSizes{}
+		r := NewCachedInstanceSizeRepository(next, c)
+		_, err := r.GetByName(ctx, "small")
+		require.NoError(t, err)
+		c.cache.Wait()
+		c.Invalidate(CatalogTemplates)
+		_, err = r.GetByName(ctx, "small")
+		require.NoError(t, err)
+		require.Equal(t, 1, next.calls)
+	})
+}
+
+func TestCachedInstanceSizeRepository_ListEnabled(t *testing.T) {
+	c, next := newTestCatalogCache(t), &stubInstanceSizes{}
+	r := NewCachedInstanceSizeRepository(next, c)
+	checkReadThrough(t, c, CatalogInstanceSizes, &next.calls, func() (*domain.InstanceSize, error) {
+		sizes, err := r.ListEnabled(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return sizes[0], nil
+	})
+}
+
+func TestCachedTemplateRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubTemplates{}
+		r := NewCachedTemplateRepository(next, c)
+		checkReadThrough(t, c, CatalogTemplates, &next.calls, func() (*domain.Template, error) {
+			return r.Get(ctx, "tpl-1")
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubTemplates{}
+		r := NewCachedTemplateRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.Template, error) {
+			return r.Get(ctx, "missing")
+		})
+	})
+}
+
+func TestCachedTemplateRepository_GetActive(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubTemplates{}
+		r := NewCachedTemplateRepository(next, c)
+		checkReadThrough(t, c, CatalogTemplates, &next.calls, func() (*domain.Template, error) {
+			return r.GetActive(ctx, "ubuntu")
+		})
+	})
+	t.Run("not confused with Get", func(t *testing.T) {
+		// Template IDs and names share a key space only through the prefix
+		c, next := newTestCatalogCache(t), &stubTemplates{}
+		r := NewCachedTemplateRepository(next, c)
+		_, err := r.Get(ctx, "ubuntu")
+		require.NoError(t, err)
+		c.cache.Wait()
+		active, err := r.GetActive(ctx, "ubuntu")
+		require.NoError(t, err)
+		require.Equal(t, 2, next.calls)
+		require.Equal(t, "ubuntu", active.Name)
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubTemplates{}
+		r := NewCachedTemplateRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.Template, error) {
+			return r.GetActive(ctx, "missing")
+		})
+	})
+}
+
+func TestCachedClusterRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubClusters{}
+		r := NewCachedClusterRepository(next, c)
+		checkReadThrough(t, c, CatalogClusters, &next.calls, func() (*domain.Cluster, error) {
+			return r.Get(ctx, "c-1")
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubClusters{}
+		r := NewCachedClusterRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.Cluster, error) {
+			return r.Get(ctx, "missing")
+		})
+	})
+}
+
+func TestCachedClusterRepository_GetCapabilities(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubClusters{}
+		r := NewCachedClusterRepository(next, c)
+		checkReadThrough(t, c, CatalogClusters, &next.calls, func() (*domain.ClusterCapabilities, error) {
+			return r.GetCapabilities(ctx, "c-1")
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubClusters{}
+		r := NewCachedClusterRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.ClusterCapabilities, error) {
+			return r.GetCapabilities(ctx, "missing")
+		})
+	})
+}
+
+func TestCachedEnvironmentPolicyRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read-through", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubEnvironmentPolicies{}
+		r := NewCachedEnvironmentPolicyRepository(next, c)
+		checkReadThrough(t, c, CatalogEnvironmentPolicies, &next.calls, func() (*domain.EnvironmentPolicy, error) {
+			return r.Get(ctx, domain.EnvironmentProd)
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		c, next := newTestCatalogCache(t), &stubEnvironmentPolicies{}
+		r := NewCachedEnvironmentPolicyRepository(next, c)
+		checkNotFoundNotCached(t, c, &next.calls, func() (*domain.EnvironmentPolicy, error) {
+			return r.Get(ctx, "missing")
+		})
+	})
+}
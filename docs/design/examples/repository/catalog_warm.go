@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// Cache warming after catalog changes. A change drops the whole table
+// (catalog_cache.go), which on its own would make the next request of
+// every user rebuild its values: the enabled sizes, the active templates,
+// each Service's request form. Instead the keys served since the previous
+// change are reloaded in the background right after the invalidation, on
+// every replica, since each replica's listener receives the change.
+//
+// Only keys that were actually requested are warmed, at most maxHotKeys
+// per table: a Service whose form nobody opened is not rebuilt.
+
+// remember records the loader of a key just stored, under each table it
+// depends on.
+func (c *CatalogCache) remember(tables []CatalogTable, key string, reload func(context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range tables {
+		hot := c.hot[t]
+		if hot == nil {
+			hot = make(map[string]func(context.Context) error)
+			c.hot[t] = hot
+		}
+		if _, ok := hot[key]; ok || len(hot) < maxHotKeys {
+			hot[key] = reload
+		}
+	}
+}
+
+// markDirty schedules table's hot keys for warming. Several changes
+// before the warmer runs are warmed once.
+func (c *CatalogCache) markDirty(table CatalogTable) {
+	c.mu.Lock()
+	c.dirty[table] = true
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default: // Already woken
+	}
+}
+
+// takeDirty returns the loaders of the hot keys of every dirty table,
+// once each, and forgets them: they register again when reloaded.
+func (c *CatalogCache) takeDirty() map[string]func(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	loaders := make(map[string]func(context.Context) error)
+	for t := range c.dirty {
+		for key, reload := range c.hot[t] {
+			loaders[key] = reload
+		}
+		delete(c.hot, t)
+		delete(c.dirty, t)
+	}
+	return loaders
+}
+
+// RunCatalogWarmer reloads hot keys after each change until ctx is done.
+// Keys are reloaded one at a time so a catalog edit does not fan out into
+// a burst of queries; a key that fails to load is left for the next
+// request to load and report.
+func RunCatalogWarmer(ctx context.Context, cache *CatalogCache) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cache.wake:
+		}
+
+		loaders := cache.takeDirty()
+		if len(loaders) == 0 {
+			continue
+		}
+		began := time.Now()
+		var failed int
+		for key, reload := range loaders {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := reload(ctx); err != nil {
+				failed++
+				logger.Warn("Catalog cache warm-up failed", zap.String("key", key), zap.Error(err))
+			}
+		}
+		logger.Info("Catalog cache warmed",
+			zap.Int("keys", len(loaders)),
+			zap.Int("failed", failed),
+			zap.Duration("took", time.Since(began)),
+		)
+	}
+}
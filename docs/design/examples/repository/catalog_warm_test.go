@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startWarmer runs RunCatalogWarmer until the returned stop is called;
+// stop waits for it to return, so a reload in progress has finished.
+func startWarmer(t *testing.T, c *CatalogCache) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunCatalogWarmer(ctx, c)
+	}()
+	stop = func() {
+		cancel()
+		<-done
+		c.cache.Wait()
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func TestRunCatalogWarmer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reloads served keys after a change", func(t *testing.T) {
+		c := newTestCatalogCache(t)
+		var calls atomic.Int32
+		load := func(ctx context.Context) (int32, error) { return calls.Add(1), nil }
+
+		_, err := cachedLoad(ctx, c, CatalogTemplates, "active:ubuntu", load)
+		require.NoError(t, err)
+		c.Invalidate(CatalogTemplates)
+
+		stop := startWarmer(t, c)
+		require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+		stop()
+
+		// The first request after the change is served the warmed value
+		v, err := cachedLoad(ctx, c, CatalogTemplates, "active:ubuntu", load)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, v)
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("only keys of changed tables", func(t *testing.T) {
+		c := newTestCatalogCache(t)
+		var sizes, templates atomic.Int32
+		_, err := cachedLoad(ctx, c, CatalogInstanceSizes, "enabled", func(ctx context.Context) (int32, error) { return sizes.Add(1), nil })
+		require.NoError(t, err)
+		_, err = cachedLoad(ctx, c, CatalogTemplates, "active:ubuntu", func(ctx context.Context) (int32, error) { return templates.Add(1), nil })
+		require.NoError(t, err)
+
+		c.Invalidate(CatalogInstanceSizes)
+		stop := startWarmer(t, c)
+		require.Eventually(t, func() bool { return sizes.Load() == 2 }, time.Second, time.Millisecond)
+		stop()
+		require.EqualValues(t, 1, templates.Load())
+	})
+
+	t.Run("changes before the warmer runs are warmed once", func(t *testing.T) {
+		c := newTestCatalogCache(t)
+		var calls atomic.Int32
+		_, err := cachedLoad(ctx, c, CatalogClusters, "id:c-1", func(ctx context.Context) (int32, error) { return calls.Add(1), nil })
+		require.NoError(t, err)
+
+		c.Invalidate(CatalogClusters)
+		c.Invalidate(CatalogClusters)
+		c.InvalidateAll()
+
+		stop := startWarmer(t, c)
+		require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+		stop()
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("failed reload is left to the next request", func(t *testing.T) {
+		c := newTestCatalogCache(t)
+		var calls atomic.Int32
+		load := func(ctx context.Context) (int32, error) {
+			if calls.Add(1) == 2 {
+				return 0, errors.New("connection reset")
+			}
+			return calls.Load(), nil
+		}
+		_, err := cachedLoad(ctx, c, CatalogEnvironmentPolicies, "prod", load)
+		require.NoError(t, err)
+		c.Invalidate(CatalogEnvironmentPolicies)
+
+		stop := startWarmer(t, c)
+		require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+		stop()
+
+		v, err := cachedLoad(ctx, c, CatalogEnvironmentPolicies, "prod", load)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, v)
+	})
+}
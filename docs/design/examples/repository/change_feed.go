@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// ChangeChannel is the LISTEN/NOTIFY channel of the status change feed
+// (domain/change_feed.go). The status_changes trigger notifies it with an
+// empty payload, which PostgreSQL folds into one notification per
+// transaction.
+const ChangeChannel = "shepherd_status_changed"
+
+// ScopedChangeRepository reads the status change feed within a scope,
+// with the predicate of the scoped list queries (scope.go).
+type ScopedChangeRepository interface {
+	// ListChanges returns up to limit changes after cursor, committed and
+	// older than every running transaction, in cursor order, and where to
+	// resume: the last change returned when the page is full, else the
+	// head, past the changes the caller cannot see.
+	ListChanges(ctx context.Context, filter ScopeFilter, after domain.ChangeCursor, limit int) ([]*domain.StatusChange, domain.ChangeCursor, error)
+
+	// Head returns the position of the last servable change, visible to
+	// the caller or not.
+	Head(ctx context.Context) (domain.ChangeCursor, error)
+
+	// PurgedThrough returns the position of the last change purged; zero
+	// before the first purge.
+	PurgedThrough(ctx context.Context) (domain.ChangeCursor, error)
+}
+
+// ChangeHub wakes the requests waiting on the feed, in this replica, when
+// a change is committed anywhere.
+type ChangeHub struct {
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+// NewChangeHub creates a hub.
+func NewChangeHub() *ChangeHub {
+	return &ChangeHub{changed: make(chan struct{})}
+}
+
+// Changed returns a channel closed at the next change. Take it BEFORE
+// querying the feed: a change committed during the query then still
+// wakes the caller.
+func (h *ChangeHub) Changed() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.changed
+}
+
+// broadcast wakes every waiter.
+func (h *ChangeHub) broadcast() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	close(h.changed)
+	h.changed = make(chan struct{})
+}
+
+// RunChangeListener wakes the hub on every change notification until ctx
+// is done, reconnecting like RunCatalogListener, on a connection from
+// GetWorkerPool() for the same reason. After a reconnect the
+// waiters are woken once: changes may have been missed meanwhile.
+func RunChangeListener(ctx context.Context, pool *pgxpool.Pool, hub *ChangeHub) {
+	backoff := listenRetryMin
+	for ctx.Err() == nil {
+		err := listenChanges(ctx, pool, hub, func() { backoff = listenRetryMin })
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("Change feed listener disconnected",
+			zap.Duration("retry_in", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenRetryMax)
+	}
+}
+
+func listenChanges(ctx context.Context, pool *pgxpool.Pool, hub *ChangeHub, connected func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+ChangeChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	hub.broadcast()
+	connected()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		hub.broadcast()
+	}
+}
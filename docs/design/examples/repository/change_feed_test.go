@@ -0,0 +1,82 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+func requireClosed(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("not woken: %s", msg)
+	}
+}
+
+func requireOpen(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatalf("woken: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestChangeHub_Changed(t *testing.T) {
+	db := pgtest.New(t)
+	hub := repository.NewChangeHub()
+	notify := func(t *testing.T) {
+		t.Helper()
+		_, err := db.Pool.Exec(context.Background(), `SELECT pg_notify($1, '')`, repository.ChangeChannel)
+		require.NoError(t, err)
+	}
+
+	connected := hub.Changed()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		repository.RunChangeListener(ctx, db.Pool, hub)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// Waiters are woken once on connect: changes may have been missed
+	requireClosed(t, connected, "listener connected")
+
+	t.Run("same channel until the next change", func(t *testing.T) {
+		a, b := hub.Changed(), hub.Changed()
+		require.Equal(t, a, b)
+		requireOpen(t, a, "no change yet")
+	})
+	t.Run("committed change wakes every waiter", func(t *testing.T) {
+		waiters := []<-chan struct{}{hub.Changed(), hub.Changed(), hub.Changed()}
+		notify(t)
+		for _, ch := range waiters {
+			requireClosed(t, ch, "change committed")
+		}
+		requireOpen(t, hub.Changed(), "taken after the change, waits for the next one")
+	})
+	t.Run("rolled back change wakes nobody", func(t *testing.T) {
+		ch := hub.Changed()
+		tx, err := db.Pool.Begin(context.Background())
+		require.NoError(t, err)
+		_, err = tx.Exec(context.Background(), `SELECT pg_notify($1, '')`, repository.ChangeChannel)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback(context.Background()))
+		requireOpen(t, ch, "transaction rolled back")
+	})
+	t.Run("taken before a query, woken by a change during it", func(t *testing.T) {
+		ch := hub.Changed()
+		notify(t) // Committed while the caller reads the feed
+		requireClosed(t, ch, "change during the query")
+	})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ArchiveFallbackEventRepository looks events up in domain_events_archive
+// when they are no longer in domain_events (domain/event_archive.go).
+// Every other method passes through: archived events are terminal, so
+// nothing writes to them.
+//
+// Wired in the composition root around the event repository given to
+// readers that may hold an old event ID (Slack approvals, replacement,
+// the event worker's duplicate-delivery check):
+//
+//	eventRepo = repository.NewArchiveFallbackEventRepository(eventRepo, sqlcQueries)
+type ArchiveFallbackEventRepository struct {
+	DomainEventRepository
+	sqlcQueries *sqlc.Queries
+}
+
+// NewArchiveFallbackEventRepository wraps next.
+func NewArchiveFallbackEventRepository(next DomainEventRepository, sqlcQueries *sqlc.Queries) *ArchiveFallbackEventRepository {
+	return &ArchiveFallbackEventRepository{DomainEventRepository: next, sqlcQueries: sqlcQueries}
+}
+
+// Get returns the event, from the archive if it was moved there.
+func (r *ArchiveFallbackEventRepository) Get(ctx context.Context, eventID string) (*domain.DomainEvent, error) {
+	event, err := r.DomainEventRepository.Get(ctx, eventID)
+	if !errors.Is(err, ErrNotFound) {
+		return event, err
+	}
+
+	row, err := r.sqlcQueries.GetArchivedDomainEvent(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get archived event: %w", err)
+	}
+	archivedAt := row.ArchivedAt
+	return &domain.DomainEvent{
+		EventID:         row.EventID,
+		EventType:       domain.EventType(row.EventType),
+		AggregateType:   row.AggregateType,
+		AggregateID:     row.AggregateID,
+		Payload:         row.Payload,
+		PayloadEncoding: domain.PayloadEncoding(row.PayloadEncoding),
+		SchemaVersion:   int(row.SchemaVersion),
+		Result:          row.Result,
+		Status:          domain.EventStatus(row.Status),
+		RequeueCount:    int(row.RequeueCount),
+		CreatedBy:       row.CreatedBy,
+		CreatedAt:       row.CreatedAt,
+		ArchivedAt:      &archivedAt,
+	}, nil
+}
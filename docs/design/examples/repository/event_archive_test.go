@@ -0,0 +1,99 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+	"kv-shepherd.io/shepherd/internal/testutil/factory"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+// liveEvents stands in for the wrapped repository: the events still in
+// domain_events.
+type liveEvents struct {
+	repository.DomainEventRepository
+	events map[string]*domain.DomainEvent
+	err    error
+}
+
+func (r *liveEvents) Get(ctx context.Context, eventID string) (*domain.DomainEvent, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if e, ok := r.events[eventID]; ok {
+		return e, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// archive moves every finished event of the test's transaction to
+// domain_events_archive, as the archive job does once they are old enough.
+func archive(t *testing.T, db *pgtest.TxDB) {
+	t.Helper()
+	statuses := make([]string, len(domain.ArchivableEventStatuses))
+	for i, s := range domain.ArchivableEventStatuses {
+		statuses[i] = string(s)
+	}
+	_, err := db.SqlcQueries.ArchiveDomainEvents(context.Background(), sqlc.ArchiveDomainEventsParams{
+		Cutoff:     time.Now().Add(time.Hour),
+		Statuses:   statuses,
+		ArchivedAt: factory.Now,
+		Limit:      domain.EventArchiveBatchSize,
+	})
+	require.NoError(t, err)
+}
+
+func TestArchiveFallbackEventRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.Tx(t)
+
+	completed := func(e *domain.DomainEvent) { e.Status = domain.EventStatusCompleted }
+	archived := factory.CreationEvent(factory.CreationPayload(), completed)
+	factory.Seed(t, db, archived)
+	archive(t, db)
+
+	t.Run("live event", func(t *testing.T) {
+		live := factory.CreationEvent(factory.CreationPayload())
+		r := repository.NewArchiveFallbackEventRepository(&liveEvents{events: map[string]*domain.DomainEvent{live.EventID: live}}, db.SqlcQueries)
+
+		got, err := r.Get(ctx, live.EventID)
+		require.NoError(t, err)
+		require.Same(t, live, got)
+		require.Nil(t, got.ArchivedAt)
+	})
+	t.Run("archived event", func(t *testing.T) {
+		r := repository.NewArchiveFallbackEventRepository(&liveEvents{}, db.SqlcQueries)
+
+		got, err := r.Get(ctx, archived.EventID)
+		require.NoError(t, err)
+		require.Equal(t, archived.EventID, got.EventID)
+		require.Equal(t, archived.EventType, got.EventType)
+		require.Equal(t, archived.AggregateID, got.AggregateID)
+		require.Equal(t, domain.EventStatusCompleted, got.Status)
+		require.Equal(t, archived.SchemaVersion, got.SchemaVersion)
+		require.JSONEq(t, string(archived.Payload), string(got.Payload))
+		require.NotNil(t, got.ArchivedAt)
+		require.True(t, got.ArchivedAt.Equal(factory.Now), "archived_at %v", got.ArchivedAt)
+	})
+	t.Run("in neither table", func(t *testing.T) {
+		r := repository.NewArchiveFallbackEventRepository(&liveEvents{}, db.SqlcQueries)
+
+		_, err := r.Get(ctx, factory.ID("evt"))
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+	t.Run("live lookup failed", func(t *testing.T) {
+		// Only ErrNotFound falls back: an outage must not read as archived
+		down := errors.New("connection refused")
+		r := repository.NewArchiveFallbackEventRepository(&liveEvents{err: down}, db.SqlcQueries)
+
+		_, err := r.Get(ctx, archived.EventID)
+		require.ErrorIs(t, err, down)
+	})
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// InstanceSizeVersionRepository reads InstanceSize versions
+// (domain/instance_size_version.go). Versions are written by
+// InstanceSizeUseCase in the TX that changes the size; the table rejects
+// UPDATE and DELETE.
+type InstanceSizeVersionRepository interface {
+	// ListVersions returns every version of the size, newest first;
+	// ErrNotFound if the size does not exist.
+	ListVersions(ctx context.Context, sizeID string) ([]*domain.InstanceSizeVersion, error)
+
+	// GetVersion returns one version; ErrNotFound if there is none.
+	GetVersion(ctx context.Context, sizeID string, version int) (*domain.InstanceSizeVersion, error)
+
+	// ListOutdatedVMs returns the non-deleted VMs whose pinned version is
+	// older than their size's current one, most versions behind first.
+	// sizeID narrows the report to one size; empty lists all.
+	ListOutdatedVMs(ctx context.Context, sizeID string) ([]*domain.OutdatedSizeVM, error)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NonceCache persists nonces of signed requests until they expire
+// (domain.SignedRequest.NonceExpiry), so a captured request cannot be
+// replayed against another replica or after a restart.
+//
+//	CREATE UNLOGGED TABLE request_nonces (
+//	    key_id     TEXT        NOT NULL,
+//	    nonce      TEXT        NOT NULL,
+//	    expires_at TIMESTAMPTZ NOT NULL,
+//	    PRIMARY KEY (key_id, nonce)
+//	);
+//
+// UNLOGGED: losing the table in a crash only reopens the replay window
+// for at most the allowed clock skew, and writes stay cheap. Rows are
+// small and short-lived; PurgeExpired keeps the table bounded.
+type NonceCache struct {
+	pool *pgxpool.Pool
+}
+
+// NewNonceCache creates a cache on the shared pool.
+func NewNonceCache(pool *pgxpool.Pool) *NonceCache {
+	return &NonceCache{pool: pool}
+}
+
+// Claim records the nonce for keyID. It returns false if the nonce was
+// already claimed and has not expired. The insert is atomic, so two
+// replicas racing on the same request cannot both succeed.
+func (c *NonceCache) Claim(ctx context.Context, keyID, nonce string, expiresAt time.Time) (bool, error) {
+	// An expired row with the same nonce is overwritten: the timestamp check
+	// already rejects the old request, so the nonce is free again.
+	tag, err := c.pool.Exec(ctx,
+		`INSERT INTO request_nonces (key_id, nonce, expires_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (key_id, nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		 WHERE request_nonces.expires_at < now()`,
+		keyID, nonce, expiresAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim nonce: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// PurgeExpired deletes expired nonces and returns how many were removed.
+func (c *NonceCache) PurgeExpired(ctx context.Context) (int64, error) {
+	tag, err := c.pool.Exec(ctx, `DELETE FROM request_nonces WHERE expires_at < now()`)
+	if err != nil {
+		return 0, fmt.Errorf("purge nonces: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+func TestNonceCache_Claim(t *testing.T) {
+	db := pgtest.New(t)
+	cache := repository.NewNonceCache(db.Pool)
+	ctx := context.Background()
+	live := time.Now().Add(5 * time.Minute) // request_nonces is compared with the server's now()
+
+	t.Run("first claim", func(t *testing.T) {
+		ok, err := cache.Claim(ctx, "key-1", "n-first", live)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+	t.Run("replay", func(t *testing.T) {
+		ok, err := cache.Claim(ctx, "key-1", "n-replay", live)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = cache.Claim(ctx, "key-1", "n-replay", live)
+		require.NoError(t, err)
+		require.False(t, ok, "a claimed nonce must not be claimed again before it expires")
+	})
+	t.Run("same nonce, other key", func(t *testing.T) {
+		ok, err := cache.Claim(ctx, "key-1", "n-shared", live)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = cache.Claim(ctx, "key-2", "n-shared", live)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+	t.Run("expired nonce is free again", func(t *testing.T) {
+		ok, err := cache.Claim(ctx, "key-1", "n-expired", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = cache.Claim(ctx, "key-1", "n-expired", live)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// Reclaimed with a live expiry: replays are rejected again
+		ok, err = cache.Claim(ctx, "key-1", "n-expired", live)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestNonceCache_PurgeExpired(t *testing.T) {
+	db := pgtest.New(t)
+	cache := repository.NewNonceCache(db.Pool)
+	ctx := context.Background()
+
+	for _, nonce := range []string{"old-1", "old-2"} {
+		ok, err := cache.Claim(ctx, "key-1", nonce, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	ok, err := cache.Claim(ctx, "key-1", "live", time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	n, err := cache.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	n, err = cache.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// The live nonce survived the purge
+	ok, err = cache.Claim(ctx, "key-1", "live", time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
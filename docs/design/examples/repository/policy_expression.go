@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// PolicyExpressionRepository reads policy expressions
+// (domain/policy_expression.go). Policies and their versions are written
+// by PolicyExpressionUseCase; policy_expression_versions rejects UPDATE
+// and DELETE.
+type PolicyExpressionRepository interface {
+	// List returns every policy at its current version, disabled ones
+	// included, by priority then name.
+	List(ctx context.Context) ([]*domain.PolicyExpression, error)
+
+	// ListEnabled returns the enabled policies, as List; read on every
+	// submission.
+	ListEnabled(ctx context.Context) ([]*domain.PolicyExpression, error)
+
+	// Get returns a policy at its current version; ErrNotFound if none.
+	Get(ctx context.Context, id string) (*domain.PolicyExpression, error)
+
+	// ListVersions returns every version of the policy, newest first;
+	// ErrNotFound if the policy does not exist.
+	ListVersions(ctx context.Context, id string) ([]*domain.PolicyExpressionVersion, error)
+}
+
+// RequesterAttributeRepository reads the IdP attributes policy
+// expressions may use: the department claim stored on users at login, and
+// the user's groups in idp_synced_groups (master-flow Stage 2.C).
+type RequesterAttributeRepository interface {
+	// GetRequesterAttributes returns the user's department and IdP
+	// groups; both empty for local users and unmapped fields.
+	GetRequesterAttributes(ctx context.Context, userID string) (department string, groups []string, err error)
+}
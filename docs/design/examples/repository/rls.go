@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RLSTables are the tables protected by row-level security policies
+// (Phase 4 §10.5). Policies mirror the ScopeFilter predicate.
+var RLSTables = []string{"vms", "approval_tickets", "domain_events"}
+
+// ScopedTx runs scoped reads inside a read-only transaction and, when
+// row-level security is enabled (database.row_level_security), publishes
+// the caller's scope as transaction-local settings for the RLS policies:
+//
+//	shepherd.actor       user ID
+//	shepherd.all_access  "on" / "off"
+//	shepherd.grants      ScopeFilter.Grants (JSONB text)
+//
+// The application role's default is shepherd.actor = 'system' (ALTER ROLE
+// ... SET), so workers and use cases are unaffected. RLS is a backstop for
+// wrong predicates in scoped queries, not a replacement for them: the query
+// still applies ScopeFilter, the policy only catches its mistakes.
+type ScopedTx struct {
+	pool    *pgxpool.Pool
+	enabled bool
+}
+
+// NewScopedTx creates a runner. enabled comes from database.row_level_security.
+func NewScopedTx(pool *pgxpool.Pool, enabled bool) *ScopedTx {
+	return &ScopedTx{pool: pool, enabled: enabled}
+}
+
+// Run executes fn with the scope applied.
+func (s *ScopedTx) Run(ctx context.Context, filter ScopeFilter, fn func(tx pgx.Tx) error) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if s.enabled {
+		// is_local = true: settings end with the TX and never leak to the
+		// next user of this pooled connection.
+		_, err := tx.Exec(ctx,
+			`SELECT set_config('shepherd.actor', $1, true),
+			        set_config('shepherd.all_access', $2, true),
+			        set_config('shepherd.grants', $3, true)`,
+			filter.UserID, onOff(filter.AllAccess), string(filter.Grants),
+		)
+		if err != nil {
+			return fmt.Errorf("set rls scope: %w", err)
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// VerifyRLS checks at startup that every RLSTables entry has row-level
+// security enabled and forced. Enabling the mode without the migration
+// would silently give no protection, so startup fails instead.
+func VerifyRLS(ctx context.Context, pool *pgxpool.Pool) error {
+	rows, err := pool.Query(ctx,
+		`SELECT relname FROM pg_class
+		 WHERE relname = ANY($1) AND relrowsecurity AND relforcerowsecurity`,
+		RLSTables,
+	)
+	if err != nil {
+		return fmt.Errorf("query rls status: %w", err)
+	}
+	defer rows.Close()
+
+	protected := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("scan rls status: %w", err)
+		}
+		protected[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read rls status: %w", err)
+	}
+
+	var missing []string
+	for _, t := range RLSTables {
+		if !protected[t] {
+			missing = append(missing, strconv.Quote(t))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("row_level_security enabled but not forced on %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
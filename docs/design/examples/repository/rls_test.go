@@ -0,0 +1,107 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+// rlsScope is the setting set Run publishes.
+type rlsScope struct {
+	actor, allAccess, grants string
+}
+
+// readScope reads the settings as the RLS policies see them; unset ones
+// read as "" (missing_ok).
+func readScope(ctx context.Context, q interface {
+	QueryRow(context.Context, string, ...any) pgx.Row
+}) (rlsScope, error) {
+	var s rlsScope
+	err := q.QueryRow(ctx,
+		`SELECT coalesce(current_setting('shepherd.actor', true), ''),
+		        coalesce(current_setting('shepherd.all_access', true), ''),
+		        coalesce(current_setting('shepherd.grants', true), '')`,
+	).Scan(&s.actor, &s.allAccess, &s.grants)
+	return s, err
+}
+
+// singleConnPool returns a pool of one connection, so the reads after Run
+// are on the connection Run used.
+func singleConnPool(t *testing.T, db *pgtest.DB) *pgxpool.Pool {
+	t.Helper()
+	cfg, err := pgxpool.ParseConfig(db.URL)
+	require.NoError(t, err)
+	cfg.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestScopedTx_Run(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.New(t)
+	pool := singleConnPool(t, db)
+	filter := repository.ScopeFilter{UserID: "alice", Grants: []byte(`[{"service_id":"svc-1"}]`)}
+
+	// The connection's own values (the role default), which Run must restore
+	before, err := readScope(ctx, pool)
+	require.NoError(t, err)
+	require.NotEqual(t, "alice", before.actor)
+
+	t.Run("settings applied inside and reset after", func(t *testing.T) {
+		s := repository.NewScopedTx(pool, true)
+		var inside rlsScope
+		require.NoError(t, s.Run(ctx, filter, func(tx pgx.Tx) error {
+			var err error
+			inside, err = readScope(ctx, tx)
+			return err
+		}))
+		require.Equal(t, rlsScope{actor: "alice", allAccess: "off", grants: `[{"service_id":"svc-1"}]`}, inside)
+
+		after, err := readScope(ctx, pool)
+		require.NoError(t, err)
+		require.Equal(t, before, after, "settings must not leak to the next user of the connection")
+	})
+	t.Run("all access", func(t *testing.T) {
+		s := repository.NewScopedTx(pool, true)
+		require.NoError(t, s.Run(ctx, repository.ScopeFilter{UserID: "admin", AllAccess: true, Grants: []byte(`[]`)}, func(tx pgx.Tx) error {
+			inside, err := readScope(ctx, tx)
+			require.Equal(t, "on", inside.allAccess)
+			return err
+		}))
+	})
+	t.Run("reset after fn fails", func(t *testing.T) {
+		s := repository.NewScopedTx(pool, true)
+		failed := errors.New("query failed")
+		err := s.Run(ctx, filter, func(tx pgx.Tx) error { return failed })
+		require.ErrorIs(t, err, failed)
+
+		after, err := readScope(ctx, pool)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
+	t.Run("disabled publishes nothing", func(t *testing.T) {
+		s := repository.NewScopedTx(pool, false)
+		require.NoError(t, s.Run(ctx, filter, func(tx pgx.Tx) error {
+			inside, err := readScope(ctx, tx)
+			require.Equal(t, before, inside)
+			return err
+		}))
+	})
+	t.Run("read-only", func(t *testing.T) {
+		s := repository.NewScopedTx(pool, true)
+		err := s.Run(ctx, filter, func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, `DELETE FROM vms`)
+			return err
+		})
+		require.ErrorContains(t, err, "read-only transaction")
+	})
+}
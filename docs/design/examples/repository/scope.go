@@ -0,0 +1,78 @@
+// Package repository provides data access for the platform.
+//
+// This file defines the query-level form of a domain.AccessScope.
+//
+// Every list query over VMs, approval tickets and domain events takes a
+// ScopeFilter and applies it in SQL (Phase 4 §Scoped Queries), so rows
+// outside the caller's permissions are never loaded, counted or paged over.
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/repository
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// ScopeFilter is bound as sqlc parameters:
+//
+//	@all_access::bool   bypasses resource predicates
+//	@user_id::text      caller's own tickets/events are always visible
+//	@grants::jsonb      [{resource_type, resource_id, environments}]
+type ScopeFilter struct {
+	AllAccess bool
+	UserID    string
+	Grants    []byte
+}
+
+// NewScopeFilter converts a resolved scope into query parameters.
+func NewScopeFilter(scope *domain.AccessScope) (ScopeFilter, error) {
+	grants := scope.Grants
+	if grants == nil {
+		grants = []domain.ScopeGrant{} // '[]'::jsonb, never NULL
+	}
+	data, err := json.Marshal(grants)
+	if err != nil {
+		return ScopeFilter{}, fmt.Errorf("encode scope grants: %w", err)
+	}
+	return ScopeFilter{
+		AllAccess: scope.All,
+		UserID:    scope.UserID,
+		Grants:    data,
+	}, nil
+}
+
+// Page is keyset pagination shared by scoped list queries.
+// After is the opaque cursor returned with the previous page.
+type Page struct {
+	Limit int
+	After string
+}
+
+// VMQuery narrows a VM list within the scope. The zero value lists every
+// VM in scope. Bound as @gpu::bool and @gpu_device::text.
+type VMQuery struct {
+	GPU       bool   // Only VMs with a GPU
+	GPUDevice string // Only VMs with a GPU of this model (domain.GPUDevice)
+}
+
+// ScopedVMRepository lists VMs within a scope.
+type ScopedVMRepository interface {
+	ListVMs(ctx context.Context, filter ScopeFilter, query VMQuery, page Page) ([]*domain.VM, string, error)
+}
+
+// ScopedTicketRepository lists approval tickets within a scope.
+// Tickets on the caller's own requests are included regardless of grants.
+type ScopedTicketRepository interface {
+	ListTickets(ctx context.Context, filter ScopeFilter, status string, page Page) ([]*domain.ApprovalTicket, string, error)
+}
+
+// ScopedEventRepository lists domain events within a scope.
+// Events are scoped through their ticket; events without a ticket
+// (platform maintenance) are visible to AllAccess only.
+type ScopedEventRepository interface {
+	ListEvents(ctx context.Context, filter ScopeFilter, page Page) ([]*domain.DomainEvent, string, error)
+}
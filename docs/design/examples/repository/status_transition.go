@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+)
+
+// TransitionVMStatus moves a VM to status to inside tx, if the state
+// machine allows it from the status the row has now (domain/status_machine.go).
+// The row is locked first, so the check and the write see the same
+// status. source is stored with the vm_status_history row the trigger
+// writes (shepherd.status_source, local to tx). now stamps the returned
+// record and comes from the caller's clock.
+//
+// A refused transition leaves the row unchanged, is logged and returns
+// domain.ErrInvalidStatusTransition with the record; the caller decides
+// whether that fails its operation (worker) or is skipped (watcher,
+// resync). ErrNotFound if the VM does not exist.
+func TransitionVMStatus(ctx context.Context, tx pgx.Tx, vmID string, to domain.VMStatus, message string, source domain.TransitionSource, now time.Time) (*domain.StatusTransition, error) {
+	var from string
+	err := tx.QueryRow(ctx, `SELECT status FROM vms WHERE id = $1 FOR UPDATE`, vmID).Scan(&from)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock vm: %w", err)
+	}
+
+	t := &domain.StatusTransition{Kind: "vm", ID: vmID, From: from, To: string(to), Source: source, OccurredAt: now}
+	if err := domain.CheckVMTransition(domain.VMStatus(from), to); err != nil {
+		return refused(ctx, t, err)
+	}
+	if from == string(to) {
+		return t, nil
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT set_config('shepherd.status_source', $1, true)`, string(source)); err != nil {
+		return nil, fmt.Errorf("set status source: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE vms SET status = $2, status_message = $3, updated_at = now() WHERE id = $1`,
+		vmID, string(to), message,
+	); err != nil {
+		return nil, fmt.Errorf("update vm status: %w", err)
+	}
+	return t, nil
+}
+
+// TransitionEventStatus moves an event to status to inside tx, like
+// TransitionVMStatus. result is written once, as by CompleteDomainEvent;
+// nil leaves it unset. Moving an event to the status it already has is a
+// no-op, so a redelivered completion succeeds without writing.
+func TransitionEventStatus(ctx context.Context, tx pgx.Tx, eventID string, to domain.EventStatus, result []byte, source domain.TransitionSource, now time.Time) (*domain.StatusTransition, error) {
+	var from string
+	err := tx.QueryRow(ctx, `SELECT status FROM domain_events WHERE event_id = $1 FOR UPDATE`, eventID).Scan(&from)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock event: %w", err)
+	}
+
+	t := &domain.StatusTransition{Kind: "event", ID: eventID, From: from, To: string(to), Source: source, OccurredAt: now}
+	if err := domain.CheckEventTransition(domain.EventStatus(from), to); err != nil {
+		return refused(ctx, t, err)
+	}
+	if from == string(to) {
+		return t, nil
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE domain_events SET status = $2, result = COALESCE(result, $3) WHERE event_id = $1`,
+		eventID, string(to), result,
+	); err != nil {
+		return nil, fmt.Errorf("update event status: %w", err)
+	}
+	return t, nil
+}
+
+func refused(ctx context.Context, t *domain.StatusTransition, err error) (*domain.StatusTransition, error) {
+	t.Refused = true
+	logger.WarnCtx(ctx, "Status transition refused",
+		zap.String("kind", t.Kind),
+		zap.String("id", t.ID),
+		zap.String("from", t.From),
+		zap.String("to", t.To),
+		zap.String("source", string(t.Source)),
+	)
+	return t, err
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// VMRelocationRepository reads cross-cluster relocations
+// (domain/vm_relocation.go). Steps are written by VMRelocationHandler and
+// RelocateVMUseCase in their own TXs, conditional on the current step.
+type VMRelocationRepository interface {
+	// Get returns one relocation with its recorded steps and disk
+	// progress; ErrNotFound if there is none.
+	Get(ctx context.Context, id string) (*domain.VMRelocation, error)
+
+	// ListActive returns the relocations not finished yet, oldest step
+	// first, so the one waiting longest is on top. Includes decommission
+	// items.
+	ListActive(ctx context.Context) ([]*domain.VMRelocation, error)
+}
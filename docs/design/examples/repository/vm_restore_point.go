@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// RestorePointRepository reads the restore points recorded by snapshot,
+// backup and restore requests (domain/vm_backup.go).
+type RestorePointRepository interface {
+	// Get returns one restore point; ErrNotFound if there is none.
+	Get(ctx context.Context, id string) (*domain.RestorePoint, error)
+
+	// ListForVM returns the VM's restore points not expired at now,
+	// newest first. Snapshots and backups are listed together.
+	ListForVM(ctx context.Context, vmID string, now time.Time) ([]*domain.RestorePoint, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// ScopedTimelineRepository reads a VM's timeline (domain/vm_timeline.go).
+type ScopedTimelineRepository interface {
+	// VMInScope reports whether the VM exists and the filter allows it,
+	// with the predicate of ListVMsScoped (scope.go). A VM outside the
+	// scope is reported like a missing one.
+	VMInScope(ctx context.Context, filter ScopeFilter, vmID string) (bool, error)
+
+	// ListVMTimeline returns up to limit entries after cursor, oldest
+	// first, each with its own cursor set.
+	ListVMTimeline(ctx context.Context, vmID string, after domain.TimelineCursor, limit int) ([]*domain.TimelineEntry, error)
+}
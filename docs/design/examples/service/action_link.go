@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// TicketDecider applies an approval decision.
+// Implemented by usecase.CreateVMAtomicUseCase.
+type TicketDecider interface {
+	ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error)
+	RejectTicket(ctx context.Context, ticketID, rejectedBy, reason string) error
+}
+
+// ActionLinkService issues and redeems email action links
+// (domain/action_link.go).
+//
+// Links act as the approver without a session, so every path that a
+// logged-in approver would go through still applies: the decision is made
+// by the same use case, including separation-of-duties checks. Links only
+// skip the login, never the rules.
+type ActionLinkService struct {
+	linkRepo   repository.ActionLinkRepository
+	ticketRepo repository.ApprovalTicketRepository
+	decider    TicketDecider
+	clock      domain.Clock
+	key        []byte        // governance.action_link_key
+	ttl        time.Duration // governance.action_link_ttl
+}
+
+// NewActionLinkService creates a new service.
+func NewActionLinkService(
+	linkRepo repository.ActionLinkRepository,
+	ticketRepo repository.ApprovalTicketRepository,
+	decider TicketDecider,
+	clock domain.Clock,
+	key []byte,
+	ttl time.Duration,
+) *ActionLinkService {
+	return &ActionLinkService{
+		linkRepo:   linkRepo,
+		ticketRepo: ticketRepo,
+		decider:    decider,
+		clock:      clock,
+		key:        key,
+		ttl:        ttl,
+	}
+}
+
+// Issue creates one link per action for an assigned approver and returns
+// the URL tokens, keyed by action, for the approval email.
+func (s *ActionLinkService) Issue(ctx context.Context, ticketID, approverID string) (map[domain.ActionLinkAction]string, error) {
+	expires := s.clock.Now().Add(s.ttl)
+	links := make([]*domain.ActionLink, 0, len(domain.ActionLinkActions))
+	tokens := make(map[domain.ActionLinkAction]string, len(domain.ActionLinkActions))
+	for _, action := range domain.ActionLinkActions {
+		link := &domain.ActionLink{
+			ID:         uuid.New().String(),
+			TicketID:   ticketID,
+			ApproverID: approverID,
+			Action:     action,
+			ExpiresAt:  expires,
+		}
+		token, err := domain.SignActionLink(s.key, link)
+		if err != nil {
+			return nil, fmt.Errorf("sign action link: %w", err)
+		}
+		links = append(links, link)
+		tokens[action] = token
+	}
+	if err := s.linkRepo.Create(ctx, links); err != nil {
+		return nil, fmt.Errorf("store action links: %w", err)
+	}
+	return tokens, nil
+}
+
+// Inspect validates a token without side effects (GET, safe for mail
+// scanners) and returns the link and its ticket for the confirm page.
+func (s *ActionLinkService) Inspect(ctx context.Context, token string) (*domain.ActionLink, *domain.ApprovalTicket, error) {
+	id, err := domain.ParseActionLink(s.key, token, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	link, err := s.linkRepo.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, domain.ErrActionLinkInvalid // Signed but purged
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("get action link: %w", err)
+	}
+	ticket, err := s.ticketRepo.Get(ctx, link.TicketID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if err := link.CheckUsable(ticket, s.clock.Now()); err != nil {
+		return nil, nil, err
+	}
+	return link, ticket, nil
+}
+
+// Confirm redeems an approve or reject link.
+//
+// The link is claimed before the decision: ConsumeForApprover marks every
+// unused deciding link of this approver on the ticket used in one
+// statement and reports whether this link was among them, so double clicks
+// race to a single winner. Other approvers' links stay valid (tickets may
+// need several approvals) until the ticket leaves PENDING_APPROVAL. If the
+// decision fails (e.g. a SoD violation), the link stays spent and the
+// approver continues in the web UI.
+func (s *ActionLinkService) Confirm(ctx context.Context, token, reason string) (*domain.ActionLink, error) {
+	link, _, err := s.Inspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !link.Decides() {
+		return nil, domain.ErrActionLinkInvalid
+	}
+	if link.Action == domain.ActionLinkReject {
+		// Checked before the link is consumed, so a missing reason can be retried
+		if err := domain.ValidateRejectReason(reason); err != nil {
+			return nil, err
+		}
+	}
+
+	claimed, err := s.linkRepo.ConsumeForApprover(ctx, link.TicketID, link.ApproverID, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("consume action link: %w", err)
+	}
+	if !claimed {
+		return nil, domain.ErrActionLinkUsed
+	}
+
+	switch link.Action {
+	case domain.ActionLinkApprove:
+		_, err = s.decider.ApproveAndEnqueue(ctx, link.TicketID, link.ApproverID, nil, nil) // Requested spec as-is, executed on approval
+	case domain.ActionLinkReject:
+		err = s.decider.RejectTicket(ctx, link.TicketID, link.ApproverID, reason)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// No session, so no user in the context: the link's approver acts
+	ctx = logger.WithUser(logger.WithTicket(ctx, link.TicketID), link.ApproverID)
+	logger.InfoCtx(ctx, "Approval decided via action link",
+		zap.String("action", string(link.Action)),
+	)
+	return link, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ApprovalGuard enforces separation of duties on every approval path.
+//
+// Enforce runs inside the caller's approval TX (so the assignment read is
+// consistent with the status update), but violations are audited through
+// the pool, OUTSIDE that TX: the approval rolls back, the audit row must not.
+// This is the one deliberate exception to "audit in the same TX" (Phase 4 §7).
+type ApprovalGuard struct {
+	sqlcQueries *sqlc.Queries
+	permissions domain.PermissionChecker
+	ids         domain.IDGenerator
+}
+
+// NewApprovalGuard creates a new guard.
+func NewApprovalGuard(sqlcQueries *sqlc.Queries, permissions domain.PermissionChecker, ids domain.IDGenerator) *ApprovalGuard {
+	return &ApprovalGuard{sqlcQueries: sqlcQueries, permissions: permissions, ids: ids}
+}
+
+// Enforce returns a *domain.SoDViolation if approverID may not approve the ticket.
+// sqlcTx is the caller's transaction-bound queries.
+//
+// An approver who is not assigned may act as the delegate of one who is
+// (domain/delegation.go); the returned ActingApprover names the delegator.
+func (g *ApprovalGuard) Enforce(ctx context.Context, sqlcTx *sqlc.Queries, ticketID, serviceID, requesterID, approverID string) (domain.ActingApprover, error) {
+	acting := domain.ActingApprover{UserID: approverID}
+	attempt := domain.ApprovalAttempt{
+		TicketID:    ticketID,
+		ServiceID:   serviceID,
+		RequesterID: requesterID,
+		ApproverID:  approverID,
+	}
+
+	// Cheap check first: no lookups needed to reject self-approval
+	if approverID != requesterID {
+		// All queries compare against the ticket's current_stage
+		assigned, err := sqlcTx.IsTicketApprover(ctx, sqlc.IsTicketApproverParams{
+			TicketID: ticketID,
+			UserID:   approverID,
+		})
+		if err != nil {
+			return acting, fmt.Errorf("check approver assignment: %w", err)
+		}
+		if !assigned {
+			// Own assignment first: a delegate who is also assigned acts for themselves
+			delegator, err := sqlcTx.GetActiveTicketDelegator(ctx, sqlc.GetActiveTicketDelegatorParams{
+				TicketID:   ticketID,
+				DelegateID: approverID,
+			})
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+			case err != nil:
+				return acting, fmt.Errorf("check delegations: %w", err)
+			default:
+				acting.OnBehalfOf = delegator
+				assigned = true
+			}
+		}
+		attempt.Assigned = assigned
+		attempt.OnBehalfOf = acting.OnBehalfOf
+
+		// Matches approver_id or on_behalf_of: neither person may return
+		repeat, err := sqlcTx.HasApprovedEarlierStage(ctx, sqlc.HasApprovedEarlierStageParams{
+			TicketID:   ticketID,
+			ApproverID: approverID,
+			Principal:  acting.Principal(),
+		})
+		if err != nil {
+			return acting, fmt.Errorf("check earlier stages: %w", err)
+		}
+		attempt.ApprovedEarlierStage = repeat
+
+		if assigned {
+			// The authority used is the delegator's
+			perm, err := g.permissions.CheckPermission(acting.Principal(), "approval:approve", string(domain.ResourceTypeService), serviceID)
+			if err != nil {
+				return acting, fmt.Errorf("check approval permission: %w", err)
+			}
+			attempt.Permission = perm
+		}
+	}
+
+	violation := domain.CheckSeparationOfDuties(attempt)
+	if violation == nil {
+		return acting, nil
+	}
+
+	g.recordViolation(ctx, violation)
+	return acting, violation
+}
+
+// recordViolation writes approval.sod_violation via the pool (autocommit).
+// A failed audit write is logged, never returned: the approval is already rejected.
+func (g *ApprovalGuard) recordViolation(ctx context.Context, v *domain.SoDViolation) {
+	err := g.sqlcQueries.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           g.ids.NewID(),
+		Action:       domain.AuditApprovalSoDViolation,
+		ActorID:      v.ApproverID,
+		ResourceType: "approval",
+		ResourceID:   v.TicketID,
+		Details: map[string]interface{}{
+			"rule":         string(v.Rule),
+			"on_behalf_of": v.OnBehalfOf,
+			"requester_id": v.RequesterID,
+			"reason":       v.Reason,
+		},
+	})
+	if err != nil {
+		logger.WarnCtx(logger.WithTicket(ctx, v.TicketID), "Failed to audit SoD violation",
+			zap.String("approver_id", v.ApproverID),
+			zap.String("rule", string(v.Rule)),
+			zap.Error(err),
+		)
+	}
+}
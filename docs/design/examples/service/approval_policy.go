@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ApprovalPolicyService routes requests to auto-approval or the manual
+// ticket path using the approval rules (domain/approval_policy.go) and
+// the policy expressions (domain/policy_expression.go), and refuses
+// requests a guardrail policy denies.
+//
+// Rules and policies are read on every submission, so edits take effect
+// immediately. The route is not frozen anywhere: only the resulting
+// ticket is.
+type ApprovalPolicyService struct {
+	ruleRepo     repository.ApprovalRuleRepository
+	policyRepo   repository.PolicyExpressionRepository
+	engine       *PolicyEngine
+	serviceRepo  repository.ServiceRepository
+	bindingRepo  repository.ResourceRoleBindingRepository
+	roleRepo     repository.RoleBindingRepository
+	metadataRepo repository.SystemMetadataRepository
+	attributes   repository.RequesterAttributeRepository
+	clock        domain.Clock
+}
+
+// NewApprovalPolicyService creates a new service.
+func NewApprovalPolicyService(
+	ruleRepo repository.ApprovalRuleRepository,
+	policyRepo repository.PolicyExpressionRepository,
+	engine *PolicyEngine,
+	serviceRepo repository.ServiceRepository,
+	bindingRepo repository.ResourceRoleBindingRepository,
+	roleRepo repository.RoleBindingRepository,
+	metadataRepo repository.SystemMetadataRepository,
+	attributes repository.RequesterAttributeRepository,
+	clock domain.Clock,
+) *ApprovalPolicyService {
+	return &ApprovalPolicyService{
+		ruleRepo:     ruleRepo,
+		policyRepo:   policyRepo,
+		engine:       engine,
+		serviceRepo:  serviceRepo,
+		bindingRepo:  bindingRepo,
+		roleRepo:     roleRepo,
+		metadataRepo: metadataRepo,
+		attributes:   attributes,
+		clock:        clock,
+	}
+}
+
+// Route decides how a request on a Service is approved. env is the
+// environment decision for the same request; rules and policies cannot
+// exceed it. Returns *domain.GuardrailDenied if a guardrail refuses the
+// request.
+func (s *ApprovalPolicyService) Route(ctx context.Context, serviceID, requester string, req domain.ApprovalRequest, env *domain.EnvironmentDecision) (*domain.ApprovalRoute, error) {
+	rules, err := s.ruleRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list approval rules: %w", err)
+	}
+	policies, err := s.policyRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list policy expressions: %w", err)
+	}
+
+	// Roles are only needed if some rule or policy may condition on them
+	if domain.NeedRequesterRoles(rules) || len(policies) > 0 {
+		roles, err := s.RequesterRoles(ctx, serviceID, requester)
+		if err != nil {
+			return nil, err
+		}
+		req.RequesterRoles = roles
+	}
+
+	var results []domain.PolicyResult
+	if len(policies) > 0 {
+		in, err := s.policyInput(ctx, serviceID, requester, req, env.Environment)
+		if err != nil {
+			return nil, err
+		}
+		results = s.engine.Evaluate(policies, in)
+		if err := domain.CheckGuardrails(results); err != nil {
+			return nil, err
+		}
+	}
+
+	route := domain.RouteApprovalWithPolicies(rules, results, req, *env)
+	return &route, nil
+}
+
+// policyInput assembles what policy expressions see for a request whose
+// requester roles are resolved: the requester's IdP attributes and the
+// System's labels, at the current time.
+func (s *ApprovalPolicyService) policyInput(ctx context.Context, serviceID, requester string, req domain.ApprovalRequest, environment domain.DeploymentEnvironment) (*domain.PolicyInput, error) {
+	department, groups, err := s.attributes.GetRequesterAttributes(ctx, requester)
+	if err != nil {
+		return nil, fmt.Errorf("get requester attributes: %w", err)
+	}
+	systemID, err := s.serviceRepo.GetSystemID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent system: %w", err)
+	}
+	var labels map[string]string
+	meta, err := s.metadataRepo.Get(ctx, systemID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		// No System metadata: no labels
+	case err != nil:
+		return nil, fmt.Errorf("get system metadata: %w", err)
+	default:
+		labels = meta.Labels
+	}
+
+	return &domain.PolicyInput{
+		Request: domain.PolicyRequest{
+			Type:        req.RequestType,
+			Namespace:   req.Namespace,
+			Environment: string(environment),
+			CPU:         req.CPU,
+			MemoryMB:    req.MemoryMB,
+			DiskGB:      req.DiskGB,
+		},
+		Requester: domain.PolicyRequester{
+			ID:         requester,
+			Department: department,
+			Groups:     groups,
+			Roles:      req.RequesterRoles,
+		},
+		Service: domain.PolicyService{ID: serviceID, System: systemID, Labels: labels},
+		Now:     s.clock.Now(),
+	}, nil
+}
+
+// RequesterRoles returns the requester's global roles and their roles on
+// the Service and its System, as rules match them.
+func (s *ApprovalPolicyService) RequesterRoles(ctx context.Context, serviceID, requester string) ([]string, error) {
+	global, err := s.roleRepo.ListRoleNames(ctx, requester)
+	if err != nil {
+		return nil, fmt.Errorf("list global roles: %w", err)
+	}
+
+	// ADR-0015 §3: System is resolved via Service
+	systemID, err := s.serviceRepo.GetSystemID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent system: %w", err)
+	}
+	serviceBindings, err := s.bindingRepo.ListByResource(ctx, string(domain.ResourceTypeService), serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("list service bindings: %w", err)
+	}
+	systemBindings, err := s.bindingRepo.ListByResource(ctx, string(domain.ResourceTypeSystem), systemID)
+	if err != nil {
+		return nil, fmt.Errorf("list system bindings: %w", err)
+	}
+
+	return domain.RequesterRoles(requester, global, serviceBindings, systemBindings, s.clock.Now()), nil
+}
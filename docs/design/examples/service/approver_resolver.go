@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ApproverResolver assigns eligible approvers to new tickets.
+//
+// Reads happen BEFORE the submission transaction; the resulting rows are
+// inserted inside it (approval_ticket_approvers), so the assignment is
+// frozen at submission. Later binding changes do not reassign open tickets.
+type ApproverResolver struct {
+	serviceRepo repository.ServiceRepository
+	bindingRepo repository.ResourceRoleBindingRepository
+	roleRepo    repository.RoleBindingRepository
+	clock       domain.Clock
+}
+
+// NewApproverResolver creates a new resolver.
+func NewApproverResolver(
+	serviceRepo repository.ServiceRepository,
+	bindingRepo repository.ResourceRoleBindingRepository,
+	roleRepo repository.RoleBindingRepository,
+	clock domain.Clock,
+) *ApproverResolver {
+	return &ApproverResolver{
+		serviceRepo: serviceRepo,
+		bindingRepo: bindingRepo,
+		roleRepo:    roleRepo,
+		clock:       clock,
+	}
+}
+
+// Resolve returns the approvers of each stage for a ticket on a Service.
+func (r *ApproverResolver) Resolve(ctx context.Context, ticketID, serviceID, requester string, stages []domain.ApprovalStage) ([]*domain.TicketApprover, error) {
+	// ADR-0015 §3: System is resolved via Service, never stored on the ticket
+	systemID, err := r.serviceRepo.GetSystemID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent system: %w", err)
+	}
+
+	serviceBindings, err := r.bindingRepo.ListByResource(ctx, string(domain.ResourceTypeService), serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("list service bindings: %w", err)
+	}
+
+	systemBindings, err := r.bindingRepo.ListByResource(ctx, string(domain.ResourceTypeSystem), systemID)
+	if err != nil {
+		return nil, fmt.Errorf("list system bindings: %w", err)
+	}
+
+	// Fallback pool: global holders of approval:approve
+	admins, err := r.roleRepo.ListUsersWithPermission(ctx, "approval:approve")
+	if err != nil {
+		return nil, fmt.Errorf("list platform approvers: %w", err)
+	}
+
+	return domain.ResolveStageApprovers(ticketID, requester, stages, serviceBindings, systemBindings, admins, r.clock.Now())
+}
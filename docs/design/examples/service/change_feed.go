@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ChangeFeedService serves the status change feed (domain/change_feed.go)
+// to both transports: the long-poll endpoint calls Poll once per request,
+// the stream calls it in a loop.
+type ChangeFeedService struct {
+	permissions domain.PermissionChecker
+	changeRepo  repository.ScopedChangeRepository
+	hub         *repository.ChangeHub
+}
+
+// NewChangeFeedService creates a new service.
+func NewChangeFeedService(
+	permissions domain.PermissionChecker,
+	changeRepo repository.ScopedChangeRepository,
+	hub *repository.ChangeHub,
+) *ChangeFeedService {
+	return &ChangeFeedService{
+		permissions: permissions,
+		changeRepo:  changeRepo,
+		hub:         hub,
+	}
+}
+
+// Poll returns the changes after cursor the user may read, waiting up to
+// timeout for one. An empty cursor returns the head at once, without
+// changes: clients load their lists, then follow from there.
+//
+// A wake-up may bring only changes the user cannot see; the wait then
+// continues until timeout. Changes are served once older than every
+// running transaction, so a long transaction elsewhere may delay them
+// until it ends, at most one timeout after the notification.
+func (s *ChangeFeedService) Poll(ctx context.Context, userID, cursor string, timeout time.Duration) (*domain.ChangePage, error) {
+	after, ok, err := domain.ParseChangeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		head, err := s.changeRepo.Head(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.ChangePage{Changes: []*domain.StatusChange{}, Cursor: head.String()}, nil
+	}
+
+	purged, err := s.changeRepo.PurgedThrough(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if after.Before(purged) {
+		// Changes after the cursor are gone: the client reloads
+		return nil, domain.ErrChangeCursorExpired
+	}
+
+	filter, err := s.filter(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		changed := s.hub.Changed() // Before the query, see Changed
+		changes, next, err := s.changeRepo.ListChanges(ctx, filter, after, domain.MaxChangesPerPoll)
+		if err != nil {
+			return nil, err
+		}
+		if next.Before(after) {
+			next = after
+		}
+		if len(changes) > 0 {
+			return &domain.ChangePage{Changes: changes, Cursor: next.String()}, nil
+		}
+		after = next // Skip what the user cannot see
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return &domain.ChangePage{Changes: []*domain.StatusChange{}, Cursor: after.String()}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// filter resolves the user's scope once per poll. Same action as the
+// event list: tickets and events of the user's own requests are always
+// visible.
+func (s *ChangeFeedService) filter(userID string) (repository.ScopeFilter, error) {
+	scope, err := s.permissions.ResolveScope(userID, "service:read")
+	if err != nil {
+		return repository.ScopeFilter{}, fmt.Errorf("resolve service:read scope: %w", err)
+	}
+	return repository.NewScopeFilter(scope)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// EnvironmentPolicyService evaluates the Service's environment policy for
+// a request (domain/environment_policy.go).
+type EnvironmentPolicyService struct {
+	serviceRepo   repository.ServiceRepository
+	namespaceRepo repository.NamespaceRepository
+	policyRepo    repository.EnvironmentPolicyRepository
+}
+
+// NewEnvironmentPolicyService creates a new service.
+func NewEnvironmentPolicyService(
+	serviceRepo repository.ServiceRepository,
+	namespaceRepo repository.NamespaceRepository,
+	policyRepo repository.EnvironmentPolicyRepository,
+) *EnvironmentPolicyService {
+	return &EnvironmentPolicyService{
+		serviceRepo:   serviceRepo,
+		namespaceRepo: namespaceRepo,
+		policyRepo:    policyRepo,
+	}
+}
+
+// Decide checks the namespace matches the Service's environment class and
+// returns how the request must be approved. A request above the policy's
+// max_size fails with ErrAboveEnvironmentMaxSize.
+func (s *EnvironmentPolicyService) Decide(ctx context.Context, serviceID, namespace string, spec *domain.VMCreationPayload) (*domain.EnvironmentDecision, error) {
+	env, err := s.serviceRepo.GetEnvironment(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("get service environment: %w", err)
+	}
+	nsEnv, err := s.namespaceRepo.GetEnvironment(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("get namespace environment: %w", err)
+	}
+	if err := domain.CheckNamespaceEnvironment(env, nsEnv); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyRepo.Get(ctx, env)
+	if err != nil {
+		return nil, fmt.Errorf("get environment policy: %w", err)
+	}
+	decision := policy.Evaluate(spec)
+	if decision.Refused {
+		return nil, fmt.Errorf("%s: %w", env, domain.ErrAboveEnvironmentMaxSize)
+	}
+	return &decision, nil
+}
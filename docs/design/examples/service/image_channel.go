@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ImageSourceResolver resolves the image a VM is built from
+// (domain/image_channel.go). Called by the creation worker when it builds
+// the VMSpec, so a VM approved before a promotion and created after it
+// gets the promoted digest; the digest is pinned on the VM from then on.
+type ImageSourceResolver struct {
+	channels repository.ImageChannelRepository
+}
+
+// NewImageSourceResolver creates a new resolver.
+func NewImageSourceResolver(channels repository.ImageChannelRepository) *ImageSourceResolver {
+	return &ImageSourceResolver{channels: channels}
+}
+
+// Resolve returns the image source and its digest for t. Templates with a
+// fixed ImageSource are used as-is; the digest is only known when the
+// source is already pinned (repository@sha256:...).
+func (r *ImageSourceResolver) Resolve(ctx context.Context, t *domain.Template) (source, digest string, err error) {
+	if t.ImageChannel == "" {
+		if i := strings.LastIndex(t.ImageSource, "@sha256:"); i >= 0 {
+			return t.ImageSource, t.ImageSource[i+1:], nil
+		}
+		return t.ImageSource, "", nil
+	}
+
+	p, err := r.channels.Get(ctx, t.Image, t.ImageChannel)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", "", fmt.Errorf("%s/%s: %w", t.Image, t.ImageChannel, domain.ErrImageChannelEmpty)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("get image channel: %w", err)
+	}
+	return p.Source(), p.Digest, nil
+}
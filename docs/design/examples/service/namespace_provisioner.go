@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/render"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// NamespaceProvisioner creates Shepherd namespaces on clusters just in
+// time (ADR-0017) and keeps their baseline objects in place
+// (domain/namespace_baseline.go).
+//
+// Provision is called by the VM creation worker before CreateVM; Sync by
+// NamespaceBaselineSyncWorker for every namespace already on a cluster.
+type NamespaceProvisioner struct {
+	providers     *provider.Registry
+	namespaceRepo repository.NamespaceRepository
+	baselineRepo  repository.NamespaceBaselineRepository
+}
+
+// NewNamespaceProvisioner creates a new provisioner.
+func NewNamespaceProvisioner(
+	providers *provider.Registry,
+	namespaceRepo repository.NamespaceRepository,
+	baselineRepo repository.NamespaceBaselineRepository,
+) *NamespaceProvisioner {
+	return &NamespaceProvisioner{
+		providers:     providers,
+		namespaceRepo: namespaceRepo,
+		baselineRepo:  baselineRepo,
+	}
+}
+
+// Provision creates the namespace if missing and applies its baseline.
+// The baseline is applied on every call, so a VM is never created in a
+// namespace whose policies are missing. Returns provider.ErrForbidden
+// (NAMESPACE_PERMISSION_DENIED) when the cluster refuses either.
+func (p *NamespaceProvisioner) Provision(ctx context.Context, cluster, namespace string) error {
+	np, err := p.providers.Namespaces(cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return nil // Provider has no namespaces (e.g. libvirt hosts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := np.EnsureNamespace(ctx, cluster, namespace, map[string]string{
+		domain.LabelManagedBy: domain.ManagedByValue,
+	}); err != nil {
+		return fmt.Errorf("ensure namespace: %w", err)
+	}
+	_, err = p.sync(ctx, np, cluster, namespace)
+	return err
+}
+
+// Sync makes the namespace's governed objects match its baseline and
+// returns what it changed. A namespace with no baseline for its
+// environment keeps no governed objects.
+func (p *NamespaceProvisioner) Sync(ctx context.Context, cluster, namespace string) (*domain.BaselineSyncPlan, error) {
+	np, err := p.providers.Namespaces(cluster)
+	if errors.Is(err, provider.ErrCapabilityUnsupported) {
+		return &domain.BaselineSyncPlan{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.sync(ctx, np, cluster, namespace)
+}
+
+func (p *NamespaceProvisioner) sync(ctx context.Context, np provider.NamespaceProvider, cluster, namespace string) (*domain.BaselineSyncPlan, error) {
+	desired, err := p.desired(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := np.ListGovernedObjects(ctx, cluster, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list governed objects: %w", err)
+	}
+
+	plan := domain.PlanBaselineSync(desired, actual)
+	for _, o := range plan.Apply {
+		if err := np.ApplyGovernedObject(ctx, cluster, o); err != nil {
+			return nil, fmt.Errorf("apply %s: %w", o.Key(), err)
+		}
+	}
+	for _, o := range plan.Delete {
+		if err := np.DeleteGovernedObject(ctx, cluster, namespace, o.Kind, o.Name); err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return nil, fmt.Errorf("delete %s: %w", o.Key(), err)
+		}
+	}
+	return plan, nil
+}
+
+// desired renders the baseline of the namespace's environment.
+func (p *NamespaceProvisioner) desired(ctx context.Context, cluster, namespace string) ([]*domain.GovernedObject, error) {
+	env, err := p.namespaceRepo.GetEnvironment(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("get namespace environment: %w", err)
+	}
+	baseline, err := p.baselineRepo.Get(ctx, env)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get namespace baseline: %w", err)
+	}
+	return render.RenderBaseline(baseline, cluster, namespace)
+}
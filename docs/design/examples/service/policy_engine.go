@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// policyCostLimit bounds the work of one evaluation, so a pathological
+// expression (nested comprehensions over groups) cannot stall submission.
+const policyCostLimit = 10000
+
+// PolicyEngine compiles and evaluates CEL policy expressions
+// (domain/policy_expression.go) against domain.PolicyInput.
+//
+// Programs are cached per policy ID and version: versions are immutable,
+// so a cached program never goes stale, and an edit compiles once on the
+// next submission.
+type PolicyEngine struct {
+	env *cel.Env
+
+	mu       sync.Mutex
+	programs map[string]cel.Program // "id@version"
+}
+
+// NewPolicyEngine creates an engine with the PolicyInput variables
+// declared.
+func NewPolicyEngine() (*PolicyEngine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("requester", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("service", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create cel env: %w", err)
+	}
+	return &PolicyEngine{env: env, programs: make(map[string]cel.Program)}, nil
+}
+
+// Check compiles expression without caching it; used when a policy is
+// saved. It must type-check to a bool.
+func (e *PolicyEngine) Check(expression string) error {
+	_, err := e.compile(expression)
+	return err
+}
+
+// Evaluate returns the result of each policy for in, in order. Disabled
+// policies are evaluated too, so a dry run can show them.
+func (e *PolicyEngine) Evaluate(policies []*domain.PolicyExpression, in *domain.PolicyInput) []domain.PolicyResult {
+	vars := in.Activation()
+	results := make([]domain.PolicyResult, len(policies))
+	for i, p := range policies {
+		results[i] = domain.PolicyResult{Policy: p}
+		prg, err := e.program(p)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		matched, ok := out.Value().(bool)
+		if !ok {
+			results[i].Error = fmt.Sprintf("result is %s, not bool", out.Type())
+			continue
+		}
+		results[i].Matched = matched
+	}
+	return results
+}
+
+// program returns the cached program of p at its version. Drafts (no ID,
+// dry runs) are compiled every time and not cached.
+func (e *PolicyEngine) program(p *domain.PolicyExpression) (cel.Program, error) {
+	if p.ID == "" {
+		return e.compile(p.Expression)
+	}
+	key := fmt.Sprintf("%s@%d", p.ID, p.Version)
+	e.mu.Lock()
+	prg, ok := e.programs[key]
+	e.mu.Unlock()
+	if ok {
+		return prg, nil
+	}
+
+	prg, err := e.compile(p.Expression)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.programs[key] = prg
+	e.mu.Unlock()
+	return prg, nil
+}
+
+func (e *PolicyEngine) compile(expression string) (cel.Program, error) {
+	ast, iss := e.env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("%v: %w", iss.Err(), domain.ErrInvalidPolicyExpression)
+	}
+	// Dyn: a bare map field, e.g. request.flag; checked at evaluation
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression is %s, not bool: %w", t, domain.ErrInvalidPolicyExpression)
+	}
+	prg, err := e.env.Program(ast, cel.CostLimit(policyCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, domain.ErrInvalidPolicyExpression)
+	}
+	return prg, nil
+}
@@ -0,0 +1,164 @@
+// Package service provides the business logic layer (Phase 3).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// RequestDefaultsService resolves Service-level defaults into VM requests.
+//
+// Used by three call sites so they can never disagree:
+//   - GET  /api/v1/services/:id/vm-request/ui-schema  (form pre-population)
+//   - POST /api/v1/services/:id/vm-request/preview    (resolved request)
+//   - POST /api/v1/vms/request                        (before validation)
+type RequestDefaultsService struct {
+	defaultsRepo  repository.ServiceRequestDefaultsRepository
+	templateRepo  repository.TemplateRepository
+	sizeRepo      repository.InstanceSizeRepository
+	namespaceRepo repository.NamespaceRegistryRepository
+}
+
+// NewRequestDefaultsService creates a new service.
+func NewRequestDefaultsService(
+	defaultsRepo repository.ServiceRequestDefaultsRepository,
+	templateRepo repository.TemplateRepository,
+	sizeRepo repository.InstanceSizeRepository,
+	namespaceRepo repository.NamespaceRegistryRepository,
+) *RequestDefaultsService {
+	return &RequestDefaultsService{
+		defaultsRepo:  defaultsRepo,
+		templateRepo:  templateRepo,
+		sizeRepo:      sizeRepo,
+		namespaceRepo: namespaceRepo,
+	}
+}
+
+// RequestInput holds the user-provided values (any may be empty).
+type RequestInput struct {
+	ServiceID      string
+	TemplateID     string
+	InstanceSizeID string
+	Namespace      string
+}
+
+// Resolve applies the Service's defaults to the input.
+// Stale defaults are reported per field, never returned as errors.
+func (s *RequestDefaultsService) Resolve(ctx context.Context, in RequestInput) (*domain.ResolvedRequest, error) {
+	defaults, err := s.defaultsRepo.Get(ctx, in.ServiceID)
+	if errors.Is(err, repository.ErrNotFound) {
+		defaults = &domain.ServiceRequestDefaults{ServiceID: in.ServiceID}
+	} else if err != nil {
+		return nil, fmt.Errorf("get service defaults: %w", err)
+	}
+
+	resolved := &domain.ResolvedRequest{ServiceID: in.ServiceID}
+
+	// Template: default is a name, resolved to the active version's ID
+	templateID, stale, err := s.activeTemplateID(ctx, in.TemplateID, defaults.TemplateName)
+	if err != nil {
+		return nil, err
+	}
+	resolved.TemplateID = domain.ResolveField(in.TemplateID, templateID, stale)
+
+	sizeID, stale, err := s.enabledSizeID(ctx, in.InstanceSizeID, defaults.InstanceSizeName)
+	if err != nil {
+		return nil, err
+	}
+	resolved.InstanceSize = domain.ResolveField(in.InstanceSizeID, sizeID, stale)
+
+	stale, err = s.namespaceStale(ctx, in.Namespace, defaults.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Namespace = domain.ResolveField(in.Namespace, defaults.Namespace, stale)
+
+	return resolved, nil
+}
+
+// activeTemplateID returns the active version ID for a default template name.
+// Lookups are skipped when the user already chose a value.
+func (s *RequestDefaultsService) activeTemplateID(ctx context.Context, userValue, name string) (id, stale string, err error) {
+	if userValue != "" || name == "" {
+		return "", "", nil
+	}
+	t, err := s.templateRepo.GetActive(ctx, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return name, "template has no active version", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("get active template: %w", err)
+	}
+	return t.ID, "", nil
+}
+
+func (s *RequestDefaultsService) enabledSizeID(ctx context.Context, userValue, name string) (id, stale string, err error) {
+	if userValue != "" || name == "" {
+		return "", "", nil
+	}
+	size, err := s.sizeRepo.GetByName(ctx, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return name, "instance size no longer exists", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("get instance size: %w", err)
+	}
+	if !size.Enabled {
+		return name, "instance size is disabled", nil
+	}
+	return size.ID, "", nil
+}
+
+func (s *RequestDefaultsService) namespaceStale(ctx context.Context, userValue, namespace string) (string, error) {
+	if userValue != "" || namespace == "" {
+		return "", nil
+	}
+	_, err := s.namespaceRepo.Get(ctx, namespace)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "namespace is not registered", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get namespace: %w", err)
+	}
+	return "", nil
+}
+
+// Update validates and stores defaults. Unlike Resolve, invalid values are
+// rejected here so owners notice mistakes at configuration time.
+// Caller must hold service:update on the Service (owner/admin binding).
+func (s *RequestDefaultsService) Update(ctx context.Context, d *domain.ServiceRequestDefaults) error {
+	if d.TemplateName != "" {
+		if _, err := s.templateRepo.GetActive(ctx, d.TemplateName); err != nil {
+			return fmt.Errorf("template %s: %w", d.TemplateName, ErrInvalidDefault)
+		}
+	}
+	if d.InstanceSizeName != "" {
+		size, err := s.sizeRepo.GetByName(ctx, d.InstanceSizeName)
+		if err != nil || !size.Enabled {
+			return fmt.Errorf("instance size %s: %w", d.InstanceSizeName, ErrInvalidDefault)
+		}
+	}
+	if d.Namespace != "" {
+		if _, err := s.namespaceRepo.Get(ctx, d.Namespace); err != nil {
+			return fmt.Errorf("namespace %s: %w", d.Namespace, ErrInvalidDefault)
+		}
+	}
+
+	// Upsert, audit log (service.request_defaults_updated) and
+	// NotifyCatalogChanged(CatalogServiceDefaults) in one TX: the Service's
+	// cached request form is rebuilt and re-tagged on every replica
+	if err := s.defaultsRepo.Upsert(ctx, d); err != nil {
+		return fmt.Errorf("save service defaults: %w", err)
+	}
+	return nil
+}
+
+// ErrInvalidDefault is returned when a configured default does not resolve.
+var ErrInvalidDefault = domainerr.Unprocessable("INVALID_SERVICE_DEFAULT", "default value does not reference an active resource")
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// requestFormTables are the catalogs a request form is built from: its
+// cached copy is dropped, re-warmed and re-tagged when any of them changes.
+var requestFormTables = []repository.CatalogTable{
+	repository.CatalogTemplates,
+	repository.CatalogInstanceSizes,
+	repository.CatalogServiceDefaults,
+	repository.CatalogNamespaces,
+}
+
+// FormField describes one field of the request form.
+type FormField struct {
+	Name     string               `json:"name"`
+	Widget   string               `json:"widget"`
+	Required bool                 `json:"required"`
+	Default  domain.ResolvedField `json:"default"`
+}
+
+// RequestForm is the VM request form of a Service, defaults applied.
+// The same for every user of the Service: no user input is resolved.
+type RequestForm struct {
+	ServiceID string      `json:"service_id"`
+	Fields    []FormField `json:"fields"`
+}
+
+// RequestFormService serves the VM request form (ui-schema) from the
+// catalog cache. Forms opened since the last catalog change are rebuilt
+// by the cache warmer right after the next one (repository/catalog_warm.go).
+type RequestFormService struct {
+	defaults *RequestDefaultsService
+	cache    *repository.CatalogCache
+}
+
+// NewRequestFormService creates a new service.
+func NewRequestFormService(defaults *RequestDefaultsService, cache *repository.CatalogCache) *RequestFormService {
+	return &RequestFormService{defaults: defaults, cache: cache}
+}
+
+// Form returns the Service's form and its ETag. The tag changes with any
+// catalog the form is built from, on every replica alike.
+func (s *RequestFormService) Form(ctx context.Context, serviceID string) (*RequestForm, string, error) {
+	etag := s.cache.ETag(requestFormTables...) // Before the load, see ETag
+	form, err := repository.LoadCached(ctx, s.cache, requestFormTables, "request-form:"+serviceID, func(ctx context.Context) (*RequestForm, error) {
+		return s.build(ctx, serviceID)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return form, etag, nil
+}
+
+func (s *RequestFormService) build(ctx context.Context, serviceID string) (*RequestForm, error) {
+	resolved, err := s.defaults.Resolve(ctx, RequestInput{ServiceID: serviceID})
+	if err != nil {
+		return nil, err
+	}
+	return &RequestForm{
+		ServiceID: resolved.ServiceID,
+		Fields: []FormField{
+			{Name: "template_id", Widget: "template-select", Required: true, Default: resolved.TemplateID},
+			{Name: "instance_size_id", Widget: "instance-size-select", Required: true, Default: resolved.InstanceSize},
+			{Name: "namespace", Widget: "namespace-select", Required: true, Default: resolved.Namespace},
+			{Name: "reason", Widget: "textarea", Required: true, Default: domain.ResolvedField{Source: domain.DefaultSourceNone}},
+		},
+	}, nil
+}
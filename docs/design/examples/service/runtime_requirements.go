@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// RuntimeRequirementsChecker builds the /health/requirements report
+// (domain/runtime_requirements.go). It reads the database and the stored
+// cluster capabilities only, so it is cheap enough to poll.
+type RuntimeRequirementsChecker struct {
+	pool     *pgxpool.Pool
+	clusters repository.ClusterRepository
+}
+
+// NewRuntimeRequirementsChecker creates a new checker.
+func NewRuntimeRequirementsChecker(pool *pgxpool.Pool, clusters repository.ClusterRepository) *RuntimeRequirementsChecker {
+	return &RuntimeRequirementsChecker{pool: pool, clusters: clusters}
+}
+
+// Check returns the report. err is for database failures only; unmet
+// requirements are in the report.
+func (c *RuntimeRequirementsChecker) Check(ctx context.Context) (*domain.RuntimeRequirements, error) {
+	var versionNum int
+	var version string
+	err := c.pool.QueryRow(ctx,
+		`SELECT current_setting('server_version_num')::int, current_setting('server_version')`,
+	).Scan(&versionNum, &version)
+	if err != nil {
+		return nil, fmt.Errorf("query server version: %w", err)
+	}
+	rows, err := c.pool.Query(ctx, `SELECT extname FROM pg_extension`)
+	if err != nil {
+		return nil, fmt.Errorf("list extensions: %w", err)
+	}
+	extensions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list extensions: %w", err)
+	}
+
+	clusters, err := c.clusters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	byCluster := make([]domain.ClusterRequirements, 0, len(clusters))
+	for _, cluster := range clusters {
+		// Decommissioning clusters still run VMs; archived ones run nothing
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		if cluster.ProviderType != "" && cluster.ProviderType != provider.ProviderTypeKubeVirt {
+			continue // KubeVirt requirements only
+		}
+		cr := domain.ClusterRequirements{
+			Cluster:      cluster.Name,
+			Requirements: domain.KubeVirtRequirements(cluster.Capabilities),
+		}
+		if cluster.Capabilities != nil {
+			cr.DetectedAt = &cluster.Capabilities.DetectedAt
+		}
+		byCluster = append(byCluster, cr)
+	}
+
+	return domain.NewRuntimeRequirements(
+		domain.DatabaseRequirements(versionNum, version, extensions), byCluster, time.Now(),
+	), nil
+}
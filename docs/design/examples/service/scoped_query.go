@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// ScopedQueryService serves the VM, ticket and event list endpoints, the
+// VM timeline and the VM's restore points.
+//
+// Each list resolves the caller's scope once and passes it to the
+// repository as a query predicate; nothing is filtered after loading.
+type ScopedQueryService struct {
+	permissions domain.PermissionChecker
+	vmRepo      repository.ScopedVMRepository
+	ticketRepo  repository.ScopedTicketRepository
+	eventRepo   repository.ScopedEventRepository
+	timeline    repository.ScopedTimelineRepository
+	restores    repository.RestorePointRepository
+	clock       domain.Clock
+}
+
+// NewScopedQueryService creates a new service.
+func NewScopedQueryService(
+	permissions domain.PermissionChecker,
+	vmRepo repository.ScopedVMRepository,
+	ticketRepo repository.ScopedTicketRepository,
+	eventRepo repository.ScopedEventRepository,
+	timeline repository.ScopedTimelineRepository,
+	restores repository.RestorePointRepository,
+	clock domain.Clock,
+) *ScopedQueryService {
+	return &ScopedQueryService{
+		permissions: permissions,
+		vmRepo:      vmRepo,
+		ticketRepo:  ticketRepo,
+		eventRepo:   eventRepo,
+		timeline:    timeline,
+		restores:    restores,
+		clock:       clock,
+	}
+}
+
+// ListVMs returns the VMs the user may read that match query.
+func (s *ScopedQueryService) ListVMs(ctx context.Context, userID string, query repository.VMQuery, page repository.Page) ([]*domain.VM, string, error) {
+	filter, empty, err := s.filter(userID, "vm:read")
+	if err != nil || empty {
+		// VMs have no "own request" escape: no grants, no rows
+		return nil, "", err
+	}
+	return s.vmRepo.ListVMs(ctx, filter, query, page)
+}
+
+// ListTickets returns tickets the user may read, plus their own requests.
+func (s *ScopedQueryService) ListTickets(ctx context.Context, userID, status string, page repository.Page) ([]*domain.ApprovalTicket, string, error) {
+	filter, _, err := s.filter(userID, "service:read")
+	if err != nil {
+		return nil, "", err
+	}
+	return s.ticketRepo.ListTickets(ctx, filter, status, page)
+}
+
+// ListEvents returns events the user may read, plus their own requests.
+func (s *ScopedQueryService) ListEvents(ctx context.Context, userID string, page repository.Page) ([]*domain.DomainEvent, string, error) {
+	filter, _, err := s.filter(userID, "service:read")
+	if err != nil {
+		return nil, "", err
+	}
+	return s.eventRepo.ListEvents(ctx, filter, page)
+}
+
+// VMTimeline returns a page of the VM's timeline, oldest first. A VM the
+// user may not read is not found; one they may read shows its whole
+// timeline, requests by others included.
+func (s *ScopedQueryService) VMTimeline(ctx context.Context, userID, vmID string, page repository.Page) (*domain.TimelinePage, error) {
+	after, err := domain.ParseTimelineCursor(page.After)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireVM(ctx, userID, vmID); err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether another page exists
+	entries, err := s.timeline.ListVMTimeline(ctx, vmID, after, page.Limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list vm timeline: %w", err)
+	}
+	p := &domain.TimelinePage{Entries: entries}
+	if len(entries) > page.Limit {
+		p.Entries = entries[:page.Limit]
+		p.Next = p.Entries[page.Limit-1].Cursor
+	}
+	return p, nil
+}
+
+// VMRestorePoints returns the VM's unexpired restore points, newest
+// first: platform snapshots and external backups together. A VM the user
+// may not read is not found.
+func (s *ScopedQueryService) VMRestorePoints(ctx context.Context, userID, vmID string) ([]*domain.RestorePoint, error) {
+	if err := s.requireVM(ctx, userID, vmID); err != nil {
+		return nil, err
+	}
+	points, err := s.restores.ListForVM(ctx, vmID, s.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("list restore points: %w", err)
+	}
+	return points, nil
+}
+
+// requireVM returns repository.ErrNotFound unless the user may read the VM.
+func (s *ScopedQueryService) requireVM(ctx context.Context, userID, vmID string) error {
+	filter, empty, err := s.filter(userID, "vm:read")
+	if err != nil {
+		return err
+	}
+	if empty {
+		return repository.ErrNotFound
+	}
+	visible, err := s.timeline.VMInScope(ctx, filter, vmID)
+	if err != nil {
+		return fmt.Errorf("check vm scope: %w", err)
+	}
+	if !visible {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (s *ScopedQueryService) filter(userID, action string) (repository.ScopeFilter, bool, error) {
+	scope, err := s.permissions.ResolveScope(userID, action)
+	if err != nil {
+		return repository.ScopeFilter{}, false, fmt.Errorf("resolve %s scope: %w", action, err)
+	}
+	filter, err := repository.NewScopeFilter(scope)
+	if err != nil {
+		return repository.ScopeFilter{}, false, err
+	}
+	return filter, scope.Empty(), nil
+}
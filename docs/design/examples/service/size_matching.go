@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// SizeMatchingService tells which clusters can host an InstanceSize
+// (domain/size_matching.go). Used by the approval cluster dropdown and to
+// refuse, at submission, a size no cluster of the Service's environment
+// can host.
+type SizeMatchingService struct {
+	sizeRepo    repository.InstanceSizeRepository
+	clusterRepo repository.ClusterRepository
+	serviceRepo repository.ServiceRepository
+}
+
+// NewSizeMatchingService creates a new service.
+func NewSizeMatchingService(
+	sizeRepo repository.InstanceSizeRepository,
+	clusterRepo repository.ClusterRepository,
+	serviceRepo repository.ServiceRepository,
+) *SizeMatchingService {
+	return &SizeMatchingService{
+		sizeRepo:    sizeRepo,
+		clusterRepo: clusterRepo,
+		serviceRepo: serviceRepo,
+	}
+}
+
+// Match returns, for every cluster accepting placements in environment
+// (test, prod), whether it can host the size named name. Hosting
+// clusters come first, then by name.
+func (s *SizeMatchingService) Match(ctx context.Context, name, environment string) ([]domain.SizeMatch, error) {
+	size, err := s.sizeRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get instance size: %w", err)
+	}
+	candidates, err := s.candidates(ctx, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	req := size.Requirements()
+	matches := make([]domain.SizeMatch, len(candidates))
+	for i, c := range candidates {
+		matches[i] = req.MatchCluster(c)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Hosts != matches[j].Hosts {
+			return matches[i].Hosts
+		}
+		return matches[i].Cluster < matches[j].Cluster
+	})
+	return matches, nil
+}
+
+// CheckForService fails with domain.ErrNoClusterForSize when no cluster
+// of the Service's environment can host the size with ID sizeID. Called
+// on submission, so such a request never waits for an approver.
+func (s *SizeMatchingService) CheckForService(ctx context.Context, serviceID, sizeID string) error {
+	size, err := s.sizeRepo.Get(ctx, sizeID)
+	if err != nil {
+		return fmt.Errorf("get instance size: %w", err)
+	}
+	req := size.Requirements()
+	if req.IsZero() {
+		return nil
+	}
+	env, err := s.serviceRepo.GetEnvironment(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("get service environment: %w", err)
+	}
+	candidates, err := s.candidates(ctx, env.NamespaceEnvironment())
+	if err != nil {
+		return err
+	}
+	if len(domain.FilterClustersForSize(req, candidates)) == 0 {
+		return fmt.Errorf("instance size %s in %s: %w", size.Name, env, domain.ErrNoClusterForSize)
+	}
+	return nil
+}
+
+func (s *SizeMatchingService) candidates(ctx context.Context, environment string) ([]*domain.Cluster, error) {
+	clusters, err := s.clusterRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	return domain.FilterPlacementCandidates(clusters, environment), nil
+}
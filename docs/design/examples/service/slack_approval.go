@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+)
+
+// Slack action and view IDs. Button values carry only the ticket ID.
+const (
+	slackActionApprove = "shepherd_approve"
+	slackActionModify  = "shepherd_modify"
+	slackActionReject  = "shepherd_reject"
+
+	slackViewReject  = "shepherd_reject_view"
+	slackViewApprove = "shepherd_approve_modified_view"
+)
+
+// SlackAPI is the subset of *slack.Client used for approvals.
+type SlackAPI interface {
+	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error)
+	OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
+}
+
+// slackMessageRef is kept in modal private metadata so the original
+// approval message can be updated after submission.
+type slackMessageRef struct {
+	TicketID  string `json:"ticket_id"`
+	ChannelID string `json:"channel_id"`
+	MessageTS string `json:"message_ts"`
+}
+
+// SlackApprovalService posts interactive approval messages and applies
+// button clicks and modal submissions through the approval use case.
+//
+// The Slack user is resolved to a platform user first (chat_identities);
+// the decision then runs as that user with the same SoD and assignment
+// checks as the web UI. Unmapped users are refused, never defaulted.
+type SlackApprovalService struct {
+	api          SlackAPI
+	identityRepo repository.ChatIdentityRepository
+	userRepo     repository.UserRepository
+	ticketRepo   repository.ApprovalTicketRepository
+	eventRepo    repository.DomainEventRepository
+	decider      TicketDecider
+	channelID    string // slack.approval_channel
+	mapByEmail   bool   // slack.map_by_email
+}
+
+// NewSlackApprovalService creates a new service.
+func NewSlackApprovalService(
+	api SlackAPI,
+	identityRepo repository.ChatIdentityRepository,
+	userRepo repository.UserRepository,
+	ticketRepo repository.ApprovalTicketRepository,
+	eventRepo repository.DomainEventRepository,
+	decider TicketDecider,
+	channelID string,
+	mapByEmail bool,
+) *SlackApprovalService {
+	return &SlackApprovalService{
+		api:          api,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		ticketRepo:   ticketRepo,
+		eventRepo:    eventRepo,
+		decider:      decider,
+		channelID:    channelID,
+		mapByEmail:   mapByEmail,
+	}
+}
+
+// PostApprovalRequest posts the approval message for a new ticket.
+func (s *SlackApprovalService) PostApprovalRequest(ctx context.Context, ticket *domain.ApprovalTicket, spec *domain.VMCreationPayload) error {
+	summary := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf(
+		"*%s* requested by %s\n>%s\nCPU: %d · Memory: %d MiB · Disk: %d GiB",
+		ticket.RequestType, ticket.CreatedBy, ticket.RequestReason, spec.CPU, spec.MemoryMB, spec.DiskGB,
+	), false, false)
+
+	approve := slack.NewButtonBlockElement(slackActionApprove, ticket.TicketID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false)).WithStyle(slack.StylePrimary)
+	modify := slack.NewButtonBlockElement(slackActionModify, ticket.TicketID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Modify & approve", false, false))
+	reject := slack.NewButtonBlockElement(slackActionReject, ticket.TicketID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Reject", false, false)).WithStyle(slack.StyleDanger)
+
+	_, _, err := s.api.PostMessageContext(ctx, s.channelID, slack.MsgOptionBlocks(
+		slack.NewSectionBlock(summary, nil, nil),
+		slack.NewActionBlock("ticket_"+ticket.TicketID, approve, modify, reject),
+	))
+	if err != nil {
+		return fmt.Errorf("post slack approval message: %w", err)
+	}
+	return nil
+}
+
+// HandleBlockAction handles a button click. Approve decides immediately;
+// modify and reject open a modal.
+func (s *SlackApprovalService) HandleBlockAction(ctx context.Context, cb *slack.InteractionCallback) error {
+	if len(cb.ActionCallback.BlockActions) == 0 {
+		return nil
+	}
+	action := cb.ActionCallback.BlockActions[0]
+	ref := slackMessageRef{TicketID: action.Value, ChannelID: cb.Container.ChannelID, MessageTS: cb.Container.MessageTs}
+
+	userID, err := s.resolveUser(ctx, cb.Team.ID, cb.User.ID)
+	if err != nil {
+		return s.replyError(ctx, cb, err)
+	}
+	ticket, err := s.ticketRepo.Get(ctx, ref.TicketID)
+	if err != nil {
+		return fmt.Errorf("get ticket: %w", err)
+	}
+	if ticket.Status != domain.TicketPendingApproval {
+		return s.markDecided(ctx, ref, fmt.Sprintf("Already %s.", strings.ToLower(string(ticket.Status))))
+	}
+
+	switch action.ActionID {
+	case slackActionApprove:
+		progress, err := s.decider.ApproveAndEnqueue(ctx, ref.TicketID, userID, nil, nil)
+		if err != nil {
+			return s.replyError(ctx, cb, err)
+		}
+		if !progress.Approved {
+			return s.replyProgress(ctx, cb, progress)
+		}
+		return s.markDecided(ctx, ref, fmt.Sprintf("Approved by <@%s>.", cb.User.ID))
+	case slackActionReject:
+		return s.openView(ctx, cb.TriggerID, rejectView(ref))
+	case slackActionModify:
+		event, err := s.eventRepo.Get(ctx, ticket.EventID)
+		if err != nil {
+			return fmt.Errorf("get event: %w", err)
+		}
+		if err := event.Upcast(); err != nil {
+			return err
+		}
+		spec, err := domain.GetEffectiveSpec[domain.VMCreationPayload](event.EventType, event.Payload, ticket.ModifiedSpec)
+		if err != nil {
+			return fmt.Errorf("resolve effective spec: %w", err)
+		}
+		return s.openView(ctx, cb.TriggerID, modifyView(ref, &spec))
+	}
+	return nil
+}
+
+// HandleViewSubmission applies a submitted reject or modify modal.
+// A non-nil response is returned to Slack to show field errors in the modal.
+func (s *SlackApprovalService) HandleViewSubmission(ctx context.Context, cb *slack.InteractionCallback) (*slack.ViewSubmissionResponse, error) {
+	var ref slackMessageRef
+	if err := json.Unmarshal([]byte(cb.View.PrivateMetadata), &ref); err != nil {
+		return nil, fmt.Errorf("decode view metadata: %w", err)
+	}
+	userID, err := s.resolveUser(ctx, cb.Team.ID, cb.User.ID)
+	if err != nil {
+		return viewError("reason", err), nil
+	}
+
+	values := cb.View.State.Values
+	reason := strings.TrimSpace(values["reason"]["reason"].Value)
+
+	switch cb.View.CallbackID {
+	case slackViewReject:
+		if err := domain.ValidateRejectReason(reason); err != nil {
+			return viewError("reason", err), nil
+		}
+		if err := s.decider.RejectTicket(ctx, ref.TicketID, userID, reason); err != nil {
+			return viewError("reason", err), nil
+		}
+		return nil, s.markDecided(ctx, ref, fmt.Sprintf("Rejected by <@%s>: %s", cb.User.ID, reason))
+
+	case slackViewApprove:
+		mods := &domain.ModifiedSpec{ModifiedBy: userID, ModifiedReason: reason}
+		fields := []struct {
+			block string
+			dst   **int
+		}{{"cpu", &mods.CPU}, {"memory_mb", &mods.MemoryMB}, {"disk_gb", &mods.DiskGB}}
+		for _, f := range fields {
+			n, err := strconv.Atoi(strings.TrimSpace(values[f.block][f.block].Value))
+			if err != nil || n <= 0 {
+				return slack.NewErrorsViewSubmissionResponse(map[string]string{f.block: "Enter a positive whole number"}), nil
+			}
+			*f.dst = &n
+		}
+		progress, err := s.decider.ApproveAndEnqueue(ctx, ref.TicketID, userID, mods, nil)
+		if err != nil {
+			return viewError("reason", err), nil
+		}
+		if !progress.Approved {
+			return nil, s.replyProgress(ctx, cb, progress)
+		}
+		return nil, s.markDecided(ctx, ref, fmt.Sprintf("Approved with modifications by <@%s>.", cb.User.ID))
+	}
+	return nil, nil
+}
+
+// resolveUser maps a Slack user to a platform user, linking by
+// IdP-verified email on first use when slack.map_by_email is set.
+func (s *SlackApprovalService) resolveUser(ctx context.Context, teamID, slackUserID string) (string, error) {
+	identity, err := s.identityRepo.Get(ctx, domain.ChatProviderSlack, teamID, slackUserID)
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return "", fmt.Errorf("get chat identity: %w", err)
+	}
+	if !s.mapByEmail {
+		return "", domain.ErrChatIdentityUnmapped
+	}
+
+	su, err := s.api.GetUserInfoContext(ctx, slackUserID)
+	if err != nil {
+		return "", fmt.Errorf("get slack user: %w", err)
+	}
+	if su.Profile.Email == "" {
+		return "", domain.ErrChatIdentityUnmapped
+	}
+	user, err := s.userRepo.GetByVerifiedEmail(ctx, su.Profile.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", domain.ErrChatIdentityUnmapped
+	}
+	if err != nil {
+		return "", fmt.Errorf("get user by email: %w", err)
+	}
+
+	err = s.identityRepo.Create(ctx, &domain.ChatIdentity{
+		Provider:       domain.ChatProviderSlack,
+		WorkspaceID:    teamID,
+		ExternalUserID: slackUserID,
+		UserID:         user.ID,
+		Source:         domain.ChatIdentityEmail,
+	})
+	if err != nil {
+		return "", fmt.Errorf("link chat identity: %w", err)
+	}
+	logger.InfoCtx(logger.WithUser(ctx, user.ID), "Linked Slack user by email",
+		zap.String("slack_user_id", slackUserID),
+	)
+	return user.ID, nil
+}
+
+// markDecided replaces the buttons with the outcome so the message cannot
+// be clicked again.
+func (s *SlackApprovalService) markDecided(ctx context.Context, ref slackMessageRef, outcome string) error {
+	_, _, _, err := s.api.UpdateMessageContext(ctx, ref.ChannelID, ref.MessageTS, slack.MsgOptionBlocks(
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType,
+			fmt.Sprintf("Ticket `%s`: %s", ref.TicketID, outcome), false, false), nil, nil),
+	))
+	if err != nil {
+		return fmt.Errorf("update slack message: %w", err)
+	}
+	return nil
+}
+
+// replyError tells only the clicking user why the action was refused.
+func (s *SlackApprovalService) replyError(ctx context.Context, cb *slack.InteractionCallback, err error) error {
+	_, postErr := s.api.PostEphemeralContext(ctx, cb.Container.ChannelID, cb.User.ID, slack.MsgOptionText(slackErrorText(err), false))
+	if postErr != nil {
+		return fmt.Errorf("post slack ephemeral: %w", postErr)
+	}
+	return nil
+}
+
+// replyProgress tells the approver their approval counted but more are
+// needed. The message keeps its buttons for the next approver.
+func (s *SlackApprovalService) replyProgress(ctx context.Context, cb *slack.InteractionCallback, p *domain.ApprovalProgress) error {
+	text := fmt.Sprintf("Approval recorded (%d of %d). Another approver must also approve.", p.Approvals, p.Required)
+	if p.Stages > 1 {
+		text = fmt.Sprintf("Approval recorded. Stage %d of %d: %d of %d approvals.", p.Stage+1, p.Stages, p.Approvals, p.Required)
+	}
+	if _, err := s.api.PostEphemeralContext(ctx, cb.Container.ChannelID, cb.User.ID, slack.MsgOptionText(text, false)); err != nil {
+		return fmt.Errorf("post slack ephemeral: %w", err)
+	}
+	return nil
+}
+
+func (s *SlackApprovalService) openView(ctx context.Context, triggerID string, view slack.ModalViewRequest) error {
+	if _, err := s.api.OpenViewContext(ctx, triggerID, view); err != nil {
+		return fmt.Errorf("open slack view: %w", err)
+	}
+	return nil
+}
+
+func slackErrorText(err error) string {
+	var sod *domain.SoDViolation
+	var pre *domain.PreconditionFailed
+	switch {
+	case errors.Is(err, domain.ErrChatIdentityUnmapped):
+		return "Your Slack account is not linked to a Shepherd user. Ask a platform admin to link it."
+	case errors.As(err, &sod):
+		return "You cannot decide this ticket: " + sod.Error()
+	case errors.As(err, &pre):
+		return "Not approved, the request no longer fits: " + pre.Error() + ". Reduce the spec or reject it."
+	default:
+		return "Action failed: " + err.Error()
+	}
+}
+
+func viewError(block string, err error) *slack.ViewSubmissionResponse {
+	return slack.NewErrorsViewSubmissionResponse(map[string]string{block: slackErrorText(err)})
+}
+
+func rejectView(ref slackMessageRef) slack.ModalViewRequest {
+	return modalView(slackViewReject, "Reject request", "Reject", ref,
+		textInput("reason", "Reason", "", true),
+	)
+}
+
+func modifyView(ref slackMessageRef, spec *domain.VMCreationPayload) slack.ModalViewRequest {
+	return modalView(slackViewApprove, "Modify & approve", "Approve", ref,
+		textInput("cpu", "CPU", strconv.Itoa(spec.CPU), false),
+		textInput("memory_mb", "Memory (MiB)", strconv.Itoa(spec.MemoryMB), false),
+		textInput("disk_gb", "Disk (GiB)", strconv.Itoa(spec.DiskGB), false),
+		textInput("reason", "Reason for modification", "", true),
+	)
+}
+
+func modalView(callbackID, title, submit string, ref slackMessageRef, blocks ...slack.Block) slack.ModalViewRequest {
+	meta, _ := json.Marshal(ref)
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      callbackID,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, submit, false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Blocks:          slack.Blocks{BlockSet: blocks},
+		PrivateMetadata: string(meta),
+	}
+}
+
+// textInput uses the same ID for block and action, so values are read as
+// State.Values[id][id].
+func textInput(id, label, initial string, multiline bool) *slack.InputBlock {
+	el := slack.NewPlainTextInputBlockElement(nil, id)
+	el.InitialValue = initial
+	el.Multiline = multiline
+	return slack.NewInputBlock(id, slack.NewTextBlockObject(slack.PlainTextType, label, false, false), nil, el)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+)
+
+// PortProber checks that a TCP address accepts connections.
+// Replaceable where Shepherd cannot reach VM networks directly (e.g. a
+// prober running inside the cluster).
+type PortProber interface {
+	Probe(ctx context.Context, addr string) error
+}
+
+// TCPProber dials the address from the Shepherd pod.
+type TCPProber struct {
+	Timeout time.Duration
+}
+
+// Probe opens and immediately closes a TCP connection.
+func (p TCPProber) Probe(ctx context.Context, addr string) error {
+	d := net.Dialer{Timeout: p.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// WarmupVerifier runs the configured warm-up checks against a VM.
+type WarmupVerifier struct {
+	vms        provider.InfrastructureProvider
+	prober     PortProber
+	guestAgent bool
+	port       int
+}
+
+// NewWarmupVerifier creates a new verifier. port 0 skips the port check.
+func NewWarmupVerifier(vms provider.InfrastructureProvider, prober PortProber, guestAgent bool, port int) *WarmupVerifier {
+	return &WarmupVerifier{vms: vms, prober: prober, guestAgent: guestAgent, port: port}
+}
+
+// Verify runs one attempt. Checks that cannot run yet (no IP) fail with
+// a detail instead of returning an error; err is only for provider failures.
+func (v *WarmupVerifier) Verify(ctx context.Context, cluster, namespace, name string) (*domain.WarmupReport, error) {
+	vm, err := v.vms.GetVM(ctx, cluster, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+
+	report := &domain.WarmupReport{CheckedAt: time.Now()}
+	running := vm.Status == domain.VMStatusRunning
+	report.Results = append(report.Results, domain.WarmupResult{
+		Check:  domain.WarmupCheckRunning,
+		Passed: running,
+		Detail: string(vm.Status),
+	})
+	if !running {
+		return report, nil // Guest checks are meaningless until the VM runs
+	}
+
+	if v.guestAgent {
+		res := domain.WarmupResult{Check: domain.WarmupCheckGuestAgent, Passed: vm.GuestAgentConnected}
+		if !res.Passed {
+			res.Detail = "guest agent not connected"
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	if v.port > 0 {
+		res := domain.WarmupResult{Check: domain.WarmupCheckPort}
+		if vm.IP == "" {
+			res.Detail = "no IP reported"
+		} else if err := v.prober.Probe(ctx, net.JoinHostPort(vm.IP, strconv.Itoa(v.port))); err != nil {
+			res.Detail = err.Error()
+		} else {
+			res.Passed = true
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
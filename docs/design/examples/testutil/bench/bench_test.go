@@ -0,0 +1,12 @@
+package bench_test
+
+import (
+	"testing"
+
+	"kv-shepherd.io/shepherd/internal/testutil/bench"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+func BenchmarkSubmitAndEnqueue(b *testing.B) { bench.SubmitAndEnqueue(b, pgtest.New(b)) }
+func BenchmarkTicketInbox(b *testing.B)      { bench.TicketInbox(b, pgtest.New(b)) }
+func BenchmarkVMList(b *testing.B)           { bench.VMList(b, pgtest.New(b)) }
@@ -0,0 +1,268 @@
+// Package bench holds the hot-path database benchmarks.
+//
+// The benchmark bodies live here, outside _test.go files, so the perf
+// smoke (make perf-smoke) and ad-hoc profiling run the same code. The
+// package's bench_test.go only wires them to a database:
+//
+//	func BenchmarkSubmitAndEnqueue(b *testing.B) { bench.SubmitAndEnqueue(b, pgtest.New(b)) }
+//
+// Each benchmark gets a fresh migrated database (pgtest.New), so the
+// results measure the schema and queries, not leftovers of other runs.
+// Fixture sizes are fixed: a schema change that makes a query slower at
+// these sizes shows up against the committed baseline
+// (ci/scripts/check_bench_regression.go).
+//
+// Import Path: kv-shepherd.io/shepherd/internal/testutil/bench
+package bench
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+	"kv-shepherd.io/shepherd/internal/testutil/factory"
+	"kv-shepherd.io/shepherd/internal/testutil/pgtest"
+)
+
+// Fixture sizes. Changing them invalidates the baseline.
+const (
+	services          = 50  // Spread over 10 systems
+	ticketsPerService = 100 // 5,000 tickets, a quarter still pending
+	vmsPerService     = 100 // 5,000 VMs
+	grantedServices   = 5   // Services the benchmarked user is bound to
+	pageLimit         = 50  // As the list endpoints' default page
+)
+
+// SubmitAndEnqueue measures the ADR-0012 write path of an auto-approved
+// request: event insert, ticket create and River job insert in one
+// transaction, committed. Fixtures are built before the timer starts.
+func SubmitAndEnqueue(b *testing.B, db *pgtest.DB) {
+	ctx := context.Background()
+	riverClient, err := river.NewClient(riverpgxv5.New(db.Pool), &river.Config{}) // Insert-only
+	if err != nil {
+		b.Fatalf("create river client: %v", err)
+	}
+
+	events := make([]*domain.DomainEvent, b.N)
+	tickets := make([]*domain.ApprovalTicket, b.N)
+	for i := range events {
+		events[i] = factory.CreationEvent(factory.CreationPayload())
+		tickets[i] = factory.Ticket(events[i], factory.Approved("bench-approver"))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := submit(ctx, db, riverClient, events[i], tickets[i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func submit(ctx context.Context, db *pgtest.DB, riverClient *river.Client[pgx.Tx], event *domain.DomainEvent, ticket *domain.ApprovalTicket) error {
+	tx, err := db.Pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := db.SqlcQueries.WithTx(tx)
+	err = sqlcTx.CreateDomainEvent(ctx, sqlc.CreateDomainEventParams{
+		EventID:       event.EventID,
+		EventType:     string(event.EventType),
+		SchemaVersion: event.SchemaVersion,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		Status:        string(domain.EventStatusProcessing),
+		CreatedBy:     event.CreatedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("create domain event: %w", err)
+	}
+	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+		TicketID:          ticket.TicketID,
+		EventID:           ticket.EventID,
+		ServiceID:         ticket.ServiceID,
+		RequestType:       ticket.RequestType,
+		RequestReason:     ticket.RequestReason,
+		Status:            string(ticket.Status),
+		Priority:          string(ticket.Priority),
+		PriorityRank:      int16(ticket.Priority.Rank()),
+		Environment:       string(ticket.Environment),
+		RequiredApprovals: int32(ticket.RequiredApprovals),
+		Stages:            ticket.Stages,
+		CreatedBy:         ticket.CreatedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("create approval ticket: %w", err)
+	}
+	_, err = riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: event.EventID}, &river.InsertOpts{
+		Queue: ticket.Priority.Queue(),
+	})
+	if err != nil {
+		return fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// TicketInbox measures the approver inbox: the first page of pending
+// tickets in a user's scope, in inbox order (priority, age).
+func TicketInbox(b *testing.B, db *pgtest.DB) {
+	ctx := context.Background()
+	serviceIDs := seedServices(b, db)
+	seedTickets(b, db, serviceIDs)
+	filter := scopeFilter(b, serviceIDs[:grantedServices])
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.SqlcQueries.ListTicketsScoped(ctx, sqlc.ListTicketsScopedParams{
+			AllAccess: filter.AllAccess,
+			UserID:    filter.UserID,
+			Grants:    filter.Grants,
+			Status:    string(domain.TicketPendingApproval),
+			PageLimit: pageLimit,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(rows) == 0 {
+			b.Fatal("inbox is empty: fixtures are wrong")
+		}
+	}
+}
+
+// VMList measures the VM list projection: the first page of VMs in a
+// user's scope, through the Service → System and namespace joins.
+func VMList(b *testing.B, db *pgtest.DB) {
+	ctx := context.Background()
+	serviceIDs := seedServices(b, db)
+	seedVMs(b, db, serviceIDs)
+	filter := scopeFilter(b, serviceIDs[:grantedServices])
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.SqlcQueries.ListVMsScoped(ctx, sqlc.ListVMsScopedParams{
+			AllAccess: filter.AllAccess,
+			Grants:    filter.Grants,
+			PageLimit: pageLimit,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(rows) == 0 {
+			b.Fatal("VM list is empty: fixtures are wrong")
+		}
+	}
+}
+
+// seedServices creates the systems, services and the test namespace the
+// scoped queries join, and returns the service IDs.
+func seedServices(b *testing.B, db *pgtest.DB) []string {
+	b.Helper()
+	ctx := context.Background()
+
+	if _, err := db.EntClient.NamespaceRegistry.Create().
+		SetName(factory.Namespace).
+		SetEnvironment("test").
+		Save(ctx); err != nil {
+		b.Fatalf("seed namespace: %v", err)
+	}
+
+	ids := make([]string, services)
+	for i := range ids {
+		systemID := fmt.Sprintf("sys-%02d", i%10)
+		if i < 10 {
+			if _, err := db.EntClient.System.Create().
+				SetID(systemID).
+				SetName(systemID).
+				SetCreatedBy("bench").
+				Save(ctx); err != nil {
+				b.Fatalf("seed system: %v", err)
+			}
+		}
+		ids[i] = fmt.Sprintf("svc-%02d", i)
+		if _, err := db.EntClient.Service.Create().
+			SetID(ids[i]).
+			SetName(ids[i]).
+			SetSystemID(systemID).
+			SetCreatedBy("bench").
+			Save(ctx); err != nil {
+			b.Fatalf("seed service: %v", err)
+		}
+	}
+	return ids
+}
+
+func seedTickets(b *testing.B, db *pgtest.DB, serviceIDs []string) {
+	b.Helper()
+	var objs []any
+	for _, serviceID := range serviceIDs {
+		for i := 0; i < ticketsPerService; i++ {
+			payload := factory.CreationPayload(func(p *domain.VMCreationPayload) { p.ServiceID = serviceID })
+			event := factory.CreationEvent(payload)
+			ticket := factory.Ticket(event, func(t *domain.ApprovalTicket) {
+				t.ServiceID = serviceID
+				t.CreatedBy = fmt.Sprintf("user-%d", i%20)
+			})
+			if i%4 != 0 {
+				factory.Approved("bench-approver")(ticket)
+			}
+			objs = append(objs, event, ticket)
+		}
+	}
+	seed(b, db, objs)
+}
+
+func seedVMs(b *testing.B, db *pgtest.DB, serviceIDs []string) {
+	b.Helper()
+	var objs []any
+	for _, serviceID := range serviceIDs {
+		for i := 0; i < vmsPerService; i++ {
+			objs = append(objs, factory.VM(func(vm *domain.VM) { vm.ServiceID = serviceID }))
+		}
+	}
+	seed(b, db, objs)
+}
+
+// seed inserts the fixtures and refreshes planner statistics, so queries
+// are planned as in production rather than on empty-table estimates.
+func seed(b *testing.B, db *pgtest.DB, objs []any) {
+	b.Helper()
+	factory.Seed(b, db, objs...)
+	if _, err := db.Pool.Exec(context.Background(), "ANALYZE"); err != nil {
+		b.Fatalf("analyze: %v", err)
+	}
+}
+
+// scopeFilter is the scope of a user bound to serviceIDs, as
+// ResolveScope would build it.
+func scopeFilter(b *testing.B, serviceIDs []string) repository.ScopeFilter {
+	b.Helper()
+	scope := &domain.AccessScope{UserID: "bench-user"}
+	for _, id := range serviceIDs {
+		scope.Grants = append(scope.Grants, domain.ScopeGrant{
+			ResourceType: domain.ResourceTypeService,
+			ResourceID:   id,
+		})
+	}
+	filter, err := repository.NewScopeFilter(scope)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return filter
+}
@@ -0,0 +1,61 @@
+package factory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock is a domain.Clock for tests. It starts at Now and only moves when
+// the test says so, so TTL and SLA boundaries can be crossed exactly.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock stopped at Now.
+func NewClock() *Clock {
+	return &Clock{now: Now}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// IDs is a domain.IDGenerator for tests: "<prefix>-1", "<prefix>-2", ... in
+// call order, so a test can predict the IDs a use case will create.
+type IDs struct {
+	mu     sync.Mutex
+	prefix string
+	n      int
+}
+
+// NewIDs returns a generator counting from 1 under prefix.
+func NewIDs(prefix string) *IDs {
+	return &IDs{prefix: prefix}
+}
+
+// NewID returns the next ID.
+func (g *IDs) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("%s-%d", g.prefix, g.n)
+}
@@ -0,0 +1,202 @@
+// Package factory builds valid domain objects for tests.
+//
+// Every builder returns an object that passes the domain's own checks,
+// with unique IDs and the fixed time Now. Tests override only the fields
+// they are about, through option funcs:
+//
+//	vm := factory.VM(func(vm *domain.VM) { vm.CPU = 8 })
+//	ticket := factory.Ticket(factory.CreationEvent(factory.CreationPayload()), factory.Approved("bob"))
+//
+// Builders never touch the database; Seed persists what they build
+// (seed.go).
+//
+// Import Path: kv-shepherd.io/shepherd/internal/testutil/factory
+package factory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+)
+
+// Now is the time builders stamp. Tests comparing times use it instead of
+// time.Now.
+var Now = time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+// Defaults shared by the builders, so related objects line up without
+// wiring: a VM, payload and binding built with no options all belong to
+// ServiceID.
+const (
+	ServiceID = "svc-redis"
+	Namespace = "dev-shop"
+	Cluster   = "cluster-a"
+	Requester = "alice"
+)
+
+// Option modifies an object after the defaults are applied.
+type Option[T any] func(*T)
+
+var seq atomic.Int64
+
+// ID returns a unique ID with prefix, e.g. "vm-0007". Unique per test
+// binary, so parallel tests sharing a database do not collide.
+func ID(prefix string) string {
+	return fmt.Sprintf("%s-%04d", prefix, seq.Add(1))
+}
+
+func apply[T any](v *T, opts []Option[T]) *T {
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// VM returns a running 2 vCPU / 4 GiB VM of ServiceID in Namespace.
+func VM(opts ...Option[domain.VM]) *domain.VM {
+	id := ID("vm")
+	return apply(&domain.VM{
+		ID:        id,
+		Name:      Namespace + "-shop-redis-" + id[len(id)-2:],
+		Namespace: Namespace,
+		Cluster:   Cluster,
+		ServiceID: ServiceID,
+		Instance:  id[len(id)-2:],
+		CPU:       2,
+		MemoryMB:  4096,
+		DiskGB:    50,
+		Template:  "ubuntu-24.04",
+		Status:    domain.VMStatusRunning,
+	}, opts)
+}
+
+// CreationPayload returns a small VM creation request for ServiceID.
+func CreationPayload(opts ...Option[domain.VMCreationPayload]) domain.VMCreationPayload {
+	return *apply(&domain.VMCreationPayload{
+		ServiceID:  ServiceID,
+		TemplateID: "tpl-ubuntu",
+		CPU:        2,
+		MemoryMB:   4096,
+		DiskGB:     50,
+		Reason:     "test",
+	}, opts)
+}
+
+// CreationEvent returns a PENDING VM_CREATION_REQUESTED event carrying p.
+func CreationEvent(p domain.VMCreationPayload, opts ...Option[domain.DomainEvent]) *domain.DomainEvent {
+	id := ID("evt")
+	payload, _ := json.Marshal(p) // Not MarshalPayload: tests seed malformed payloads too
+	return apply(&domain.DomainEvent{
+		EventID:       id,
+		EventType:     domain.EventVMCreationRequested,
+		AggregateType: "VM",
+		AggregateID:   p.ServiceID + "-" + id, // Temporary ID, as for real submissions
+		Payload:       payload,
+		SchemaVersion: domain.PayloadVersion(domain.EventVMCreationRequested),
+		Status:        domain.EventStatusPending,
+		CreatedBy:     Requester,
+		CreatedAt:     Now,
+	}, opts)
+}
+
+// Event returns a PENDING event of any type on aggregateID.
+func Event(eventType domain.EventType, aggregateID string, payload []byte, opts ...Option[domain.DomainEvent]) *domain.DomainEvent {
+	return apply(&domain.DomainEvent{
+		EventID:       ID("evt"),
+		EventType:     eventType,
+		AggregateType: "VM",
+		AggregateID:   aggregateID,
+		Payload:       payload,
+		SchemaVersion: domain.PayloadVersion(eventType), // Override to seed an old payload
+		Status:        domain.EventStatusPending,
+		CreatedBy:     Requester,
+		CreatedAt:     Now,
+	}, opts)
+}
+
+// Ticket returns a dev ticket for event, pending one approval, with its
+// SLA due time set as at submission.
+func Ticket(event *domain.DomainEvent, opts ...Option[domain.ApprovalTicket]) *domain.ApprovalTicket {
+	policy := domain.DefaultEnvironmentPolicies[0] // dev
+	return apply(&domain.ApprovalTicket{
+		TicketID:          ID("tkt"),
+		EventID:           event.EventID,
+		ServiceID:         ServiceID,
+		RequestType:       "CREATE_VM",
+		RequestReason:     "test",
+		Status:            domain.TicketPendingApproval,
+		Priority:          domain.PriorityNormal,
+		Environment:       policy.Environment,
+		RequiredApprovals: policy.RequiredApprovals,
+		Stages:            domain.DefaultStages(policy.RequiredApprovals),
+		CreatedBy:         event.CreatedBy,
+		SLADueAt:          domain.SLADueAt(Now, policy.ApprovalSLA, domain.PriorityNormal),
+		CreatedAt:         Now,
+		UpdatedAt:         Now,
+	}, opts)
+}
+
+// Approved marks a ticket approved by approver.
+func Approved(approver string) Option[domain.ApprovalTicket] {
+	return func(t *domain.ApprovalTicket) {
+		t.Status = domain.TicketApproved
+		t.ApprovedBy = approver
+		t.SLADueAt = nil
+	}
+}
+
+// Prod makes a ticket a prod ticket under the default prod policy.
+func Prod() Option[domain.ApprovalTicket] {
+	return func(t *domain.ApprovalTicket) {
+		policy := domain.DefaultEnvironmentPolicies[2]
+		t.Environment = policy.Environment
+		t.RequiredApprovals = policy.RequiredApprovals
+		t.Stages = domain.DefaultStages(policy.RequiredApprovals)
+		t.SLADueAt = domain.SLADueAt(t.CreatedAt, policy.ApprovalSLA, t.Priority)
+	}
+}
+
+// InstanceSize returns an enabled 2 vCPU / 4Gi size without special
+// hardware.
+func InstanceSize(opts ...Option[domain.InstanceSize]) *domain.InstanceSize {
+	id := ID("size")
+	return apply(&domain.InstanceSize{
+		ID:        id,
+		Name:      "small-" + id,
+		CPUCores:  2,
+		Memory:    "4Gi",
+		Enabled:   true,
+		CreatedAt: Now,
+		UpdatedAt: Now,
+	}, opts)
+}
+
+// RoleBinding grants userID role on ServiceID, without expiry.
+func RoleBinding(userID string, role domain.ResourceRole, opts ...Option[domain.ResourceRoleBinding]) *domain.ResourceRoleBinding {
+	return apply(&domain.ResourceRoleBinding{
+		ID:           ID("rb"),
+		UserID:       userID,
+		Role:         string(role),
+		ResourceType: string(domain.ResourceTypeService),
+		ResourceID:   ServiceID,
+		GrantedBy:    "admin",
+		CreatedAt:    Now,
+	}, opts)
+}
+
+// OnSystem moves a binding to a System.
+func OnSystem(systemID string) Option[domain.ResourceRoleBinding] {
+	return func(b *domain.ResourceRoleBinding) {
+		b.ResourceType = string(domain.ResourceTypeSystem)
+		b.ResourceID = systemID
+	}
+}
+
+// ExpiresAt sets a binding's expiry.
+func ExpiresAt(t time.Time) Option[domain.ResourceRoleBinding] {
+	return func(b *domain.ResourceRoleBinding) {
+		b.ExpiresAt = &t
+	}
+}
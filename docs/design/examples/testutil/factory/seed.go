@@ -0,0 +1,101 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"kv-shepherd.io/shepherd/ent"
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// Store is where Seed writes: a *pgtest.DB, or a *pgtest.TxDB to have the
+// fixtures rolled back with the test's transaction.
+type Store interface {
+	Queries() *sqlc.Queries
+	Ent() *ent.Client
+}
+
+// Seed inserts objs in order, failing the test on the first error. Events
+// and tickets go through the same sqlc queries as the use cases; VMs,
+// sizes and bindings through Ent, like their repositories (ADR-0012).
+// Insert parents first: an event before its ticket.
+func Seed(t testing.TB, db Store, objs ...any) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, obj := range objs {
+		var err error
+		switch o := obj.(type) {
+		case *domain.DomainEvent:
+			err = db.Queries().CreateDomainEvent(ctx, sqlc.CreateDomainEventParams{
+				EventID:       o.EventID,
+				EventType:     string(o.EventType),
+				SchemaVersion: o.SchemaVersion,
+				AggregateType: o.AggregateType,
+				AggregateID:   o.AggregateID,
+				Payload:       o.Payload,
+				Status:        string(o.Status),
+				CreatedBy:     o.CreatedBy,
+			})
+		case *domain.ApprovalTicket:
+			err = db.Queries().CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+				TicketID:          o.TicketID,
+				EventID:           o.EventID,
+				ServiceID:         o.ServiceID,
+				RequestType:       o.RequestType,
+				RequestReason:     o.RequestReason,
+				Status:            string(o.Status),
+				Priority:          string(o.Priority),
+				PriorityRank:      int16(o.Priority.Rank()),
+				Environment:       string(o.Environment),
+				RequiredApprovals: int32(o.RequiredApprovals),
+				Stages:            o.Stages,
+				SLADueAt:          o.SLADueAt,
+				CreatedBy:         o.CreatedBy,
+			})
+		case *domain.VM:
+			_, err = db.Ent().VM.Create().
+				SetID(o.ID).
+				SetName(o.Name).
+				SetNamespace(o.Namespace).
+				SetCluster(o.Cluster).
+				SetServiceID(o.ServiceID).
+				SetInstance(o.Instance).
+				SetCPU(o.CPU).
+				SetMemoryMB(o.MemoryMB).
+				SetDiskGB(o.DiskGB).
+				SetStatus(string(o.Status)).
+				Save(ctx)
+		case *domain.InstanceSize:
+			_, err = db.Ent().InstanceSize.Create().
+				SetID(o.ID).
+				SetName(o.Name).
+				SetCPUCores(o.CPUCores).
+				SetMemory(o.Memory).
+				SetRequiresGpu(o.RequiresGPU).
+				SetRequiresSriov(o.RequiresSRIOV).
+				SetRequiresHugepages(o.RequiresHugepages).
+				SetDedicatedCPU(o.DedicatedCPU).
+				SetGpus(o.GPUs).
+				SetSpecOverrides(o.SpecOverrides).
+				SetEnabled(o.Enabled).
+				Save(ctx)
+		case *domain.ResourceRoleBinding:
+			_, err = db.Ent().ResourceRoleBinding.Create().
+				SetID(o.ID).
+				SetUserID(o.UserID).
+				SetRole(o.Role).
+				SetResourceType(o.ResourceType).
+				SetResourceID(o.ResourceID).
+				SetGrantedBy(o.GrantedBy).
+				SetNillableExpiresAt(o.ExpiresAt).
+				Save(ctx)
+		default:
+			t.Fatalf("factory: cannot seed %T", obj)
+		}
+		if err != nil {
+			t.Fatalf("factory: seed %T: %v", obj, err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package pgtest gives each test its own migrated PostgreSQL database.
+//
+// One server per test binary: a testcontainers PostgreSQL 18 container,
+// or the server in SHEPHERD_TEST_DATABASE_URL (CI service container). The
+// Atlas and River migrations run once into a template database; every
+// New clones it (CREATE DATABASE ... TEMPLATE), which takes milliseconds,
+// so tests are isolated and may run in parallel. Tests that only run
+// queries can use Tx instead: a rolled-back transaction on one shared
+// clone, cheaper still (tx.go).
+//
+// Docker is required unless SHEPHERD_TEST_DATABASE_URL is set. Without
+// either, tests calling New are skipped locally and fail in CI.
+//
+// Import Path: kv-shepherd.io/shepherd/internal/testutil/pgtest
+package pgtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivermigrate"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"kv-shepherd.io/shepherd/ent"
+	"kv-shepherd.io/shepherd/internal/infrastructure"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// EnvDatabaseURL points at an existing server to use instead of a
+// container. The user needs CREATEDB.
+const EnvDatabaseURL = "SHEPHERD_TEST_DATABASE_URL"
+
+// templateDB is per test binary: go test runs packages in parallel, and
+// on a shared server (EnvDatabaseURL) they must not rebuild each other's
+// template.
+var templateDB = fmt.Sprintf("shepherd_template_%d", os.Getpid())
+
+// DB is one test's database, wired like production (ADR-0012: Ent, sqlc
+// and River share the pool; NewRiverClient works as in the server).
+type DB struct {
+	*infrastructure.DatabaseClients
+	URL string
+}
+
+var (
+	setupOnce sync.Once
+	serverURL string // Admin connection, database "postgres"
+	setupErr  error
+	dbSeq     atomic.Int64
+)
+
+// New creates a fresh database from the migrated template and drops it
+// when the test ends.
+func New(t testing.TB) *DB {
+	t.Helper()
+
+	requireServer(t)
+
+	ctx := context.Background()
+	name := fmt.Sprintf("test_%d_%d", os.Getpid(), dbSeq.Add(1))
+	if err := adminExec(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, templateDB)); err != nil {
+		t.Fatalf("pgtest: create database: %v", err)
+	}
+
+	dbURL := withDatabase(serverURL, name)
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("pgtest: connect: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Close()
+		// WITH (FORCE): a leaked connection must not fail the next test
+		if err := adminExec(context.Background(), fmt.Sprintf("DROP DATABASE %s WITH (FORCE)", name)); err != nil {
+			t.Logf("pgtest: drop database %s: %v", name, err)
+		}
+	})
+
+	return &DB{
+		DatabaseClients: &infrastructure.DatabaseClients{
+			Pool:        pool,
+			EntClient:   ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, stdlib.OpenDBFromPool(pool)))),
+			SqlcQueries: sqlc.New(pool),
+		},
+		URL: dbURL,
+	}
+}
+
+// Queries returns the sqlc client (factory.Store).
+func (db *DB) Queries() *sqlc.Queries { return db.SqlcQueries }
+
+// Ent returns the Ent client (factory.Store).
+func (db *DB) Ent() *ent.Client { return db.EntClient }
+
+// requireServer sets up the server and template once per test binary,
+// skipping the test locally when no server is available.
+func requireServer(t testing.TB) {
+	t.Helper()
+	setupOnce.Do(func() { serverURL, setupErr = setup() })
+	if setupErr != nil {
+		if errors.Is(setupErr, errNoServer) && os.Getenv("CI") == "" {
+			t.Skipf("pgtest: %v", setupErr)
+		}
+		t.Fatalf("pgtest: %v", setupErr)
+	}
+}
+
+// setup starts (or finds) the server and builds the template database.
+func setup() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	admin := os.Getenv(EnvDatabaseURL)
+	if admin == "" {
+		// Reaped by testcontainers when the test binary exits
+		container, err := postgres.Run(ctx, "postgres:18-alpine",
+			postgres.WithDatabase("postgres"),
+			postgres.WithUsername("shepherd"),
+			postgres.WithPassword("shepherd"),
+			testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").WithOccurrence(2)),
+		)
+		if err != nil {
+			return "", fmt.Errorf("start postgres container (set %s to use a server): %w: %v", EnvDatabaseURL, errNoServer, err)
+		}
+		if admin, err = container.ConnectionString(ctx, "sslmode=disable"); err != nil {
+			return "", fmt.Errorf("container connection string: %w", err)
+		}
+	}
+	serverURL = admin
+
+	// A crashed earlier run with the same PID may have left it behind
+	if err := adminExec(ctx, "DROP DATABASE IF EXISTS "+templateDB+" WITH (FORCE)"); err != nil {
+		return "", fmt.Errorf("drop stale template: %w", err)
+	}
+	if err := adminExec(ctx, "CREATE DATABASE "+templateDB); err != nil {
+		return "", fmt.Errorf("create template: %w", err)
+	}
+	if err := migrate(ctx, withDatabase(admin, templateDB)); err != nil {
+		return "", err
+	}
+	return admin, nil
+}
+
+// migrate applies the Atlas migration files in version order, then the
+// River migrations, as the application does at startup.
+func migrate(ctx context.Context, dbURL string) error {
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		return fmt.Errorf("connect template: %w", err)
+	}
+	defer pool.Close()
+
+	dir, err := migrationsDir()
+	if err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Strings(files) // Atlas file names start with the version
+	for _, f := range files {
+		sql, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", filepath.Base(f), err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("apply %s: %w", filepath.Base(f), err)
+		}
+	}
+
+	migrator, err := rivermigrate.New(riverpgxv5.New(pool), nil)
+	if err != nil {
+		return fmt.Errorf("create river migrator: %w", err)
+	}
+	if _, err := migrator.Migrate(ctx, rivermigrate.DirectionUp, nil); err != nil {
+		return fmt.Errorf("river migrate: %w", err)
+	}
+	return nil
+}
+
+// migrationsDir finds migrations/atlas from the test's working directory
+// (the package directory) by walking up to go.mod.
+func migrationsDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getwd: %w", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "migrations", "atlas"), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found above the test directory")
+		}
+		dir = parent
+	}
+}
+
+func adminExec(ctx context.Context, sql string) error {
+	conn, err := pgx.Connect(ctx, serverURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, sql)
+	return err
+}
+
+var errNoServer = errors.New("no database server")
+
+func withDatabase(rawURL, name string) string {
+	u, _ := url.Parse(rawURL)
+	u.Path = "/" + name
+	return u.String()
+}
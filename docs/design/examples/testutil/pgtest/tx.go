@@ -0,0 +1,133 @@
+package pgtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"kv-shepherd.io/shepherd/ent"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// TxDB is one test's transaction on a database shared by the whole test
+// binary. Everything the test writes is rolled back when it ends, so the
+// database never needs truncating and no per-test database is created:
+// cheaper than New for the many tests that only run queries.
+//
+// sqlc and Ent share the transaction: both run on the same dedicated
+// connection, which holds the open transaction. Code under test must not
+// begin its own transaction through Ent or a pool (use cases do, ADR-0012;
+// test those with New). Nested transactions go through Savepoint.
+//
+// Parallel tests each get their own connection. Uncommitted rows are
+// invisible to other tests, but inserting the same unique key blocks until
+// the other test ends; factory IDs are unique per binary, so this only
+// happens with hand-written keys.
+type TxDB struct {
+	Tx          pgx.Tx
+	EntClient   *ent.Client
+	SqlcQueries *sqlc.Queries
+}
+
+var (
+	sharedOnce sync.Once
+	sharedDB   *sql.DB // database/sql view of the shared pool, for dedicated conns
+	sharedErr  error
+)
+
+// Tx begins a transaction on the shared database and rolls it back when
+// the test ends.
+func Tx(t testing.TB) *TxDB {
+	t.Helper()
+
+	requireServer(t)
+	sharedOnce.Do(func() { sharedDB, sharedErr = openShared() })
+	if sharedErr != nil {
+		t.Fatalf("pgtest: %v", sharedErr)
+	}
+
+	ctx := context.Background()
+	conn, err := sharedDB.Conn(ctx)
+	if err != nil {
+		t.Fatalf("pgtest: get connection: %v", err)
+	}
+
+	// The pgx connection under conn. Only this test uses conn until
+	// cleanup, so sqlc (via pgx) and Ent (via conn) never use it at once.
+	var pgxConn *pgx.Conn
+	if err := conn.Raw(func(driverConn any) error {
+		pgxConn = driverConn.(*stdlib.Conn).Conn()
+		return nil
+	}); err != nil {
+		conn.Close()
+		t.Fatalf("pgtest: unwrap connection: %v", err)
+	}
+
+	tx, err := pgxConn.Begin(ctx)
+	if err != nil {
+		conn.Close()
+		t.Fatalf("pgtest: begin: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil {
+			t.Logf("pgtest: rollback: %v", err)
+		}
+		conn.Close()
+	})
+
+	return &TxDB{
+		Tx:          tx,
+		EntClient:   ent.NewClient(ent.Driver(entsql.NewDriver(dialect.Postgres, entsql.Conn{ExecQuerier: conn}))),
+		SqlcQueries: sqlc.New(tx),
+	}
+}
+
+// Savepoint opens a nested transaction (SAVEPOINT) for t, typically a
+// subtest, rolled back to when t ends. Fixtures seeded into db before are
+// visible; what t writes is undone, so subtests can share an expensive
+// setup.
+func (db *TxDB) Savepoint(t testing.TB) *TxDB {
+	t.Helper()
+
+	sp, err := db.Tx.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("pgtest: savepoint: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := sp.Rollback(context.Background()); err != nil {
+			t.Logf("pgtest: rollback to savepoint: %v", err)
+		}
+	})
+	return &TxDB{Tx: sp, EntClient: db.EntClient, SqlcQueries: sqlc.New(sp)}
+}
+
+// Queries returns the sqlc client (factory.Store).
+func (db *TxDB) Queries() *sqlc.Queries { return db.SqlcQueries }
+
+// Ent returns the Ent client (factory.Store).
+func (db *TxDB) Ent() *ent.Client { return db.EntClient }
+
+// openShared clones the template once per test binary. Nothing is ever
+// committed to it, so it stays as migrated; it goes away with the
+// container (or the CI service container).
+func openShared() (*sql.DB, error) {
+	ctx := context.Background()
+	name := fmt.Sprintf("test_shared_%d", os.Getpid())
+	if err := adminExec(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, templateDB)); err != nil {
+		return nil, fmt.Errorf("create shared database: %w", err)
+	}
+	pool, err := pgxpool.New(ctx, withDatabase(serverURL, name))
+	if err != nil {
+		return nil, fmt.Errorf("connect shared database: %w", err)
+	}
+	return stdlib.OpenDBFromPool(pool), nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// APIUsageUseCase reports API usage and manages per-token quotas
+// (domain/api_usage.go). Quota changes are audited in the same TX and
+// enforced by every replica's APIMeter after its next reload.
+type APIUsageUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewAPIUsageUseCase creates a new use case instance.
+func NewAPIUsageUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *APIUsageUseCase {
+	return &APIUsageUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// Usage returns the daily usage of the last days, of one principal or,
+// with userID empty, of everyone (admins only), with the quotas of the
+// tokens listed.
+func (uc *APIUsageUseCase) Usage(ctx context.Context, userID string, days int) (*domain.APIUsage, error) {
+	_, today := domain.APIQuotaWindows(uc.clock.Now())
+	from := today.AddDate(0, 0, 1-days)
+
+	rows, err := uc.sqlcQueries.ListAPIUsage(ctx, sqlc.ListAPIUsageParams{UserID: userID, From: from})
+	if err != nil {
+		return nil, fmt.Errorf("list api usage: %w", err)
+	}
+	quotaRows, err := uc.sqlcQueries.ListAPIQuotas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api quotas: %w", err)
+	}
+	quotas := make(map[string]domain.APIQuota, len(quotaRows))
+	for _, q := range quotaRows {
+		quotas[q.KeyID] = toAPIQuota(q)
+	}
+
+	usage := &domain.APIUsage{From: from, Days: make([]domain.APIUsageDay, 0, len(rows))}
+	listed := make(map[string]bool)
+	for _, row := range rows {
+		usage.Days = append(usage.Days, domain.APIUsageDay{
+			Day:      row.Day,
+			UserID:   row.UserID,
+			Token:    row.Token,
+			Requests: row.Requests,
+			Rejected: row.Rejected,
+		})
+		if q, ok := quotas[row.Token]; ok && !listed[row.Token] {
+			listed[row.Token] = true
+			usage.Quotas = append(usage.Quotas, q)
+		}
+	}
+	return usage, nil
+}
+
+// Quotas returns every quota.
+func (uc *APIUsageUseCase) Quotas(ctx context.Context) ([]domain.APIQuota, error) {
+	rows, err := uc.sqlcQueries.ListAPIQuotas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api quotas: %w", err)
+	}
+	quotas := make([]domain.APIQuota, 0, len(rows))
+	for _, row := range rows {
+		quotas = append(quotas, toAPIQuota(row))
+	}
+	return quotas, nil
+}
+
+// SetQuota sets or replaces the quota of a signing key. The key need not
+// exist yet: a quota can be set before the key is issued.
+func (uc *APIUsageUseCase) SetQuota(ctx context.Context, q domain.APIQuota, actorID string) (*domain.APIQuota, error) {
+	q.UpdatedBy = actorID
+	q.UpdatedAt = uc.clock.Now()
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Locked so two admins changing the same quota are audited in order
+	var previous any
+	prev, err := sqlcTx.GetAPIQuotaForUpdate(ctx, q.KeyID)
+	switch {
+	case err == nil:
+		previous = toAPIQuota(prev)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("get api quota: %w", err)
+	}
+
+	if err := sqlcTx.UpsertAPIQuota(ctx, sqlc.UpsertAPIQuotaParams{
+		KeyID:     q.KeyID,
+		PerMinute: q.PerMinute,
+		PerDay:    q.PerDay,
+		Reason:    q.Reason,
+		UpdatedBy: q.UpdatedBy,
+		UpdatedAt: q.UpdatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("upsert api quota: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditAPIQuotaSet,
+		ActorID:      actorID,
+		ResourceType: "signing_key",
+		ResourceID:   q.KeyID,
+		Details: map[string]interface{}{
+			"per_minute": q.PerMinute,
+			"per_day":    q.PerDay,
+			"reason":     q.Reason,
+			"previous":   previous,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return &q, nil
+}
+
+// RemoveQuota removes the quota of a signing key; its calls are then only
+// subject to the global rate limiter. repository.ErrNotFound if it had none.
+func (uc *APIUsageUseCase) RemoveQuota(ctx context.Context, keyID, actorID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	prev, err := sqlcTx.GetAPIQuotaForUpdate(ctx, keyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get api quota: %w", err)
+	}
+	if err := sqlcTx.DeleteAPIQuota(ctx, keyID); err != nil {
+		return fmt.Errorf("delete api quota: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditAPIQuotaRemoved,
+		ActorID:      actorID,
+		ResourceType: "signing_key",
+		ResourceID:   keyID,
+		Details:      map[string]interface{}{"previous": toAPIQuota(prev)},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+func toAPIQuota(row sqlc.ApiQuota) domain.APIQuota {
+	return domain.APIQuota{
+		KeyID:     row.KeyID,
+		PerMinute: row.PerMinute,
+		PerDay:    row.PerDay,
+		Reason:    row.Reason,
+		UpdatedBy: row.UpdatedBy,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
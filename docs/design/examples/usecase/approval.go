@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// recordApproval records acting's approval of a locked ticket in its
+// current stage and returns the progress. A stage with its required
+// distinct approvals advances the ticket to the next stage; Approved is
+// set only when the last stage is complete. reset discards the current
+// stage's approvals, e.g. when the spec was modified; earlier stages
+// stand (a later stage may adjust what an earlier one approved).
+//
+// Callers hold the ticket row lock (GetApprovalTicketForUpdate) so that
+// concurrent approvals count each other.
+func recordApproval(ctx context.Context, sqlcTx *sqlc.Queries, ticket sqlc.ApprovalTicket, acting domain.ActingApprover, reset bool) (*domain.ApprovalProgress, error) {
+	stages := domain.TicketStages(ticket.Stages, int(ticket.RequiredApprovals))
+	current := int(ticket.CurrentStage)
+	stage := stages[current]
+
+	if reset {
+		err := sqlcTx.DeleteTicketStageApprovals(ctx, sqlc.DeleteTicketStageApprovalsParams{
+			TicketID: ticket.TicketID,
+			Stage:    int32(current),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reset approvals: %w", err)
+		}
+	}
+	// Primary key (ticket_id, approver_id) and unique (ticket_id, principal):
+	// approving twice counts once, and so do a delegator and their delegate
+	err := sqlcTx.CreateTicketApproval(ctx, sqlc.CreateTicketApprovalParams{
+		TicketID:   ticket.TicketID,
+		ApproverID: acting.UserID,
+		OnBehalfOf: acting.OnBehalfOf,
+		Principal:  acting.Principal(),
+		Stage:      int32(current),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record approval: %w", err)
+	}
+	approvals, err := sqlcTx.CountTicketStageApprovals(ctx, sqlc.CountTicketStageApprovalsParams{
+		TicketID: ticket.TicketID,
+		Stage:    int32(current),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("count approvals: %w", err)
+	}
+
+	progress := &domain.ApprovalProgress{
+		Approvals:  int(approvals),
+		Required:   stage.RequiredApprovals,
+		Stage:      current,
+		Stages:     len(stages),
+		OnBehalfOf: acting.OnBehalfOf,
+	}
+	if progress.Approvals < progress.Required {
+		return progress, nil
+	}
+	if current == len(stages)-1 {
+		progress.Approved = true
+		return progress, nil
+	}
+
+	// Stage complete: the next stage's approvers take over
+	next := current + 1
+	err = sqlcTx.UpdateApprovalTicketStage(ctx, sqlc.UpdateApprovalTicketStageParams{
+		TicketID:     ticket.TicketID,
+		CurrentStage: int32(next),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("advance stage: %w", err)
+	}
+	return &domain.ApprovalProgress{
+		Required:   stages[next].RequiredApprovals,
+		Stage:      next,
+		Stages:     len(stages),
+		OnBehalfOf: acting.OnBehalfOf,
+	}, nil
+}
+
+// rejectTicket rejects a pending ticket in the caller's transaction: the
+// ticket becomes REJECTED with the rejecting user and reason, its event
+// CANCELLED, and a batch parent counts the child as failed.
+//
+// Rejection has no type-specific side effects (quota is reserved and the
+// VM touched only on final approval), so this serves every request type.
+// A rejected VNC_ACCESS request also records VNC_ACCESS_DENIED.
+func rejectTicket(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, guard ApprovalGuard, ticketID, rejectedBy, reason string) error {
+	if err := domain.ValidateRejectReason(reason); err != nil {
+		return err
+	}
+
+	ticket, err := lockTicket(ctx, sqlcTx, ticketID)
+	if err != nil {
+		return err
+	}
+	if err := requirePending(ticket.Status); err != nil {
+		return err
+	}
+
+	// Same eligibility as approving: only assigned approvers (or their
+	// delegates) decide
+	acting, err := guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, rejectedBy)
+	if err != nil {
+		return err
+	}
+
+	err = sqlcTx.RejectApprovalTicket(ctx, sqlc.RejectApprovalTicketParams{
+		TicketID:           ticketID,
+		RejectedBy:         rejectedBy,
+		RejectedOnBehalfOf: acting.OnBehalfOf,
+		RejectReason:       reason,
+	})
+	if err != nil {
+		return fmt.Errorf("reject ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusCancelled),
+	})
+	if err != nil {
+		return fmt.Errorf("cancel event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditApprovalRejected,
+		ActorID:      rejectedBy,
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details: map[string]interface{}{
+			"request_type": ticket.RequestType,
+			"event_id":     ticket.EventID,
+			"reason":       reason,
+			"on_behalf_of": acting.OnBehalfOf,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if ticket.RequestType == domain.VNCAccessRequestType {
+		if err := denyVNCAccess(ctx, sqlcTx, ids, ticket, rejectedBy, reason); err != nil {
+			return err
+		}
+	}
+
+	return closeBatchChild(ctx, sqlcTx, ticket.ParentTicketID)
+}
+
+// cancelTicket withdraws a pending ticket on behalf of its requester in
+// the caller's transaction: the ticket and its event become CANCELLED and
+// a REQUEST_CANCELLED event records who cancelled and why.
+//
+// Holding the ticket lock while checking PENDING_APPROVAL is what keeps a
+// cancelled ticket from ever being enqueued: approveTx takes the same lock
+// and re-checks the status (requirePending) before inserting a River job.
+func cancelTicket(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, ticketID, userID, reason string) error {
+	ticket, err := lockTicket(ctx, sqlcTx, ticketID)
+	if err != nil {
+		return err
+	}
+	if err := domain.CheckCancel(domain.TicketStatus(ticket.Status), ticket.CreatedBy, userID, reason); err != nil {
+		return fmt.Errorf("cancel ticket %s: %w", ticketID, err)
+	}
+
+	err = sqlcTx.CancelApprovalTicket(ctx, sqlc.CancelApprovalTicketParams{
+		TicketID:     ticketID,
+		CancelReason: reason,
+	})
+	if err != nil {
+		return fmt.Errorf("cancel ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusCancelled),
+	})
+	if err != nil {
+		return fmt.Errorf("cancel event: %w", err)
+	}
+
+	// Recorded only: no handler, no River job
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       ids.NewID(),
+		EventType:     string(domain.EventRequestCancelled),
+		SchemaVersion: domain.PayloadVersion(domain.EventRequestCancelled),
+		AggregateType: "ApprovalTicket",
+		AggregateID:   ticketID,
+		Status:        string(domain.EventStatusCompleted),
+		CreatedBy:     userID,
+	}, domain.RequestCancelledPayload{
+		TicketID:    ticketID,
+		EventID:     ticket.EventID,
+		RequestType: ticket.RequestType,
+		CancelledBy: userID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("create cancellation event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditRequestCancelled,
+		ActorID:      userID,
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details: map[string]interface{}{
+			"request_type": ticket.RequestType,
+			"event_id":     ticket.EventID,
+			"reason":       reason,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	return closeBatchChild(ctx, sqlcTx, ticket.ParentTicketID)
+}
+
+// lockTicket locks a ticket for a decision. Batch children: parent lock
+// first, child after (same order as ApproveAll/ApproveItem), so a
+// rejection or cancellation cannot deadlock an approval.
+func lockTicket(ctx context.Context, sqlcTx *sqlc.Queries, ticketID string) (sqlc.ApprovalTicket, error) {
+	peek, err := sqlcTx.GetApprovalTicket(ctx, ticketID)
+	if err != nil {
+		return sqlc.ApprovalTicket{}, fmt.Errorf("get ticket: %w", err)
+	}
+	if peek.ParentTicketID != "" {
+		if _, err := sqlcTx.GetBatchApprovalTicketForUpdate(ctx, peek.ParentTicketID); err != nil {
+			return sqlc.ApprovalTicket{}, fmt.Errorf("get batch ticket: %w", err)
+		}
+	}
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return sqlc.ApprovalTicket{}, fmt.Errorf("get ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// requirePending rejects decisions on a ticket that is no longer
+// PENDING_APPROVAL (approved, rejected or cancelled meanwhile). Callers
+// hold the ticket row lock.
+func requirePending(status string) error {
+	if domain.TicketStatus(status) != domain.TicketPendingApproval {
+		return fmt.Errorf("ticket is %s: %w", status, domain.ErrTicketNotPending)
+	}
+	return nil
+}
+
+// closeBatchChild counts a rejected or cancelled batch child as failed on
+// its parent. No-op for tickets outside a batch.
+func closeBatchChild(ctx context.Context, sqlcTx *sqlc.Queries, parentTicketID string) error {
+	if parentTicketID == "" {
+		return nil
+	}
+	// failed_count + 1, pending_count - 1
+	if err := sqlcTx.RecordBatchChildRejected(ctx, parentTicketID); err != nil {
+		return fmt.Errorf("update batch progress: %w", err)
+	}
+	return refreshBatchStatus(ctx, sqlcTx, parentTicketID)
+}
+
+// storeModifiedSpec saves an approver's modifications on a locked ticket
+// and returns the spec in effect: modifiedSpec when given, otherwise what
+// earlier approvers stored (nil if unmodified). Callers check it against
+// the event with effectiveEventPayload in the same transaction.
+func storeModifiedSpec(ctx context.Context, sqlcTx *sqlc.Queries, ticket sqlc.ApprovalTicket, modifiedSpec *domain.ModifiedSpec) ([]byte, error) {
+	if modifiedSpec == nil {
+		return ticket.ModifiedSpec, nil
+	}
+	specJSON, err := modifiedSpec.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	err = sqlcTx.UpdateApprovalTicketModifiedSpec(ctx, sqlc.UpdateApprovalTicketModifiedSpecParams{
+		TicketID:     ticket.TicketID,
+		ModifiedSpec: specJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update modified spec: %w", err)
+	}
+	return specJSON, nil
+}
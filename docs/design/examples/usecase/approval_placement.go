@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	domainerr "kv-shepherd.io/shepherd/internal/domain/errors"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// applyApprovalInstanceSize expands the InstanceSize an approver picked
+// into cpu and memory_mb before modifiedSpec is stored, so the approval
+// snapshots the size's resources (domain/approval_placement.go). The
+// version the approver pinned is used, otherwise the current one, which
+// is pinned from then on. Returns the size at that version, for
+// requireSizePlacement; nil without a size.
+func applyApprovalInstanceSize(ctx context.Context, sqlcTx *sqlc.Queries, modifiedSpec *domain.ModifiedSpec) (*domain.InstanceSize, error) {
+	if modifiedSpec == nil || modifiedSpec.InstanceSizeID == nil {
+		return nil, nil
+	}
+	sizeID := *modifiedSpec.InstanceSizeID
+	row, err := sqlcTx.GetInstanceSize(ctx, sizeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("instance size %s: %w", sizeID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get instance size: %w", err)
+	}
+	size := &domain.InstanceSize{
+		ID:                row.ID,
+		Version:           int(row.CurrentVersion),
+		Name:              row.Name,
+		CPUCores:          row.CPUCores,
+		Memory:            row.Memory,
+		RequiresGPU:       row.RequiresGPU,
+		RequiresSRIOV:     row.RequiresSRIOV,
+		RequiresHugepages: row.RequiresHugepages,
+		HugepagesSize:     row.HugepagesSize,
+		DedicatedCPU:      row.DedicatedCPU,
+		GPUs:              row.GPUs,          // JSONB
+		SpecOverrides:     row.SpecOverrides, // JSONB
+		Enabled:           row.Enabled,
+	}
+
+	if modifiedSpec.InstanceSizeVersion != nil && *modifiedSpec.InstanceSizeVersion != size.Version {
+		version, err := sqlcTx.GetInstanceSizeVersion(ctx, sqlc.GetInstanceSizeVersionParams{
+			SizeID:  sizeID,
+			Version: int32(*modifiedSpec.InstanceSizeVersion),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("instance size %s version %d: %w", sizeID, *modifiedSpec.InstanceSizeVersion, repository.ErrNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get instance size version: %w", err)
+		}
+		var v domain.InstanceSizeVersion
+		if err := json.Unmarshal(version.Spec, &v); err != nil {
+			return nil, fmt.Errorf("decode instance size version: %w", err)
+		}
+		size = v.Size(row.Enabled) // Disabling a size retires every version
+	}
+
+	if err := modifiedSpec.ApplyInstanceSize(size); err != nil {
+		return nil, err
+	}
+	return size, nil
+}
+
+// requireSizePlacement checks, at each approval that picks a size, that
+// the size can be hosted in the ticket's environment
+// (domain/size_matching.go): on the cluster picked with it, or else on at
+// least one placement candidate, which the worker's weight-based
+// selection is then limited to.
+func requireSizePlacement(ctx context.Context, sqlcTx *sqlc.Queries, size *domain.InstanceSize, modifiedSpec *domain.ModifiedSpec, environment string) error {
+	if size == nil {
+		return nil
+	}
+	req := size.Requirements()
+	if req.IsZero() {
+		return nil
+	}
+	clusters, err := environmentClusters(ctx, sqlcTx, environment)
+	if err != nil {
+		return err
+	}
+
+	if modifiedSpec.ClusterID != nil {
+		for _, c := range clusters {
+			if c.ID == *modifiedSpec.ClusterID {
+				return domain.CheckClusterHostsSize(req, c)
+			}
+		}
+		// Other environment or unknown: rejected by CheckApprovalCluster
+		return nil
+	}
+	if len(domain.FilterClustersForSize(req, domain.FilterPlacementCandidates(clusters, environment))) == 0 {
+		// 409 here: the request was placeable at submission
+		return fmt.Errorf("instance size %s: %w", size.Name, domain.ErrNoClusterForSize.WithKind(domainerr.KindConflict))
+	}
+	return nil
+}
+
+// environmentClusters returns the clusters of environment with their
+// detected capabilities.
+func environmentClusters(ctx context.Context, sqlcTx *sqlc.Queries, environment string) ([]*domain.Cluster, error) {
+	rows, err := sqlcTx.ListClustersByEnvironment(ctx, environment)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	clusters := make([]*domain.Cluster, len(rows))
+	for i, row := range rows {
+		clusters[i] = &domain.Cluster{
+			ID:               row.ID,
+			Name:             row.Name,
+			Environment:      row.Environment,
+			Status:           domain.ClusterHealthStatus(row.Status),
+			Lifecycle:        domain.ClusterLifecycle(row.Lifecycle),
+			SchedulingWeight: int(row.SchedulingWeight),
+		}
+		if row.Capabilities != nil {
+			var caps domain.ClusterCapabilities
+			if err := json.Unmarshal(row.Capabilities, &caps); err != nil {
+				return nil, fmt.Errorf("decode capabilities of cluster %s: %w", row.Name, err)
+			}
+			clusters[i].Capabilities = &caps
+		}
+	}
+	return clusters, nil
+}
+
+// approvalClusters returns the clusters a VM of the effective spec may
+// land on, for requireCapacity: the approver's cluster after checking it
+// still accepts VMs of the ticket's environment, otherwise every
+// candidate of weight-based selection.
+func approvalClusters(ctx context.Context, sqlcTx *sqlc.Queries, spec *domain.VMCreationPayload, environment string) ([]domain.ClusterHeadroom, error) {
+	if spec.ClusterID == "" {
+		return placementHeadroom(ctx, sqlcTx, environment)
+	}
+	row, err := sqlcTx.GetCluster(ctx, spec.ClusterID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("cluster %s: %w", spec.ClusterID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cluster: %w", err)
+	}
+	cluster := &domain.Cluster{
+		ID:          row.ID,
+		Name:        row.Name,
+		Environment: row.Environment,
+		Status:      domain.ClusterHealthStatus(row.Status),
+		Lifecycle:   domain.ClusterLifecycle(row.Lifecycle),
+	}
+	if err := domain.CheckApprovalCluster(cluster, environment); err != nil {
+		return nil, err
+	}
+	return clusterHeadroom(ctx, sqlcTx, cluster.Name)
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// requireCapacity re-checks, before the final approval reserves r, that
+// the Service and its System still have the quota and that one of
+// clusters has the headroom (domain/approval_precondition.go). Returns
+// *domain.PreconditionFailed with every shortfall; the caller's TX then
+// rolls back, approval included.
+//
+// clusters are the clusters the VM may land on, any one of which must
+// fit, GPUs included; none (no capacity observed yet) skips the headroom
+// check. GPUs are not a quota, so only the headroom counts them.
+func requireCapacity(ctx context.Context, sqlcTx *sqlc.Queries, ticketID string, r *domain.QuotaReservation, gpus []domain.GPUDevice, clusters []domain.ClusterHeadroom) error {
+	req := r.Amount()
+	if req.IsZero() && len(gpus) == 0 {
+		return nil
+	}
+
+	// Serializes final approvals within the System (System quota spans its
+	// Services): two approvals that fit alone cannot commit together past
+	// the limit. HELD reservations of this TX (batch children) are counted.
+	if err := sqlcTx.LockSystemQuota(ctx, r.ServiceID); err != nil {
+		return fmt.Errorf("lock system quota: %w", err)
+	}
+	rows, err := sqlcTx.GetQuotaStandings(ctx, r.ServiceID)
+	if err != nil {
+		return fmt.Errorf("get quota standings: %w", err)
+	}
+	standings := make([]domain.QuotaStanding, len(rows))
+	for i, row := range rows {
+		standings[i] = domain.QuotaStanding{
+			Scope:   domain.PreconditionScope(row.Scope),
+			ScopeID: row.ScopeID,
+			Limit:   domain.QuotaAmount{CPU: row.LimitCPU, MemoryMB: row.LimitMemoryMB, DiskGB: row.LimitDiskGB, VMCount: row.LimitVMCount},
+			Used:    domain.QuotaAmount{CPU: row.UsedCPU, MemoryMB: row.UsedMemoryMB, DiskGB: row.UsedDiskGB, VMCount: row.UsedVMCount},
+		}
+	}
+	shortfalls := domain.CheckQuota(standings, req)
+
+	var capacity []domain.Shortfall
+	for _, h := range clusters {
+		s := domain.CheckHeadroom(h, req, gpus)
+		if len(s) == 0 {
+			capacity = nil
+			break
+		}
+		capacity = append(capacity, s...)
+	}
+	shortfalls = append(shortfalls, capacity...)
+
+	if len(shortfalls) > 0 {
+		return &domain.PreconditionFailed{TicketID: ticketID, Shortfalls: shortfalls}
+	}
+	return nil
+}
+
+// placementHeadroom returns the headroom of the clusters a new VM in
+// environment may be placed on (domain.FilterPlacementCandidates).
+func placementHeadroom(ctx context.Context, sqlcTx *sqlc.Queries, environment string) ([]domain.ClusterHeadroom, error) {
+	rows, err := sqlcTx.ListPlacementHeadroom(ctx, environment)
+	if err != nil {
+		return nil, fmt.Errorf("list cluster headroom: %w", err)
+	}
+	out := make([]domain.ClusterHeadroom, len(rows))
+	for i, row := range rows {
+		out[i] = domain.ClusterHeadroom{Cluster: row.Cluster, CPU: row.CPU, MemoryMB: row.MemoryMB, ObservedAt: row.ObservedAt, GPUs: row.GPUs} // JSONB
+	}
+	return out, nil
+}
+
+// clusterHeadroom returns the headroom of one cluster, e.g. the one a
+// resized VM runs on; none if not observed yet.
+func clusterHeadroom(ctx context.Context, sqlcTx *sqlc.Queries, cluster string) ([]domain.ClusterHeadroom, error) {
+	row, err := sqlcTx.GetClusterHeadroom(ctx, cluster)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cluster headroom: %w", err)
+	}
+	return []domain.ClusterHeadroom{{Cluster: row.Cluster, CPU: row.CPU, MemoryMB: row.MemoryMB, ObservedAt: row.ObservedAt, GPUs: row.GPUs}}, nil
+}
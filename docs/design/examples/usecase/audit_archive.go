@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// maxVerifyBatches bounds one verification request; larger ranges are
+// verified in several requests.
+const maxVerifyBatches = 1000
+
+// AuditArchiveOptions are the audit_archive settings the use case needs.
+type AuditArchiveOptions struct {
+	Prefix    string
+	Retention time.Duration // At least domain.MinAuditArchiveRetention
+	BatchSize int
+}
+
+// AuditArchiveUseCase writes audit logs to append-only object storage,
+// anchors the batch chain and verifies it (domain/audit_archive.go). Run
+// by the audit_archive and audit_archive_anchor periodic jobs; verified on
+// demand by platform admins.
+//
+// Every object is written before the row recording it: a recorded batch
+// always has its object. A run that wrote an object but
+// failed to commit leaves it behind, locked; the next run finds it under
+// the same key and adopts it as the batch instead of writing another.
+type AuditArchiveUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	store       domain.AuditArchiveStore
+	timestamper domain.AuditTimestamper // nil: anchors are not timestamped
+	opts        AuditArchiveOptions
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewAuditArchiveUseCase creates a new use case instance.
+func NewAuditArchiveUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	store domain.AuditArchiveStore,
+	timestamper domain.AuditTimestamper,
+	opts AuditArchiveOptions,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) (*AuditArchiveUseCase, error) {
+	if opts.Retention < domain.MinAuditArchiveRetention {
+		return nil, fmt.Errorf("audit_archive.retention %s is below %s", opts.Retention, domain.MinAuditArchiveRetention)
+	}
+	if opts.BatchSize < 1 || opts.BatchSize > domain.MaxAuditArchiveBatchSize {
+		return nil, fmt.Errorf("audit_archive.batch_size must be 1-%d", domain.MaxAuditArchiveBatchSize)
+	}
+	return &AuditArchiveUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		store:       store,
+		timestamper: timestamper,
+		opts:        opts,
+		clock:       clock,
+		ids:         ids,
+	}, nil
+}
+
+// Archive writes batches until the unarchived audit logs are written or
+// the job times out, and returns the batches written. Each batch commits
+// on its own.
+func (uc *AuditArchiveUseCase) Archive(ctx context.Context) ([]*domain.AuditBatch, error) {
+	if err := uc.store.CheckImmutable(ctx); err != nil {
+		return nil, fmt.Errorf("check archive bucket: %w", err)
+	}
+
+	var written []*domain.AuditBatch
+	for ctx.Err() == nil {
+		batch, err := uc.archiveBatch(ctx)
+		if err != nil {
+			return written, err
+		}
+		if batch == nil {
+			break
+		}
+		written = append(written, batch)
+		if batch.Records < uc.opts.BatchSize {
+			break
+		}
+	}
+	return written, nil
+}
+
+// archiveBatch writes the next batch of unarchived audit logs and returns
+// it; nil if there was nothing to archive.
+func (uc *AuditArchiveUseCase) archiveBatch(ctx context.Context) (*domain.AuditBatch, error) {
+	prev := domain.GenesisChainDigest
+	seq := int64(1)
+	afterXID, afterID := uint64(0), "00000000-0000-0000-0000-000000000000"
+	last, err := uc.sqlcQueries.GetLastAuditArchiveBatch(ctx)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// First batch: from the oldest audit log
+	case err != nil:
+		return nil, fmt.Errorf("get last audit batch: %w", err)
+	default:
+		prev, seq = last.Chain, last.Seq+1
+		afterXID, afterID = last.ToXID, last.ToID
+	}
+
+	key := domain.AuditBatchKey(uc.opts.Prefix, seq)
+	body, digest, records, err := uc.writeBatch(ctx, key, afterXID, afterID)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	batch := &domain.AuditBatch{
+		Seq:            seq,
+		ObjectKey:      key,
+		Records:        len(records),
+		FirstCreatedAt: records[0].CreatedAt,
+		LastCreatedAt:  records[len(records)-1].CreatedAt,
+		ToXID:          records[len(records)-1].XID,
+		ToID:           records[len(records)-1].ID,
+		SHA256:         digest,
+		Chain:          domain.ChainDigest(prev, seq, digest),
+		ArchivedAt:     uc.clock.Now(),
+	}
+	// Primary key seq: a concurrent run fails here and retries later
+	if err := uc.sqlcQueries.InsertAuditArchiveBatch(ctx, sqlc.InsertAuditArchiveBatchParams{
+		Seq:            batch.Seq,
+		ObjectKey:      batch.ObjectKey,
+		Records:        int32(batch.Records),
+		FirstCreatedAt: batch.FirstCreatedAt,
+		LastCreatedAt:  batch.LastCreatedAt,
+		ToXID:          batch.ToXID,
+		ToID:           batch.ToID,
+		SHA256:         batch.SHA256,
+		Chain:          batch.Chain,
+		ArchivedAt:     batch.ArchivedAt,
+		Bytes:          int64(len(body)),
+	}); err != nil {
+		return nil, fmt.Errorf("insert audit batch: %w", err)
+	}
+	return batch, nil
+}
+
+// writeBatch writes the audit logs after the cursor under key, or adopts
+// the object a failed earlier attempt left there. Returns no records if
+// there is nothing to archive.
+func (uc *AuditArchiveUseCase) writeBatch(ctx context.Context, key string, afterXID uint64, afterID string) ([]byte, string, []*domain.AuditArchiveRecord, error) {
+	rows, err := uc.sqlcQueries.ListAuditLogsAfter(ctx, sqlc.ListAuditLogsAfterParams{
+		AfterXID: afterXID,
+		AfterID:  afterID,
+		Limit:    int32(uc.opts.BatchSize),
+	})
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("list unarchived audit logs: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, "", nil, nil
+	}
+	records := make([]*domain.AuditArchiveRecord, len(rows))
+	for i, row := range rows {
+		records[i] = &domain.AuditArchiveRecord{
+			XID:          row.XID,
+			ID:           row.ID,
+			Action:       row.Action,
+			ActorID:      row.ActorID,
+			ResourceType: row.ResourceType,
+			ResourceID:   row.ResourceID,
+			ResourceName: row.ResourceName,
+			ParentType:   row.ParentType,
+			ParentID:     row.ParentID,
+			Environment:  row.Environment,
+			Details:      row.Details,
+			CreatedAt:    row.CreatedAt,
+		}
+	}
+
+	body, digest, err := domain.EncodeAuditBatch(records)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	err = uc.store.Put(ctx, key, body, uc.clock.Now().Add(uc.opts.Retention))
+	if errors.Is(err, domain.ErrAuditObjectExists) {
+		// Written by an attempt that did not commit. It starts at the same
+		// cursor, so the locked object is the batch
+		body, err = uc.store.Get(ctx, key)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("get orphaned batch %s: %w", key, err)
+		}
+		if records, err = domain.DecodeAuditBatch(body); err != nil {
+			return nil, "", nil, fmt.Errorf("orphaned batch %s: %w", key, err)
+		}
+		sum := sha256.Sum256(body)
+		return body, hex.EncodeToString(sum[:]), records, nil
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("put batch %s: %w", key, err)
+	}
+	return body, digest, records, nil
+}
+
+// Anchor anchors the batches archived since the last anchor and returns
+// the anchor; nil if there is no new batch.
+func (uc *AuditArchiveUseCase) Anchor(ctx context.Context) (*domain.AuditAnchor, error) {
+	prev := domain.GenesisChainDigest
+	seq, from := int64(1), int64(1)
+	last, err := uc.sqlcQueries.GetLastAuditArchiveAnchor(ctx)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// First anchor
+	case err != nil:
+		return nil, fmt.Errorf("get last audit anchor: %w", err)
+	default:
+		prev, seq, from = last.Chain, last.Seq+1, last.ToBatch+1
+	}
+
+	batches, err := uc.batches(ctx, from, 0)
+	if err != nil || len(batches) == 0 {
+		return nil, err
+	}
+	anchor, err := domain.NewAuditAnchor(seq, prev, batches, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if uc.timestamper != nil {
+		digest, _ := hex.DecodeString(anchor.Chain) // Always hex: ChainDigest
+		if anchor.Timestamp, err = uc.timestamper.Timestamp(ctx, digest); err != nil {
+			return nil, fmt.Errorf("timestamp anchor: %w", err)
+		}
+	}
+
+	key := domain.AuditAnchorKey(uc.opts.Prefix, seq)
+	body, err := json.Marshal(anchor)
+	if err != nil {
+		return nil, fmt.Errorf("encode anchor: %w", err)
+	}
+	err = uc.store.Put(ctx, key, body, uc.clock.Now().Add(uc.opts.Retention))
+	if errors.Is(err, domain.ErrAuditObjectExists) {
+		// Written by an attempt that did not commit; it anchors a prefix
+		// of the same batches, so it stands as anchor seq
+		if anchor, err = uc.getAnchor(ctx, key); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("put anchor %s: %w", key, err)
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := sqlcTx.InsertAuditArchiveAnchor(ctx, sqlc.InsertAuditArchiveAnchorParams{
+		Seq:       anchor.Seq,
+		FromBatch: anchor.FromBatch,
+		ToBatch:   anchor.ToBatch,
+		Chain:     anchor.Chain,
+		ObjectKey: key,
+		Timestamp: anchor.Timestamp,
+		CreatedAt: anchor.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("insert audit anchor: %w", err)
+	}
+
+	// Archived with the next batch, so the anchor is in the chain too
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditArchiveAnchored,
+		ActorID:      "system",
+		ResourceType: "audit_archive",
+		ResourceID:   key,
+		Details: map[string]interface{}{
+			"anchor":      anchor.Seq,
+			"from_batch":  anchor.FromBatch,
+			"to_batch":    anchor.ToBatch,
+			"chain":       anchor.Chain,
+			"timestamped": len(anchor.Timestamp) > 0,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return anchor, nil
+}
+
+// ListAnchors returns the anchors, newest first.
+func (uc *AuditArchiveUseCase) ListAnchors(ctx context.Context) ([]*domain.AuditAnchor, error) {
+	rows, err := uc.sqlcQueries.ListAuditArchiveAnchors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list audit anchors: %w", err)
+	}
+	anchors := make([]*domain.AuditAnchor, len(rows))
+	for i, row := range rows {
+		anchors[i] = &domain.AuditAnchor{
+			Seq:       row.Seq,
+			FromBatch: row.FromBatch,
+			ToBatch:   row.ToBatch,
+			Chain:     row.Chain,
+			Timestamp: row.Timestamp,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return anchors, nil
+}
+
+// Verify re-reads batches from through to (0: the latest, at most
+// maxVerifyBatches) and the anchors ending in that range, and checks the
+// object digests, the chain and the anchored chain digests. A mismatch is
+// reported in the result; err is for storage and database failures.
+func (uc *AuditArchiveUseCase) Verify(ctx context.Context, from, to int64) (*domain.AuditArchiveVerification, error) {
+	if from < 1 {
+		from = 1
+	}
+	batches, err := uc.batches(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) > maxVerifyBatches {
+		batches = batches[:maxVerifyBatches]
+	}
+	v := &domain.AuditArchiveVerification{FromBatch: from, Intact: true, CheckedAt: uc.clock.Now()}
+	if len(batches) == 0 {
+		return v, nil
+	}
+	v.ToBatch, v.Batches = batches[len(batches)-1].Seq, len(batches)
+
+	prev := domain.GenesisChainDigest
+	if from > 1 {
+		before, err := uc.sqlcQueries.GetAuditArchiveBatch(ctx, from-1)
+		if err != nil {
+			return nil, fmt.Errorf("get audit batch %d: %w", from-1, err)
+		}
+		prev = before.Chain
+	}
+	if err := domain.VerifyAuditChain(prev, batches); err != nil {
+		v.Intact, v.Problem = false, err.Error()
+		return v, nil
+	}
+
+	bySeq := make(map[int64]*domain.AuditBatch, len(batches))
+	for _, b := range batches {
+		bySeq[b.Seq] = b
+		body, err := uc.store.Get(ctx, b.ObjectKey)
+		if err != nil {
+			return nil, fmt.Errorf("get batch %s: %w", b.ObjectKey, err)
+		}
+		sum := sha256.Sum256(body)
+		if got := hex.EncodeToString(sum[:]); got != b.SHA256 {
+			v.Intact, v.Problem = false, fmt.Sprintf("batch %d object digest %s, recorded %s", b.Seq, got, b.SHA256)
+			return v, nil
+		}
+	}
+
+	rows, err := uc.sqlcQueries.ListAuditArchiveAnchorsEndingIn(ctx, sqlc.ListAuditArchiveAnchorsEndingInParams{
+		FromBatch: from,
+		ToBatch:   v.ToBatch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit anchors: %w", err)
+	}
+	for _, row := range rows {
+		anchor, err := uc.getAnchor(ctx, row.ObjectKey)
+		if err != nil {
+			return nil, err
+		}
+		if anchor.Chain != bySeq[anchor.ToBatch].Chain {
+			v.Intact, v.Problem = false, fmt.Sprintf("anchor %d chain %s, batch %d chain %s", anchor.Seq, anchor.Chain, anchor.ToBatch, bySeq[anchor.ToBatch].Chain)
+			return v, nil
+		}
+		for _, ab := range anchor.Batches {
+			if b, ok := bySeq[ab.Seq]; ok && b.SHA256 != ab.SHA256 {
+				v.Intact, v.Problem = false, fmt.Sprintf("anchor %d lists batch %d as %s, recorded %s", anchor.Seq, ab.Seq, ab.SHA256, b.SHA256)
+				return v, nil
+			}
+		}
+		v.Anchors++
+	}
+	return v, nil
+}
+
+// batches returns the recorded batches from through to (0: the latest),
+// in order.
+func (uc *AuditArchiveUseCase) batches(ctx context.Context, from, to int64) ([]*domain.AuditBatch, error) {
+	rows, err := uc.sqlcQueries.ListAuditArchiveBatches(ctx, sqlc.ListAuditArchiveBatchesParams{
+		FromSeq: from,
+		ToSeq:   to, // 0: no upper bound
+		Limit:   maxVerifyBatches + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit batches: %w", err)
+	}
+	batches := make([]*domain.AuditBatch, len(rows))
+	for i, row := range rows {
+		batches[i] = &domain.AuditBatch{
+			Seq:            row.Seq,
+			ObjectKey:      row.ObjectKey,
+			Records:        int(row.Records),
+			FirstCreatedAt: row.FirstCreatedAt,
+			LastCreatedAt:  row.LastCreatedAt,
+			SHA256:         row.SHA256,
+			Chain:          row.Chain,
+			ArchivedAt:     row.ArchivedAt,
+		}
+	}
+	return batches, nil
+}
+
+// getAnchor reads the anchor object under key.
+func (uc *AuditArchiveUseCase) getAnchor(ctx context.Context, key string) (*domain.AuditAnchor, error) {
+	body, err := uc.store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("get anchor %s: %w", key, err)
+	}
+	var anchor domain.AuditAnchor
+	if err := json.Unmarshal(body, &anchor); err != nil {
+		return nil, fmt.Errorf("decode anchor %s: %w", key, err)
+	}
+	return &anchor, nil
+}
@@ -0,0 +1,452 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// BatchCreateVMUseCase submits N identical VMs for a Service as one batch
+// (ADR-0015 §19):
+//
+//	Execute()     → parent event + ticket, N child events + tickets  (one TX)
+//	ApproveAll()  → every undecided child approved                  (one TX)
+//	ApproveItem() → one child approved, like a single request       (one TX)
+//
+// Children are ordinary CREATE_VM tickets with a parent; approval and
+// execution per child reuse CreateVMAtomicUseCase, so SoD, environment
+// policy approvals and quota reservation behave exactly as for single
+// requests. Each child executes independently once approved.
+//
+// Placement constraints (domain/batch_placement.go): a batch spread over
+// several clusters gets a cluster assigned to every child as it is
+// approved; node anti-affinity is carried in each child's payload.
+type BatchCreateVMUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	create       *CreateVMAtomicUseCase
+	approvers    ApproverResolver
+	environments EnvironmentPolicies
+	limits       domain.BatchLimits
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewBatchCreateVMUseCase creates a new use case instance.
+func NewBatchCreateVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	create *CreateVMAtomicUseCase,
+	approvers ApproverResolver,
+	environments EnvironmentPolicies,
+	limits domain.BatchLimits,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *BatchCreateVMUseCase {
+	return &BatchCreateVMUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		create:       create,
+		approvers:    approvers,
+		environments: environments,
+		limits:       limits,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// BatchCreateVMRequest contains the batch creation request data.
+// Same fields as CreateVMRequest plus Count; every child gets the same spec.
+type BatchCreateVMRequest struct {
+	ServiceID   string // Required
+	TemplateID  string // Required
+	Namespace   string // Required
+	Count       int    // Required: 1..domain.MaxBatchCreate
+	CPU         int    // Optional: override template default
+	MemoryMB    int    // Optional: override template default
+	Reason      string // Required
+	RequestedBy string // Required
+	Exempt      bool   // Rate-limit exemption (admin-granted), resolved by the handler
+
+	Placement domain.BatchPlacement // Optional: cluster spread, node anti-affinity
+}
+
+// BatchCreateVMResult contains the batch creation result.
+type BatchCreateVMResult struct {
+	BatchTicketID  string
+	EventID        string
+	ChildTicketIDs []string
+}
+
+// BatchItemProgress is the approval progress of one child.
+type BatchItemProgress struct {
+	TicketID string                   `json:"ticket_id"`
+	Progress *domain.ApprovalProgress `json:"progress"`
+}
+
+// BatchPlacementReport is where the children of a batch were assigned and
+// created, and whether that honored the batch's constraints.
+type BatchPlacementReport struct {
+	Placement domain.BatchPlacement         `json:"placement"`
+	Items     []*domain.BatchChildPlacement `json:"items"`
+	Status    domain.BatchPlacementStatus   `json:"status"`
+}
+
+// Execute writes the parent and all children atomically: a batch is
+// either fully submitted or rejected (ADR-0015 §19 Key Guarantee).
+func (uc *BatchCreateVMUseCase) Execute(ctx context.Context, req BatchCreateVMRequest) (*BatchCreateVMResult, error) {
+	if req.Count < 1 || req.Count > domain.MaxBatchCreate {
+		return nil, fmt.Errorf("count %d (max %d): %w", req.Count, domain.MaxBatchCreate, domain.ErrInvalidBatchSize)
+	}
+	if err := req.Placement.Validate(req.Count); err != nil {
+		return nil, err
+	}
+
+	payload := domain.VMCreationPayload{
+		ServiceID:  req.ServiceID,
+		TemplateID: req.TemplateID,
+		Namespace:  req.Namespace,
+		CPU:        req.CPU,
+		MemoryMB:   req.MemoryMB,
+		Reason:     req.Reason,
+	}
+
+	// Identical children: one decision for all. Batches always go through
+	// approval, even where a single VM would auto-approve.
+	decision, err := uc.environments.Decide(ctx, req.ServiceID, req.Namespace, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BatchCreateVMResult{
+		BatchTicketID:  uc.ids.NewID(),
+		EventID:        uc.ids.NewID(),
+		ChildTicketIDs: make([]string, req.Count),
+	}
+	if req.Placement.NodeAntiAffinity {
+		payload.AntiAffinityGroup = result.BatchTicketID
+	}
+	var placement *domain.BatchPlacement
+	if !req.Placement.IsZero() {
+		placement = &req.Placement
+	}
+	childApprovers := make([][]*domain.TicketApprover, req.Count)
+	for i := range result.ChildTicketIDs {
+		result.ChildTicketIDs[i] = uc.ids.NewID()
+		childApprovers[i], err = uc.approvers.Resolve(ctx, result.ChildTicketIDs[i], req.ServiceID, req.RequestedBy, decision.Stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if !req.Exempt {
+		if err := checkBatchLimits(ctx, sqlcTx, uc.limits, req.RequestedBy, req.Count, uc.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 1: Parent event + parent ticket
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       result.EventID,
+		EventType:     string(domain.EventBatchCreateRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventBatchCreateRequested),
+		AggregateType: "Batch",
+		AggregateID:   result.BatchTicketID,
+		Status:        string(domain.EventStatusPending),
+		CreatedBy:     req.RequestedBy,
+	}, domain.BatchCreatePayload{
+		BatchTicketID: result.BatchTicketID,
+		ServiceID:     req.ServiceID,
+		TemplateID:    req.TemplateID,
+		Namespace:     req.Namespace,
+		Count:         req.Count,
+		Reason:        req.Reason,
+		Placement:     placement,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create batch event: %w", err)
+	}
+
+	err = sqlcTx.CreateBatchApprovalTicket(ctx, sqlc.CreateBatchApprovalTicketParams{
+		TicketID:     result.BatchTicketID,
+		EventID:      result.EventID,
+		ServiceID:    req.ServiceID,
+		BatchType:    string(domain.BatchCreate),
+		ChildCount:   int32(req.Count),
+		PendingCount: int32(req.Count),
+		Status:       string(domain.BatchPendingApproval),
+		Reason:       req.Reason,
+		Placement:    req.Placement, // JSONB
+		CreatedBy:    req.RequestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create batch ticket: %w", err)
+	}
+
+	// Step 2: One child event + ticket + approvers per VM, all due together
+	slaDueAt := domain.SLADueAt(uc.clock.Now(), decision.ApprovalSLA, domain.PriorityNormal)
+	for i, childTicketID := range result.ChildTicketIDs {
+		childEventID := uc.ids.NewID()
+		err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+			EventID:       childEventID,
+			EventType:     string(domain.EventVMCreationRequested),
+			SchemaVersion: domain.PayloadVersion(domain.EventVMCreationRequested),
+			AggregateType: "VM",
+			AggregateID:   req.ServiceID + "-" + childEventID[:8], // Temporary ID, as for single requests
+			Status:        string(domain.EventStatusPending),
+			CreatedBy:     req.RequestedBy,
+		}, payload)
+		if err != nil {
+			return nil, fmt.Errorf("create child event %d: %w", i, err)
+		}
+
+		err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+			TicketID:          childTicketID,
+			EventID:           childEventID,
+			ParentTicketID:    result.BatchTicketID,
+			ServiceID:         req.ServiceID,
+			RequestType:       "CREATE_VM",
+			RequestReason:     req.Reason,
+			Status:            "PENDING_APPROVAL",
+			Priority:          string(domain.PriorityNormal),
+			Environment:       string(decision.Environment),
+			RequiredApprovals: int32(decision.RequiredApprovals),
+			Stages:            decision.Stages,
+			SLADueAt:          slaDueAt,
+			CreatedBy:         req.RequestedBy,
+		})
+		if err != nil {
+			// Entire batch rolls back
+			return nil, fmt.Errorf("create child ticket %d: %w", i, err)
+		}
+
+		for _, a := range childApprovers[i] {
+			err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+				TicketID: childTicketID,
+				UserID:   a.UserID,
+				Source:   string(a.Source),
+				Stage:    int32(a.Stage),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("assign approver: %w", err)
+			}
+		}
+	}
+
+	// Step 3: Atomic commit - all tickets created or none
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// ApproveAll approves every undecided child in one transaction: either
+// all approvals (and their quota reservations) are recorded or none,
+// e.g. when the Service quota cannot hold the whole batch. Children of a
+// spread batch without a cluster are assigned one.
+func (uc *BatchCreateVMUseCase) ApproveAll(ctx context.Context, batchTicketID, approverID string) ([]BatchItemProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Parent lock first, children after (same order as ApproveItem)
+	batch, err := sqlcTx.GetBatchApprovalTicketForUpdate(ctx, batchTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("get batch ticket: %w", err)
+	}
+	children, err := sqlcTx.ListUndecidedBatchChildren(ctx, batchTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("list batch children: %w", err)
+	}
+
+	var spread map[string]string
+	if batch.Placement.Spreads() {
+		if spread, err = assignBatchSpread(ctx, sqlcTx, batchTicketID, batch.Placement, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]BatchItemProgress, 0, len(children))
+	for _, child := range children {
+		var modifiedSpec *domain.ModifiedSpec
+		if clusterID, ok := spread[child.TicketID]; ok {
+			if modifiedSpec, err = withSpreadCluster(child.ModifiedSpec, nil, clusterID, approverID, batch.Placement); err != nil {
+				return nil, err
+			}
+		}
+		progress, err := uc.create.approveTx(ctx, tx, child.TicketID, approverID, modifiedSpec, nil)
+		if err != nil {
+			return nil, fmt.Errorf("approve %s: %w", child.TicketID, err)
+		}
+		items = append(items, BatchItemProgress{TicketID: child.TicketID, Progress: progress})
+	}
+
+	if err := refreshBatchStatus(ctx, sqlcTx, batchTicketID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return items, nil
+}
+
+// ApproveItem approves one child; modifiedSpec applies to that child only.
+// In a spread batch, a cluster_id in modifiedSpec must keep the spread
+// reachable for the other children; without one the child is assigned a
+// cluster.
+func (uc *BatchCreateVMUseCase) ApproveItem(ctx context.Context, batchTicketID, childTicketID, approverID string, modifiedSpec *domain.ModifiedSpec) (*domain.ApprovalProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	batch, err := sqlcTx.GetBatchApprovalTicketForUpdate(ctx, batchTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("get batch ticket: %w", err)
+	}
+	child, err := sqlcTx.GetApprovalTicket(ctx, childTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if child.ParentTicketID != batchTicketID {
+		return nil, domain.ErrNotBatchChild
+	}
+
+	if batch.Placement.Spreads() {
+		picks := map[string]string{}
+		if modifiedSpec != nil && modifiedSpec.ClusterID != nil {
+			picks[childTicketID] = *modifiedSpec.ClusterID
+		}
+		spread, err := assignBatchSpread(ctx, sqlcTx, batchTicketID, batch.Placement, picks)
+		if err != nil {
+			return nil, err
+		}
+		// Only this child's assignment is kept; the others are assigned at their own approval
+		if clusterID, ok := spread[childTicketID]; ok {
+			if modifiedSpec, err = withSpreadCluster(child.ModifiedSpec, modifiedSpec, clusterID, approverID, batch.Placement); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	progress, err := uc.create.approveTx(ctx, tx, childTicketID, approverID, modifiedSpec, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshBatchStatus(ctx, sqlcTx, batchTicketID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return progress, nil
+}
+
+// Placement returns the assigned and actual placement of every child.
+func (uc *BatchCreateVMUseCase) Placement(ctx context.Context, batchTicketID string) (*BatchPlacementReport, error) {
+	batch, err := uc.sqlcQueries.GetBatchApprovalTicket(ctx, batchTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("get batch ticket: %w", err)
+	}
+	children, _, err := batchChildPlacements(ctx, uc.sqlcQueries, batchTicketID)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchPlacementReport{
+		Placement: batch.Placement,
+		Items:     children,
+		Status:    domain.EvaluateBatchPlacement(batch.Placement, children),
+	}, nil
+}
+
+// checkBatchLimits enforces the submission rate limits for a batch of
+// count children. The per-user advisory lock makes concurrent submissions
+// see each other's counts.
+func checkBatchLimits(ctx context.Context, sqlcTx *sqlc.Queries, limits domain.BatchLimits, userID string, count int, now time.Time) error {
+	if err := sqlcTx.LockUserBatchSubmissions(ctx, userID); err != nil {
+		return fmt.Errorf("lock batch submissions: %w", err)
+	}
+	usage, err := sqlcTx.GetBatchUsage(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get batch usage: %w", err)
+	}
+	return limits.Check(domain.BatchUsage{
+		GlobalPendingBatches: int(usage.GlobalPendingBatches),
+		UserPendingBatches:   int(usage.UserPendingBatches),
+		UserPendingChildren:  int(usage.UserPendingChildren),
+		UserLastSubmittedAt:  usage.UserLastSubmittedAt,
+	}, count, now)
+}
+
+// refreshBatchStatus moves the parent to IN_PROGRESS (and its event to
+// PROCESSING) once no child awaits a decision, or straight to a terminal
+// status if no child is left to execute (all rejected). Otherwise terminal
+// statuses are set by the children's execution results
+// (repository.RecordBatchItemResult).
+func refreshBatchStatus(ctx context.Context, sqlcTx *sqlc.Queries, batchTicketID string) error {
+	undecided, err := sqlcTx.CountUndecidedBatchChildren(ctx, batchTicketID)
+	if err != nil {
+		return fmt.Errorf("count undecided children: %w", err)
+	}
+	if undecided > 0 {
+		return nil
+	}
+
+	batch, err := sqlcTx.GetBatchApprovalTicket(ctx, batchTicketID)
+	if err != nil {
+		return fmt.Errorf("get batch ticket: %w", err)
+	}
+	t := domain.BatchApprovalTicket{
+		SuccessCount: int(batch.SuccessCount),
+		FailedCount:  int(batch.FailedCount),
+		PendingCount: int(batch.PendingCount),
+	}
+	status := t.CalculateStatus(0)
+	eventStatus := domain.EventStatusProcessing
+	switch status {
+	case domain.BatchFailed:
+		eventStatus = domain.EventStatusFailed
+	case domain.BatchCompleted, domain.BatchPartialSuccess:
+		eventStatus = domain.EventStatusCompleted
+	}
+
+	err = sqlcTx.UpdateBatchApprovalTicketStatus(ctx, sqlc.UpdateBatchApprovalTicketStatusParams{
+		TicketID: batchTicketID,
+		Status:   string(status),
+	})
+	if err != nil {
+		return fmt.Errorf("update batch ticket: %w", err)
+	}
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: batch.EventID,
+		Status:  string(eventStatus),
+	})
+	if err != nil {
+		return fmt.Errorf("update batch event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// BatchDeleteVMUseCase deletes the VMs of a Service matched by a label
+// selector as one batch (ADR-0015 §19):
+//
+//	Preview()     → VMs the selector matches now               (read-only)
+//	Execute()     → parent event + ticket with the VM snapshot,
+//	                one child event + ticket per VM            (one TX)
+//	ApproveAll()  → every undecided child approved             (one TX)
+//	ApproveItem() → one child approved, like a single request  (one TX)
+//
+// The selector is resolved once, at submission; the resolved list is
+// stored in the parent payload and never re-evaluated. Children are
+// ordinary DELETE_VM tickets with a parent; approval reuses
+// DeleteVMAtomicUseCase, so each approved child marks its VM DELETING and
+// enqueues its own River job. The parent ticket counters are the batch
+// progress record.
+type BatchDeleteVMUseCase struct {
+	pool          *pgxpool.Pool
+	sqlcQueries   *sqlc.Queries
+	del           *DeleteVMAtomicUseCase
+	vmRepo        repository.VMRepository
+	namespaceRepo repository.NamespaceRepository
+	approvers     ApproverResolver
+	environments  EnvironmentPolicies
+	limits        domain.BatchLimits
+	clock         domain.Clock
+	ids           domain.IDGenerator
+}
+
+// NewBatchDeleteVMUseCase creates a new use case instance.
+func NewBatchDeleteVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	del *DeleteVMAtomicUseCase,
+	vmRepo repository.VMRepository,
+	namespaceRepo repository.NamespaceRepository,
+	approvers ApproverResolver,
+	environments EnvironmentPolicies,
+	limits domain.BatchLimits,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *BatchDeleteVMUseCase {
+	return &BatchDeleteVMUseCase{
+		pool:          pool,
+		sqlcQueries:   sqlcQueries,
+		del:           del,
+		vmRepo:        vmRepo,
+		namespaceRepo: namespaceRepo,
+		approvers:     approvers,
+		environments:  environments,
+		limits:        limits,
+		clock:         clock,
+		ids:           ids,
+	}
+}
+
+// BatchDeleteVMRequest contains the batch deletion request data.
+type BatchDeleteVMRequest struct {
+	ServiceID   string // Required
+	Selector    string // Required: label selector over the Service's VMs
+	Reason      string // Required
+	RequestedBy string // Required
+	Exempt      bool   // Rate-limit exemption (admin-granted), resolved by the handler
+
+	// Tiered confirmation: Confirm if all VMs are in test namespaces,
+	// ConfirmCount (number of VMs from Preview) if any is in prod.
+	Confirm      bool
+	ConfirmCount int
+}
+
+// BatchDeleteVMResult contains the batch deletion result.
+type BatchDeleteVMResult struct {
+	BatchTicketID  string
+	EventID        string
+	VMs            []domain.BatchDeleteItem
+	ChildTicketIDs []string // Same order as VMs
+}
+
+// Preview returns the VMs the selector matches now, for the
+// confirmation dialog. Nothing is written.
+func (uc *BatchDeleteVMUseCase) Preview(ctx context.Context, serviceID, selector string) ([]*domain.VM, error) {
+	return uc.resolve(ctx, serviceID, selector)
+}
+
+// Execute snapshots the matched VMs and writes the parent and all
+// children atomically. If any VM is being deleted or has an operation in
+// flight, the whole batch is rejected: the user narrows the selector
+// instead of getting a batch that silently skips VMs.
+func (uc *BatchDeleteVMUseCase) Execute(ctx context.Context, req BatchDeleteVMRequest) (*BatchDeleteVMResult, error) {
+	vms, err := uc.resolve(ctx, req.ServiceID, req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	// One environment decision per namespace; the strictest applies to all
+	// children. Deletions never auto-approve.
+	anyProd := false
+	requiredApprovals := 0
+	var slaDueAt *time.Time // Earliest due time of all decisions
+	var environment domain.DeploymentEnvironment
+	decided := map[string]bool{}
+	for _, vm := range vms {
+		if decided[vm.Namespace] {
+			continue
+		}
+		decided[vm.Namespace] = true
+
+		nsEnv, err := uc.namespaceRepo.GetEnvironment(ctx, vm.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("get namespace environment: %w", err)
+		}
+		anyProd = anyProd || nsEnv == "prod"
+
+		decision, err := uc.environments.Decide(ctx, req.ServiceID, vm.Namespace, &domain.VMCreationPayload{ServiceID: req.ServiceID})
+		if err != nil {
+			return nil, err
+		}
+		environment = decision.Environment
+		requiredApprovals = max(requiredApprovals, decision.RequiredApprovals)
+		if due := domain.SLADueAt(uc.clock.Now(), decision.ApprovalSLA, domain.PriorityNormal); slaDueAt == nil || due.Before(*slaDueAt) {
+			slaDueAt = due
+		}
+	}
+	if err := domain.CheckBatchDeleteConfirmation(len(vms), anyProd, req.Confirm, req.ConfirmCount); err != nil {
+		return nil, err
+	}
+
+	result := &BatchDeleteVMResult{
+		BatchTicketID:  uc.ids.NewID(),
+		EventID:        uc.ids.NewID(),
+		VMs:            make([]domain.BatchDeleteItem, len(vms)),
+		ChildTicketIDs: make([]string, len(vms)),
+	}
+	// Staged approval is for sizing decisions: deletions use one stage
+	stages := domain.DefaultStages(requiredApprovals)
+	childApprovers := make([][]*domain.TicketApprover, len(vms))
+	for i, vm := range vms {
+		result.VMs[i] = domain.BatchDeleteItem{VMID: vm.ID, Name: vm.Name, Namespace: vm.Namespace, Cluster: vm.Cluster}
+		result.ChildTicketIDs[i] = uc.ids.NewID()
+		childApprovers[i], err = uc.approvers.Resolve(ctx, result.ChildTicketIDs[i], req.ServiceID, req.RequestedBy, stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if !req.Exempt {
+		if err := checkBatchLimits(ctx, sqlcTx, uc.limits, req.RequestedBy, len(vms), uc.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 1: Lock every VM (in ID order, so overlapping batches cannot
+	// deadlock) and check none has an operation in flight
+	for _, vm := range vms {
+		if _, err := lockVM(ctx, sqlcTx, vm.ID); err != nil {
+			return nil, fmt.Errorf("vm %s: %w", vm.Name, err)
+		}
+	}
+
+	// Step 2: Parent event (with the VM snapshot) + parent ticket
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       result.EventID,
+		EventType:     string(domain.EventBatchDeleteRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventBatchDeleteRequested),
+		AggregateType: "Batch",
+		AggregateID:   result.BatchTicketID,
+		Status:        string(domain.EventStatusPending),
+		CreatedBy:     req.RequestedBy,
+	}, domain.BatchDeletePayload{
+		BatchTicketID: result.BatchTicketID,
+		ServiceID:     req.ServiceID,
+		Selector:      req.Selector,
+		VMs:           result.VMs,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create batch event: %w", err)
+	}
+
+	err = sqlcTx.CreateBatchApprovalTicket(ctx, sqlc.CreateBatchApprovalTicketParams{
+		TicketID:     result.BatchTicketID,
+		EventID:      result.EventID,
+		ServiceID:    req.ServiceID,
+		BatchType:    string(domain.BatchDelete),
+		ChildCount:   int32(len(vms)),
+		PendingCount: int32(len(vms)),
+		Status:       string(domain.BatchPendingApproval),
+		Reason:       req.Reason,
+		CreatedBy:    req.RequestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create batch ticket: %w", err)
+	}
+
+	// Step 3: One child event + ticket + approvers per VM
+	for i, vm := range vms {
+		childEventID := uc.ids.NewID()
+		err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+			EventID:       childEventID,
+			EventType:     string(domain.EventVMDeletionRequested),
+			SchemaVersion: domain.PayloadVersion(domain.EventVMDeletionRequested),
+			AggregateType: "VM",
+			AggregateID:   vm.ID, // Blocks other operations on the VM (CountPendingVMEvents)
+			Status:        string(domain.EventStatusPending),
+			CreatedBy:     req.RequestedBy,
+		}, domain.VMDeletionPayload{
+			VMID:      vm.ID,
+			Name:      vm.Name,
+			Namespace: vm.Namespace,
+			Cluster:   vm.Cluster,
+			ServiceID: vm.ServiceID,
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create child event %d: %w", i, err)
+		}
+
+		err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+			TicketID:          result.ChildTicketIDs[i],
+			EventID:           childEventID,
+			ParentTicketID:    result.BatchTicketID,
+			ServiceID:         req.ServiceID,
+			RequestType:       "DELETE_VM",
+			RequestReason:     req.Reason,
+			Status:            "PENDING_APPROVAL",
+			Priority:          string(domain.PriorityNormal),
+			Environment:       string(environment),
+			RequiredApprovals: int32(requiredApprovals),
+			Stages:            stages,
+			SLADueAt:          slaDueAt,
+			CreatedBy:         req.RequestedBy,
+		})
+		if err != nil {
+			// Entire batch rolls back
+			return nil, fmt.Errorf("create child ticket %d: %w", i, err)
+		}
+
+		for _, a := range childApprovers[i] {
+			err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+				TicketID: result.ChildTicketIDs[i],
+				UserID:   a.UserID,
+				Source:   string(a.Source),
+				Stage:    int32(a.Stage),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("assign approver: %w", err)
+			}
+		}
+	}
+
+	vmIDs := make([]string, len(vms))
+	for i, vm := range vms {
+		vmIDs[i] = vm.ID
+	}
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditBatchDeleteRequested,
+		ActorID:      req.RequestedBy,
+		ResourceType: "batch",
+		ResourceID:   result.BatchTicketID,
+		Details: map[string]interface{}{
+			"service_id": req.ServiceID,
+			"selector":   req.Selector,
+			"vm_ids":     vmIDs,
+			"reason":     req.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	// Step 4: Atomic commit - all tickets created or none
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// ApproveAll approves every undecided child in one transaction. On each
+// child's last required approval its VM is marked DELETING and its job
+// is enqueued, so the jobs of a fully approved batch start together.
+func (uc *BatchDeleteVMUseCase) ApproveAll(ctx context.Context, batchTicketID, approverID string) ([]BatchItemProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Parent lock first, children after (same order as ApproveItem)
+	if _, err := sqlcTx.GetBatchApprovalTicketForUpdate(ctx, batchTicketID); err != nil {
+		return nil, fmt.Errorf("get batch ticket: %w", err)
+	}
+	children, err := sqlcTx.ListUndecidedBatchChildren(ctx, batchTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("list batch children: %w", err)
+	}
+
+	items := make([]BatchItemProgress, 0, len(children))
+	for _, child := range children {
+		progress, err := uc.del.approveTx(ctx, tx, child.TicketID, approverID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("approve %s: %w", child.TicketID, err)
+		}
+		items = append(items, BatchItemProgress{TicketID: child.TicketID, Progress: progress})
+	}
+
+	if err := refreshBatchStatus(ctx, sqlcTx, batchTicketID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return items, nil
+}
+
+// ApproveItem approves one child, optionally with modifications
+// (modified_by/modified_reason only, as for a single deletion).
+func (uc *BatchDeleteVMUseCase) ApproveItem(ctx context.Context, batchTicketID, childTicketID, approverID string, modifiedSpec *domain.ModifiedSpec) (*domain.ApprovalProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if _, err := sqlcTx.GetBatchApprovalTicketForUpdate(ctx, batchTicketID); err != nil {
+		return nil, fmt.Errorf("get batch ticket: %w", err)
+	}
+	child, err := sqlcTx.GetApprovalTicket(ctx, childTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if child.ParentTicketID != batchTicketID {
+		return nil, domain.ErrNotBatchChild
+	}
+
+	progress, err := uc.del.approveTx(ctx, tx, childTicketID, approverID, modifiedSpec, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshBatchStatus(ctx, sqlcTx, batchTicketID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return progress, nil
+}
+
+// resolve lists the Service's VMs matching selector, sorted by ID (the
+// lock order in Execute). The Service scope is always applied: a
+// selector cannot reach another Service's VMs.
+func (uc *BatchDeleteVMUseCase) resolve(ctx context.Context, serviceID, selector string) ([]*domain.VM, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err, domain.ErrInvalidSelector)
+	}
+	if sel.Empty() {
+		// An empty selector matches everything; "delete the whole Service"
+		// must be spelled out
+		return nil, fmt.Errorf("selector is required: %w", domain.ErrInvalidSelector)
+	}
+
+	all, err := uc.vmRepo.ListByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("list vms: %w", err)
+	}
+	var vms []*domain.VM
+	for _, vm := range all {
+		if sel.Matches(labels.Set(vm.Labels)) {
+			vms = append(vms, vm)
+		}
+	}
+
+	if len(vms) == 0 {
+		return nil, domain.ErrNoVMsMatched
+	}
+	if len(vms) > domain.MaxBatchDelete {
+		return nil, fmt.Errorf("selector matches %d vms (max %d): %w", len(vms), domain.MaxBatchDelete, domain.ErrInvalidBatchSize)
+	}
+	sort.Slice(vms, func(i, j int) bool { return vms[i].ID < vms[j].ID })
+	return vms, nil
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// batchChildPlacements returns every child of the batch with its assigned
+// and recorded placement (domain/batch_placement.go), and the
+// environment the children were submitted for.
+func batchChildPlacements(ctx context.Context, sqlcTx *sqlc.Queries, batchTicketID string) ([]*domain.BatchChildPlacement, string, error) {
+	// cluster_id is modified_spec->>'cluster_id'; placed_* are set by the worker
+	rows, err := sqlcTx.ListBatchChildPlacements(ctx, batchTicketID)
+	if err != nil {
+		return nil, "", fmt.Errorf("list batch child placements: %w", err)
+	}
+	children := make([]*domain.BatchChildPlacement, len(rows))
+	environment := ""
+	for i, row := range rows {
+		children[i] = &domain.BatchChildPlacement{
+			TicketID:  row.TicketID,
+			Status:    domain.TicketStatus(row.Status),
+			ClusterID: row.ClusterID,
+			Cluster:   row.PlacedCluster,
+			Node:      row.PlacedNode,
+		}
+		environment = row.Environment // Identical for all children
+	}
+	return children, environment, nil
+}
+
+// assignBatchSpread returns the cluster to approve each child on that has
+// none yet, for a batch with cluster spread. picks are clusters an
+// approver chose in this approval, by child ticket; they count as
+// assigned, so a pick that makes the spread unreachable fails with
+// domain.ErrBatchPlacementUnsatisfiable.
+func assignBatchSpread(ctx context.Context, sqlcTx *sqlc.Queries, batchTicketID string, placement domain.BatchPlacement, picks map[string]string) (map[string]string, error) {
+	children, environment, err := batchChildPlacements(ctx, sqlcTx, batchTicketID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range children {
+		if id := picks[c.TicketID]; id != "" {
+			c.ClusterID = id
+		}
+	}
+
+	clusters, err := environmentClusters(ctx, sqlcTx, environment)
+	if err != nil {
+		return nil, err
+	}
+	return domain.AssignSpread(placement, children, domain.FilterPlacementCandidates(clusters, environment))
+}
+
+// withSpreadCluster returns the modification to approve a child with on
+// clusterID: the approver's own modification if any, else the one stored
+// on the ticket by an earlier approver, so spreading never drops another
+// change.
+func withSpreadCluster(stored []byte, modifiedSpec *domain.ModifiedSpec, clusterID, approverID string, placement domain.BatchPlacement) (*domain.ModifiedSpec, error) {
+	mods := modifiedSpec
+	if mods == nil {
+		mods = &domain.ModifiedSpec{}
+		if stored != nil {
+			if err := json.Unmarshal(stored, mods); err != nil {
+				return nil, fmt.Errorf("decode modified spec: %w", err)
+			}
+		}
+	}
+	mods.ClusterID = &clusterID
+	if mods.ModifiedBy == "" {
+		mods.ModifiedBy = approverID
+	}
+	if mods.ModifiedReason == "" {
+		mods.ModifiedReason = fmt.Sprintf("batch spread across at least %d clusters", placement.MinClusters)
+	}
+	return mods, nil
+}
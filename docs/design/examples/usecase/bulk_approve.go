@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// BulkApproveUseCase approves many pending tickets for one platform admin
+// (domain/bulk_approval.go).
+//
+// Each ticket goes through its request type's ApproveAndEnqueue, in its
+// own transaction: the same SoD checks, stages, quota reservation and
+// River job insert as a single approval. A ticket that fails (SoD,
+// decided meanwhile, quota) is reported and the rest go on; a ticket that
+// needs further approvals (two-person rule, later stages) is reported
+// with its progress and stays pending.
+type BulkApproveUseCase struct {
+	sqlcQueries *sqlc.Queries
+	create      *CreateVMAtomicUseCase
+	modify      *ModifyVMAtomicUseCase
+	del         *DeleteVMAtomicUseCase
+	power       *PowerOperationUseCase
+	lease       *LeaseRenewalUseCase
+	snapshot    *SnapshotVMUseCase
+	ids         domain.IDGenerator
+}
+
+// NewBulkApproveUseCase creates a new use case instance.
+func NewBulkApproveUseCase(
+	sqlcQueries *sqlc.Queries,
+	create *CreateVMAtomicUseCase,
+	modify *ModifyVMAtomicUseCase,
+	del *DeleteVMAtomicUseCase,
+	power *PowerOperationUseCase,
+	lease *LeaseRenewalUseCase,
+	snapshot *SnapshotVMUseCase,
+	ids domain.IDGenerator,
+) *BulkApproveUseCase {
+	return &BulkApproveUseCase{
+		sqlcQueries: sqlcQueries,
+		create:      create,
+		modify:      modify,
+		del:         del,
+		power:       power,
+		lease:       lease,
+		snapshot:    snapshot,
+		ids:         ids,
+	}
+}
+
+// BulkApprovalResult is the outcome for one ticket: Progress on success,
+// Err otherwise.
+type BulkApprovalResult struct {
+	TicketID string
+	Progress *domain.ApprovalProgress
+	Err      error
+}
+
+// bulkCandidate is a ticket to approve, as read before its approval TX.
+type bulkCandidate struct {
+	ticketID       string
+	requestType    string
+	parentTicketID string
+	err            error // Not approvable, e.g. unknown ID
+}
+
+// Execute approves the selected tickets as approverID, in the order given
+// (ticket IDs) or in inbox order (filter). The error is non-nil only when
+// the selection is invalid or cannot be resolved; per-ticket failures are
+// in the results.
+func (uc *BulkApproveUseCase) Execute(ctx context.Context, sel domain.BulkApprovalSelection, approverID string) ([]BulkApprovalResult, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+
+	var candidates []bulkCandidate
+	if sel.Filter != nil {
+		rows, err := uc.sqlcQueries.ListBulkApprovalCandidates(ctx, sqlc.ListBulkApprovalCandidatesParams{
+			RequestType: sel.Filter.RequestType,
+			ServiceID:   sel.Filter.ServiceID,
+			CreatedBy:   sel.Filter.RequestedBy,
+			Limit:       domain.MaxBulkApproval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list bulk approval candidates: %w", err)
+		}
+		for _, row := range rows {
+			candidates = append(candidates, bulkCandidate{ticketID: row.TicketID, requestType: row.RequestType, parentTicketID: row.ParentTicketID})
+		}
+	} else {
+		seen := make(map[string]bool, len(sel.TicketIDs))
+		for _, id := range sel.TicketIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ticket, err := uc.sqlcQueries.GetApprovalTicket(ctx, id)
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				candidates = append(candidates, bulkCandidate{ticketID: id, err: repository.ErrNotFound})
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("get ticket %s: %w", id, err)
+			}
+			candidates = append(candidates, bulkCandidate{ticketID: id, requestType: ticket.RequestType, parentTicketID: ticket.ParentTicketID})
+		}
+	}
+
+	results := make([]BulkApprovalResult, 0, len(candidates))
+	for _, c := range candidates {
+		progress, err := uc.approve(ctx, c, approverID)
+		results = append(results, BulkApprovalResult{TicketID: c.ticketID, Progress: progress, Err: err})
+	}
+
+	uc.audit(ctx, sel, approverID, results)
+	return results, nil
+}
+
+// approve dispatches to the request type's use case. No modified spec:
+// bulk approval accepts each ticket as it stands.
+func (uc *BulkApproveUseCase) approve(ctx context.Context, c bulkCandidate, approverID string) (*domain.ApprovalProgress, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.parentTicketID != "" {
+		return nil, fmt.Errorf("%w: batch child of %s", domain.ErrBulkApprovalUnsupported, c.parentTicketID)
+	}
+	switch c.requestType {
+	case "CREATE_VM":
+		return uc.create.ApproveAndEnqueue(ctx, c.ticketID, approverID, nil, nil)
+	case "MODIFY_VM":
+		return uc.modify.ApproveAndEnqueue(ctx, c.ticketID, approverID, nil, nil)
+	case "DELETE_VM":
+		return uc.del.ApproveAndEnqueue(ctx, c.ticketID, approverID, nil, nil)
+	case "START_VM", "STOP_VM", "RESTART_VM":
+		return uc.power.ApproveAndEnqueue(ctx, c.ticketID, approverID, nil, nil)
+	case domain.LeaseRenewalRequestType:
+		return uc.lease.Approve(ctx, c.ticketID, approverID)
+	case domain.SnapshotRequestType, domain.BackupRequestType:
+		return uc.snapshot.ApproveAndEnqueue(ctx, c.ticketID, approverID, nil)
+	}
+	return nil, fmt.Errorf("%w: request type %s", domain.ErrBulkApprovalUnsupported, c.requestType)
+}
+
+// audit records the bulk call itself via the pool (autocommit); each
+// approval is already recorded with its ticket. Best effort: the
+// approvals are committed.
+func (uc *BulkApproveUseCase) audit(ctx context.Context, sel domain.BulkApprovalSelection, approverID string, results []BulkApprovalResult) {
+	var approved, pending, failed []string
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed = append(failed, r.TicketID)
+		case r.Progress.Approved:
+			approved = append(approved, r.TicketID)
+		default:
+			pending = append(pending, r.TicketID)
+		}
+	}
+
+	details := map[string]interface{}{
+		"approved": approved,
+		"pending":  pending, // Approval recorded, more required
+		"failed":   failed,
+	}
+	if sel.Filter != nil {
+		details["filter"] = sel.Filter
+	}
+	err := uc.sqlcQueries.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditApprovalBulkApproved,
+		ActorID:      approverID,
+		ResourceType: "approval",
+		Details:      details,
+	})
+	if err != nil {
+		logger.WarnCtx(ctx, "Failed to audit bulk approval",
+			zap.Int("tickets", len(results)),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// CancelRequestUseCase lets a requester withdraw their own request while
+// it is still PENDING_APPROVAL (ADR-0015 §10).
+//
+// Works for tickets of any request type: before approval no River job
+// exists and no quota is reserved, so cancelling only changes status
+// (see cancelTicket). Approval paths re-check the status under the same
+// row lock, so a cancelled ticket is never enqueued.
+type CancelRequestUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	ids         domain.IDGenerator
+}
+
+// NewCancelRequestUseCase creates a new use case instance.
+func NewCancelRequestUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, ids domain.IDGenerator) *CancelRequestUseCase {
+	return &CancelRequestUseCase{pool: pool, sqlcQueries: sqlcQueries, ids: ids}
+}
+
+// Execute cancels the ticket. Returns domain.ErrNotRequester when userID
+// did not submit it and domain.ErrTicketNotPending once an approver has
+// decided (approval already given, or rejected).
+func (uc *CancelRequestUseCase) Execute(ctx context.Context, ticketID, userID, reason string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := cancelTicket(ctx, uc.sqlcQueries.WithTx(tx), uc.ids, ticketID, userID, reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
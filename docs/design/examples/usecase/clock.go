@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SystemClock is the production domain.Clock.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// UUIDGenerator is the production domain.IDGenerator: random UUIDv4s, as
+// the IDs have always been.
+type UUIDGenerator struct{}
+
+// NewID returns a new random UUID.
+func (UUIDGenerator) NewID() string { return uuid.New().String() }
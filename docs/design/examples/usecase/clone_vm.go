@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// CloneVMUseCase clones a VM, live or from one of its snapshots
+// (domain/vm_clone.go), with the same atomic transaction pattern as
+// CreateVMAtomicUseCase (ADR-0012):
+//
+//	Submit(), routed to a ticket   → Event + Ticket, no River Job            → PENDING_APPROVAL
+//	ApproveAndEnqueue()            → Ticket APPROVED, quota held, River Job  → APPROVED
+//	Submit(), auto-approved        → Event + Ticket + quota + Job in one TX  → PROCESSING
+//
+// The ticket belongs to the target Service: its environment policy,
+// approvers and quota apply. The clone is made by CloneHandler.
+type CloneVMUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	vmRepo       repository.VMRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	permissions  domain.PermissionChecker
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewCloneVMUseCase creates a new use case instance.
+func NewCloneVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	permissions domain.PermissionChecker,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *CloneVMUseCase {
+	return &CloneVMUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		vmRepo:       vmRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		router:       router,
+		permissions:  permissions,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// CloneVMResult contains the clone request result.
+type CloneVMResult struct {
+	EventID    string
+	TicketID   string
+	TargetName string
+	Route      *domain.ApprovalRoute
+}
+
+// Submit routes the request by the target Service's approval policy:
+// auto-approved clones are enqueued at once, all others get a ticket.
+func (uc *CloneVMUseCase) Submit(ctx context.Context, vmID string, req domain.CloneRequest, requestedBy string) (*CloneVMResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	fromSnapshot := req.SnapshotName != ""
+	// Rechecked under the VM lock; this spares the routing for the common mistake
+	if err := domain.CheckClone(vm.Status, fromSnapshot); err != nil {
+		return nil, err
+	}
+	targetServiceID := req.TargetServiceID
+	if targetServiceID == "" {
+		targetServiceID = vm.ServiceID
+	}
+	if err := uc.authorize(requestedBy, vm.ServiceID, targetServiceID); err != nil {
+		return nil, err
+	}
+
+	payload := domain.ClonePayload{
+		SourceVMID:   vm.ID,
+		SourceName:   vm.Name,
+		SnapshotName: req.SnapshotName,
+		Namespace:    vm.Namespace,
+		Cluster:      vm.Cluster,
+		ServiceID:    targetServiceID,
+		CPU:          vm.CPU,
+		MemoryMB:     vm.MemoryMB,
+		DiskGB:       vm.DiskGB,
+		GPUs:         vm.GPUs,
+		Reason:       req.Reason,
+	}
+
+	// The namespace must fit the target Service's environment, as for a new VM
+	decision, err := uc.environments.Decide(ctx, targetServiceID, vm.Namespace, payload.Spec())
+	if err != nil {
+		return nil, err
+	}
+	route, err := uc.router.Route(ctx, targetServiceID, requestedBy, domain.ApprovalRequest{
+		RequestType: domain.CloneRequestType,
+		Namespace:   vm.Namespace,
+		CPU:         vm.CPU,
+		MemoryMB:    vm.MemoryMB,
+		DiskGB:      vm.DiskGB,
+	}, decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	var approvers []*domain.TicketApprover
+	if !route.AutoApprove {
+		approvers, err = uc.approvers.Resolve(ctx, ticketID, targetServiceID, requestedBy, decision.Stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Serializes requests against the source VM
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckClone(status, fromSnapshot); err != nil {
+		return nil, err
+	}
+
+	// Increments vm_name_indexes for (service, namespace) and returns the
+	// names: an index is never handed out twice, even if this request is
+	// rejected
+	naming, err := sqlcTx.NextVMIndex(ctx, sqlc.NextVMIndexParams{
+		ServiceID: targetServiceID,
+		Namespace: vm.Namespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("next vm index: %w", err)
+	}
+	if int(naming.Index) > domain.MaxVMIndex {
+		return nil, domain.ErrVMIndexExhausted
+	}
+	payload.Instance = fmt.Sprintf("%02d", naming.Index)
+	payload.TargetName = domain.GenerateVMName(vm.Namespace, naming.SystemName, naming.ServiceName, int(naming.Index))
+
+	err = writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:     eventID,
+		ticketID:    ticketID,
+		vmID:        vm.ID,
+		vmName:      vm.Name,
+		serviceID:   targetServiceID,
+		requestType: domain.CloneRequestType,
+		eventType:   domain.EventVMCloneRequested,
+		payload:     payload,
+		reason:      req.Reason,
+		requestedBy: requestedBy,
+		decision:    *decision,
+		autoApprove: route.AutoApprove,
+		approvers:   approvers,
+		auditAction: domain.AuditVMCloneRequested,
+		auditDetails: map[string]interface{}{
+			"target_name":       payload.TargetName,
+			"target_service_id": targetServiceID,
+			"snapshot_name":     req.SnapshotName,
+			"reason":            req.Reason,
+			"auto_approve":      route.AutoApprove,
+			"route_reason":      route.Reason,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if route.AutoApprove {
+		if err := reserveClone(ctx, sqlcTx, uc.ids, ticketID, eventID, payload); err != nil {
+			return nil, err
+		}
+		if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, eventID, domain.QueueNormal, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &CloneVMResult{
+		EventID:    eventID,
+		TicketID:   ticketID,
+		TargetName: payload.TargetName,
+		Route:      route,
+	}, nil
+}
+
+// ApproveAndEnqueue records an approval. Once the ticket has its required
+// approvals, the clone's size is reserved against the target Service's
+// quota and the River job inserted, in the same transaction. executeAt,
+// when set, plans the clone for later.
+func (uc *CloneVMUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, false)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusProcessing),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	payload, err := decodeEventPayload[domain.ClonePayload](event)
+	if err != nil {
+		return nil, fmt.Errorf("decode clone payload: %w", err)
+	}
+	if err := reserveClone(ctx, sqlcTx, uc.ids, ticketID, ticket.EventID, payload); err != nil {
+		return nil, err
+	}
+
+	if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, ticket.EventID, domain.QueueNormal, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// authorize checks the requester may copy the source VM and create VMs in
+// the target Service.
+func (uc *CloneVMUseCase) authorize(userID, sourceServiceID, targetServiceID string) error {
+	checks := []struct{ perm, serviceID string }{
+		{domain.CloneSourcePermission, sourceServiceID},
+		{domain.CloneTargetPermission, targetServiceID},
+	}
+	for _, c := range checks {
+		p, err := uc.permissions.CheckPermission(userID, c.perm, string(domain.ResourceTypeService), c.serviceID)
+		if err != nil {
+			return fmt.Errorf("check permission: %w", err)
+		}
+		if !p.Allowed {
+			return domain.ErrCloneForbidden
+		}
+	}
+	return nil
+}
+
+// reserveClone holds the clone's size against the target Service's quota.
+// The clone lands on the source's cluster, so only its headroom counts.
+// Settled by CloneHandler, or by the quota sweep.
+func reserveClone(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, ticketID, eventID string, p domain.ClonePayload) error {
+	r := domain.NewQuotaReservation(ids.NewID(), ticketID, eventID, p.Spec())
+
+	clusters, err := clusterHeadroom(ctx, sqlcTx, p.Cluster)
+	if err != nil {
+		return err
+	}
+	if err := requireCapacity(ctx, sqlcTx, ticketID, r, p.GPUs, clusters); err != nil {
+		return err
+	}
+
+	err = sqlcTx.CreateQuotaReservation(ctx, sqlc.CreateQuotaReservationParams{
+		ID:        r.ID,
+		ServiceID: r.ServiceID,
+		TicketID:  r.TicketID,
+		EventID:   r.EventID,
+		CPU:       r.CPU,
+		MemoryMB:  r.MemoryMB,
+		DiskGB:    r.DiskGB,
+		VMCount:   r.VMCount,
+		Status:    string(r.Status),
+	})
+	if err != nil {
+		return fmt.Errorf("reserve quota: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ComplianceUseCase records image advisories and reports non-compliant
+// VMs (domain/compliance.go). Platform admins only (route middleware).
+type ComplianceUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewComplianceUseCase creates a new use case instance.
+func NewComplianceUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *ComplianceUseCase {
+	return &ComplianceUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// RecordAdvisory flags an image. VMs it matches show up in the report at
+// once; nothing changes on the cluster.
+func (uc *ComplianceUseCase) RecordAdvisory(ctx context.Context, a domain.ImageAdvisory, actor string) (*domain.ImageAdvisory, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	a.ID = uc.ids.NewID()
+	a.CreatedBy = actor
+	a.CreatedAt = uc.clock.Now()
+	a.WithdrawnAt = nil
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := createAdvisory(ctx, sqlcTx, &a, uc.ids); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &a, nil
+}
+
+// WithdrawAdvisory stops an advisory from matching, e.g. one recorded in
+// error. The record is kept.
+func (uc *ComplianceUseCase) WithdrawAdvisory(ctx context.Context, advisoryID, actor string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	row, err := sqlcTx.GetImageAdvisoryForUpdate(ctx, advisoryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get advisory: %w", err)
+	}
+	if row.WithdrawnAt != nil {
+		return domain.ErrAdvisoryNotOpen
+	}
+
+	if err := withdrawAdvisory(ctx, sqlcTx, advisoryID, row.Image, actor, uc.clock, uc.ids); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ListAdvisories returns the open advisories, newest first.
+func (uc *ComplianceUseCase) ListAdvisories(ctx context.Context) ([]*domain.ImageAdvisory, error) {
+	rows, err := uc.sqlcQueries.ListOpenImageAdvisories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list advisories: %w", err)
+	}
+	advisories := make([]*domain.ImageAdvisory, 0, len(rows))
+	for _, r := range rows {
+		advisories = append(advisories, advisoryFromRow(r))
+	}
+	return advisories, nil
+}
+
+// Report lists the non-compliant VMs matching filter, most severe first.
+func (uc *ComplianceUseCase) Report(ctx context.Context, filter domain.ComplianceFilter) ([]*domain.VMCompliance, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	advisories, err := uc.ListAdvisories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// VMs not deleted whose template version is not active, or whose
+	// image_digest/image_source has an open advisory; optional service and
+	// template name filters
+	rows, err := uc.sqlcQueries.ListVMBuildsForCompliance(ctx, sqlc.ListVMBuildsForComplianceParams{
+		ServiceID:    filter.ServiceID,
+		TemplateName: filter.TemplateName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list vm builds: %w", err)
+	}
+
+	var report []*domain.VMCompliance
+	for _, r := range rows {
+		c := domain.NewVMCompliance(domain.VMBuild{
+			VMID:            r.VMID,
+			Name:            r.Name,
+			Namespace:       r.Namespace,
+			ServiceID:       r.ServiceID,
+			TemplateID:      r.TemplateID,
+			TemplateName:    r.TemplateName,
+			TemplateVersion: int(r.TemplateVersion),
+			TemplateStatus:  domain.TemplateStatus(r.TemplateStatus),
+			ImageSource:     r.ImageSource,
+			ImageDigest:     r.ImageDigest,
+			ProvisionedAt:   r.ProvisionedAt,
+		}, advisories)
+		if c != nil && filter.Includes(c) {
+			report = append(report, c)
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].MaxSeverity.Rank() > report[j].MaxSeverity.Rank()
+	})
+	return report, nil
+}
+
+// createAdvisory writes a (validated) advisory with its audit log in the
+// caller's TX. Shared with ImageScanUseCase.
+func createAdvisory(ctx context.Context, sqlcTx *sqlc.Queries, a *domain.ImageAdvisory, ids domain.IDGenerator) error {
+	if err := sqlcTx.CreateImageAdvisory(ctx, sqlc.CreateImageAdvisoryParams{
+		ID:        a.ID,
+		Image:     a.Image,
+		Kind:      string(a.Kind),
+		Severity:  string(a.Severity),
+		Reference: a.Reference,
+		Summary:   a.Summary,
+		CreatedBy: a.CreatedBy,
+		CreatedAt: a.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("create advisory: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditImageAdvisoryCreated,
+		ActorID:      a.CreatedBy,
+		ResourceType: "image",
+		ResourceID:   a.Image,
+		Details: map[string]interface{}{
+			"advisory_id": a.ID,
+			"kind":        a.Kind,
+			"severity":    a.Severity,
+			"reference":   a.Reference,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
+
+// withdrawAdvisory withdraws an open advisory with its audit log in the
+// caller's TX. Shared with ImageScanUseCase.
+func withdrawAdvisory(ctx context.Context, sqlcTx *sqlc.Queries, advisoryID, image, actor string, clock domain.Clock, ids domain.IDGenerator) error {
+	if err := sqlcTx.WithdrawImageAdvisory(ctx, sqlc.WithdrawImageAdvisoryParams{
+		ID:          advisoryID,
+		WithdrawnBy: actor,
+		WithdrawnAt: clock.Now(),
+	}); err != nil {
+		return fmt.Errorf("withdraw advisory: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditImageAdvisoryWithdrawn,
+		ActorID:      actor,
+		ResourceType: "image",
+		ResourceID:   image,
+		Details: map[string]interface{}{
+			"advisory_id": advisoryID,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
+
+func advisoryFromRow(row sqlc.ImageAdvisory) *domain.ImageAdvisory {
+	return &domain.ImageAdvisory{
+		ID:          row.ID,
+		Image:       row.Image,
+		Kind:        domain.AdvisoryKind(row.Kind),
+		Severity:    domain.AdvisorySeverity(row.Severity),
+		Reference:   row.Reference,
+		Summary:     row.Summary,
+		CreatedBy:   row.CreatedBy,
+		CreatedAt:   row.CreatedAt,
+		WithdrawnAt: row.WithdrawnAt,
+	}
+}
@@ -22,17 +22,31 @@
 //	                                         → Inserts River Job atomically
 //	                                         → Returns: APPROVED
 //
+//	Admin rejects a pending request       RejectTicket()
+//	                                         → Ticket REJECTED (by, reason)
+//	                                         → Event CANCELLED
+//	                                         → Returns: REJECTED
+//
+//	Requester withdraws a pending request CancelRequestUseCase.Execute()
+//	                                         → Ticket + Event CANCELLED
+//	                                         → REQUEST_CANCELLED recorded
+//	                                         → Later approvals: TICKET_NOT_PENDING
+//
 //	Operation auto-approved by policy     AutoApproveAndEnqueue()
 //	(e.g., CreateVM for privileged user)    → Creates Event + Ticket + Job
 //	                                         → All in single atomic TX
 //	                                         → Returns: PROCESSING
+//
+//	Caller does not know which applies    Submit()
+//	(e.g., the VM request API)              → Approval rules pick one of
+//	                                           the two methods above
 package usecase
 
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
@@ -50,9 +64,40 @@ import (
 // 3. Insert River Job via InsertTx
 // 4. Single atomic commit
 type CreateVMAtomicUseCase struct {
-	pool        *pgxpool.Pool
-	sqlcQueries *sqlc.Queries
-	riverClient *river.Client[pgx.Tx]
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	permissions  domain.PermissionChecker
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// ApproverResolver resolves eligible approvers for each stage of a new ticket.
+// Implemented by service.ApproverResolver.
+type ApproverResolver interface {
+	Resolve(ctx context.Context, ticketID, serviceID, requester string, stages []domain.ApprovalStage) ([]*domain.TicketApprover, error)
+}
+
+// ApprovalGuard enforces separation of duties before a ticket is approved
+// and resolves delegation. Implemented by service.ApprovalGuard.
+type ApprovalGuard interface {
+	Enforce(ctx context.Context, sqlcTx *sqlc.Queries, ticketID, serviceID, requesterID, approverID string) (domain.ActingApprover, error)
+}
+
+// EnvironmentPolicies evaluates the Service's environment policy for a request.
+// Implemented by service.EnvironmentPolicyService.
+type EnvironmentPolicies interface {
+	Decide(ctx context.Context, serviceID, namespace string, spec *domain.VMCreationPayload) (*domain.EnvironmentDecision, error)
+}
+
+// ApprovalRouter routes a request to auto-approval or the manual ticket path.
+// Implemented by service.ApprovalPolicyService.
+type ApprovalRouter interface {
+	Route(ctx context.Context, serviceID, requester string, req domain.ApprovalRequest, env *domain.EnvironmentDecision) (*domain.ApprovalRoute, error)
 }
 
 // NewCreateVMAtomicUseCase creates a new use case instance.
@@ -60,11 +105,25 @@ func NewCreateVMAtomicUseCase(
 	pool *pgxpool.Pool,
 	sqlcQueries *sqlc.Queries,
 	riverClient *river.Client[pgx.Tx],
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	permissions domain.PermissionChecker,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	clock domain.Clock,
+	ids domain.IDGenerator,
 ) *CreateVMAtomicUseCase {
 	return &CreateVMAtomicUseCase{
-		pool:        pool,
-		sqlcQueries: sqlcQueries,
-		riverClient: riverClient,
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		approvers:    approvers,
+		guard:        guard,
+		permissions:  permissions,
+		environments: environments,
+		router:       router,
+		clock:        clock,
+		ids:          ids,
 	}
 }
 
@@ -84,16 +143,53 @@ type CreateVMRequest struct {
 	TemplateID string // Required: template to use
 	Namespace  string // Required: target K8s namespace (immutable after submission)
 	// NOTE: ClusterID is NOT here - admin selects during approval (ADR-0017)
-	CPU         int    // Optional: override template default
-	MemoryMB    int    // Optional: override template default
-	Reason      string // Required: business reason for request
-	RequestedBy string // Required: user who submitted the request
+	CPU         int             // Optional: override template default
+	MemoryMB    int             // Optional: override template default
+	Reason      string          // Required: business reason for request
+	RequestedBy string          // Required: user who submitted the request
+	Priority    domain.Priority // Optional: normal (default), high, emergency; permission-gated
+
+	Lease *domain.LeaseTerms // Optional: expiry for temporary workloads (vm_lease.go)
+
+	ResubmittedFrom string // Set by ResubmitRequestUseCase: the rejected ticket revised
+
+	Replacement *domain.VMReplacement // Set by VMReplacementUseCase: recorded in the same TX
 }
 
 // CreateVMResult contains the VM creation result.
 type CreateVMResult struct {
 	EventID  string
 	TicketID string
+	Route    *domain.ApprovalRoute // Set by Submit
+}
+
+// Submit routes the request by the approval rules: auto-approved requests
+// are enqueued at once (PROCESSING), all others get a ticket
+// (PENDING_APPROVAL). Result.Route tells the caller which happened.
+func (uc *CreateVMAtomicUseCase) Submit(ctx context.Context, req CreateVMRequest) (*CreateVMResult, error) {
+	payload := newCreationPayload(req)
+	decision, err := uc.environments.Decide(ctx, req.ServiceID, req.Namespace, &payload)
+	if err != nil {
+		return nil, err
+	}
+	route, err := uc.route(ctx, req, decision)
+	if err != nil {
+		return nil, err
+	}
+
+	// AutoApproveAndEnqueue evaluates the rules again: it never trusts a
+	// route computed outside its own call
+	var result *CreateVMResult
+	if route.AutoApprove {
+		result, err = uc.AutoApproveAndEnqueue(ctx, req)
+	} else {
+		result, err = uc.Execute(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Route = route
+	return result, nil
 }
 
 // Execute performs the VM creation with atomic transaction.
@@ -104,21 +200,32 @@ type CreateVMResult struct {
 // - No orphan events possible (unlike eventual consistency model)
 func (uc *CreateVMAtomicUseCase) Execute(ctx context.Context, req CreateVMRequest) (*CreateVMResult, error) {
 	// Generate IDs
-	eventID := uuid.New().String()
-	ticketID := uuid.New().String()
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
 
 	// Create domain event payload
-	// NOTE (ADR-0015 §3): No SystemID - resolved via ServiceID
-	// NOTE (ADR-0015 §4): No Name - platform-generated after approval
-	// NOTE (ADR-0017): No ClusterID - admin selects during approval
-	payload := domain.VMCreationPayload{
-		ServiceID:  req.ServiceID,
-		TemplateID: req.TemplateID,
-		Namespace:  req.Namespace,
-		// ClusterID is NOT included - admin determines this during approval (ADR-0017)
-		CPU:      req.CPU,
-		MemoryMB: req.MemoryMB,
-		Reason:   req.Reason,
+	payload := newCreationPayload(req)
+
+	if err := uc.authorizePriority(req); err != nil {
+		return nil, err
+	}
+	if err := req.Lease.Validate(uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	// Environment policy: namespace class check + required approvals,
+	// frozen on the ticket so later policy edits do not affect it.
+	decision, err := uc.environments.Decide(ctx, req.ServiceID, req.Namespace, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve approvers before the TX (read-only, keeps the TX short).
+	// Per stage: owner/admin bindings on the Service and its System, or
+	// platform admins; requester excluded.
+	approvers, err := uc.approvers.Resolve(ctx, ticketID, req.ServiceID, req.RequestedBy, decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("resolve approvers: %w", err)
 	}
 
 	// ========== Atomic Transaction ==========
@@ -131,32 +238,60 @@ func (uc *CreateVMAtomicUseCase) Execute(ctx context.Context, req CreateVMReques
 	// Step 1: Write DomainEvent via sqlc (within tx)
 	sqlcTx := uc.sqlcQueries.WithTx(tx)
 	// AggregateID uses ServiceID since VM Name is generated after approval
-	err = sqlcTx.CreateDomainEvent(ctx, sqlc.CreateDomainEventParams{
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
 		EventID:       eventID,
 		EventType:     "VM_CREATION_REQUESTED",
+		SchemaVersion: domain.PayloadVersion(domain.EventVMCreationRequested),
 		AggregateType: "VM",
 		AggregateID:   req.ServiceID + "-" + eventID[:8], // Temporary ID, actual VM name assigned later
-		Payload:       payload.ToJSON(),
 		Status:        "PENDING",
 		CreatedBy:     req.RequestedBy,
-	})
+	}, payload)
 	if err != nil {
 		return nil, fmt.Errorf("create domain event: %w", err)
 	}
 
 	// Step 2: Create ApprovalTicket (within same tx)
 	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
-		TicketID:      ticketID,
-		EventID:       eventID,
-		RequestType:   "CREATE_VM",
-		RequestReason: req.Reason,
-		Status:        "PENDING_APPROVAL",
-		CreatedBy:     req.RequestedBy,
+		TicketID:          ticketID,
+		EventID:           eventID,
+		ServiceID:         req.ServiceID, // Immutable after submission (ADR-0017)
+		RequestType:       "CREATE_VM",
+		RequestReason:     req.Reason,
+		Status:            "PENDING_APPROVAL",
+		Priority:          string(req.Priority),
+		PriorityRank:      int16(req.Priority.Rank()), // Inbox order (Phase 4 §10.4)
+		Environment:       string(decision.Environment),
+		RequiredApprovals: int32(decision.RequiredApprovals),
+		Stages:            decision.Stages, // JSONB (sqlc type override)
+		SLADueAt:          domain.SLADueAt(uc.clock.Now(), decision.ApprovalSLA, req.Priority),
+		ResubmittedFrom:   req.ResubmittedFrom,
+		CreatedBy:         req.RequestedBy,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create approval ticket: %w", err)
 	}
 
+	// Step 2b: Assign approvers (within same tx, frozen at submission)
+	for _, a := range approvers {
+		err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+			TicketID: ticketID,
+			UserID:   a.UserID,
+			Source:   string(a.Source),
+			Stage:    int32(a.Stage),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("assign approver: %w", err)
+		}
+	}
+
+	// Step 2c: Blue/green replacement this VM is created for
+	if req.Replacement != nil {
+		if err := insertReplacement(ctx, tx, sqlcTx, uc.riverClient, uc.ids, req.Replacement, eventID, ticketID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Step 3: River Job insertion strategy (ADR-0006 + ADR-0012)
 	//
 	// IMPORTANT: This flow demonstrates the "Approval Required" path:
@@ -180,30 +315,109 @@ func (uc *CreateVMAtomicUseCase) Execute(ctx context.Context, req CreateVMReques
 }
 
 // ApproveAndEnqueue is called after admin approval.
-// Inserts the River job to trigger actual VM creation.
-func (uc *CreateVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID string, modifiedSpec *domain.ModifiedSpec) error {
+// Inserts the River job to trigger actual VM creation once the ticket has
+// its required number of distinct approvals (environment policy).
+// approverID must pass separation-of-duties checks (see ApprovalGuard).
+// executeAt, when set, plans execution for later (domain/execution_schedule.go).
+func (uc *CreateVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
 	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	result, err := uc.approveTx(ctx, tx, ticketID, approverID, modifiedSpec, executeAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Atomic commit
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return result, nil
+}
+
+// approveTx records one approval inside the caller's transaction and, on
+// the last required approval, reserves quota and inserts the River job.
+// Shared with BatchCreateVMUseCase, which approves every child in one TX.
+func (uc *CreateVMAtomicUseCase) approveTx(ctx context.Context, tx pgx.Tx, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
 	sqlcTx := uc.sqlcQueries.WithTx(tx)
 
-	// Get ticket and event
-	ticket, err := sqlcTx.GetApprovalTicket(ctx, ticketID)
+	// Get ticket (FOR UPDATE: concurrent approvals must count each other)
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	// Separation of duties: returns *domain.SoDViolation (audited) on rejection
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A picked InstanceSize becomes cpu/memory_mb now: later size edits do not change this approval
+	size, err := applyApprovalInstanceSize(ctx, sqlcTx, modifiedSpec)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireSizePlacement(ctx, sqlcTx, size, modifiedSpec, ticket.Environment); err != nil {
+		return nil, err
+	}
+
+	// Earlier approvals of this stage were given for the unmodified spec: start the stage over
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, modifiedSpec != nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+
+	specJSON, err := modifiedSpec.ToJSON()
 	if err != nil {
-		return fmt.Errorf("get ticket: %w", err)
+		return nil, err
+	}
+
+	if !result.Approved {
+		// Not final: keep PENDING_APPROVAL, store modifications for the next approver
+		if modifiedSpec != nil {
+			err = sqlcTx.UpdateApprovalTicketModifiedSpec(ctx, sqlc.UpdateApprovalTicketModifiedSpecParams{
+				TicketID:     ticketID,
+				ModifiedSpec: specJSON,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("update modified spec: %w", err)
+			}
+		}
+		return result, nil
+	}
+
+	// Final approver without own changes accepts earlier approvers' modifications
+	if specJSON == nil {
+		specJSON = ticket.ModifiedSpec
 	}
 
 	// Update ticket status
 	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
-		TicketID:     ticketID,
-		Status:       "APPROVED",
-		ModifiedSpec: modifiedSpec.ToJSON(),
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+		ModifiedSpec:       specJSON,
 	})
 	if err != nil {
-		return fmt.Errorf("update ticket: %w", err)
+		return nil, fmt.Errorf("update ticket: %w", err)
 	}
 
 	// Update event status
@@ -212,20 +426,56 @@ func (uc *CreateVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID
 		Status:  "PROCESSING",
 	})
 	if err != nil {
-		return fmt.Errorf("update event: %w", err)
+		return nil, fmt.Errorf("update event: %w", err)
 	}
 
-	// Insert River Job (atomic with above updates)
-	_, err = uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: ticket.EventID}, nil)
+	// Reserve Service quota for the effective spec (admin modifications applied).
+	// Released by the event worker on final failure, or by the quota sweep.
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
 	if err != nil {
-		return fmt.Errorf("insert river job: %w", err)
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	spec, err := effectiveEventPayload[domain.VMCreationPayload](event, specJSON)
+	if err != nil {
+		return nil, err
+	}
+	// Still within quota and placeable? Otherwise the ticket stays pending
+	// and the approver sees the shortfall (domain/approval_precondition.go).
+	if err := reserveCreation(ctx, sqlcTx, uc.ids, ticketID, ticket.EventID, &spec, ticket.Environment); err != nil {
+		return nil, err
+	}
+
+	// Insert River Job (atomic with above updates), on the priority's queue,
+	// scheduled for the planned execution time if any
+	err = insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, ticket.EventID,
+		domain.Priority(ticket.Priority).Queue(), result.ExecuteAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RejectTicket rejects a pending ticket with a required reason (1-500
+// characters). The ticket becomes REJECTED and its event CANCELLED in one
+// transaction; rejectedBy and the reason are kept on the ticket and in the
+// audit log. No River job exists before approval, so nothing is dequeued.
+//
+// Works for tickets of any request type (see rejectTicket).
+func (uc *CreateVMAtomicUseCase) RejectTicket(ctx context.Context, ticketID, rejectedBy, reason string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := rejectTicket(ctx, uc.sqlcQueries.WithTx(tx), uc.ids, uc.guard, ticketID, rejectedBy, reason); err != nil {
+		return err
 	}
 
-	// Atomic commit
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
-
 	return nil
 }
 
@@ -236,19 +486,30 @@ func (uc *CreateVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID
 // - Event + Ticket + River Job are ALL created in a SINGLE atomic transaction
 // - This achieves true ACID atomicity as promised by ADR-0012
 func (uc *CreateVMAtomicUseCase) AutoApproveAndEnqueue(ctx context.Context, req CreateVMRequest) (*CreateVMResult, error) {
-	eventID := uuid.New().String()
-	ticketID := uuid.New().String()
+	if err := uc.authorizePriority(req); err != nil {
+		return nil, err
+	}
+	if err := req.Lease.Validate(uc.clock.Now()); err != nil {
+		return nil, err
+	}
 
-	// NOTE (ADR-0015 §3, §4): No SystemID, no Name in payload
-	// NOTE (ADR-0017): No ClusterID - admin selects during approval
-	payload := domain.VMCreationPayload{
-		ServiceID:  req.ServiceID,
-		TemplateID: req.TemplateID,
-		Namespace:  req.Namespace,
-		// ClusterID is NOT included - admin determines this during approval (ADR-0017)
-		CPU:      req.CPU,
-		MemoryMB: req.MemoryMB,
-		Reason:   req.Reason,
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	payload := newCreationPayload(req)
+
+	// Only requests the approval rules allow, within the environment
+	// policy (e.g. small dev VMs, never prod)
+	decision, err := uc.environments.Decide(ctx, req.ServiceID, req.Namespace, &payload)
+	if err != nil {
+		return nil, err
+	}
+	route, err := uc.route(ctx, req, decision)
+	if err != nil {
+		return nil, err
+	}
+	if !route.AutoApprove {
+		return nil, fmt.Errorf("%s: %w", route.Reason, domain.ErrApprovalRequired)
 	}
 
 	// ========== Single Atomic Transaction (ADR-0012 True ACID) ==========
@@ -262,34 +523,54 @@ func (uc *CreateVMAtomicUseCase) AutoApproveAndEnqueue(ctx context.Context, req
 
 	// Step 1: Create DomainEvent (status = PROCESSING for auto-approve)
 	// AggregateID uses ServiceID since VM Name is generated after approval
-	err = sqlcTx.CreateDomainEvent(ctx, sqlc.CreateDomainEventParams{
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
 		EventID:       eventID,
 		EventType:     "VM_CREATION_REQUESTED",
+		SchemaVersion: domain.PayloadVersion(domain.EventVMCreationRequested),
 		AggregateType: "VM",
 		AggregateID:   req.ServiceID + "-" + eventID[:8], // Temporary ID, actual VM name assigned later
-		Payload:       payload.ToJSON(),
-		Status:        "PROCESSING", // Skip PENDING for auto-approve
+		Status:        "PROCESSING",                      // Skip PENDING for auto-approve
 		CreatedBy:     req.RequestedBy,
-	})
+	}, payload)
 	if err != nil {
 		return nil, fmt.Errorf("create domain event: %w", err)
 	}
 
 	// Step 2: Create ApprovalTicket (status = APPROVED for auto-approve)
 	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
-		TicketID:      ticketID,
-		EventID:       eventID,
-		RequestType:   "CREATE_VM",
-		RequestReason: req.Reason,
-		Status:        "APPROVED", // Auto-approved
-		CreatedBy:     req.RequestedBy,
+		TicketID:          ticketID,
+		EventID:           eventID,
+		ServiceID:         req.ServiceID, // Immutable after submission (ADR-0017)
+		RequestType:       "CREATE_VM",
+		RequestReason:     req.Reason,
+		Status:            "APPROVED", // Auto-approved
+		Priority:          string(req.Priority),
+		PriorityRank:      int16(req.Priority.Rank()),
+		Environment:       string(decision.Environment),
+		RequiredApprovals: 0,
+		ResubmittedFrom:   req.ResubmittedFrom,
+		CreatedBy:         req.RequestedBy,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create approval ticket: %w", err)
 	}
 
+	if req.Replacement != nil {
+		if err := insertReplacement(ctx, tx, sqlcTx, uc.riverClient, uc.ids, req.Replacement, eventID, ticketID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 2b: Check quota and headroom and reserve, as the final approval
+	// does; a shortfall fails the submission (409 APPROVAL_PRECONDITION_FAILED)
+	if err := reserveCreation(ctx, sqlcTx, uc.ids, ticketID, eventID, &payload, string(decision.Environment)); err != nil {
+		return nil, err
+	}
+
 	// Step 3: Insert River Job (same transaction - ADR-0012 core pattern)
-	_, err = uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: eventID}, nil)
+	_, err = uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: eventID}, &river.InsertOpts{
+		Queue: req.Priority.Queue(),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("insert river job: %w", err)
 	}
@@ -304,3 +585,87 @@ func (uc *CreateVMAtomicUseCase) AutoApproveAndEnqueue(ctx context.Context, req
 		TicketID: ticketID,
 	}, nil
 }
+
+// reserveCreation holds spec, the approved spec of a new VM, against the
+// Service's quota, after checking the quota and the headroom of the
+// clusters it may land on in environment (requireCapacity). An
+// approver-selected cluster must fit on its own (ADR-0017). Both approval
+// paths call it: the final approval (approveTx) and auto-approval
+// (AutoApproveAndEnqueue). Released by the event worker on final failure,
+// or by the quota sweep.
+func reserveCreation(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, ticketID, eventID string, spec *domain.VMCreationPayload, environment string) error {
+	r := domain.NewQuotaReservation(ids.NewID(), ticketID, eventID, spec)
+
+	clusters, err := approvalClusters(ctx, sqlcTx, spec, environment)
+	if err != nil {
+		return err
+	}
+	if err := requireCapacity(ctx, sqlcTx, ticketID, r, spec.GPUs, clusters); err != nil {
+		return err
+	}
+
+	err = sqlcTx.CreateQuotaReservation(ctx, sqlc.CreateQuotaReservationParams{
+		ID:        r.ID,
+		ServiceID: r.ServiceID,
+		TicketID:  r.TicketID,
+		EventID:   r.EventID,
+		CPU:       r.CPU,
+		MemoryMB:  r.MemoryMB,
+		DiskGB:    r.DiskGB,
+		VMCount:   r.VMCount,
+		Status:    string(r.Status),
+	})
+	if err != nil {
+		return fmt.Errorf("reserve quota: %w", err)
+	}
+	return nil
+}
+
+// route applies the approval rules on top of the environment decision.
+func (uc *CreateVMAtomicUseCase) route(ctx context.Context, req CreateVMRequest, decision *domain.EnvironmentDecision) (*domain.ApprovalRoute, error) {
+	route, err := uc.router.Route(ctx, req.ServiceID, req.RequestedBy, domain.ApprovalRequest{
+		RequestType: "CREATE_VM",
+		Namespace:   req.Namespace,
+		CPU:         req.CPU,
+		MemoryMB:    req.MemoryMB,
+	}, decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+	return route, nil
+}
+
+// newCreationPayload builds the event payload of a request, the same for
+// the ticket and the auto-approval path.
+//
+// NOTE (ADR-0015 §3): No SystemID - resolved via ServiceID
+// NOTE (ADR-0015 §4): No Name - platform-generated after approval
+// NOTE (ADR-0017): No ClusterID - admin selects during approval
+func newCreationPayload(req CreateVMRequest) domain.VMCreationPayload {
+	return domain.VMCreationPayload{
+		ServiceID:  req.ServiceID,
+		TemplateID: req.TemplateID,
+		Namespace:  req.Namespace,
+		CPU:        req.CPU,
+		MemoryMB:   req.MemoryMB,
+		Reason:     req.Reason,
+		Lease:      req.Lease,
+	}
+}
+
+// authorizePriority checks the requester may use req.Priority on the Service.
+// Normal needs no extra permission; an empty priority is treated as normal.
+func (uc *CreateVMAtomicUseCase) authorizePriority(req CreateVMRequest) error {
+	perm := req.Priority.Permission()
+	if perm == "" {
+		return nil
+	}
+	p, err := uc.permissions.CheckPermission(req.RequestedBy, perm, string(domain.ResourceTypeService), req.ServiceID)
+	if err != nil {
+		return fmt.Errorf("check priority permission: %w", err)
+	}
+	if !p.Allowed {
+		return fmt.Errorf("%s: %w", req.Priority, domain.ErrPriorityForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,466 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// DecommissionClusterUseCase guides an admin through retiring a cluster.
+//
+// Steps (each is a separate admin action so the plan can be reviewed):
+//
+//	Start()   → freeze placements, record decommission       (atomic TX)
+//	Plan()    → list managed VMs, pick target clusters       (K8s read, then TX)
+//	Execute() → one relocation event, row + River job per VM (atomic TX)
+//	Archive() → verify empty, archive cluster record         (K8s read, then TX)
+//	Cancel()  → unfreeze placements (before Execute only)    (atomic TX)
+//
+// Every step writes an audit log in the same transaction as the state change.
+type DecommissionClusterUseCase struct {
+	pool          *pgxpool.Pool
+	sqlcQueries   *sqlc.Queries
+	riverClient   *river.Client[pgx.Tx]
+	vms           provider.InfrastructureProvider
+	clusterRepo   repository.ClusterRepository
+	namespaceRepo repository.NamespaceRegistryRepository
+	clock         domain.Clock
+	ids           domain.IDGenerator
+}
+
+// NewDecommissionClusterUseCase creates a new use case instance.
+func NewDecommissionClusterUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vms provider.InfrastructureProvider,
+	clusterRepo repository.ClusterRepository,
+	namespaceRepo repository.NamespaceRegistryRepository,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *DecommissionClusterUseCase {
+	return &DecommissionClusterUseCase{
+		pool:          pool,
+		sqlcQueries:   sqlcQueries,
+		riverClient:   riverClient,
+		vms:           vms,
+		clusterRepo:   clusterRepo,
+		namespaceRepo: namespaceRepo,
+		clock:         clock,
+		ids:           ids,
+	}
+}
+
+// StartDecommissionRequest contains the request to start a decommission.
+type StartDecommissionRequest struct {
+	ClusterID   string // Required
+	Reason      string // Required
+	RequestedBy string // Required: platform admin
+}
+
+// Start freezes new placements on the cluster and records the decommission.
+func (uc *DecommissionClusterUseCase) Start(ctx context.Context, req StartDecommissionRequest) (*domain.ClusterDecommission, error) {
+	cluster, err := uc.clusterRepo.Get(ctx, req.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("get cluster: %w", err)
+	}
+	if cluster.Lifecycle != domain.ClusterLifecycleActive {
+		return nil, domain.ErrDecommissionInvalidState
+	}
+
+	d := &domain.ClusterDecommission{
+		ID:          uc.ids.NewID(),
+		EventID:     uc.ids.NewID(),
+		ClusterID:   cluster.ID,
+		Status:      domain.DecommissionPlanning,
+		Reason:      req.Reason,
+		RequestedBy: req.RequestedBy,
+		CreatedAt:   uc.clock.Now(),
+	}
+
+	err = uc.inTx(ctx, func(tx pgx.Tx, sqlcTx *sqlc.Queries) error {
+		// Freeze: DECOMMISSIONING clusters are excluded from FilterPlacementCandidates.
+		// Conditional update guards against a concurrent Start on the same cluster.
+		if err := sqlcTx.UpdateClusterLifecycle(ctx, sqlc.UpdateClusterLifecycleParams{
+			ID:           cluster.ID,
+			Lifecycle:    string(domain.ClusterLifecycleDecommissioning),
+			ExpectedFrom: string(domain.ClusterLifecycleActive),
+		}); err != nil {
+			return fmt.Errorf("freeze cluster: %w", err)
+		}
+
+		if err := createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+			EventID:       d.EventID,
+			EventType:     string(domain.EventClusterDecommissionRequested),
+			SchemaVersion: domain.PayloadVersion(domain.EventClusterDecommissionRequested),
+			AggregateType: "Cluster",
+			AggregateID:   cluster.ID,
+			Status:        string(domain.EventStatusProcessing),
+			CreatedBy:     req.RequestedBy,
+		}, domain.ClusterDecommissionPayload{
+			DecommissionID: d.ID,
+			ClusterID:      cluster.ID,
+			ClusterName:    cluster.Name,
+			Reason:         req.Reason,
+		}); err != nil {
+			return fmt.Errorf("create domain event: %w", err)
+		}
+
+		if err := sqlcTx.CreateClusterDecommission(ctx, sqlc.CreateClusterDecommissionParams{
+			ID:          d.ID,
+			EventID:     d.EventID,
+			ClusterID:   d.ClusterID,
+			Status:      string(d.Status),
+			Reason:      d.Reason,
+			RequestedBy: d.RequestedBy,
+		}); err != nil {
+			return fmt.Errorf("create decommission: %w", err)
+		}
+
+		return uc.audit(ctx, sqlcTx, domain.AuditClusterDecommissionStarted, req.RequestedBy, cluster, map[string]interface{}{
+			"decommission_id": d.ID,
+			"reason":          req.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Plan lists managed VMs on the cluster and assigns target clusters.
+// May be called repeatedly while PLANNING/PLANNED (e.g., after registering namespaces).
+func (uc *DecommissionClusterUseCase) Plan(ctx context.Context, decommissionID, actor string) (*domain.ClusterDecommission, error) {
+	d, err := uc.clusterRepo.GetDecommission(ctx, decommissionID)
+	if err != nil {
+		return nil, fmt.Errorf("get decommission: %w", err)
+	}
+	if !d.CanCancel() { // PLANNING or PLANNED
+		return nil, domain.ErrDecommissionInvalidState
+	}
+
+	source, err := uc.clusterRepo.Get(ctx, d.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("get cluster: %w", err)
+	}
+
+	// ========== Reads (K8s OUTSIDE transaction, ADR-0012) ==========
+	vms, err := uc.listManagedVMs(ctx, source.Name)
+	if err != nil {
+		return nil, fmt.Errorf("list managed vms: %w", err)
+	}
+
+	clusters, err := uc.clusterRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	candidates := domain.FilterPlacementCandidates(clusters, source.Environment)
+
+	namespaces := make(map[string][]string)
+	for _, vm := range vms {
+		if _, ok := namespaces[vm.Namespace]; ok {
+			continue
+		}
+		ids, err := uc.namespaceRepo.ClusterIDsForNamespace(ctx, vm.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("resolve namespace %s: %w", vm.Namespace, err)
+		}
+		namespaces[vm.Namespace] = ids
+	}
+
+	d.Plan = domain.PlanRelocations(source, vms, candidates, namespaces)
+	d.Status = domain.DecommissionPlanned
+
+	err = uc.inTx(ctx, func(tx pgx.Tx, sqlcTx *sqlc.Queries) error {
+		if err := sqlcTx.UpdateDecommissionPlan(ctx, sqlc.UpdateDecommissionPlanParams{
+			ID:     d.ID,
+			Plan:   d.Plan, // JSONB (sqlc type override)
+			Status: string(d.Status),
+		}); err != nil {
+			return fmt.Errorf("save plan: %w", err)
+		}
+		return uc.audit(ctx, sqlcTx, domain.AuditClusterDecommissionPlanned, actor, source, map[string]interface{}{
+			"decommission_id": d.ID,
+			"vm_count":        len(d.Plan),
+			"unplaceable":     domain.UnplaceableCount(d.Plan),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ExecuteDecommissionRequest contains the request to execute a reviewed plan.
+type ExecuteDecommissionRequest struct {
+	DecommissionID string
+	// Overrides maps "namespace/vm" to an admin-chosen target cluster.
+	Overrides map[string]string
+	Actor     string
+}
+
+// Execute enqueues one relocation per VM as a batch (ADR-0015 §19).
+// All child events and jobs are created atomically; each executes independently.
+func (uc *DecommissionClusterUseCase) Execute(ctx context.Context, req ExecuteDecommissionRequest) error {
+	d, err := uc.clusterRepo.GetDecommission(ctx, req.DecommissionID)
+	if err != nil {
+		return fmt.Errorf("get decommission: %w", err)
+	}
+	if d.Status != domain.DecommissionPlanned {
+		return domain.ErrDecommissionInvalidState
+	}
+
+	source, err := uc.clusterRepo.Get(ctx, d.ClusterID)
+	if err != nil {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	clusters, err := uc.clusterRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list clusters: %w", err)
+	}
+	eligible := make(map[string]bool)
+	for _, c := range domain.FilterPlacementCandidates(clusters, source.Environment) {
+		eligible[c.Name] = true
+	}
+
+	for _, item := range d.Plan {
+		if target, ok := req.Overrides[item.Namespace+"/"+item.VMName]; ok {
+			// Overrides obey the same freeze/environment rules as the planner
+			if !eligible[target] {
+				return domain.ErrDecommissionInvalidTarget
+			}
+			item.TargetCluster = target
+			item.Unplaceable = ""
+		}
+	}
+	if domain.UnplaceableCount(d.Plan) > 0 {
+		return domain.ErrDecommissionUnplaceableVMs
+	}
+
+	return uc.inTx(ctx, func(tx pgx.Tx, sqlcTx *sqlc.Queries) error {
+		now := uc.clock.Now()
+		for _, item := range d.Plan {
+			item.EventID = uc.ids.NewID()
+
+			if err := createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+				EventID:       item.EventID,
+				EventType:     string(domain.EventVMRelocationRequested),
+				SchemaVersion: domain.PayloadVersion(domain.EventVMRelocationRequested),
+				AggregateType: "VM",
+				AggregateID:   item.VMID,
+				Status:        string(domain.EventStatusProcessing),
+				CreatedBy:     req.Actor,
+			}, domain.VMRelocationPayload{
+				DecommissionID: d.ID,
+				VMID:           item.VMID,
+				VMName:         item.VMName,
+				Namespace:      item.Namespace,
+				ServiceID:      item.ServiceID,
+				SourceCluster:  item.SourceCluster,
+				TargetCluster:  item.TargetCluster,
+			}); err != nil {
+				return fmt.Errorf("create relocation event %s: %w", item.VMName, err)
+			}
+
+			// Run by VMRelocationHandler like a standalone relocation, without the cutover hold
+			if err := sqlcTx.CreateVMRelocation(ctx, sqlc.CreateVMRelocationParams{
+				ID:             item.EventID,
+				DecommissionID: d.ID,
+				VMID:           item.VMID,
+				VMName:         item.VMName,
+				Namespace:      item.Namespace,
+				ServiceID:      item.ServiceID,
+				SourceCluster:  item.SourceCluster,
+				TargetCluster:  item.TargetCluster,
+				Status:         string(domain.RelocationPending),
+				Reason:         d.Reason,
+				RequestedBy:    req.Actor,
+				CreatedAt:      now,
+			}); err != nil {
+				return fmt.Errorf("create vm relocation %s: %w", item.VMName, err)
+			}
+
+			if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: item.EventID}, nil); err != nil {
+				return fmt.Errorf("insert river job %s: %w", item.VMName, err)
+			}
+		}
+
+		if err := sqlcTx.StartDecommissionExecution(ctx, sqlc.StartDecommissionExecutionParams{
+			ID:           d.ID,
+			Plan:         d.Plan, // Now carries EventIDs
+			Status:       string(domain.DecommissionExecuting),
+			TotalCount:   len(d.Plan),
+			PendingCount: len(d.Plan),
+		}); err != nil {
+			return fmt.Errorf("start execution: %w", err)
+		}
+
+		return uc.audit(ctx, sqlcTx, domain.AuditClusterDecommissionExecuted, req.Actor, source, map[string]interface{}{
+			"decommission_id": d.ID,
+			"vm_count":        len(d.Plan),
+			"overrides":       req.Overrides,
+		})
+	})
+}
+
+// Archive verifies the cluster is empty and archives the cluster record.
+// Requires READY_TO_ARCHIVE (all relocations finished; set by the relocation handler).
+func (uc *DecommissionClusterUseCase) Archive(ctx context.Context, decommissionID, actor string) error {
+	d, err := uc.clusterRepo.GetDecommission(ctx, decommissionID)
+	if err != nil {
+		return fmt.Errorf("get decommission: %w", err)
+	}
+	if d.Status != domain.DecommissionReadyToArchive {
+		return domain.ErrDecommissionInvalidState
+	}
+
+	source, err := uc.clusterRepo.Get(ctx, d.ClusterID)
+	if err != nil {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	// Final safety check against the live cluster (OUTSIDE transaction)
+	remaining, err := uc.listManagedVMs(ctx, source.Name)
+	if err != nil {
+		return fmt.Errorf("list managed vms: %w", err)
+	}
+	if len(remaining) > 0 {
+		return domain.ErrClusterNotEmpty
+	}
+
+	return uc.inTx(ctx, func(tx pgx.Tx, sqlcTx *sqlc.Queries) error {
+		// Archive, not delete: VM history and audit logs reference the cluster
+		if err := sqlcTx.ArchiveCluster(ctx, sqlc.ArchiveClusterParams{
+			ID:        source.ID,
+			Lifecycle: string(domain.ClusterLifecycleDecommissioned),
+		}); err != nil {
+			return fmt.Errorf("archive cluster: %w", err)
+		}
+		// Credentials are no longer needed once archived
+		if err := sqlcTx.DeleteClusterCredentials(ctx, source.ID); err != nil {
+			return fmt.Errorf("delete cluster credentials: %w", err)
+		}
+		if err := sqlcTx.CompleteDecommission(ctx, sqlc.CompleteDecommissionParams{
+			ID:     d.ID,
+			Status: string(domain.DecommissionCompleted),
+		}); err != nil {
+			return fmt.Errorf("complete decommission: %w", err)
+		}
+		if err := sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+			EventID: d.EventID,
+			Status:  string(domain.EventStatusCompleted),
+		}); err != nil {
+			return fmt.Errorf("update event: %w", err)
+		}
+		return uc.audit(ctx, sqlcTx, domain.AuditClusterArchived, actor, source, map[string]interface{}{
+			"decommission_id": d.ID,
+			"relocated":       d.SuccessCount,
+			"failed":          d.FailedCount,
+		})
+	})
+}
+
+// Cancel abandons a decommission before execution and unfreezes placements.
+func (uc *DecommissionClusterUseCase) Cancel(ctx context.Context, decommissionID, actor, reason string) error {
+	d, err := uc.clusterRepo.GetDecommission(ctx, decommissionID)
+	if err != nil {
+		return fmt.Errorf("get decommission: %w", err)
+	}
+	if !d.CanCancel() {
+		return domain.ErrDecommissionInvalidState
+	}
+
+	source, err := uc.clusterRepo.Get(ctx, d.ClusterID)
+	if err != nil {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	return uc.inTx(ctx, func(tx pgx.Tx, sqlcTx *sqlc.Queries) error {
+		if err := sqlcTx.UpdateClusterLifecycle(ctx, sqlc.UpdateClusterLifecycleParams{
+			ID:           source.ID,
+			Lifecycle:    string(domain.ClusterLifecycleActive),
+			ExpectedFrom: string(domain.ClusterLifecycleDecommissioning),
+		}); err != nil {
+			return fmt.Errorf("unfreeze cluster: %w", err)
+		}
+		if err := sqlcTx.CompleteDecommission(ctx, sqlc.CompleteDecommissionParams{
+			ID:     d.ID,
+			Status: string(domain.DecommissionCancelled),
+		}); err != nil {
+			return fmt.Errorf("cancel decommission: %w", err)
+		}
+		if err := sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+			EventID: d.EventID,
+			Status:  string(domain.EventStatusCancelled),
+		}); err != nil {
+			return fmt.Errorf("update event: %w", err)
+		}
+		return uc.audit(ctx, sqlcTx, domain.AuditClusterDecommissionCancelled, actor, source, map[string]interface{}{
+			"decommission_id": d.ID,
+			"reason":          reason,
+		})
+	})
+}
+
+// listManagedVMs pages through all platform-owned VMs on a cluster.
+func (uc *DecommissionClusterUseCase) listManagedVMs(ctx context.Context, cluster string) ([]*domain.VM, error) {
+	opts := provider.ListOptions{LabelSelector: domain.ManagedSelector(), Limit: 100}
+
+	var all []*domain.VM
+	for {
+		page, err := uc.vms.ListVMs(ctx, cluster, "", opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		if page.Continue == "" {
+			return all, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// inTx runs fn inside a single pgx transaction shared by sqlc and River.
+func (uc *DecommissionClusterUseCase) inTx(ctx context.Context, fn func(tx pgx.Tx, sqlcTx *sqlc.Queries) error) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx, uc.sqlcQueries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// audit appends an audit record inside the caller's transaction.
+func (uc *DecommissionClusterUseCase) audit(ctx context.Context, sqlcTx *sqlc.Queries, action, actor string, cluster *domain.Cluster, details map[string]interface{}) error {
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      actor,
+		ResourceType: "cluster",
+		ResourceID:   cluster.ID,
+		ResourceName: cluster.Name,
+		Environment:  cluster.Environment,
+		Details:      details, // No sensitive fields (ADR-0019)
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// DelegationUseCase creates and revokes approval delegations
+// (domain/delegation.go). Self-service: approvers manage their own
+// delegations; every change is audited in the same TX.
+//
+// ApprovalGuard reads delegations at decision time, so a revocation takes
+// effect on the next approval; approvals already given stand.
+type DelegationUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewDelegationUseCase creates a new use case instance.
+func NewDelegationUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *DelegationUseCase {
+	return &DelegationUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// CreateDelegationRequest contains the request to delegate approvals.
+type CreateDelegationRequest struct {
+	DelegatorID string    // Required: the current user
+	DelegateID  string    // Required
+	StartsAt    time.Time // Optional: defaults to now
+	EndsAt      time.Time // Required
+	Reason      string    // Required
+}
+
+// Create records a delegation.
+func (uc *DelegationUseCase) Create(ctx context.Context, req CreateDelegationRequest) (*domain.Delegation, error) {
+	now := uc.clock.Now()
+	d := &domain.Delegation{
+		ID:          uc.ids.NewID(),
+		DelegatorID: req.DelegatorID,
+		DelegateID:  req.DelegateID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+		CreatedAt:   now,
+	}
+	if d.StartsAt.IsZero() {
+		d.StartsAt = now
+	}
+	if err := d.Validate(now); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	err = sqlcTx.CreateDelegation(ctx, sqlc.CreateDelegationParams{
+		ID:          d.ID,
+		DelegatorID: d.DelegatorID,
+		DelegateID:  d.DelegateID,
+		StartsAt:    d.StartsAt,
+		EndsAt:      d.EndsAt,
+		Reason:      d.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create delegation: %w", err)
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditDelegationCreated, d.DelegatorID, d.ID, map[string]interface{}{
+		"delegate_id": d.DelegateID,
+		"starts_at":   d.StartsAt,
+		"ends_at":     d.EndsAt,
+		"reason":      d.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return d, nil
+}
+
+// Revoke ends a delegation early. Only the delegator may revoke it.
+func (uc *DelegationUseCase) Revoke(ctx context.Context, delegationID, userID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	row, err := sqlcTx.GetDelegationForUpdate(ctx, delegationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get delegation: %w", err)
+	}
+	if row.DelegatorID != userID {
+		return domain.ErrNotDelegator
+	}
+	if row.RevokedAt != nil {
+		return nil // Already revoked
+	}
+
+	if err := sqlcTx.RevokeDelegation(ctx, delegationID); err != nil {
+		return fmt.Errorf("revoke delegation: %w", err)
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditDelegationRevoked, userID, delegationID, map[string]interface{}{
+		"delegate_id": row.DelegateID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// audit appends an audit record inside the caller's transaction.
+func (uc *DelegationUseCase) audit(ctx context.Context, sqlcTx *sqlc.Queries, action, actor, delegationID string, details map[string]interface{}) error {
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      actor,
+		ResourceType: "delegation",
+		ResourceID:   delegationID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// DeleteVMAtomicUseCase handles VM deletion with the same atomic
+// transaction pattern as CreateVMAtomicUseCase (ADR-0012):
+//
+//	Execute()               → Event + Ticket, no River Job    → PENDING_APPROVAL
+//	ApproveAndEnqueue()     → Ticket APPROVED, VM DELETING,
+//	                          River Job                       → APPROVED
+//	AutoApproveAndEnqueue() → Event + Ticket + Job in one TX  → PROCESSING
+//
+// A request is accepted only if the VM has no operation in flight. The
+// check runs under the VM row lock, so two concurrent deletions (or a
+// deletion racing a modification request) cannot both be accepted.
+type DeleteVMAtomicUseCase struct {
+	pool          *pgxpool.Pool
+	sqlcQueries   *sqlc.Queries
+	riverClient   *river.Client[pgx.Tx]
+	vmRepo        repository.VMRepository
+	namespaceRepo repository.NamespaceRepository
+	approvers     ApproverResolver
+	guard         ApprovalGuard
+	environments  EnvironmentPolicies
+	clock         domain.Clock
+	ids           domain.IDGenerator
+}
+
+// NewDeleteVMAtomicUseCase creates a new use case instance.
+func NewDeleteVMAtomicUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	namespaceRepo repository.NamespaceRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *DeleteVMAtomicUseCase {
+	return &DeleteVMAtomicUseCase{
+		pool:          pool,
+		sqlcQueries:   sqlcQueries,
+		riverClient:   riverClient,
+		vmRepo:        vmRepo,
+		namespaceRepo: namespaceRepo,
+		approvers:     approvers,
+		guard:         guard,
+		environments:  environments,
+		clock:         clock,
+		ids:           ids,
+	}
+}
+
+// DeleteVMRequest contains the VM deletion request data.
+type DeleteVMRequest struct {
+	VMID        string // Required
+	Reason      string // Required: business reason for request
+	RequestedBy string // Required: user who submitted the request
+
+	// Tiered confirmation (ADR-0015 §13.1): Confirm for test namespaces,
+	// ConfirmName (the VM name) for prod namespaces.
+	Confirm     bool
+	ConfirmName string
+}
+
+// DeleteVMResult contains the VM deletion result.
+type DeleteVMResult struct {
+	EventID  string
+	TicketID string
+}
+
+// Execute records the deletion request for approval.
+func (uc *DeleteVMAtomicUseCase) Execute(ctx context.Context, req DeleteVMRequest) (*DeleteVMResult, error) {
+	vm, decision, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	approvers, err := uc.approvers.Resolve(ctx, ticketID, vm.ServiceID, req.RequestedBy, decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("resolve approvers: %w", err)
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := uc.createRequest(ctx, sqlcTx, vm, decision, req, eventID, ticketID, false, approvers); err != nil {
+		return nil, err
+	}
+
+	// No River Job before approval (ADR-0006)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &DeleteVMResult{EventID: eventID, TicketID: ticketID}, nil
+}
+
+// ApproveAndEnqueue records an approval. Once the ticket has its required
+// approvals, the VM is marked DELETING and the River job is inserted in
+// the same transaction. executeAt, when set, plans the deletion for later;
+// the VM is DELETING from approval on. modifiedSpec goes through the same
+// merge as other requests; a deletion has no modifiable fields, so only
+// its modified_by/modified_reason are accepted.
+func (uc *DeleteVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := uc.approveTx(ctx, tx, ticketID, approverID, modifiedSpec, executeAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// approveTx records one approval inside the caller's transaction and, on
+// the last required approval, marks the VM DELETING and inserts the job.
+// Shared with BatchDeleteVMUseCase, which approves every child in one TX.
+func (uc *DeleteVMAtomicUseCase) approveTx(ctx context.Context, tx pgx.Tx, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, modifiedSpec != nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+
+	specJSON, err := storeModifiedSpec(ctx, sqlcTx, ticket, modifiedSpec)
+	if err != nil {
+		return nil, err
+	}
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	if _, err := effectiveEventPayload[domain.VMDeletionPayload](event, specJSON); err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		return result, nil
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+		ModifiedSpec:       specJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	if err := uc.enqueue(ctx, tx, sqlcTx, ticketID, event.EventID, event.AggregateID, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AutoApproveAndEnqueue deletes without human approval, for deletions the
+// platform initiates itself (e.g. expired leases). Event + Ticket + Job
+// are created in a single transaction. Confirmation still applies.
+func (uc *DeleteVMAtomicUseCase) AutoApproveAndEnqueue(ctx context.Context, req DeleteVMRequest) (*DeleteVMResult, error) {
+	vm, decision, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	// ========== Single Atomic Transaction (ADR-0012 True ACID) ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := uc.createRequest(ctx, sqlcTx, vm, decision, req, eventID, ticketID, true, nil); err != nil {
+		return nil, err
+	}
+	if err := uc.enqueue(ctx, tx, sqlcTx, ticketID, eventID, vm.ID, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &DeleteVMResult{EventID: eventID, TicketID: ticketID}, nil
+}
+
+// prepare loads the VM, checks confirmation and evaluates the environment
+// policy (for the required approvals) before any transaction is opened.
+func (uc *DeleteVMAtomicUseCase) prepare(ctx context.Context, req DeleteVMRequest) (*domain.VM, *domain.EnvironmentDecision, error) {
+	vm, err := uc.vmRepo.Get(ctx, req.VMID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get vm: %w", err)
+	}
+	nsEnv, err := uc.namespaceRepo.GetEnvironment(ctx, vm.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get namespace environment: %w", err)
+	}
+	if err := domain.CheckDeleteConfirmation(vm, nsEnv, req.Confirm, req.ConfirmName); err != nil {
+		return nil, nil, err
+	}
+
+	// Deletions never auto-approve by size: the ticket records the
+	// environment and its RequiredApprovals
+	decision, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Staged approval is for sizing decisions: deletions use one stage
+	decision.Stages = domain.DefaultStages(decision.RequiredApprovals)
+	decision.Staged = false
+	return vm, decision, nil
+}
+
+// createRequest locks the VM and writes the deletion event, ticket,
+// approvers and audit record.
+func (uc *DeleteVMAtomicUseCase) createRequest(
+	ctx context.Context,
+	sqlcTx *sqlc.Queries,
+	vm *domain.VM,
+	decision *domain.EnvironmentDecision,
+	req DeleteVMRequest,
+	eventID, ticketID string,
+	autoApprove bool,
+	approvers []*domain.TicketApprover,
+) error {
+	if _, err := lockVM(ctx, sqlcTx, vm.ID); err != nil {
+		return err
+	}
+	return writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:     eventID,
+		ticketID:    ticketID,
+		vmID:        vm.ID,
+		vmName:      vm.Name,
+		serviceID:   vm.ServiceID,
+		requestType: "DELETE_VM",
+		eventType:   domain.EventVMDeletionRequested,
+		payload: domain.VMDeletionPayload{
+			VMID:      vm.ID,
+			Name:      vm.Name,
+			Namespace: vm.Namespace,
+			Cluster:   vm.Cluster,
+			ServiceID: vm.ServiceID,
+			Reason:    req.Reason,
+		},
+		reason:      req.Reason,
+		requestedBy: req.RequestedBy,
+		decision:    *decision,
+		autoApprove: autoApprove,
+		approvers:   approvers,
+		auditAction: domain.AuditVMDeletionRequested,
+		auditDetails: map[string]interface{}{
+			"reason": req.Reason,
+		},
+	})
+}
+
+// enqueue marks the VM DELETING, moves the event to PROCESSING and inserts
+// the River job (scheduled at executeAt if set), all in the caller's
+// transaction. Both moves go through the state machine: a VM that is
+// already DELETED refuses the approval.
+func (uc *DeleteVMAtomicUseCase) enqueue(ctx context.Context, tx pgx.Tx, sqlcTx *sqlc.Queries, ticketID, eventID, vmID string, executeAt *time.Time) error {
+	now := uc.clock.Now()
+	if _, err := repository.TransitionVMStatus(ctx, tx, vmID, domain.VMStatusDeleting, "", domain.TransitionRequest, now); err != nil {
+		return fmt.Errorf("update vm: %w", err)
+	}
+	if _, err := repository.TransitionEventStatus(ctx, tx, eventID, domain.EventStatusProcessing, nil, domain.TransitionRequest, now); err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	return insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, eventID, domain.QueueNormal, executeAt)
+}
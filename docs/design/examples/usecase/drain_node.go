@@ -0,0 +1,318 @@
+// Package usecase provides Clean Architecture use cases.
+//
+// This file implements admin-initiated node drain coordination.
+//
+// Flow:
+//  1. List managed VMs on the node and plan an action per VM (outside TX)
+//  2. Cordon the node so stop/start reschedules elsewhere (outside TX)
+//  3. Single atomic TX: parent event + drain record + child events + River jobs
+//     - LIVE_MIGRATE jobs run immediately
+//     - STOP_START jobs are scheduled at the maintenance window start
+//  4. Notify Service owners of affected VMs (after commit, best-effort)
+//
+// Admin-initiated: no ApprovalTicket is created (same as Reconciler actions).
+//
+// Import Path (ADR-0016): kv-shepherd.io/shepherd/internal/usecase
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ServiceOwnerResolver resolves the users responsible for a Service.
+// Implementation reads owner/admin ResourceRoleBindings on the Service and
+// its parent System (inheritance per master-flow.md §Stage 2.D).
+type ServiceOwnerResolver interface {
+	ResolveOwners(ctx context.Context, serviceID string) ([]string, error)
+}
+
+// DrainNodeUseCase coordinates moving managed VMs off a node.
+type DrainNodeUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	vms         provider.InfrastructureProvider // Narrow interfaces (ADR-0024)
+	nodes       provider.NodeProvider
+	owners      ServiceOwnerResolver
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewDrainNodeUseCase creates a new use case instance.
+func NewDrainNodeUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vms provider.InfrastructureProvider,
+	nodes provider.NodeProvider,
+	owners ServiceOwnerResolver,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *DrainNodeUseCase {
+	return &DrainNodeUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		vms:         vms,
+		nodes:       nodes,
+		owners:      owners,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// DrainNodeRequest contains the drain request data.
+type DrainNodeRequest struct {
+	Cluster  string // Required
+	NodeName string // Required
+	// Window is required when any VM can not live-migrate, unless Force is set.
+	Window      *domain.MaintenanceWindow
+	Force       bool   // Stop/start non-migratable VMs immediately
+	Reason      string // Required: e.g., "kernel upgrade"
+	RequestedBy string // Required: platform admin
+}
+
+// DrainNodeResult contains the drain plan.
+type DrainNodeResult struct {
+	DrainID string
+	EventID string
+	Items   []*domain.NodeDrainItem
+}
+
+// Execute plans and enqueues the drain.
+func (uc *DrainNodeUseCase) Execute(ctx context.Context, req DrainNodeRequest) (*DrainNodeResult, error) {
+	if err := req.Window.Validate(); err != nil {
+		return nil, err
+	}
+
+	// ========== Plan (K8s reads OUTSIDE transaction, ADR-0012) ==========
+	vms, err := uc.listManagedVMsOnNode(ctx, req.Cluster, req.NodeName)
+	if err != nil {
+		return nil, fmt.Errorf("list vms on node: %w", err)
+	}
+
+	now := uc.clock.Now()
+	drainID := uc.ids.NewID()
+	eventID := uc.ids.NewID()
+
+	items := make([]*domain.NodeDrainItem, 0, len(vms))
+	for _, vm := range vms {
+		item := &domain.NodeDrainItem{
+			ID:        uc.ids.NewID(),
+			DrainID:   drainID,
+			EventID:   uc.ids.NewID(),
+			VMName:    vm.Name,
+			Namespace: vm.Namespace,
+			ServiceID: vm.ServiceID,
+			Action:    domain.PlanDrainAction(vm),
+			Status:    domain.NodeDrainItemPending,
+		}
+		if item.Action == domain.DrainActionStopStart {
+			if req.Window == nil && !req.Force {
+				return nil, domain.ErrStopStartOutsideWindow
+			}
+			if at := req.Window.ScheduleAt(now); !at.IsZero() {
+				item.ScheduledAt = &at
+				item.Status = domain.NodeDrainItemScheduled
+			}
+		}
+		items = append(items, item)
+	}
+
+	// Cordon before enqueueing so restarted VMs never land on this node again.
+	// Outside TX: cordon is idempotent, a failed commit leaves only a cordoned node.
+	if err := uc.nodes.CordonNode(ctx, req.Cluster, req.NodeName); err != nil {
+		return nil, fmt.Errorf("cordon node: %w", err)
+	}
+
+	parentPayload := domain.NodeDrainPayload{
+		DrainID:  drainID,
+		Cluster:  req.Cluster,
+		NodeName: req.NodeName,
+		Window:   req.Window,
+		Reason:   req.Reason,
+		VMCount:  len(items),
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Step 1: Parent event (PROCESSING: admin-initiated, no approval)
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       eventID,
+		EventType:     string(domain.EventNodeDrainRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventNodeDrainRequested),
+		AggregateType: "Node",
+		AggregateID:   req.Cluster + "/" + req.NodeName,
+		Status:        string(domain.EventStatusProcessing),
+		CreatedBy:     req.RequestedBy,
+	}, parentPayload)
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	// Step 2: Drain record (progress counters)
+	err = sqlcTx.CreateNodeDrain(ctx, sqlc.CreateNodeDrainParams{
+		ID:           drainID,
+		EventID:      eventID,
+		Cluster:      req.Cluster,
+		NodeName:     req.NodeName,
+		Window:       req.Window, // JSONB (sqlc type override)
+		Reason:       req.Reason,
+		Status:       string(domain.NodeDrainStatusInProgress),
+		TotalCount:   len(items),
+		PendingCount: len(items),
+		RequestedBy:  req.RequestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create node drain: %w", err)
+	}
+
+	// Step 3: One child event + item + River job per VM
+	for _, item := range items {
+		childType := item.Action.EventType()
+		err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+			EventID:       item.EventID,
+			EventType:     string(childType),
+			SchemaVersion: domain.PayloadVersion(childType),
+			AggregateType: "VM",
+			AggregateID:   item.VMName,
+			Status:        string(domain.EventStatusProcessing),
+			CreatedBy:     req.RequestedBy,
+		}, domain.NodeDrainItemPayload{
+			DrainID:   drainID,
+			ItemID:    item.ID,
+			Cluster:   req.Cluster,
+			Namespace: item.Namespace,
+			VMName:    item.VMName,
+			Action:    item.Action,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create item event %s: %w", item.VMName, err)
+		}
+
+		err = sqlcTx.CreateNodeDrainItem(ctx, sqlc.CreateNodeDrainItemParams{
+			ID:          item.ID,
+			DrainID:     drainID,
+			EventID:     item.EventID,
+			VMName:      item.VMName,
+			Namespace:   item.Namespace,
+			ServiceID:   item.ServiceID,
+			Action:      string(item.Action),
+			Status:      string(item.Status),
+			ScheduledAt: item.ScheduledAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create drain item %s: %w", item.VMName, err)
+		}
+
+		// Stop/start waits for the window via River scheduled jobs
+		var opts *river.InsertOpts
+		if item.ScheduledAt != nil {
+			opts = &river.InsertOpts{ScheduledAt: *item.ScheduledAt}
+		}
+		if _, err = uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: item.EventID}, opts); err != nil {
+			return nil, fmt.Errorf("insert river job %s: %w", item.VMName, err)
+		}
+	}
+
+	// Step 4: Single atomic commit
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	// Notifications are best-effort: the drain proceeds even if delivery fails.
+	uc.notifyOwners(ctx, req, drainID, items)
+
+	return &DrainNodeResult{
+		DrainID: drainID,
+		EventID: eventID,
+		Items:   items,
+	}, nil
+}
+
+// listManagedVMsOnNode pages through platform-owned VMs whose VMI runs on the node.
+func (uc *DrainNodeUseCase) listManagedVMsOnNode(ctx context.Context, cluster, nodeName string) ([]*domain.VM, error) {
+	opts := provider.ListOptions{
+		LabelSelector: domain.ManagedSelector() + "," + domain.LabelKubeVirtNodeName + "=" + nodeName,
+		Limit:         100,
+	}
+
+	var all []*domain.VM
+	for {
+		// Empty namespace = all namespaces
+		page, err := uc.vms.ListVMs(ctx, cluster, "", opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		if page.Continue == "" {
+			return all, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// notifyOwners sends one notification per owner listing their affected VMs.
+func (uc *DrainNodeUseCase) notifyOwners(ctx context.Context, req DrainNodeRequest, drainID string, items []*domain.NodeDrainItem) {
+	ctx = logger.WithResource(logger.WithCluster(ctx, req.Cluster), "node_drain", drainID)
+	byOwner := make(map[string][]string)
+	for _, item := range items {
+		owners, err := uc.owners.ResolveOwners(ctx, item.ServiceID)
+		if err != nil {
+			logger.WarnCtx(ctx, "Resolve service owners failed",
+				zap.String("service_id", item.ServiceID),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, owner := range owners {
+			byOwner[owner] = append(byOwner[owner], fmt.Sprintf("%s (%s)", item.VMName, item.Action))
+		}
+	}
+
+	when := "now"
+	if req.Window != nil {
+		when = req.Window.Start.Format(time.RFC3339) + " - " + req.Window.End.Format(time.RFC3339)
+	}
+
+	notifications := make([]*domain.Notification, 0, len(byOwner))
+	for owner, vmNames := range byOwner {
+		notifications = append(notifications, &domain.Notification{
+			ID:        uc.ids.NewID(),
+			Recipient: owner,
+			Type:      domain.NotificationNodeDrainScheduled,
+			Title:     fmt.Sprintf("Node maintenance on %s/%s", req.Cluster, req.NodeName),
+			Content: fmt.Sprintf("Reason: %s\nWindow: %s\nAffected VMs:\n- %s",
+				req.Reason, when, strings.Join(vmNames, "\n- ")),
+			CreatedAt: uc.clock.Now(),
+		})
+	}
+
+	if err := uc.notifier.SendBatch(ctx, notifications); err != nil {
+		logger.WarnCtx(ctx, "Send drain notifications failed", zap.Error(err))
+	}
+}
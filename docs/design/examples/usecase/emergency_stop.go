@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// EmergencyStopUseCase stops every VM of a Service or System
+// (domain/emergency_stop.go).
+//
+// Flow:
+//  1. Permission, confirmation and VM list (DB reads, outside TX)
+//  2. Single atomic TX: parent event + stop record + child events +
+//     River jobs on the emergency queue + audit
+//  3. Notify the owners of the affected Services (after commit, best-effort)
+type EmergencyStopUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	vmRepo      repository.VMRepository
+	stopRepo    repository.EmergencyStopRepository
+	permissions domain.PermissionChecker
+	owners      ServiceOwnerResolver
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewEmergencyStopUseCase creates a new use case instance.
+func NewEmergencyStopUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	stopRepo repository.EmergencyStopRepository,
+	permissions domain.PermissionChecker,
+	owners ServiceOwnerResolver,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *EmergencyStopUseCase {
+	return &EmergencyStopUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		vmRepo:      vmRepo,
+		stopRepo:    stopRepo,
+		permissions: permissions,
+		owners:      owners,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// EmergencyStopResult contains the enqueued stop.
+type EmergencyStopResult struct {
+	StopID  string
+	EventID string
+	Items   []*domain.EmergencyStopItem
+	Skipped int // VMs already stopped or being deleted
+}
+
+// Execute stops every VM in scope as userID. Returns
+// domain.ErrEmergencyStopForbidden, domain.ErrEmergencyStopNotConfirmed,
+// domain.ErrEmergencyStopNothingToStop or domain.ErrEmergencyStopInProgress
+// when nothing was enqueued.
+func (uc *EmergencyStopUseCase) Execute(ctx context.Context, scopeType domain.ResourceType, scopeID, userID string, req domain.EmergencyStopRequest) (*EmergencyStopResult, error) {
+	ctx = logger.WithResource(ctx, string(scopeType), scopeID)
+
+	// ========== Checks and plan (outside TX) ==========
+	scopeName, err := uc.scopeName(ctx, scopeType, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := uc.permissions.CheckPermission(userID, domain.EmergencyStopPermission, string(scopeType), scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, domain.ErrEmergencyStopForbidden
+	}
+	if err := req.Validate(scopeName); err != nil {
+		return nil, err
+	}
+
+	vms, err := uc.listVMs(ctx, scopeType, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("list vms: %w", err)
+	}
+
+	stopID := uc.ids.NewID()
+	eventID := uc.ids.NewID()
+	result := &EmergencyStopResult{StopID: stopID, EventID: eventID}
+	for _, vm := range vms {
+		if !domain.EmergencyStoppable(vm.Status) {
+			result.Skipped++
+			continue
+		}
+		result.Items = append(result.Items, &domain.EmergencyStopItem{
+			ID:        uc.ids.NewID(),
+			StopID:    stopID,
+			EventID:   uc.ids.NewID(),
+			VMID:      vm.ID,
+			VMName:    vm.Name,
+			Namespace: vm.Namespace,
+			Cluster:   vm.Cluster,
+			Status:    domain.EmergencyStopItemPending,
+		})
+	}
+	if len(result.Items) == 0 {
+		return nil, domain.ErrEmergencyStopNothingToStop
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Step 1: Stop record first: the partial unique index allows one
+	// IN_PROGRESS stop per scope, so a double click enqueues nothing twice
+	created, err := sqlcTx.CreateEmergencyStop(ctx, sqlc.CreateEmergencyStopParams{
+		ID:           stopID,
+		EventID:      eventID,
+		ScopeType:    string(scopeType),
+		ScopeID:      scopeID,
+		Reason:       req.Reason,
+		Status:       string(domain.EmergencyStopInProgress),
+		TotalCount:   len(result.Items),
+		PendingCount: len(result.Items),
+		RequestedBy:  userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create emergency stop: %w", err)
+	}
+	if created == 0 {
+		return nil, domain.ErrEmergencyStopInProgress
+	}
+
+	// Step 2: Parent event (PROCESSING: no approval)
+	aggregateType := "Service"
+	if scopeType == domain.ResourceTypeSystem {
+		aggregateType = "System"
+	}
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       eventID,
+		EventType:     string(domain.EventEmergencyStopRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventEmergencyStopRequested),
+		AggregateType: aggregateType,
+		AggregateID:   scopeID,
+		Status:        string(domain.EventStatusProcessing),
+		CreatedBy:     userID,
+	}, domain.EmergencyStopPayload{
+		StopID:    stopID,
+		ScopeType: scopeType,
+		ScopeID:   scopeID,
+		Reason:    req.Reason,
+		VMCount:   len(result.Items),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	// Step 3: One child event + item + River job per VM, on the emergency
+	// queue ahead of everything else
+	opts := &river.InsertOpts{Queue: domain.QueueEmergency, Priority: 1}
+	for _, item := range result.Items {
+		err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+			EventID:       item.EventID,
+			EventType:     string(domain.EventEmergencyStopItemRequested),
+			SchemaVersion: domain.PayloadVersion(domain.EventEmergencyStopItemRequested),
+			AggregateType: "VM",
+			AggregateID:   item.VMID,
+			Status:        string(domain.EventStatusProcessing),
+			CreatedBy:     userID,
+		}, domain.EmergencyStopItemPayload{
+			StopID:    stopID,
+			ItemID:    item.ID,
+			VMID:      item.VMID,
+			Cluster:   item.Cluster,
+			Namespace: item.Namespace,
+			VMName:    item.VMName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create item event %s: %w", item.VMName, err)
+		}
+
+		err = sqlcTx.CreateEmergencyStopItem(ctx, sqlc.CreateEmergencyStopItemParams{
+			ID:        item.ID,
+			StopID:    stopID,
+			EventID:   item.EventID,
+			VMID:      item.VMID,
+			VMName:    item.VMName,
+			Namespace: item.Namespace,
+			Cluster:   item.Cluster,
+			Status:    string(item.Status),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create emergency stop item %s: %w", item.VMName, err)
+		}
+
+		if _, err = uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: item.EventID}, opts); err != nil {
+			return nil, fmt.Errorf("insert river job %s: %w", item.VMName, err)
+		}
+	}
+
+	// Step 4: Audit on the scope; each VM's stop is audited by its job
+	details, _ := json.Marshal(map[string]interface{}{
+		"emergency_stop_id": stopID,
+		"reason":            req.Reason,
+		"vm_count":          len(result.Items),
+		"skipped":           result.Skipped,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVMEmergencyStop,
+		ActorID:      userID,
+		ResourceType: string(scopeType),
+		ResourceID:   scopeID,
+		Details:      details,
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	// Step 5: Single atomic commit
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	logger.WarnCtx(ctx, "Emergency stop started",
+		zap.String("emergency_stop_id", stopID),
+		zap.String("requested_by", userID),
+		zap.Int("vm_count", len(result.Items)),
+	)
+	uc.notifyOwners(ctx, scopeName, stopID, req.Reason, userID, vms)
+
+	return result, nil
+}
+
+// Progress returns the stop and its items, visible to whoever can read
+// the Service or System.
+func (uc *EmergencyStopUseCase) Progress(ctx context.Context, stopID, userID string) (*domain.EmergencyStop, []*domain.EmergencyStopItem, error) {
+	stop, err := uc.stopRepo.Get(ctx, stopID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get emergency stop: %w", err)
+	}
+	perm, err := uc.permissions.CheckPermission(userID, string(stop.ScopeType)+":read", string(stop.ScopeType), stop.ScopeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		// Not found rather than forbidden: stop IDs are not discoverable
+		return nil, nil, repository.ErrNotFound
+	}
+	items, err := uc.stopRepo.ListItems(ctx, stop.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list emergency stop items: %w", err)
+	}
+	return stop, items, nil
+}
+
+// scopeName returns the name the confirmation must repeat.
+func (uc *EmergencyStopUseCase) scopeName(ctx context.Context, scopeType domain.ResourceType, scopeID string) (string, error) {
+	var (
+		name string
+		err  error
+	)
+	switch scopeType {
+	case domain.ResourceTypeService:
+		name, err = uc.sqlcQueries.GetServiceName(ctx, scopeID)
+	case domain.ResourceTypeSystem:
+		name, err = uc.sqlcQueries.GetSystemName(ctx, scopeID)
+	default:
+		return "", fmt.Errorf("%q: %w", scopeType, domain.ErrInvalidEmergencyStopScope)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", repository.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get %s: %w", scopeType, err)
+	}
+	return name, nil
+}
+
+// listVMs lists the platform's VMs in scope. The DB is the source here,
+// not the clusters: a stop must not wait for a slow or unreachable
+// cluster, and its job retries against that cluster on its own.
+func (uc *EmergencyStopUseCase) listVMs(ctx context.Context, scopeType domain.ResourceType, scopeID string) ([]*domain.VM, error) {
+	if scopeType == domain.ResourceTypeSystem {
+		return uc.vmRepo.ListBySystem(ctx, scopeID)
+	}
+	return uc.vmRepo.ListByService(ctx, scopeID)
+}
+
+// notifyOwners tells every owner of an affected Service, except the one who
+// stopped it. Best-effort: the stop proceeds even if delivery fails.
+func (uc *EmergencyStopUseCase) notifyOwners(ctx context.Context, scopeName, stopID, reason, userID string, vms []*domain.VM) {
+	recipients := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	for _, vm := range vms {
+		if _, ok := seen[vm.ServiceID]; ok {
+			continue
+		}
+		seen[vm.ServiceID] = struct{}{}
+		owners, err := uc.owners.ResolveOwners(ctx, vm.ServiceID)
+		if err != nil {
+			logger.WarnCtx(ctx, "Resolve service owners failed",
+				zap.String("service_id", vm.ServiceID),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, owner := range owners {
+			if owner != userID {
+				recipients[owner] = struct{}{}
+			}
+		}
+	}
+
+	notifications := make([]*domain.Notification, 0, len(recipients))
+	for owner := range recipients {
+		notifications = append(notifications, &domain.Notification{
+			ID:        uc.ids.NewID(),
+			Recipient: owner,
+			Type:      domain.NotificationEmergencyStopStarted,
+			Title:     fmt.Sprintf("Emergency stop of %s", scopeName),
+			Content:   fmt.Sprintf("Stopped by: %s\nReason: %s\nEmergency stop: %s", userID, reason, stopID),
+			Priority:  domain.PriorityEmergency, // Sent immediately, bypassing digests
+			CreatedAt: uc.clock.Now(),
+		})
+	}
+	if err := uc.notifier.SendBatch(ctx, notifications); err != nil {
+		logger.WarnCtx(ctx, "Send emergency stop notifications failed", zap.Error(err))
+	}
+}
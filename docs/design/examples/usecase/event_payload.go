@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// eventPayload returns a stored event's payload decompressed and upgraded
+// to the current version (domain/event_encoding.go, event_upcast.go). Use
+// cases decode this, never event.Payload: a PENDING event may predate the
+// current payload struct, and a large one is stored compressed.
+func eventPayload(event sqlc.DomainEvent) ([]byte, error) {
+	payload, err := domain.DecodePayload(domain.PayloadEncoding(event.PayloadEncoding), event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode event payload: %w", err)
+	}
+	payload, err = domain.UpcastPayload(domain.EventType(event.EventType), int(event.SchemaVersion), payload)
+	if err != nil {
+		return nil, fmt.Errorf("upcast event payload: %w", err)
+	}
+	return payload, nil
+}
+
+// decodeEventPayload returns a stored event's payload as T, the payload
+// type registered for the event's type (domain/event_payloads.go).
+func decodeEventPayload[T domain.EventPayload](event sqlc.DomainEvent) (T, error) {
+	payload, err := eventPayload(event)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return domain.UnmarshalPayload[T](domain.EventType(event.EventType), payload)
+}
+
+// effectiveEventPayload returns a stored event's payload as T with a
+// ticket's ModifiedSpec (nil if unmodified) merged in, validated: an
+// approver's change must leave a payload the worker can execute. Fields
+// T does not allow fail with domain.ErrSpecFieldNotModifiable.
+func effectiveEventPayload[T domain.EventPayload](event sqlc.DomainEvent, modifiedSpec []byte) (T, error) {
+	payload, err := eventPayload(event)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	p, err := domain.GetEffectiveSpec[T](domain.EventType(event.EventType), payload, modifiedSpec)
+	if err != nil {
+		return p, fmt.Errorf("resolve effective spec: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// createDomainEvent validates and marshals payload, then inserts the event,
+// compressing the payload when large. Use cases write events through this,
+// never CreateDomainEvent directly: a malformed payload fails here, inside
+// the caller's transaction, and nothing is committed.
+func createDomainEvent(ctx context.Context, sqlcTx *sqlc.Queries, p sqlc.CreateDomainEventParams, payload domain.EventPayload) error {
+	data, err := domain.MarshalPayload(domain.EventType(p.EventType), payload)
+	if err != nil {
+		return err
+	}
+	stored, enc, err := domain.EncodePayload(data)
+	if err != nil {
+		return fmt.Errorf("encode event payload: %w", err)
+	}
+	p.Payload, p.PayloadEncoding = stored, string(enc)
+	return sqlcTx.CreateDomainEvent(ctx, p)
+}
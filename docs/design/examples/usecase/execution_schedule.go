@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// planExecution returns the planned execution time of a locked ticket:
+// the one given with this approval, which replaces any earlier approver's,
+// or the stored one. Shared by the request types' approveTx.
+func planExecution(ctx context.Context, sqlcTx *sqlc.Queries, ticket sqlc.ApprovalTicket, executeAt *time.Time) (*time.Time, error) {
+	if executeAt == nil {
+		return ticket.ExecuteAt, nil
+	}
+	if err := sqlcTx.SetTicketExecuteAt(ctx, sqlc.SetTicketExecuteAtParams{
+		TicketID:  ticket.TicketID,
+		ExecuteAt: executeAt,
+	}); err != nil {
+		return nil, fmt.Errorf("set execute_at: %w", err)
+	}
+	return executeAt, nil
+}
+
+// insertExecutionJob inserts the River job executing an approved ticket,
+// scheduled at executeAt when given (already validated), and records the
+// job on the ticket so the execution can be rescheduled.
+func insertExecutionJob(ctx context.Context, tx pgx.Tx, sqlcTx *sqlc.Queries, riverClient *river.Client[pgx.Tx], ticketID, eventID, queue string, executeAt *time.Time) error {
+	opts := &river.InsertOpts{Queue: queue}
+	if executeAt != nil {
+		opts.ScheduledAt = *executeAt
+	}
+	res, err := riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: eventID}, opts)
+	if err != nil {
+		return fmt.Errorf("insert river job: %w", err)
+	}
+	if err := sqlcTx.SetTicketExecutionJob(ctx, sqlc.SetTicketExecutionJobParams{
+		TicketID:       ticketID,
+		ExecutionJobID: res.Job.ID,
+	}); err != nil {
+		return fmt.Errorf("record execution job: %w", err)
+	}
+	return nil
+}
+
+// RescheduleExecutionUseCase moves the planned execution of an approved
+// ticket (domain/execution_schedule.go).
+//
+// River cannot move a job, so the scheduled job is cancelled and a new one
+// inserted, in one TX with the ticket update and the audit log. The quota
+// reservation stays with the ticket.
+type RescheduleExecutionUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	ticketRepo  repository.ApprovalTicketRepository
+	permissions domain.PermissionChecker
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewRescheduleExecutionUseCase creates a new use case instance.
+func NewRescheduleExecutionUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	ticketRepo repository.ApprovalTicketRepository,
+	permissions domain.PermissionChecker,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *RescheduleExecutionUseCase {
+	return &RescheduleExecutionUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		ticketRepo:  ticketRepo,
+		permissions: permissions,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Execute moves the execution of ticketID to executeAt as userID; nil
+// executes now. Returns domain.ErrRescheduleForbidden,
+// domain.ErrInvalidExecuteAt or domain.ErrExecutionStarted when the
+// execution cannot move.
+func (uc *RescheduleExecutionUseCase) Execute(ctx context.Context, ticketID, userID string, executeAt *time.Time) (*domain.ApprovalTicket, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	ticket, err := uc.ticketRepo.Get(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	p, err := ticketParticipation(ctx, uc.sqlcQueries, uc.permissions, ticket, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Locked: a concurrent reschedule must not leave two live jobs
+	locked, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if locked.ExecutionJobID == 0 {
+		// Auto-approved, or not approved yet: nothing planned to move
+		return nil, fmt.Errorf("ticket is %s without planned execution: %w", locked.Status, domain.ErrExecutionStarted)
+	}
+	job, err := uc.riverClient.JobGetTx(ctx, tx, locked.ExecutionJobID)
+	if err != nil {
+		return nil, fmt.Errorf("get execution job: %w", err)
+	}
+	if err := domain.CheckReschedule(domain.TicketStatus(locked.Status), string(job.State), p); err != nil {
+		return nil, err
+	}
+
+	// A worker may have fetched the job since: River then only flags it
+	// for cancellation and it runs, so the reschedule is refused
+	cancelled, err := uc.riverClient.JobCancelTx(ctx, tx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cancel execution job: %w", err)
+	}
+	if cancelled.State != rivertype.JobStateCancelled {
+		return nil, fmt.Errorf("job is %s: %w", cancelled.State, domain.ErrExecutionStarted)
+	}
+
+	if err := sqlcTx.SetTicketExecuteAt(ctx, sqlc.SetTicketExecuteAtParams{
+		TicketID:  ticketID,
+		ExecuteAt: executeAt,
+	}); err != nil {
+		return nil, fmt.Errorf("set execute_at: %w", err)
+	}
+	if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, locked.EventID, job.Queue, executeAt); err != nil {
+		return nil, err
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"previous_execute_at": locked.ExecuteAt,
+		"execute_at":          executeAt, // null: now
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditApprovalExecutionRescheduled,
+		ActorID:      userID,
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details:      details,
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	ticket.ExecuteAt = executeAt
+	return ticket, nil
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// leaseBatchSize bounds the leases warned, and those expired, per run;
+// the rest are picked up by the next run.
+const leaseBatchSize = 100
+
+// ExpireLeasesUseCase warns the owners of VMs whose lease is about to end
+// and stops or deletes the VMs whose lease has ended (domain/vm_lease.go).
+// Run by the lease_expiry periodic job.
+//
+// The action goes through the ordinary auto-approved flows with "system"
+// as actor, so a VM with an operation in flight is retried on the next
+// run. The lease is marked expired only once the action is enqueued.
+type ExpireLeasesUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	power       *PowerOperationUseCase
+	del         *DeleteVMAtomicUseCase
+	owners      ServiceOwnerResolver
+	notifier    domain.NotificationSender
+	warnLead    time.Duration
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewExpireLeasesUseCase creates a new use case instance. warnLead comes
+// from governance.lease_warning_lead.
+func NewExpireLeasesUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	power *PowerOperationUseCase,
+	del *DeleteVMAtomicUseCase,
+	owners ServiceOwnerResolver,
+	notifier domain.NotificationSender,
+	warnLead time.Duration,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ExpireLeasesUseCase {
+	return &ExpireLeasesUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		power:       power,
+		del:         del,
+		owners:      owners,
+		notifier:    notifier,
+		warnLead:    warnLead,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Execute warns, then expires, up to leaseBatchSize leases each, soonest
+// expiry first, and returns how many it warned and expired.
+func (uc *ExpireLeasesUseCase) Execute(ctx context.Context) (warned, expired int, err error) {
+	now := uc.clock.Now()
+
+	// lease_expires_at <= cutoff, not warned, not expired, VM not deleting/deleted
+	due, err := uc.sqlcQueries.ListLeasesToWarn(ctx, sqlc.ListLeasesToWarnParams{
+		Cutoff: now.Add(uc.warnLead),
+		Limit:  leaseBatchSize,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("list leases to warn: %w", err)
+	}
+	for _, l := range due {
+		ok, err := uc.mark(ctx, l, domain.AuditVMLeaseWarned, nil)
+		if err != nil {
+			return warned, expired, fmt.Errorf("warn lease of vm %s: %w", l.VMID, err)
+		}
+		if !ok {
+			continue // Renewed since the listing
+		}
+		warned++
+		uc.notify(ctx, l, domain.NotificationLeaseExpiring,
+			fmt.Sprintf("%s expires %s", l.Name, l.ExpiresAt.Format(time.RFC3339)),
+			fmt.Sprintf("The VM will be %s at expiry. Request a renewal if it is still needed.", leaseActionVerb(l.Action)))
+	}
+
+	// lease_expires_at <= now, not expired, VM not deleting/deleted
+	ended, err := uc.sqlcQueries.ListExpiredLeases(ctx, sqlc.ListExpiredLeasesParams{
+		Now:   now,
+		Limit: leaseBatchSize,
+	})
+	if err != nil {
+		return warned, expired, fmt.Errorf("list expired leases: %w", err)
+	}
+	for _, l := range ended {
+		ctx := logger.WithResource(ctx, "vm", l.VMID)
+
+		eventID, err := uc.act(ctx, l)
+		switch {
+		case errors.Is(err, domain.ErrVMOperationPending):
+			continue // Retried next run, once the operation is done
+		case errors.Is(err, domain.ErrPowerActionNotAllowed), errors.Is(err, domain.ErrVMDeletionInProgress):
+			// Already stopped (or not running) or being deleted: nothing to do
+		case err != nil:
+			// One broken VM must not hold up the others
+			logger.WarnCtx(ctx, "Lease expiry action failed", zap.Error(err))
+			continue
+		}
+
+		ok, err := uc.mark(ctx, l, domain.AuditVMLeaseExpired, map[string]interface{}{
+			"action":   l.Action,
+			"event_id": eventID,
+		})
+		if err != nil {
+			return warned, expired, fmt.Errorf("expire lease of vm %s: %w", l.VMID, err)
+		}
+		if !ok {
+			continue
+		}
+		expired++
+		uc.notify(ctx, l, domain.NotificationLeaseExpired,
+			fmt.Sprintf("Lease of %s expired", l.Name),
+			fmt.Sprintf("The VM is being %s.", leaseActionVerb(l.Action)))
+	}
+	return warned, expired, nil
+}
+
+// act enqueues the lease's action and returns its event ID. A lease
+// renewed since the listing is left alone.
+func (uc *ExpireLeasesUseCase) act(ctx context.Context, l sqlc.VMLease) (string, error) {
+	current, err := uc.sqlcQueries.GetVMLease(ctx, l.VMID)
+	if err != nil {
+		return "", fmt.Errorf("get lease: %w", err)
+	}
+	if current.LeaseExpiresAt == nil || !current.LeaseExpiresAt.Equal(l.ExpiresAt) {
+		return "", nil // Renewed or removed: mark finds nothing to do
+	}
+
+	reason := fmt.Sprintf("Lease expired at %s", l.ExpiresAt.Format(time.RFC3339))
+	if domain.LeaseAction(l.Action) == domain.LeaseDelete {
+		res, err := uc.del.AutoApproveAndEnqueue(ctx, DeleteVMRequest{
+			VMID:        l.VMID,
+			Reason:      reason,
+			RequestedBy: "system",
+			Confirm:     true,
+			ConfirmName: l.Name,
+		})
+		if err != nil {
+			return "", err
+		}
+		return res.EventID, nil
+	}
+	res, err := uc.power.AutoApproveAndEnqueue(ctx, PowerOperationRequest{
+		VMID:        l.VMID,
+		Action:      domain.PowerStop,
+		Reason:      reason,
+		RequestedBy: "system",
+	})
+	if err != nil {
+		return "", err
+	}
+	return res.EventID, nil
+}
+
+// mark records the warning (details nil) or the expiry of the lease with
+// its audit log, unless the lease was renewed since the listing. Reports
+// whether it did.
+func (uc *ExpireLeasesUseCase) mark(ctx context.Context, l sqlc.VMLease, action string, details map[string]interface{}) (bool, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Same lock as the renewal approval: a renewal that commits first wins
+	lease, _, err := lockLease(ctx, sqlcTx, l.VMID)
+	if err != nil {
+		return false, err
+	}
+	if lease == nil || !lease.ExpiresAt.Equal(l.ExpiresAt) || lease.ExpiredAt != nil {
+		return false, nil
+	}
+
+	now := uc.clock.Now()
+	if details == nil {
+		if lease.WarnedAt != nil {
+			return false, nil
+		}
+		err = sqlcTx.MarkVMLeaseWarned(ctx, sqlc.MarkVMLeaseWarnedParams{VMID: l.VMID, WarnedAt: now})
+	} else {
+		err = sqlcTx.MarkVMLeaseExpired(ctx, sqlc.MarkVMLeaseExpiredParams{VMID: l.VMID, ExpiredAt: now})
+	}
+	if err != nil {
+		return false, fmt.Errorf("mark lease: %w", err)
+	}
+
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["expires_at"] = l.ExpiresAt
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      "system",
+		ResourceType: "vm",
+		ResourceID:   l.VMID,
+		ResourceName: l.Name,
+		Details:      details,
+	}); err != nil {
+		return false, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+	return true, nil
+}
+
+// notify tells the Service owners. Best effort: the lease is already
+// marked.
+func (uc *ExpireLeasesUseCase) notify(ctx context.Context, l sqlc.VMLease, typ domain.NotificationType, title, content string) {
+	owners, err := uc.owners.ResolveOwners(ctx, l.ServiceID)
+	if err != nil {
+		logger.WarnCtx(ctx, "Resolve lease owners failed", zap.Error(err))
+		return
+	}
+	now := uc.clock.Now()
+	batch := make([]*domain.Notification, 0, len(owners))
+	for _, owner := range owners {
+		batch = append(batch, &domain.Notification{
+			ID:        uc.ids.NewID(),
+			Recipient: owner,
+			Type:      typ,
+			Title:     title,
+			Content:   content,
+			CreatedAt: now,
+		})
+	}
+	if err := uc.notifier.SendBatch(ctx, batch); err != nil {
+		logger.WarnCtx(ctx, "Send lease notification failed", zap.Error(err))
+	}
+}
+
+func leaseActionVerb(action string) string {
+	if domain.LeaseAction(action) == domain.LeaseDelete {
+		return "deleted"
+	}
+	return "stopped"
+}
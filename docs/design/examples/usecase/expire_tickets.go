@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// expireBatchSize bounds the tickets expired per run; the rest are picked
+// up by the next run.
+const expireBatchSize = 200
+
+// ExpireTicketsUseCase expires pending tickets older than their TTL
+// (domain/ticket_expiry.go). Run by the ticket_expiry periodic job.
+//
+// Each ticket is expired in its own TX, like a cancellation by "system":
+// ticket → EXPIRED, event → CANCELLED, REQUEST_EXPIRED recorded, audit
+// request.expired. No River job or quota reservation exists before
+// approval, so nothing else is undone.
+type ExpireTicketsUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	notifier    domain.NotificationSender
+	ttl         domain.TicketTTL
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewExpireTicketsUseCase creates a new use case instance.
+func NewExpireTicketsUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	notifier domain.NotificationSender,
+	ttl domain.TicketTTL,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ExpireTicketsUseCase {
+	return &ExpireTicketsUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		notifier:    notifier,
+		ttl:         ttl,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Execute expires up to expireBatchSize stale tickets, oldest first, and
+// returns how many it expired.
+func (uc *ExpireTicketsUseCase) Execute(ctx context.Context) (int, error) {
+	now := uc.clock.Now()
+	types, cutoffs, other := uc.ttl.Cutoffs(now)
+
+	stale, err := uc.sqlcQueries.ListExpiredPendingTickets(ctx, sqlc.ListExpiredPendingTicketsParams{
+		RequestTypes: types,
+		Cutoffs:      cutoffs,
+		OtherCutoff:  other,
+		Limit:        expireBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list expired tickets: %w", err)
+	}
+
+	var expired int
+	for _, ticket := range stale {
+		err := uc.expire(ctx, ticket.TicketID)
+		switch {
+		case errors.Is(err, domain.ErrTicketNotPending):
+			continue // Decided or cancelled since the listing
+		case err != nil:
+			return expired, fmt.Errorf("expire ticket %s: %w", ticket.TicketID, err)
+		}
+		expired++
+		uc.notify(ctx, ticket)
+	}
+	return expired, nil
+}
+
+func (uc *ExpireTicketsUseCase) expire(ctx context.Context, ticketID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Same lock as the approval paths: an approval that commits first wins
+	ticket, err := lockTicket(ctx, sqlcTx, ticketID)
+	if err != nil {
+		return err
+	}
+	if err := requirePending(ticket.Status); err != nil {
+		return err
+	}
+	ttl := uc.ttl.For(ticket.RequestType)
+
+	if err := sqlcTx.ExpireApprovalTicket(ctx, ticketID); err != nil {
+		return fmt.Errorf("expire ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusCancelled),
+	})
+	if err != nil {
+		return fmt.Errorf("cancel event: %w", err)
+	}
+
+	// Recorded only: no handler, no River job
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       uc.ids.NewID(),
+		EventType:     string(domain.EventRequestExpired),
+		SchemaVersion: domain.PayloadVersion(domain.EventRequestExpired),
+		AggregateType: "ApprovalTicket",
+		AggregateID:   ticketID,
+		Status:        string(domain.EventStatusCompleted),
+		CreatedBy:     "system",
+	}, domain.RequestExpiredPayload{
+		TicketID:    ticketID,
+		EventID:     ticket.EventID,
+		RequestType: ticket.RequestType,
+		SubmittedAt: ticket.CreatedAt,
+		TTL:         ttl,
+	})
+	if err != nil {
+		return fmt.Errorf("create expiry event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditRequestExpired,
+		ActorID:      "system",
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details: map[string]interface{}{
+			"request_type": ticket.RequestType,
+			"event_id":     ticket.EventID,
+			"requester":    ticket.CreatedBy,
+			"ttl":          ttl.String(),
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	// Batch child: counted as failed, as with rejection and cancellation
+	if err := closeBatchChild(ctx, sqlcTx, ticket.ParentTicketID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// notify tells the requester. Best effort: the ticket is already expired.
+func (uc *ExpireTicketsUseCase) notify(ctx context.Context, ticket sqlc.ApprovalTicket) {
+	ttl := uc.ttl.For(ticket.RequestType)
+	n := &domain.Notification{
+		ID:              uc.ids.NewID(),
+		Recipient:       ticket.CreatedBy,
+		Type:            domain.NotificationRequestExpired,
+		Title:           fmt.Sprintf("%s request expired", ticket.RequestType),
+		Content:         fmt.Sprintf("Not approved within %s of submission; submit it again if it is still needed.\nReason: %s", ttl, ticket.RequestReason),
+		RelatedTicketID: ticket.TicketID,
+		Priority:        domain.Priority(ticket.Priority),
+		CreatedAt:       uc.clock.Now(),
+	}
+	if err := uc.notifier.SendBatch(ctx, []*domain.Notification{n}); err != nil {
+		logger.WarnCtx(logger.WithTicket(ctx, ticket.TicketID), "Send expiry notification failed", zap.Error(err))
+	}
+}
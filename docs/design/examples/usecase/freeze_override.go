@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// FreezeOverrideUseCase records emergency overrides of a change freeze.
+//
+// Two steps, two people: Request records who asks and why; Approve must
+// come from someone else (domain.CheckOverrideApproval) holding
+// freeze:override. The held job picks the override up at its next check.
+type FreezeOverrideUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewFreezeOverrideUseCase creates a new use case instance.
+func NewFreezeOverrideUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *FreezeOverrideUseCase {
+	return &FreezeOverrideUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// Request asks for an emergency override for an approved ticket.
+func (uc *FreezeOverrideUseCase) Request(ctx context.Context, ticketID, requestedBy, reason string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// ON CONFLICT DO NOTHING: a second request for the same ticket keeps the first
+	err = sqlcTx.CreateFreezeOverride(ctx, sqlc.CreateFreezeOverrideParams{
+		TicketID:    ticketID,
+		Reason:      reason,
+		RequestedBy: requestedBy,
+		RequestedAt: uc.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("create freeze override: %w", err)
+	}
+
+	err = sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditFreezeOverrideRequested,
+		ActorID:      requestedBy,
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details:      map[string]interface{}{"reason": reason},
+	})
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// Approve approves a requested override as the second person.
+func (uc *FreezeOverrideUseCase) Approve(ctx context.Context, ticketID, approverID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// FOR UPDATE: two approvers racing cannot both pass the check
+	row, err := sqlcTx.GetFreezeOverrideForUpdate(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrFreezeOverrideNotRequested
+	}
+	if err != nil {
+		return fmt.Errorf("get freeze override: %w", err)
+	}
+	ticket, err := sqlcTx.GetApprovalTicket(ctx, ticketID)
+	if err != nil {
+		return fmt.Errorf("get ticket: %w", err)
+	}
+
+	override := &domain.FreezeOverride{
+		TicketID:    row.TicketID,
+		RequestedBy: row.RequestedBy,
+		ApprovedBy:  row.ApprovedBy,
+	}
+	if err := domain.CheckOverrideApproval(override, ticket.CreatedBy, approverID); err != nil {
+		return err
+	}
+
+	err = sqlcTx.ApproveFreezeOverride(ctx, sqlc.ApproveFreezeOverrideParams{
+		TicketID:   ticketID,
+		ApprovedBy: approverID,
+		ApprovedAt: uc.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("approve freeze override: %w", err)
+	}
+
+	err = sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditFreezeOverrideApproved,
+		ActorID:      approverID,
+		ResourceType: "approval",
+		ResourceID:   ticketID,
+		Details: map[string]interface{}{
+			"requested_by": row.RequestedBy,
+			"reason":       row.Reason,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ImagePromotionUseCase moves golden image digests through the channels
+// (domain/image_channel.go). Platform admins only (route middleware).
+//
+// Two steps, two people: Request records the digest the previous channel
+// points at; Approve must come from someone else and moves the target
+// channel, conditional on it not having moved since the request. VMs keep
+// the digest they were built from.
+type ImagePromotionUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewImagePromotionUseCase creates a new use case instance.
+func NewImagePromotionUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *ImagePromotionUseCase {
+	return &ImagePromotionUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// RecordBuild points the dev channel of image at a new build. Called by
+// the image pipeline; dev needs no approval.
+func (uc *ImagePromotionUseCase) RecordBuild(ctx context.Context, image string, build domain.ImageBuild, actor string) (*domain.ImageChannelPointer, error) {
+	if err := build.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	pointer := &domain.ImageChannelPointer{
+		Image:      image,
+		Channel:    domain.ImageChannelDev,
+		Repository: build.Repository,
+		Digest:     build.Digest,
+		UpdatedBy:  actor,
+		UpdatedAt:  uc.clock.Now(),
+	}
+	// INSERT ... ON CONFLICT (image, channel) DO UPDATE
+	if err := sqlcTx.UpsertImageChannel(ctx, sqlc.UpsertImageChannelParams{
+		Image:      image,
+		Channel:    string(pointer.Channel),
+		Repository: pointer.Repository,
+		Digest:     pointer.Digest,
+		UpdatedBy:  actor,
+		UpdatedAt:  pointer.UpdatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("update dev channel: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditImageBuildRecorded,
+		ActorID:      actor,
+		ResourceType: "image",
+		ResourceID:   image,
+		Details: map[string]interface{}{
+			"repository": build.Repository,
+			"digest":     build.Digest,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return pointer, nil
+}
+
+// Request asks to promote the digest of the channel before req.Channel.
+func (uc *ImagePromotionUseCase) Request(ctx context.Context, image string, req domain.PromotionRequest, requestedBy string) (*domain.ImagePromotion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	from, _ := req.Channel.Previous()
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	source, err := sqlcTx.GetImageChannel(ctx, sqlc.GetImageChannelParams{Image: image, Channel: string(from)})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s/%s: %w", image, from, domain.ErrImageChannelEmpty)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s channel: %w", from, err)
+	}
+
+	var previous string
+	target, err := sqlcTx.GetImageChannel(ctx, sqlc.GetImageChannelParams{Image: image, Channel: string(req.Channel)})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// First promotion into this channel
+	case err != nil:
+		return nil, fmt.Errorf("get %s channel: %w", req.Channel, err)
+	case target.Digest == source.Digest:
+		return nil, domain.ErrPromotionNoChange
+	default:
+		previous = target.Digest
+	}
+
+	p := &domain.ImagePromotion{
+		ID:          uc.ids.NewID(),
+		Image:       image,
+		FromChannel: from,
+		ToChannel:   req.Channel,
+		Repository:  source.Repository,
+		Digest:      source.Digest,
+		Previous:    previous,
+		Status:      domain.PromotionPending,
+		Reason:      req.Reason,
+		RequestedBy: requestedBy,
+		RequestedAt: uc.clock.Now(),
+	}
+
+	// Partial unique index on (image, to_channel) WHERE status = 'PENDING':
+	// ON CONFLICT DO NOTHING returns 0 rows
+	rows, err := sqlcTx.CreateImagePromotion(ctx, sqlc.CreateImagePromotionParams{
+		ID:             p.ID,
+		Image:          image,
+		FromChannel:    string(from),
+		ToChannel:      string(req.Channel),
+		Repository:     p.Repository,
+		Digest:         p.Digest,
+		PreviousDigest: previous,
+		Reason:         req.Reason,
+		RequestedBy:    requestedBy,
+		RequestedAt:    p.RequestedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create promotion: %w", err)
+	}
+	if rows == 0 {
+		return nil, domain.ErrPromotionPending
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditImagePromotionRequested,
+		ActorID:      requestedBy,
+		ResourceType: "image",
+		ResourceID:   image,
+		Details: map[string]interface{}{
+			"promotion_id":    p.ID,
+			"channel":         req.Channel,
+			"digest":          p.Digest,
+			"previous_digest": previous,
+			"reason":          req.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return p, nil
+}
+
+// Approve moves the target channel to the promoted digest as the second
+// person. A target channel that moved since the request (another
+// promotion approved first) supersedes the promotion instead; it is then
+// requested again against the current state.
+func (uc *ImagePromotionUseCase) Approve(ctx context.Context, promotionID, approverID string) (*domain.ImagePromotion, error) {
+	return uc.decide(ctx, promotionID, approverID, true)
+}
+
+// Reject closes the promotion without moving the channel.
+func (uc *ImagePromotionUseCase) Reject(ctx context.Context, promotionID, approverID string) (*domain.ImagePromotion, error) {
+	return uc.decide(ctx, promotionID, approverID, false)
+}
+
+func (uc *ImagePromotionUseCase) decide(ctx context.Context, promotionID, approverID string, approve bool) (*domain.ImagePromotion, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// FOR UPDATE: two approvers racing cannot both decide
+	row, err := sqlcTx.GetImagePromotionForUpdate(ctx, promotionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get promotion: %w", err)
+	}
+	p := promotionFromRow(row)
+	if err := domain.CheckPromotionDecision(p, approverID); err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	status := domain.PromotionRejected
+	action := domain.AuditImagePromotionRejected
+	if approve {
+		status = domain.PromotionApproved
+		action = domain.AuditImagePromoted
+
+		// Compare-and-set on the digest recorded at request time
+		moved, err := sqlcTx.PromoteImageChannel(ctx, sqlc.PromoteImageChannelParams{
+			Image:          p.Image,
+			Channel:        string(p.ToChannel),
+			Repository:     p.Repository,
+			Digest:         p.Digest,
+			ExpectedDigest: p.Previous, // Empty: channel must not exist yet
+			UpdatedBy:      approverID,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("promote channel: %w", err)
+		}
+		if moved == 0 {
+			status = domain.PromotionSuperseded
+		}
+	}
+
+	if err := sqlcTx.DecideImagePromotion(ctx, sqlc.DecideImagePromotionParams{
+		ID:        p.ID,
+		Status:    string(status),
+		DecidedBy: approverID,
+		DecidedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("decide promotion: %w", err)
+	}
+
+	if status != domain.PromotionSuperseded {
+		if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+			ID:           uc.ids.NewID(),
+			Action:       action,
+			ActorID:      approverID,
+			ResourceType: "image",
+			ResourceID:   p.Image,
+			Details: map[string]interface{}{
+				"promotion_id":    p.ID,
+				"channel":         p.ToChannel,
+				"digest":          p.Digest,
+				"previous_digest": p.Previous,
+				"requested_by":    p.RequestedBy,
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("create audit log: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	if status == domain.PromotionSuperseded {
+		return nil, domain.ErrPromotionSuperseded
+	}
+
+	p.Status = status
+	p.DecidedBy = approverID
+	p.DecidedAt = &now
+	return p, nil
+}
+
+// Report lists, per digest of image, the VMs built from it, the channels
+// pointing at it and its latest scans.
+func (uc *ImagePromotionUseCase) Report(ctx context.Context, image string) ([]*domain.ImageDigestUsage, error) {
+	channels, err := uc.sqlcQueries.ListImageChannels(ctx, image)
+	if err != nil {
+		return nil, fmt.Errorf("list channels: %w", err)
+	}
+	// GROUP BY image_digest over vms of templates referencing image, not deleted
+	counts, err := uc.sqlcQueries.CountVMsByImageDigest(ctx, image)
+	if err != nil {
+		return nil, fmt.Errorf("count vms by digest: %w", err)
+	}
+
+	byDigest := make(map[string]*domain.ImageDigestUsage)
+	var report []*domain.ImageDigestUsage
+	usage := func(digest string) *domain.ImageDigestUsage {
+		u, ok := byDigest[digest]
+		if !ok {
+			u = &domain.ImageDigestUsage{Image: image, Digest: digest, Channels: []domain.ImageChannel{}}
+			byDigest[digest] = u
+			report = append(report, u)
+		}
+		return u
+	}
+	for _, c := range channels {
+		u := usage(c.Digest)
+		u.Channels = append(u.Channels, domain.ImageChannel(c.Channel))
+	}
+	for _, c := range counts {
+		usage(c.ImageDigest).VMCount = int(c.VMCount)
+	}
+
+	digests := make([]string, 0, len(report))
+	for _, u := range report {
+		digests = append(digests, u.Digest)
+	}
+	scans, err := uc.sqlcQueries.ListImageScans(ctx, digests)
+	if err != nil {
+		return nil, fmt.Errorf("list image scans: %w", err)
+	}
+	for _, s := range scans {
+		u := byDigest[s.Digest]
+		u.Scans = append(u.Scans, imageScanFromRow(s))
+	}
+	return report, nil
+}
+
+func promotionFromRow(row sqlc.ImagePromotion) *domain.ImagePromotion {
+	return &domain.ImagePromotion{
+		ID:          row.ID,
+		Image:       row.Image,
+		FromChannel: domain.ImageChannel(row.FromChannel),
+		ToChannel:   domain.ImageChannel(row.ToChannel),
+		Repository:  row.Repository,
+		Digest:      row.Digest,
+		Previous:    row.PreviousDigest,
+		Status:      domain.PromotionStatus(row.Status),
+		Reason:      row.Reason,
+		RequestedBy: row.RequestedBy,
+		RequestedAt: row.RequestedAt,
+	}
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ImageScanUseCase takes scan results from the vulnerability feed
+// (domain/image_scan.go) and applies the vulnerability policy.
+//
+// The scan and the advisory it keeps on the digest are written in one TX,
+// so the compliance report always matches the latest scan. Notifications
+// and the remediation campaign follow after commit and are best effort:
+// a failure is logged, and the VMs stay in the compliance report for an
+// admin to act on.
+type ImageScanUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	compliance  *ComplianceUseCase
+	campaigns   *RemediationCampaignUseCase
+	owners      ServiceOwnerResolver
+	notifier    domain.NotificationSender
+	policy      domain.VulnerabilityPolicy
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewImageScanUseCase creates a new use case instance. policy comes from
+// governance.vulnerability_* and is validated at startup.
+func NewImageScanUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	compliance *ComplianceUseCase,
+	campaigns *RemediationCampaignUseCase,
+	owners ServiceOwnerResolver,
+	notifier domain.NotificationSender,
+	policy domain.VulnerabilityPolicy,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ImageScanUseCase {
+	return &ImageScanUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		compliance:  compliance,
+		campaigns:   campaigns,
+		owners:      owners,
+		notifier:    notifier,
+		policy:      policy,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Record stores the scan of report.Digest by scanner, replacing its
+// previous one, and keeps, replaces or withdraws the scanner's advisory
+// on the digest. A report older than the stored scan changes nothing and
+// returns the stored scan, so out-of-order deliveries are harmless.
+func (uc *ImageScanUseCase) Record(ctx context.Context, report domain.ScanReport, scanner string) (*domain.ImageScanSummary, error) {
+	if err := report.Validate(); err != nil {
+		return nil, err
+	}
+	scan := report.Summarize(scanner, uc.clock.Now())
+	ctx = logger.WithResource(ctx, "image", report.Digest)
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// SELECT ... FOR UPDATE: serializes deliveries for the same digest and scanner
+	var previous *domain.ImageScanSummary
+	row, err := sqlcTx.GetImageScanForUpdate(ctx, sqlc.GetImageScanForUpdateParams{
+		Digest:  report.Digest,
+		Scanner: scanner,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("get image scan: %w", err)
+	default:
+		previous = imageScanFromRow(row)
+		if !report.ScannedAt.After(previous.ScannedAt) {
+			return previous, nil
+		}
+	}
+
+	var previousSeverity domain.AdvisorySeverity
+	if previous != nil {
+		previousSeverity = previous.MaxSeverity
+	}
+	escalation := uc.policy.Escalate(previousSeverity, scan.MaxSeverity)
+
+	if err := uc.keepAdvisory(ctx, sqlcTx, scan, previous, escalation.Advisory); err != nil {
+		return nil, err
+	}
+
+	if err := sqlcTx.UpsertImageScan(ctx, sqlc.UpsertImageScanParams{
+		Digest:      scan.Digest,
+		Scanner:     scan.Scanner,
+		ScannedAt:   scan.ScannedAt,
+		MaxSeverity: string(scan.MaxSeverity),
+		Counts:      scan.Counts,
+		Fixable:     int32(scan.Fixable),
+		TopFinding:  scan.TopFinding,
+		AdvisoryID:  scan.AdvisoryID,
+		Findings:    report.Findings,
+		ReceivedAt:  scan.ReceivedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("upsert image scan: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditImageScanRecorded,
+		ActorID:      scanner,
+		ResourceType: "image",
+		ResourceID:   scan.Digest,
+		Details: map[string]interface{}{
+			"scanned_at":   scan.ScannedAt,
+			"max_severity": scan.MaxSeverity,
+			"counts":       scan.Counts,
+			"advisory_id":  scan.AdvisoryID,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	if escalation.Notify {
+		uc.notifyOwners(ctx, scan)
+	}
+	if escalation.Campaign {
+		uc.startCampaign(ctx, scan)
+	}
+	return scan, nil
+}
+
+// Get returns the latest scan of digest per scanner, with findings.
+func (uc *ImageScanUseCase) Get(ctx context.Context, digest string) ([]*domain.ImageScanSummary, error) {
+	rows, err := uc.sqlcQueries.ListImageScans(ctx, []string{digest})
+	if err != nil {
+		return nil, fmt.Errorf("list image scans: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	scans := make([]*domain.ImageScanSummary, 0, len(rows))
+	for _, r := range rows {
+		s := imageScanFromRow(r)
+		s.Findings = r.Findings
+		scans = append(scans, s)
+	}
+	return scans, nil
+}
+
+// keepAdvisory sets scan.AdvisoryID. The previous advisory is kept while
+// the top finding and severity are unchanged, including when an admin
+// withdrew it (a dismissed finding stays dismissed). Otherwise it is
+// withdrawn and, if the scan still reaches the threshold, replaced.
+func (uc *ImageScanUseCase) keepAdvisory(ctx context.Context, sqlcTx *sqlc.Queries, scan, previous *domain.ImageScanSummary, keep bool) error {
+	if keep && scan.SameAdvisory(previous) {
+		scan.AdvisoryID = previous.AdvisoryID
+		return nil
+	}
+	if previous != nil && previous.AdvisoryID != "" {
+		a, err := sqlcTx.GetImageAdvisoryForUpdate(ctx, previous.AdvisoryID)
+		if err != nil {
+			return fmt.Errorf("get advisory: %w", err)
+		}
+		if a.WithdrawnAt == nil {
+			if err := withdrawAdvisory(ctx, sqlcTx, a.ID, a.Image, scan.Scanner, uc.clock, uc.ids); err != nil {
+				return err
+			}
+		}
+	}
+	if !keep {
+		return nil
+	}
+
+	a := &domain.ImageAdvisory{
+		ID:        uc.ids.NewID(),
+		Image:     scan.Digest,
+		Kind:      domain.AdvisoryVulnerable,
+		Severity:  scan.MaxSeverity,
+		Reference: scan.TopFinding,
+		Summary:   scan.AdvisorySummary(),
+		CreatedBy: scan.Scanner,
+		CreatedAt: scan.ReceivedAt,
+	}
+	if err := createAdvisory(ctx, sqlcTx, a, uc.ids); err != nil {
+		return err
+	}
+	scan.AdvisoryID = a.ID
+	return nil
+}
+
+// notifyOwners sends one notification per owner of a Service with VMs on
+// the digest, listing their VMs.
+func (uc *ImageScanUseCase) notifyOwners(ctx context.Context, scan *domain.ImageScanSummary) {
+	report, err := uc.compliance.Report(ctx, domain.ComplianceFilter{AdvisoryID: scan.AdvisoryID})
+	if err != nil {
+		logger.WarnCtx(ctx, "List vulnerable vms failed", zap.Error(err))
+		return
+	}
+
+	byService := make(map[string][]string)
+	for _, vm := range report {
+		byService[vm.ServiceID] = append(byService[vm.ServiceID], vm.Namespace+"/"+vm.Name)
+	}
+	byOwner := make(map[string][]string)
+	for serviceID, vmNames := range byService {
+		owners, err := uc.owners.ResolveOwners(ctx, serviceID)
+		if err != nil {
+			logger.WarnCtx(ctx, "Resolve service owners failed",
+				zap.String("service_id", serviceID),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, owner := range owners {
+			byOwner[owner] = append(byOwner[owner], vmNames...)
+		}
+	}
+
+	now := uc.clock.Now()
+	notifications := make([]*domain.Notification, 0, len(byOwner))
+	for owner, vmNames := range byOwner {
+		notifications = append(notifications, &domain.Notification{
+			ID:        uc.ids.NewID(),
+			Recipient: owner,
+			Type:      domain.NotificationImageVulnerable,
+			Title:     fmt.Sprintf("%s vulnerability in the image of %d VM(s)", scan.MaxSeverity, len(vmNames)),
+			Content: fmt.Sprintf("%s\nImage: %s\nAffected VMs:\n- %s",
+				scan.AdvisorySummary(), scan.Digest, strings.Join(vmNames, "\n- ")),
+			CreatedAt: now,
+		})
+	}
+	if err := uc.notifier.SendBatch(ctx, notifications); err != nil {
+		logger.WarnCtx(ctx, "Send vulnerability notifications failed", zap.Error(err))
+	}
+}
+
+// startCampaign rebuilds the VMs on the digest, as "system". Each rebuild
+// still goes through its own approval.
+func (uc *ImageScanUseCase) startCampaign(ctx context.Context, scan *domain.ImageScanSummary) {
+	c, err := uc.campaigns.Start(ctx, domain.CampaignRequest{
+		Name:         fmt.Sprintf("%s %s", scan.TopFinding, shortDigest(scan.Digest)),
+		Filter:       domain.ComplianceFilter{AdvisoryID: scan.AdvisoryID},
+		Action:       domain.RemediationRebuild,
+		Confirmation: uc.policy.CampaignConfirmation,
+		Reason:       "Vulnerability feed: " + scan.AdvisorySummary(),
+	}, "system")
+	if errors.Is(err, domain.ErrCampaignEmpty) {
+		return // No VM runs the digest
+	}
+	if err != nil {
+		logger.WarnCtx(ctx, "Start vulnerability campaign failed", zap.Error(err))
+		return
+	}
+	logger.InfoCtx(ctx, "Vulnerability campaign started",
+		zap.String("campaign_id", c.ID),
+		zap.Int("submitted", c.Submitted),
+		zap.Int("failed", c.Failed),
+	)
+}
+
+// shortDigest is "sha256:" and the first 12 hex characters.
+func shortDigest(digest string) string {
+	if len(digest) > len("sha256:")+12 {
+		return digest[:len("sha256:")+12]
+	}
+	return digest
+}
+
+func imageScanFromRow(row sqlc.ImageScan) *domain.ImageScanSummary {
+	return &domain.ImageScanSummary{
+		Digest:      row.Digest,
+		Scanner:     row.Scanner,
+		ScannedAt:   row.ScannedAt,
+		MaxSeverity: domain.AdvisorySeverity(row.MaxSeverity),
+		Counts:      row.Counts,
+		Fixable:     int(row.Fixable),
+		TopFinding:  row.TopFinding,
+		AdvisoryID:  row.AdvisoryID,
+		ReceivedAt:  row.ReceivedAt,
+	}
+}
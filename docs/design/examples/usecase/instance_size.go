@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// InstanceSizeUseCase edits InstanceSizes as immutable versions and
+// reports the VMs left on older ones (platform admin only,
+// domain/instance_size_version.go).
+//
+// An edit never rewrites a version: it writes the next one, moves the
+// size's current_version to it, audits the change and invalidates the
+// catalog cache in one TX. VMs keep the version their approval pinned.
+type InstanceSizeUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	versions    repository.InstanceSizeVersionRepository
+	riverClient *river.Client[pgx.Tx]
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewInstanceSizeUseCase creates a new use case instance.
+func NewInstanceSizeUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	versions repository.InstanceSizeVersionRepository,
+	riverClient *river.Client[pgx.Tx],
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *InstanceSizeUseCase {
+	return &InstanceSizeUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		versions:    versions,
+		riverClient: riverClient,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Update applies edit to the size named name as its next version.
+// Fails with ErrInstanceSizeVersionConflict if the size was edited since
+// edit.ExpectedVersion.
+func (uc *InstanceSizeUseCase) Update(ctx context.Context, name string, edit *domain.InstanceSizeEdit, actor string) (*domain.InstanceSize, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// FOR UPDATE: two edits of one size must get different versions
+	row, err := sqlcTx.GetInstanceSizeByNameForUpdate(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("instance size %s: %w", name, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get instance size: %w", err)
+	}
+	current := &domain.InstanceSize{
+		ID:             row.ID,
+		Name:           row.Name,
+		Version:        int(row.CurrentVersion),
+		CPUCores:       row.CPUCores,
+		Memory:         row.Memory,
+		Enabled:        row.Enabled,
+		PushToClusters: row.PushToClusters,
+		CreatedAt:      row.CreatedAt,
+	}
+
+	now := uc.clock.Now()
+	next, err := edit.Apply(current, now)
+	if err != nil {
+		return nil, err
+	}
+	version := domain.NewInstanceSizeVersion(next, actor, edit.Reason, now)
+
+	if err := sqlcTx.InsertInstanceSizeVersion(ctx, sqlc.InsertInstanceSizeVersionParams{
+		SizeID:       version.SizeID,
+		Version:      int32(version.Version),
+		Name:         version.Name,
+		CPUCores:     version.CPUCores,
+		Memory:       version.Memory,
+		Spec:         version, // JSONB, the whole version
+		ChangedBy:    version.ChangedBy,
+		ChangeReason: version.ChangeReason,
+		CreatedAt:    version.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("insert instance size version: %w", err)
+	}
+
+	// Indexed columns and spec_overrides mirror the current version
+	if err := sqlcTx.UpdateInstanceSizeToVersion(ctx, sqlc.UpdateInstanceSizeToVersionParams{
+		ID:                next.ID,
+		CurrentVersion:    int32(next.Version),
+		Description:       next.Description,
+		CPUCores:          next.CPUCores,
+		Memory:            next.Memory,
+		RequiresGPU:       next.RequiresGPU,
+		RequiresSRIOV:     next.RequiresSRIOV,
+		RequiresHugepages: next.RequiresHugepages,
+		HugepagesSize:     next.HugepagesSize,
+		DedicatedCPU:      next.DedicatedCPU,
+		GPUs:              next.GPUs,          // JSONB
+		CPUOvercommit:     next.CPUOvercommit, // JSONB
+		MemOvercommit:     next.MemOvercommit, // JSONB
+		SpecOverrides:     next.SpecOverrides, // JSONB
+		Enabled:           next.Enabled,
+		PushToClusters:    next.PushToClusters,
+		UpdatedAt:         now,
+	}); err != nil {
+		return nil, fmt.Errorf("update instance size: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditInstanceSizeUpdated,
+		ActorID:      actor,
+		ResourceType: "instance_size",
+		ResourceID:   next.ID,
+		ResourceName: next.Name,
+		Details: map[string]interface{}{
+			"from_version": current.Version,
+			"version":      next.Version,
+			"cpu_cores":    next.CPUCores,
+			"memory":       next.Memory,
+			"gpus":         next.GPUs,
+			"enabled":      next.Enabled,
+			"reason":       edit.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := repository.NotifyCatalogChanged(ctx, tx, repository.CatalogInstanceSizes); err != nil {
+		return nil, err
+	}
+
+	// Pushed instancetypes follow the current version; a size no longer
+	// pushed has its instancetype removed
+	if current.PushToClusters || next.PushToClusters {
+		if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.InstanceTypeSyncArgs{}, nil); err != nil {
+			return nil, fmt.Errorf("insert river job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return next, nil
+}
+
+// ListVersions returns the versions of the size named name, newest first.
+func (uc *InstanceSizeUseCase) ListVersions(ctx context.Context, name string) ([]*domain.InstanceSizeVersion, error) {
+	row, err := uc.sqlcQueries.GetInstanceSizeByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("instance size %s: %w", name, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get instance size: %w", err)
+	}
+	return uc.versions.ListVersions(ctx, row.ID)
+}
+
+// GetVersion returns one version of the size named name.
+func (uc *InstanceSizeUseCase) GetVersion(ctx context.Context, name string, version int) (*domain.InstanceSizeVersion, error) {
+	row, err := uc.sqlcQueries.GetInstanceSizeByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("instance size %s: %w", name, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get instance size: %w", err)
+	}
+	return uc.versions.GetVersion(ctx, row.ID, version)
+}
+
+// OutdatedVMs returns the VMs on an older version than their size's
+// current one; name narrows the report to one size, empty lists all.
+func (uc *InstanceSizeUseCase) OutdatedVMs(ctx context.Context, name string) ([]*domain.OutdatedSizeVM, error) {
+	sizeID := ""
+	if name != "" {
+		row, err := uc.sqlcQueries.GetInstanceSizeByName(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("instance size %s: %w", name, repository.ErrNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get instance size: %w", err)
+		}
+		sizeID = row.ID
+	}
+	return uc.versions.ListOutdatedVMs(ctx, sizeID)
+}
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// MigrateVMUseCase live-migrates single VMs on a platform admin's request
+// (domain/vm_migration.go). No approval: the event, the vm_migrations
+// record and the River job are written in one TX (ADR-0012), as for node
+// drains. The migration is run and tracked by MigrationHandler.
+type MigrateVMUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	vmRepo      repository.VMRepository
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewMigrateVMUseCase creates a new use case instance.
+func NewMigrateVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *MigrateVMUseCase {
+	return &MigrateVMUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		vmRepo:      vmRepo,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Submit records the migration and enqueues it.
+func (uc *MigrateVMUseCase) Submit(ctx context.Context, vmID string, req domain.MigrationRequest, requestedBy string) (*domain.VMMigration, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	if err := domain.CheckMigration(vm); err != nil {
+		return nil, err
+	}
+
+	m := &domain.VMMigration{
+		ID:          uc.ids.NewID(),
+		VMID:        vm.ID,
+		VMName:      vm.Name,
+		Namespace:   vm.Namespace,
+		Cluster:     vm.Cluster,
+		Status:      domain.VMMigrationPending,
+		Reason:      req.Reason,
+		RequestedBy: requestedBy,
+		CreatedAt:   uc.clock.Now(),
+	}
+	payload := domain.MigrationPayload{
+		MigrationID: m.ID,
+		VMID:        vm.ID,
+		Name:        vm.Name,
+		Namespace:   vm.Namespace,
+		Cluster:     vm.Cluster,
+		Reason:      req.Reason,
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status != domain.VMStatusRunning {
+		return nil, fmt.Errorf("cannot migrate a %s vm: %w", status, domain.ErrMigrationNotAllowed)
+	}
+
+	// The migration ID is the event ID: one record per event
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       m.ID,
+		EventType:     string(domain.EventVMMigrationRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventVMMigrationRequested),
+		AggregateType: "VM",
+		AggregateID:   vm.ID,
+		Status:        string(domain.EventStatusProcessing), // Admin-initiated, no approval
+		CreatedBy:     requestedBy,
+	}, payload)
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	if err := sqlcTx.CreateVMMigration(ctx, sqlc.CreateVMMigrationParams{
+		ID:          m.ID,
+		VMID:        m.VMID,
+		VMName:      m.VMName,
+		Namespace:   m.Namespace,
+		Cluster:     m.Cluster,
+		Status:      string(m.Status),
+		Reason:      m.Reason,
+		RequestedBy: m.RequestedBy,
+		CreatedAt:   m.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("create vm migration: %w", err)
+	}
+
+	if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: m.ID}, nil); err != nil {
+		return nil, fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVMMigrationRequested,
+		ActorID:      requestedBy,
+		ResourceType: "vm",
+		ResourceID:   vm.ID,
+		ResourceName: vm.Name,
+		Details: map[string]interface{}{
+			"migration_id": m.ID,
+			"cluster":      vm.Cluster,
+			"reason":       req.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return m, nil
+}
+
+// Get returns the migration with its latest recorded phase.
+func (uc *MigrateVMUseCase) Get(ctx context.Context, migrationID string) (*domain.VMMigration, error) {
+	row, err := uc.sqlcQueries.GetVMMigration(ctx, migrationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vm migration: %w", err)
+	}
+	return vmMigrationFromRow(row), nil
+}
+
+// Cancel asks to cancel the migration. One not started yet is cancelled
+// at once; a running one is cancelled by MigrationHandler on its next
+// poll, so the returned migration may still be RUNNING. Repeating the
+// request changes nothing; a finished migration returns
+// domain.ErrMigrationFinished.
+func (uc *MigrateVMUseCase) Cancel(ctx context.Context, migrationID, actor string) (*domain.VMMigration, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// SELECT ... FOR UPDATE: the worker records the VMIM under the same lock
+	row, err := sqlcTx.GetVMMigrationForUpdate(ctx, migrationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vm migration: %w", err)
+	}
+	m := vmMigrationFromRow(row)
+	if m.Status.IsTerminal() {
+		return nil, domain.ErrMigrationFinished
+	}
+	if m.CancelRequestedAt != nil {
+		return m, nil
+	}
+
+	now := uc.clock.Now()
+	m.CancelRequestedBy, m.CancelRequestedAt = actor, &now
+	if err := sqlcTx.RequestVMMigrationCancel(ctx, sqlc.RequestVMMigrationCancelParams{
+		ID:                m.ID,
+		CancelRequestedBy: actor,
+		CancelRequestedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("request cancel: %w", err)
+	}
+
+	if m.Status == domain.VMMigrationPending {
+		// Nothing runs on the cluster yet; the job finds the event terminal
+		m.Status, m.CompletedAt = domain.VMMigrationCancelled, &now
+		if _, err := sqlcTx.FinishVMMigration(ctx, sqlc.FinishVMMigrationParams{
+			ID:          m.ID,
+			Status:      string(m.Status),
+			CompletedAt: now,
+		}); err != nil {
+			return nil, fmt.Errorf("finish vm migration: %w", err)
+		}
+		result, err := domain.MigrationResult{Status: m.Status}.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+			EventID: m.ID,
+			Status:  string(domain.EventStatusCancelled),
+			Result:  result,
+		}); err != nil {
+			return nil, fmt.Errorf("update event: %w", err)
+		}
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVMMigrationCancelRequested,
+		ActorID:      actor,
+		ResourceType: "vm",
+		ResourceID:   m.VMID,
+		ResourceName: m.VMName,
+		Details: map[string]interface{}{
+			"migration_id":   m.ID,
+			"migration_name": m.MigrationName,
+			"status":         m.Status,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return m, nil
+}
+
+func vmMigrationFromRow(row sqlc.VMMigration) *domain.VMMigration {
+	return &domain.VMMigration{
+		ID:                row.ID,
+		VMID:              row.VMID,
+		VMName:            row.VMName,
+		Namespace:         row.Namespace,
+		Cluster:           row.Cluster,
+		Status:            domain.VMMigrationStatus(row.Status),
+		Phase:             row.Phase,
+		MigrationName:     row.MigrationName,
+		SourceNode:        row.SourceNode,
+		TargetNode:        row.TargetNode,
+		Reason:            row.Reason,
+		RequestedBy:       row.RequestedBy,
+		ErrorMessage:      row.ErrorMessage,
+		CancelRequestedBy: row.CancelRequestedBy,
+		CancelRequestedAt: row.CancelRequestedAt,
+		CreatedAt:         row.CreatedAt,
+		StartedAt:         row.StartedAt,
+		CompletedAt:       row.CompletedAt,
+	}
+}
@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ModifyVMAtomicUseCase handles CPU/memory resize requests with the same
+// approval flow as CreateVMAtomicUseCase (ADR-0012):
+//
+//	Execute()               → Event + Ticket (with resize plan)  → PENDING_APPROVAL
+//	ApproveAndEnqueue()     → Ticket APPROVED, quota delta, Job   → APPROVED
+//	AutoApproveAndEnqueue() → Event + Ticket + Job in one TX      → PROCESSING
+//	Submit()                → one of the two above, by approval rules
+//
+// The resize plan (live hotplug vs restart, domain.PlanResize) is stored on
+// the ticket and recomputed when an approver changes CPU/memory.
+type ModifyVMAtomicUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	vms          provider.InfrastructureProvider
+	vmRepo       repository.VMRepository
+	clusterRepo  repository.ClusterRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewModifyVMAtomicUseCase creates a new use case instance.
+func NewModifyVMAtomicUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vms provider.InfrastructureProvider,
+	vmRepo repository.VMRepository,
+	clusterRepo repository.ClusterRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ModifyVMAtomicUseCase {
+	return &ModifyVMAtomicUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		vms:          vms,
+		vmRepo:       vmRepo,
+		clusterRepo:  clusterRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		router:       router,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// ModifyVMRequest contains the resize request data.
+type ModifyVMRequest struct {
+	VMID        string // Required
+	CPU         int    // Optional: 0 keeps the current value
+	MemoryMB    int    // Optional: 0 keeps the current value
+	Reason      string // Required: business reason for request
+	RequestedBy string // Required: user who submitted the request
+
+	ResubmittedFrom string // Set by ResubmitRequestUseCase: the rejected ticket revised
+}
+
+// ModifyVMResult contains the resize request result.
+type ModifyVMResult struct {
+	EventID  string
+	TicketID string
+	Plan     domain.ResizePlan
+	Route    *domain.ApprovalRoute
+}
+
+// modifyRequest is a validated request ready to be written.
+type modifyRequest struct {
+	payload  domain.VMModifyPayload
+	plan     domain.ResizePlan
+	decision *domain.EnvironmentDecision
+	route    *domain.ApprovalRoute
+}
+
+// Submit routes the resize by the approval rules: auto-approved resizes
+// are enqueued at once, all others get a ticket.
+func (uc *ModifyVMAtomicUseCase) Submit(ctx context.Context, req ModifyVMRequest) (*ModifyVMResult, error) {
+	m, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if m.route.AutoApprove {
+		return uc.autoApprove(ctx, req, m)
+	}
+	return uc.execute(ctx, req, m)
+}
+
+// Execute records the resize request for approval.
+func (uc *ModifyVMAtomicUseCase) Execute(ctx context.Context, req ModifyVMRequest) (*ModifyVMResult, error) {
+	m, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return uc.execute(ctx, req, m)
+}
+
+func (uc *ModifyVMAtomicUseCase) execute(ctx context.Context, req ModifyVMRequest, m *modifyRequest) (*ModifyVMResult, error) {
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	approvers, err := uc.approvers.Resolve(ctx, ticketID, m.payload.ServiceID, req.RequestedBy, m.decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("resolve approvers: %w", err)
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := uc.createRequest(ctx, sqlcTx, m, req, eventID, ticketID, false, approvers); err != nil {
+		return nil, err
+	}
+
+	// No River Job before approval (ADR-0006)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &ModifyVMResult{EventID: eventID, TicketID: ticketID, Plan: m.plan, Route: m.route}, nil
+}
+
+// ApproveAndEnqueue records an approval; modifiedSpec may change CPU/memory
+// (the plan is recomputed and earlier approvals are reset). The last
+// required approval reserves the quota increase and inserts the River job
+// in the same transaction. executeAt, when set, plans the resize for later.
+func (uc *ModifyVMAtomicUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	// Recompute the plan before the TX: it reads the VM from the cluster
+	// (ADR-0012: no K8s calls in TX)
+	var replanned *domain.ResizePlan
+	if modifiedSpec != nil {
+		plan, err := uc.replan(ctx, ticketID, modifiedSpec)
+		if err != nil {
+			return nil, err
+		}
+		replanned = plan
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, modifiedSpec != nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+
+	specJSON, err := modifiedSpec.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	if modifiedSpec != nil {
+		planJSON, err := replanned.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		err = sqlcTx.UpdateApprovalTicketModifiedSpec(ctx, sqlc.UpdateApprovalTicketModifiedSpecParams{
+			TicketID:     ticketID,
+			ModifiedSpec: specJSON,
+			ResizePlan:   planJSON,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("update modified spec: %w", err)
+		}
+	} else {
+		specJSON = ticket.ModifiedSpec
+	}
+
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	payload, err := effectiveEventPayload[domain.VMModifyPayload](event, specJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+		ModifiedSpec:       specJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	if err := uc.enqueue(ctx, tx, sqlcTx, ticketID, ticket.EventID, payload, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// AutoApproveAndEnqueue resizes without human approval when the approval
+// rules allow the resulting size, within the environment policy (e.g.
+// small dev VMs).
+func (uc *ModifyVMAtomicUseCase) AutoApproveAndEnqueue(ctx context.Context, req ModifyVMRequest) (*ModifyVMResult, error) {
+	m, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !m.route.AutoApprove {
+		return nil, fmt.Errorf("%s: %w", m.route.Reason, domain.ErrApprovalRequired)
+	}
+	return uc.autoApprove(ctx, req, m)
+}
+
+func (uc *ModifyVMAtomicUseCase) autoApprove(ctx context.Context, req ModifyVMRequest, m *modifyRequest) (*ModifyVMResult, error) {
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	// ========== Single Atomic Transaction (ADR-0012 True ACID) ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := uc.createRequest(ctx, sqlcTx, m, req, eventID, ticketID, true, nil); err != nil {
+		return nil, err
+	}
+	if err := uc.enqueue(ctx, tx, sqlcTx, ticketID, eventID, m.payload, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &ModifyVMResult{EventID: eventID, TicketID: ticketID, Plan: m.plan, Route: m.route}, nil
+}
+
+// prepare reads the VM from the cluster (current size, resourceVersion,
+// migratability), plans the resize and evaluates the environment policy
+// and approval rules for the resulting size.
+func (uc *ModifyVMAtomicUseCase) prepare(ctx context.Context, req ModifyVMRequest) (*modifyRequest, error) {
+	rec, err := uc.vmRepo.Get(ctx, req.VMID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	vm, err := uc.vms.GetVM(ctx, rec.Cluster, rec.Namespace, rec.Name)
+	if err != nil {
+		return nil, fmt.Errorf("read vm from cluster: %w", err)
+	}
+
+	p := domain.VMModifyPayload{
+		VMID:                    rec.ID,
+		Name:                    vm.Name,
+		Namespace:               vm.Namespace,
+		Cluster:                 vm.Cluster,
+		ServiceID:               rec.ServiceID,
+		FromCPU:                 vm.CPU,
+		FromMemoryMB:            vm.MemoryMB,
+		CPU:                     vm.CPU,
+		MemoryMB:                vm.MemoryMB,
+		ExpectedResourceVersion: vm.ResourceVersion,
+		Reason:                  req.Reason,
+	}
+	if req.CPU != 0 {
+		p.CPU = req.CPU
+	}
+	if req.MemoryMB != 0 {
+		p.MemoryMB = req.MemoryMB
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	caps, err := uc.clusterRepo.GetCapabilities(ctx, vm.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get cluster capabilities: %w", err)
+	}
+
+	decision, err := uc.environments.Decide(ctx, p.ServiceID, p.Namespace, &domain.VMCreationPayload{
+		ServiceID: p.ServiceID,
+		CPU:       p.CPU,
+		MemoryMB:  p.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := uc.router.Route(ctx, p.ServiceID, req.RequestedBy, domain.ApprovalRequest{
+		RequestType: "MODIFY_VM",
+		Namespace:   p.Namespace,
+		CPU:         p.CPU,
+		MemoryMB:    p.MemoryMB,
+	}, decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+
+	return &modifyRequest{
+		payload:  p,
+		plan:     domain.PlanResize(vm, p.CPU, p.MemoryMB, caps),
+		decision: decision,
+		route:    route,
+	}, nil
+}
+
+// replan recomputes the resize plan for an approver's modification.
+func (uc *ModifyVMAtomicUseCase) replan(ctx context.Context, ticketID string, mods *domain.ModifiedSpec) (*domain.ResizePlan, error) {
+	ticket, err := uc.sqlcQueries.GetApprovalTicket(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	event, err := uc.sqlcQueries.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	modsJSON, err := mods.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := effectiveEventPayload[domain.VMModifyPayload](event, modsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := uc.vms.GetVM(ctx, payload.Cluster, payload.Namespace, payload.Name)
+	if err != nil {
+		return nil, fmt.Errorf("read vm from cluster: %w", err)
+	}
+	caps, err := uc.clusterRepo.GetCapabilities(ctx, payload.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get cluster capabilities: %w", err)
+	}
+	plan := domain.PlanResize(vm, payload.CPU, payload.MemoryMB, caps)
+	return &plan, nil
+}
+
+// createRequest locks the VM and writes the modify event, ticket,
+// approvers and audit record.
+func (uc *ModifyVMAtomicUseCase) createRequest(
+	ctx context.Context,
+	sqlcTx *sqlc.Queries,
+	m *modifyRequest,
+	req ModifyVMRequest,
+	eventID, ticketID string,
+	autoApprove bool,
+	approvers []*domain.TicketApprover,
+) error {
+	if _, err := lockVM(ctx, sqlcTx, m.payload.VMID); err != nil {
+		return err
+	}
+
+	planJSON, err := m.plan.ToJSON()
+	if err != nil {
+		return err
+	}
+	return writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:         eventID,
+		ticketID:        ticketID,
+		vmID:            m.payload.VMID,
+		vmName:          m.payload.Name,
+		serviceID:       m.payload.ServiceID,
+		requestType:     "MODIFY_VM",
+		eventType:       domain.EventVMModifyRequested,
+		payload:         m.payload,
+		reason:          req.Reason,
+		requestedBy:     req.RequestedBy,
+		resubmittedFrom: req.ResubmittedFrom,
+		resizePlan:      planJSON,
+		decision:        *m.decision, // Growing into a large VM may add stages
+		autoApprove:     autoApprove,
+		approvers:       approvers,
+		auditAction:     domain.AuditVMModifyRequested,
+		auditDetails: map[string]interface{}{
+			"cpu":         []int{m.payload.FromCPU, m.payload.CPU},
+			"memory_mb":   []int{m.payload.FromMemoryMB, m.payload.MemoryMB},
+			"resize_mode": m.plan.Mode,
+		},
+	})
+}
+
+// enqueue re-checks quota and headroom for the increase, reserves it,
+// moves the event to PROCESSING and inserts the River job (scheduled at
+// executeAt if set), all in the caller's transaction.
+func (uc *ModifyVMAtomicUseCase) enqueue(ctx context.Context, tx pgx.Tx, sqlcTx *sqlc.Queries, ticketID, eventID string, p domain.VMModifyPayload, executeAt *time.Time) error {
+	err := sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: eventID,
+		Status:  string(domain.EventStatusProcessing),
+	})
+	if err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+
+	r := domain.NewResizeReservation(uc.ids.NewID(), ticketID, eventID, p)
+
+	// The increase must still fit the quota and the VM's own cluster; a
+	// resize keeps the VM's GPUs, so they are not checked again
+	clusters, err := clusterHeadroom(ctx, sqlcTx, p.Cluster)
+	if err != nil {
+		return err
+	}
+	if err := requireCapacity(ctx, sqlcTx, ticketID, r, nil, clusters); err != nil {
+		return err
+	}
+
+	err = sqlcTx.CreateQuotaReservation(ctx, sqlc.CreateQuotaReservationParams{
+		ID:        r.ID,
+		ServiceID: r.ServiceID,
+		TicketID:  r.TicketID,
+		EventID:   r.EventID,
+		CPU:       r.CPU,
+		MemoryMB:  r.MemoryMB,
+		DiskGB:    r.DiskGB,
+		VMCount:   r.VMCount,
+		Status:    string(r.Status),
+	})
+	if err != nil {
+		return fmt.Errorf("reserve quota: %w", err)
+	}
+
+	return insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, eventID, domain.QueueNormal, executeAt)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/render"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// NamespaceBaselineUseCase manages the per-environment namespace
+// baselines (platform admin only, domain/namespace_baseline.go).
+//
+// Saving a baseline bumps its version and enqueues NamespaceBaselineSyncArgs
+// in the same TX, so every namespace of the environment converges on the
+// new objects without waiting for the periodic sync.
+type NamespaceBaselineUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewNamespaceBaselineUseCase creates a new use case instance.
+func NewNamespaceBaselineUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *NamespaceBaselineUseCase {
+	return &NamespaceBaselineUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Get returns the environment's baseline; one never saved is empty, at
+// version 0.
+func (uc *NamespaceBaselineUseCase) Get(ctx context.Context, environment string) (*domain.NamespaceBaseline, error) {
+	row, err := uc.sqlcQueries.GetNamespaceBaseline(ctx, environment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		b := &domain.NamespaceBaseline{Environment: environment, Objects: []domain.NamespaceBaselineObject{}}
+		return b, b.Validate() // Rejects an unknown environment
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get namespace baseline: %w", err)
+	}
+	return &domain.NamespaceBaseline{
+		Environment: row.Environment,
+		Objects:     row.Objects, // JSONB
+		Version:     int(row.Version),
+		UpdatedBy:   row.UpdatedBy,
+		UpdatedAt:   row.UpdatedAt,
+	}, nil
+}
+
+// Update validates and saves the environment's objects, replacing the
+// previous set.
+func (uc *NamespaceBaselineUseCase) Update(ctx context.Context, environment string, objects []domain.NamespaceBaselineObject, actor string) (*domain.NamespaceBaseline, error) {
+	b := &domain.NamespaceBaseline{
+		Environment: environment,
+		Objects:     objects,
+		UpdatedBy:   actor,
+		UpdatedAt:   uc.clock.Now(),
+	}
+	if err := render.ValidateBaseline(b); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// INSERT ... ON CONFLICT (environment) DO UPDATE SET version = version + 1
+	version, err := sqlcTx.UpsertNamespaceBaseline(ctx, sqlc.UpsertNamespaceBaselineParams{
+		Environment: b.Environment,
+		Objects:     b.Objects, // JSONB
+		UpdatedBy:   b.UpdatedBy,
+		UpdatedAt:   b.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save namespace baseline: %w", err)
+	}
+	b.Version = int(version)
+
+	kinds := make([]string, 0, len(b.Objects))
+	for _, o := range b.Objects {
+		kinds = append(kinds, string(o.Kind)+"/"+o.Name)
+	}
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditNamespaceBaselineUpdated,
+		ActorID:      actor,
+		ResourceType: "namespace_baseline",
+		ResourceID:   b.Environment,
+		Details: map[string]interface{}{
+			"version": b.Version,
+			"objects": kinds,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.NamespaceBaselineSyncArgs{}, nil); err != nil {
+		return nil, fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// PolicyEvaluator compiles and evaluates policy expressions. Implemented
+// by service.PolicyEngine.
+type PolicyEvaluator interface {
+	Check(expression string) error
+	Evaluate(policies []*domain.PolicyExpression, in *domain.PolicyInput) []domain.PolicyResult
+}
+
+// PolicyExpressionUseCase creates and edits policy expressions as
+// immutable versions, and dry-runs drafts (platform admin only,
+// domain/policy_expression.go).
+//
+// As for InstanceSizes, an edit never rewrites a version: it writes the
+// next one, moves the policy's current version to it and audits the change
+// in one TX. Routing reads the current versions on the next submission.
+type PolicyExpressionUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	policies    repository.PolicyExpressionRepository
+	evaluator   PolicyEvaluator
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewPolicyExpressionUseCase creates a new use case instance.
+func NewPolicyExpressionUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	policies repository.PolicyExpressionRepository,
+	evaluator PolicyEvaluator,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *PolicyExpressionUseCase {
+	return &PolicyExpressionUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		policies:    policies,
+		evaluator:   evaluator,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Create saves p as version 1 of a new policy. The expression must compile.
+func (uc *PolicyExpressionUseCase) Create(ctx context.Context, p *domain.PolicyExpression, actor string) (*domain.PolicyExpression, error) {
+	created := *p
+	created.ID = uc.ids.NewID()
+	created.Version = 1
+	created.UpdatedBy = actor
+	created.UpdatedAt = uc.clock.Now()
+	if err := created.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.evaluator.Check(created.Expression); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// UNIQUE (name): ON CONFLICT DO NOTHING returns 0 rows
+	rows, err := sqlcTx.InsertPolicyExpression(ctx, sqlc.InsertPolicyExpressionParams{
+		ID:             created.ID,
+		Name:           created.Name,
+		Kind:           string(created.Kind),
+		Language:       string(created.Language),
+		Expression:     created.Expression,
+		Priority:       int32(created.Priority),
+		Enabled:        created.Enabled,
+		Action:         string(created.Action),
+		Message:        created.Message,
+		CurrentVersion: 1,
+		UpdatedBy:      actor,
+		UpdatedAt:      created.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("insert policy expression: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("policy %s: %w", created.Name, domain.ErrPolicyExpressionNameTaken)
+	}
+	if err := uc.insertVersion(ctx, sqlcTx, &domain.PolicyExpressionVersion{PolicyExpression: created, ChangeReason: "created"}); err != nil {
+		return nil, err
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditPolicyExpressionCreated,
+		ActorID:      actor,
+		ResourceType: "policy_expression",
+		ResourceID:   created.ID,
+		ResourceName: created.Name,
+		Details: map[string]interface{}{
+			"kind":       created.Kind,
+			"expression": created.Expression,
+			"priority":   created.Priority,
+			"enabled":    created.Enabled,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &created, nil
+}
+
+// Update applies edit to the policy id as its next version. Fails with
+// ErrPolicyExpressionVersionConflict if the policy was edited since
+// edit.ExpectedVersion.
+func (uc *PolicyExpressionUseCase) Update(ctx context.Context, id string, edit *domain.PolicyExpressionEdit, actor string) (*domain.PolicyExpression, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// FOR UPDATE: two edits of one policy must get different versions
+	row, err := sqlcTx.GetPolicyExpressionForUpdate(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("policy expression %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get policy expression: %w", err)
+	}
+	current := &domain.PolicyExpression{
+		ID:         row.ID,
+		Name:       row.Name,
+		Kind:       domain.PolicyKind(row.Kind),
+		Language:   domain.PolicyLanguage(row.Language),
+		Expression: row.Expression,
+		Priority:   int(row.Priority),
+		Enabled:    row.Enabled,
+		Action:     domain.ApprovalAction(row.Action),
+		Message:    row.Message,
+		Version:    int(row.CurrentVersion),
+		UpdatedBy:  row.UpdatedBy,
+		UpdatedAt:  row.UpdatedAt,
+	}
+
+	next, err := edit.Apply(current, actor, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.evaluator.Check(next.Expression); err != nil {
+		return nil, err
+	}
+	if err := uc.insertVersion(ctx, sqlcTx, &domain.PolicyExpressionVersion{PolicyExpression: *next, ChangeReason: edit.Reason}); err != nil {
+		return nil, err
+	}
+
+	if err := sqlcTx.UpdatePolicyExpressionToVersion(ctx, sqlc.UpdatePolicyExpressionToVersionParams{
+		ID:             next.ID,
+		CurrentVersion: int32(next.Version),
+		Expression:     next.Expression,
+		Priority:       int32(next.Priority),
+		Enabled:        next.Enabled,
+		Action:         string(next.Action),
+		Message:        next.Message,
+		UpdatedBy:      actor,
+		UpdatedAt:      next.UpdatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("update policy expression: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditPolicyExpressionUpdated,
+		ActorID:      actor,
+		ResourceType: "policy_expression",
+		ResourceID:   next.ID,
+		ResourceName: next.Name,
+		Details: map[string]interface{}{
+			"from_version": current.Version,
+			"version":      next.Version,
+			"expression":   next.Expression,
+			"priority":     next.Priority,
+			"enabled":      next.Enabled,
+			"reason":       edit.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return next, nil
+}
+
+// insertVersion writes v; policy_expression_versions rejects UPDATE and
+// DELETE.
+func (uc *PolicyExpressionUseCase) insertVersion(ctx context.Context, sqlcTx *sqlc.Queries, v *domain.PolicyExpressionVersion) error {
+	if err := sqlcTx.InsertPolicyExpressionVersion(ctx, sqlc.InsertPolicyExpressionVersionParams{
+		PolicyID:     v.ID,
+		Version:      int32(v.Version),
+		Spec:         v, // JSONB, the whole version
+		ChangedBy:    v.UpdatedBy,
+		ChangeReason: v.ChangeReason,
+		CreatedAt:    v.UpdatedAt,
+	}); err != nil {
+		return fmt.Errorf("insert policy expression version: %w", err)
+	}
+	return nil
+}
+
+// List returns every policy at its current version.
+func (uc *PolicyExpressionUseCase) List(ctx context.Context) ([]*domain.PolicyExpression, error) {
+	return uc.policies.List(ctx)
+}
+
+// ListVersions returns the versions of the policy id, newest first.
+func (uc *PolicyExpressionUseCase) ListVersions(ctx context.Context, id string) ([]*domain.PolicyExpressionVersion, error) {
+	return uc.policies.ListVersions(ctx, id)
+}
+
+// DryRun evaluates the draft against each input, in order. The draft is
+// checked as if saved, but neither saved nor audited.
+func (uc *PolicyExpressionUseCase) DryRun(ctx context.Context, run *domain.PolicyDryRun) ([]domain.PolicyResult, error) {
+	if err := run.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.evaluator.Check(run.Policy.Expression); err != nil {
+		return nil, err
+	}
+
+	// No ID: the engine does not cache the draft's program
+	draft := run.Policy
+	draft.ID, draft.Version = "", 0
+	results := make([]domain.PolicyResult, len(run.Inputs))
+	for i := range run.Inputs {
+		results[i] = uc.evaluator.Evaluate([]*domain.PolicyExpression{&draft}, &run.Inputs[i])[0]
+	}
+	return results, nil
+}
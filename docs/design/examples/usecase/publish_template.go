@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/render"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// TemplateImageResolver resolves the image a template builds from.
+// Implemented by service.ImageSourceResolver.
+type TemplateImageResolver interface {
+	Resolve(ctx context.Context, t *domain.Template) (source, digest string, err error)
+}
+
+// PublishTemplateUseCase moves a draft template to active (ADR-0007).
+//
+// Gates, in order:
+//  1. Golden cases: the draft must have some and all of them must match the
+//     current render, i.e. the admin has previewed and accepted every
+//     output change.
+//  2. Lint (render.Lint): schema, platform-managed fields (ADR-0015 §4)
+//     and cloud-init syntax.
+//  3. Dry-run: the VM rendered for the first golden case is validated
+//     server-side on the reference cluster (ADR-0011); nothing is created.
+//
+// The previous active version is deprecated in the same transaction
+// (only one active per name).
+type PublishTemplateUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	templateRepo repository.TemplateRepository
+	images       TemplateImageResolver
+	vms          provider.InfrastructureProvider // Narrow interfaces (ADR-0024)
+	dryRun       TemplateDryRunTarget
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// TemplateDryRunTarget is where templates are dry-run before publishing
+// (k8s.template_dry_run_*). An empty Cluster skips the dry-run.
+type TemplateDryRunTarget struct {
+	Cluster   string
+	Namespace string
+}
+
+// Sizing of the dry-run VM. Sizes come from InstanceSizes at request
+// time, so a small fixed shape is enough to exercise the template.
+const (
+	dryRunCPU      = 1
+	dryRunMemoryMB = 1024
+)
+
+// NewPublishTemplateUseCase creates a new use case instance.
+func NewPublishTemplateUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	templateRepo repository.TemplateRepository,
+	images TemplateImageResolver,
+	vms provider.InfrastructureProvider,
+	dryRun TemplateDryRunTarget,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *PublishTemplateUseCase {
+	return &PublishTemplateUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		templateRepo: templateRepo,
+		images:       images,
+		vms:          vms,
+		dryRun:       dryRun,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// TemplateChecks is the outcome of the publish gates, returned on failure
+// so the editor can show the diff or the findings.
+type TemplateChecks struct {
+	Golden   []render.GoldenResult `json:"golden_results"`
+	Findings []render.LintFinding  `json:"findings,omitempty"`
+}
+
+// Execute publishes the draft.
+func (uc *PublishTemplateUseCase) Execute(ctx context.Context, templateID, actor string) (*TemplateChecks, error) {
+	t, err := uc.templateRepo.Get(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("get template: %w", err)
+	}
+	if !t.Editable() {
+		return nil, domain.ErrTemplateNotDraft
+	}
+	if len(t.GoldenCases) == 0 {
+		return nil, domain.ErrTemplateNoGoldenCases
+	}
+
+	checks := &TemplateChecks{Golden: render.CheckGolden(t)}
+	if !render.AllPassed(checks.Golden) {
+		return checks, domain.ErrTemplateGoldenMismatch
+	}
+	checks.Findings = render.Lint(t, t.GoldenCases[0].Vars)
+	if len(checks.Findings) == 0 {
+		if checks.Findings, err = uc.dryRunTemplate(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+	if len(checks.Findings) > 0 {
+		return checks, domain.ErrTemplateLintFailed
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Deprecate first: a partial unique index allows one active version per name.
+	// Existing VMs on the previous version keep running.
+	if err := sqlcTx.DeprecateActiveTemplates(ctx, sqlc.DeprecateActiveTemplatesParams{
+		Name:     t.Name,
+		ExceptID: t.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("deprecate previous version: %w", err)
+	}
+
+	// Conditional on status = 'draft' so two concurrent publishes cannot both win
+	rows, err := sqlcTx.ActivateTemplate(ctx, sqlc.ActivateTemplateParams{
+		ID:          t.ID,
+		PublishedAt: uc.clock.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("activate template: %w", err)
+	}
+	if rows == 0 {
+		return nil, domain.ErrTemplateNotDraft
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditTemplatePublished,
+		ActorID:      actor,
+		ResourceType: "template",
+		ResourceID:   t.ID,
+		ResourceName: t.Name,
+		Details: map[string]interface{}{
+			"version":      t.Version,
+			"golden_cases": len(t.GoldenCases),
+			"dry_run":      uc.dryRun.Cluster, // Empty: skipped
+			"content_hash": render.Hash(t.CloudInit + "\n---\n" + t.Manifest),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+	if err := repository.NotifyCatalogChanged(ctx, tx, repository.CatalogTemplates); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return checks, nil
+}
+
+// dryRunTemplate validates the VM rendered for the first golden case on
+// the reference cluster. A rejected spec becomes findings; an unreachable
+// cluster is an error, so publishing waits rather than skipping the gate.
+func (uc *PublishTemplateUseCase) dryRunTemplate(ctx context.Context, t *domain.Template) ([]render.LintFinding, error) {
+	if uc.dryRun.Cluster == "" {
+		return nil, nil
+	}
+	source, digest, err := uc.images.Resolve(ctx, t)
+	if errors.Is(err, domain.ErrImageChannelEmpty) {
+		return []render.LintFinding{{Check: render.LintDryRun, Path: "image_channel", Message: err.Error()}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve image: %w", err)
+	}
+	cloudInit, manifest, err := render.RenderTemplate(t, t.GoldenCases[0].Vars)
+	if err != nil {
+		return nil, err // Linted already
+	}
+
+	result, err := uc.vms.ValidateSpec(ctx, uc.dryRun.Cluster, uc.dryRun.Namespace, &domain.VMSpec{
+		CPU:         dryRunCPU,
+		MemoryMB:    dryRunMemoryMB,
+		Template:    t.Name,
+		ImageSource: source,
+		ImageDigest: digest,
+		CloudInit:   domain.CloudInit{UserData: cloudInit},
+		Manifest:    manifest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dry-run template on %s: %w", uc.dryRun.Cluster, err)
+	}
+	if result.Valid {
+		return nil, nil
+	}
+	if len(result.Errors) == 0 {
+		result.Errors = []string{"rejected by the reference cluster"}
+	}
+	findings := make([]render.LintFinding, 0, len(result.Errors))
+	for _, msg := range result.Errors {
+		findings = append(findings, render.LintFinding{Check: render.LintDryRun, Message: msg})
+	}
+	return findings, nil
+}
+
+// AcceptGolden records the current render as the reviewed expectation for
+// every golden case of a draft. Returns the diff that was accepted.
+func (uc *PublishTemplateUseCase) AcceptGolden(ctx context.Context, templateID, actor string) ([]render.GoldenResult, error) {
+	t, err := uc.templateRepo.Get(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("get template: %w", err)
+	}
+	if !t.Editable() {
+		return nil, domain.ErrTemplateNotDraft
+	}
+
+	accepted := render.CheckGolden(t)
+	if err := render.AcceptGolden(t); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	rows, err := sqlcTx.UpdateDraftTemplateGoldenCases(ctx, sqlc.UpdateDraftTemplateGoldenCasesParams{
+		ID:          t.ID,
+		GoldenCases: t.GoldenCases, // JSONB
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update golden cases: %w", err)
+	}
+	if rows == 0 {
+		return nil, domain.ErrTemplateNotDraft
+	}
+
+	changed := 0
+	for _, r := range accepted {
+		if !r.Passed {
+			changed++
+		}
+	}
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditTemplateGoldenAccepted,
+		ActorID:      actor,
+		ResourceType: "template",
+		ResourceID:   t.ID,
+		ResourceName: t.Name,
+		Details:      map[string]interface{}{"version": t.Version, "changed_cases": changed},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+	if err := repository.NotifyCatalogChanged(ctx, tx, repository.CatalogTemplates); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return accepted, nil
+}
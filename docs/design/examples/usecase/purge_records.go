@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// purgeBatchSize bounds each DELETE, so a large backlog never holds
+// locks for long. A run repeats batches until none is full or the job
+// times out; the next run continues.
+const purgeBatchSize = 1000
+
+// PurgeRecordsUseCase deletes the records of one type older than its
+// retention window (domain/retention.go). Run by the retention_purge
+// periodic job, once per record type.
+//
+// Every purge query skips records covered by an active legal hold
+// (held_resources view, Phase 4 §7). Batches commit on their own: a
+// failed run keeps what it already purged. Each run that deleted
+// anything is audited as retention.purged.
+//
+// With the audit archive enabled, only audit logs at or before the last
+// archived batch's cursor are purged (domain/audit_archive.go), so an
+// unreachable bucket delays the purge instead of losing records.
+type PurgeRecordsUseCase struct {
+	sqlcQueries    *sqlc.Queries
+	recordings     domain.ConsoleRecordingStore
+	auditArchiveOn bool // audit_archive.enabled
+	clock          domain.Clock
+	ids            domain.IDGenerator
+}
+
+// NewPurgeRecordsUseCase creates a new use case instance.
+func NewPurgeRecordsUseCase(
+	sqlcQueries *sqlc.Queries,
+	recordings domain.ConsoleRecordingStore,
+	auditArchiveOn bool,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *PurgeRecordsUseCase {
+	return &PurgeRecordsUseCase{
+		sqlcQueries:    sqlcQueries,
+		recordings:     recordings,
+		auditArchiveOn: auditArchiveOn,
+		clock:          clock,
+		ids:            ids,
+	}
+}
+
+// Execute purges expired records of recordType and returns how many it
+// deleted.
+func (uc *PurgeRecordsUseCase) Execute(ctx context.Context, recordType domain.RetentionRecordType) (int64, error) {
+	if !recordType.Known() {
+		return 0, fmt.Errorf("record type %q: %w", recordType, domain.ErrUnknownRecordType)
+	}
+	policy, err := uc.policy(ctx, recordType)
+	if err != nil {
+		return 0, err
+	}
+	now := uc.clock.Now()
+	cutoff := policy.Cutoff(now)
+
+	var total int64
+	for {
+		if ctx.Err() != nil {
+			break // Timeout or shutdown: keep what was purged, audit it
+		}
+		n, err := uc.purgeBatch(ctx, recordType, cutoff, now)
+		total += n
+		if err != nil {
+			uc.audit(ctx, policy, cutoff, total)
+			return total, fmt.Errorf("purge %s: %w", recordType, err)
+		}
+		if n < purgeBatchSize {
+			break
+		}
+	}
+	uc.audit(ctx, policy, cutoff, total)
+	return total, nil
+}
+
+// policy returns the admin-set policy, or the default if none is set.
+func (uc *PurgeRecordsUseCase) policy(ctx context.Context, recordType domain.RetentionRecordType) (domain.RetentionPolicy, error) {
+	p := domain.DefaultRetentionPolicy(recordType)
+	row, err := uc.sqlcQueries.GetRetentionPolicy(ctx, string(recordType))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("get retention policy: %w", err)
+	}
+	p.WindowDays = int(row.WindowDays)
+	p.Default = false
+	// Re-checked: a minimum raised after the policy was saved wins
+	if p.WindowDays < p.MinDays {
+		p.WindowDays = p.MinDays
+	}
+	return p, nil
+}
+
+// purgeBatch deletes up to purgeBatchSize records created before cutoff.
+func (uc *PurgeRecordsUseCase) purgeBatch(ctx context.Context, recordType domain.RetentionRecordType, cutoff, now time.Time) (int64, error) {
+	switch recordType {
+	case domain.RetentionEvents:
+		// Terminal statuses only; events of held tickets and VMs are skipped
+		n, err := uc.sqlcQueries.PurgeDomainEvents(ctx, sqlc.PurgeDomainEventsParams{
+			Cutoff: cutoff,
+			Limit:  purgeBatchSize,
+		})
+		if err != nil || n == purgeBatchSize {
+			return n, err
+		}
+		// Then the archive (event_archive.go), same window and holds
+		archived, err := uc.sqlcQueries.PurgeArchivedDomainEvents(ctx, sqlc.PurgeArchivedDomainEventsParams{
+			Cutoff: cutoff,
+			Limit:  purgeBatchSize - n,
+		})
+		return n + archived, err
+	case domain.RetentionAuditLogs:
+		return uc.sqlcQueries.PurgeAuditLogs(ctx, sqlc.PurgeAuditLogsParams{
+			Cutoff:            cutoff,
+			SensitiveCutoff:   now.Add(-domain.SensitiveAuditRetention),
+			SensitivePatterns: domain.SensitiveAuditPatterns,
+			ArchivedOnly:      uc.auditArchiveOn,
+			Limit:             purgeBatchSize,
+		})
+	case domain.RetentionNotifications:
+		return uc.sqlcQueries.PurgeNotifications(ctx, sqlc.PurgeNotificationsParams{
+			Cutoff: cutoff,
+			Limit:  purgeBatchSize,
+		})
+	case domain.RetentionJobHistory:
+		// completed, cancelled, discarded: River's own cleaner is off
+		return uc.sqlcQueries.PurgeJobHistory(ctx, sqlc.PurgeJobHistoryParams{
+			Cutoff: cutoff,
+			Limit:  purgeBatchSize,
+		})
+	case domain.RetentionConsoleRecordings:
+		return uc.purgeRecordings(ctx, cutoff)
+	case domain.RetentionAPIUsage:
+		return uc.sqlcQueries.PurgeAPIUsage(ctx, sqlc.PurgeAPIUsageParams{
+			Cutoff: cutoff,
+			Limit:  purgeBatchSize,
+		})
+	default:
+		return 0, domain.ErrUnknownRecordType
+	}
+}
+
+// purgeRecordings deletes the blobs, then the rows: rows whose blobs
+// could not be deleted stay listed and are retried on the next run.
+func (uc *PurgeRecordsUseCase) purgeRecordings(ctx context.Context, cutoff time.Time) (int64, error) {
+	expired, err := uc.sqlcQueries.ListExpiredConsoleRecordings(ctx, sqlc.ListExpiredConsoleRecordingsParams{
+		Cutoff: cutoff,
+		Limit:  purgeBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list expired recordings: %w", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, 0, len(expired))
+	keys := make([]string, 0, len(expired))
+	for _, r := range expired {
+		ids = append(ids, r.ID)
+		keys = append(keys, r.StorageKey)
+	}
+	if err := uc.recordings.Delete(ctx, keys); err != nil {
+		return 0, fmt.Errorf("delete recording blobs: %w", err)
+	}
+	n, err := uc.sqlcQueries.DeleteConsoleRecordings(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("delete recordings: %w", err)
+	}
+	return n, nil
+}
+
+// audit records the run. Autocommit and best effort: the records are
+// already gone and the audit must not fail the job into re-purging.
+// Written even when the run timed out, hence WithoutCancel.
+func (uc *PurgeRecordsUseCase) audit(ctx context.Context, policy domain.RetentionPolicy, cutoff time.Time, deleted int64) {
+	if deleted == 0 {
+		return
+	}
+	err := uc.sqlcQueries.CreateAuditLog(context.WithoutCancel(ctx), sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditRetentionPurged,
+		ActorID:      "system",
+		ResourceType: "retention_policy",
+		ResourceID:   string(policy.RecordType),
+		Details: map[string]interface{}{
+			"window_days": policy.WindowDays,
+			"cutoff":      cutoff,
+			"deleted":     deleted,
+		},
+	})
+	if err != nil {
+		logger.WarnCtx(ctx, "Create retention purge audit log failed",
+			zap.String("record_type", string(policy.RecordType)),
+			zap.Int64("deleted", deleted),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,360 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RelocateVMUseCase moves single VMs to another cluster on a platform
+// admin's request (domain/vm_relocation.go). No approval, as for live
+// migrations: the event, the vm_relocations record and the River job are
+// written in one TX (ADR-0012). VMRelocationHandler runs the steps; the
+// admin supervises them here: progress, confirming the cutover, aborting.
+type RelocateVMUseCase struct {
+	pool          *pgxpool.Pool
+	sqlcQueries   *sqlc.Queries
+	riverClient   *river.Client[pgx.Tx]
+	vmRepo        repository.VMRepository
+	clusterRepo   repository.ClusterRepository
+	namespaceRepo repository.NamespaceRegistryRepository
+	relocations   repository.VMRelocationRepository
+	clock         domain.Clock
+	ids           domain.IDGenerator
+}
+
+// NewRelocateVMUseCase creates a new use case instance.
+func NewRelocateVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	clusterRepo repository.ClusterRepository,
+	namespaceRepo repository.NamespaceRegistryRepository,
+	relocations repository.VMRelocationRepository,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *RelocateVMUseCase {
+	return &RelocateVMUseCase{
+		pool:          pool,
+		sqlcQueries:   sqlcQueries,
+		riverClient:   riverClient,
+		vmRepo:        vmRepo,
+		clusterRepo:   clusterRepo,
+		namespaceRepo: namespaceRepo,
+		relocations:   relocations,
+		clock:         clock,
+		ids:           ids,
+	}
+}
+
+// Submit records the relocation and enqueues it.
+func (uc *RelocateVMUseCase) Submit(ctx context.Context, vmID string, req domain.RelocationRequest, requestedBy string) (*domain.VMRelocation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	eligible, err := uc.eligibleTargets(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckRelocation(vm, req.TargetCluster, eligible); err != nil {
+		return nil, err
+	}
+
+	// Disks leave through a VirtualMachineExport and arrive through CDI
+	for _, cluster := range []string{vm.Cluster, req.TargetCluster} {
+		caps, err := uc.clusterRepo.GetCapabilities(ctx, cluster)
+		if err != nil {
+			return nil, fmt.Errorf("get cluster capabilities: %w", err)
+		}
+		if !caps.Supports(domain.FeatureVMExport) {
+			return nil, fmt.Errorf("cluster %s does not support vm export: %w", cluster, domain.ErrRelocationNotAllowed)
+		}
+	}
+
+	now := uc.clock.Now()
+	r := &domain.VMRelocation{
+		ID:                uc.ids.NewID(),
+		VMID:              vm.ID,
+		VMName:            vm.Name,
+		Namespace:         vm.Namespace,
+		ServiceID:         vm.ServiceID,
+		SourceCluster:     vm.Cluster,
+		TargetCluster:     req.TargetCluster,
+		Status:            domain.RelocationPending,
+		Reason:            req.Reason,
+		RequestedBy:       requestedBy,
+		HoldBeforeCutover: req.Hold(),
+		CreatedAt:         now,
+		StepStartedAt:     now,
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	vm.Status = status
+	if err := domain.CheckRelocation(vm, req.TargetCluster, eligible); err != nil {
+		return nil, err
+	}
+
+	// The relocation ID is the event ID: one record per event
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       r.ID,
+		EventType:     string(domain.EventVMRelocationRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventVMRelocationRequested),
+		AggregateType: "VM",
+		AggregateID:   vm.ID,
+		Status:        string(domain.EventStatusProcessing), // Admin-initiated, no approval
+		CreatedBy:     requestedBy,
+	}, domain.VMRelocationPayload{
+		VMID:          vm.ID,
+		VMName:        vm.Name,
+		Namespace:     vm.Namespace,
+		ServiceID:     vm.ServiceID,
+		SourceCluster: vm.Cluster,
+		TargetCluster: req.TargetCluster,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	if err := sqlcTx.CreateVMRelocation(ctx, sqlc.CreateVMRelocationParams{
+		ID:                r.ID,
+		VMID:              r.VMID,
+		VMName:            r.VMName,
+		Namespace:         r.Namespace,
+		ServiceID:         r.ServiceID,
+		SourceCluster:     r.SourceCluster,
+		TargetCluster:     r.TargetCluster,
+		Status:            string(r.Status),
+		Reason:            r.Reason,
+		RequestedBy:       r.RequestedBy,
+		HoldBeforeCutover: r.HoldBeforeCutover,
+		CreatedAt:         r.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("create vm relocation: %w", err)
+	}
+
+	if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: r.ID}, nil); err != nil {
+		return nil, fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditVMRelocationRequested, requestedBy, r, map[string]interface{}{
+		"target_cluster":      r.TargetCluster,
+		"hold_before_cutover": r.HoldBeforeCutover,
+		"reason":              r.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return r, nil
+}
+
+// Get returns the relocation with its recorded steps and disk progress.
+func (uc *RelocateVMUseCase) Get(ctx context.Context, relocationID string) (*domain.VMRelocation, error) {
+	return uc.relocations.Get(ctx, relocationID)
+}
+
+// ListActive returns the relocations in progress, decommission items
+// included.
+func (uc *RelocateVMUseCase) ListActive(ctx context.Context) ([]*domain.VMRelocation, error) {
+	return uc.relocations.ListActive(ctx)
+}
+
+// ConfirmCutover lets a relocation held at AWAITING_CUTOVER cut over; the
+// worker picks it up on its next poll.
+func (uc *RelocateVMUseCase) ConfirmCutover(ctx context.Context, relocationID, actor string) (*domain.VMRelocation, error) {
+	r, err := uc.relocations.Get(ctx, relocationID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != domain.RelocationAwaitingCutover || r.AbortRequestedAt != nil {
+		return nil, fmt.Errorf("confirm a %s relocation: %w", r.Status, domain.ErrRelocationInvalidState)
+	}
+
+	now := uc.clock.Now()
+	err = uc.inTx(ctx, func(sqlcTx *sqlc.Queries) error {
+		// Conditional on AWAITING_CUTOVER and no abort request
+		n, err := sqlcTx.ConfirmVMRelocation(ctx, sqlc.ConfirmVMRelocationParams{
+			ID:          r.ID,
+			ConfirmedBy: actor,
+			ConfirmedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("confirm relocation: %w", err)
+		}
+		if n == 0 {
+			return domain.ErrRelocationInvalidState
+		}
+		return uc.audit(ctx, sqlcTx, domain.AuditVMRelocationConfirmed, actor, r, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Status, r.ConfirmedBy, r.ConfirmedAt = domain.RelocationCuttingOver, actor, &now
+	return r, nil
+}
+
+// Abort asks to abandon the relocation before cutover. One not started
+// yet is finished at once; otherwise the worker rolls back on its next
+// poll, so the returned relocation may still be in its step. Repeating
+// the request changes nothing. From cutover on the target is the VM of
+// record and domain.ErrRelocationInvalidState is returned.
+func (uc *RelocateVMUseCase) Abort(ctx context.Context, relocationID, actor string) (*domain.VMRelocation, error) {
+	r, err := uc.relocations.Get(ctx, relocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !r.Status.BeforeCutover() {
+		return nil, fmt.Errorf("abort a %s relocation: %w", r.Status, domain.ErrRelocationInvalidState)
+	}
+	if r.AbortRequestedAt != nil {
+		return r, nil
+	}
+
+	now := uc.clock.Now()
+	err = uc.inTx(ctx, func(sqlcTx *sqlc.Queries) error {
+		// Conditional on a status before cutover: a confirmed cutover wins
+		n, err := sqlcTx.RequestVMRelocationAbort(ctx, sqlc.RequestVMRelocationAbortParams{
+			ID:               r.ID,
+			AbortRequestedBy: actor,
+			AbortRequestedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("request abort: %w", err)
+		}
+		if n == 0 {
+			return domain.ErrRelocationInvalidState
+		}
+
+		if r.Status == domain.RelocationPending {
+			// Nothing exists on either cluster yet; the job finds the event terminal
+			if _, err := sqlcTx.FinishVMRelocation(ctx, sqlc.FinishVMRelocationParams{
+				ID:           r.ID,
+				Status:       string(domain.RelocationAborted),
+				ErrorMessage: "aborted by " + actor,
+				CompletedAt:  now,
+			}); err != nil {
+				return fmt.Errorf("finish vm relocation: %w", err)
+			}
+			result, err := domain.RelocationResult{
+				Status:        domain.RelocationAborted,
+				SourceCluster: r.SourceCluster,
+				TargetCluster: r.TargetCluster,
+			}.ToJSON()
+			if err != nil {
+				return err
+			}
+			if err := sqlcTx.CompleteDomainEvent(ctx, sqlc.CompleteDomainEventParams{
+				EventID: r.ID,
+				Status:  string(domain.EventStatusCancelled),
+				Result:  result,
+			}); err != nil {
+				return fmt.Errorf("update event: %w", err)
+			}
+			r.Status, r.CompletedAt = domain.RelocationAborted, &now
+		}
+		return uc.audit(ctx, sqlcTx, domain.AuditVMRelocationAbortRequested, actor, r, map[string]interface{}{
+			"status": r.Status,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.AbortRequestedBy, r.AbortRequestedAt = actor, &now
+	return r, nil
+}
+
+// eligibleTargets returns the clusters vm may move to: placeable clusters
+// of its environment with its namespace registered.
+func (uc *RelocateVMUseCase) eligibleTargets(ctx context.Context, vm *domain.VM) ([]*domain.Cluster, error) {
+	clusters, err := uc.clusterRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	var source *domain.Cluster
+	for _, c := range clusters {
+		if c.Name == vm.Cluster {
+			source = c
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("cluster %s: %w", vm.Cluster, repository.ErrNotFound)
+	}
+	registered, err := uc.namespaceRepo.ClusterIDsForNamespace(ctx, vm.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("resolve namespace %s: %w", vm.Namespace, err)
+	}
+
+	var eligible []*domain.Cluster
+	for _, c := range domain.FilterPlacementCandidates(clusters, source.Environment) {
+		for _, id := range registered {
+			if c.ID == id {
+				eligible = append(eligible, c)
+			}
+		}
+	}
+	return eligible, nil
+}
+
+func (uc *RelocateVMUseCase) inTx(ctx context.Context, fn func(sqlcTx *sqlc.Queries) error) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(uc.sqlcQueries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// audit appends an audit record on the VM inside the caller's transaction.
+func (uc *RelocateVMUseCase) audit(ctx context.Context, sqlcTx *sqlc.Queries, action, actor string, r *domain.VMRelocation, details map[string]interface{}) error {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["relocation_id"] = r.ID
+	details["source_cluster"] = r.SourceCluster
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      actor,
+		ResourceType: "vm",
+		ResourceID:   r.VMID,
+		ResourceName: r.VMName,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
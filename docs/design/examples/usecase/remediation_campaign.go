@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RemediationCampaignUseCase submits one request per non-compliant VM
+// (domain/remediation.go). Platform admins only (route middleware).
+//
+// The campaign row is written first, then each VM's request is submitted
+// through its ordinary use case, in its own transaction, and its outcome
+// recorded as an item. One VM failing (operation in flight, replacement
+// already running) never blocks the others, as with bulk approval.
+type RemediationCampaignUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	compliance  *ComplianceUseCase
+	replace     *VMReplacementUseCase
+	modify      *ModifyVMAtomicUseCase
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewRemediationCampaignUseCase creates a new use case instance.
+func NewRemediationCampaignUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	compliance *ComplianceUseCase,
+	replace *VMReplacementUseCase,
+	modify *ModifyVMAtomicUseCase,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *RemediationCampaignUseCase {
+	return &RemediationCampaignUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		compliance:  compliance,
+		replace:     replace,
+		modify:      modify,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Start creates the campaign for the VMs matching req.Filter, most severe
+// first, and submits their requests as userID.
+func (uc *RemediationCampaignUseCase) Start(ctx context.Context, req domain.CampaignRequest, userID string) (*domain.RemediationCampaign, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	report, err := uc.compliance.Report(ctx, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(report) == 0 {
+		return nil, domain.ErrCampaignEmpty
+	}
+
+	c := &domain.RemediationCampaign{
+		ID:        uc.ids.NewID(),
+		Name:      req.Name,
+		Filter:    req.Filter,
+		Action:    req.Action,
+		Reason:    req.Reason,
+		Truncated: len(report) > domain.MaxCampaignVMs,
+		CreatedBy: userID,
+		CreatedAt: uc.clock.Now(),
+	}
+	if c.Truncated {
+		report = report[:domain.MaxCampaignVMs]
+	}
+	if err := uc.create(ctx, c, len(report)); err != nil {
+		return nil, err
+	}
+
+	ctx = logger.WithResource(ctx, "remediation_campaign", c.ID)
+	for _, vm := range report {
+		item := uc.submit(ctx, c, req, vm, userID)
+		if item.Status == domain.RemediationSubmitted {
+			c.Submitted++
+		} else {
+			c.Failed++
+		}
+		c.Items = append(c.Items, item)
+
+		// Autocommit: the request is already submitted either way
+		if err := uc.sqlcQueries.CreateRemediationItem(ctx, sqlc.CreateRemediationItemParams{
+			CampaignID:    c.ID,
+			VMID:          item.VMID,
+			VMName:        item.VMName,
+			Status:        string(item.Status),
+			TicketID:      item.TicketID,
+			ReplacementID: item.ReplacementID,
+			Error:         item.Error,
+		}); err != nil {
+			logger.WarnCtx(ctx, "Record remediation item failed", zap.String("vm_id", item.VMID), zap.Error(err))
+		}
+	}
+
+	if err := uc.sqlcQueries.UpdateRemediationCampaignCounts(ctx, sqlc.UpdateRemediationCampaignCountsParams{
+		ID:        c.ID,
+		Submitted: int32(c.Submitted),
+		Failed:    int32(c.Failed),
+	}); err != nil {
+		return nil, fmt.Errorf("update campaign: %w", err)
+	}
+	return c, nil
+}
+
+// Get returns the campaign with its items and the current status of each
+// item's ticket.
+func (uc *RemediationCampaignUseCase) Get(ctx context.Context, campaignID string) (*domain.RemediationCampaign, error) {
+	row, err := uc.sqlcQueries.GetRemediationCampaign(ctx, campaignID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get campaign: %w", err)
+	}
+	// LEFT JOIN approval_tickets for ticket_status
+	items, err := uc.sqlcQueries.ListRemediationItems(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("list campaign items: %w", err)
+	}
+
+	c := &domain.RemediationCampaign{
+		ID:        row.ID,
+		Name:      row.Name,
+		Filter:    row.Filter,
+		Action:    domain.RemediationAction(row.Action),
+		Reason:    row.Reason,
+		Submitted: int(row.Submitted),
+		Failed:    int(row.Failed),
+		Truncated: row.Truncated,
+		CreatedBy: row.CreatedBy,
+		CreatedAt: row.CreatedAt,
+	}
+	for _, i := range items {
+		c.Items = append(c.Items, &domain.RemediationItem{
+			VMID:          i.VMID,
+			VMName:        i.VMName,
+			Status:        domain.RemediationItemStatus(i.Status),
+			TicketID:      i.TicketID,
+			ReplacementID: i.ReplacementID,
+			TicketStatus:  i.TicketStatus,
+			Error:         i.Error,
+		})
+	}
+	return c, nil
+}
+
+// create writes the campaign row with its audit log.
+func (uc *RemediationCampaignUseCase) create(ctx context.Context, c *domain.RemediationCampaign, vms int) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := sqlcTx.CreateRemediationCampaign(ctx, sqlc.CreateRemediationCampaignParams{
+		ID:        c.ID,
+		Name:      c.Name,
+		Filter:    c.Filter,
+		Action:    string(c.Action),
+		Reason:    c.Reason,
+		Truncated: c.Truncated,
+		CreatedBy: c.CreatedBy,
+		CreatedAt: c.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("create campaign: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditRemediationCampaignStarted,
+		ActorID:      c.CreatedBy,
+		ResourceType: "remediation_campaign",
+		ResourceID:   c.ID,
+		ResourceName: c.Name,
+		Details: map[string]interface{}{
+			"filter":    c.Filter,
+			"action":    c.Action,
+			"vm_count":  vms,
+			"truncated": c.Truncated,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// submit files the request of one VM and reports its outcome.
+func (uc *RemediationCampaignUseCase) submit(ctx context.Context, c *domain.RemediationCampaign, req domain.CampaignRequest, vm *domain.VMCompliance, userID string) *domain.RemediationItem {
+	item := &domain.RemediationItem{VMID: vm.VMID, VMName: vm.Name, Status: domain.RemediationSubmitted}
+	reason := fmt.Sprintf("Remediation campaign %q (%s): %s", c.Name, vm.Findings[0].Reason, req.Reason)
+
+	var err error
+	switch req.Action {
+	case domain.RemediationRebuild:
+		var res *ReplacementResult
+		res, err = uc.replace.Start(ctx, vm.VMID, userID, domain.StartReplacementRequest{
+			Confirmation: req.Confirmation,
+			Reason:       reason,
+		})
+		if err == nil {
+			item.TicketID = res.TicketID
+			item.ReplacementID = res.ReplacementID
+		}
+	case domain.RemediationModify:
+		var res *ModifyVMResult
+		res, err = uc.modify.Submit(ctx, ModifyVMRequest{
+			VMID:        vm.VMID,
+			CPU:         req.CPU,
+			MemoryMB:    req.MemoryMB,
+			Reason:      reason,
+			RequestedBy: userID,
+		})
+		if err == nil {
+			item.TicketID = res.TicketID
+		}
+	}
+	if err != nil {
+		item.Status = domain.RemediationFailed
+		item.Error = err.Error()
+	}
+	return item
+}
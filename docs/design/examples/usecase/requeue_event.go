@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RequeueEventUseCase sends a FAILED event back to execution (platform
+// admin only, domain/event_requeue.go).
+//
+// The event goes back to PROCESSING and a fresh EventJobArgs job is
+// inserted in one TX with the audit log, the same claim check as the
+// approval: a crash leaves the event either FAILED or requeued, never
+// PROCESSING without a job.
+type RequeueEventUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewRequeueEventUseCase creates a new use case instance.
+func NewRequeueEventUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *RequeueEventUseCase {
+	return &RequeueEventUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Execute requeues eventID and returns the event as requeued.
+func (uc *RequeueEventUseCase) Execute(ctx context.Context, eventID string, req domain.RequeueRequest, actor string) (*domain.DomainEvent, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Locked: two admins requeueing at once must not insert two jobs
+	row, err := sqlcTx.GetDomainEventForUpdate(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	event := &domain.DomainEvent{
+		EventID:         row.EventID,
+		EventType:       domain.EventType(row.EventType),
+		AggregateType:   row.AggregateType,
+		AggregateID:     row.AggregateID,
+		Payload:         row.Payload,
+		PayloadEncoding: domain.PayloadEncoding(row.PayloadEncoding),
+		SchemaVersion:   int(row.SchemaVersion),
+		Status:          domain.EventStatus(row.Status),
+		RequeueCount:    int(row.RequeueCount),
+		CreatedBy:       row.CreatedBy,
+		CreatedAt:       row.CreatedAt,
+	}
+	if err := event.Upcast(); err != nil {
+		return nil, fmt.Errorf("upcast event payload: %w", err)
+	}
+	if err := domain.CheckRequeue(event); err != nil {
+		return nil, err
+	}
+
+	// Power operations without approval have no ticket
+	queue := river.QueueDefault
+	ticket, err := sqlcTx.GetApprovalTicketByEventForUpdate(ctx, eventID)
+	hasTicket := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if hasTicket {
+		if ticket.ParentTicketID != "" {
+			// The batch already counted the child as failed
+			return nil, fmt.Errorf("event is an item of batch %s: %w", ticket.ParentTicketID, domain.ErrEventNotRequeueable)
+		}
+		queue = domain.Priority(ticket.Priority).Queue()
+	}
+
+	if err := uc.reholdQuota(ctx, sqlcTx, eventID); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... SET status = 'PROCESSING', requeue_count = requeue_count + 1
+	// WHERE event_id = $1 AND status = 'FAILED'
+	n, err := sqlcTx.RequeueDomainEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("requeue event: %w", err)
+	}
+	if n == 0 {
+		return nil, domain.ErrEventNotFailed
+	}
+	event.Status = domain.EventStatusProcessing
+	event.RequeueCount++
+
+	// Runs now, whatever execution was planned originally
+	if hasTicket {
+		if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticket.TicketID, eventID, queue, nil); err != nil {
+			return nil, err
+		}
+	} else if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.EventJobArgs{EventID: eventID}, &river.InsertOpts{Queue: queue}); err != nil {
+		return nil, fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditEventRequeued,
+		ActorID:      actor,
+		ResourceType: "event",
+		ResourceID:   eventID,
+		Details: map[string]interface{}{
+			"event_type":    event.EventType,
+			"aggregate_id":  event.AggregateID,
+			"requeue_count": event.RequeueCount,
+			"reason":        req.Reason,
+			"requeued_at":   uc.clock.Now(),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return event, nil
+}
+
+// reholdQuota holds again the reservation released when the event failed,
+// if the Service still has the quota; otherwise the requeue is refused
+// with the shortfall. The cluster was chosen at approval, so only quota
+// is checked.
+func (uc *RequeueEventUseCase) reholdQuota(ctx context.Context, sqlcTx *sqlc.Queries, eventID string) error {
+	row, err := sqlcTx.GetQuotaReservationByEvent(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // Operation without quota (power, snapshot, deletion)
+	}
+	if err != nil {
+		return fmt.Errorf("get quota reservation: %w", err)
+	}
+	if domain.ReservationStatus(row.Status) != domain.ReservationReleased ||
+		domain.ReleaseReason(row.ReleaseReason) != domain.ReleaseExecutionFailed {
+		return nil // Still HELD: the sweep has not run yet
+	}
+
+	r := &domain.QuotaReservation{
+		ID:        row.ID,
+		ServiceID: row.ServiceID,
+		TicketID:  row.TicketID,
+		EventID:   row.EventID,
+		CPU:       int(row.CPU),
+		MemoryMB:  int(row.MemoryMB),
+		DiskGB:    int(row.DiskGB),
+		VMCount:   int(row.VMCount),
+	}
+	if err := requireCapacity(ctx, sqlcTx, r.TicketID, r, nil, nil); err != nil {
+		return err
+	}
+	// status = 'HELD', release_reason = NULL, settled_at = NULL
+	if err := sqlcTx.ReholdQuotaReservation(ctx, r.ID); err != nil {
+		return fmt.Errorf("hold quota: %w", err)
+	}
+	return nil
+}
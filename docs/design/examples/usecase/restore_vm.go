@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RestoreVMUseCase restores a VM to one of its restore points
+// (domain/vm_restore.go) with the same atomic transaction pattern as
+// DeleteVMAtomicUseCase (ADR-0012):
+//
+//	Submit()            → Event + Ticket, no River Job  → PENDING_APPROVAL
+//	ApproveAndEnqueue() → Ticket APPROVED, River Job    → APPROVED
+//
+// A restore overwrites the VM's disks, so there is no auto-approval path:
+// approval rules are not consulted, the environment policy's approvals
+// always apply. The safety snapshot and the restore itself are done by
+// RestoreHandler.
+type RestoreVMUseCase struct {
+	pool          *pgxpool.Pool
+	sqlcQueries   *sqlc.Queries
+	riverClient   *river.Client[pgx.Tx]
+	vmRepo        repository.VMRepository
+	restorePoints repository.RestorePointRepository
+	approvers     ApproverResolver
+	guard         ApprovalGuard
+	environments  EnvironmentPolicies
+	permissions   domain.PermissionChecker
+	clock         domain.Clock
+	ids           domain.IDGenerator
+
+	backupProvider string // backup.provider; backups of another provider cannot be restored
+}
+
+// NewRestoreVMUseCase creates a new use case instance.
+func NewRestoreVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	restorePoints repository.RestorePointRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	permissions domain.PermissionChecker,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+	backupProvider string,
+) *RestoreVMUseCase {
+	return &RestoreVMUseCase{
+		pool:          pool,
+		sqlcQueries:   sqlcQueries,
+		riverClient:   riverClient,
+		vmRepo:        vmRepo,
+		restorePoints: restorePoints,
+		approvers:     approvers,
+		guard:         guard,
+		environments:  environments,
+		permissions:   permissions,
+		clock:         clock,
+		ids:           ids,
+
+		backupProvider: backupProvider,
+	}
+}
+
+// RestoreVMResult contains the restore request result.
+type RestoreVMResult struct {
+	EventID            string
+	TicketID           string
+	SafetySnapshotName string
+}
+
+// Submit records the restore request for approval.
+func (uc *RestoreVMUseCase) Submit(ctx context.Context, vmID string, req domain.RestoreRequest, requestedBy string) (*RestoreVMResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	// Rechecked under the VM lock; this spares the approver lookup for the common mistake
+	if err := domain.CheckRestore(vm.Status); err != nil {
+		return nil, err
+	}
+	perm, err := uc.permissions.CheckPermission(requestedBy, domain.RestorePermission, string(domain.ResourceTypeService), vm.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, domain.ErrRestoreForbidden
+	}
+	rp, err := uc.restorePoint(ctx, req.RestorePointID, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restores never auto-approve: the ticket records the environment
+	// and its RequiredApprovals
+	decision, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	// Staged approval is for sizing decisions: restores use one stage
+	decision.Stages = domain.DefaultStages(decision.RequiredApprovals)
+	decision.Staged = false
+	approvers, err := uc.approvers.Resolve(ctx, ticketID, vm.ServiceID, requestedBy, decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("resolve approvers: %w", err)
+	}
+
+	payload := domain.NewRestorePayload(vm, rp, eventID, req.Reason)
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckRestore(status); err != nil {
+		return nil, err
+	}
+
+	err = writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:     eventID,
+		ticketID:    ticketID,
+		vmID:        vm.ID,
+		vmName:      vm.Name,
+		serviceID:   vm.ServiceID,
+		requestType: domain.RestoreRequestType,
+		eventType:   domain.EventVMRestoreRequested,
+		payload:     payload,
+		reason:      req.Reason,
+		requestedBy: requestedBy,
+		decision:    *decision,
+		approvers:   approvers,
+		auditAction: domain.AuditVMRestoreRequested,
+		auditDetails: map[string]interface{}{
+			"restore_point_id":     rp.ID,
+			"source":               rp.Source,
+			"point_name":           rp.Name,
+			"safety_snapshot_name": payload.SafetySnapshotName,
+			"reason":               req.Reason,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// No River Job before approval (ADR-0006)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &RestoreVMResult{
+		EventID:            eventID,
+		TicketID:           ticketID,
+		SafetySnapshotName: payload.SafetySnapshotName,
+	}, nil
+}
+
+// ApproveAndEnqueue records an approval of a restore ticket. Once the
+// ticket has its required approvals, the River job is inserted in the
+// same transaction. The restore point is checked again: it may have
+// expired while the ticket waited.
+func (uc *RestoreVMUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	payload, err := decodeEventPayload[domain.RestorePayload](event)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.restorePoint(ctx, payload.RestorePointID, payload.VMID); err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, false)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusProcessing),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+	if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, ticket.EventID, domain.QueueNormal, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// restorePoint loads a restore point the VM can be restored to now. A
+// backup taken by a provider other than the configured one cannot be
+// restored: it goes back through the system that holds it.
+func (uc *RestoreVMUseCase) restorePoint(ctx context.Context, id, vmID string) (*domain.RestorePoint, error) {
+	rp, err := uc.restorePoints.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("restore point %s: %w", id, domain.ErrRestorePointUnavailable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get restore point: %w", err)
+	}
+	if err := domain.CheckRestorePoint(rp, vmID, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+	if rp.Source == domain.RestorePointBackup && rp.Provider != uc.backupProvider {
+		return nil, fmt.Errorf("restore point %s was taken by backup provider %s: %w", id, rp.Provider, domain.ErrRestorePointUnavailable)
+	}
+	return rp, nil
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ResubmitRequestUseCase resubmits a rejected request with the
+// requester's edits (domain/resubmission.go).
+//
+// The new request is submitted through the request type's own Submit,
+// exactly like a first submission: the environment policy, approval rules
+// and approvers are evaluated again, and a MODIFY_VM is re-planned against
+// the VM as it is now. The only difference is the link to the rejected
+// ticket (approval_tickets.resubmitted_from).
+type ResubmitRequestUseCase struct {
+	sqlcQueries *sqlc.Queries
+	ticketRepo  repository.ApprovalTicketRepository
+	permissions domain.PermissionChecker
+	create      *CreateVMAtomicUseCase
+	modify      *ModifyVMAtomicUseCase
+}
+
+// NewResubmitRequestUseCase creates a new use case instance.
+func NewResubmitRequestUseCase(
+	sqlcQueries *sqlc.Queries,
+	ticketRepo repository.ApprovalTicketRepository,
+	permissions domain.PermissionChecker,
+	create *CreateVMAtomicUseCase,
+	modify *ModifyVMAtomicUseCase,
+) *ResubmitRequestUseCase {
+	return &ResubmitRequestUseCase{
+		sqlcQueries: sqlcQueries,
+		ticketRepo:  ticketRepo,
+		permissions: permissions,
+		create:      create,
+		modify:      modify,
+	}
+}
+
+// ResubmitResult contains the new request.
+type ResubmitResult struct {
+	EventID         string
+	TicketID        string
+	ResubmittedFrom string
+	Route           *domain.ApprovalRoute // Auto-approved resubmissions are PROCESSING
+}
+
+// Execute resubmits the rejected ticket as userID. Returns
+// domain.ErrNotRequester, domain.ErrTicketNotRejected,
+// domain.ErrResubmitUnsupported or domain.ErrAlreadyResubmitted when the
+// ticket cannot be resubmitted (again).
+func (uc *ResubmitRequestUseCase) Execute(ctx context.Context, ticketID, userID string, edits domain.ResubmitEdits) (*ResubmitResult, error) {
+	ticket, err := uc.sqlcQueries.GetApprovalTicket(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if err := domain.CheckResubmit(domain.TicketStatus(ticket.Status), ticket.RequestType, ticket.CreatedBy, ticket.ParentTicketID, userID); err != nil {
+		return nil, err
+	}
+	if err := edits.Validate(ticket.RequestType); err != nil {
+		return nil, err
+	}
+
+	// One resubmission per rejected ticket: the chain stays linear. The
+	// unique index on resubmitted_from catches concurrent resubmissions.
+	next, err := uc.sqlcQueries.GetResubmissionOf(ctx, ticketID)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("as %s: %w", next.TicketID, domain.ErrAlreadyResubmitted)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("get resubmission: %w", err)
+	}
+
+	// The original payload, not the effective spec: approver changes were
+	// part of the rejected round
+	event, err := uc.sqlcQueries.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	raw, err := eventPayload(event)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ticket.RequestType {
+	case "CREATE_VM":
+		original, err := domain.UnmarshalPayload[domain.VMCreationPayload](domain.EventType(event.EventType), raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode creation payload: %w", err)
+		}
+		p := edits.ApplyToCreation(original)
+		res, err := uc.create.Submit(ctx, CreateVMRequest{
+			ServiceID:       p.ServiceID,
+			TemplateID:      p.TemplateID,
+			Namespace:       p.Namespace,
+			CPU:             p.CPU,
+			MemoryMB:        p.MemoryMB,
+			Reason:          p.Reason,
+			RequestedBy:     userID,
+			Priority:        domain.Priority(ticket.Priority),
+			Lease:           p.Lease, // Revalidated: an expiry now past fails
+			ResubmittedFrom: ticketID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &ResubmitResult{EventID: res.EventID, TicketID: res.TicketID, ResubmittedFrom: ticketID, Route: res.Route}, nil
+
+	case "MODIFY_VM":
+		original, err := domain.UnmarshalPayload[domain.VMModifyPayload](domain.EventType(event.EventType), raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode modify payload: %w", err)
+		}
+		req := ModifyVMRequest{
+			VMID:            original.VMID,
+			CPU:             original.CPU,
+			MemoryMB:        original.MemoryMB,
+			Reason:          edits.Reason,
+			RequestedBy:     userID,
+			ResubmittedFrom: ticketID,
+		}
+		if edits.CPU != nil {
+			req.CPU = *edits.CPU
+		}
+		if edits.MemoryMB != nil {
+			req.MemoryMB = *edits.MemoryMB
+		}
+		res, err := uc.modify.Submit(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &ResubmitResult{EventID: res.EventID, TicketID: res.TicketID, ResubmittedFrom: ticketID, Route: res.Route}, nil
+
+	default:
+		return nil, fmt.Errorf("%s: %w", ticket.RequestType, domain.ErrResubmitUnsupported)
+	}
+}
+
+// History returns every round of the ticket's resubmission chain, oldest
+// first, from any ticket in it. Visible to the participants of that
+// ticket, as for comments: approvers of a resubmission see how the
+// earlier rounds were rejected.
+func (uc *ResubmitRequestUseCase) History(ctx context.Context, ticketID, userID string) ([]domain.ResubmissionRound, error) {
+	ticket, err := uc.ticketRepo.Get(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	p, err := ticketParticipation(ctx, uc.sqlcQueries, uc.permissions, ticket, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Role(); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.sqlcQueries.ListResubmissionChain(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("list resubmission chain: %w", err)
+	}
+	rounds := make([]domain.ResubmissionRound, 0, len(rows))
+	for _, row := range rows {
+		payload, err := domain.DecodePayload(domain.PayloadEncoding(row.PayloadEncoding), row.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode payload of %s: %w", row.TicketID, err)
+		}
+		rounds = append(rounds, domain.ResubmissionRound{
+			TicketID:        row.TicketID,
+			ResubmittedFrom: row.ResubmittedFrom,
+			Status:          domain.TicketStatus(row.Status),
+			Payload:         payload,
+			RequestReason:   row.RequestReason,
+			RejectedBy:      row.RejectedBy,
+			RejectReason:    row.RejectReason,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	return rounds, nil
+}
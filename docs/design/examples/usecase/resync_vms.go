@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// ResyncVMsUseCase starts and cancels admin-triggered VM resyncs
+// (Phase 2 §3 Bulk Resync).
+//
+// Start records the run, one progress row per cluster and one River job
+// per cluster in a single TX; VMResyncWorker does the paging. Admin-only:
+// no ApprovalTicket, but every action is audited.
+type ResyncVMsUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	clusterRepo repository.ClusterRepository
+	resyncRepo  repository.ResyncRepository
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewResyncVMsUseCase creates a new use case instance.
+func NewResyncVMsUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	clusterRepo repository.ClusterRepository,
+	resyncRepo repository.ResyncRepository,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *ResyncVMsUseCase {
+	return &ResyncVMsUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		clusterRepo: clusterRepo,
+		resyncRepo:  resyncRepo,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// StartResyncRequest contains the request to start a resync.
+type StartResyncRequest struct {
+	Clusters    []string // Cluster names; empty = every non-decommissioned cluster
+	Reason      string   // Required: e.g., "DB restored from 02:00 backup"
+	RequestedBy string   // Required: platform admin
+}
+
+// Start records a resync and enqueues one job per cluster.
+func (uc *ResyncVMsUseCase) Start(ctx context.Context, req StartResyncRequest) (*domain.Resync, error) {
+	clusters, err := uc.targetClusters(ctx, req.Clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &domain.Resync{
+		ID:          uc.ids.NewID(),
+		Status:      domain.ResyncRunning,
+		Reason:      req.Reason,
+		RequestedBy: req.RequestedBy,
+		StartedAt:   uc.clock.Now(),
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Partial unique index on resyncs (status = 'RUNNING') backs this
+	// check against concurrent Starts
+	running, err := sqlcTx.CountRunningResyncs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count running resyncs: %w", err)
+	}
+	if running > 0 {
+		return nil, domain.ErrResyncInProgress
+	}
+
+	if err := sqlcTx.CreateResync(ctx, sqlc.CreateResyncParams{
+		ID:          run.ID,
+		Status:      string(run.Status),
+		Reason:      run.Reason,
+		RequestedBy: run.RequestedBy,
+		StartedAt:   run.StartedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("create resync: %w", err)
+	}
+
+	for _, cluster := range clusters {
+		if err := sqlcTx.CreateResyncCluster(ctx, sqlc.CreateResyncClusterParams{
+			ResyncID: run.ID,
+			Cluster:  cluster,
+		}); err != nil {
+			return nil, fmt.Errorf("create resync cluster %s: %w", cluster, err)
+		}
+		if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.VMResyncArgs{ResyncID: run.ID, Cluster: cluster}, nil); err != nil {
+			return nil, fmt.Errorf("insert river job %s: %w", cluster, err)
+		}
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditVMResyncStarted, req.RequestedBy, run, map[string]interface{}{
+		"reason":   req.Reason,
+		"clusters": clusters,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return run, nil
+}
+
+// Cancel stops a running resync. Jobs notice on their next page; rows
+// already reconciled stay reconciled, missing VMs are not flagged for
+// clusters that had not finished.
+func (uc *ResyncVMsUseCase) Cancel(ctx context.Context, resyncID, actor string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Conditional on RUNNING: races with the last cluster finishing
+	rows, err := sqlcTx.CancelResync(ctx, resyncID)
+	if err != nil {
+		return fmt.Errorf("cancel resync: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrResyncFinished
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditVMResyncCancelled, actor, &domain.Resync{ID: resyncID}, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// Get returns a resync and the progress of each cluster.
+func (uc *ResyncVMsUseCase) Get(ctx context.Context, resyncID string) (*domain.Resync, []*domain.ResyncCluster, error) {
+	run, err := uc.resyncRepo.Get(ctx, resyncID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get resync: %w", err)
+	}
+	clusters, err := uc.resyncRepo.ListClusters(ctx, resyncID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list resync clusters: %w", err)
+	}
+	return run, clusters, nil
+}
+
+// targetClusters resolves the requested clusters, defaulting to all
+// clusters that are not decommissioned.
+func (uc *ResyncVMsUseCase) targetClusters(ctx context.Context, names []string) ([]string, error) {
+	if len(names) > 0 {
+		for _, name := range names {
+			if _, err := uc.clusterRepo.Get(ctx, name); err != nil {
+				return nil, fmt.Errorf("get cluster %s: %w", name, err)
+			}
+		}
+		return names, nil
+	}
+
+	clusters, err := uc.clusterRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clusters: %w", err)
+	}
+	result := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Lifecycle == domain.ClusterLifecycleDecommissioned {
+			continue
+		}
+		result = append(result, cluster.Name)
+	}
+	return result, nil
+}
+
+// audit appends an audit record inside the caller's transaction.
+func (uc *ResyncVMsUseCase) audit(ctx context.Context, sqlcTx *sqlc.Queries, action, actor string, run *domain.Resync, details map[string]interface{}) error {
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      actor,
+		ResourceType: "resync",
+		ResourceID:   run.ID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
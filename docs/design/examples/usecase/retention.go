@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RetentionUseCase manages retention policies and legal holds
+// (domain/retention.go). Platform admin only; every change is audited in
+// the same TX.
+//
+// Purge jobs read the policy and the active holds at each run, so a
+// change takes effect on the next run of the record type's purge.
+type RetentionUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewRetentionUseCase creates a new use case instance.
+func NewRetentionUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *RetentionUseCase {
+	return &RetentionUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// Policies returns the policy of every record type, defaults included.
+func (uc *RetentionUseCase) Policies(ctx context.Context) ([]domain.RetentionPolicy, error) {
+	rows, err := uc.sqlcQueries.ListRetentionPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list retention policies: %w", err)
+	}
+	set := make(map[domain.RetentionRecordType]sqlc.RetentionPolicy, len(rows))
+	for _, row := range rows {
+		set[domain.RetentionRecordType(row.RecordType)] = row
+	}
+
+	policies := make([]domain.RetentionPolicy, 0, len(domain.RetentionRecordTypes))
+	for _, t := range domain.RetentionRecordTypes {
+		p := domain.DefaultRetentionPolicy(t)
+		if row, ok := set[t]; ok {
+			p.WindowDays = int(row.WindowDays)
+			p.Default = false
+			p.UpdatedBy = row.UpdatedBy
+			p.UpdatedAt = row.UpdatedAt
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// SetPolicy sets the window of a record type.
+func (uc *RetentionUseCase) SetPolicy(ctx context.Context, recordType domain.RetentionRecordType, windowDays int, actorID string) (*domain.RetentionPolicy, error) {
+	p := domain.DefaultRetentionPolicy(recordType)
+	previous := p.WindowDays
+	p.WindowDays = windowDays
+	p.Default = false
+	p.UpdatedBy = actorID
+	p.UpdatedAt = uc.clock.Now()
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Locked so two admins changing the same window are audited in order
+	row, err := sqlcTx.GetRetentionPolicyForUpdate(ctx, string(recordType))
+	switch {
+	case err == nil:
+		previous = int(row.WindowDays)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("get retention policy: %w", err)
+	}
+
+	err = sqlcTx.UpsertRetentionPolicy(ctx, sqlc.UpsertRetentionPolicyParams{
+		RecordType: string(p.RecordType),
+		WindowDays: int32(p.WindowDays),
+		UpdatedBy:  p.UpdatedBy,
+		UpdatedAt:  p.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upsert retention policy: %w", err)
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditRetentionPolicyUpdated, actorID, "retention_policy", string(recordType), map[string]interface{}{
+		"previous_window_days": previous,
+		"window_days":          p.WindowDays,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &p, nil
+}
+
+// PlaceLegalHoldRequest contains the request to place a legal hold.
+type PlaceLegalHoldRequest struct {
+	ResourceType string // Required: domain.HoldResource*
+	ResourceID   string // Required
+	Reason       string // Required
+	CaseRef      string // Optional
+	ActorID      string // Required: the current user
+}
+
+// PlaceHold places a legal hold. The resource does not have to exist any
+// more: a hold on a deleted VM keeps what is left of its records.
+func (uc *RetentionUseCase) PlaceHold(ctx context.Context, req PlaceLegalHoldRequest) (*domain.LegalHold, error) {
+	h := &domain.LegalHold{
+		ID:           uc.ids.NewID(),
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		Reason:       req.Reason,
+		CaseRef:      req.CaseRef,
+		PlacedBy:     req.ActorID,
+		PlacedAt:     uc.clock.Now(),
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// ON CONFLICT DO NOTHING on the active-hold unique index
+	created, err := sqlcTx.CreateLegalHold(ctx, sqlc.CreateLegalHoldParams{
+		ID:           h.ID,
+		ResourceType: h.ResourceType,
+		ResourceID:   h.ResourceID,
+		Reason:       h.Reason,
+		CaseRef:      h.CaseRef,
+		PlacedBy:     h.PlacedBy,
+		PlacedAt:     h.PlacedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create legal hold: %w", err)
+	}
+	if created == 0 {
+		return nil, domain.ErrLegalHoldExists
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditLegalHoldPlaced, req.ActorID, h.ResourceType, h.ResourceID, map[string]interface{}{
+		"hold_id":  h.ID,
+		"reason":   h.Reason,
+		"case_ref": h.CaseRef,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return h, nil
+}
+
+// ReleaseHold releases a legal hold. The resource's records become
+// purgeable again from the next purge run.
+func (uc *RetentionUseCase) ReleaseHold(ctx context.Context, holdID, actorID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	row, err := sqlcTx.GetLegalHoldForUpdate(ctx, holdID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get legal hold: %w", err)
+	}
+	if row.ReleasedAt != nil {
+		return domain.ErrLegalHoldReleased
+	}
+
+	err = sqlcTx.ReleaseLegalHold(ctx, sqlc.ReleaseLegalHoldParams{
+		ID:         holdID,
+		ReleasedBy: actorID,
+		ReleasedAt: uc.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("release legal hold: %w", err)
+	}
+
+	if err := uc.audit(ctx, sqlcTx, domain.AuditLegalHoldReleased, actorID, row.ResourceType, row.ResourceID, map[string]interface{}{
+		"hold_id":   holdID,
+		"placed_by": row.PlacedBy,
+		"placed_at": row.PlacedAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ActiveHolds returns the holds not yet released, newest first.
+func (uc *RetentionUseCase) ActiveHolds(ctx context.Context) ([]domain.LegalHold, error) {
+	rows, err := uc.sqlcQueries.ListActiveLegalHolds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list legal holds: %w", err)
+	}
+	holds := make([]domain.LegalHold, 0, len(rows))
+	for _, row := range rows {
+		holds = append(holds, domain.LegalHold{
+			ID:           row.ID,
+			ResourceType: row.ResourceType,
+			ResourceID:   row.ResourceID,
+			Reason:       row.Reason,
+			CaseRef:      row.CaseRef,
+			PlacedBy:     row.PlacedBy,
+			PlacedAt:     row.PlacedAt,
+		})
+	}
+	return holds, nil
+}
+
+// audit appends an audit record inside the caller's transaction. Holds
+// are audited on the held resource, so its own audit trail shows them.
+func (uc *RetentionUseCase) audit(ctx context.Context, sqlcTx *sqlc.Queries, action, actor, resourceType, resourceID string, details map[string]interface{}) error {
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      actor,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
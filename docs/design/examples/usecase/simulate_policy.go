@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// RequesterRoleResolver returns the roles approval rules match a requester
+// by. Implemented by service.ApprovalPolicyService.
+type RequesterRoleResolver interface {
+	RequesterRoles(ctx context.Context, serviceID, requester string) ([]string, error)
+}
+
+// PolicySimulationUseCase replays recent requests under a draft approval
+// policy (domain/policy_simulation.go). Platform admins only (route
+// middleware). Read-only: the draft is neither saved nor audited.
+type PolicySimulationUseCase struct {
+	sqlcQueries *sqlc.Queries
+	policyRepo  repository.EnvironmentPolicyRepository
+	ruleRepo    repository.ApprovalRuleRepository
+	roles       RequesterRoleResolver
+}
+
+// NewPolicySimulationUseCase creates a new use case instance.
+func NewPolicySimulationUseCase(
+	sqlcQueries *sqlc.Queries,
+	policyRepo repository.EnvironmentPolicyRepository,
+	ruleRepo repository.ApprovalRuleRepository,
+	roles RequesterRoleResolver,
+) *PolicySimulationUseCase {
+	return &PolicySimulationUseCase{
+		sqlcQueries: sqlcQueries,
+		policyRepo:  policyRepo,
+		ruleRepo:    ruleRepo,
+		roles:       roles,
+	}
+}
+
+// Simulate routes the last limit CREATE_VM and MODIFY_VM requests under
+// draft, newest first; environment, if set, narrows them to one
+// environment. limit 0 uses domain.DefaultSimulationRequests.
+func (uc *PolicySimulationUseCase) Simulate(ctx context.Context, draft *domain.PolicyDraft, environment domain.DeploymentEnvironment, limit int) (*domain.PolicySimulation, error) {
+	if limit == 0 {
+		limit = domain.DefaultSimulationRequests
+	}
+	if limit < 0 || limit > domain.MaxSimulationRequests {
+		return nil, fmt.Errorf("limit must be 1-%d: %w", domain.MaxSimulationRequests, domain.ErrInvalidPolicySimulation)
+	}
+	if err := draft.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := uc.policyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list environment policies: %w", err)
+	}
+	byEnv := make(map[domain.DeploymentEnvironment]*domain.EnvironmentPolicy, len(current))
+	for _, p := range current {
+		byEnv[p.Environment] = p
+	}
+	rules, err := uc.ruleRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list approval rules: %w", err)
+	}
+	policies, rules := draft.Apply(byEnv, rules)
+
+	requests, err := uc.recentRequests(ctx, environment, limit)
+	if err != nil {
+		return nil, err
+	}
+	if domain.NeedRequesterRoles(rules) {
+		if err := uc.resolveRoles(ctx, requests); err != nil {
+			return nil, err
+		}
+	}
+	return domain.SimulatePolicy(policies, rules, requests), nil
+}
+
+// recentRequests loads the requests with the spec they were submitted
+// with; approver modifications do not count, since routing preceded them.
+func (uc *PolicySimulationUseCase) recentRequests(ctx context.Context, environment domain.DeploymentEnvironment, limit int) ([]*domain.SimulatedRequest, error) {
+	rows, err := uc.sqlcQueries.ListRecentRoutedTickets(ctx, sqlc.ListRecentRoutedTicketsParams{
+		Environment: string(environment), // Empty: all
+		Limit:       int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list recent tickets: %w", err)
+	}
+
+	requests := make([]*domain.SimulatedRequest, 0, len(rows))
+	for _, row := range rows {
+		req := &domain.SimulatedRequest{
+			TicketID:     row.TicketID,
+			RequestType:  row.RequestType,
+			Environment:  domain.DeploymentEnvironment(row.Environment),
+			RequestedBy:  row.RequestedBy,
+			CreatedAt:    row.CreatedAt,
+			Status:       domain.TicketStatus(row.Status),
+			AutoApproved: row.RequiredApprovals == 0,
+		}
+		switch row.RequestType {
+		case "CREATE_VM":
+			p, err := decodeEventPayload[domain.VMCreationPayload](row.Event)
+			if err != nil {
+				return nil, fmt.Errorf("ticket %s: %w", row.TicketID, err)
+			}
+			req.Spec = p
+			req.ServiceID, req.Namespace = p.ServiceID, p.Namespace
+		case "MODIFY_VM":
+			// Routed on the resulting size. The disk size was read from the
+			// VM and is not stored, so disk limits are not simulated
+			p, err := decodeEventPayload[domain.VMModifyPayload](row.Event)
+			if err != nil {
+				return nil, fmt.Errorf("ticket %s: %w", row.TicketID, err)
+			}
+			req.Spec = domain.VMCreationPayload{ServiceID: p.ServiceID, Namespace: p.Namespace, CPU: p.CPU, MemoryMB: p.MemoryMB}
+			req.ServiceID, req.Namespace = p.ServiceID, p.Namespace
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// resolveRoles sets each request's requester roles as of now, once per
+// requester and Service.
+func (uc *PolicySimulationUseCase) resolveRoles(ctx context.Context, requests []*domain.SimulatedRequest) error {
+	cache := make(map[[2]string][]string)
+	for _, req := range requests {
+		key := [2]string{req.ServiceID, req.RequestedBy}
+		roles, ok := cache[key]
+		if !ok {
+			var err error
+			if roles, err = uc.roles.RequesterRoles(ctx, req.ServiceID, req.RequestedBy); err != nil {
+				return fmt.Errorf("resolve requester roles: %w", err)
+			}
+			cache[key] = roles
+		}
+		req.RequesterRoles = roles
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// SnapshotVMUseCase takes snapshots of single VMs (domain/vm_snapshot.go)
+// with the same atomic transaction pattern as PowerOperationUseCase
+// (ADR-0012):
+//
+//	Submit(), routed to a ticket   → Event + Ticket, no River Job   → PENDING_APPROVAL
+//	ApproveAndEnqueue()            → Ticket APPROVED, River Job     → APPROVED
+//	Submit(), auto-approved        → Event + Ticket + Job in one TX → PROCESSING
+//
+// The snapshot is taken by SnapshotHandler, which records its name and
+// size as the event's result. Backup requests (domain/vm_backup.go) take
+// the same path with their own request and event type; BackupHandler
+// has the configured backup provider take them.
+type SnapshotVMUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	vmRepo       repository.VMRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	permissions  domain.PermissionChecker
+	clock        domain.Clock
+	ids          domain.IDGenerator
+
+	backupProvider string // backup.provider; empty disables SubmitBackup
+}
+
+// NewSnapshotVMUseCase creates a new use case instance.
+func NewSnapshotVMUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	permissions domain.PermissionChecker,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+	backupProvider string,
+) *SnapshotVMUseCase {
+	return &SnapshotVMUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		vmRepo:       vmRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		router:       router,
+		permissions:  permissions,
+		clock:        clock,
+		ids:          ids,
+
+		backupProvider: backupProvider,
+	}
+}
+
+// SnapshotVMResult contains the snapshot or backup request result.
+type SnapshotVMResult struct {
+	EventID  string
+	TicketID string
+	Name     string // Snapshot or backup name
+	Route    *domain.ApprovalRoute
+}
+
+// copyRequest is what differs between a snapshot and a backup request.
+type copyRequest struct {
+	requestType string
+	eventType   domain.EventType
+	auditAction string
+	nameKey     string // Audit detail holding the copy's name
+
+	// payload builds the event payload and the name chosen for the copy.
+	payload func(vm *domain.VM, eventID, reason string) (domain.EventPayload, string)
+}
+
+var snapshotRequest = copyRequest{
+	requestType: domain.SnapshotRequestType,
+	eventType:   domain.EventVMSnapshotRequested,
+	auditAction: domain.AuditVMSnapshotRequested,
+	nameKey:     "snapshot_name",
+	payload: func(vm *domain.VM, eventID, reason string) (domain.EventPayload, string) {
+		p := domain.SnapshotPayload{
+			VMID:         vm.ID,
+			Name:         vm.Name,
+			Namespace:    vm.Namespace,
+			Cluster:      vm.Cluster,
+			ServiceID:    vm.ServiceID,
+			SnapshotName: domain.SnapshotName(vm.Name, eventID),
+			Reason:       reason,
+		}
+		return p, p.SnapshotName
+	},
+}
+
+func backupRequest(provider string) copyRequest {
+	return copyRequest{
+		requestType: domain.BackupRequestType,
+		eventType:   domain.EventVMBackupRequested,
+		auditAction: domain.AuditVMBackupRequested,
+		nameKey:     "backup_name",
+		payload: func(vm *domain.VM, eventID, reason string) (domain.EventPayload, string) {
+			p := domain.BackupPayload{
+				VMID:       vm.ID,
+				Name:       vm.Name,
+				Namespace:  vm.Namespace,
+				Cluster:    vm.Cluster,
+				ServiceID:  vm.ServiceID,
+				BackupName: domain.BackupName(vm.Name, eventID),
+				Provider:   provider,
+				Reason:     reason,
+			}
+			return p, p.BackupName
+		},
+	}
+}
+
+// Submit routes the request by the approval policy: auto-approved
+// snapshots are enqueued at once, all others get a ticket.
+func (uc *SnapshotVMUseCase) Submit(ctx context.Context, vmID string, req domain.SnapshotRequest, requestedBy string) (*SnapshotVMResult, error) {
+	return uc.submit(ctx, vmID, req, requestedBy, snapshotRequest)
+}
+
+// SubmitBackup routes a backup request like Submit. The backup is taken by
+// the configured backup provider, recorded in the payload; without one it
+// fails with domain.ErrBackupNotConfigured.
+func (uc *SnapshotVMUseCase) SubmitBackup(ctx context.Context, vmID string, req domain.SnapshotRequest, requestedBy string) (*SnapshotVMResult, error) {
+	if err := domain.CheckBackupProvider(uc.backupProvider); err != nil {
+		return nil, err
+	}
+	return uc.submit(ctx, vmID, req, requestedBy, backupRequest(uc.backupProvider))
+}
+
+// submit creates the request described by op.
+func (uc *SnapshotVMUseCase) submit(ctx context.Context, vmID string, req domain.SnapshotRequest, requestedBy string, op copyRequest) (*SnapshotVMResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	// Rechecked under the VM lock; this spares the routing for the common mistake
+	if err := domain.CheckSnapshot(vm.Status); err != nil {
+		return nil, err
+	}
+	perm, err := uc.permissions.CheckPermission(requestedBy, domain.SnapshotPermission, string(domain.ResourceTypeService), vm.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, domain.ErrSnapshotForbidden
+	}
+
+	decision, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	route, err := uc.router.Route(ctx, vm.ServiceID, requestedBy, domain.ApprovalRequest{
+		RequestType: op.requestType,
+		Namespace:   vm.Namespace,
+		CPU:         vm.CPU,
+		MemoryMB:    vm.MemoryMB,
+	}, decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	var approvers []*domain.TicketApprover
+	if !route.AutoApprove {
+		approvers, err = uc.approvers.Resolve(ctx, ticketID, vm.ServiceID, requestedBy, decision.Stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	payload, copyName := op.payload(vm, eventID, req.Reason)
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckSnapshot(status); err != nil {
+		return nil, err
+	}
+
+	err = writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:     eventID,
+		ticketID:    ticketID,
+		vmID:        vm.ID,
+		vmName:      vm.Name,
+		serviceID:   vm.ServiceID,
+		requestType: op.requestType,
+		eventType:   op.eventType,
+		payload:     payload,
+		reason:      req.Reason,
+		requestedBy: requestedBy,
+		decision:    *decision,
+		autoApprove: route.AutoApprove,
+		approvers:   approvers,
+		auditAction: op.auditAction,
+		auditDetails: map[string]interface{}{
+			op.nameKey:     copyName,
+			"reason":       req.Reason,
+			"auto_approve": route.AutoApprove,
+			"route_reason": route.Reason,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if route.AutoApprove {
+		if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, eventID, domain.QueueNormal, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &SnapshotVMResult{
+		EventID:  eventID,
+		TicketID: ticketID,
+		Name:     copyName,
+		Route:    route,
+	}, nil
+}
+
+// ApproveAndEnqueue records an approval of a snapshot or backup ticket.
+// Once the ticket has its required approvals, the River job is inserted
+// in the same transaction. executeAt, when set, plans the snapshot for
+// later (e.g. before a maintenance window).
+func (uc *SnapshotVMUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, false)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusProcessing),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+	if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, ticket.EventID, domain.QueueNormal, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
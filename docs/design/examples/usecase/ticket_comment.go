@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// TicketCommentUseCase reads and writes a ticket's comment thread
+// (domain/ticket_comment.go).
+//
+// A comment is a single INSERT, so no TX is opened. The thread is the
+// record; comments are not additionally audited.
+type TicketCommentUseCase struct {
+	sqlcQueries *sqlc.Queries
+	ticketRepo  repository.ApprovalTicketRepository
+	permissions domain.PermissionChecker
+	notifier    domain.NotificationSender
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewTicketCommentUseCase creates a new use case instance.
+func NewTicketCommentUseCase(
+	sqlcQueries *sqlc.Queries,
+	ticketRepo repository.ApprovalTicketRepository,
+	permissions domain.PermissionChecker,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *TicketCommentUseCase {
+	return &TicketCommentUseCase{
+		sqlcQueries: sqlcQueries,
+		ticketRepo:  ticketRepo,
+		permissions: permissions,
+		notifier:    notifier,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Create appends a comment by userID. Returns domain.ErrNotTicketParticipant
+// if userID may not take part and domain.ErrInvalidComment for an empty or
+// oversized body.
+func (uc *TicketCommentUseCase) Create(ctx context.Context, ticketID, userID, body string) (*domain.TicketComment, error) {
+	body, err := domain.NormalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := uc.ticketRepo.Get(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	p, err := ticketParticipation(ctx, uc.sqlcQueries, uc.permissions, ticket, userID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := p.Role()
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &domain.TicketComment{
+		ID:         uc.ids.NewID(),
+		TicketID:   ticketID,
+		AuthorID:   userID,
+		AuthorRole: role,
+		Body:       body,
+		CreatedAt:  uc.clock.Now(),
+	}
+	if role == domain.CommentRoleDelegate {
+		comment.OnBehalfOf = p.DelegateOf
+	}
+
+	err = uc.sqlcQueries.CreateTicketComment(ctx, sqlc.CreateTicketCommentParams{
+		ID:         comment.ID,
+		TicketID:   comment.TicketID,
+		AuthorID:   comment.AuthorID,
+		AuthorRole: string(comment.AuthorRole),
+		OnBehalfOf: comment.OnBehalfOf,
+		Body:       comment.Body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+
+	uc.notify(ctx, ticket, comment)
+	return comment, nil
+}
+
+// List returns the thread oldest first, to participants only.
+func (uc *TicketCommentUseCase) List(ctx context.Context, ticketID, userID string) ([]*domain.TicketComment, error) {
+	ticket, err := uc.ticketRepo.Get(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	p, err := ticketParticipation(ctx, uc.sqlcQueries, uc.permissions, ticket, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Role(); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.ticketRepo.ListComments(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ticketParticipation resolves userID's relation to the ticket. Assignment
+// is checked on every stage: approvers of a later stage may ask questions
+// before their turn. Shared by the ticket views limited to participants.
+func ticketParticipation(ctx context.Context, q *sqlc.Queries, permissions domain.PermissionChecker, ticket *domain.ApprovalTicket, userID string) (domain.TicketParticipation, error) {
+	p := domain.TicketParticipation{Requester: ticket.CreatedBy == userID}
+	if p.Requester {
+		return p, nil
+	}
+
+	assigned, err := q.IsAssignedToTicket(ctx, sqlc.IsAssignedToTicketParams{
+		TicketID: ticket.TicketID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return p, fmt.Errorf("check approver assignment: %w", err)
+	}
+	if assigned {
+		p.Approver = true
+		return p, nil
+	}
+
+	delegator, err := q.GetActiveTicketDelegator(ctx, sqlc.GetActiveTicketDelegatorParams{
+		TicketID:   ticket.TicketID,
+		DelegateID: userID,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return p, fmt.Errorf("get active delegation: %w", err)
+	default:
+		p.DelegateOf = delegator
+		return p, nil
+	}
+
+	perm, err := permissions.CheckPermission(userID, "platform:admin", string(domain.ResourceTypeService), ticket.ServiceID)
+	if err != nil {
+		return p, fmt.Errorf("check platform admin: %w", err)
+	}
+	p.PlatformAdmin = perm.Allowed
+	return p, nil
+}
+
+// notify tells the requester and the current stage's approvers, except
+// the author. Best effort: the comment is already saved.
+func (uc *TicketCommentUseCase) notify(ctx context.Context, ticket *domain.ApprovalTicket, comment *domain.TicketComment) {
+	ctx = logger.WithTicket(ctx, ticket.TicketID)
+	approvers, err := uc.ticketRepo.ListStageApprovers(ctx, ticket.TicketID, ticket.CurrentStage)
+	if err != nil {
+		logger.WarnCtx(ctx, "List stage approvers failed", zap.Error(err))
+	}
+
+	seen := map[string]bool{comment.AuthorID: true}
+	var notifications []*domain.Notification
+	for _, recipient := range append([]string{ticket.CreatedBy}, approvers...) {
+		if seen[recipient] {
+			continue
+		}
+		seen[recipient] = true
+		notifications = append(notifications, &domain.Notification{
+			ID:              uc.ids.NewID(),
+			Recipient:       recipient,
+			Type:            domain.NotificationTicketComment,
+			Title:           fmt.Sprintf("New comment on %s request", ticket.RequestType),
+			Content:         fmt.Sprintf("%s (%s): %s", comment.AuthorID, comment.AuthorRole, comment.Body),
+			RelatedTicketID: ticket.TicketID,
+			Priority:        ticket.Priority,
+			CreatedAt:       comment.CreatedAt,
+		})
+	}
+
+	if err := uc.notifier.SendBatch(ctx, notifications); err != nil {
+		logger.WarnCtx(ctx, "Send comment notifications failed", zap.Error(err))
+	}
+}
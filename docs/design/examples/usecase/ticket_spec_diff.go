@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// TicketSpecDiffUseCase shows what an approver's ModifiedSpec changed on a
+// ticket (domain/spec_diff.go). Read-only, so no TX is opened.
+//
+// Visible to the ticket's participants, as for comments: the requester
+// sees what will actually be created, reviewers see what earlier stages
+// changed before they approve.
+type TicketSpecDiffUseCase struct {
+	sqlcQueries *sqlc.Queries
+	ticketRepo  repository.ApprovalTicketRepository
+	permissions domain.PermissionChecker
+}
+
+// NewTicketSpecDiffUseCase creates a new use case instance.
+func NewTicketSpecDiffUseCase(
+	sqlcQueries *sqlc.Queries,
+	ticketRepo repository.ApprovalTicketRepository,
+	permissions domain.PermissionChecker,
+) *TicketSpecDiffUseCase {
+	return &TicketSpecDiffUseCase{
+		sqlcQueries: sqlcQueries,
+		ticketRepo:  ticketRepo,
+		permissions: permissions,
+	}
+}
+
+// Get returns the ticket's original payload, effective spec and changed
+// fields. Returns domain.ErrNotTicketParticipant if userID may not see the
+// ticket and domain.ErrSpecDiffUnsupported for request types whose payload
+// is not domain.Modifiable (e.g. DELETE_VM).
+func (uc *TicketSpecDiffUseCase) Get(ctx context.Context, ticketID, userID string) (*domain.SpecDiff, error) {
+	ticket, err := uc.ticketRepo.Get(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	p, err := ticketParticipation(ctx, uc.sqlcQueries, uc.permissions, ticket, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Role(); err != nil {
+		return nil, err
+	}
+
+	event, err := uc.sqlcQueries.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+
+	payload, err := eventPayload(event)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := domain.DiffSpec(ticket.RequestType, domain.EventType(event.EventType), payload, ticket.ModifiedSpec)
+	if err != nil {
+		return nil, err
+	}
+	diff.TicketID = ticket.TicketID
+	return diff, nil
+}
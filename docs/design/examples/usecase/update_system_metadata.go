@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// UpdateSystemMetadataUseCase stores a System's labels/annotations and
+// schedules propagation to its VMs and namespaces.
+//
+// Caller must hold system:update on the System (owner/admin binding).
+// Propagation is asynchronous (ADR-0006): the row, the audit log and the
+// SystemMetadataSyncArgs job commit together, so a saved change is never
+// left unpropagated.
+type UpdateSystemMetadataUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	riverClient *river.Client[pgx.Tx]
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewUpdateSystemMetadataUseCase creates a new use case instance.
+func NewUpdateSystemMetadataUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *UpdateSystemMetadataUseCase {
+	return &UpdateSystemMetadataUseCase{
+		pool:        pool,
+		sqlcQueries: sqlcQueries,
+		riverClient: riverClient,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// Execute validates and saves the metadata, replacing the previous set.
+func (uc *UpdateSystemMetadataUseCase) Execute(ctx context.Context, m *domain.SystemMetadata) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	m.UpdatedAt = uc.clock.Now()
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := sqlcTx.UpsertSystemMetadata(ctx, sqlc.UpsertSystemMetadataParams{
+		SystemID:    m.SystemID,
+		Labels:      m.Labels,      // JSONB
+		Annotations: m.Annotations, // JSONB
+		UpdatedBy:   m.UpdatedBy,
+		UpdatedAt:   m.UpdatedAt,
+	}); err != nil {
+		return fmt.Errorf("save system metadata: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditSystemMetadataUpdated,
+		ActorID:      m.UpdatedBy,
+		ResourceType: "system",
+		ResourceID:   m.SystemID,
+		Details: map[string]interface{}{
+			"labels":      m.Labels,
+			"annotations": m.Annotations,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.SystemMetadataSyncArgs{SystemID: m.SystemID}, nil); err != nil {
+		return fmt.Errorf("insert river job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
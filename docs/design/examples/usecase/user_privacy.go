@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// exportPageSize is the keyset page size of user data export queries.
+const exportPageSize = 500
+
+// UserPrivacyUseCase exports and anonymizes a user's data
+// (domain/user_privacy.go). Platform admin only.
+//
+// The export is streamed, never stored: it is a copy of personal data, and
+// a stored copy would need its own retention. The anonymization runs in
+// one TX, so a user is either fully anonymized or not at all.
+type UserPrivacyUseCase struct {
+	pool        *pgxpool.Pool
+	sqlcQueries *sqlc.Queries
+	clock       domain.Clock
+	ids         domain.IDGenerator
+}
+
+// NewUserPrivacyUseCase creates a new use case instance.
+func NewUserPrivacyUseCase(pool *pgxpool.Pool, sqlcQueries *sqlc.Queries, clock domain.Clock, ids domain.IDGenerator) *UserPrivacyUseCase {
+	return &UserPrivacyUseCase{pool: pool, sqlcQueries: sqlcQueries, clock: clock, ids: ids}
+}
+
+// Export writes every record attributable to userID to w as one JSON
+// document, section by section (domain.ExportSections).
+//
+// The export is audited before the first byte is written: if the audit
+// fails, nothing is exported. Once streaming has started, errors can no
+// longer change the HTTP status; a truncated document is a failed export.
+func (uc *UserPrivacyUseCase) Export(ctx context.Context, userID, actorID string, w io.Writer) error {
+	// Every users column but password_hash
+	profile, err := uc.sqlcQueries.GetUserProfile(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get user profile: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]any{"sections": domain.ExportSections})
+	if err := uc.sqlcQueries.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditUserDataExported,
+		ActorID:      actorID,
+		ResourceType: "user",
+		ResourceID:   userID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	ew := &exportWriter{w: bufio.NewWriter(w)}
+	ew.raw(`{"user_id":`)
+	ew.value(userID)
+	ew.raw(`,"exported_at":`)
+	ew.value(uc.clock.Now())
+	ew.raw(`,"` + domain.ExportSectionProfile + `":`)
+	ew.value(profile)
+
+	q := uc.sqlcQueries
+	sections := []func() error{
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionRoleBindings,
+				func(after string) ([]sqlc.RoleBinding, error) {
+					return q.ListUserRoleBindings(ctx, sqlc.ListUserRoleBindingsParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.RoleBinding) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionRequests,
+				func(after string) ([]sqlc.ListUserRequestsRow, error) {
+					rows, err := q.ListUserRequests(ctx, sqlc.ListUserRequestsParams{UserID: userID, After: after, Limit: exportPageSize})
+					if err != nil {
+						return nil, err
+					}
+					// Payloads are exported as JSON, also when stored compressed
+					for i := range rows {
+						payload, err := domain.DecodePayload(domain.PayloadEncoding(rows[i].PayloadEncoding), rows[i].Payload)
+						if err != nil {
+							return nil, fmt.Errorf("decode payload of %s: %w", rows[i].TicketID, err)
+						}
+						rows[i].Payload, rows[i].PayloadEncoding = payload, string(domain.PayloadEncodingIdentity)
+					}
+					return rows, nil
+				},
+				func(r sqlc.ListUserRequestsRow) string { return r.TicketID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionDecisions,
+				func(after string) ([]sqlc.ListUserDecisionsRow, error) {
+					return q.ListUserDecisions(ctx, sqlc.ListUserDecisionsParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.ListUserDecisionsRow) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionComments,
+				func(after string) ([]sqlc.TicketComment, error) {
+					return q.ListUserComments(ctx, sqlc.ListUserCommentsParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.TicketComment) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionDelegations,
+				func(after string) ([]sqlc.ApprovalDelegation, error) {
+					return q.ListUserDelegations(ctx, sqlc.ListUserDelegationsParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.ApprovalDelegation) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionNotifications,
+				func(after string) ([]sqlc.Notification, error) {
+					return q.ListUserNotificationsForExport(ctx, sqlc.ListUserNotificationsForExportParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.Notification) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionChatIdentities,
+				func(after string) ([]sqlc.ChatIdentity, error) {
+					return q.ListUserChatIdentities(ctx, sqlc.ListUserChatIdentitiesParams{UserID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.ChatIdentity) string { return r.ID })
+		},
+		func() error {
+			return writeSection(ctx, ew, domain.ExportSectionAuditLogs,
+				func(after string) ([]sqlc.AuditLog, error) {
+					return q.ListUserAuditLogs(ctx, sqlc.ListUserAuditLogsParams{ActorID: userID, After: after, Limit: exportPageSize})
+				},
+				func(r sqlc.AuditLog) string { return r.ID })
+		},
+	}
+	for _, write := range sections {
+		if err := write(); err != nil {
+			return err
+		}
+	}
+	ew.raw("}\n")
+	if ew.err != nil {
+		return fmt.Errorf("write export: %w", ew.err)
+	}
+	return ew.w.Flush()
+}
+
+// writeSection writes one export section as a JSON array, fetching the
+// records in keyset pages (ordered by the cursor column).
+func writeSection[T any](ctx context.Context, ew *exportWriter, name string, fetch func(after string) ([]T, error), cursor func(T) string) error {
+	ew.raw(`,"` + name + `":[`)
+	after, first := "", true
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := fetch(after)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+		for _, item := range page {
+			if !first {
+				ew.raw(",")
+			}
+			first = false
+			ew.value(item)
+		}
+		if ew.err != nil {
+			return fmt.Errorf("write export: %w", ew.err)
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		after = cursor(page[len(page)-1])
+	}
+	ew.raw("]")
+	return nil
+}
+
+// exportWriter writes a JSON document piece by piece, keeping the first
+// error.
+type exportWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (ew *exportWriter) raw(s string) {
+	if ew.err == nil {
+		_, ew.err = ew.w.WriteString(s)
+	}
+}
+
+func (ew *exportWriter) value(v any) {
+	if ew.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		ew.err = err
+		return
+	}
+	_, ew.err = ew.w.Write(b)
+}
+
+// Anonymize erases what identifies userID (domain/user_privacy.go) and
+// keeps the opaque ID, so every record stays linked. Returns
+// domain.ErrUserAnonymized, domain.ErrAnonymizeNotConfirmed,
+// domain.ErrUserUnderLegalHold or domain.ErrUserHasOpenRequests when the
+// user cannot be anonymized.
+//
+// Open requests must be cancelled (or decided) first: an executing request
+// still notifies its requester. Resources the user was the only owner of
+// stay manageable by platform admins; transfer them beforehand. The IdP
+// subject is erased, so a later login creates a new user. The audit record
+// carries the counts and reason only, never the erased values.
+func (uc *UserPrivacyUseCase) Anonymize(ctx context.Context, userID, actorID string, req domain.AnonymizeUserRequest) (*domain.UserAnonymization, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Locked: a concurrent login (IdP sync) must not rewrite the profile
+	// between the checks and the scrub
+	user, err := sqlcTx.GetUserForUpdate(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if user.AnonymizedAt != nil {
+		return nil, domain.ErrUserAnonymized
+	}
+	if err := req.Validate(user.Username); err != nil {
+		return nil, err
+	}
+
+	held, err := sqlcTx.IsResourceHeld(ctx, sqlc.IsResourceHeldParams{ResourceType: domain.HoldResourceUser, ResourceID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("check legal hold: %w", err)
+	}
+	if held {
+		return nil, domain.ErrUserUnderLegalHold
+	}
+	open, err := sqlcTx.CountOpenRequestsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count open requests: %w", err)
+	}
+	if open > 0 {
+		return nil, fmt.Errorf("%d open: %w", open, domain.ErrUserHasOpenRequests)
+	}
+
+	now := uc.clock.Now()
+	result := &domain.UserAnonymization{
+		UserID:       userID,
+		Username:     domain.AnonymizedUsername(userID),
+		AnonymizedAt: now,
+		Changed:      make(map[string]int64),
+	}
+
+	// The values to scrub from JSON payloads, captured before the profile
+	// is overwritten
+	erased := []string{user.Username, user.Email, user.DisplayName, user.ExternalID}
+
+	steps := []struct {
+		table string
+		run   func() (int64, error)
+	}{
+		{"users", func() (int64, error) {
+			return sqlcTx.AnonymizeUser(ctx, sqlc.AnonymizeUserParams{
+				ID:           userID,
+				Username:     result.Username,
+				DisplayName:  domain.AnonymizedDisplayName,
+				AnonymizedAt: now,
+			})
+		}},
+		{"audit_logs", func() (int64, error) {
+			return sqlcTx.AnonymizeAuditActor(ctx, sqlc.AnonymizeAuditActorParams{
+				ActorID:   userID,
+				ActorName: domain.AnonymizedDisplayName,
+				Erased:    erased,
+			})
+		}},
+		{"domain_events", func() (int64, error) {
+			n, err := sqlcTx.ScrubUserFromEvents(ctx, sqlc.ScrubUserFromEventsParams{
+				UserID:      userID,
+				Erased:      erased,
+				Replacement: domain.AnonymizedDisplayName,
+			})
+			if err != nil {
+				return 0, err
+			}
+			m, err := scrubCompressedEvents(ctx, sqlcTx, userID, erased)
+			return n + m, err
+		}},
+		{"role_bindings", func() (int64, error) { return sqlcTx.DeleteUserRoleBindings(ctx, userID) }},
+		{"resource_role_bindings", func() (int64, error) { return sqlcTx.DeleteUserResourceRoleBindings(ctx, userID) }},
+		{"approval_delegations", func() (int64, error) {
+			return sqlcTx.RevokeUserDelegations(ctx, sqlc.RevokeUserDelegationsParams{UserID: userID, RevokedAt: now})
+		}},
+		{"chat_identities", func() (int64, error) { return sqlcTx.DeleteUserChatIdentities(ctx, userID) }},
+		{"approval_action_links", func() (int64, error) { return sqlcTx.DeleteUserActionLinks(ctx, userID) }},
+		{"notifications", func() (int64, error) { return sqlcTx.DeleteUserNotifications(ctx, userID) }},
+		{"sessions", func() (int64, error) { return sqlcTx.DeleteUserSessions(ctx, userID) }},
+	}
+	for _, step := range steps {
+		n, err := step.run()
+		if err != nil {
+			return nil, fmt.Errorf("anonymize %s: %w", step.table, err)
+		}
+		result.Changed[step.table] = n
+	}
+
+	details, _ := json.Marshal(map[string]any{
+		"reason":  req.Reason,
+		"changed": result.Changed,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditUserAnonymized,
+		ActorID:      actorID,
+		ResourceType: "user",
+		ResourceID:   userID,
+		Details:      details,
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	logger.InfoCtx(logger.WithResource(ctx, "user", userID), "User anonymized",
+		zap.String("actor_id", actorID),
+	)
+	return result, nil
+}
+
+// scrubCompressedEvents scrubs the payloads ScrubUserFromEvents cannot
+// read: compressed ones are decoded, scrubbed and re-encoded in Go.
+func scrubCompressedEvents(ctx context.Context, sqlcTx *sqlc.Queries, userID string, erased []string) (int64, error) {
+	rows, err := sqlcTx.ListCompressedEventPayloadsByUser(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("list compressed payloads: %w", err)
+	}
+	for _, row := range rows {
+		payload, err := domain.DecodePayload(domain.PayloadEncoding(row.PayloadEncoding), row.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("decode payload of %s: %w", row.EventID, err)
+		}
+		if payload, err = domain.ScrubJSON(payload, erased, domain.AnonymizedDisplayName); err != nil {
+			return 0, fmt.Errorf("scrub payload of %s: %w", row.EventID, err)
+		}
+		stored, enc, err := domain.EncodePayload(payload)
+		if err != nil {
+			return 0, fmt.Errorf("encode payload of %s: %w", row.EventID, err)
+		}
+		if err := sqlcTx.ReplaceEventPayload(ctx, sqlc.ReplaceEventPayloadParams{
+			EventID:         row.EventID,
+			Payload:         stored,
+			PayloadEncoding: string(enc),
+		}); err != nil {
+			return 0, fmt.Errorf("replace payload of %s: %w", row.EventID, err)
+		}
+	}
+	return int64(len(rows)), nil
+}
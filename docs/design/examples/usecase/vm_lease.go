@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// LeaseRenewalUseCase handles renewal requests of VM leases
+// (domain/vm_lease.go):
+//
+//	RequestRenewal() → Event + Ticket (RENEW_LEASE)        → PENDING_APPROVAL
+//	Approve()        → final approval extends the lease     → COMPLETED
+//
+// Renewals are never auto-approved: the environment policy's approvals
+// apply as for any request. The event's aggregate type is VMLease, not
+// VM, so a pending renewal neither blocks other operations on the VM nor
+// holds off the expiry.
+type LeaseRenewalUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	vmRepo       repository.VMRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	permissions  domain.PermissionChecker
+	notifier     domain.NotificationSender
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewLeaseRenewalUseCase creates a new use case instance.
+func NewLeaseRenewalUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	vmRepo repository.VMRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	permissions domain.PermissionChecker,
+	notifier domain.NotificationSender,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *LeaseRenewalUseCase {
+	return &LeaseRenewalUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		vmRepo:       vmRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		permissions:  permissions,
+		notifier:     notifier,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// LeaseRenewalResult contains the renewal request result.
+type LeaseRenewalResult struct {
+	EventID  string
+	TicketID string
+}
+
+// RequestRenewal asks to move the lease of vmID to req.ExpiresAt. One
+// pending renewal per VM.
+func (uc *LeaseRenewalUseCase) RequestRenewal(ctx context.Context, vmID string, req domain.LeaseRenewalRequest, requestedBy string) (*LeaseRenewalResult, error) {
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	perm, err := uc.permissions.CheckPermission(requestedBy, domain.LeaseRenewalPermission, string(domain.ResourceTypeService), vm.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, domain.ErrLeaseRenewalForbidden
+	}
+	// Rechecked under the lock; this spares the approver lookup
+	if err := req.Validate(vm.Lease, vm.Status, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	decision, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	approvers, err := uc.approvers.Resolve(ctx, ticketID, vm.ServiceID, requestedBy, decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("resolve approvers: %w", err)
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// SELECT status, lease_* FROM vms ... FOR UPDATE
+	lease, status, err := lockLease(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(lease, status, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+	// PENDING events of type VM_LEASE_RENEWAL_REQUESTED with aggregate_id = vm.ID
+	pending, err := sqlcTx.CountPendingLeaseRenewals(ctx, vm.ID)
+	if err != nil {
+		return nil, fmt.Errorf("count pending renewals: %w", err)
+	}
+	if pending > 0 {
+		return nil, domain.ErrLeaseRenewalPending
+	}
+
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       eventID,
+		EventType:     string(domain.EventVMLeaseRenewalRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventVMLeaseRenewalRequested),
+		AggregateType: "VMLease",
+		AggregateID:   vm.ID,
+		Status:        string(domain.EventStatusPending),
+		CreatedBy:     requestedBy,
+	}, domain.LeaseRenewalPayload{
+		VMID:             vm.ID,
+		Name:             vm.Name,
+		Namespace:        vm.Namespace,
+		ServiceID:        vm.ServiceID,
+		CurrentExpiresAt: lease.ExpiresAt,
+		ExpiresAt:        req.ExpiresAt,
+		Reason:           req.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+		TicketID:          ticketID,
+		EventID:           eventID,
+		ServiceID:         vm.ServiceID,
+		RequestType:       domain.LeaseRenewalRequestType,
+		RequestReason:     req.Reason,
+		Status:            "PENDING_APPROVAL",
+		Priority:          string(domain.PriorityNormal),
+		RequiredApprovals: int32(decision.RequiredApprovals),
+		SLADueAt:          domain.SLADueAt(uc.clock.Now(), decision.ApprovalSLA, domain.PriorityNormal),
+		CreatedBy:         requestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create approval ticket: %w", err)
+	}
+	for _, a := range approvers {
+		err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+			TicketID: ticketID,
+			UserID:   a.UserID,
+			Source:   string(a.Source),
+			Stage:    int32(a.Stage),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("assign approver: %w", err)
+		}
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVMLeaseRenewalRequested,
+		ActorID:      requestedBy,
+		ResourceType: "vm",
+		ResourceID:   vm.ID,
+		ResourceName: vm.Name,
+		Details: map[string]interface{}{
+			"ticket_id":          ticketID,
+			"current_expires_at": lease.ExpiresAt,
+			"expires_at":         req.ExpiresAt,
+			"reason":             req.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &LeaseRenewalResult{EventID: eventID, TicketID: ticketID}, nil
+}
+
+// Approve records an approval. The final approval extends the lease in
+// the same transaction: the warning is re-armed, and a lease that already
+// expired with action stop becomes active again (the VM stays stopped
+// until its owner starts it). A VM deleted meanwhile cannot be renewed.
+func (uc *LeaseRenewalUseCase) Approve(ctx context.Context, ticketID, approverID string) (*domain.ApprovalProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, false)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	p, err := decodeEventPayload[domain.LeaseRenewalPayload](event)
+	if err != nil {
+		return nil, fmt.Errorf("decode lease renewal payload: %w", err)
+	}
+
+	_, status, err := lockLease(ctx, sqlcTx, p.VMID)
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.VMStatusDeleting || status == domain.VMStatusDeleted {
+		return nil, domain.ErrVMDeletionInProgress
+	}
+	// lease_expires_at = $2, lease_warned_at = NULL, lease_expired_at = NULL,
+	// lease_renewals = lease_renewals + 1
+	if err := sqlcTx.RenewVMLease(ctx, sqlc.RenewVMLeaseParams{
+		VMID:      p.VMID,
+		ExpiresAt: p.ExpiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("renew lease: %w", err)
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	// Nothing to run on the cluster: the renewal is done
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusCompleted),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVMLeaseRenewed,
+		ActorID:      approverID,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		ResourceName: p.Name,
+		Details: map[string]interface{}{
+			"ticket_id":           ticketID,
+			"requested_by":        ticket.CreatedBy,
+			"previous_expires_at": p.CurrentExpiresAt,
+			"expires_at":          p.ExpiresAt,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	// Best effort after commit: the lease is renewed either way
+	if err := uc.notifier.Send(ctx, &domain.Notification{
+		ID:              uc.ids.NewID(),
+		Recipient:       ticket.CreatedBy,
+		Type:            domain.NotificationLeaseRenewed,
+		Title:           fmt.Sprintf("Lease of %s renewed", p.Name),
+		Content:         fmt.Sprintf("Expires %s.", p.ExpiresAt.Format(time.RFC3339)),
+		RelatedTicketID: ticketID,
+		CreatedAt:       uc.clock.Now(),
+	}); err != nil {
+		logger.WarnCtx(logger.WithTicket(ctx, ticketID), "Send lease renewal notification failed", zap.Error(err))
+	}
+	return result, nil
+}
+
+// lockLease locks the VM row and returns its lease (nil without one) and
+// status. Shared with ExpireLeasesUseCase.
+func lockLease(ctx context.Context, sqlcTx *sqlc.Queries, vmID string) (*domain.VMLease, domain.VMStatus, error) {
+	row, err := sqlcTx.GetVMLeaseForUpdate(ctx, vmID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("lock vm lease: %w", err)
+	}
+	status := domain.VMStatus(row.Status)
+	if row.LeaseExpiresAt == nil {
+		return nil, status, nil
+	}
+	return &domain.VMLease{
+		ExpiresAt: *row.LeaseExpiresAt,
+		Action:    domain.LeaseAction(row.LeaseAction),
+		WarnedAt:  row.LeaseWarnedAt,
+		ExpiredAt: row.LeaseExpiredAt,
+		Renewals:  int(row.LeaseRenewals),
+	}, status, nil
+}
@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// PowerOperationUseCase starts, stops and restarts single VMs
+// (domain/vm_power.go) with the same atomic transaction pattern as
+// DeleteVMAtomicUseCase (ADR-0012):
+//
+//	Submit(), routed to a ticket   → Event + Ticket, no River Job   → PENDING_APPROVAL
+//	ApproveAndEnqueue()            → Ticket APPROVED, River Job     → APPROVED
+//	Submit(), auto-approved        → Event + Ticket + Job in one TX → PROCESSING
+//	AutoApproveAndEnqueue()        → Event + Ticket + Job in one TX → PROCESSING
+//
+// The event is on the VM, so a power operation in flight blocks deletion
+// and resize requests, and the other way round. The VM status is set by
+// PowerOperationHandler once the cluster has done it.
+type PowerOperationUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	vmRepo       repository.VMRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	permissions  domain.PermissionChecker
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewPowerOperationUseCase creates a new use case instance.
+func NewPowerOperationUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	permissions domain.PermissionChecker,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *PowerOperationUseCase {
+	return &PowerOperationUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		vmRepo:       vmRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		router:       router,
+		permissions:  permissions,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// PowerOperationRequest contains the power operation request data.
+type PowerOperationRequest struct {
+	VMID        string             // Required
+	Action      domain.PowerAction // Required
+	Reason      string             // Required: business reason for request
+	RequestedBy string             // Required: user who submitted the request
+}
+
+// PowerOperationResult contains the power operation result.
+type PowerOperationResult struct {
+	EventID  string
+	TicketID string
+	Route    *domain.ApprovalRoute
+}
+
+// powerRequest is a validated request ready to be written.
+type powerRequest struct {
+	vm       *domain.VM
+	decision domain.EnvironmentDecision
+	route    *domain.ApprovalRoute
+}
+
+// Submit routes the operation by the approval policy: auto-approved
+// operations are enqueued at once, all others get a ticket.
+func (uc *PowerOperationUseCase) Submit(ctx context.Context, req PowerOperationRequest) (*PowerOperationResult, error) {
+	p, err := uc.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return uc.submit(ctx, p, req)
+}
+
+// AutoApproveAndEnqueue runs the operation without permission check or
+// approval, for operations the platform initiates itself (e.g. stopping a
+// VM whose lease expired). The status and in-flight checks still apply.
+func (uc *PowerOperationUseCase) AutoApproveAndEnqueue(ctx context.Context, req PowerOperationRequest) (*PowerOperationResult, error) {
+	vm, err := uc.load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return uc.submit(ctx, &powerRequest{
+		vm:    vm,
+		route: &domain.ApprovalRoute{AutoApprove: true, Reason: "platform-initiated"},
+	}, req)
+}
+
+// submit writes a routed request: enqueued at once when auto-approved, a
+// ticket with its approvers otherwise.
+func (uc *PowerOperationUseCase) submit(ctx context.Context, p *powerRequest, req PowerOperationRequest) (*PowerOperationResult, error) {
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	var approvers []*domain.TicketApprover
+	if !p.route.AutoApprove {
+		var err error
+		approvers, err = uc.approvers.Resolve(ctx, ticketID, p.vm.ServiceID, req.RequestedBy, p.decision.Stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	// ========== Atomic Transaction ==========
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := uc.createRequest(ctx, sqlcTx, p, req, eventID, ticketID, approvers); err != nil {
+		return nil, err
+	}
+	if p.route.AutoApprove {
+		if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, eventID, domain.QueueNormal, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &PowerOperationResult{EventID: eventID, TicketID: ticketID, Route: p.route}, nil
+}
+
+// ApproveAndEnqueue records an approval. Once the ticket has its required
+// approvals, the River job is inserted in the same transaction. executeAt,
+// when set, plans the operation for later (e.g. a restart in the
+// maintenance window). modifiedSpec is merged like for other requests;
+// PowerOperationPayload has no modifiable fields (the action is the event
+// type), so only modified_by/modified_reason are accepted.
+func (uc *PowerOperationUseCase) ApproveAndEnqueue(ctx context.Context, ticketID, approverID string, modifiedSpec *domain.ModifiedSpec, executeAt *time.Time) (*domain.ApprovalProgress, error) {
+	if err := domain.ValidateExecuteAt(executeAt, uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+
+	// Cancelled, rejected or already approved meanwhile: never enqueue
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, modifiedSpec != nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecuteAt, err = planExecution(ctx, sqlcTx, ticket, executeAt); err != nil {
+		return nil, err
+	}
+
+	specJSON, err := storeModifiedSpec(ctx, sqlcTx, ticket, modifiedSpec)
+	if err != nil {
+		return nil, err
+	}
+	event, err := sqlcTx.GetDomainEvent(ctx, ticket.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+	if _, err := effectiveEventPayload[domain.PowerOperationPayload](event, specJSON); err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+		ModifiedSpec:       specJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusProcessing),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+	if err := insertExecutionJob(ctx, tx, sqlcTx, uc.riverClient, ticketID, ticket.EventID, domain.QueueNormal, result.ExecuteAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// prepare loads the VM, checks permission and status, and routes the
+// request before any transaction is opened.
+func (uc *PowerOperationUseCase) prepare(ctx context.Context, req PowerOperationRequest) (*powerRequest, error) {
+	vm, err := uc.load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := uc.permissions.CheckPermission(req.RequestedBy, domain.PowerPermission, string(domain.ResourceTypeService), vm.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, domain.ErrPowerForbidden
+	}
+
+	env, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	decision := domain.PowerDecision(*env)
+
+	route, err := uc.router.Route(ctx, vm.ServiceID, req.RequestedBy, domain.ApprovalRequest{
+		RequestType: req.Action.RequestType(),
+		Namespace:   vm.Namespace,
+		CPU:         vm.CPU,
+		MemoryMB:    vm.MemoryMB,
+	}, &decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+
+	return &powerRequest{vm: vm, decision: decision, route: route}, nil
+}
+
+// load validates the request and loads the VM.
+func (uc *PowerOperationUseCase) load(ctx context.Context, req PowerOperationRequest) (*domain.VM, error) {
+	body := domain.PowerOperationRequest{Action: req.Action, Reason: req.Reason}
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+
+	vm, err := uc.vmRepo.Get(ctx, req.VMID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	// Rechecked under the VM lock; this spares the routing for the common mistake
+	if err := domain.CheckPowerAction(vm.Status, req.Action); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// createRequest locks the VM, verifies the action fits its status, and
+// writes the power event, ticket, approvers and audit record.
+func (uc *PowerOperationUseCase) createRequest(
+	ctx context.Context,
+	sqlcTx *sqlc.Queries,
+	p *powerRequest,
+	req PowerOperationRequest,
+	eventID, ticketID string,
+	approvers []*domain.TicketApprover,
+) error {
+	vm := p.vm
+
+	status, err := lockVM(ctx, sqlcTx, vm.ID)
+	if err != nil {
+		return err
+	}
+	if err := domain.CheckPowerAction(status, req.Action); err != nil {
+		return err
+	}
+
+	return writeVMRequest(ctx, sqlcTx, uc.ids, uc.clock.Now(), vmRequest{
+		eventID:     eventID,
+		ticketID:    ticketID,
+		vmID:        vm.ID,
+		vmName:      vm.Name,
+		serviceID:   vm.ServiceID,
+		requestType: req.Action.RequestType(),
+		eventType:   req.Action.EventType(),
+		payload: domain.PowerOperationPayload{
+			VMID:      vm.ID,
+			Name:      vm.Name,
+			Namespace: vm.Namespace,
+			Cluster:   vm.Cluster,
+			ServiceID: vm.ServiceID,
+			Action:    req.Action,
+			Reason:    req.Reason,
+		},
+		reason:      req.Reason,
+		requestedBy: req.RequestedBy,
+		decision:    p.decision,
+		autoApprove: p.route.AutoApprove,
+		approvers:   approvers,
+		auditAction: domain.AuditVMPowerRequested,
+		auditDetails: map[string]interface{}{
+			"action":       req.Action,
+			"reason":       req.Reason,
+			"auto_approve": p.route.AutoApprove,
+			"route_reason": p.route.Reason,
+		},
+	})
+}
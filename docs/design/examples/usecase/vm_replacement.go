@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/jobs"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// VMReplacementUseCase replaces a VM blue/green (domain/vm_replacement.go).
+//
+// Green is an ordinary create request, submitted through
+// CreateVMAtomicUseCase.Submit: the environment policy, approval rules,
+// quota and warm-up all apply. The replacement record and its River job
+// are written in the create request's TX (CreateVMRequest.Replacement).
+// The job (jobs.VMReplacementWorker) follows green's creation and carries
+// out the cutover; blue is retired through the deletion path.
+type VMReplacementUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	riverClient  *river.Client[pgx.Tx]
+	vmRepo       repository.VMRepository
+	templateRepo repository.TemplateRepository
+	replacements repository.VMReplacementRepository
+	permissions  domain.PermissionChecker
+	create       *CreateVMAtomicUseCase
+	del          *DeleteVMAtomicUseCase
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewVMReplacementUseCase creates a new use case instance.
+func NewVMReplacementUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	riverClient *river.Client[pgx.Tx],
+	vmRepo repository.VMRepository,
+	templateRepo repository.TemplateRepository,
+	replacements repository.VMReplacementRepository,
+	permissions domain.PermissionChecker,
+	create *CreateVMAtomicUseCase,
+	del *DeleteVMAtomicUseCase,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *VMReplacementUseCase {
+	return &VMReplacementUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		riverClient:  riverClient,
+		vmRepo:       vmRepo,
+		templateRepo: templateRepo,
+		replacements: replacements,
+		permissions:  permissions,
+		create:       create,
+		del:          del,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// ReplacementResult contains the started replacement and green's request.
+type ReplacementResult struct {
+	ReplacementID string
+	EventID       string
+	TicketID      string
+	Route         *domain.ApprovalRoute // Auto-approved: green is being created
+}
+
+// Start requests a replacement of vmID as userID. Returns
+// domain.ErrReplacementForbidden, domain.ErrInvalidReplacement,
+// domain.ErrVMNotReplaceable, domain.ErrReplacementSameTemplate or
+// domain.ErrReplacementInProgress when nothing was submitted.
+func (uc *VMReplacementUseCase) Start(ctx context.Context, vmID, userID string, req domain.StartReplacementRequest) (*ReplacementResult, error) {
+	ctx = logger.WithResource(ctx, "vm", vmID)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	blue, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	if err := uc.authorize(userID, blue.ServiceID); err != nil {
+		return nil, err
+	}
+	if !domain.Replaceable(blue.Status) {
+		return nil, fmt.Errorf("vm is %s: %w", blue.Status, domain.ErrVMNotReplaceable)
+	}
+
+	templateID, err := uc.targetTemplate(ctx, blue, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &domain.VMReplacement{
+		ID:             uc.ids.NewID(),
+		Status:         domain.ReplacementProvisioning,
+		Confirmation:   req.Confirmation,
+		Reason:         req.Reason,
+		RequestedBy:    userID,
+		ServiceID:      blue.ServiceID,
+		BlueVMID:       blue.ID,
+		BlueName:       blue.Name,
+		BlueTemplateID: blue.Template,
+		TemplateID:     templateID,
+		Hostname:       blue.Labels[domain.LabelHostname],
+		FQDN:           blue.Annotations[domain.AnnotationFQDN],
+		CreatedAt:      uc.clock.Now(),
+	}
+
+	// Same size and placement scope as blue; the reason tells approvers
+	// they approve the swap, blue's retirement included
+	res, err := uc.create.Submit(ctx, CreateVMRequest{
+		ServiceID:   blue.ServiceID,
+		TemplateID:  templateID,
+		Namespace:   blue.Namespace,
+		CPU:         blue.CPU,
+		MemoryMB:    blue.MemoryMB,
+		Reason:      fmt.Sprintf("Replacement of %s: %s", blue.Name, req.Reason),
+		RequestedBy: userID,
+		Replacement: r,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReplacementResult{ReplacementID: r.ID, EventID: res.EventID, TicketID: res.TicketID, Route: res.Route}, nil
+}
+
+// targetTemplate resolves green's template: the requested one, or the
+// active version of blue's template. Blue's own version is allowed for
+// channel templates only: green is built from the channel's current
+// digest, e.g. to leave a vulnerable image (domain/compliance.go).
+func (uc *VMReplacementUseCase) targetTemplate(ctx context.Context, blue *domain.VM, requested string) (string, error) {
+	current, err := uc.templateRepo.Get(ctx, blue.Template)
+	if err != nil {
+		return "", fmt.Errorf("get current template: %w", err)
+	}
+	if requested == "" {
+		active, err := uc.templateRepo.GetActive(ctx, current.Name)
+		if err != nil {
+			return "", fmt.Errorf("get active template: %w", err)
+		}
+		requested = active.ID
+	}
+	if requested == blue.Template && current.ImageChannel == "" {
+		return "", domain.ErrReplacementSameTemplate
+	}
+	return requested, nil
+}
+
+// Confirm lets green take over. The cutover runs in the replacement job,
+// inserted in the same TX.
+func (uc *VMReplacementUseCase) Confirm(ctx context.Context, replacementID, userID string) (*domain.VMReplacement, error) {
+	return uc.decide(ctx, replacementID, userID, domain.ReplacementCuttingOver, domain.AuditVMReplacementConfirmed)
+}
+
+// Abort keeps blue. Green is retired by the replacement job, inserted in
+// the same TX. While PROVISIONING, the requester cancels green's creation
+// request instead, which aborts the replacement.
+func (uc *VMReplacementUseCase) Abort(ctx context.Context, replacementID, userID string) (*domain.VMReplacement, error) {
+	return uc.decide(ctx, replacementID, userID, domain.ReplacementAborted, domain.AuditVMReplacementAborted)
+}
+
+// decide moves an AWAITING_CONFIRMATION replacement to next.
+func (uc *VMReplacementUseCase) decide(ctx context.Context, replacementID, userID string, next domain.ReplacementStatus, action string) (*domain.VMReplacement, error) {
+	r, err := uc.replacements.Get(ctx, replacementID)
+	if err != nil {
+		return nil, fmt.Errorf("get replacement: %w", err)
+	}
+	if err := uc.authorize(userID, r.ServiceID); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	// Conditional on the status: confirm and abort racing each other (or
+	// the health probe) cannot both win
+	now := uc.clock.Now()
+	n, err := sqlcTx.DecideVMReplacement(ctx, sqlc.DecideVMReplacementParams{
+		ID:          replacementID,
+		From:        string(domain.ReplacementAwaitingConfirmation),
+		To:          string(next),
+		ConfirmedBy: userID,
+		ConfirmedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update replacement: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("replacement is %s: %w", r.Status, domain.ErrReplacementInvalidState)
+	}
+
+	if _, err := uc.riverClient.InsertTx(ctx, tx, jobs.VMReplacementArgs{ReplacementID: replacementID}, nil); err != nil {
+		return nil, fmt.Errorf("insert river job: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"replacement_id": replacementID,
+		"green_vm_id":    r.GreenVMID,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       action,
+		ActorID:      userID,
+		ResourceType: "vm",
+		ResourceID:   r.BlueVMID,
+		Details:      details,
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	r.Status = next
+	r.ConfirmedBy = userID
+	r.ConfirmedAt = &now
+	return r, nil
+}
+
+// Get returns a replacement to readers of its Service; others get
+// repository.ErrNotFound.
+func (uc *VMReplacementUseCase) Get(ctx context.Context, replacementID, userID string) (*domain.VMReplacement, error) {
+	r, err := uc.replacements.Get(ctx, replacementID)
+	if err != nil {
+		return nil, fmt.Errorf("get replacement: %w", err)
+	}
+	perm, err := uc.permissions.CheckPermission(userID, "vm:read", string(domain.ResourceTypeService), r.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		return nil, repository.ErrNotFound
+	}
+	return r, nil
+}
+
+// Retire deletes vmID (blue after cutover, green after abort) through the
+// deletion path, auto-approved: the replacement was approved with green's
+// creation and confirmed by an owner. Implements jobs.VMRetirer.
+//
+// A VM already DELETING or DELETED counts as retired, with no new event:
+// the job may retry after the deletion committed.
+func (uc *VMReplacementUseCase) Retire(ctx context.Context, r *domain.VMReplacement, vmID string) (string, error) {
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return "", fmt.Errorf("get vm: %w", err)
+	}
+	if vm.Status == domain.VMStatusDeleting || vm.Status == domain.VMStatusDeleted {
+		return "", nil
+	}
+
+	requestedBy := r.ConfirmedBy
+	if requestedBy == "" {
+		requestedBy = r.RequestedBy
+	}
+	res, err := uc.del.AutoApproveAndEnqueue(ctx, DeleteVMRequest{
+		VMID:        vmID,
+		Reason:      fmt.Sprintf("Retired by replacement %s (%s)", r.ID, r.Status),
+		RequestedBy: requestedBy,
+		Confirm:     true,
+		ConfirmName: vm.Name,
+	})
+	if err != nil {
+		// e.g. ErrVMOperationPending: retried once the operation finished
+		return "", fmt.Errorf("retire %s: %w", vm.Name, err)
+	}
+	return res.EventID, nil
+}
+
+// authorize checks userID holds domain.ReplacementPermissions on the Service.
+func (uc *VMReplacementUseCase) authorize(userID, serviceID string) error {
+	for _, perm := range domain.ReplacementPermissions {
+		p, err := uc.permissions.CheckPermission(userID, perm, string(domain.ResourceTypeService), serviceID)
+		if err != nil {
+			return fmt.Errorf("check permission: %w", err)
+		}
+		if !p.Allowed {
+			return domain.ErrReplacementForbidden
+		}
+	}
+	return nil
+}
+
+// insertReplacement records a replacement in green's create request TX,
+// with the River job following green's creation. The partial unique index
+// on blue_vm_id allows one active replacement per VM.
+func insertReplacement(ctx context.Context, tx pgx.Tx, sqlcTx *sqlc.Queries, riverClient *river.Client[pgx.Tx], ids domain.IDGenerator, r *domain.VMReplacement, eventID, ticketID string) error {
+	r.CreationEventID = eventID
+	r.CreationTicket = ticketID
+
+	n, err := sqlcTx.CreateVMReplacement(ctx, sqlc.CreateVMReplacementParams{
+		ID:               r.ID,
+		Status:           string(r.Status),
+		Confirmation:     string(r.Confirmation),
+		Reason:           r.Reason,
+		RequestedBy:      r.RequestedBy,
+		ServiceID:        r.ServiceID,
+		BlueVMID:         r.BlueVMID,
+		BlueName:         r.BlueName,
+		BlueTemplateID:   r.BlueTemplateID,
+		TemplateID:       r.TemplateID,
+		CreationEventID:  eventID,
+		CreationTicketID: ticketID,
+		Hostname:         r.Hostname,
+		FQDN:             r.FQDN,
+	})
+	if err != nil {
+		return fmt.Errorf("create vm replacement: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrReplacementInProgress
+	}
+
+	if _, err := riverClient.InsertTx(ctx, tx, jobs.VMReplacementArgs{ReplacementID: r.ID}, nil); err != nil {
+		return fmt.Errorf("insert river job: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"replacement_id": r.ID,
+		"template_id":    r.TemplateID,
+		"confirmation":   r.Confirmation,
+		"ticket_id":      ticketID,
+		"reason":         r.Reason,
+	})
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditVMReplacementStarted,
+		ActorID:      r.RequestedBy,
+		ResourceType: "vm",
+		ResourceID:   r.BlueVMID,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
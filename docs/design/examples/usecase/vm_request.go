@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// lockVM locks the VM row (SELECT ... FOR UPDATE), which serializes
+// requests against the same VM, and refuses the request while another
+// operation is in flight. It returns the locked status for the request
+// type's own checks. Every request against an existing VM starts with it.
+func lockVM(ctx context.Context, sqlcTx *sqlc.Queries, vmID string) (domain.VMStatus, error) {
+	status, err := sqlcTx.LockVMStatus(ctx, vmID)
+	if err != nil {
+		return "", fmt.Errorf("lock vm: %w", err)
+	}
+	// PENDING/PROCESSING events with aggregate_id = vmID
+	pending, err := sqlcTx.CountPendingVMEvents(ctx, vmID)
+	if err != nil {
+		return "", fmt.Errorf("count pending operations: %w", err)
+	}
+	if err := domain.CheckNoOperationInFlight(domain.VMStatus(status), int(pending)); err != nil {
+		return "", err
+	}
+	return domain.VMStatus(status), nil
+}
+
+// vmRequest is a ticketed request against an existing VM, ready to be
+// written under the VM lock.
+type vmRequest struct {
+	eventID, ticketID string
+	vmID, vmName      string
+	serviceID         string // The ticket's Service: a clone's is the target Service
+	requestType       string
+	eventType         domain.EventType
+	payload           domain.EventPayload
+	reason            string
+	requestedBy       string
+	resubmittedFrom   string
+	resizePlan        []byte
+
+	// decision is frozen on the ticket (environment, approvals, stages),
+	// so the environment policy applies to every request type.
+	decision    domain.EnvironmentDecision
+	autoApprove bool
+	approvers   []*domain.TicketApprover // Unused when auto-approved
+
+	auditAction  string
+	auditDetails map[string]interface{} // ticket_id is added
+}
+
+// writeVMRequest writes the request's event, ticket, approvers and audit
+// record in the caller's transaction, after lockVM. An auto-approved
+// request is written PROCESSING/APPROVED; the caller inserts its River
+// job. Otherwise it waits for approval, with no job (ADR-0006).
+func writeVMRequest(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, now time.Time, r vmRequest) error {
+	eventStatus, ticketStatus := domain.EventStatusPending, "PENDING_APPROVAL"
+	requiredApprovals, slaDueAt := r.decision.RequiredApprovals, domain.SLADueAt(now, r.decision.ApprovalSLA, domain.PriorityNormal)
+	if r.autoApprove {
+		eventStatus, ticketStatus = domain.EventStatusProcessing, "APPROVED"
+		requiredApprovals, slaDueAt = 0, nil
+	}
+
+	err := createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       r.eventID,
+		EventType:     string(r.eventType),
+		SchemaVersion: domain.PayloadVersion(r.eventType),
+		AggregateType: "VM",
+		AggregateID:   r.vmID,
+		Status:        string(eventStatus),
+		CreatedBy:     r.requestedBy,
+	}, r.payload)
+	if err != nil {
+		return fmt.Errorf("create domain event: %w", err)
+	}
+
+	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+		TicketID:          r.ticketID,
+		EventID:           r.eventID,
+		ServiceID:         r.serviceID,
+		RequestType:       r.requestType,
+		RequestReason:     r.reason,
+		Status:            ticketStatus,
+		Priority:          string(domain.PriorityNormal),
+		Environment:       string(r.decision.Environment),
+		RequiredApprovals: int32(requiredApprovals),
+		Stages:            r.decision.Stages,
+		ResizePlan:        r.resizePlan,
+		SLADueAt:          slaDueAt,
+		ResubmittedFrom:   r.resubmittedFrom,
+		CreatedBy:         r.requestedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("create approval ticket: %w", err)
+	}
+
+	if !r.autoApprove {
+		for _, a := range r.approvers {
+			err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+				TicketID: r.ticketID,
+				UserID:   a.UserID,
+				Source:   string(a.Source),
+				Stage:    int32(a.Stage),
+			})
+			if err != nil {
+				return fmt.Errorf("assign approver: %w", err)
+			}
+		}
+	}
+
+	details := map[string]interface{}{"ticket_id": r.ticketID}
+	for k, v := range r.auditDetails {
+		details[k] = v
+	}
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       r.auditAction,
+		ActorID:      r.requestedBy,
+		ResourceType: "vm",
+		ResourceID:   r.vmID,
+		ResourceName: r.vmName,
+		Details:      details,
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
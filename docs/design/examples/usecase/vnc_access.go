@@ -0,0 +1,680 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"kv-shepherd.io/shepherd/internal/domain"
+	"kv-shepherd.io/shepherd/internal/pkg/logger"
+	"kv-shepherd.io/shepherd/internal/provider"
+	"kv-shepherd.io/shepherd/internal/repository"
+	"kv-shepherd.io/shepherd/internal/repository/sqlc"
+)
+
+// VNCAccessUseCase grants VNC console access (domain/vnc_access.go,
+// ADR-0015 §18):
+//
+//	Request(), no vnc:access       → VNC_ACCESS_DENIED               → ErrVNCForbidden
+//	Request(), routed to a ticket  → Event + Ticket (VNC_ACCESS)     → PENDING_APPROVAL
+//	Request(), auto-approved       → Event + Ticket + grant in one TX → VNC_ACCESS_GRANTED
+//	Approve()                      → final approval creates the grant → VNC_ACCESS_GRANTED
+//	rejectTicket()                 → VNC_ACCESS_DENIED (see denyVNCAccess)
+//	Redeem()                       → grant used once by the console proxy
+//	Revoke()                       → VNC_TOKEN_REVOKED
+//
+// The event's aggregate type is VMConsole, not VM: watching a console
+// does not change the VM, so it neither blocks nor waits for operations
+// on it. No River job is involved.
+type VNCAccessUseCase struct {
+	pool         *pgxpool.Pool
+	sqlcQueries  *sqlc.Queries
+	vmRepo       repository.VMRepository
+	approvers    ApproverResolver
+	guard        ApprovalGuard
+	environments EnvironmentPolicies
+	router       ApprovalRouter
+	permissions  domain.PermissionChecker
+	console      provider.ConsoleProvider
+	key          []byte // governance.vnc_token_key
+	clock        domain.Clock
+	ids          domain.IDGenerator
+}
+
+// NewVNCAccessUseCase creates a new use case instance.
+func NewVNCAccessUseCase(
+	pool *pgxpool.Pool,
+	sqlcQueries *sqlc.Queries,
+	vmRepo repository.VMRepository,
+	approvers ApproverResolver,
+	guard ApprovalGuard,
+	environments EnvironmentPolicies,
+	router ApprovalRouter,
+	permissions domain.PermissionChecker,
+	console provider.ConsoleProvider,
+	key []byte,
+	clock domain.Clock,
+	ids domain.IDGenerator,
+) *VNCAccessUseCase {
+	return &VNCAccessUseCase{
+		pool:         pool,
+		sqlcQueries:  sqlcQueries,
+		vmRepo:       vmRepo,
+		approvers:    approvers,
+		guard:        guard,
+		environments: environments,
+		router:       router,
+		permissions:  permissions,
+		console:      console,
+		key:          key,
+		clock:        clock,
+		ids:          ids,
+	}
+}
+
+// VNCAccessResult contains the access request result. Grant and Token are
+// set when the request was auto-approved; otherwise the requester fetches
+// the token once the ticket is approved (Token).
+type VNCAccessResult struct {
+	EventID  string
+	TicketID string
+	Route    *domain.ApprovalRoute
+	Grant    *domain.VNCAccessToken
+	Token    string
+}
+
+// Request asks for console access to vmID for req.Duration. The request
+// is routed like a power operation: auto-approved outside prod unless a
+// rule routes it to a ticket.
+func (uc *VNCAccessUseCase) Request(ctx context.Context, vmID string, req domain.VNCAccessRequest, userID string) (*VNCAccessResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	if err := domain.CheckVNCAccess(vm.Status); err != nil {
+		return nil, err
+	}
+
+	perm, err := uc.permissions.CheckPermission(userID, domain.VNCPermission, string(domain.ResourceTypeService), vm.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("check permission: %w", err)
+	}
+	if !perm.Allowed {
+		// Sensitive read: the refusal is recorded too
+		if err := uc.recordForbidden(ctx, vm, userID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrVNCForbidden
+	}
+
+	env, err := uc.environments.Decide(ctx, vm.ServiceID, vm.Namespace, &domain.VMCreationPayload{
+		ServiceID: vm.ServiceID,
+		CPU:       vm.CPU,
+		MemoryMB:  vm.MemoryMB,
+		DiskGB:    vm.DiskGB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	decision := domain.VNCDecision(*env)
+
+	route, err := uc.router.Route(ctx, vm.ServiceID, userID, domain.ApprovalRequest{
+		RequestType: domain.VNCAccessRequestType,
+		Namespace:   vm.Namespace,
+		CPU:         vm.CPU,
+		MemoryMB:    vm.MemoryMB,
+	}, &decision)
+	if err != nil {
+		return nil, fmt.Errorf("route approval: %w", err)
+	}
+
+	eventID := uc.ids.NewID()
+	ticketID := uc.ids.NewID()
+
+	var approvers []*domain.TicketApprover
+	if !route.AutoApprove {
+		approvers, err = uc.approvers.Resolve(ctx, ticketID, vm.ServiceID, userID, decision.Stages)
+		if err != nil {
+			return nil, fmt.Errorf("resolve approvers: %w", err)
+		}
+	}
+
+	payload := domain.VNCAccessPayload{
+		VMID:            vm.ID,
+		Name:            vm.Name,
+		Namespace:       vm.Namespace,
+		Cluster:         vm.Cluster,
+		ServiceID:       vm.ServiceID,
+		DurationMinutes: req.DurationMinutes,
+		Reason:          req.Reason,
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	eventStatus, ticketStatus := domain.EventStatusPending, "PENDING_APPROVAL"
+	requiredApprovals, slaDueAt := decision.RequiredApprovals, domain.SLADueAt(uc.clock.Now(), decision.ApprovalSLA, domain.PriorityNormal)
+	if route.AutoApprove {
+		eventStatus, ticketStatus = domain.EventStatusCompleted, "APPROVED"
+		requiredApprovals, slaDueAt = 0, nil
+	}
+
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       eventID,
+		EventType:     string(domain.EventVNCAccessRequested),
+		SchemaVersion: domain.PayloadVersion(domain.EventVNCAccessRequested),
+		AggregateType: "VMConsole",
+		AggregateID:   vm.ID,
+		Status:        string(eventStatus),
+		CreatedBy:     userID,
+	}, payload)
+	if err != nil {
+		return nil, fmt.Errorf("create domain event: %w", err)
+	}
+
+	err = sqlcTx.CreateApprovalTicket(ctx, sqlc.CreateApprovalTicketParams{
+		TicketID:          ticketID,
+		EventID:           eventID,
+		ServiceID:         vm.ServiceID,
+		RequestType:       domain.VNCAccessRequestType,
+		RequestReason:     req.Reason,
+		Status:            ticketStatus,
+		Priority:          string(domain.PriorityNormal),
+		RequiredApprovals: int32(requiredApprovals),
+		SLADueAt:          slaDueAt,
+		CreatedBy:         userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create approval ticket: %w", err)
+	}
+	for _, a := range approvers {
+		err = sqlcTx.CreateTicketApprover(ctx, sqlc.CreateTicketApproverParams{
+			TicketID: ticketID,
+			UserID:   a.UserID,
+			Source:   string(a.Source),
+			Stage:    int32(a.Stage),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("assign approver: %w", err)
+		}
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVNCAccessRequested,
+		ActorID:      userID,
+		ResourceType: "vm",
+		ResourceID:   vm.ID,
+		ResourceName: vm.Name,
+		Details: map[string]interface{}{
+			"ticket_id":        ticketID,
+			"duration_minutes": req.DurationMinutes,
+			"reason":           req.Reason,
+			"auto_approve":     route.AutoApprove,
+			"route_reason":     route.Reason,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	res := &VNCAccessResult{EventID: eventID, TicketID: ticketID, Route: route}
+	if route.AutoApprove {
+		if res.Grant, err = uc.grant(ctx, sqlcTx, payload, userID, ticketID, "system"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	if res.Grant != nil {
+		if res.Token, err = domain.SignVNCToken(uc.key, res.Grant); err != nil {
+			return nil, fmt.Errorf("sign vnc token: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// Approve records an approval. The final approval creates the grant in
+// the same transaction; the token's lifetime starts then, not when the
+// request was made.
+func (uc *VNCAccessUseCase) Approve(ctx context.Context, ticketID, approverID string) (*domain.ApprovalProgress, error) {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	ticket, err := sqlcTx.GetApprovalTicketForUpdate(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("get ticket: %w", err)
+	}
+	if err := requirePending(ticket.Status); err != nil {
+		return nil, err
+	}
+
+	acting, err := uc.guard.Enforce(ctx, sqlcTx, ticketID, ticket.ServiceID, ticket.CreatedBy, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recordApproval(ctx, sqlcTx, ticket, acting, false)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return result, nil
+	}
+
+	p, err := vncAccessPayload(ctx, sqlcTx, ticket.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sqlcTx.UpdateApprovalTicketStatus(ctx, sqlc.UpdateApprovalTicketStatusParams{
+		TicketID:           ticketID,
+		Status:             "APPROVED",
+		ApprovedBy:         approverID,
+		ApprovedOnBehalfOf: acting.OnBehalfOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update ticket: %w", err)
+	}
+
+	// Nothing to run on the cluster: the grant is the outcome
+	err = sqlcTx.UpdateDomainEventStatus(ctx, sqlc.UpdateDomainEventStatusParams{
+		EventID: ticket.EventID,
+		Status:  string(domain.EventStatusCompleted),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+
+	if _, err := uc.grant(ctx, sqlcTx, p, ticket.CreatedBy, ticketID, approverID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// Token returns the signed token of the grant made for ticketID. Only the
+// requester gets it; it can be fetched again until it is used.
+func (uc *VNCAccessUseCase) Token(ctx context.Context, ticketID, userID string) (string, *domain.VNCAccessToken, error) {
+	row, err := uc.sqlcQueries.GetVNCAccessTokenByTicket(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil, repository.ErrNotFound // Not approved (yet)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("get vnc grant: %w", err)
+	}
+	grant := vncGrantFromRow(row)
+	if grant.UserID != userID {
+		return "", nil, repository.ErrNotFound // Never reveal other users' grants
+	}
+	if err := grant.CheckUsable(uc.clock.Now()); err != nil {
+		return "", nil, err
+	}
+
+	token, err := domain.SignVNCToken(uc.key, grant)
+	if err != nil {
+		return "", nil, fmt.Errorf("sign vnc token: %w", err)
+	}
+	return token, grant, nil
+}
+
+// Redeem spends a token presented by userID and returns the console
+// connection for the proxy (RFC-0011). The grant is claimed before the
+// connection is opened: UseVNCAccessToken sets used_at only while the
+// grant is unused, unrevoked and unexpired, so a replayed token loses the
+// race. A VM that stopped meanwhile does not spend the token.
+func (uc *VNCAccessUseCase) Redeem(ctx context.Context, token, userID string) (*domain.ConsoleConnection, error) {
+	now := uc.clock.Now()
+	tokenID, err := domain.ParseVNCToken(uc.key, token, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := uc.loadGrant(ctx, tokenID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, domain.ErrVNCTokenInvalid // Signed but purged
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := grant.CheckUsable(now); err != nil {
+		return nil, err
+	}
+	vm, err := uc.vmRepo.Get(ctx, grant.VMID)
+	if err != nil {
+		return nil, fmt.Errorf("get vm: %w", err)
+	}
+	if err := domain.CheckVNCAccess(vm.Status); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	claimed, err := sqlcTx.UseVNCAccessToken(ctx, sqlc.UseVNCAccessTokenParams{TokenID: tokenID, UsedAt: now})
+	if err != nil {
+		return nil, fmt.Errorf("use vnc token: %w", err)
+	}
+	if claimed == 0 {
+		return nil, domain.ErrVNCTokenUsed // Used, revoked or expired since the check above
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVNCTokenUsed,
+		ActorID:      userID,
+		ResourceType: "vm",
+		ResourceID:   vm.ID,
+		ResourceName: vm.Name,
+		Details: map[string]interface{}{
+			"token_id":     tokenID,
+			"ticket_id":    grant.TicketID,
+			"connected_at": now,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	// After commit: a failed connection leaves the token spent, the user
+	// requests access again
+	conn, err := uc.console.GetVNCConnection(ctx, vm.Cluster, vm.Namespace, vm.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get vnc connection: %w", err)
+	}
+	return conn, nil
+}
+
+// Revoke ends a grant before its expiry. The holder may revoke their own
+// grant; anyone else needs platform:admin. Revoking does not close a
+// console already open: the proxy checks the grant when it connects.
+func (uc *VNCAccessUseCase) Revoke(ctx context.Context, tokenID, revokedBy string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	row, err := sqlcTx.GetVNCAccessTokenForUpdate(ctx, tokenID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get vnc grant: %w", err)
+	}
+	grant := vncGrantFromRow(row)
+	if grant.UserID != revokedBy {
+		vm, err := uc.vmRepo.Get(ctx, grant.VMID)
+		if err != nil {
+			return fmt.Errorf("get vm: %w", err)
+		}
+		perm, err := uc.permissions.CheckPermission(revokedBy, "platform:admin", string(domain.ResourceTypeService), vm.ServiceID)
+		if err != nil {
+			return fmt.Errorf("check permission: %w", err)
+		}
+		if !perm.Allowed {
+			return domain.ErrVNCRevokeDenied
+		}
+	}
+	if grant.RevokedAt != nil {
+		return domain.ErrVNCTokenRevoked
+	}
+
+	now := uc.clock.Now()
+	if err := sqlcTx.RevokeVNCAccessToken(ctx, sqlc.RevokeVNCAccessTokenParams{
+		TokenID:   tokenID,
+		RevokedBy: revokedBy,
+		RevokedAt: now,
+	}); err != nil {
+		return fmt.Errorf("revoke vnc token: %w", err)
+	}
+
+	// Recorded only: no handler, no River job
+	err = createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       uc.ids.NewID(),
+		EventType:     string(domain.EventVNCTokenRevoked),
+		SchemaVersion: domain.PayloadVersion(domain.EventVNCTokenRevoked),
+		AggregateType: "VMConsole",
+		AggregateID:   grant.VMID,
+		Status:        string(domain.EventStatusCompleted),
+		CreatedBy:     revokedBy,
+	}, domain.VNCTokenRevokedPayload{
+		TokenID:   tokenID,
+		VMID:      grant.VMID,
+		UserID:    grant.UserID,
+		RevokedBy: revokedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("create revocation event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVNCTokenRevoked,
+		ActorID:      revokedBy,
+		ResourceType: "vm",
+		ResourceID:   grant.VMID,
+		Details: map[string]interface{}{
+			"token_id":  tokenID,
+			"ticket_id": grant.TicketID,
+			"holder":    grant.UserID,
+			"used":      grant.UsedAt != nil,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	logger.InfoCtx(logger.WithResource(ctx, "vm", grant.VMID), "VNC token revoked",
+		zap.String("token_id", tokenID),
+		zap.String("revoked_by", revokedBy),
+	)
+	return nil
+}
+
+// grant stores the grant for userID and records VNC_ACCESS_GRANTED in the
+// caller's transaction. grantedBy is the final approver, or "system".
+func (uc *VNCAccessUseCase) grant(ctx context.Context, sqlcTx *sqlc.Queries, p domain.VNCAccessPayload, userID, ticketID, grantedBy string) (*domain.VNCAccessToken, error) {
+	now := uc.clock.Now()
+	g := &domain.VNCAccessToken{
+		TokenID:   uc.ids.NewID(),
+		VMID:      p.VMID,
+		UserID:    userID,
+		TicketID:  ticketID,
+		ExpiresAt: now.Add(time.Duration(p.DurationMinutes) * time.Minute),
+		CreatedAt: now,
+	}
+	if err := sqlcTx.CreateVNCAccessToken(ctx, sqlc.CreateVNCAccessTokenParams{
+		TokenID:   g.TokenID,
+		VMID:      g.VMID,
+		UserID:    g.UserID,
+		TicketID:  g.TicketID,
+		ExpiresAt: g.ExpiresAt,
+		CreatedAt: g.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("create vnc grant: %w", err)
+	}
+
+	err := createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       uc.ids.NewID(),
+		EventType:     string(domain.EventVNCAccessGranted),
+		SchemaVersion: domain.PayloadVersion(domain.EventVNCAccessGranted),
+		AggregateType: "VMConsole",
+		AggregateID:   p.VMID,
+		Status:        string(domain.EventStatusCompleted),
+		CreatedBy:     grantedBy,
+	}, domain.VNCAccessDecisionPayload{
+		VMID:      p.VMID,
+		UserID:    userID,
+		TicketID:  ticketID,
+		TokenID:   g.TokenID,
+		ExpiresAt: &g.ExpiresAt,
+		DecidedBy: grantedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create grant event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           uc.ids.NewID(),
+		Action:       domain.AuditVNCTokenIssued,
+		ActorID:      grantedBy,
+		ResourceType: "vm",
+		ResourceID:   p.VMID,
+		ResourceName: p.Name,
+		Details: map[string]interface{}{
+			"token_id":   g.TokenID,
+			"ticket_id":  ticketID,
+			"holder":     userID,
+			"expires_at": g.ExpiresAt,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+	return g, nil
+}
+
+// recordForbidden records a request refused by RBAC. No ticket exists.
+func (uc *VNCAccessUseCase) recordForbidden(ctx context.Context, vm *domain.VM, userID string) error {
+	tx, err := uc.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sqlcTx := uc.sqlcQueries.WithTx(tx)
+
+	if err := createVNCDenial(ctx, sqlcTx, uc.ids, vm.ID, vm.Name, userID, "", "system", domain.ErrVNCForbidden.Error()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// denyVNCAccess records VNC_ACCESS_DENIED for a rejected VNC_ACCESS
+// ticket, in rejectTicket's transaction.
+func denyVNCAccess(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, ticket sqlc.ApprovalTicket, rejectedBy, reason string) error {
+	p, err := vncAccessPayload(ctx, sqlcTx, ticket.EventID)
+	if err != nil {
+		return err
+	}
+	return createVNCDenial(ctx, sqlcTx, ids, p.VMID, p.Name, ticket.CreatedBy, ticket.TicketID, rejectedBy, reason)
+}
+
+// createVNCDenial writes the VNC_ACCESS_DENIED event and its audit log.
+func createVNCDenial(ctx context.Context, sqlcTx *sqlc.Queries, ids domain.IDGenerator, vmID, vmName, userID, ticketID, deniedBy, reason string) error {
+	err := createDomainEvent(ctx, sqlcTx, sqlc.CreateDomainEventParams{
+		EventID:       ids.NewID(),
+		EventType:     string(domain.EventVNCAccessDenied),
+		SchemaVersion: domain.PayloadVersion(domain.EventVNCAccessDenied),
+		AggregateType: "VMConsole",
+		AggregateID:   vmID,
+		Status:        string(domain.EventStatusCompleted),
+		CreatedBy:     deniedBy,
+	}, domain.VNCAccessDecisionPayload{
+		VMID:      vmID,
+		UserID:    userID,
+		TicketID:  ticketID,
+		DecidedBy: deniedBy,
+		Reason:    reason,
+	})
+	if err != nil {
+		return fmt.Errorf("create denial event: %w", err)
+	}
+
+	if err := sqlcTx.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:           ids.NewID(),
+		Action:       domain.AuditVNCAccessDenied,
+		ActorID:      deniedBy,
+		ResourceType: "vm",
+		ResourceID:   vmID,
+		ResourceName: vmName,
+		Details: map[string]interface{}{
+			"requested_by": userID,
+			"ticket_id":    ticketID,
+			"reason":       reason,
+		},
+	}); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
+
+// vncAccessPayload decodes the payload of a VNC_ACCESS_REQUESTED event.
+func vncAccessPayload(ctx context.Context, sqlcTx *sqlc.Queries, eventID string) (domain.VNCAccessPayload, error) {
+	event, err := sqlcTx.GetDomainEvent(ctx, eventID)
+	if err != nil {
+		return domain.VNCAccessPayload{}, fmt.Errorf("get event: %w", err)
+	}
+	p, err := decodeEventPayload[domain.VNCAccessPayload](event)
+	if err != nil {
+		return p, fmt.Errorf("decode vnc access payload: %w", err)
+	}
+	return p, nil
+}
+
+// loadGrant reads a grant.
+func (uc *VNCAccessUseCase) loadGrant(ctx context.Context, tokenID string) (*domain.VNCAccessToken, error) {
+	row, err := uc.sqlcQueries.GetVNCAccessToken(ctx, tokenID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vnc grant: %w", err)
+	}
+	return vncGrantFromRow(row), nil
+}
+
+func vncGrantFromRow(row sqlc.VNCAccessToken) *domain.VNCAccessToken {
+	return &domain.VNCAccessToken{
+		TokenID:   row.TokenID,
+		VMID:      row.VMID,
+		UserID:    row.UserID,
+		TicketID:  row.TicketID,
+		ExpiresAt: row.ExpiresAt,
+		UsedAt:    row.UsedAt,
+		RevokedAt: row.RevokedAt,
+		RevokedBy: row.RevokedBy,
+		CreatedAt: row.CreatedAt,
+	}
+}